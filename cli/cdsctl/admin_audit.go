@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+var adminAuditCmd = cli.Command{
+	Name:  "audit",
+	Short: "Manage CDS security audit log",
+	Long: `The security audit log contains a record for each mutating API call and each sign-in.
+Records are chained with a hash, an exported audit log can be checked with the verify command.`,
+}
+
+func adminAudit() *cobra.Command {
+	return cli.NewCommand(adminAuditCmd, nil, []*cobra.Command{
+		cli.NewListCommand(adminAuditList, adminAuditListFunc, nil),
+		cli.NewCommand(adminAuditExport, adminAuditExportFunc, nil),
+		cli.NewCommand(adminAuditVerify, adminAuditVerifyFunc, nil),
+	})
+}
+
+var adminAuditFilterFlags = []cli.Flag{
+	{Name: "since", Usage: "Only records created after given RFC3339 date"},
+	{Name: "until", Usage: "Only records created before given RFC3339 date"},
+	{Name: "username", Usage: "Only records for given username"},
+	{Name: "consumer", Usage: "Only records for given consumer id"},
+	{Name: "method", Usage: "Only records for given HTTP method"},
+	{Name: "route", Usage: "Only records for given route, ie. /project/<project-key>"},
+	{Name: "target", Type: cli.FlagArray, Usage: "Only records for given target, ie. project_key=MYPROJ"},
+}
+
+func adminAuditFilterMods(v cli.Values) []cdsclient.RequestModifier {
+	var mods []cdsclient.RequestModifier
+	for _, name := range []string{"since", "until", "username", "consumer", "method", "route", "limit"} {
+		if value := v.GetString(name); value != "" {
+			mods = append(mods, cdsclient.WithQueryParameter(name, value))
+		}
+	}
+	if targets := v.GetStringArray("target"); len(targets) > 0 {
+		mods = append(mods, func(req *http.Request) {
+			q := req.URL.Query()
+			for _, t := range targets {
+				q.Add("target", t)
+			}
+			req.URL.RawQuery = q.Encode()
+		})
+	}
+	return mods
+}
+
+var adminAuditList = cli.Command{
+	Name:  "list",
+	Short: "List latest security audit log records",
+	Flags: append([]cli.Flag{
+		{Name: "limit", Usage: "Max number of records (max 1000)", Default: "100"},
+	}, adminAuditFilterFlags...),
+}
+
+func adminAuditListFunc(v cli.Values) (cli.ListResult, error) {
+	logs, err := client.AdminAuditLogs(adminAuditFilterMods(v)...)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(logs), nil
+}
+
+var adminAuditExport = cli.Command{
+	Name:  "export",
+	Short: "Export security audit log records as JSON Lines, oldest first",
+	Flags: append([]cli.Flag{
+		{Name: "file", Usage: "Write the export to given file instead of stdout"},
+	}, adminAuditFilterFlags...),
+}
+
+func adminAuditExportFunc(v cli.Values) error {
+	var w io.Writer = os.Stdout
+	if path := v.GetString("file"); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return cli.WrapError(err, "unable to create file %s", path)
+		}
+		defer f.Close()
+		w = f
+	}
+	return client.AdminAuditLogsExport(context.Background(), w, adminAuditFilterMods(v)...)
+}
+
+var adminAuditVerify = cli.Command{
+	Name:  "verify",
+	Short: "Verify the hash chain of an exported security audit log",
+	Long:  "The export should not be filtered, otherwise the chain between records is broken. Hashes are checked with the HMAC key set in the API configuration (audit.hmacKey).",
+	Args: []cli.Arg{
+		{Name: "file"},
+	},
+	Flags: []cli.Flag{
+		{Name: "key-file", Usage: "File that contains the HMAC key of the audit log"},
+	},
+}
+
+func adminAuditVerifyFunc(v cli.Values) error {
+	if v.GetString("key-file") == "" {
+		return cli.NewError("the HMAC key of the audit log is required to verify an export, use --key-file")
+	}
+	key, err := ioutil.ReadFile(v.GetString("key-file"))
+	if err != nil {
+		return cli.WrapError(err, "unable to read key file")
+	}
+
+	f, err := os.Open(v.GetString("file"))
+	if err != nil {
+		return cli.WrapError(err, "unable to open file")
+	}
+	defer f.Close()
+
+	var logs []sdk.AuditLog
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var l sdk.AuditLog
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return cli.WrapError(err, "invalid audit log record at line %d", len(logs)+1)
+		}
+		logs = append(logs, l)
+	}
+	if err := scanner.Err(); err != nil {
+		return cli.WrapError(err, "unable to read file")
+	}
+
+	if err := sdk.VerifyAuditLogs(logs, bytes.TrimSpace(key)); err != nil {
+		return err
+	}
+	fmt.Printf("%d audit log records verified\n", len(logs))
+	return nil
+}
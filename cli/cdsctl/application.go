@@ -27,6 +27,7 @@ func application() *cobra.Command {
 		cli.NewDeleteCommand(applicationDeleteCmd, applicationDeleteRun, nil, withAllCommandModifiers()...),
 		applicationKey(),
 		applicationVariable(),
+		applicationPreview(),
 		cli.NewCommand(applicationExportCmd, applicationExportRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(applicationImportCmd, applicationImportRun, nil, withAllCommandModifiers()...),
 	})
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 
 	"github.com/spf13/cobra"
 
@@ -20,6 +21,9 @@ func applicationKey() *cobra.Command {
 		cli.NewCommand(applicationKeyCreateCmd, applicationCreateKeyRun, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(applicationKeyListCmd, applicationListKeyRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(applicationKeyDeleteCmd, applicationDeleteKeyRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(applicationKeyRotateCmd, applicationRotateKeyRun, nil, withAllCommandModifiers()...),
+		cli.NewGetCommand(applicationKeyUsageCmd, applicationUsageKeyRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(applicationKeyRetireCmd, applicationRetireKeyRun, nil, withAllCommandModifiers()...),
 	})
 }
 
@@ -34,12 +38,30 @@ var applicationKeyCreateCmd = cli.Command{
 		{Name: "key-name"},
 		{Name: "key-type"},
 	},
+	Flags: []cli.Flag{
+		{
+			Name:  "algorithm",
+			Usage: "Algorithm of a generated ssh key: rsa, ed25519 or ecdsa",
+		},
+		{
+			Name:  "private-key-file",
+			Usage: "Import an existing private key from given file instead of generating a new one",
+		},
+	},
 }
 
 func applicationCreateKeyRun(v cli.Values) error {
 	key := &sdk.ApplicationKey{
-		Name: v.GetString("key-name"),
-		Type: sdk.KeyType(v.GetString("key-type")),
+		Name:      v.GetString("key-name"),
+		Type:      sdk.KeyType(v.GetString("key-type")),
+		Algorithm: sdk.SSHKeyAlgorithm(v.GetString("algorithm")),
+	}
+	if file := v.GetString("private-key-file"); file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return cli.WrapError(err, "unable to read file %s", file)
+		}
+		key.Private = string(b)
 	}
 	if err := client.ApplicationKeyCreate(v.GetString(_ProjectKey), v.GetString(_ApplicationName), key); err != nil {
 		return err
@@ -82,3 +104,102 @@ var applicationKeyDeleteCmd = cli.Command{
 func applicationDeleteKeyRun(v cli.Values) error {
 	return client.ApplicationKeysDelete(v.GetString(_ProjectKey), v.GetString(_ApplicationName), v.GetString("key-name"))
 }
+
+var applicationKeyRotateCmd = cli.Command{
+	Name:  "rotate",
+	Short: "Create a successor for a application key, both keys are valid until the old one is retired",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _ApplicationName},
+	},
+	Args: []cli.Arg{
+		{Name: "key-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "successor-name",
+			Usage: "Name of the successor key, generated from the key name if empty",
+		},
+		{
+			Name:  "algorithm",
+			Usage: "Algorithm of the successor ssh key: rsa, ed25519 or ecdsa",
+		},
+		{
+			Name:  "private-key-file",
+			Usage: "Import an existing private key from given file instead of generating a new one",
+		},
+		{
+			Name:    "overlap",
+			Usage:   "Duration in hours while both keys are valid",
+			Default: "168",
+		},
+	},
+}
+
+func applicationRotateKeyRun(v cli.Values) error {
+	overlap, err := v.GetInt64("overlap")
+	if err != nil {
+		return err
+	}
+	req := sdk.KeyRotationRequest{
+		SuccessorName: v.GetString("successor-name"),
+		Algorithm:     sdk.SSHKeyAlgorithm(v.GetString("algorithm")),
+		OverlapHours:  overlap,
+	}
+	if file := v.GetString("private-key-file"); file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return cli.WrapError(err, "unable to read file %s", file)
+		}
+		req.Private = string(b)
+	}
+
+	key, err := client.ApplicationKeyRotate(v.GetString(_ProjectKey), v.GetString(_ApplicationName), v.GetString("key-name"), req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Key %s rotated to %s, retire it once it is not used anymore\n", v.GetString("key-name"), key.Name)
+	fmt.Println(key.Public)
+	return nil
+}
+
+var applicationKeyUsageCmd = cli.Command{
+	Name:  "usage",
+	Short: "Show applications, pipelines and workflows that still reference a application key",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _ApplicationName},
+	},
+	Args: []cli.Arg{
+		{Name: "key-name"},
+	},
+}
+
+func applicationUsageKeyRun(v cli.Values) (interface{}, error) {
+	return client.ApplicationKeyUsage(v.GetString(_ProjectKey), v.GetString(_ApplicationName), v.GetString("key-name"))
+}
+
+var applicationKeyRetireCmd = cli.Command{
+	Name:  "retire",
+	Short: "Retire a application key, it will fail if the key is still used",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _ApplicationName},
+	},
+	Args: []cli.Arg{
+		{Name: "key-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Type:    cli.FlagBool,
+			Name:    "force",
+			Usage:   "Retire the key even if it is still used",
+			Default: "false",
+		},
+	},
+}
+
+func applicationRetireKeyRun(v cli.Values) error {
+	return client.ApplicationKeyRetire(v.GetString(_ProjectKey), v.GetString(_ApplicationName), v.GetString("key-name"), v.GetBool("force"))
+}
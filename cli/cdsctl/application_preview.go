@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var applicationPreviewCmd = cli.Command{
+	Name:    "preview",
+	Aliases: []string{"previews"},
+	Short:   "Manage CDS application preview environments",
+}
+
+func applicationPreview() *cobra.Command {
+	return cli.NewCommand(applicationPreviewCmd, nil, []*cobra.Command{
+		cli.NewListCommand(applicationPreviewListCmd, applicationPreviewListRun, nil, withAllCommandModifiers()...),
+		cli.NewDeleteCommand(applicationPreviewDeleteCmd, applicationPreviewDeleteRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var applicationPreviewListCmd = cli.Command{
+	Name:  "list",
+	Short: "List preview environments of the application pull requests",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _ApplicationName},
+	},
+}
+
+func applicationPreviewListRun(v cli.Values) (cli.ListResult, error) {
+	previews, err := client.ApplicationPreviewEnvironmentList(v.GetString(_ProjectKey), v.GetString(_ApplicationName))
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(previews), nil
+}
+
+var applicationPreviewDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete the preview environment of a pull request without running its teardown pipeline",
+	Long: `Delete the preview environment of a pull request, ie. when its teardown keeps failing. The teardown pipeline is
+not run: the resources deployed for the pull request must be cleaned up by other means.`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _ApplicationName},
+	},
+	Args: []cli.Arg{
+		{Name: "workflow-name"},
+		{Name: "pull-request"},
+	},
+}
+
+func applicationPreviewDeleteRun(v cli.Values) error {
+	pullRequestID, err := strconv.ParseInt(v.GetString("pull-request"), 10, 64)
+	if err != nil {
+		return cli.NewError("invalid pull request number %q", v.GetString("pull-request"))
+	}
+	err = client.ApplicationPreviewEnvironmentDelete(v.GetString(_ProjectKey), v.GetString(_ApplicationName), v.GetString("workflow-name"), pullRequestID)
+	if err != nil && v.GetBool("force") && sdk.ErrorIs(err, sdk.ErrNotFound) {
+		fmt.Println(err.Error())
+		os.Exit(0)
+	}
+	return err
+}
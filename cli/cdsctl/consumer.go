@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -90,10 +91,47 @@ var authConsumerNewCmd = cli.Command{
 		}, {
 			Name:  "duration",
 			Usage: "Validity period of the token generated for the consumer (in days)",
+		}, {
+			Name:  "restrictions",
+			Type:  cli.FlagSlice,
+			Usage: "Restrict the consumer to projects or workflows with a max permission, ex: MY_PROJECT:rx,MY_PROJECT/my-workflow:rwx",
+		}, {
+			Name:  "allowed-cidrs",
+			Type:  cli.FlagSlice,
+			Usage: "Restrict the consumer to source networks, ex: 10.0.0.0/8,192.168.1.0/24",
 		},
 	},
 }
 
+var consumerPermissions = map[string]int{
+	"r":   sdk.PermissionRead,
+	"rx":  sdk.PermissionReadExecute,
+	"rwx": sdk.PermissionReadWriteExecute,
+}
+
+// parseConsumerRestriction parses restriction formatted as PROJECT_KEY[/WORKFLOW_NAME]:permission.
+func parseConsumerRestriction(value string) (sdk.AuthConsumerResourceRestriction, error) {
+	var r sdk.AuthConsumerResourceRestriction
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		return r, errors.Errorf("invalid given restriction %q, expected format is PROJECT_KEY[/WORKFLOW_NAME]:r|rx|rwx", value)
+	}
+	perm, ok := consumerPermissions[strings.ToLower(value[i+1:])]
+	if !ok {
+		return r, errors.Errorf("invalid given permission in restriction %q, expected r, rx or rwx", value)
+	}
+	r.Permission = perm
+	r.ProjectKey = value[:i]
+	if j := strings.Index(r.ProjectKey, "/"); j >= 0 {
+		r.WorkflowName = r.ProjectKey[j+1:]
+		r.ProjectKey = r.ProjectKey[:j]
+	}
+	if r.ProjectKey == "" {
+		return r, errors.Errorf("invalid given restriction %q, missing project key", value)
+	}
+	return r, nil
+}
+
 func authConsumerNewRun(v cli.Values) error {
 	username := v.GetString("username")
 	if username == "" {
@@ -167,12 +205,28 @@ func authConsumerNewRun(v cli.Values) error {
 		duration = time.Duration(iDuration) * (24 * time.Hour)
 	}
 
+	var restrictions sdk.AuthConsumerResourceRestrictions
+	for _, s := range v.GetStringSlice("restrictions") {
+		r, err := parseConsumerRestriction(s)
+		if err != nil {
+			return err
+		}
+		restrictions = append(restrictions, r)
+	}
+
+	allowedCIDRs := sdk.AuthConsumerAllowedCIDRs(v.GetStringSlice("allowed-cidrs"))
+	if err := allowedCIDRs.IsValid(); err != nil {
+		return err
+	}
+
 	res, err := client.AuthConsumerCreateForUser(username, sdk.AuthConsumer{
-		Name:            name,
-		Description:     description,
-		GroupIDs:        groupIDs,
-		ScopeDetails:    sdk.NewAuthConsumerScopeDetails(scopes...),
-		ValidityPeriods: sdk.NewAuthConsumerValidityPeriod(time.Now(), duration),
+		Name:                 name,
+		Description:          description,
+		GroupIDs:             groupIDs,
+		ScopeDetails:         sdk.NewAuthConsumerScopeDetails(scopes...),
+		ValidityPeriods:      sdk.NewAuthConsumerValidityPeriod(time.Now(), duration),
+		ResourceRestrictions: restrictions,
+		AllowedCIDRs:         allowedCIDRs,
 	})
 	if err != nil {
 		return err
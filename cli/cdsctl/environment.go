@@ -28,6 +28,7 @@ func environment() *cobra.Command {
 		cli.NewDeleteCommand(environmentDeleteCmd, environmentDeleteRun, nil, withAllCommandModifiers()...),
 		environmentKey(),
 		environmentVariable(),
+		environmentProtection(),
 		cli.NewCommand(environmentExportCmd, environmentExportRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(environmentImportCmd, environmentImportRun, nil, withAllCommandModifiers()...),
 	})
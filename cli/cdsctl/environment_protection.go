@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var environmentProtectionCmd = cli.Command{
+	Name:  "protection",
+	Short: "Manage CDS environment protection rules",
+	Long: `Protection rules are not part of environment files, they can only be managed from the API or with this command.
+This also applies to environments managed 'as-code'.`,
+}
+
+func environmentProtection() *cobra.Command {
+	return cli.NewCommand(environmentProtectionCmd, nil, []*cobra.Command{
+		cli.NewCommand(environmentProtectionShowCmd, environmentProtectionShowRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(environmentProtectionSetCmd, environmentProtectionSetRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(environmentProtectionRemoveCmd, environmentProtectionRemoveRun, nil, withAllCommandModifiers()...),
+	})
+}
+
+var environmentProtectionShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show the protection rules of an environment",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "env-name"},
+	},
+}
+
+func environmentProtectionShowRun(v cli.Values) error {
+	protection, err := client.EnvironmentProtectionGet(v.GetString(_ProjectKey), v.GetString("env-name"))
+	if err != nil {
+		return err
+	}
+
+	b, err := yaml.Marshal(protection)
+	if err != nil {
+		return cli.WrapError(err, "unable to marshal content")
+	}
+
+	fmt.Println(string(b))
+	return nil
+}
+
+var environmentProtectionSetCmd = cli.Command{
+	Name:  "set",
+	Short: "Set the protection rules of an environment from a yaml file",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "env-name"},
+		{Name: "file"},
+	},
+}
+
+func environmentProtectionSetRun(v cli.Values) error {
+	b, err := ioutil.ReadFile(v.GetString("file"))
+	if err != nil {
+		return cli.WrapError(err, "unable to read file %s", v.GetString("file"))
+	}
+
+	var protection sdk.EnvironmentProtection
+	if err := yaml.Unmarshal(b, &protection); err != nil {
+		return cli.WrapError(err, "unable to load file")
+	}
+
+	if err := client.EnvironmentProtectionUpdate(v.GetString(_ProjectKey), v.GetString("env-name"), protection); err != nil {
+		return err
+	}
+	fmt.Printf("Protection rules updated on environment %s\n", v.GetString("env-name"))
+	return nil
+}
+
+var environmentProtectionRemoveCmd = cli.Command{
+	Name:  "remove",
+	Short: "Remove all the protection rules of an environment",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "env-name"},
+	},
+}
+
+func environmentProtectionRemoveRun(v cli.Values) error {
+	if err := client.EnvironmentProtectionUpdate(v.GetString(_ProjectKey), v.GetString("env-name"), sdk.EnvironmentProtection{}); err != nil {
+		return err
+	}
+	fmt.Printf("Protection rules removed from environment %s\n", v.GetString("env-name"))
+	return nil
+}
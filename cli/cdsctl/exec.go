@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/engine/worker/pkg/local"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	cdslog "github.com/ovh/cds/sdk/log"
+)
+
+var execCmd = cli.Command{
+	Name:  "exec",
+	Short: "Run a pipeline locally from its file without calling CDS API",
+	Long: `Run the jobs of a pipeline file on your machine with the worker builtin actions. Script steps can be executed in a Docker container with the --docker flag.
+
+Pipeline parameters can be given with --parameter name=value, secrets are read from a YAML file that contains a map of variable names to values (ex: cds.proj.password: mypassword).
+
+Checkout application steps copy the sources directory (default current directory) in the job workspace. Artifacts and cache are stored in a local directory (default user cache directory).
+
+Deployment, release, tag and plugin steps are skipped, worker commands are not available in scripts.`,
+	Example: `cdsctl exec .cds/build.pip.yml
+cdsctl exec .cds/build.pip.yml --job Build --parameter branch=master --secrets-file secrets.yml
+cdsctl exec .cds/build.pip.yml --docker golang:1.13`,
+	Args: []cli.Arg{
+		{Name: "path"},
+	},
+	Flags: []cli.Flag{
+		{
+			Type:  cli.FlagArray,
+			Name:  "job",
+			Usage: "Specify a job to run, all jobs are executed if not set",
+		},
+		{
+			Type:      cli.FlagArray,
+			Name:      "parameter",
+			ShortHand: "p",
+			Usage:     "Specify a parameter like --parameter name=value",
+		},
+		{
+			Type:  cli.FlagString,
+			Name:  "secrets-file",
+			Usage: "Specify a YAML file that contains secret values",
+		},
+		{
+			Type:  cli.FlagString,
+			Name:  "directory",
+			Usage: "Specify the directory where artifacts and cache are stored",
+		},
+		{
+			Type:  cli.FlagString,
+			Name:  "sources",
+			Usage: "Specify the directory copied by checkout application steps",
+		},
+		{
+			Type:  cli.FlagString,
+			Name:  "docker",
+			Usage: "Specify a Docker image to run script steps in a container",
+		},
+	},
+}
+
+func execCommand() *cobra.Command {
+	return cli.NewCommand(execCmd, execRun, nil, cli.CommandWithoutExtraFlags)
+}
+
+func execRun(v cli.Values) error {
+	path := v.GetString("path")
+	btes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cli.WrapError(err, "unable to read file %s", path)
+	}
+	format, err := exportentities.GetFormatFromPath(path)
+	if err != nil {
+		return cli.WrapError(sdk.Cause(err), "unable to get format of file %s", path)
+	}
+	payload, err := exportentities.ParsePipeline(format, btes)
+	if err != nil {
+		return cli.WrapError(sdk.Cause(err), "unable to parse pipeline %s", path)
+	}
+	pip, err := payload.Pipeline()
+	if err != nil {
+		return cli.WrapError(sdk.Cause(err), "unable to parse pipeline %s", path)
+	}
+
+	opts := local.Options{
+		Jobs:        v.GetStringArray("job"),
+		Parameters:  make(map[string]string),
+		Secrets:     make(map[string]string),
+		Directory:   v.GetString("directory"),
+		Sources:     v.GetString("sources"),
+		DockerImage: v.GetString("docker"),
+		Output:      os.Stdout,
+	}
+
+	for _, p := range v.GetStringArray("parameter") {
+		ps := strings.SplitN(p, "=", 2)
+		if len(ps) < 2 {
+			return cli.NewError("Invalid given parameter %s", ps[0])
+		}
+		opts.Parameters[ps[0]] = ps[1]
+	}
+
+	if f := v.GetString("secrets-file"); f != "" {
+		btes, err := ioutil.ReadFile(f)
+		if err != nil {
+			return cli.WrapError(err, "unable to read secrets file %s", f)
+		}
+		if err := yaml.Unmarshal(btes, &opts.Secrets); err != nil {
+			return cli.WrapError(err, "unable to parse secrets file %s", f)
+		}
+	}
+
+	if opts.Directory == "" {
+		dir, err := os.UserCacheDir()
+		if err != nil {
+			return cli.WrapError(err, "unable to get user cache directory")
+		}
+		opts.Directory = filepath.Join(dir, "cdsctl", "exec")
+	}
+	if opts.Sources == "" {
+		opts.Sources = "."
+	}
+	if opts.Directory, err = filepath.Abs(opts.Directory); err != nil {
+		return cli.WrapError(err, "invalid directory")
+	}
+	if opts.Sources, err = filepath.Abs(opts.Sources); err != nil {
+		return cli.WrapError(err, "invalid sources directory")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Worker logs are only displayed in verbose mode, steps logs are written on stdout
+	logConf := cdslog.Conf{Format: "discard"}
+	if cli.Verbose {
+		logConf = cdslog.Conf{Level: "debug"}
+	}
+	cdslog.Initialize(ctx, &logConf)
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt)
+	go func() {
+		<-sigs
+		cancel()
+	}()
+
+	if err := local.RunPipeline(ctx, *pip, opts); err != nil {
+		return cli.NewError("%v", sdk.Cause(err))
+	}
+	return nil
+}
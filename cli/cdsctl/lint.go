@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities/lint"
+)
+
+var lintCmd = cli.Command{
+	Name:  "lint",
+	Short: "Check workflow as-code files without calling CDS API",
+	Long: `Check workflow, pipeline, application and environment files from given directory (default .cds) without calling CDS API.
+
+The following rules are checked:
+
+* syntax: files can be parsed like on a workflow push
+* reference: pipelines, applications, environments and pipeline parameters used by workflow nodes are declared in the directory
+* interpolation: interpolated values are valid, parameters and variables used by pipelines are declared
+* requirement: job requirements have a known type and are valid
+* condition: plain conditions use a known operator and lua scripts are valid
+* hook: hooks use a known model and a valid configuration
+
+The command exits with code 1 if at least one error is found. The output format can be text, json or sarif for code scanning annotations.`,
+	Example: `cdsctl lint
+cdsctl lint .cds --format sarif > cds.sarif`,
+	OptionalArgs: []cli.Arg{
+		{Name: "path"},
+	},
+	Flags: []cli.Flag{
+		{
+			Type:    cli.FlagString,
+			Name:    "format",
+			Usage:   "Specify output format (text, json or sarif)",
+			Default: "text",
+			IsValid: func(s string) bool {
+				return s == "text" || s == "json" || s == "sarif"
+			},
+		},
+	},
+}
+
+func lintCommand() *cobra.Command {
+	return cli.NewCommand(lintCmd, lintRun, nil, cli.CommandWithoutExtraFlags)
+}
+
+func lintRun(v cli.Values) error {
+	path := v.GetString("path")
+	if path == "" {
+		path = ".cds"
+	}
+
+	issues, err := lint.Dir(path)
+	if err != nil {
+		return cli.WrapError(sdk.Cause(err), "unable to lint directory %s", path)
+	}
+
+	switch v.GetString("format") {
+	case "json":
+		if issues == nil {
+			issues = lint.Issues{}
+		}
+		btes, err := json.MarshalIndent(issues, "", "  ")
+		if err != nil {
+			return cli.WrapError(err, "unable to marshal issues")
+		}
+		fmt.Println(string(btes))
+	case "sarif":
+		btes, err := issues.SARIF(sdk.VersionCurrent().Version)
+		if err != nil {
+			return cli.WrapError(err, "unable to marshal issues")
+		}
+		fmt.Println(string(btes))
+	default:
+		var nbErrors, nbWarnings int
+		for _, i := range issues {
+			if i.Severity == lint.SeverityError {
+				nbErrors++
+				fmt.Println(cli.Red("%s", i.String()))
+			} else {
+				nbWarnings++
+				fmt.Println(cli.Yellow("%s", i.String()))
+			}
+		}
+		if len(issues) == 0 {
+			fmt.Printf("%s no issue found in %s\n", cli.OKChar, path)
+		} else {
+			fmt.Printf("%d error(s), %d warning(s) found in %s\n", nbErrors, nbWarnings, path)
+		}
+	}
+
+	if issues.HasErrors() {
+		cli.OSExit(1)
+	}
+	return nil
+}
@@ -28,9 +28,11 @@ func main() {
 		encrypt(),
 		contexts(),
 		environment(),
+		execCommand(),
 		events(),
 		group(),
 		health(),
+		lintCommand(),
 		login(),
 		reset(),
 		signup(),
@@ -76,6 +78,8 @@ func rootFromSubCommands(cmds []*cobra.Command) *cobra.Command {
 			cmd.Name() == "reset-password" ||
 			cmd.Name() == "confirm" ||
 			cmd.Name() == "version" ||
+			cmd.Name() == "lint" ||
+			cmd.Name() == "exec" ||
 			cmd.Name() == "doc" || strings.HasPrefix(cmd.Use, "doc ") || (cmd.Run == nil && cmd.RunE == nil) {
 			return
 		}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 
 	"github.com/spf13/cobra"
 
@@ -20,6 +21,9 @@ func projectKey() *cobra.Command {
 		cli.NewCommand(projectKeyCreateCmd, projectCreateKeyRun, nil, withAllCommandModifiers()...),
 		cli.NewListCommand(projectKeyListCmd, projectListKeyRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(projectKeyDeleteCmd, projectDeleteKeyRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectKeyRotateCmd, projectRotateKeyRun, nil, withAllCommandModifiers()...),
+		cli.NewGetCommand(projectKeyUsageCmd, projectUsageKeyRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(projectKeyRetireCmd, projectRetireKeyRun, nil, withAllCommandModifiers()...),
 	})
 }
 
@@ -33,12 +37,30 @@ var projectKeyCreateCmd = cli.Command{
 		{Name: "key-name"},
 		{Name: "key-type"},
 	},
+	Flags: []cli.Flag{
+		{
+			Name:  "algorithm",
+			Usage: "Algorithm of a generated ssh key: rsa, ed25519 or ecdsa",
+		},
+		{
+			Name:  "private-key-file",
+			Usage: "Import an existing private key from given file instead of generating a new one",
+		},
+	},
 }
 
 func projectCreateKeyRun(v cli.Values) error {
 	key := &sdk.ProjectKey{
-		Name: v.GetString("key-name"),
-		Type: sdk.KeyType(v.GetString("key-type")),
+		Name:      v.GetString("key-name"),
+		Type:      sdk.KeyType(v.GetString("key-type")),
+		Algorithm: sdk.SSHKeyAlgorithm(v.GetString("algorithm")),
+	}
+	if file := v.GetString("private-key-file"); file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return cli.WrapError(err, "unable to read file %s", file)
+		}
+		key.Private = string(b)
 	}
 	if err := client.ProjectKeyCreate(v.GetString(_ProjectKey), key); err != nil {
 		return err
@@ -79,3 +101,99 @@ var projectKeyDeleteCmd = cli.Command{
 func projectDeleteKeyRun(v cli.Values) error {
 	return client.ProjectKeysDelete(v.GetString(_ProjectKey), v.GetString("key-name"))
 }
+
+var projectKeyRotateCmd = cli.Command{
+	Name:  "rotate",
+	Short: "Create a successor for a project key, both keys are valid until the old one is retired",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "key-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "successor-name",
+			Usage: "Name of the successor key, generated from the key name if empty",
+		},
+		{
+			Name:  "algorithm",
+			Usage: "Algorithm of the successor ssh key: rsa, ed25519 or ecdsa",
+		},
+		{
+			Name:  "private-key-file",
+			Usage: "Import an existing private key from given file instead of generating a new one",
+		},
+		{
+			Name:    "overlap",
+			Usage:   "Duration in hours while both keys are valid",
+			Default: "168",
+		},
+	},
+}
+
+func projectRotateKeyRun(v cli.Values) error {
+	overlap, err := v.GetInt64("overlap")
+	if err != nil {
+		return err
+	}
+	req := sdk.KeyRotationRequest{
+		SuccessorName: v.GetString("successor-name"),
+		Algorithm:     sdk.SSHKeyAlgorithm(v.GetString("algorithm")),
+		OverlapHours:  overlap,
+	}
+	if file := v.GetString("private-key-file"); file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return cli.WrapError(err, "unable to read file %s", file)
+		}
+		req.Private = string(b)
+	}
+
+	key, err := client.ProjectKeyRotate(v.GetString(_ProjectKey), v.GetString("key-name"), req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Key %s rotated to %s, retire it once it is not used anymore\n", v.GetString("key-name"), key.Name)
+	fmt.Println(key.Public)
+	return nil
+}
+
+var projectKeyUsageCmd = cli.Command{
+	Name:  "usage",
+	Short: "Show applications, pipelines and workflows that still reference a project key",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "key-name"},
+	},
+}
+
+func projectUsageKeyRun(v cli.Values) (interface{}, error) {
+	return client.ProjectKeyUsage(v.GetString(_ProjectKey), v.GetString("key-name"))
+}
+
+var projectKeyRetireCmd = cli.Command{
+	Name:  "retire",
+	Short: "Retire a project key, it will fail if the key is still used",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+	Args: []cli.Arg{
+		{Name: "key-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Type:    cli.FlagBool,
+			Name:    "force",
+			Usage:   "Retire the key even if it is still used",
+			Default: "false",
+		},
+	},
+}
+
+func projectRetireKeyRun(v cli.Values) error {
+	return client.ProjectKeyRetire(v.GetString(_ProjectKey), v.GetString("key-name"), v.GetBool("force"))
+}
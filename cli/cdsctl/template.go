@@ -33,6 +33,9 @@ func template() *cobra.Command {
 			cli.NewCommand(templateInstancesExportCmd, templateInstancesExportRun, nil, withAllCommandModifiers()...),
 		}),
 		cli.NewCommand(templateDetachCmd, templateDetachRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(templateReleaseCmd, templateReleaseRun, nil, withAllCommandModifiers()...),
+		cli.NewListCommand(templateReleasesCmd, templateReleasesRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(templateUpgradeCmd, templateUpgradeRun, nil, withAllCommandModifiers()...),
 	})
 }
 
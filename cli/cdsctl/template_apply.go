@@ -212,22 +212,24 @@ func templateApplyRun(v cli.Values) error {
 		var listRepositories []string
 		var listSSHKeys []string
 		var listPGPKeys []string
+		var listEnvironments []string
+		var listIntegrations []string
 		var localRepoPath string
 
 		// if there are params of type repository in list of params to fill prepare
 		// the list of repositories for project
-		var withRepository bool
-		var withKey bool
+		var withRepository, withKey, withEnvironment, withIntegration bool
 		for _, p := range wt.Parameters {
 			if _, ok := params[p.Key]; !ok {
-				if p.Type == sdk.ParameterTypeRepository {
+				switch p.Type {
+				case sdk.ParameterTypeRepository:
 					withRepository = true
-				}
-				if p.Type == sdk.ParameterTypeSSHKey || p.Type == sdk.ParameterTypePGPKey {
+				case sdk.ParameterTypeSSHKey, sdk.ParameterTypePGPKey:
 					withKey = true
-				}
-				if withRepository && withKey {
-					break
+				case sdk.ParameterTypeEnvironment:
+					withEnvironment = true
+				case sdk.ParameterTypeIntegration:
+					withIntegration = true
 				}
 			}
 		}
@@ -266,6 +268,24 @@ func templateApplyRun(v cli.Values) error {
 				}
 			}
 		}
+		if withEnvironment {
+			envs, err := client.EnvironmentList(projectKey)
+			if err != nil {
+				return err
+			}
+			for _, e := range envs {
+				listEnvironments = append(listEnvironments, e.Name)
+			}
+		}
+		if withIntegration {
+			integs, err := client.ProjectIntegrationList(projectKey)
+			if err != nil {
+				return err
+			}
+			for _, i := range integs {
+				listIntegrations = append(listIntegrations, i.Name)
+			}
+		}
 
 		// for each param not already fill ask for the value
 		for _, p := range wt.Parameters {
@@ -291,11 +311,32 @@ func templateApplyRun(v cli.Values) error {
 						selected := cli.AskChoice(label, listPGPKeys...)
 						choice = listPGPKeys[selected]
 					}
+				case sdk.ParameterTypeEnvironment:
+					if len(listEnvironments) > 0 {
+						selected := cli.AskChoice(label, listEnvironments...)
+						choice = listEnvironments[selected]
+					}
+				case sdk.ParameterTypeIntegration:
+					if len(listIntegrations) > 0 {
+						selected := cli.AskChoice(label, listIntegrations...)
+						choice = listIntegrations[selected]
+					}
+				case sdk.ParameterTypeChoice:
+					selected := cli.AskChoice(label, p.Choices...)
+					choice = p.Choices[selected]
+				case sdk.ParameterTypeList:
+					if len(p.Choices) > 0 {
+						var items []string
+						for _, i := range cli.AskSelect(label, p.Choices...) {
+							items = append(items, p.Choices[i])
+						}
+						choice = strings.Join(items, ",")
+					}
 				case sdk.ParameterTypeBoolean:
 					choice = fmt.Sprintf("%t", cli.AskConfirm(fmt.Sprintf("Set value to 'true' for param '%s'", p.Key)))
 				}
 				if choice == "" {
-					choice = cli.AskValue(label)
+					choice = askTemplateParameterValue(p, label)
 				}
 
 				params[p.Key] = choice
@@ -377,6 +418,35 @@ func templateApplyRun(v cli.Values) error {
 	return workflowTarReaderToFiles(v, dir, tr)
 }
 
+// askTemplateParameterValue asks for a value until it is valid for given parameter.
+func askTemplateParameterValue(p sdk.WorkflowTemplateParameter, label string) string {
+	switch p.Type {
+	case sdk.ParameterTypeInteger:
+		switch {
+		case p.Min != nil && p.Max != nil:
+			label = fmt.Sprintf("%s between %d and %d", label, *p.Min, *p.Max)
+		case p.Min != nil:
+			label = fmt.Sprintf("%s greater or equal to %d", label, *p.Min)
+		case p.Max != nil:
+			label = fmt.Sprintf("%s lower or equal to %d", label, *p.Max)
+		}
+	case sdk.ParameterTypeList:
+		label += " as comma separated values"
+	}
+	if p.Pattern != "" {
+		label = fmt.Sprintf("%s matching %s", label, p.Pattern)
+	}
+
+	for {
+		v := cli.AskValue(label)
+		err := p.CheckValue(v)
+		if err == nil {
+			return v
+		}
+		fmt.Println(err)
+	}
+}
+
 func teeTarReader(r *tar.Reader, buf io.Writer) (*tar.Reader, error) {
 	var b bytes.Buffer
 	tw1, tw2 := tar.NewWriter(&b), tar.NewWriter(buf)
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+var templateReleaseCmd = cli.Command{
+	Name:    "release",
+	Short:   "Create a semver release of a CDS workflow template",
+	Example: "cdsctl template release group-name/template-slug 2.1.0 --changelog \"Add a deploy pipeline\"",
+	Args: []cli.Arg{
+		{Name: "template-path"},
+		{Name: "version"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "changelog",
+			Usage: "Description of the changes in this release",
+		},
+	},
+}
+
+func templateReleaseRun(v cli.Values) error {
+	groupName, templateSlug, err := cli.ParsePath(v.GetString("template-path"))
+	if err != nil {
+		return err
+	}
+
+	rel, err := client.TemplateRelease(groupName, templateSlug, sdk.WorkflowTemplateRelease{
+		Version:   v.GetString("version"),
+		Changelog: v.GetString("changelog"),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Release %s created for template %s/%s\n", rel.Version, groupName, templateSlug)
+	return nil
+}
+
+var templateReleasesCmd = cli.Command{
+	Name:    "releases",
+	Short:   "Get releases of a CDS workflow template",
+	Example: "cdsctl template releases group-name/template-slug",
+	OptionalArgs: []cli.Arg{
+		{Name: "template-path"},
+	},
+}
+
+func templateReleasesRun(v cli.Values) (cli.ListResult, error) {
+	wt, err := getTemplateFromCLI(v)
+	if err != nil {
+		return nil, err
+	}
+	if wt == nil {
+		wt, err = suggestTemplate()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rs, err := client.TemplateGetReleases(wt.Group.Name, wt.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	type TemplateReleaseDisplay struct {
+		Version         string `cli:"version,key"`
+		TemplateVersion int64  `cli:"template_version"`
+		Created         string `cli:"created"`
+		Changelog       string `cli:"changelog"`
+	}
+
+	rds := make([]TemplateReleaseDisplay, len(rs))
+	for i := range rs {
+		rds[i].Version = rs[i].Version
+		rds[i].TemplateVersion = rs[i].TemplateVersion
+		rds[i].Created = fmt.Sprintf("On %s by %s", rs[i].Created.Format(time.RFC3339), rs[i].Author)
+		rds[i].Changelog = rs[i].Changelog
+	}
+
+	return cli.AsListResult(rds), nil
+}
+
+var templateUpgradeCmd = cli.Command{
+	Name:  "upgrade",
+	Short: "Upgrade a workflow to the latest template release that matches a version range",
+	Long: `Render the changes between the current workflow and the latest template release that matches given version range (or the range pinned on the workflow), then apply them.
+For as code workflows, a pull request is opened on the workflow repository.`,
+	Example: "cdsctl template upgrade project-key workflow-name ^2.1 --dry-run",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName, AllowEmpty: true},
+	},
+	OptionalArgs: []cli.Arg{
+		{Name: "version-range"},
+	},
+	Flags: []cli.Flag{
+		{
+			Type:    cli.FlagBool,
+			Name:    "dry-run",
+			Usage:   "Only display the changes without applying them",
+			Default: "false",
+		},
+		{
+			Name:  "branch",
+			Usage: "Branch name of the pull request for as code workflow",
+		},
+		{
+			Name:  "message",
+			Usage: "Commit message of the pull request for as code workflow",
+		},
+	},
+}
+
+func templateUpgradeRun(v cli.Values) error {
+	projectKey := v.GetString(_ProjectKey)
+	workflowName := v.GetString(_WorkflowName)
+
+	wk, err := client.WorkflowGet(projectKey, workflowName, cdsclient.WithTemplate())
+	if err != nil {
+		return err
+	}
+	if wk.TemplateInstance == nil {
+		return cli.NewError("given workflow was not generated by a template")
+	}
+
+	res, err := client.TemplateUpgradeInstance(wk.TemplateInstance.Template.Group.Name, wk.TemplateInstance.Template.Slug, wk.TemplateInstance.ID,
+		sdk.WorkflowTemplateUpgradeRequest{
+			VersionRange: v.GetString("version-range"),
+			DryRun:       v.GetBool("dry-run"),
+			Branch:       v.GetString("branch"),
+			Message:      v.GetString("message"),
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Upgrade template %s from %s to %s\n", wk.TemplateInstance.Template.Path(), res.FromVersion, res.ToVersion)
+	if len(res.Changelogs) > 0 {
+		fmt.Printf("\nChangelog:\n- %s\n", strings.Join(res.Changelogs, "\n- "))
+	}
+	if res.Diff == "" {
+		fmt.Println("\nNo changes in generated workflow")
+	} else {
+		fmt.Printf("\n%s\n", res.Diff)
+	}
+
+	switch {
+	case res.Operation != nil:
+		fmt.Printf("Pull request creation requested for workflow %s/%s (operation %s)\n", projectKey, workflowName, res.Operation.UUID)
+	case res.Applied:
+		fmt.Printf("Workflow %s/%s successfully upgraded\n", projectKey, workflowName)
+	}
+
+	return nil
+}
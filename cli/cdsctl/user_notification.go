@@ -0,0 +1,99 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var userNotificationCmd = cli.Command{
+	Name:    "notifications",
+	Aliases: []string{"notification", "notif"},
+	Short:   "Manage your notification subscriptions",
+	Long: `Subscribe to the end of workflow runs in a project or of the runs you triggered.
+
+Notifications are sent by email to your primary email address or on a chat incoming webhook (slack, mattermost or teams),
+immediately or grouped in an hourly or daily digest.`,
+}
+
+func userNotification() *cobra.Command {
+	return cli.NewCommand(userNotificationCmd, nil, []*cobra.Command{
+		cli.NewListCommand(userNotificationListCmd, userNotificationListRun, nil),
+		cli.NewGetCommand(userNotificationAddCmd, userNotificationAddRun, nil),
+		cli.NewDeleteCommand(userNotificationDeleteCmd, userNotificationDeleteRun, nil),
+	})
+}
+
+var userNotificationListCmd = cli.Command{
+	Name:    "list",
+	Short:   "List your notification subscriptions",
+	Aliases: []string{"ls"},
+}
+
+func userNotificationListRun(v cli.Values) (cli.ListResult, error) {
+	subs, err := client.UserNotificationSubscriptionList("me")
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(subs), nil
+}
+
+var userNotificationAddCmd = cli.Command{
+	Name:  "add",
+	Short: "Subscribe to workflow run notifications",
+	Example: `# Get a daily email digest of the failures on master in project MYPROJ
+cdsctl user notifications add --project MYPROJ --branch master --status Fail --mode daily
+
+# Be notified on Slack when a run you triggered ends
+cdsctl user notifications add --triggered-by-me --channel chat --chat-type slack --webhook-url https://hooks.slack.com/services/...`,
+	Flags: []cli.Flag{
+		{Name: "project", Usage: "Key of the project"},
+		{Name: "workflow", Usage: "Name of the workflow, requires a project"},
+		{Name: "branch", Usage: "Only runs on given git branch"},
+		{Name: "status", Type: cli.FlagArray, Usage: "Only runs ending with given status (Success, Fail, Stopped), default is any"},
+		{Name: "triggered-by-me", Type: cli.FlagBool, Usage: "Only runs you triggered"},
+		{Name: "channel", Default: sdk.UserSubscriptionChannelEmail, Usage: "Channel of the notifications: email or chat"},
+		{Name: "chat-type", Usage: "Type of chat: slack, mattermost or teams"},
+		{Name: "webhook-url", Usage: "Incoming webhook url of the chat"},
+		{Name: "mode", Default: sdk.UserSubscriptionModeImmediate, Usage: "Delivery mode: immediate, hourly or daily"},
+	},
+}
+
+func userNotificationAddRun(v cli.Values) (interface{}, error) {
+	s := sdk.UserNotificationSubscription{
+		ProjectKey:    v.GetString("project"),
+		WorkflowName:  v.GetString("workflow"),
+		Branch:        v.GetString("branch"),
+		Statuses:      v.GetStringArray("status"),
+		TriggeredByMe: v.GetBool("triggered-by-me"),
+		Channel:       v.GetString("channel"),
+		ChatType:      v.GetString("chat-type"),
+		WebhookURL:    v.GetString("webhook-url"),
+		Mode:          v.GetString("mode"),
+	}
+	if err := s.IsValid(); err != nil {
+		return nil, err
+	}
+	if err := client.UserNotificationSubscriptionCreate("me", &s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+var userNotificationDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a notification subscription",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+	Aliases: []string{"rm", "del"},
+}
+
+func userNotificationDeleteRun(v cli.Values) error {
+	id, err := v.GetInt64("id")
+	if err != nil {
+		return err
+	}
+	return client.UserNotificationSubscriptionDelete("me", id)
+}
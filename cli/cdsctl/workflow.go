@@ -26,6 +26,8 @@ func workflow() *cobra.Command {
 		cli.NewGetCommand(workflowStatusCmd, workflowStatusRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowRunManualCmd, workflowRunManualRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowStopCmd, workflowStopRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowApproveCmd, workflowApproveRun, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowRejectCmd, workflowRejectRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowExportCmd, workflowExportRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowImportCmd, workflowImportRun, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowPullCmd, workflowPullRun, nil, withAllCommandModifiers()...),
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowApproveCmd = cli.Command{
+	Name:  "approve",
+	Short: "Approve a CDS workflow node run waiting for approval",
+	Example: `cdsctl workflow approve MYPROJECT myworkflow 5 deploy-prod
+cdsctl workflow approve MYPROJECT myworkflow 5 deploy-prod --comment "change CHG-1234 validated"`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "run-number"},
+		{Name: "node-name"},
+	},
+	Flags: []cli.Flag{
+		{Name: "comment", Usage: "Comment recorded with the approval"},
+	},
+}
+
+var workflowRejectCmd = cli.Command{
+	Name:    "reject",
+	Short:   "Reject a CDS workflow node run waiting for approval, the node run is stopped",
+	Example: `cdsctl workflow reject MYPROJECT myworkflow 5 deploy-prod --comment "freeze period"`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{Name: "run-number"},
+		{Name: "node-name"},
+	},
+	Flags: []cli.Flag{
+		{Name: "comment", Usage: "Comment recorded with the rejection"},
+	},
+}
+
+func workflowApproveRun(v cli.Values) error {
+	return workflowDecideApproval(v, true)
+}
+
+func workflowRejectRun(v cli.Values) error {
+	return workflowDecideApproval(v, false)
+}
+
+func workflowDecideApproval(v cli.Values, approved bool) error {
+	projectKey := v.GetString(_ProjectKey)
+	workflowName := v.GetString(_WorkflowName)
+	runNumber, err := v.GetInt64("run-number")
+	if err != nil {
+		return err
+	}
+
+	wr, err := client.WorkflowRunGet(projectKey, workflowName, runNumber)
+	if err != nil {
+		return err
+	}
+	var nodeRun *sdk.WorkflowNodeRun
+	for _, wnrs := range wr.WorkflowNodeRuns {
+		if len(wnrs) > 0 && wnrs[0].WorkflowNodeName == v.GetString("node-name") {
+			nodeRun = &wnrs[0]
+			break
+		}
+	}
+	if nodeRun == nil {
+		return cli.NewError("node %s not found in workflow run %d", v.GetString("node-name"), runNumber)
+	}
+	if !nodeRun.Approval.IsPending() {
+		return cli.NewError("node %s is not waiting for approval", v.GetString("node-name"))
+	}
+
+	if !approved {
+		if _, err := client.WorkflowNodeRunReject(projectKey, workflowName, runNumber, nodeRun.ID, v.GetString("comment")); err != nil {
+			return err
+		}
+		fmt.Printf("Workflow node %s from workflow %s #%d has been rejected\n", nodeRun.WorkflowNodeName, workflowName, runNumber)
+		return nil
+	}
+
+	res, err := client.WorkflowNodeRunApprove(projectKey, workflowName, runNumber, nodeRun.ID, v.GetString("comment"))
+	if err != nil {
+		return err
+	}
+	if res.Approval.IsPending() {
+		fmt.Printf("Workflow node %s from workflow %s #%d has been approved (%d/%d approvals)\n", nodeRun.WorkflowNodeName, workflowName, runNumber, res.Approval.Approvals(), res.Approval.MinRequiredApprovals())
+	} else {
+		fmt.Printf("Workflow node %s from workflow %s #%d has been approved\n", nodeRun.WorkflowNodeName, workflowName, runNumber)
+	}
+	return nil
+}
@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"regexp"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/ovh/cds/cli"
 	"github.com/ovh/cds/sdk"
@@ -28,6 +30,8 @@ func workflowRunResult() *cobra.Command {
 	return cli.NewCommand(workflowRunResultCmd, nil, []*cobra.Command{
 		cli.NewListCommand(workflowRunResultListCmd, workflowRunResultList, nil, withAllCommandModifiers()...),
 		cli.NewCommand(workflowRunResultGetCmd, workflowRunResultGet, nil, withAllCommandModifiers()...),
+		cli.NewCommand(workflowRunResultVerifyCmd, workflowRunResultVerify, nil, withAllCommandModifiers()...),
+		cli.NewGetCommand(workflowRunResultProvenanceKeyCmd, workflowRunResultProvenanceKey, nil, withAllCommandModifiers()...),
 	})
 }
 
@@ -100,6 +104,15 @@ func workflowRunResultGet(v cli.Values) error {
 			fileName = cov.Name
 			perm = cov.Perm
 			md5 = cov.MD5
+		case sdk.WorkflowRunResultTypeProvenance:
+			prov, err := r.GetProvenance()
+			if err != nil {
+				return err
+			}
+			cdnHash = prov.CDNRefHash
+			fileName = prov.Name
+			perm = prov.Perm
+			md5 = prov.MD5
 		default:
 			return cli.NewError("cannot get result of type %s", r.Type)
 		}
@@ -231,6 +244,12 @@ func toCLIRunResult(results []sdk.WorkflowRunResult) ([]RunResultCli, error) {
 			}
 			name = artiResult.Name
 			artiType = artiResult.RepoType
+		case sdk.WorkflowRunResultTypeProvenance:
+			provResult, err := r.GetProvenance()
+			if err != nil {
+				return nil, err
+			}
+			name = provResult.Name
 		}
 
 		cliresults = append(cliresults, RunResultCli{
@@ -241,3 +260,164 @@ func toCLIRunResult(results []sdk.WorkflowRunResult) ([]RunResultCli, error) {
 	}
 	return cliresults, nil
 }
+
+var workflowRunResultVerifyCmd = cli.Command{
+	Name:  "verify",
+	Short: "Verify a file against the signed provenance of a workflow run result",
+	Long: `Verify that a file was produced by a workflow run, using the provenance attestation signed by CDS for the run result.
+
+The signature of the attestation is checked with the public part of the project provenance key, then the SHA-256 digest of the file is compared to the attested one.
+
+	$ cdsctl workflow result verify MYPROJ my-workflow 42 my-binary --file ./bin/my-binary
+`,
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+		{Name: _WorkflowName},
+	},
+	Args: []cli.Arg{
+		{
+			Name: "run-number",
+			IsValid: func(s string) bool {
+				match, _ := regexp.MatchString(`[0-9]?`, s)
+				return match
+			},
+		},
+		{Name: "artifact-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "file",
+			Usage: "Path of the file to verify, default is the artifact name in the current directory",
+		},
+		{
+			Name:  "public-key",
+			Usage: "Path of the public key to use instead of the project provenance key",
+		},
+	},
+}
+
+func workflowRunResultVerify(v cli.Values) error {
+	ctx := context.Background()
+	runNumber, err := v.GetInt64("run-number")
+	if err != nil {
+		return err
+	}
+	artifactName := v.GetString("artifact-name")
+	filePath := v.GetString("file")
+	if filePath == "" {
+		filePath = artifactName
+	}
+
+	runResults, err := client.WorkflowRunResultsList(ctx, v.GetString(_ProjectKey), v.GetString(_WorkflowName), runNumber)
+	if err != nil {
+		return err
+	}
+	var provenance *sdk.WorkflowRunResultProvenance
+	for i := range runResults {
+		if runResults[i].Type != sdk.WorkflowRunResultTypeProvenance {
+			continue
+		}
+		p, err := runResults[i].GetProvenance()
+		if err != nil {
+			return err
+		}
+		if p.Subject == artifactName {
+			provenance = &p
+			break
+		}
+	}
+	if provenance == nil {
+		return cli.NewError("no provenance found for %s in run %d", artifactName, runNumber)
+	}
+
+	// Load the public key
+	var publicKey string
+	if path := v.GetString("public-key"); path != "" {
+		btes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return cli.WrapError(err, "unable to read public key %s", path)
+		}
+		publicKey = string(btes)
+	} else {
+		k, err := client.ProjectProvenanceKeyGet(v.GetString(_ProjectKey))
+		if err != nil {
+			return err
+		}
+		if k.Name != provenance.KeyName {
+			return cli.NewError("provenance key %s used to sign the provenance not found", provenance.KeyName)
+		}
+		publicKey = k.Public
+	}
+	sshPub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return cli.WrapError(err, "invalid public key")
+	}
+	cryptoPub, ok := sshPub.(ssh.CryptoPublicKey)
+	if !ok {
+		return cli.NewError("unsupported public key type %s", sshPub.Type())
+	}
+
+	// Download the attestation
+	confCDN, err := client.ConfigCDN()
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile("", "provenance")
+	if err != nil {
+		return cli.WrapError(err, "unable to create temporary file")
+	}
+	defer os.Remove(tmp.Name()) // nolint
+	if err := client.CDNItemDownload(ctx, confCDN.HTTPURL, provenance.CDNRefHash, sdk.CDNTypeItemRunResult, provenance.MD5, tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return cli.WrapError(err, "unable to close file %s", tmp.Name())
+	}
+	content, err := ioutil.ReadFile(tmp.Name())
+	if err != nil {
+		return cli.WrapError(err, "unable to read provenance")
+	}
+	var envelope sdk.DSSEEnvelope
+	if err := sdk.JSONUnmarshal(content, &envelope); err != nil {
+		return cli.WrapError(err, "invalid provenance")
+	}
+
+	statement, err := envelope.Verify(provenance.KeyName, cryptoPub.CryptoPublicKey())
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return cli.WrapError(err, "unable to open file %s", filePath)
+	}
+	defer f.Close() // nolint
+	if err := statement.VerifySubject(artifactName, f); err != nil {
+		return err
+	}
+
+	env := statement.Predicate.Invocation.Environment
+	fmt.Printf("File %s verified: signed by %s with key %s\n", filePath, statement.Predicate.Builder.ID, provenance.KeyName)
+	fmt.Printf("Built by %s/%s #%d.%d (pipeline %s, job %s)\n", env.ProjectKey, env.WorkflowName, env.RunNumber, env.RunSubNumber, env.PipelineName, env.JobName)
+	if source := statement.Predicate.Invocation.ConfigSource; source.URI != "" {
+		fmt.Printf("Source %s (%s)\n", source.URI, source.Digest["sha1"])
+	}
+	return nil
+}
+
+var workflowRunResultProvenanceKeyCmd = cli.Command{
+	Name:  "provenance-key",
+	Short: "Show the public key used to sign the provenances of the project",
+	Ctx: []cli.Arg{
+		{Name: _ProjectKey},
+	},
+}
+
+func workflowRunResultProvenanceKey(v cli.Values) (interface{}, error) {
+	k, err := client.ProjectProvenanceKeyGet(v.GetString(_ProjectKey))
+	if err != nil {
+		return nil, err
+	}
+	return k, nil
+}
@@ -25,6 +25,9 @@ func insertActionChild(db gorp.SqlExecutor, child sdk.Action, actionID int64, ex
 		AlwaysExecuted: child.AlwaysExecuted,
 		Enabled:        child.Enabled,
 	}
+	if child.Conditions != nil && !child.Conditions.IsEmpty() {
+		ae.Conditions = child.Conditions
+	}
 	if err := insertEdge(db, &ae); err != nil {
 		return err
 	}
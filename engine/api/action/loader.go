@@ -153,6 +153,7 @@ func loadChildren(ctx context.Context, db gorp.SqlExecutor, as ...*sdk.Action) e
 			child.StepName = edges[i].StepName
 			child.Optional = edges[i].Optional
 			child.AlwaysExecuted = edges[i].AlwaysExecuted
+			child.Conditions = edges[i].Conditions
 			child.Enabled = edges[i].Enabled
 
 			// replace action parameter with value configured by user when he created the child action
@@ -33,6 +33,7 @@ import (
 	"github.com/ovh/cds/engine/api/broadcast"
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/idtoken"
 	"github.com/ovh/cds/engine/api/integration"
 	"github.com/ovh/cds/engine/api/mail"
 	"github.com/ovh/cds/engine/api/metrics"
@@ -100,6 +101,14 @@ type Configuration struct {
 			UserFullname    string `toml:"userFullname" default:"{{.givenName}} {{.sn}}" json:"userFullname"`
 			ManagerDN       string `toml:"managerDN" default:"cn=admin,dc=myorganization,dc=com" comment:"Define it if ldapsearch need to be authenticated" json:"managerDN"`
 			ManagerPassword string `toml:"managerPassword" default:"SECRET_PASSWORD_MANAGER" comment:"Define it if ldapsearch need to be authenticated" json:"-"`
+			GroupSync       struct {
+				Enabled         bool     `toml:"enabled" default:"false" json:"enabled"`
+				GroupAttribute  string   `toml:"groupAttribute" default:"memberOf" comment:"User attribute that contains the DN of its groups, not used if groupSearch is set" json:"groupAttribute"`
+				GroupSearchBase string   `toml:"groupSearchBase" default:"" comment:"Base of the group search, relative to rootDN, ex: ou=groups" json:"groupSearchBase"`
+				GroupSearch     string   `toml:"groupSearch" default:"" comment:"Filter to search the groups of a user, {0} is replaced by the user DN, ex: (&(objectClass=groupOfNames)(member={0}))" json:"groupSearch"`
+				Mappings        []string `toml:"mappings" comment:"LDAP groups to CDS groups mappings, ex: [\"developers=my-team\"]. Memberships of mapped CDS groups are managed by the synchronisation" json:"mappings"`
+				Interval        int64    `toml:"interval" default:"60" comment:"Interval between two synchronisations of all LDAP users (in minutes), 0 to only synchronise on signin" json:"interval"`
+			} `toml:"groupSync" json:"groupSync"`
 		} `toml:"ldap" json:"ldap"`
 		Local struct {
 			Enabled              bool   `toml:"enabled" default:"true" json:"enabled"`
@@ -143,7 +152,18 @@ type Configuration struct {
 			URL            string `toml:"url" json:"url" default:"" comment:"Open ID connect config URL"`
 			ClientID       string `toml:"clientId" json:"-" comment:"OIDC Client ID"`
 			ClientSecret   string `toml:"clientSecret" json:"-" comment:"OIDC Client Secret"`
+			GroupSync      struct {
+				Enabled  bool     `toml:"enabled" default:"false" json:"enabled"`
+				Claim    string   `toml:"claim" default:"groups" comment:"Claim of the ID token that contains the groups of the user" json:"claim"`
+				Mappings []string `toml:"mappings" comment:"OIDC groups to CDS groups mappings, ex: [\"developers=my-team\"]. Memberships of mapped CDS groups are managed by the synchronisation" json:"mappings"`
+				Interval int64    `toml:"interval" default:"60" comment:"Interval between two synchronisations of all OIDC users (in minutes) with the refresh tokens given at signin, 0 to only synchronise on signin" json:"interval"`
+			} `toml:"groupSync" json:"groupSync"`
 		} `toml:"oidc" json:"oidc" comment:"#######\n CDS <-> Open ID Connect Auth. Documentation on https://ovh.github.io/cds/docs/integrations/openid-connect/ \n######"`
+		IDToken struct {
+			Enabled       bool   `toml:"enabled" default:"false" json:"enabled"`
+			RSAPrivateKey string `toml:"rsaPrivateKey" default:"" comment:"The RSA Private Key used to sign jobs identity tokens, its public key is published on /.well-known/jwks.json" json:"-"`
+			Duration      int64  `toml:"duration" default:"10" comment:"The duration of an identity token (in minutes)" json:"duration"`
+		} `toml:"idToken" json:"idToken" comment:"#######\n OpenID Connect identity tokens for jobs, requested with worker id-token \n######"`
 	} `toml:"auth" comment:"##############################\n CDS Authentication Settings# \n#############################" json:"auth"`
 	SMTP struct {
 		Disable               bool   `toml:"disable" default:"true" json:"disable" comment:"Set to false to enable the internal SMTP client. If false, emails will be displayed in CDS API Log."`
@@ -206,6 +226,9 @@ type Configuration struct {
 		DefaultRetentionPolicy string `toml:"defaultRetentionPolicy" comment:"Default rule for workflow run retention policy, this rule can be overridden on each workflow.\n Example: 'return run_days_before < 365' keeps runs for one year." json:"defaultRetentionPolicy" default:"return run_days_before < 365"`
 		DisablePurgeDeletion   bool   `toml:"disablePurgeDeletion" comment:"Allow you to disable the deletion part of the purge. Workflow run will only be marked as delete" json:"disablePurgeDeletion" default:"false"`
 	} `toml:"workflow" comment:"######################\n 'Workflow' global configuration \n######################" json:"workflow"`
+	Audit struct {
+		HMACKey string `toml:"hmacKey" comment:"Key of the HMAC that chains the security audit log records, it is needed to verify an export.\n It should not be stored with the database. If not set, the records are not chained." json:"-"`
+	} `toml:"audit" comment:"######################\n Security audit log settings \n######################" json:"audit"`
 }
 
 // DefaultValues is the struc for API Default configuration default values
@@ -274,6 +297,7 @@ type API struct {
 		DatabaseConns            *stats.Int64Measure
 	}
 	AuthenticationDrivers map[sdk.AuthConsumerType]sdk.AuthDriver
+	GroupMappings         map[sdk.AuthConsumerType]authentication.GroupMappings
 }
 
 // ApplyConfiguration apply an object of type api.Configuration after checking it
@@ -351,6 +375,12 @@ func (a *API) CheckConfiguration(config interface{}) error {
 		return fmt.Errorf("Invalid secret key. It should be 32 bits (%d)", len(aConfig.Secrets.Key))
 	}
 
+	if aConfig.Audit.HMACKey == "" {
+		log.Warn(context.Background(), "No audit HMAC key in your configuration, the security audit log records will not be chained and can't be verified")
+	} else if len(aConfig.Audit.HMACKey) < 32 {
+		return fmt.Errorf("Invalid audit HMAC key. It should be at least 32 characters (%d)", len(aConfig.Audit.HMACKey))
+	}
+
 	if aConfig.DefaultArch == "" {
 		log.Warn(context.Background(), `You should add a default architecture in your configuration (example: defaultArch: "amd64"). It means if there is no model and os/arch requirement on your job then spawn on a worker based on this architecture`)
 	}
@@ -366,6 +396,10 @@ func (a *API) CheckConfiguration(config interface{}) error {
 		return errors.New("invalid given authentication rsa private key")
 	}
 
+	if aConfig.Auth.LDAP.Enabled && aConfig.Auth.LDAP.GroupSync.Enabled && aConfig.Auth.LDAP.GroupSync.Interval > 0 && aConfig.Auth.LDAP.ManagerDN == "" {
+		return errors.New("a LDAP manager DN is required to synchronise the groups of the users periodically")
+	}
+
 	return nil
 }
 
@@ -433,6 +467,16 @@ func (a *API) Serve(ctx context.Context) error {
 		return sdk.WrapError(err, "unable to initialize the JWT Layer")
 	}
 
+	// Initialize the jobs identity tokens issuer
+	if a.Config.Auth.IDToken.Enabled {
+		if a.Config.Auth.IDToken.Duration <= 0 {
+			a.Config.Auth.IDToken.Duration = 10
+		}
+		if err := idtoken.Init(a.Config.URL.API, []byte(a.Config.Auth.IDToken.RSAPrivateKey), time.Duration(a.Config.Auth.IDToken.Duration)*time.Minute); err != nil {
+			return sdk.WrapError(err, "unable to initialize the identity tokens issuer")
+		}
+	}
+
 	// Intialize service mesh httpclient
 	if a.Config.InternalServiceMesh.RequestSecondsTimeout == 0 {
 		a.Config.InternalServiceMesh.RequestSecondsTimeout = 60
@@ -573,6 +617,7 @@ func (a *API) Serve(ctx context.Context) error {
 
 	log.Info(ctx, "Initializing Authentication drivers...")
 	a.AuthenticationDrivers = make(map[sdk.AuthConsumerType]sdk.AuthDriver)
+	a.GroupMappings = make(map[sdk.AuthConsumerType]authentication.GroupMappings)
 
 	a.AuthenticationDrivers[sdk.ConsumerBuiltin] = builtin.NewDriver()
 	if a.Config.Auth.Local.Enabled {
@@ -585,20 +630,30 @@ func (a *API) Serve(ctx context.Context) error {
 	}
 
 	if a.Config.Auth.LDAP.Enabled {
+		ldapConfig := ldap.Config{
+			Host:            a.Config.Auth.LDAP.Host,
+			Port:            a.Config.Auth.LDAP.Port,
+			SSL:             a.Config.Auth.LDAP.SSL,
+			RootDN:          a.Config.Auth.LDAP.RootDN,
+			UserSearchBase:  a.Config.Auth.LDAP.UserSearchBase,
+			UserSearch:      a.Config.Auth.LDAP.UserSearch,
+			UserFullname:    a.Config.Auth.LDAP.UserFullname,
+			ManagerDN:       a.Config.Auth.LDAP.ManagerDN,
+			ManagerPassword: a.Config.Auth.LDAP.ManagerPassword,
+		}
+		if a.Config.Auth.LDAP.GroupSync.Enabled {
+			ldapConfig.GroupAttribute = a.Config.Auth.LDAP.GroupSync.GroupAttribute
+			ldapConfig.GroupSearchBase = a.Config.Auth.LDAP.GroupSync.GroupSearchBase
+			ldapConfig.GroupSearch = a.Config.Auth.LDAP.GroupSync.GroupSearch
+			a.GroupMappings[sdk.ConsumerLDAP], err = authentication.ParseGroupMappings(a.Config.Auth.LDAP.GroupSync.Mappings)
+			if err != nil {
+				return err
+			}
+		}
 		a.AuthenticationDrivers[sdk.ConsumerLDAP], err = ldap.NewDriver(
 			ctx,
 			a.Config.Auth.LDAP.SignupDisabled,
-			ldap.Config{
-				Host:            a.Config.Auth.LDAP.Host,
-				Port:            a.Config.Auth.LDAP.Port,
-				SSL:             a.Config.Auth.LDAP.SSL,
-				RootDN:          a.Config.Auth.LDAP.RootDN,
-				UserSearchBase:  a.Config.Auth.LDAP.UserSearchBase,
-				UserSearch:      a.Config.Auth.LDAP.UserSearch,
-				UserFullname:    a.Config.Auth.LDAP.UserFullname,
-				ManagerDN:       a.Config.Auth.LDAP.ManagerDN,
-				ManagerPassword: a.Config.Auth.LDAP.ManagerPassword,
-			},
+			ldapConfig,
 		)
 		if err != nil {
 			return err
@@ -624,12 +679,21 @@ func (a *API) Serve(ctx context.Context) error {
 		)
 	}
 	if a.Config.Auth.OIDC.Enabled {
+		var groupsClaim string
+		if a.Config.Auth.OIDC.GroupSync.Enabled {
+			groupsClaim = a.Config.Auth.OIDC.GroupSync.Claim
+			a.GroupMappings[sdk.ConsumerOIDC], err = authentication.ParseGroupMappings(a.Config.Auth.OIDC.GroupSync.Mappings)
+			if err != nil {
+				return err
+			}
+		}
 		a.AuthenticationDrivers[sdk.ConsumerOIDC], err = oidc.NewDriver(
 			a.Config.Auth.OIDC.SignupDisabled,
 			a.Config.URL.UI,
 			a.Config.Auth.OIDC.URL,
 			a.Config.Auth.OIDC.ClientID,
 			a.Config.Auth.OIDC.ClientSecret,
+			groupsClaim,
 		)
 		if err != nil {
 			return err
@@ -706,9 +770,25 @@ func (a *API) Serve(ctx context.Context) error {
 	a.GoRoutines.RunWithRestart(ctx, "authentication.SessionCleaner", func(ctx context.Context) {
 		authentication.SessionCleaner(ctx, a.mustDB, 10*time.Second)
 	})
+	if driver, ok := a.AuthenticationDrivers[sdk.ConsumerLDAP]; ok && a.Config.Auth.LDAP.GroupSync.Enabled && a.Config.Auth.LDAP.GroupSync.Interval > 0 {
+		a.GoRoutines.RunWithRestart(ctx, "authentication.GroupSynchronizer.ldap", func(ctx context.Context) {
+			authentication.GroupSynchronizer(ctx, a.mustDB, driver, a.GroupMappings[sdk.ConsumerLDAP], time.Duration(a.Config.Auth.LDAP.GroupSync.Interval)*time.Minute)
+		})
+	}
+	if driver, ok := a.AuthenticationDrivers[sdk.ConsumerOIDC]; ok && a.Config.Auth.OIDC.GroupSync.Enabled && a.Config.Auth.OIDC.GroupSync.Interval > 0 {
+		a.GoRoutines.RunWithRestart(ctx, "authentication.GroupSynchronizer.oidc", func(ctx context.Context) {
+			authentication.GroupSynchronizer(ctx, a.mustDB, driver, a.GroupMappings[sdk.ConsumerOIDC], time.Duration(a.Config.Auth.OIDC.GroupSync.Interval)*time.Minute)
+		})
+	}
 	a.GoRoutines.RunWithRestart(ctx, "api.WorkflowRunCraft", func(ctx context.Context) {
 		a.WorkflowRunCraft(ctx, 100*time.Millisecond)
 	})
+	a.GoRoutines.RunWithRestart(ctx, "api.WorkflowNodeRunApprovalExpiration", func(ctx context.Context) {
+		a.WorkflowNodeRunApprovalExpiration(ctx, time.Minute)
+	})
+	a.GoRoutines.RunWithRestart(ctx, "api.UserNotificationDigest", func(ctx context.Context) {
+		a.UserNotificationDigest(ctx, time.Minute)
+	})
 
 	migrate.Add(ctx, sdk.Migration{Name: "RunsSecrets", Release: "0.47.0", Blocker: false, Automatic: true, ExecFunc: func(ctx context.Context) error {
 		return migrate.RunsSecrets(ctx, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper))
@@ -718,6 +798,10 @@ func (a *API) Serve(ctx context.Context) error {
 		return migrate.AuthConsumerTokenExpiration(ctx, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper), time.Duration(a.Config.Auth.TokenDefaultDuration)*(24*time.Hour))
 	}})
 
+	migrate.Add(ctx, sdk.Migration{Name: "ProvenanceKeys", Release: "0.48.0", Blocker: false, Automatic: true, ExecFunc: func(ctx context.Context) error {
+		return migrate.ProvenanceKeys(ctx, a.DBConnectionFactory.GetDBMap(gorpmapping.Mapper))
+	}})
+
 	isFreshInstall, errF := version.IsFreshInstall(a.mustDB())
 	if errF != nil {
 		return sdk.WrapError(errF, "Unable to check if it's a fresh installation of CDS")
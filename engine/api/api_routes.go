@@ -26,6 +26,7 @@ func (api *API) InitRouter() {
 	api.Router.DefaultAuthMiddleware = api.authMiddleware
 	api.Router.PostAuthMiddlewares = append(api.Router.PostAuthMiddlewares, api.xsrfMiddleware, api.maintenanceMiddleware)
 	api.Router.PostMiddlewares = append(api.Router.PostMiddlewares, TracingPostMiddleware)
+	api.Router.AuditFunc = api.auditRequest
 
 	r := api.Router
 
@@ -47,6 +48,10 @@ func (api *API) InitRouter() {
 	r.Handle("/auth/consumer/signout", ScopeNone(), r.POST(api.postAuthSignoutHandler))
 	r.Handle("/auth/session/{sessionID}", ScopeNone(), r.GET(api.getAuthSession))
 
+	// OpenID Connect identity tokens for jobs
+	r.Handle("/.well-known/openid-configuration", ScopeNone(), r.GET(api.getOpenIDConfigurationHandler, service.OverrideAuth(service.NoAuthMiddleware)))
+	r.Handle("/.well-known/jwks.json", ScopeNone(), r.GET(api.getJWKSHandler, service.OverrideAuth(service.NoAuthMiddleware)))
+
 	// Action
 	r.Handle("/action", Scope(sdk.AuthConsumerScopeAction), r.GET(api.getActionsHandler), r.POST(api.postActionHandler))
 	r.Handle("/action/import", Scope(sdk.AuthConsumerScopeAction), r.POST(api.importActionHandler))
@@ -71,6 +76,9 @@ func (api *API) InitRouter() {
 	r.Handle("/admin/database/migration/unlock/{id}", Scope(sdk.AuthConsumerScopeAdmin), r.POST(api.postDatabaseMigrationUnlockedHandler, service.OverrideAuth(api.authAdminMiddleware)))
 	r.Handle("/admin/database/migration", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getDatabaseMigrationHandler, service.OverrideAuth(api.authAdminMiddleware)))
 
+	r.Handle("/admin/audit", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminAuditLogsHandler, service.OverrideAuth(api.authMaintainerMiddleware)))
+	r.Handle("/admin/audit/export", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getAdminAuditLogsExportHandler, service.OverrideAuth(api.authMaintainerMiddleware)))
+
 	r.Handle("/admin/debug/profiles", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getDebugProfilesHandler, service.OverrideAuth(api.authMaintainerMiddleware)))
 	r.Handle("/admin/debug/goroutines", Scope(sdk.AuthConsumerScopeAdmin), r.GET(api.getDebugGoroutinesHandler, service.OverrideAuth(api.authMaintainerMiddleware)))
 	r.Handle("/admin/debug/trace", Scope(sdk.AuthConsumerScopeAdmin), r.POST(api.getTraceHandler, service.OverrideAuth(api.authAdminMiddleware)), r.GET(api.getTraceHandler, service.OverrideAuth(api.authMaintainerMiddleware)))
@@ -113,10 +121,18 @@ func (api *API) InitRouter() {
 	// Group
 	r.Handle("/group", Scope(sdk.AuthConsumerScopeGroup), r.GET(api.getGroupsHandler), r.POST(api.postGroupHandler))
 	r.Handle("/group/{permGroupName}", Scope(sdk.AuthConsumerScopeGroup), r.GET(api.getGroupHandler), r.PUT(api.putGroupHandler), r.DELETE(api.deleteGroupHandler))
+	r.Handle("/group/{permGroupName}/audit", Scope(sdk.AuthConsumerScopeGroup), r.GET(api.getGroupAuditsHandler))
 	r.Handle("/group/{permGroupName}/user", Scope(sdk.AuthConsumerScopeGroup), r.POST(api.postGroupUserHandler))
 	r.Handle("/group/{permGroupName}/user/{username}", Scope(sdk.AuthConsumerScopeGroup), r.PUT(api.putGroupUserHandler), r.DELETE(api.deleteGroupUserHandler))
 	r.Handle("/group/{permGroupName}/project", Scope(sdk.AuthConsumerScopeGroup), r.GET(api.getProjectGroupHandler))
 
+	// SCIM provisioning
+	r.Handle("/scim/v2/ServiceProviderConfig", Scope(sdk.AuthConsumerScopeSCIM), r.GET(api.getSCIMServiceProviderConfigHandler, service.OverrideAuth(api.authSCIMMiddleware)))
+	r.Handle("/scim/v2/Users", Scope(sdk.AuthConsumerScopeSCIM), r.GET(api.getSCIMUsersHandler, service.OverrideAuth(api.authSCIMMiddleware)), r.POST(api.postSCIMUserHandler, service.OverrideAuth(api.authSCIMMiddleware)))
+	r.Handle("/scim/v2/Users/{scimUserID}", Scope(sdk.AuthConsumerScopeSCIM), r.GET(api.getSCIMUserHandler, service.OverrideAuth(api.authSCIMMiddleware)), r.PUT(api.putSCIMUserHandler, service.OverrideAuth(api.authSCIMMiddleware)), r.PATCH(api.patchSCIMUserHandler, service.OverrideAuth(api.authSCIMMiddleware)), r.DELETE(api.deleteSCIMUserHandler, service.OverrideAuth(api.authSCIMMiddleware)))
+	r.Handle("/scim/v2/Groups", Scope(sdk.AuthConsumerScopeSCIM), r.GET(api.getSCIMGroupsHandler, service.OverrideAuth(api.authSCIMMiddleware)), r.POST(api.postSCIMGroupHandler, service.OverrideAuth(api.authSCIMMiddleware)))
+	r.Handle("/scim/v2/Groups/{scimGroupID}", Scope(sdk.AuthConsumerScopeSCIM), r.GET(api.getSCIMGroupHandler, service.OverrideAuth(api.authSCIMMiddleware)), r.PUT(api.putSCIMGroupHandler, service.OverrideAuth(api.authSCIMMiddleware)), r.PATCH(api.patchSCIMGroupHandler, service.OverrideAuth(api.authSCIMMiddleware)), r.DELETE(api.deleteSCIMGroupHandler, service.OverrideAuth(api.authSCIMMiddleware)))
+
 	// Hooks
 	r.Handle("/hook/{uuid}/workflow/{workflowID}/vcsevent/{vcsServer}", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getHookPollingVCSEvents))
 
@@ -166,9 +182,14 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/applications", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getApplicationsHandler), r.POST(api.addApplicationHandler))
 	r.Handle("/project/{permProjectKey}/integrations", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getProjectIntegrationsHandler), r.POST(api.postProjectIntegrationHandler))
 	r.Handle("/project/{permProjectKey}/integrations/{integrationName}", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getProjectIntegrationHandler), r.PUT(api.putProjectIntegrationHandler), r.DELETE(api.deleteProjectIntegrationHandler))
+	r.Handle("/project/{permProjectKey}/integrations/{integrationName}/audit", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getProjectIntegrationAuditsHandler))
 	r.Handle("/project/{permProjectKey}/notifications", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getProjectNotificationsHandler, DEPRECATED))
 	r.Handle("/project/{permProjectKey}/keys", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getKeysInProjectHandler), r.POST(api.addKeyInProjectHandler))
 	r.Handle("/project/{permProjectKey}/keys/{name}", Scope(sdk.AuthConsumerScopeProject), r.DELETE(api.deleteKeyInProjectHandler))
+	r.Handle("/project/{permProjectKey}/keys/{name}/rotate", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postRotateKeyInProjectHandler))
+	r.Handle("/project/{permProjectKey}/keys/{name}/usage", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getKeyUsageInProjectHandler))
+	r.Handle("/project/{permProjectKey}/keys/{name}/retire", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postRetireKeyInProjectHandler))
+	r.Handle("/project/{permProjectKey}/provenance/key", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getProjectProvenanceKeyHandler))
 
 	// Import Application
 	r.Handle("/project/{permProjectKey}/import/application", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postApplicationImportHandler))
@@ -181,6 +202,9 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/metrics/{metricName}", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getApplicationMetricHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/keys", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getKeysInApplicationHandler), r.POST(api.addKeyInApplicationHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/keys/{name}", Scope(sdk.AuthConsumerScopeProject), r.DELETE(api.deleteKeyInApplicationHandler))
+	r.Handle("/project/{permProjectKey}/application/{applicationName}/keys/{name}/rotate", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postRotateKeyInApplicationHandler))
+	r.Handle("/project/{permProjectKey}/application/{applicationName}/keys/{name}/usage", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getKeyUsageInApplicationHandler))
+	r.Handle("/project/{permProjectKey}/application/{applicationName}/keys/{name}/retire", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postRetireKeyInApplicationHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/vcsinfos", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getApplicationVCSInfosHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/clone", Scope(sdk.AuthConsumerScopeProject), r.POST(api.cloneApplicationHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/variable", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getVariablesInApplicationHandler))
@@ -188,6 +212,8 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/variable/{name}", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getVariableInApplicationHandler), r.POST(api.addVariableInApplicationHandler), r.PUT(api.updateVariableInApplicationHandler), r.DELETE(api.deleteVariableFromApplicationHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/variable/{name}/audit", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getVariableAuditInApplicationHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/vulnerability/{id}", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postVulnerabilityHandler))
+	r.Handle("/project/{permProjectKey}/application/{applicationName}/preview", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getApplicationPreviewEnvironmentsHandler))
+	r.Handle("/project/{permProjectKey}/application/{applicationName}/preview/{workflowName}/{pullRequestID}", Scope(sdk.AuthConsumerScopeProject), r.DELETE(api.deleteApplicationPreviewEnvironmentHandler))
 	// Application deployment
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/deployment/config/{integration}", Scope(sdk.AuthConsumerScopeProject), r.POST(api.postApplicationDeploymentStrategyConfigHandler), r.GET(api.getApplicationDeploymentStrategyConfigHandler), r.DELETE(api.deleteApplicationDeploymentStrategyConfigHandler))
 	r.Handle("/project/{permProjectKey}/application/{applicationName}/deployment/config", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getApplicationDeploymentStrategiesConfigHandler))
@@ -273,6 +299,8 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowNodeRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/results", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowNodeRunResultsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/stop", Scope(sdk.AuthConsumerScopeRun), r.POSTEXECUTE(api.stopWorkflowNodeRunHandler, MaintenanceAware()))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/approve", Scope(sdk.AuthConsumerScopeRun), r.POSTEXECUTE(api.postApproveWorkflowNodeRunHandler, MaintenanceAware()))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/reject", Scope(sdk.AuthConsumerScopeRun), r.POSTEXECUTE(api.postRejectWorkflowNodeRunHandler, MaintenanceAware()))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeID}/history", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowNodeRunHistoryHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/{nodeName}/commits", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowCommitsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/job/{runJobID}/info", Scope(sdk.AuthConsumerScopeRun), r.GET(api.getWorkflowNodeRunJobSpawnInfosHandler))
@@ -293,6 +321,7 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{permProjectKey}/environment/import/{environmentName}", Scope(sdk.AuthConsumerScopeProject), r.POST(api.importIntoEnvironmentHandler, DEPRECATED))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getEnvironmentHandler), r.PUT(api.updateEnvironmentHandler), r.DELETE(api.deleteEnvironmentHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/ascode", Scope(sdk.AuthConsumerScopeProject), r.PUT(api.updateAsCodeEnvironmentHandler))
+	r.Handle("/project/{permProjectKey}/environment/{environmentName}/protection", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getEnvironmentProtectionHandler), r.PUT(api.putEnvironmentProtectionHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/usage", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getEnvironmentUsageHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/keys", Scope(sdk.AuthConsumerScopeProject), r.GET(api.getKeysInEnvironmentHandler), r.POST(api.addKeyInEnvironmentHandler))
 	r.Handle("/project/{permProjectKey}/environment/{environmentName}/keys/{name}", Scope(sdk.AuthConsumerScopeProject), r.DELETE(api.deleteKeyInEnvironmentHandler))
@@ -322,8 +351,13 @@ func (api *API) InitRouter() {
 	r.Handle("/queue/workflows/count", Scope(sdk.AuthConsumerScopeRun), r.GET(api.countWorkflowJobQueueHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{id}/take", Scope(sdk.AuthConsumerScopeRunExecution), r.POST(api.postTakeWorkflowJobHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{permJobID}/cache/{tag}/links", Scope(sdk.AuthConsumerScopeRunExecution), r.GET(api.getWorkerCacheLinkHandler, MaintenanceAware()))
+	r.Handle("/queue/workflows/{permJobID}/cache/links", Scope(sdk.AuthConsumerScopeRunExecution), r.GET(api.getWorkerCacheLinksHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{permJobID}/book", Scope(sdk.AuthConsumerScopeRunExecution), r.POST(api.postBookWorkflowJobHandler, MaintenanceAware()), r.DELETE(api.deleteBookWorkflowJobHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{permJobID}/infos", Scope(sdk.AuthConsumerScopeRunExecution), r.GET(api.getWorkflowJobHandler, MaintenanceAware()))
+	r.Handle("/queue/workflows/{permJobID}/mirror", Scope(sdk.AuthConsumerScopeRunExecution), r.GET(api.getWorkflowJobGitMirrorHandler, MaintenanceAware()))
+	r.Handle("/queue/workflows/{permJobID}/mirror/{mirrorID}/info/refs", Scope(sdk.AuthConsumerScopeRunExecution), r.GET(api.getWorkflowJobGitMirrorInfoRefsHandler, MaintenanceAware()))
+	r.Handle("/queue/workflows/{permJobID}/mirror/{mirrorID}/git-upload-pack", Scope(sdk.AuthConsumerScopeRunExecution), r.POST(api.postWorkflowJobGitMirrorUploadPackHandler, MaintenanceAware()))
+	r.Handle("/queue/workflows/{permJobID}/idtoken", Scope(sdk.AuthConsumerScopeRunExecution), r.POST(api.postWorkflowJobIDTokenHandler))
 	r.Handle("/queue/workflows/{permJobID}/vulnerability", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTEXECUTE(api.postVulnerabilityReportHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{permJobID}/spawn/infos", Scope(sdk.AuthConsumerScopeRunExecution), r.POST(api.postSpawnInfosWorkflowJobHandler, MaintenanceAware()))
 	r.Handle("/queue/workflows/{permJobID}/result", Scope(sdk.AuthConsumerScopeRunExecution), r.POSTEXECUTE(api.postWorkflowJobResultHandler, MaintenanceAware()))
@@ -380,6 +414,8 @@ func (api *API) InitRouter() {
 	r.Handle("/user/{permUsernamePublic}", Scope(sdk.AuthConsumerScopeUser), r.GET(api.getUserHandler), r.PUT(api.putUserHandler), r.DELETE(api.deleteUserHandler))
 	r.Handle("/user/{permUsernamePublic}/group", Scope(sdk.AuthConsumerScopeUser), r.GET(api.getUserGroupsHandler))
 	r.Handle("/user/{permUsername}/contact", Scope(sdk.AuthConsumerScopeUser), r.GET(api.getUserContactsHandler))
+	r.Handle("/user/{permUsername}/notification/subscription", Scope(sdk.AuthConsumerScopeUser), r.GET(api.getUserNotificationSubscriptionsHandler), r.POST(api.postUserNotificationSubscriptionHandler))
+	r.Handle("/user/{permUsername}/notification/subscription/{subscriptionID}", Scope(sdk.AuthConsumerScopeUser), r.PUT(api.putUserNotificationSubscriptionHandler), r.DELETE(api.deleteUserNotificationSubscriptionHandler))
 	r.Handle("/user/{permUsername}/auth/consumer", Scope(sdk.AuthConsumerScopeAccessToken), r.GET(api.getConsumersByUserHandler), r.POST(api.postConsumerByUserHandler))
 	r.Handle("/user/{permUsername}/auth/consumer/{permConsumerID}", Scope(sdk.AuthConsumerScopeAccessToken), r.DELETE(api.deleteConsumerByUserHandler))
 	r.Handle("/user/{permUsername}/auth/consumer/{permConsumerID}/regen", Scope(sdk.AuthConsumerScopeAccessToken), r.POST(api.postConsumerRegenByUserHandler))
@@ -433,11 +469,13 @@ func (api *API) InitRouter() {
 	r.Handle("/template/{permGroupName}/{permTemplateSlug}", Scope(sdk.AuthConsumerScopeTemplate), r.GET(api.getTemplateHandler), r.PUT(api.putTemplateHandler), r.DELETE(api.deleteTemplateHandler))
 	r.Handle("/template/{permGroupName}/{permTemplateSlug}/pull", Scope(sdk.AuthConsumerScopeTemplate), r.POST(api.postTemplatePullHandler))
 	r.Handle("/template/{permGroupName}/{permTemplateSlug}/audit", Scope(sdk.AuthConsumerScopeTemplate), r.GET(api.getTemplateAuditsHandler))
+	r.Handle("/template/{permGroupName}/{permTemplateSlug}/release", Scope(sdk.AuthConsumerScopeTemplate), r.GET(api.getTemplateReleasesHandler), r.POST(api.postTemplateReleaseHandler))
 	r.Handle("/template/{groupName}/{templateSlug}/apply", Scope(sdk.AuthConsumerScopeTemplate), r.POST(api.postTemplateApplyHandler))
 	r.Handle("/template/{groupName}/{templateSlug}/bulk", Scope(sdk.AuthConsumerScopeTemplate), r.POST(api.postTemplateBulkHandler))
 	r.Handle("/template/{groupName}/{templateSlug}/bulk/{bulkID}", Scope(sdk.AuthConsumerScopeTemplate), r.GET(api.getTemplateBulkHandler))
 	r.Handle("/template/{groupName}/{templateSlug}/instance", Scope(sdk.AuthConsumerScopeTemplate), r.GET(api.getTemplateInstancesHandler))
 	r.Handle("/template/{groupName}/{templateSlug}/instance/{instanceID}", Scope(sdk.AuthConsumerScopeTemplate), r.DELETE(api.deleteTemplateInstanceHandler))
+	r.Handle("/template/{groupName}/{templateSlug}/instance/{instanceID}/upgrade", Scope(sdk.AuthConsumerScopeTemplate), r.POST(api.postTemplateInstanceUpgradeHandler))
 	r.Handle("/template/{groupName}/{templateSlug}/usage", Scope(sdk.AuthConsumerScopeTemplate), r.GET(api.getTemplateUsageHandler))
 
 	//Not Found handler
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const previewEnvironmentQuery = `
+	SELECT preview_environment.id, preview_environment.application_id, preview_environment.workflow_id,
+		workflow.name, preview_environment.environment_id, environment.name, preview_environment.pull_request_id,
+		preview_environment.branch, preview_environment.hash, preview_environment.status,
+		preview_environment.workflow_run_number, preview_environment.created, preview_environment.last_modified
+	FROM preview_environment
+	JOIN workflow ON workflow.id = preview_environment.workflow_id
+	JOIN environment ON environment.id = preview_environment.environment_id
+`
+
+func loadPreviewEnvironments(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.PreviewEnvironment, error) {
+	rows, err := db.Query(previewEnvironmentQuery+query, args...)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	defer rows.Close()
+
+	ps := []sdk.PreviewEnvironment{}
+	for rows.Next() {
+		var p sdk.PreviewEnvironment
+		if err := rows.Scan(&p.ID, &p.ApplicationID, &p.WorkflowID, &p.WorkflowName, &p.EnvironmentID, &p.EnvironmentName,
+			&p.PullRequestID, &p.Branch, &p.Hash, &p.Status, &p.WorkflowRunNumber, &p.Created, &p.LastModified); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		ps = append(ps, p)
+	}
+	return ps, sdk.WithStack(rows.Err())
+}
+
+// LoadPreviewEnvironments returns the preview environments of an application.
+func LoadPreviewEnvironments(db gorp.SqlExecutor, appID int64) ([]sdk.PreviewEnvironment, error) {
+	return loadPreviewEnvironments(db, `
+		WHERE preview_environment.application_id = $1
+		ORDER BY preview_environment.pull_request_id DESC, workflow.name
+	`, appID)
+}
+
+// LoadPreviewEnvironment returns the preview environment of a pull request for given workflow and application.
+func LoadPreviewEnvironment(db gorp.SqlExecutor, workflowID, appID, pullRequestID int64) (*sdk.PreviewEnvironment, error) {
+	ps, err := loadPreviewEnvironments(db, `
+		WHERE preview_environment.workflow_id = $1
+		AND preview_environment.application_id = $2
+		AND preview_environment.pull_request_id = $3
+	`, workflowID, appID, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ps) == 0 {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &ps[0], nil
+}
+
+// LoadPreviewEnvironmentByEnvironmentID returns the preview environment that uses given environment.
+func LoadPreviewEnvironmentByEnvironmentID(db gorp.SqlExecutor, envID int64) (*sdk.PreviewEnvironment, error) {
+	ps, err := loadPreviewEnvironments(db, "WHERE preview_environment.environment_id = $1", envID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ps) == 0 {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &ps[0], nil
+}
+
+// InsertPreviewEnvironment inserts a preview environment.
+func InsertPreviewEnvironment(db gorp.SqlExecutor, p *sdk.PreviewEnvironment) error {
+	p.Created = time.Now()
+	p.LastModified = p.Created
+	dbp := dbPreviewEnvironment(*p)
+	if err := db.Insert(&dbp); err != nil {
+		return sdk.WrapError(err, "unable to insert preview environment for pull request %d", p.PullRequestID)
+	}
+	p.ID = dbp.ID
+	return nil
+}
+
+// UpdatePreviewEnvironment updates a preview environment.
+func UpdatePreviewEnvironment(db gorp.SqlExecutor, p *sdk.PreviewEnvironment) error {
+	p.LastModified = time.Now()
+	dbp := dbPreviewEnvironment(*p)
+	if _, err := db.Update(&dbp); err != nil {
+		return sdk.WrapError(err, "unable to update preview environment %d", p.ID)
+	}
+	return nil
+}
+
+// DeletePreviewEnvironment deletes a preview environment.
+func DeletePreviewEnvironment(db gorp.SqlExecutor, id int64) error {
+	if _, err := db.Exec("DELETE FROM preview_environment WHERE id = $1", id); err != nil {
+		return sdk.WrapError(err, "unable to delete preview environment %d", id)
+	}
+	return nil
+}
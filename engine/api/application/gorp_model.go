@@ -14,6 +14,8 @@ type dbApplicationVariableAudit sdk.ApplicationVariableAudit
 
 type dbApplicationVulnerability sdk.Vulnerability
 
+type dbPreviewEnvironment sdk.PreviewEnvironment
+
 func init() {
 	gorpmapping.Register(gorpmapping.New(dbApplication{}, "application", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbApplicationVariableAudit{}, "application_variable_audit", true, "id"))
@@ -21,6 +23,7 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(dbApplicationVulnerability{}, "application_vulnerability", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbApplicationVariable{}, "application_variable", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbApplicationDeploymentStrategy{}, "application_deployment_strategy", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbPreviewEnvironment{}, "preview_environment", true, "id"))
 }
 
 // PostGet is a db hook
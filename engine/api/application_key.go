@@ -9,7 +9,6 @@ import (
 
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/event"
-	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 )
@@ -104,13 +103,15 @@ func (api *API) addKeyInApplicationHandler() service.Handler {
 			newKey.Name = "app-" + newKey.Name
 		}
 
-		k, err := keys.GenerateKey(newKey.Name, newKey.Type)
+		k, err := newKeyContent(newKey.Name, newKey.Type, newKey.Algorithm, newKey.Private)
 		if err != nil {
 			return err
 		}
 		newKey.Public = k.Public
 		newKey.Private = k.Private
 		newKey.KeyID = k.KeyID
+		newKey.Successor = ""
+		newKey.RetireAt = nil
 
 		tx, errT := api.mustDB().Begin()
 		if errT != nil {
@@ -131,3 +132,167 @@ func (api *API) addKeyInApplicationHandler() service.Handler {
 		return service.WriteJSON(w, newKey, http.StatusOK)
 	}
 }
+
+func (api *API) postRotateKeyInApplicationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+		keyName := vars["name"]
+
+		var req sdk.KeyRotationRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		app, err := application.LoadByName(api.mustDB(), key, appName, application.LoadOptions.WithKeys)
+		if err != nil {
+			return err
+		}
+		if app.FromRepository != "" {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		var oldKey *sdk.ApplicationKey
+		ks, err := application.LoadAllKeysWithPrivateContent(api.mustDB(), app.ID)
+		if err != nil {
+			return err
+		}
+		for i := range ks {
+			if ks[i].Name == keyName {
+				oldKey = &ks[i]
+				break
+			}
+		}
+		if oldKey == nil {
+			return sdk.WithStack(sdk.ErrKeyNotFound)
+		}
+		if oldKey.Successor != "" {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "key %s has already been rotated to %s", oldKey.Name, oldKey.Successor)
+		}
+
+		successorName, retireAt, err := checkKeyRotationRequest(req, oldKey.Name, "app-")
+		if err != nil {
+			return err
+		}
+		if app.GetKey(successorName) != nil {
+			return sdk.NewErrorFrom(sdk.ErrKeyAlreadyExist, "key %s already exists", successorName)
+		}
+
+		k, err := newKeyContent(successorName, oldKey.Type, req.Algorithm, req.Private)
+		if err != nil {
+			return err
+		}
+		newKey := sdk.ApplicationKey{
+			Name:          successorName,
+			Type:          oldKey.Type,
+			Public:        k.Public,
+			Private:       k.Private,
+			KeyID:         k.KeyID,
+			ApplicationID: app.ID,
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		if err := application.InsertKey(tx, &newKey); err != nil {
+			return sdk.WrapError(err, "cannot insert application key")
+		}
+
+		oldKey.Successor = newKey.Name
+		oldKey.RetireAt = &retireAt
+		if err := application.UpdateKey(ctx, tx, oldKey); err != nil {
+			return sdk.WrapError(err, "cannot update application key %s", oldKey.Name)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		event.PublishApplicationKeyAdd(ctx, key, *app, newKey, getAPIConsumer(ctx))
+
+		return service.WriteJSON(w, newKey, http.StatusOK)
+	}
+}
+
+func (api *API) getKeyUsageInApplicationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+		keyName := vars["name"]
+
+		app, err := application.LoadByName(api.mustDB(), key, appName, application.LoadOptions.WithKeys)
+		if err != nil {
+			return err
+		}
+		k := app.GetKey(keyName)
+		if k == nil {
+			return sdk.WithStack(sdk.ErrKeyNotFound)
+		}
+
+		usage, err := loadKeyUsage(ctx, api.mustDB(), key, keyName)
+		if err != nil {
+			return err
+		}
+		usage.Successor = k.Successor
+		usage.RetireAt = k.RetireAt
+
+		return service.WriteJSON(w, usage, http.StatusOK)
+	}
+}
+
+func (api *API) postRetireKeyInApplicationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+		keyName := vars["name"]
+		force := service.FormBool(r, "force")
+
+		app, err := application.LoadByName(api.mustDB(), key, appName, application.LoadOptions.WithKeys)
+		if err != nil {
+			return err
+		}
+		if app.FromRepository != "" {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+		k := app.GetKey(keyName)
+		if k == nil {
+			return sdk.WithStack(sdk.ErrKeyNotFound)
+		}
+
+		if err := checkKeyRetirement(k.Name, k.RetireAt); err != nil {
+			return err
+		}
+
+		usage, err := loadKeyUsage(ctx, api.mustDB(), key, keyName)
+		if err != nil {
+			return err
+		}
+		if usage.IsUsed() && !force {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "key %s is still used by applications %v, pipelines %v", keyName, usage.Applications, usage.Pipelines)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		if err := application.DeleteKey(tx, app.ID, keyName); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		event.PublishApplicationKeyDelete(ctx, key, *app, *k, getAPIConsumer(ctx))
+
+		return nil
+	}
+}
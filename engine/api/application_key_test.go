@@ -2,17 +2,21 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/keys"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -170,3 +174,107 @@ func Test_addKeyInApplicationHandler(t *testing.T) {
 	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &key))
 	assert.Equal(t, app.ID, key.ApplicationID)
 }
+
+func Test_rotateKeyInApplicationHandler(t *testing.T) {
+	api, db, router := newTestAPI(t)
+
+	u, pass := assets.InsertAdminUser(t, db)
+
+	pkey := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, pkey, pkey)
+
+	// The application clones its repository with the key
+	app := &sdk.Application{
+		Name:               sdk.RandomString(10),
+		RepositoryStrategy: sdk.RepositoryStrategy{ConnectionType: "ssh", SSHKey: "app-mykey"},
+	}
+	test.NoError(t, application.Insert(db, *proj, app))
+
+	kssh, err := keys.GenerateSSHKey("app-mykey")
+	test.NoError(t, err)
+	k := &sdk.ApplicationKey{
+		Name:          kssh.Name,
+		Type:          kssh.Type,
+		Public:        kssh.Public,
+		Private:       kssh.Private,
+		ApplicationID: app.ID,
+	}
+	test.NoError(t, application.InsertKey(db, k))
+
+	vars := map[string]string{
+		"permProjectKey":  proj.Key,
+		"applicationName": app.Name,
+		"name":            k.Name,
+	}
+
+	jsonBody, _ := json.Marshal(sdk.KeyRotationRequest{SuccessorName: "mykey-v2", Algorithm: sdk.SSHKeyAlgorithmECDSA})
+	uri := router.GetRoute("POST", api.postRotateKeyInApplicationHandler, vars)
+	req, err := http.NewRequest("POST", uri, bytes.NewBuffer(jsonBody))
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+
+	var successor sdk.ApplicationKey
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &successor))
+	assert.Equal(t, "app-mykey-v2", successor.Name)
+
+	uri = router.GetRoute("GET", api.getKeyUsageInApplicationHandler, vars)
+	req, err = http.NewRequest("GET", uri, nil)
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	require.Equal(t, 200, w.Code)
+
+	var usage sdk.KeyUsage
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &usage))
+	assert.Equal(t, successor.Name, usage.Successor)
+	assert.Equal(t, []string{app.Name}, usage.Applications)
+
+	// The key can't be retired during the overlap period, even with force
+	uri = router.GetRoute("POST", api.postRetireKeyInApplicationHandler, vars)
+	req, err = http.NewRequest("POST", uri+"?force=true", nil)
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	// Both keys are given to the jobs during the overlap period
+	secrets, err := workflow.LoadApplicationSecrets(db, app.ID)
+	test.NoError(t, err)
+	assert.NotNil(t, sdk.VariableFind(secrets, "cds.key.app-mykey.priv"))
+	assert.NotNil(t, sdk.VariableFind(secrets, "cds.key.app-mykey-v2.priv"))
+
+	// Then only the successor is
+	ks, err := application.LoadAllKeysWithPrivateContent(db, app.ID)
+	test.NoError(t, err)
+	for i := range ks {
+		if ks[i].Name == k.Name {
+			retireAt := time.Now().Add(-time.Minute)
+			ks[i].RetireAt = &retireAt
+			test.NoError(t, application.UpdateKey(context.TODO(), db, &ks[i]))
+		}
+	}
+	secrets, err = workflow.LoadApplicationSecrets(db, app.ID)
+	test.NoError(t, err)
+	assert.Nil(t, sdk.VariableFind(secrets, "cds.key.app-mykey.priv"))
+	assert.NotNil(t, sdk.VariableFind(secrets, "cds.key.app-mykey-v2.priv"))
+
+	// The key is still used by the application
+	req, err = http.NewRequest("POST", uri, nil)
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	req, err = http.NewRequest("POST", uri+"?force=true", nil)
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 204, w.Code)
+}
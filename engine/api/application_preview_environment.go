@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getApplicationPreviewEnvironmentsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+
+		app, err := application.LoadByName(api.mustDB(), key, appName)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load application %s", appName)
+		}
+
+		previews, err := application.LoadPreviewEnvironments(api.mustDB(), app.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, previews, http.StatusOK)
+	}
+}
+
+// deleteApplicationPreviewEnvironmentHandler deletes a preview environment without running its teardown pipeline,
+// ie. to clean up an environment whose teardown keeps failing.
+func (api *API) deleteApplicationPreviewEnvironmentHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		appName := vars["applicationName"]
+		workflowName := vars["workflowName"]
+		pullRequestID, err := strconv.ParseInt(vars["pullRequestID"], 10, 64)
+		if err != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid pull request id %q", vars["pullRequestID"])
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		app, err := application.LoadByName(tx, key, appName)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load application %s", appName)
+		}
+
+		previews, err := application.LoadPreviewEnvironments(tx, app.ID)
+		if err != nil {
+			return err
+		}
+		var preview *sdk.PreviewEnvironment
+		for i := range previews {
+			if previews[i].WorkflowName == workflowName && previews[i].PullRequestID == pullRequestID {
+				preview = &previews[i]
+				break
+			}
+		}
+		if preview == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no preview environment for pull request %d of workflow %s", pullRequestID, workflowName)
+		}
+
+		log.Info(ctx, "deleting preview environment %s of pull request %d with status %s", preview.EnvironmentName, preview.PullRequestID, preview.Status)
+		if err := application.DeletePreviewEnvironment(tx, preview.ID); err != nil {
+			return err
+		}
+		if err := environment.DeleteEnvironment(tx, preview.EnvironmentID); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
@@ -43,8 +43,9 @@ func (api *API) postImportAsCodeHandler() service.Handler {
 			ope.LoadFiles.Pattern = workflow.WorkflowAsCodePattern
 		}
 
-		if ope.LoadFiles.Pattern != workflow.WorkflowAsCodePattern {
-			return sdk.WithStack(sdk.ErrWrongRequest)
+		// The pattern can target several workflow roots in a monorepo (ex: services/*/.cds/**/*.yml)
+		if _, err := sdk.AsCodeRootFromPattern(ope.LoadFiles.Pattern); err != nil {
+			return err
 		}
 
 		tx, err := api.mustDB().Begin()
@@ -155,52 +156,59 @@ func (api *API) postPerformImportAsCodeHandler() service.Handler {
 			return sdk.WithStack(sdk.ErrMethodNotAllowed)
 		}
 
-		tr, err := workflow.ReadCDSFiles(ope.LoadFiles.Results)
-		if err != nil {
-			return sdk.WrapError(err, "unable to read cds files")
-		}
-
 		//TODO: Delete branch and default branch
 		ope.RepositoryStrategy.Branch = "{{.git.branch}}"
 		ope.RepositoryStrategy.DefaultBranch = ope.RepositoryInfo.DefaultBranch
-		opt := &workflow.PushOption{
-			VCSServer:          ope.VCSServer,
-			RepositoryName:     ope.RepositoryInfo.Name,
-			RepositoryStrategy: ope.RepositoryStrategy,
-			Branch:             ope.Setup.Checkout.Branch,
-			FromRepository:     ope.RepositoryInfo.FetchURL,
-			IsDefaultBranch:    ope.Setup.Checkout.Branch == ope.RepositoryInfo.DefaultBranch,
-		}
-
-		data, err := exportentities.UntarWorkflowComponents(ctx, tr)
-		if err != nil {
-			return err
-		}
 
 		consumer := getAPIConsumer(ctx)
 
-		mods := []workflowtemplate.TemplateRequestModifierFunc{
-			workflowtemplate.TemplateRequestModifiers.DefaultKeys(*proj),
-		}
-		if !opt.IsDefaultBranch {
-			mods = append(mods, workflowtemplate.TemplateRequestModifiers.Detached)
-		}
-		if opt.FromRepository != "" {
-			mods = append(mods, workflowtemplate.TemplateRequestModifiers.DefaultNameAndRepositories(*proj, opt.FromRepository))
-		}
+		// Each workflow root of the repository is imported as its own workflow
 		var allMsg []sdk.Message
-		msgTemplate, wti, err := workflowtemplate.CheckAndExecuteTemplate(ctx, api.mustDB(), api.Cache, *consumer, *proj, &data, mods...)
-		allMsg = append(allMsg, msgTemplate...)
-		if err != nil {
-			return err
-		}
-		msgPush, wrkflw, _, _, err := workflow.Push(ctx, api.mustDB(), api.Cache, proj, data, opt, getAPIConsumer(ctx), project.DecryptWithBuiltinKey)
-		allMsg = append(allMsg, msgPush...)
-		if err != nil {
-			return sdk.WrapError(err, "unable to push workflow")
-		}
-		if err := workflowtemplate.UpdateTemplateInstanceWithWorkflow(ctx, api.mustDB(), *wrkflw, *consumer, wti); err != nil {
-			return err
+		var wrkflws []sdk.Workflow
+		roots, filesByRoot := workflow.SplitCDSFilesByRoot(ope.LoadFiles.Results)
+		for _, root := range roots {
+			tr, err := workflow.ReadCDSFiles(filesByRoot[root])
+			if err != nil {
+				return sdk.WrapError(err, "unable to read cds files")
+			}
+
+			opt := &workflow.PushOption{
+				VCSServer:          ope.VCSServer,
+				RepositoryName:     ope.RepositoryInfo.Name,
+				RepositoryStrategy: ope.RepositoryStrategy,
+				Branch:             ope.Setup.Checkout.Branch,
+				FromRepository:     sdk.NewAsCodeFromRepository(ope.RepositoryInfo.FetchURL, root),
+				IsDefaultBranch:    ope.Setup.Checkout.Branch == ope.RepositoryInfo.DefaultBranch,
+			}
+
+			data, err := exportentities.UntarWorkflowComponents(ctx, tr)
+			if err != nil {
+				return err
+			}
+
+			mods := []workflowtemplate.TemplateRequestModifierFunc{
+				workflowtemplate.TemplateRequestModifiers.DefaultKeys(*proj),
+			}
+			if !opt.IsDefaultBranch {
+				mods = append(mods, workflowtemplate.TemplateRequestModifiers.Detached)
+			}
+			if opt.FromRepository != "" {
+				mods = append(mods, workflowtemplate.TemplateRequestModifiers.DefaultNameAndRepositories(*proj, opt.FromRepository))
+			}
+			msgTemplate, wti, err := workflowtemplate.CheckAndExecuteTemplate(ctx, api.mustDB(), api.Cache, *consumer, *proj, &data, mods...)
+			allMsg = append(allMsg, msgTemplate...)
+			if err != nil {
+				return err
+			}
+			msgPush, wrkflw, _, _, err := workflow.Push(ctx, api.mustDB(), api.Cache, proj, data, opt, getAPIConsumer(ctx), project.DecryptWithBuiltinKey)
+			allMsg = append(allMsg, msgPush...)
+			if err != nil {
+				return sdk.WrapError(err, "unable to push workflow")
+			}
+			if err := workflowtemplate.UpdateTemplateInstanceWithWorkflow(ctx, api.mustDB(), *wrkflw, *consumer, wti); err != nil {
+				return err
+			}
+			wrkflws = append(wrkflws, *wrkflw)
 		}
 		msgListString := translate(allMsg)
 
@@ -229,12 +237,14 @@ func (api *API) postPerformImportAsCodeHandler() service.Handler {
 			return sdk.WithStack(err)
 		}
 
-		if wrkflw != nil {
-			w.Header().Add(sdk.ResponseWorkflowIDHeader, fmt.Sprintf("%d", wrkflw.ID))
-			w.Header().Add(sdk.ResponseWorkflowNameHeader, wrkflw.Name)
+		if len(wrkflws) > 0 {
+			w.Header().Add(sdk.ResponseWorkflowIDHeader, fmt.Sprintf("%d", wrkflws[0].ID))
+			w.Header().Add(sdk.ResponseWorkflowNameHeader, wrkflws[0].Name)
 		}
 
-		event.PublishWorkflowAdd(ctx, proj.Key, *wrkflw, getAPIConsumer(ctx))
+		for i := range wrkflws {
+			event.PublishWorkflowAdd(ctx, proj.Key, wrkflws[i], getAPIConsumer(ctx))
+		}
 
 		return service.WriteJSON(w, msgListString, http.StatusOK)
 	}
@@ -0,0 +1,222 @@
+package ascode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	v2 "github.com/ovh/cds/sdk/exportentities/v2"
+)
+
+// PreviewStatusNodeName is the name used in the commit status of as code previews.
+const PreviewStatusNodeName = "ascode-preview"
+
+// ComputeWorkflowDiff returns the structural changes between two versions of a workflow.
+func ComputeWorkflowDiff(ctx context.Context, oldWf, newWf sdk.Workflow) (sdk.AsCodeDiff, error) {
+	oldExport, err := v2.NewWorkflow(ctx, oldWf, exportentities.WorkflowVersion2, v2.WorkflowWithPermissions)
+	if err != nil {
+		return nil, err
+	}
+	newExport, err := v2.NewWorkflow(ctx, newWf, exportentities.WorkflowVersion2, v2.WorkflowWithPermissions)
+	if err != nil {
+		return nil, err
+	}
+
+	var diff sdk.AsCodeDiff
+
+	oldNodes, newNodes := make(map[string]interface{}), make(map[string]interface{})
+	for k, v := range oldExport.Workflow {
+		oldNodes[k] = v
+	}
+	for k, v := range newExport.Workflow {
+		newNodes[k] = v
+	}
+	diff = append(diff, diffEntities(sdk.AsCodeDiffKindNode, oldNodes, newNodes)...)
+
+	oldHooks, newHooks := make(map[string]interface{}), make(map[string]interface{})
+	for k, v := range oldExport.Hooks {
+		oldHooks[k] = v
+	}
+	for k, v := range newExport.Hooks {
+		newHooks[k] = v
+	}
+	diff = append(diff, diffEntities(sdk.AsCodeDiffKindHook, oldHooks, newHooks)...)
+
+	oldPerms, newPerms := make(map[string]interface{}), make(map[string]interface{})
+	for k, v := range oldExport.Permissions {
+		oldPerms[k] = v
+	}
+	for k, v := range newExport.Permissions {
+		newPerms[k] = v
+	}
+	diff = append(diff, diffEntities(sdk.AsCodeDiffKindPermission, oldPerms, newPerms)...)
+
+	oldIntegs, newIntegs := make(map[string]interface{}), make(map[string]interface{})
+	for k, v := range oldExport.WorkflowProjectIntegration {
+		oldIntegs[k] = v
+	}
+	for k, v := range newExport.WorkflowProjectIntegration {
+		newIntegs[k] = v
+	}
+	diff = append(diff, diffEntities(sdk.AsCodeDiffKindIntegration, oldIntegs, newIntegs)...)
+
+	oldPips, newPips := make(map[string]interface{}), make(map[string]interface{})
+	for _, p := range oldWf.Pipelines {
+		oldPips[p.Name] = exportentities.NewPipelineV1(p)
+	}
+	for _, p := range newWf.Pipelines {
+		newPips[p.Name] = exportentities.NewPipelineV1(p)
+	}
+	diff = append(diff, diffEntities(sdk.AsCodeDiffKindPipeline, oldPips, newPips)...)
+
+	return diff, nil
+}
+
+// diffEntities compares the yaml representation of exported entities, given maps are indexed by entity name.
+func diffEntities(kind string, oldEntities, newEntities map[string]interface{}) sdk.AsCodeDiff {
+	names := make([]string, 0, len(oldEntities)+len(newEntities))
+	for name := range oldEntities {
+		names = append(names, name)
+	}
+	for name := range newEntities {
+		if _, has := oldEntities[name]; !has {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var diff sdk.AsCodeDiff
+	for _, name := range names {
+		oldEntity, inOld := oldEntities[name]
+		newEntity, inNew := newEntities[name]
+		switch {
+		case !inOld:
+			diff = append(diff, sdk.AsCodeDiffEntry{Kind: kind, Name: name, Change: sdk.AsCodeDiffAdded})
+		case !inNew:
+			diff = append(diff, sdk.AsCodeDiffEntry{Kind: kind, Name: name, Change: sdk.AsCodeDiffRemoved})
+		default:
+			oldYAML, _ := yaml.Marshal(oldEntity)
+			newYAML, _ := yaml.Marshal(newEntity)
+			if string(oldYAML) != string(newYAML) {
+				diff = append(diff, sdk.AsCodeDiffEntry{Kind: kind, Name: name, Change: sdk.AsCodeDiffModified})
+			}
+		}
+	}
+	return diff
+}
+
+// SendPullRequestPreview posts the validation result and the structural diff of an as code workflow imported
+// from a branch on the opened pull request of this branch. Nothing is sent if there is no pull request or if the
+// workflow was imported without any structural change.
+func SendPullRequestPreview(ctx context.Context, db *gorp.DbMap, store cache.Store, proj sdk.Project, wf sdk.Workflow,
+	runNumber int64, branch, hash string, diff sdk.AsCodeDiff, importMsgs []sdk.Message, importErr error) error {
+	if branch == "" || hash == "" || wf.WorkflowData.Node.Context == nil {
+		return nil
+	}
+	if importErr == nil && len(diff) == 0 {
+		log.Debug(ctx, "SendPullRequestPreview> no structural change for workflow %s/%s on branch %s", proj.Key, wf.Name, branch)
+		return nil
+	}
+	rootApp, has := wf.Applications[wf.WorkflowData.Node.Context.ApplicationID]
+	if !has || rootApp.VCSServer == "" || rootApp.RepositoryFullname == "" {
+		return nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	vcsServer, err := repositoriesmanager.LoadProjectVCSServerLinkByProjectKeyAndVCSServerName(ctx, tx, proj.Key, rootApp.VCSServer)
+	if err != nil {
+		return err
+	}
+	client, err := repositoriesmanager.AuthorizedClient(ctx, tx, store, proj.Key, vcsServer)
+	if err != nil {
+		return sdk.NewErrorFrom(err, "unable to create repositories manager client")
+	}
+
+	prs, err := client.PullRequests(ctx, rootApp.RepositoryFullname, sdk.VCSRequestModifierWithState(sdk.VCSPullRequestStateOpen))
+	if err != nil {
+		return sdk.NewErrorFrom(err, "unable to list pull request")
+	}
+	var pr *sdk.VCSPullRequest
+	for i := range prs {
+		if prs[i].Head.Branch.DisplayID == branch && prs[i].Head.Branch.LatestCommit == hash {
+			pr = &prs[i]
+			break
+		}
+	}
+	if pr == nil {
+		return nil
+	}
+
+	status := sdk.StatusSuccess
+	if importErr != nil {
+		status = sdk.StatusFail
+	}
+
+	eventNR := sdk.EventRunWorkflowNode{
+		Number:                runNumber,
+		Status:                status,
+		Hash:                  hash,
+		BranchName:            branch,
+		NodeName:              PreviewStatusNodeName,
+		RepositoryManagerName: rootApp.VCSServer,
+		RepositoryFullName:    rootApp.RepositoryFullname,
+	}
+	payload, _ := json.Marshal(eventNR)
+	evt := sdk.Event{
+		EventType:       fmt.Sprintf("%T", eventNR),
+		Payload:         payload,
+		Timestamp:       time.Now(),
+		ProjectKey:      proj.Key,
+		WorkflowName:    wf.Name,
+		ApplicationName: rootApp.Name,
+	}
+	if err := client.SetStatus(ctx, evt); err != nil {
+		return sdk.NewErrorFrom(err, "unable to set as code preview status")
+	}
+
+	// The preview comment of the workflow is updated on each push instead of posting a new one
+	key, message := pullRequestPreviewComment(proj.Key, wf.Name, hash, diff, importMsgs, importErr)
+	req := sdk.VCSPullRequestCommentRequest{Message: message, Key: key}
+	req.ID = pr.ID
+	req.Revision = hash
+	if err := client.PullRequestComment(ctx, rootApp.RepositoryFullname, req); err != nil {
+		return sdk.NewErrorFrom(err, "unable to comment pull request")
+	}
+
+	return sdk.WithStack(tx.Commit())
+}
+
+// pullRequestPreviewComment returns the message of the preview comment and its key, the key is the first line of the message.
+func pullRequestPreviewComment(projKey, wfName, hash string, diff sdk.AsCodeDiff, importMsgs []sdk.Message, importErr error) (string, string) {
+	key := fmt.Sprintf("**CDS as code preview of workflow %s/%s**\n", projKey, wfName)
+
+	var b strings.Builder
+	b.WriteString(key)
+	if importErr != nil {
+		fmt.Fprintf(&b, "\nWorkflow is invalid on commit %s.\n\n", hash)
+		fmt.Fprintf(&b, "```\n%s\n```\n", sdk.ExtractHTTPError(importErr).Error())
+	} else {
+		fmt.Fprintf(&b, "\nChanges on commit %s.\n\n", hash)
+		b.WriteString(diff.Markdown())
+	}
+	for i := range importMsgs {
+		fmt.Fprintf(&b, "\n- %s", importMsgs[i].String())
+	}
+	return key, b.String()
+}
@@ -0,0 +1,75 @@
+package ascode
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestComputeWorkflowDiff(t *testing.T) {
+	newWorkflow := func() sdk.Workflow {
+		return sdk.Workflow{
+			Name: "my-workflow",
+			WorkflowData: sdk.WorkflowData{
+				Node: sdk.Node{
+					Name: "build",
+					Type: sdk.NodeTypePipeline,
+					Context: &sdk.NodeContext{
+						PipelineID: 1,
+					},
+					Triggers: []sdk.NodeTrigger{{
+						ChildNode: sdk.Node{
+							Name:    "deploy",
+							Type:    sdk.NodeTypePipeline,
+							Context: &sdk.NodeContext{PipelineID: 2},
+						},
+					}},
+				},
+			},
+			Pipelines: map[int64]sdk.Pipeline{
+				1: {Name: "build", Parameter: []sdk.Parameter{{Name: "param", Type: sdk.StringParameter, Value: "a"}}},
+				2: {Name: "deploy"},
+			},
+			Groups: []sdk.GroupPermission{{Group: sdk.Group{Name: "my-group"}, Permission: sdk.PermissionReadWriteExecute}},
+		}
+	}
+
+	oldWf := newWorkflow()
+	diff, err := ComputeWorkflowDiff(context.TODO(), oldWf, newWorkflow())
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	newWf := newWorkflow()
+	newWf.WorkflowData.Node.Triggers = nil
+	delete(newWf.Pipelines, 2)
+	newWf.Pipelines[1] = sdk.Pipeline{Name: "build", Parameter: []sdk.Parameter{{Name: "param", Type: sdk.StringParameter, Value: "b"}}}
+	newWf.Groups = append(newWf.Groups, sdk.GroupPermission{Group: sdk.Group{Name: "other-group"}, Permission: sdk.PermissionRead})
+	newWf.WorkflowData.Node.Hooks = []sdk.NodeHook{{HookModelName: sdk.SchedulerModelName, Config: sdk.SchedulerModel.DefaultConfig.Clone()}}
+
+	diff, err = ComputeWorkflowDiff(context.TODO(), oldWf, newWf)
+	require.NoError(t, err)
+	require.Equal(t, sdk.AsCodeDiff{
+		{Kind: sdk.AsCodeDiffKindNode, Name: "deploy", Change: sdk.AsCodeDiffRemoved},
+		{Kind: sdk.AsCodeDiffKindHook, Name: "build", Change: sdk.AsCodeDiffAdded},
+		{Kind: sdk.AsCodeDiffKindPermission, Name: "other-group", Change: sdk.AsCodeDiffAdded},
+		{Kind: sdk.AsCodeDiffKindPipeline, Name: "build", Change: sdk.AsCodeDiffModified},
+		{Kind: sdk.AsCodeDiffKindPipeline, Name: "deploy", Change: sdk.AsCodeDiffRemoved},
+	}, diff)
+}
+
+func TestPullRequestPreviewComment(t *testing.T) {
+	diff := sdk.AsCodeDiff{{Kind: sdk.AsCodeDiffKindNode, Name: "deploy", Change: sdk.AsCodeDiffAdded}}
+	key, message := pullRequestPreviewComment("PROJ", "my-workflow", "abcdef", diff, nil, nil)
+	require.Equal(t, "**CDS as code preview of workflow PROJ/my-workflow**\n", key)
+	require.Equal(t, key+"\nChanges on commit abcdef.\n\n| Kind | Name | Change |\n|---|---|---|\n| node | deploy | added |\n", message)
+
+	// The key of a workflow is not a prefix of the comment of another workflow
+	otherKey, otherMessage := pullRequestPreviewComment("PROJ", "my-workflow-2", "abcdef", nil, nil, sdk.ErrWrongRequest)
+	require.NotEqual(t, key, otherKey)
+	require.False(t, strings.HasPrefix(otherMessage, key))
+	require.True(t, strings.HasPrefix(otherMessage, otherKey+"\nWorkflow is invalid on commit abcdef."))
+}
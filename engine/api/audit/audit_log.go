@@ -0,0 +1,144 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// logLockID is the key of the advisory lock that serializes the writes of the audit log hash chain.
+const logLockID = 4242001
+
+// maxLogsLimit is the max number of audit logs returned by a query.
+const maxLogsLimit = 1000
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(sdk.AuditLog{}, "audit_log", true, "id"))
+}
+
+// InsertLogs appends records to the audit log. The hash of each record is chained with the hash
+// of the previous record, batches are serialized between API instances with an advisory lock.
+// Without key the records are not chained.
+func InsertLogs(ctx context.Context, db *gorp.DbMap, key []byte, ls []sdk.AuditLog) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	if len(key) == 0 {
+		for i := range ls {
+			if err := gorpmapping.Insert(tx, &ls[i]); err != nil {
+				return sdk.WrapError(err, "unable to insert audit log for request %s", ls[i].RequestID)
+			}
+		}
+		return sdk.WithStack(tx.Commit())
+	}
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", logLockID); err != nil {
+		return sdk.WrapError(err, "unable to lock audit log")
+	}
+
+	var previousHash sql.NullString
+	if err := tx.QueryRow("SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1").Scan(&previousHash); err != nil && err != sql.ErrNoRows {
+		return sdk.WrapError(err, "unable to load latest audit log")
+	}
+
+	for i := range ls {
+		ls[i].PreviousHash = previousHash.String
+		ls[i].Hash = ls[i].ComputeHash(key)
+		if err := gorpmapping.Insert(tx, &ls[i]); err != nil {
+			return sdk.WrapError(err, "unable to insert audit log for request %s", ls[i].RequestID)
+		}
+		previousHash.String = ls[i].Hash
+	}
+
+	return sdk.WithStack(tx.Commit())
+}
+
+func logsQuery(f sdk.AuditLogFilter, order string) gorpmapping.Query {
+	var clauses []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.Since != nil {
+		clauses = append(clauses, "created >= "+arg(*f.Since))
+	}
+	if f.Until != nil {
+		clauses = append(clauses, "created < "+arg(*f.Until))
+	}
+	if f.Username != "" {
+		clauses = append(clauses, "username = "+arg(f.Username))
+	}
+	if f.ConsumerID != "" {
+		clauses = append(clauses, "auth_consumer_id = "+arg(f.ConsumerID))
+	}
+	if f.Method != "" {
+		clauses = append(clauses, "method = "+arg(strings.ToUpper(f.Method)))
+	}
+	if f.Route != "" {
+		clauses = append(clauses, "route = "+arg(f.Route))
+	}
+	for k, v := range f.Target {
+		clauses = append(clauses, fmt.Sprintf("target->>%s = %s", arg(k), arg(v)))
+	}
+	if f.AfterID > 0 {
+		clauses = append(clauses, "id > "+arg(f.AfterID))
+	}
+
+	query := "SELECT * FROM audit_log"
+	if len(clauses) > 0 {
+		query += " WHERE " + strings.Join(clauses, " AND ")
+	}
+
+	limit := f.Limit
+	if limit <= 0 || limit > maxLogsLimit {
+		limit = maxLogsLimit
+	}
+	query += fmt.Sprintf(" ORDER BY id %s LIMIT %s OFFSET %s", order, arg(limit), arg(f.Offset))
+
+	return gorpmapping.NewQuery(query).Args(args...)
+}
+
+// LoadLogs returns audit logs matching given filter, latest first.
+func LoadLogs(ctx context.Context, db gorp.SqlExecutor, f sdk.AuditLogFilter) ([]sdk.AuditLog, error) {
+	var ls []sdk.AuditLog
+	if err := gorpmapping.GetAll(ctx, db, logsQuery(f, "DESC"), &ls); err != nil {
+		return nil, sdk.WrapError(err, "cannot get audit logs")
+	}
+	return ls, nil
+}
+
+// StreamLogs calls given func for each audit log matching given filter, oldest first.
+// Logs are loaded by batch so the whole audit log can be exported.
+func StreamLogs(ctx context.Context, db gorp.SqlExecutor, f sdk.AuditLogFilter, fn func(sdk.AuditLog) error) error {
+	f.Limit = maxLogsLimit
+	f.Offset = 0
+	for {
+		var ls []sdk.AuditLog
+		if err := gorpmapping.GetAll(ctx, db, logsQuery(f, "ASC"), &ls); err != nil {
+			return sdk.WrapError(err, "cannot get audit logs")
+		}
+		for i := range ls {
+			if err := fn(ls[i]); err != nil {
+				return err
+			}
+		}
+		if len(ls) < maxLogsLimit {
+			return nil
+		}
+		f.AfterID = ls[len(ls)-1].ID
+		if ctx.Err() != nil {
+			return sdk.WithStack(ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package audit_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
+)
+
+func newTestLog(target string) sdk.AuditLog {
+	return sdk.AuditLog{
+		Created:   time.Now().Truncate(time.Microsecond),
+		RequestID: sdk.RandomString(10),
+		Method:    "POST",
+		Route:     "/test",
+		Target:    sdk.AuditLogTarget{"test": target},
+		Status:    200,
+	}
+}
+
+func TestInsertLogs(t *testing.T) {
+	_, factory, _ := test.SetupPGWithFactory(t)
+	db := factory.GetDBMap(gorpmapping.Mapper)()
+
+	key := []byte(sdk.RandomString(32))
+	target := sdk.RandomString(10)
+	logs := []sdk.AuditLog{newTestLog(target), newTestLog(target)}
+	require.NoError(t, audit.InsertLogs(context.TODO(), db, key, logs))
+	require.NotZero(t, logs[0].ID)
+	require.Equal(t, logs[0].Hash, logs[1].PreviousHash)
+	require.NoError(t, sdk.VerifyAuditLogs(logs, key))
+
+	res, err := audit.LoadLogs(context.TODO(), db, sdk.AuditLogFilter{Target: map[string]string{"test": target}})
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	// Latest first
+	assert.Equal(t, logs[1].ID, res[0].ID)
+	for i := range res {
+		assert.Equal(t, res[i].ComputeHash(key), res[i].Hash, "hash should be the same after a read from the database")
+	}
+
+	// The audit log is append-only
+	_, err = db.Exec("UPDATE audit_log SET username = 'someone' WHERE id = $1", logs[0].ID)
+	require.Error(t, err)
+	_, err = db.Exec("DELETE FROM audit_log WHERE id = $1", logs[0].ID)
+	require.Error(t, err)
+}
+
+func TestInsertLogsConcurrently(t *testing.T) {
+	_, factory, _ := test.SetupPGWithFactory(t)
+	dbFunc := factory.GetDBMap(gorpmapping.Mapper)
+
+	key := []byte(sdk.RandomString(32))
+	target := sdk.RandomString(10)
+
+	// Each request writes its own record, the chain is serialized between API instances
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, audit.InsertLogs(context.TODO(), dbFunc(), key, []sdk.AuditLog{newTestLog(target)}))
+		}()
+	}
+	wg.Wait()
+
+	var logs []sdk.AuditLog
+	require.NoError(t, audit.StreamLogs(context.TODO(), dbFunc(), sdk.AuditLogFilter{Target: map[string]string{"test": target}}, func(l sdk.AuditLog) error {
+		logs = append(logs, l)
+		return nil
+	}))
+	require.Len(t, logs, 10)
+
+	// Records of other tests could be inserted between batches, each record should be chained with the previous one
+	var chain []sdk.AuditLog
+	require.NoError(t, audit.StreamLogs(context.TODO(), dbFunc(), sdk.AuditLogFilter{AfterID: logs[0].ID - 1}, func(l sdk.AuditLog) error {
+		if l.ID <= logs[len(logs)-1].ID {
+			chain = append(chain, l)
+		}
+		return nil
+	}))
+	for i := 1; i < len(chain); i++ {
+		require.Equal(t, chain[i-1].Hash, chain[i].PreviousHash, "broken chain between audit logs %d and %d", chain[i-1].ID, chain[i].ID)
+	}
+}
+
+func TestInsertLogsWithoutKey(t *testing.T) {
+	_, factory, _ := test.SetupPGWithFactory(t)
+	db := factory.GetDBMap(gorpmapping.Mapper)()
+
+	logs := []sdk.AuditLog{newTestLog(sdk.RandomString(10))}
+	require.NoError(t, audit.InsertLogs(context.TODO(), db, nil, logs))
+	require.NotZero(t, logs[0].ID)
+	assert.Empty(t, logs[0].PreviousHash)
+	assert.Empty(t, logs[0].Hash)
+}
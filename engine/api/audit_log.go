@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/doc"
+	cdslog "github.com/ovh/cds/sdk/log"
+)
+
+// auditInfo is set in the context of each request, handlers can use it to complete the audit log.
+type auditInfo struct {
+	target   sdk.AuditLogTarget
+	consumer *sdk.AuthConsumer
+}
+
+func getAuditInfo(ctx context.Context) *auditInfo {
+	i, _ := ctx.Value(contextAuditInfo).(*auditInfo)
+	return i
+}
+
+// setAuditConsumer sets the consumer of a request that is not authenticated, ie. a sign-in.
+func setAuditConsumer(ctx context.Context, consumer *sdk.AuthConsumer) {
+	if i := getAuditInfo(ctx); i != nil {
+		i.consumer = consumer
+	}
+}
+
+// addAuditTarget adds an entity that is not in the route variables to the audit of a request, ie. a created entity.
+func addAuditTarget(ctx context.Context, key, value string) {
+	if i := getAuditInfo(ctx); i != nil {
+		i.target[key] = value
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// auditRequest writes an audit log for mutating requests. It is written before the end of the request so a record
+// can't be lost once the request is done.
+func (api *API) auditRequest(ctx context.Context, req *http.Request, rc *service.HandlerConfig, statusCode int) {
+	if !isMutatingMethod(req.Method) {
+		return
+	}
+
+	l := sdk.AuditLog{
+		Created:   time.Now().Truncate(time.Microsecond),
+		RequestID: cdslog.ContextValue(ctx, cdslog.RequestID),
+		Method:    req.Method,
+		Route:     rc.CleanURL,
+		Handler:   rc.Name,
+		Target:    sdk.AuditLogTarget{},
+		Status:    statusCode,
+	}
+	// The source ip can't be set by the client, unlike the ip address of the logs that comes from a header
+	if sourceIP, _ := ctx.Value(contextSourceIP).(net.IP); sourceIP != nil {
+		l.IPAddress = sourceIP.String()
+	}
+	for k, v := range mux.Vars(req) {
+		l.Target[strings.ReplaceAll(doc.CleanURLParameter(k), "-", "_")] = v
+	}
+
+	consumer := getAPIConsumer(ctx)
+	if i := getAuditInfo(ctx); i != nil {
+		for k, v := range i.target {
+			l.Target[k] = v
+		}
+		if consumer == nil {
+			consumer = i.consumer
+		}
+	}
+	if consumer != nil {
+		l.AuthConsumerID = consumer.ID
+		l.AuthConsumerName = consumer.Name
+		l.AuthentifiedUserID = consumer.AuthentifiedUserID
+		if consumer.AuthentifiedUser != nil {
+			l.Username = consumer.AuthentifiedUser.Username
+		}
+	}
+
+	if err := audit.InsertLogs(ctx, api.mustDB(), []byte(api.Config.Audit.HMACKey), []sdk.AuditLog{l}); err != nil {
+		log.Error(ctx, "auditRequest> unable to write audit log for request %s: %v", l.RequestID, err)
+	}
+}
+
+func auditLogFilterFromRequest(r *http.Request) (sdk.AuditLogFilter, error) {
+	f := sdk.AuditLogFilter{
+		Username:   r.FormValue("username"),
+		ConsumerID: r.FormValue("consumer"),
+		Method:     r.FormValue("method"),
+		Route:      r.FormValue("route"),
+		Target:     map[string]string{},
+		Limit:      service.FormInt64(r, "limit"),
+		Offset:     service.FormInt64(r, "offset"),
+	}
+	for _, s := range []struct {
+		name string
+		dest **time.Time
+	}{{"since", &f.Since}, {"until", &f.Until}} {
+		v := r.FormValue(s.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid %s value, RFC3339 date expected", s.name)
+		}
+		*s.dest = &t
+	}
+	for _, t := range r.Form["target"] {
+		kv := strings.SplitN(t, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return f, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid target filter %q, key=value expected", t)
+		}
+		f.Target[kv[0]] = kv[1]
+	}
+	return f, nil
+}
+
+func (api *API) getAdminAuditLogsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		f, err := auditLogFilterFromRequest(r)
+		if err != nil {
+			return err
+		}
+
+		ls, err := audit.LoadLogs(ctx, api.mustDB(), f)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, ls, http.StatusOK)
+	}
+}
+
+func (api *API) getAdminAuditLogsExportHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		f, err := auditLogFilterFromRequest(r)
+		if err != nil {
+			return err
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		flusher, _ := w.(http.Flusher)
+		enc := json.NewEncoder(w)
+
+		// Headers are already sent, errors can only be logged
+		if err := audit.StreamLogs(ctx, api.mustDB(), f, func(l sdk.AuditLog) error {
+			if err := enc.Encode(l); err != nil {
+				return sdk.WithStack(err)
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		}); err != nil {
+			log.Error(ctx, "getAdminAuditLogsExportHandler> unable to export audit logs: %v", err)
+		}
+
+		return nil
+	}
+}
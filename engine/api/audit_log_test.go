@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/audit"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_auditRequest(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	admin, jwtAdmin := assets.InsertAdminUser(t, db)
+	groupName := sdk.RandomString(10)
+	uri := api.Router.GetRoute(http.MethodPost, api.postGroupHandler, nil)
+	require.NotEmpty(t, uri)
+
+	req := assets.NewJWTAuthentifiedRequest(t, jwtAdmin, http.MethodPost, uri, sdk.Group{Name: groupName})
+	req.RemoteAddr = "192.0.2.1:4242"
+	// The client is not a trusted proxy, its forwarded header is ignored
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+	rec := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	// Rejected requests are audited too
+	req = assets.NewJWTAuthentifiedRequest(t, "invalid", http.MethodPost, uri, sdk.Group{Name: groupName})
+	rec = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code, rec.Body.String())
+
+	// Read requests are not audited
+	req = assets.NewJWTAuthentifiedRequest(t, jwtAdmin, http.MethodGet, api.Router.GetRoute(http.MethodGet, api.getGroupsHandler, nil), nil)
+	rec = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// Services are audited
+	srv, _, jwtSrv := assets.InitCDNService(t, db)
+	req = assets.NewJWTAuthentifiedRequest(t, jwtSrv, http.MethodPost, api.Router.GetRoute(http.MethodPost, api.postServiceHearbeatHandler, nil), sdk.MonitoringStatus{})
+	rec = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	// Logs are written before the end of the requests
+	logs, err := audit.LoadLogs(context.TODO(), db, sdk.AuditLogFilter{Username: admin.Username})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, http.MethodPost, logs[0].Method)
+	assert.Equal(t, http.StatusCreated, logs[0].Status)
+	assert.Equal(t, admin.ID, logs[0].AuthentifiedUserID)
+	assert.Equal(t, groupName, logs[0].Target["group_name"])
+	assert.Equal(t, "192.0.2.1", logs[0].IPAddress, "the ip address should be the source ip of the request")
+
+	logs, err = audit.LoadLogs(context.TODO(), db, sdk.AuditLogFilter{Route: logs[0].Route, Target: map[string]string{"group_name": groupName}})
+	require.NoError(t, err)
+	require.Len(t, logs, 1, "the unauthorized request has no target")
+
+	logs, err = audit.LoadLogs(context.TODO(), db, sdk.AuditLogFilter{ConsumerID: *srv.ConsumerID})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, http.StatusNoContent, logs[0].Status)
+}
+
+func Test_getAdminAuditLogsExportHandler(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	key := []byte(sdk.RandomString(32))
+	api.Config.Audit.HMACKey = string(key)
+
+	admin, jwtAdmin := assets.InsertAdminUser(t, db)
+	since := time.Now().Add(-time.Second)
+	for i := 0; i < 3; i++ {
+		uri := api.Router.GetRoute(http.MethodPost, api.postGroupHandler, nil)
+		req := assets.NewJWTAuthentifiedRequest(t, jwtAdmin, http.MethodPost, uri, sdk.Group{Name: sdk.RandomString(10)})
+		rec := httptest.NewRecorder()
+		api.Router.Mux.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	uri := api.Router.GetRoute(http.MethodGet, api.getAdminAuditLogsExportHandler, nil)
+	require.NotEmpty(t, uri)
+	q := url.Values{}
+	q.Set("since", since.Format(time.RFC3339))
+	q.Set("username", admin.Username)
+	req := assets.NewJWTAuthentifiedRequest(t, jwtAdmin, http.MethodGet, uri+"?"+q.Encode(), nil)
+	rec := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+
+	var logs []sdk.AuditLog
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var l sdk.AuditLog
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &l))
+		logs = append(logs, l)
+	}
+	require.Len(t, logs, 3)
+	for i := range logs {
+		if i > 0 {
+			assert.Greater(t, logs[i].ID, logs[i-1].ID, "export should be ordered oldest first")
+		}
+		assert.Equal(t, logs[i].ComputeHash(key), logs[i].Hash, "hash should be computed with the configured key")
+	}
+	// Records were written one after the other, they are chained
+	require.NoError(t, sdk.VerifyAuditLogs(logs, key))
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ovh/cds/engine/api/authentication"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/scim"
 	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
@@ -254,6 +255,32 @@ func (api *API) postAuthSigninHandler() service.Handler {
 			}
 		}
 
+		// Synchronise the user groups from the groups given by the driver
+		if mappings, ok := api.GroupMappings[consumerType]; ok {
+			u, err := user.LoadByID(ctx, tx, consumer.AuthentifiedUserID)
+			if err != nil {
+				return err
+			}
+			if err := authentication.SyncUserGroups(ctx, tx, consumerType, u, userInfo.Groups, mappings, string(consumerType)+" signin"); err != nil {
+				return err
+			}
+			// The refresh token is used by the periodic synchronisation to get the groups of the user
+			if userInfo.RefreshToken != "" {
+				if err := authentication.SetConsumerRefreshToken(ctx, tx, consumer.ID, userInfo.RefreshToken); err != nil {
+					return err
+				}
+			}
+		}
+
+		// Users deactivated by SCIM provisioning can't signin
+		deactivated, err := scim.IsUserDeactivated(ctx, tx, consumer.AuthentifiedUserID)
+		if err != nil {
+			return err
+		}
+		if deactivated {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "user is deactivated")
+		}
+
 		// If a new user has been created and a first admin has been create,
 		// let's init the builtin consumers from the magix token
 		if signupDone && hasInitToken {
@@ -273,6 +300,7 @@ func (api *API) postAuthSigninHandler() service.Handler {
 		if err != nil {
 			return err
 		}
+		setAuditConsumer(ctx, consumer)
 
 		// Store the last authentication date on the consumer
 		now := time.Now()
@@ -53,11 +53,17 @@ func (api *API) postAuthBuiltinSigninHandler() service.Handler {
 			return sdk.NewError(sdk.ErrForbidden, err)
 		}
 
+		// Check that the request comes from a network allowed for the consumer
+		if err := checkConsumerAllowedIP(ctx, consumer); err != nil {
+			return sdk.NewError(sdk.ErrForbidden, err)
+		}
+
 		// Generate a new session for consumer
 		session, err := authentication.NewSession(ctx, tx, consumer, driver.GetSessionDuration())
 		if err != nil {
 			return err
 		}
+		setAuditConsumer(ctx, consumer)
 
 		// Store the last authentication date on the consumer
 		now := time.Now()
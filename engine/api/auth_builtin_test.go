@@ -53,7 +53,7 @@ func Test_postAuthBuiltinSigninHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	_, jws, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), sdk.RandomString(10), 0, localConsumer, usr.GetGroupIDs(),
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject), nil, nil)
 	require.NoError(t, err)
 	AuthentififyBuiltinConsumer(t, api, jws)
 }
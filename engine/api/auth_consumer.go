@@ -85,7 +85,7 @@ func (api *API) postConsumerByUserHandler() service.Handler {
 
 		// Create the new built in consumer from request data
 		newConsumer, token, err := builtin.NewConsumer(ctx, tx, reqData.Name, reqData.Description, reqData.ValidityPeriods.Latest().Duration,
-			consumer, reqData.GroupIDs, reqData.ScopeDetails)
+			consumer, reqData.GroupIDs, reqData.ScopeDetails, reqData.ResourceRestrictions, reqData.AllowedCIDRs)
 		if err != nil {
 			return err
 		}
@@ -26,7 +26,7 @@ func Test_getConsumersByUserHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	consumer, _, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), "", 0, localConsumer, nil,
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeUser))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeUser), nil, nil)
 	require.NoError(t, err)
 
 	uri := api.Router.GetRoute(http.MethodGet, api.getConsumersByUserHandler, map[string]string{
@@ -113,7 +113,7 @@ func Test_deleteConsumerByUserHandler(t *testing.T) {
 		authentication.LoadConsumerOptions.WithAuthentifiedUser)
 	require.NoError(t, err)
 	newConsumer, _, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), "", 0, localConsumer, nil,
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeAccessToken))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeAccessToken), nil, nil)
 	require.NoError(t, err)
 	cs, err := authentication.LoadConsumersByUserID(context.TODO(), db, u.ID)
 	require.NoError(t, err)
@@ -153,7 +153,7 @@ func Test_postConsumerRegenByUserHandler(t *testing.T) {
 	require.Equal(t, http.StatusForbidden, rec.Code)
 
 	builtinConsumer, signinToken1, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), "", 0, localConsumer, nil,
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeUser, sdk.AuthConsumerScopeAccessToken))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeUser, sdk.AuthConsumerScopeAccessToken), nil, nil)
 	require.NoError(t, err)
 	session, err := authentication.NewSession(context.TODO(), db, builtinConsumer, 5*time.Minute)
 	require.NoError(t, err, "cannot create session")
@@ -298,7 +298,7 @@ func Test_getSessionsByUserHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	consumer, _, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), "", 0, localConsumer, nil,
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeUser))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeUser), nil, nil)
 	require.NoError(t, err)
 	s2, err := authentication.NewSession(context.TODO(), db, consumer, time.Second)
 	require.NoError(t, err)
@@ -331,7 +331,7 @@ func Test_deleteSessionByUserHandler(t *testing.T) {
 	require.NoError(t, err)
 
 	consumer, _, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), "", 0, localConsumer, nil,
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeUser))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeUser), nil, nil)
 	require.NoError(t, err)
 	s2, err := authentication.NewSession(context.TODO(), db, consumer, time.Second)
 	require.NoError(t, err)
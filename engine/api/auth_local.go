@@ -208,6 +208,7 @@ func (api *API) postAuthLocalSigninHandler() service.Handler {
 		if err != nil {
 			return err
 		}
+		setAuditConsumer(ctx, consumer)
 
 		// Store the last authentication date on the consumer
 		now := time.Now()
@@ -348,6 +349,7 @@ func (api *API) postAuthLocalVerifyHandler() service.Handler {
 		if err != nil {
 			return err
 		}
+		setAuditConsumer(ctx, consumer)
 
 		// Generate a jwt for current session
 		jwt, err := authentication.NewSessionJWT(session, "")
@@ -520,6 +522,7 @@ func (api *API) postAuthLocalResetHandler() service.Handler {
 		if err != nil {
 			return err
 		}
+		setAuditConsumer(ctx, consumer)
 
 		// Generate a jwt for current session
 		jwt, err := authentication.NewSessionJWT(session, "")
@@ -66,7 +66,7 @@ func (d AuthDriver) CheckSigninRequest(req sdk.AuthConsumerSigninRequest) error
 // NewConsumer returns a new builtin consumer for given data.
 // The parent consumer should be given with all data loaded including the authentified user.
 func NewConsumer(ctx context.Context, db gorpmapper.SqlExecutorWithTx, name, description string, duration time.Duration, parentConsumer *sdk.AuthConsumer,
-	groupIDs []int64, scopes sdk.AuthConsumerScopeDetails) (*sdk.AuthConsumer, string, error) {
+	groupIDs []int64, scopes sdk.AuthConsumerScopeDetails, restrictions sdk.AuthConsumerResourceRestrictions, allowedCIDRs sdk.AuthConsumerAllowedCIDRs) (*sdk.AuthConsumer, string, error) {
 	if name == "" {
 		return nil, "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "name should be given to create a built in consumer")
 	}
@@ -91,16 +91,28 @@ func NewConsumer(ctx context.Context, db gorpmapper.SqlExecutorWithTx, name, des
 		return nil, "", err
 	}
 
+	// A child consumer inherits the restrictions of its parent and can only reduce them
+	restrictions, err := checkNewConsumerResourceRestrictions(parentConsumer.ResourceRestrictions, restrictions)
+	if err != nil {
+		return nil, "", err
+	}
+	allowedCIDRs, err = checkNewConsumerAllowedCIDRs(parentConsumer.AllowedCIDRs, allowedCIDRs)
+	if err != nil {
+		return nil, "", err
+	}
+
 	c := sdk.AuthConsumer{
-		Name:               name,
-		Description:        description,
-		ParentID:           &parentConsumer.ID,
-		AuthentifiedUserID: parentConsumer.AuthentifiedUserID,
-		Type:               sdk.ConsumerBuiltin,
-		Data:               map[string]string{},
-		GroupIDs:           groupIDs,
-		ScopeDetails:       scopes,
-		ValidityPeriods:    sdk.NewAuthConsumerValidityPeriod(time.Now(), duration),
+		Name:                 name,
+		Description:          description,
+		ParentID:             &parentConsumer.ID,
+		AuthentifiedUserID:   parentConsumer.AuthentifiedUserID,
+		Type:                 sdk.ConsumerBuiltin,
+		Data:                 map[string]string{},
+		GroupIDs:             groupIDs,
+		ScopeDetails:         scopes,
+		ValidityPeriods:      sdk.NewAuthConsumerValidityPeriod(time.Now(), duration),
+		ResourceRestrictions: restrictions,
+		AllowedCIDRs:         allowedCIDRs,
 	}
 
 	if err := authentication.InsertConsumer(ctx, db, &c); err != nil {
@@ -172,3 +184,33 @@ func checkNewConsumerScopes(parentScopes, scopes sdk.AuthConsumerScopeDetails) e
 
 	return nil
 }
+
+func checkNewConsumerResourceRestrictions(parentRestrictions, restrictions sdk.AuthConsumerResourceRestrictions) (sdk.AuthConsumerResourceRestrictions, error) {
+	if err := restrictions.IsValid(); err != nil {
+		return nil, err
+	}
+	if len(restrictions) == 0 {
+		return parentRestrictions, nil
+	}
+	for _, r := range restrictions {
+		if !parentRestrictions.Contains(r) {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given resource restriction on %s when creating built in consumer", r.ProjectKey)
+		}
+	}
+	return restrictions, nil
+}
+
+func checkNewConsumerAllowedCIDRs(parentCIDRs, cidrs sdk.AuthConsumerAllowedCIDRs) (sdk.AuthConsumerAllowedCIDRs, error) {
+	if err := cidrs.IsValid(); err != nil {
+		return nil, err
+	}
+	if len(cidrs) == 0 {
+		return parentCIDRs, nil
+	}
+	for _, c := range cidrs {
+		if !parentCIDRs.ContainsCIDR(c) {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given CIDR %s when creating built in consumer", c)
+		}
+	}
+	return cidrs, nil
+}
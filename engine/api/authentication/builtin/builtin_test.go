@@ -148,3 +148,27 @@ func Test_checkNewConsumerScopes(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkNewConsumerRestrictions(t *testing.T) {
+	parent := sdk.AuthConsumerResourceRestrictions{{ProjectKey: "PROJ", Permission: sdk.PermissionReadExecute}}
+
+	// Restrictions are inherited from parent when not given
+	rs, err := checkNewConsumerResourceRestrictions(parent, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, parent, rs)
+
+	_, err = checkNewConsumerResourceRestrictions(parent, sdk.AuthConsumerResourceRestrictions{{ProjectKey: "PROJ", WorkflowName: "build", Permission: sdk.PermissionRead}})
+	assert.NoError(t, err)
+	_, err = checkNewConsumerResourceRestrictions(parent, sdk.AuthConsumerResourceRestrictions{{ProjectKey: "PROJ", Permission: sdk.PermissionReadWriteExecute}})
+	assert.Error(t, err)
+	_, err = checkNewConsumerResourceRestrictions(parent, sdk.AuthConsumerResourceRestrictions{{ProjectKey: "OTHER", Permission: sdk.PermissionRead}})
+	assert.Error(t, err)
+
+	cidrs, err := checkNewConsumerAllowedCIDRs(sdk.AuthConsumerAllowedCIDRs{"10.0.0.0/8"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sdk.AuthConsumerAllowedCIDRs{"10.0.0.0/8"}, cidrs)
+	_, err = checkNewConsumerAllowedCIDRs(sdk.AuthConsumerAllowedCIDRs{"10.0.0.0/8"}, sdk.AuthConsumerAllowedCIDRs{"10.1.0.0/16"})
+	assert.NoError(t, err)
+	_, err = checkNewConsumerAllowedCIDRs(sdk.AuthConsumerAllowedCIDRs{"10.0.0.0/8"}, sdk.AuthConsumerAllowedCIDRs{"192.168.0.0/16"})
+	assert.Error(t, err)
+}
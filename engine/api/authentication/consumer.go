@@ -181,13 +181,14 @@ func ConsumerRestoreInvalidatedGroupForUser(ctx context.Context, db gorpmapper.S
 		cs[i].GroupIDs = append(cs[i].GroupIDs, groupID)
 
 		// If the consumer was disabled because there was no group left inside, it can be re-enable
-		cs[i].Disabled = false
+		// unless its user was deactivated
+		cs[i].Disabled = cs[i].Warnings.Contains(sdk.WarningUserDeactivated)
 
 		// Clean warnings, removes warning for current group and last group removed warning if exists
 		filteredWarnings := make(sdk.AuthConsumerWarnings, 0, len(cs[i].Warnings))
 		for _, w := range cs[i].Warnings {
 			if (w.Type == sdk.WarningGroupInvalid && w.GroupID != groupID) ||
-				w.Type == sdk.WarningGroupRemoved {
+				w.Type == sdk.WarningGroupRemoved || w.Type == sdk.WarningUserDeactivated {
 				filteredWarnings = append(filteredWarnings, w)
 			}
 		}
@@ -256,12 +257,13 @@ func ConsumerRestoreInvalidatedGroupsForUser(ctx context.Context, db gorpmapper.
 		cs[i].InvalidGroupIDs = nil
 
 		// If the consumer was disabled because there was no group left inside, it can be re-enable
-		cs[i].Disabled = false
+		// unless its user was deactivated
+		cs[i].Disabled = cs[i].Warnings.Contains(sdk.WarningUserDeactivated)
 
 		// Clean warnings, removes warning for invalid groups and last group removed warning if exists
 		filteredWarnings := make(sdk.AuthConsumerWarnings, 0, len(cs[i].Warnings))
 		for _, w := range cs[i].Warnings {
-			if w.Type == sdk.WarningGroupRemoved {
+			if w.Type == sdk.WarningGroupRemoved || w.Type == sdk.WarningUserDeactivated {
 				filteredWarnings = append(filteredWarnings, w)
 			}
 		}
@@ -274,3 +276,63 @@ func ConsumerRestoreInvalidatedGroupsForUser(ctx context.Context, db gorpmapper.
 
 	return nil
 }
+
+// ConsumerDeactivateForUser disables all user's consumers, sets a warning and deletes their sessions.
+func ConsumerDeactivateForUser(ctx context.Context, db gorpmapper.SqlExecutorWithTx, userID string) error {
+	cs, err := LoadConsumersByUserID(ctx, db, userID)
+	if err != nil {
+		return err
+	}
+	for i := range cs {
+		if cs[i].Warnings.Contains(sdk.WarningUserDeactivated) {
+			continue
+		}
+		cs[i].Disabled = true
+		cs[i].Warnings = append(cs[i].Warnings, sdk.NewConsumerWarningUserDeactivated())
+		if err := UpdateConsumer(ctx, db, &cs[i]); err != nil {
+			return err
+		}
+	}
+
+	sessions, err := LoadSessionsByConsumerIDs(ctx, db, cs.IDs())
+	if err != nil {
+		return err
+	}
+	for i := range sessions {
+		if err := DeleteSessionByID(db, sessions[i].ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ConsumerReactivateForUser enables user's consumers that were disabled by its deactivation.
+func ConsumerReactivateForUser(ctx context.Context, db gorpmapper.SqlExecutorWithTx, userID string) error {
+	cs, err := LoadConsumersByUserID(ctx, db, userID)
+	if err != nil {
+		return err
+	}
+	for i := range cs {
+		if !cs[i].Warnings.Contains(sdk.WarningUserDeactivated) {
+			continue
+		}
+
+		filteredWarnings := make(sdk.AuthConsumerWarnings, 0, len(cs[i].Warnings))
+		for _, w := range cs[i].Warnings {
+			if w.Type != sdk.WarningUserDeactivated {
+				filteredWarnings = append(filteredWarnings, w)
+			}
+		}
+		cs[i].Warnings = filteredWarnings
+
+		// A consumer without group should stay disabled
+		cs[i].Disabled = cs[i].Warnings.Contains(sdk.WarningLastGroupRemoved) && len(cs[i].GroupIDs) == 0
+
+		if err := UpdateConsumer(ctx, db, &cs[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
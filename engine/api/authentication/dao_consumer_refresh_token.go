@@ -0,0 +1,66 @@
+package authentication
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func getConsumerRefreshToken(ctx context.Context, db gorp.SqlExecutor, consumerID string, opts ...gorpmapping.GetOptionFunc) (*authConsumerRefreshToken, error) {
+	var t authConsumerRefreshToken
+	query := gorpmapping.NewQuery("SELECT * FROM auth_consumer_refresh_token WHERE consumer_id = $1").Args(consumerID)
+	found, err := gorpmapping.Get(ctx, db, query, &t, opts...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get refresh token of auth consumer %s", consumerID)
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	isValid, err := gorpmapping.CheckSignature(t, t.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !isValid {
+		log.Error(ctx, "authentication.getConsumerRefreshToken> refresh token of auth consumer %s data corrupted", consumerID)
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &t, nil
+}
+
+// LoadConsumerRefreshTokenWithDecryption returns the refresh token given by the auth driver for a consumer.
+func LoadConsumerRefreshTokenWithDecryption(ctx context.Context, db gorp.SqlExecutor, consumerID string) (string, error) {
+	t, err := getConsumerRefreshToken(ctx, db, consumerID, gorpmapping.GetOptions.WithDecryption)
+	if err != nil {
+		return "", err
+	}
+	return t.RefreshToken, nil
+}
+
+// SetConsumerRefreshToken inserts or replaces the refresh token of a consumer.
+func SetConsumerRefreshToken(ctx context.Context, db gorpmapper.SqlExecutorWithTx, consumerID, refreshToken string) error {
+	t := authConsumerRefreshToken{
+		ConsumerID:   consumerID,
+		RefreshToken: refreshToken,
+		Updated:      time.Now(),
+	}
+	_, err := getConsumerRefreshToken(ctx, db, consumerID)
+	switch {
+	case sdk.ErrorIs(err, sdk.ErrNotFound):
+		return sdk.WrapError(gorpmapping.InsertAndSign(ctx, db, &t), "unable to insert refresh token of auth consumer %s", consumerID)
+	case err != nil:
+		return err
+	}
+	return sdk.WrapError(gorpmapping.UpdateAndSign(ctx, db, &t), "unable to update refresh token of auth consumer %s", consumerID)
+}
+
+// DeleteConsumerRefreshToken removes the refresh token of a consumer.
+func DeleteConsumerRefreshToken(db gorp.SqlExecutor, consumerID string) error {
+	_, err := db.Exec("DELETE FROM auth_consumer_refresh_token WHERE consumer_id = $1", consumerID)
+	return sdk.WrapError(err, "unable to delete refresh token of auth consumer %s", consumerID)
+}
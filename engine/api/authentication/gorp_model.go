@@ -1,6 +1,8 @@
 package authentication
 
 import (
+	"time"
+
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/engine/gorpmapper"
 	"github.com/ovh/cds/sdk"
@@ -12,8 +14,9 @@ type authConsumer struct {
 }
 
 func (c authConsumer) Canonical() gorpmapper.CanonicalForms {
-	_ = []interface{}{c.ID, c.AuthentifiedUserID, c.Type, c.Data, c.Created, c.GroupIDs, c.ScopeDetails, c.Disabled} // Checks that fields exists at compilation
+	_ = []interface{}{c.ID, c.AuthentifiedUserID, c.Type, c.Data, c.Created, c.GroupIDs, c.ScopeDetails, c.Disabled, c.ResourceRestrictions, c.AllowedCIDRs} // Checks that fields exists at compilation
 	return []gorpmapper.CanonicalForm{
+		"{{.ID}}{{.AuthentifiedUserID}}{{print .Type}}{{print .Data}}{{printDate .Created}}{{print .GroupIDs}}{{print .ScopeDetails}}{{print .Disabled}}{{print .ResourceRestrictions}}{{print .AllowedCIDRs}}",
 		"{{.ID}}{{.AuthentifiedUserID}}{{print .Type}}{{print .Data}}{{printDate .Created}}{{print .GroupIDs}}{{print .ScopeDetails}}{{print .Disabled}}",
 	}
 }
@@ -30,9 +33,25 @@ func (s authSession) Canonical() gorpmapper.CanonicalForms {
 	}
 }
 
+// authConsumerRefreshToken is the refresh token given by an auth driver at signin, it is used to synchronise the groups of the user.
+type authConsumerRefreshToken struct {
+	ConsumerID   string    `db:"consumer_id"`
+	RefreshToken string    `db:"cipher_refresh_token" gorpmapping:"encrypted,ConsumerID"`
+	Updated      time.Time `db:"updated"`
+	gorpmapper.SignedEntity
+}
+
+func (t authConsumerRefreshToken) Canonical() gorpmapper.CanonicalForms {
+	_ = []interface{}{t.ConsumerID, t.Updated} // Checks that fields exists at compilation
+	return []gorpmapper.CanonicalForm{
+		"{{.ConsumerID}}{{printDate .Updated}}",
+	}
+}
+
 func init() {
 	gorpmapping.Register(
 		gorpmapping.New(authConsumer{}, "auth_consumer", false, "id"),
 		gorpmapping.New(authSession{}, "auth_session", false, "id"),
+		gorpmapping.New(authConsumerRefreshToken{}, "auth_consumer_refresh_token", false, "consumer_id"),
 	)
 }
@@ -0,0 +1,259 @@
+package authentication
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+// GroupMapping links a group given by an auth driver to a CDS group.
+type GroupMapping struct {
+	ExternalGroup string
+	GroupName     string
+}
+
+// GroupMappings is a list of group mappings for an auth driver.
+type GroupMappings []GroupMapping
+
+// ParseGroupMappings returns mappings for given values formatted as externalGroup=cdsGroup.
+func ParseGroupMappings(values []string) (GroupMappings, error) {
+	ms := make(GroupMappings, 0, len(values))
+	for _, v := range values {
+		i := strings.LastIndex(v, "=")
+		if i < 0 {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid group mapping %q, expected format is externalGroup=cdsGroup", v)
+		}
+		m := GroupMapping{
+			ExternalGroup: strings.TrimSpace(v[:i]),
+			GroupName:     strings.TrimSpace(v[i+1:]),
+		}
+		if m.ExternalGroup == "" || m.GroupName == "" {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid group mapping %q, expected format is externalGroup=cdsGroup", v)
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+// GroupNames returns the names of the CDS groups managed by the mappings.
+func (m GroupMappings) GroupNames() []string {
+	var names []string
+	for i := range m {
+		if !sdk.IsInArray(m[i].GroupName, names) {
+			names = append(names, m[i].GroupName)
+		}
+	}
+	return names
+}
+
+// Resolve returns the names of the CDS groups mapped from given external groups, external group names are not case sensitive.
+func (m GroupMappings) Resolve(externalGroups []string) []string {
+	var names []string
+	for i := range m {
+		for _, e := range externalGroups {
+			if strings.EqualFold(m[i].ExternalGroup, e) && !sdk.IsInArray(m[i].GroupName, names) {
+				names = append(names, m[i].GroupName)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SyncUserGroups adds the user in the CDS groups mapped from its external groups and removes it from the mapped
+// groups it no longer belongs to. Groups that are not the target of a mapping are left untouched.
+func SyncUserGroups(ctx context.Context, db gorpmapper.SqlExecutorWithTx, consumerType sdk.AuthConsumerType, u *sdk.AuthentifiedUser,
+	externalGroups []string, mappings GroupMappings, triggeredBy string) error {
+	names := mappings.GroupNames()
+	if len(names) == 0 {
+		return nil
+	}
+
+	gs, err := group.LoadAllByNames(ctx, db, names)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		var found bool
+		for i := range gs {
+			found = found || gs[i].Name == name
+		}
+		if !found {
+			log.Warn(ctx, "authentication.SyncUserGroups> group %s given in %s mappings not found", name, consumerType)
+		}
+	}
+
+	links, err := group.LoadLinksGroupUserForUserIDs(ctx, db, []string{u.ID})
+	if err != nil {
+		return err
+	}
+	linksByGroupID := make(map[int64]group.LinkGroupUser, len(links))
+	for i := range links {
+		linksByGroupID[links[i].GroupID] = links[i]
+	}
+
+	wanted := mappings.Resolve(externalGroups)
+	for i := range gs {
+		g := &gs[i]
+		link, isMember := linksByGroupID[g.ID]
+		shouldBeMember := sdk.IsInArray(g.Name, wanted)
+
+		audit := sdk.AuditGroupSync{
+			AuditCommon: sdk.AuditCommon{
+				TriggeredBy: triggeredBy,
+				Created:     time.Now(),
+			},
+			GroupID:            g.ID,
+			GroupName:          g.Name,
+			AuthentifiedUserID: u.ID,
+			Username:           u.Username,
+			ConsumerType:       consumerType,
+			ExternalGroups:     strings.Join(externalGroups, ","),
+		}
+
+		switch {
+		case shouldBeMember && !isMember:
+			if err := group.InsertLinkGroupUser(ctx, db, &group.LinkGroupUser{
+				GroupID:            g.ID,
+				AuthentifiedUserID: u.ID,
+			}); err != nil {
+				return err
+			}
+			if err := ConsumerRestoreInvalidatedGroupForUser(ctx, db, g.ID, u.ID); err != nil {
+				return err
+			}
+			audit.EventType = sdk.AuditAdd
+		case !shouldBeMember && isMember:
+			// The membership is revoked even for the last admin of the group, a CDS admin can still manage the group
+			if err := group.DeleteLinkGroupUser(db, &link); err != nil {
+				return err
+			}
+			if err := ConsumerInvalidateGroupForUser(ctx, db, g, u); err != nil {
+				return err
+			}
+			audit.EventType = sdk.AuditDelete
+		default:
+			continue
+		}
+
+		log.Info(ctx, "authentication.SyncUserGroups> %s user %s in group %s from %s groups", audit.EventType, u.Username, g.Name, consumerType)
+		if err := group.InsertAudit(db, &audit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GroupSynchronizer periodically synchronises the groups of all users that have a consumer for given driver.
+// It must be run as a goroutine.
+func GroupSynchronizer(ctx context.Context, dbFunc func() *gorp.DbMap, driver sdk.AuthDriver, mappings GroupMappings, tickerDuration time.Duration) {
+	consumerType := driver.GetManifest().Type
+	log.Info(ctx, "Initializing %s group synchronizer...", consumerType)
+	tick := time.NewTicker(tickerDuration)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if ctx.Err() != nil {
+				log.Error(ctx, "GroupSynchronizer> Exiting %s group synchronizer: %v", consumerType, ctx.Err())
+				return
+			}
+		case <-tick.C:
+			if err := SyncAllUserGroups(ctx, dbFunc(), driver, mappings); err != nil {
+				log.Error(ctx, "GroupSynchronizer> %v", err)
+			}
+		}
+	}
+}
+
+// SyncAllUserGroups synchronises the groups of all users that have a consumer for given driver. The driver should
+// look up the groups of a user from its username or from the refresh token given at signin.
+func SyncAllUserGroups(ctx context.Context, db *gorp.DbMap, driver sdk.AuthDriver, mappings GroupMappings) error {
+	consumerType := driver.GetManifest().Type
+	switch driver.(type) {
+	case sdk.AuthDriverWithUserGroups, sdk.AuthDriverWithRefreshedUserGroups:
+	default:
+		return sdk.WithStack(fmt.Errorf("%s driver can't look up the groups of a user", consumerType))
+	}
+
+	cs, err := LoadConsumersByType(ctx, db, consumerType)
+	if err != nil {
+		return err
+	}
+	for i := range cs {
+		if err := syncConsumerUserGroups(ctx, db, driver, mappings, cs[i]); err != nil {
+			log.Error(ctx, "SyncAllUserGroups> unable to synchronise groups for %s consumer %s: %v", consumerType, cs[i].ID, err)
+		}
+	}
+	return nil
+}
+
+func syncConsumerUserGroups(ctx context.Context, db *gorp.DbMap, driver sdk.AuthDriver, mappings GroupMappings, c sdk.AuthConsumer) error {
+	var externalGroups []string
+	var refreshToken, newRefreshToken string
+	var err error
+	switch d := driver.(type) {
+	case sdk.AuthDriverWithUserGroups:
+		externalGroups, err = d.GetUserGroups(ctx, c.Data["username"])
+		if err != nil {
+			return err
+		}
+	case sdk.AuthDriverWithRefreshedUserGroups:
+		refreshToken, err = LoadConsumerRefreshTokenWithDecryption(ctx, db, c.ID)
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			log.Debug(ctx, "syncConsumerUserGroups> no refresh token for %s consumer %s, the user has to sign in again", c.Type, c.ID)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		externalGroups, newRefreshToken, err = d.RefreshUserGroups(ctx, refreshToken)
+		// If the refresh token was revoked or expired, the user is removed from all the mapped groups
+		// until its next signin
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrUnauthorized) {
+			return err
+		}
+		if err != nil {
+			log.Info(ctx, "syncConsumerUserGroups> refresh token of %s consumer %s is no longer valid: %v", c.Type, c.ID, err)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	u, err := user.LoadByID(ctx, tx, c.AuthentifiedUserID)
+	if err != nil {
+		return err
+	}
+	if err := SyncUserGroups(ctx, tx, c.Type, u, externalGroups, mappings, string(c.Type)+" sync"); err != nil {
+		return err
+	}
+
+	if refreshToken != "" {
+		if newRefreshToken == "" {
+			if err := DeleteConsumerRefreshToken(tx, c.ID); err != nil {
+				return err
+			}
+		} else if newRefreshToken != refreshToken {
+			if err := SetConsumerRefreshToken(ctx, tx, c.ID, newRefreshToken); err != nil {
+				return err
+			}
+		}
+	}
+
+	return sdk.WithStack(tx.Commit())
+}
@@ -0,0 +1,130 @@
+package authentication_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseGroupMappings(t *testing.T) {
+	ms, err := authentication.ParseGroupMappings([]string{"developers=team-a", " ops = team-b", "Admins=team-a"})
+	require.NoError(t, err)
+	require.Len(t, ms, 3)
+	assert.Equal(t, authentication.GroupMapping{ExternalGroup: "ops", GroupName: "team-b"}, ms[1])
+	assert.Equal(t, []string{"team-a", "team-b"}, ms.GroupNames())
+	assert.Equal(t, []string{"team-a"}, ms.Resolve([]string{"admins", "unknown"}))
+	assert.Equal(t, []string{"team-a", "team-b"}, ms.Resolve([]string{"ops", "developers"}))
+	assert.Len(t, ms.Resolve(nil), 0)
+
+	_, err = authentication.ParseGroupMappings([]string{"developers"})
+	require.Error(t, err)
+	_, err = authentication.ParseGroupMappings([]string{"developers="})
+	require.Error(t, err)
+}
+
+func TestSyncUserGroups(t *testing.T) {
+	db, _ := test.SetupPG(t, bootstrap.InitiliazeDB)
+
+	g1 := assets.InsertTestGroup(t, db, sdk.RandomString(10))
+	g2 := assets.InsertTestGroup(t, db, sdk.RandomString(10))
+	g3 := assets.InsertTestGroup(t, db, sdk.RandomString(10))
+	u, _ := assets.InsertLambdaUser(t, db, g3)
+
+	mappings := authentication.GroupMappings{
+		{ExternalGroup: "developers", GroupName: g1.Name},
+		{ExternalGroup: "ops", GroupName: g2.Name},
+	}
+
+	// The user should be added in groups mapped from its external groups, other groups are not changed
+	require.NoError(t, authentication.SyncUserGroups(context.TODO(), db, sdk.ConsumerLDAP, u, []string{"developers", "ops"}, mappings, "ldap signin"))
+	gs, err := group.LoadAllByUserID(context.TODO(), db, u.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{g1.ID, g2.ID, g3.ID}, gs.ToIDs())
+
+	// The user left the ops team so it should be removed from the mapped group
+	require.NoError(t, authentication.SyncUserGroups(context.TODO(), db, sdk.ConsumerLDAP, u, []string{"developers"}, mappings, "ldap sync"))
+	gs, err = group.LoadAllByUserID(context.TODO(), db, u.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int64{g1.ID, g3.ID}, gs.ToIDs())
+
+	audits, err := group.LoadAuditsByGroupID(context.TODO(), db, g2.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, audits, 2)
+	assert.Equal(t, sdk.AuditDelete, audits[0].EventType)
+	assert.Equal(t, "ldap sync", audits[0].TriggeredBy)
+	assert.Equal(t, u.Username, audits[0].Username)
+	assert.Equal(t, sdk.AuditAdd, audits[1].EventType)
+
+	// Without change, nothing should be audited
+	require.NoError(t, authentication.SyncUserGroups(context.TODO(), db, sdk.ConsumerLDAP, u, []string{"developers"}, mappings, "ldap sync"))
+	audits, err = group.LoadAuditsByGroupID(context.TODO(), db, g1.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, audits, 1)
+}
+
+// fakeRefreshDriver returns the groups associated with a refresh token and rotates it
+type fakeRefreshDriver struct {
+	groups map[string][]string
+}
+
+func (d fakeRefreshDriver) GetManifest() sdk.AuthDriverManifest {
+	return sdk.AuthDriverManifest{Type: sdk.ConsumerOIDC}
+}
+
+func (d fakeRefreshDriver) GetSessionDuration() time.Duration { return time.Hour }
+
+func (d fakeRefreshDriver) CheckSigninRequest(sdk.AuthConsumerSigninRequest) error { return nil }
+
+func (d fakeRefreshDriver) GetUserInfo(context.Context, sdk.AuthConsumerSigninRequest) (sdk.AuthDriverUserInfo, error) {
+	return sdk.AuthDriverUserInfo{}, nil
+}
+
+func (d fakeRefreshDriver) RefreshUserGroups(_ context.Context, refreshToken string) ([]string, string, error) {
+	gs, ok := d.groups[refreshToken]
+	if !ok {
+		return nil, "", sdk.NewErrorFrom(sdk.ErrUnauthorized, "refresh token %s is revoked", refreshToken)
+	}
+	return gs, refreshToken + "-rotated", nil
+}
+
+func TestSyncAllUserGroupsWithRefreshToken(t *testing.T) {
+	db, _ := test.SetupPG(t, bootstrap.InitiliazeDB)
+
+	g1 := assets.InsertTestGroup(t, db, sdk.RandomString(10))
+	u, _ := assets.InsertLambdaUser(t, db)
+	mappings := authentication.GroupMappings{{ExternalGroup: "developers", GroupName: g1.Name}}
+
+	c, err := authentication.NewConsumerExternal(context.TODO(), db, u.ID, sdk.ConsumerOIDC, sdk.AuthDriverUserInfo{Username: u.Username})
+	require.NoError(t, err)
+	token := sdk.RandomString(20)
+	require.NoError(t, authentication.SetConsumerRefreshToken(context.TODO(), db, c.ID, token))
+
+	driver := fakeRefreshDriver{groups: map[string][]string{token: {"developers"}}}
+
+	// The groups given by the refresh token are synchronised and the rotated token is stored
+	require.NoError(t, authentication.SyncAllUserGroups(context.TODO(), db.DbMap, driver, mappings))
+	gs, err := group.LoadAllByUserID(context.TODO(), db, u.ID)
+	require.NoError(t, err)
+	assert.Contains(t, gs.ToIDs(), g1.ID)
+	newToken, err := authentication.LoadConsumerRefreshTokenWithDecryption(context.TODO(), db, c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, token+"-rotated", newToken)
+
+	// The rotated token is revoked so the user is removed from the mapped groups and the token is deleted
+	require.NoError(t, authentication.SyncAllUserGroups(context.TODO(), db.DbMap, driver, mappings))
+	gs, err = group.LoadAllByUserID(context.TODO(), db, u.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, gs.ToIDs(), g1.ID)
+	_, err = authentication.LoadConsumerRefreshTokenWithDecryption(context.TODO(), db, c.ID)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -16,7 +17,7 @@ import (
 	"github.com/rockbears/log"
 )
 
-var _ sdk.AuthDriver = new(AuthDriver)
+var _ sdk.AuthDriverWithUserGroups = new(AuthDriver)
 
 const errUserNotFound = "ldap::user not found"
 
@@ -24,6 +25,7 @@ type AuthDriver struct {
 	signupDisabled bool
 	conf           Config
 	conn           *ldap.Conn
+	mutex          *sync.Mutex
 }
 
 // Config handles all config to connect to the LDAP.
@@ -37,6 +39,10 @@ type Config struct {
 	UserFullname    string // {{.givenName}} {{.sn}}
 	ManagerDN       string // cn=admin,dc=ejnserver,dc=fr
 	ManagerPassword string // SECRET_PASSWORD_MANAGER
+	// Groups of the users are only retrieved if one of the following is set
+	GroupAttribute  string // memberOf
+	GroupSearchBase string // ou=groups
+	GroupSearch     string // (&(objectClass=groupOfNames)(member={0}))
 }
 
 // NewDriver returns a new ldap auth driver.
@@ -44,6 +50,7 @@ func NewDriver(ctx context.Context, signupDisabled bool, cfg Config) (sdk.AuthDr
 	var d = AuthDriver{
 		signupDisabled: signupDisabled,
 		conf:           cfg,
+		mutex:          new(sync.Mutex),
 	}
 
 	if err := d.openLDAP(ctx, cfg); err != nil {
@@ -79,11 +86,14 @@ func (d AuthDriver) GetUserInfo(ctx context.Context, req sdk.AuthConsumerSigninR
 	var bind = req["bind"]
 	var password = req["password"]
 
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	if err := d.bind(ctx, bind, password); err != nil {
 		return userInfo, sdk.NewError(sdk.ErrUnauthorized, err)
 	}
 
-	entry, err := d.search(ctx, bind, "uid", "dn", "cn", "ou", "givenName", "sn", "mail", "memberOf")
+	entry, err := d.search(ctx, bind, d.userAttributes()...)
 	if err != nil && err.Error() != errUserNotFound {
 		return userInfo, sdk.NewError(sdk.ErrUnauthorized, err)
 	}
@@ -114,9 +124,96 @@ func (d AuthDriver) GetUserInfo(ctx context.Context, req sdk.AuthConsumerSigninR
 	userInfo.ExternalID = entry[0].Attributes["uid"]
 	userInfo.Username = req["bind"]
 
+	userInfo.Groups, err = d.groups(ctx, entry[0])
+	if err != nil {
+		return userInfo, err
+	}
+
 	return userInfo, nil
 }
 
+// GetUserGroups returns the groups of the user for given bind term, the directory is searched with the manager account.
+// If the user no longer exists, an empty list is returned.
+func (d AuthDriver) GetUserGroups(ctx context.Context, username string) ([]string, error) {
+	// Without manager, the connection is bound with the last user that signed in
+	if d.conf.ManagerDN == "" {
+		return nil, sdk.WithStack(errors.New("a manager DN is required to search the groups of a user"))
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if err := d.conn.Bind(d.conf.ManagerDN, d.conf.ManagerPassword); err != nil {
+		if !shoudRetry(ctx, err) {
+			return nil, sdk.WithStack(err)
+		}
+		// openLDAP binds the manager after reconnecting
+		if err := d.openLDAP(ctx, d.conf); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := d.search(ctx, username, d.userAttributes()...)
+	if err != nil {
+		if err.Error() == errUserNotFound {
+			return nil, nil
+		}
+		return nil, sdk.WithStack(err)
+	}
+	if len(entry) > 1 {
+		return nil, sdk.WithStack(fmt.Errorf("LDAP Search error multiple values"))
+	}
+
+	return d.groups(ctx, entry[0])
+}
+
+func (d AuthDriver) userAttributes() []string {
+	attrs := []string{"uid", "dn", "cn", "ou", "givenName", "sn", "mail", "memberOf"}
+	if d.conf.GroupAttribute != "" && !sdk.IsInArray(d.conf.GroupAttribute, attrs) {
+		attrs = append(attrs, d.conf.GroupAttribute)
+	}
+	return attrs
+}
+
+// groups returns the names of the groups of given user, from the group search if set or from the group attribute of the user.
+func (d *AuthDriver) groups(ctx context.Context, user Entry) ([]string, error) {
+	var dns []string
+	if d.conf.GroupSearch != "" {
+		filter := strings.Replace(d.conf.GroupSearch, "{0}", ldap.EscapeFilter(user.DN), -1)
+		baseDN := d.conf.RootDN
+		if d.conf.GroupSearchBase != "" {
+			baseDN = d.conf.GroupSearchBase + "," + d.conf.RootDN
+		}
+		log.Debug(ctx, "LDAP> Search groups %s", filter)
+		searchRequest := ldap.NewSearchRequest(
+			baseDN,
+			ldap.ScopeWholeSubtree,
+			ldap.NeverDerefAliases,
+			0,
+			0,
+			false,
+			filter,
+			[]string{"dn"},
+			nil,
+		)
+		sr, err := d.conn.Search(searchRequest)
+		if err != nil {
+			return nil, sdk.WrapError(err, "unable to search groups for %s", user.DN)
+		}
+		for _, e := range sr.Entries {
+			dns = append(dns, e.DN)
+		}
+	} else if d.conf.GroupAttribute != "" {
+		dns = user.Values[d.conf.GroupAttribute]
+	}
+
+	groups := make([]string, 0, len(dns))
+	for _, dn := range dns {
+		groups = append(groups, groupName(dn))
+	}
+	return groups, nil
+}
+
 func (d *AuthDriver) openLDAP(ctx context.Context, conf Config) error {
 	if d.conn != nil {
 		d.conn.Close()
@@ -232,10 +329,12 @@ func (d *AuthDriver) search(ctx context.Context, term string, attributes ...stri
 		entry := Entry{
 			DN:         e.DN,
 			Attributes: make(map[string]string),
+			Values:     make(map[string][]string),
 		}
 
 		for _, a := range attributes {
 			entry.Attributes[a] = e.GetAttributeValue(a)
+			entry.Values[a] = e.GetAttributeValues(a)
 		}
 		entries = append(entries, entry)
 	}
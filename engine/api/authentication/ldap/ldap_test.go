@@ -3,6 +3,7 @@ package ldap
 import (
 	"context"
 	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/rockbears/log"
@@ -63,3 +64,16 @@ func TestGetUserInfo(t *testing.T) {
 	require.NotEmpty(t, info.Fullname, "Fullname")
 	require.NotEmpty(t, info.ExternalID, "ExternalID")
 }
+
+func TestGroupName(t *testing.T) {
+	require.Equal(t, "developers", groupName("cn=developers,ou=groups,dc=myorganization,dc=com"))
+	require.Equal(t, "ops, europe", groupName(`CN=ops\, europe,OU=groups,DC=myorganization,DC=com`))
+	require.Equal(t, "developers", groupName("developers"))
+}
+
+func TestGetUserGroupsWithoutManager(t *testing.T) {
+	// The search must not be done on a connection bound by a user
+	d := AuthDriver{conf: Config{GroupAttribute: "memberOf"}, mutex: new(sync.Mutex)}
+	_, err := d.GetUserGroups(context.TODO(), "john.doe")
+	require.Error(t, err)
+}
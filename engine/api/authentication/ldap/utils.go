@@ -26,4 +26,14 @@ func shoudRetry(ctx context.Context, err error) bool {
 type Entry struct {
 	DN         string
 	Attributes map[string]string
+	Values     map[string][]string
+}
+
+// groupName returns the value of the first attribute of a group DN, ex: developers for cn=developers,ou=groups,dc=myorganization,dc=com.
+func groupName(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 || len(parsed.RDNs[0].Attributes) == 0 {
+		return dn
+	}
+	return parsed.RDNs[0].Attributes[0].Value
 }
@@ -16,9 +16,10 @@ import (
 
 var _ sdk.AuthDriverWithRedirect = (*authDriver)(nil)
 var _ sdk.AuthDriverWithSigninStateToken = (*authDriver)(nil)
+var _ sdk.AuthDriverWithRefreshedUserGroups = (*authDriver)(nil)
 
-// NewDriver returns a new OIDC auth driver for given config.
-func NewDriver(signupDisabled bool, cdsURL, url, clientID, clientSecret string) (sdk.AuthDriver, error) {
+// NewDriver returns a new OIDC auth driver for given config, if groupsClaim is set the user groups are read from this claim.
+func NewDriver(signupDisabled bool, cdsURL, url, clientID, clientSecret, groupsClaim string) (sdk.AuthDriver, error) {
 	provider, err := oidc.NewProvider(context.Background(), url)
 	if err != nil {
 		return nil, sdk.WrapError(err, "failed to initialize OIDC driver")
@@ -33,6 +34,10 @@ func NewDriver(signupDisabled bool, cdsURL, url, clientID, clientSecret string)
 		// "openid" is a required scope for OpenID Connect flows.
 		Scopes: []string{oidc.ScopeOpenID, "profile", "email"},
 	}
+	// A refresh token is needed to read the groups of the user after its signin
+	if groupsClaim != "" {
+		oauth2Config.Scopes = append(oauth2Config.Scopes, oidc.ScopeOfflineAccess)
+	}
 	oidcConfig := &oidc.Config{
 		ClientID: clientID,
 	}
@@ -41,6 +46,7 @@ func NewDriver(signupDisabled bool, cdsURL, url, clientID, clientSecret string)
 	return &authDriver{
 		signupDisabled: signupDisabled,
 		cdsURL:         cdsURL,
+		groupsClaim:    groupsClaim,
 		OAuth2Config:   oauth2Config,
 		Verifier:       verifier,
 	}, nil
@@ -49,6 +55,7 @@ func NewDriver(signupDisabled bool, cdsURL, url, clientID, clientSecret string)
 type authDriver struct {
 	signupDisabled bool
 	cdsURL         string
+	groupsClaim    string
 	OAuth2Config   oauth2.Config
 	Verifier       *oidc.IDTokenVerifier
 }
@@ -136,5 +143,64 @@ func (d authDriver) GetUserInfo(ctx context.Context, req sdk.AuthConsumerSigninR
 		return info, sdk.WithStack(errors.New("missing user's email in OIDC token claim"))
 	}
 
+	if d.groupsClaim != "" {
+		info.Groups = claimGroups(tokenClaim[d.groupsClaim])
+		info.RefreshToken = oauth2Token.RefreshToken
+	}
+
 	return info, nil
 }
+
+// RefreshUserGroups returns the groups of the user from the ID token given for a refresh token. If the refresh token
+// was revoked or expired, an unauthorized error is returned.
+func (d authDriver) RefreshUserGroups(ctx context.Context, refreshToken string) ([]string, string, error) {
+	if d.groupsClaim == "" {
+		return nil, "", sdk.WithStack(errors.New("OIDC groups claim is not configured"))
+	}
+
+	ctx2 := context.WithValue(context.Background(), oauth2.HTTPClient, http.DefaultClient)
+	oauth2Token, err := d.OAuth2Config.TokenSource(ctx2, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		if _, ok := err.(*oauth2.RetrieveError); ok {
+			return nil, "", sdk.NewError(sdk.ErrUnauthorized, err)
+		}
+		return nil, "", sdk.WrapError(err, "failed to refresh token")
+	}
+	rawIDToken, ok := oauth2Token.Extra("id_token").(string)
+	if !ok {
+		return nil, "", sdk.WithStack(fmt.Errorf("no id_token field in oauth2 token"))
+	}
+	idToken, err := d.Verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, "", sdk.WrapError(err, "failed to verify ID Token")
+	}
+	tokenClaim := make(map[string]interface{})
+	if err := idToken.Claims(&tokenClaim); err != nil {
+		return nil, "", sdk.WrapError(err, "cannot unmarshal OIDC claim")
+	}
+
+	// The provider can keep the same refresh token
+	newRefreshToken := oauth2Token.RefreshToken
+	if newRefreshToken == "" {
+		newRefreshToken = refreshToken
+	}
+
+	return claimGroups(tokenClaim[d.groupsClaim]), newRefreshToken, nil
+}
+
+// claimGroups returns group names from a claim value that can be a list or a single string.
+func claimGroups(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		groups := make([]string, 0, len(v))
+		for i := range v {
+			if s, ok := v[i].(string); ok {
+				groups = append(groups, s)
+			}
+		}
+		return groups
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	oidc "github.com/coreos/go-oidc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	jose "gopkg.in/square/go-jose.v2"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestClaimGroups(t *testing.T) {
+	assert.Equal(t, []string{"developers", "ops"}, claimGroups([]interface{}{"developers", 12, "ops"}))
+	assert.Equal(t, []string{"developers"}, claimGroups("developers"))
+	assert.Nil(t, claimGroups(nil))
+}
+
+type testKeySet struct {
+	key *rsa.PublicKey
+}
+
+func (k testKeySet) VerifySignature(ctx context.Context, jwt string) ([]byte, error) {
+	jws, err := jose.ParseSigned(jwt)
+	if err != nil {
+		return nil, err
+	}
+	return jws.Verify(k.key)
+}
+
+func TestRefreshUserGroups(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, nil)
+	require.NoError(t, err)
+
+	var issuer string
+	groups := []string{"developers", "ops"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		if r.Form.Get("refresh_token") != "valid-refresh-token" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		claims, _ := json.Marshal(map[string]interface{}{
+			"iss":    issuer,
+			"aud":    "cds",
+			"sub":    "john.doe",
+			"exp":    time.Now().Add(time.Minute).Unix(),
+			"iat":    time.Now().Unix(),
+			"groups": groups,
+		})
+		jws, err := signer.Sign(claims)
+		require.NoError(t, err)
+		idToken, err := jws.CompactSerialize()
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   60,
+			"id_token":     idToken,
+		})
+	}))
+	defer srv.Close()
+	issuer = srv.URL
+
+	d := authDriver{
+		groupsClaim: "groups",
+		OAuth2Config: oauth2.Config{
+			ClientID: "cds",
+			Endpoint: oauth2.Endpoint{TokenURL: srv.URL + "/token"},
+		},
+		Verifier: oidc.NewVerifier(issuer, testKeySet{key: &key.PublicKey}, &oidc.Config{ClientID: "cds"}),
+	}
+
+	gs, newRefreshToken, err := d.RefreshUserGroups(context.TODO(), "valid-refresh-token")
+	require.NoError(t, err)
+	assert.Equal(t, groups, gs)
+	assert.Equal(t, "valid-refresh-token", newRefreshToken, "the refresh token should be kept if the provider doesn't give a new one")
+
+	// A revoked refresh token is an unauthorized error
+	_, _, err = d.RefreshUserGroups(context.TODO(), "revoked-refresh-token")
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrUnauthorized))
+}
@@ -16,6 +16,8 @@ const (
 	ParamRunID      = "runid"
 	ParamProjectKey = "projectkey"
 	ParamCacheTag   = "cachetag"
+	// ParamCacheTagPrefix is used to get the most recent worker cache with a tag starting with given value
+	ParamCacheTagPrefix = "cachetagprefix"
 )
 
 func ListItems(ctx context.Context, db gorp.SqlExecutor, itemtype sdk.CDNItemType, params map[string]string) (sdk.CDNItemLinks, error) {
@@ -328,7 +328,7 @@ func (api *API) updateAsCodeEnvironmentHandler() service.Handler {
 			Environments: []exportentities.Environment{envExported},
 		}
 
-		ope, err := operation.PushOperationUpdate(ctx, tx, api.Cache, *proj, wp, rootApp.VCSServer, rootApp.RepositoryFullname, branch, message, rootApp.RepositoryStrategy, u)
+		ope, err := operation.PushOperationUpdate(ctx, tx, api.Cache, *proj, wp, rootApp.VCSServer, rootApp.RepositoryFullname, rootApp.FromRepository, branch, message, rootApp.RepositoryStrategy, u)
 		if err != nil {
 			return err
 		}
@@ -381,8 +381,9 @@ func (api *API) updateEnvironmentHandler() service.Handler {
 			return err
 		}
 
-		oldEnv := env
+		oldEnv := *env
 		env.Name = envPost.Name
+		env.Protection = envPost.Protection
 
 		tx, errBegin := api.mustDB().Begin()
 		if errBegin != nil {
@@ -398,7 +399,7 @@ func (api *API) updateEnvironmentHandler() service.Handler {
 			return sdk.WithStack(err)
 		}
 
-		event.PublishEnvironmentUpdate(ctx, p.Key, *env, *oldEnv, getAPIConsumer(ctx))
+		event.PublishEnvironmentUpdate(ctx, p.Key, *env, oldEnv, getAPIConsumer(ctx))
 
 		var errEnvs error
 		p.Environments, errEnvs = environment.LoadEnvironments(api.mustDB(), p.Key)
@@ -410,6 +411,68 @@ func (api *API) updateEnvironmentHandler() service.Handler {
 	}
 }
 
+func (api *API) getEnvironmentProtectionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars[permProjectKey]
+		environmentName := vars["environmentName"]
+
+		env, err := environment.LoadEnvironmentByName(api.mustDB(), projectKey, environmentName)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load environment %s", environmentName)
+		}
+
+		var protection sdk.EnvironmentProtection
+		if env.Protection != nil {
+			protection = *env.Protection
+		}
+		return service.WriteJSON(w, protection, http.StatusOK)
+	}
+}
+
+// putEnvironmentProtectionHandler sets the protection rules of an environment. Protection is never read from
+// environment files so this is also the way to protect an as code environment.
+func (api *API) putEnvironmentProtectionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars[permProjectKey]
+		environmentName := vars["environmentName"]
+
+		var protection sdk.EnvironmentProtection
+		if err := service.UnmarshalBody(r, &protection); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		env, err := environment.LoadEnvironmentByName(tx, projectKey, environmentName)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load environment %s", environmentName)
+		}
+		oldEnv := *env
+
+		var newProtection *sdk.EnvironmentProtection
+		if !protection.IsEmpty() {
+			newProtection = &protection
+		}
+		if err := environment.UpdateProtection(tx, env, newProtection); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		event.PublishEnvironmentUpdate(ctx, projectKey, *env, oldEnv, getAPIConsumer(ctx))
+
+		return service.WriteJSON(w, protection, http.StatusOK)
+	}
+}
+
 func (api *API) cloneEnvironmentHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -452,6 +515,7 @@ func (api *API) cloneEnvironmentHandler() service.Handler {
 			ProjectID:  p.ID,
 			ProjectKey: p.Key,
 			Variables:  variables,
+			Protection: env.Protection,
 		}
 
 		tx, err := api.mustDB().Begin()
@@ -17,7 +17,7 @@ func LoadAllByIDs(db gorp.SqlExecutor, ids []int64) ([]sdk.Environment, error) {
 
 	query := `
     SELECT environment.id, environment.name, environment.project_id, environment.created,
-      environment.last_modified, environment.from_repository, project.projectkey
+      environment.last_modified, environment.from_repository, environment.protection, project.projectkey
 		FROM environment
 		JOIN project ON project.id = environment.project_id
 		WHERE environment.id = ANY($1)
@@ -35,7 +35,7 @@ func LoadAllByIDs(db gorp.SqlExecutor, ids []int64) ([]sdk.Environment, error) {
 	for rows.Next() {
 		var env sdk.Environment
 		if err := rows.Scan(&env.ID, &env.Name, &env.ProjectID, &env.Created,
-			&env.LastModified, &env.FromRepository, &env.ProjectKey); err != nil {
+			&env.LastModified, &env.FromRepository, &env.Protection, &env.ProjectKey); err != nil {
 			return envs, sdk.WithStack(err)
 		}
 		envs = append(envs, env)
@@ -56,7 +56,7 @@ func LoadEnvironments(db gorp.SqlExecutor, projectKey string) ([]sdk.Environment
 
 	query := `
     SELECT environment.id, environment.name, environment.project_id, environment.created,
-      environment.last_modified, environment.from_repository, project.projectkey
+      environment.last_modified, environment.from_repository, environment.protection, project.projectkey
 		FROM environment
 		JOIN project ON project.id = environment.project_id
 		WHERE project.projectKey = $1
@@ -74,7 +74,7 @@ func LoadEnvironments(db gorp.SqlExecutor, projectKey string) ([]sdk.Environment
 	for rows.Next() {
 		var env sdk.Environment
 		if err := rows.Scan(&env.ID, &env.Name, &env.ProjectID, &env.Created,
-			&env.LastModified, &env.FromRepository, &env.ProjectKey); err != nil {
+			&env.LastModified, &env.FromRepository, &env.Protection, &env.ProjectKey); err != nil {
 			return envs, sdk.WithStack(err)
 		}
 		envs = append(envs, env)
@@ -111,13 +111,13 @@ func LoadEnvironmentByID(db gorp.SqlExecutor, ID int64) (*sdk.Environment, error
 	var env sdk.Environment
 	query := `
     SELECT environment.id, environment.name, environment.project_id, environment.created,
-      environment.last_modified, environment.from_repository, project.projectkey
+      environment.last_modified, environment.from_repository, environment.protection, project.projectkey
     FROM environment
     JOIN project ON project.id = environment.project_id
     WHERE environment.id = $1
   `
 	if err := db.QueryRow(query, ID).Scan(&env.ID, &env.Name, &env.ProjectID, &env.Created,
-		&env.LastModified, &env.FromRepository, &env.ProjectKey); err != nil {
+		&env.LastModified, &env.FromRepository, &env.Protection, &env.ProjectKey); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.WithStack(sdk.ErrEnvironmentNotFound)
 		}
@@ -126,18 +126,30 @@ func LoadEnvironmentByID(db gorp.SqlExecutor, ID int64) (*sdk.Environment, error
 	return &env, loadDependencies(db, &env)
 }
 
+// LoadProtectionByID loads the protection rules of the given environment, nil is returned if there is no rule.
+func LoadProtectionByID(db gorp.SqlExecutor, ID int64) (*sdk.EnvironmentProtection, error) {
+	var protection *sdk.EnvironmentProtection
+	if err := db.QueryRow("SELECT protection FROM environment WHERE id = $1", ID).Scan(&protection); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.WithStack(sdk.ErrEnvironmentNotFound)
+		}
+		return nil, sdk.WithStack(err)
+	}
+	return protection, nil
+}
+
 // LoadEnvironmentByName load the given environment
 func LoadEnvironmentByName(db gorp.SqlExecutor, projectKey, envName string) (*sdk.Environment, error) {
 	var env sdk.Environment
 	query := `
     SELECT environment.id, environment.name, environment.project_id, environment.created,
-      environment.last_modified, environment.from_repository, project.projectkey
+      environment.last_modified, environment.from_repository, environment.protection, project.projectkey
     FROM environment
     JOIN project ON project.id = environment.project_id
     WHERE project.projectKey = $1 AND environment.name = $2
   `
 	if err := db.QueryRow(query, projectKey, envName).Scan(&env.ID, &env.Name, &env.ProjectID, &env.Created,
-		&env.LastModified, &env.FromRepository, &env.ProjectKey); err != nil {
+		&env.LastModified, &env.FromRepository, &env.Protection, &env.ProjectKey); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.WithData(sdk.ErrEnvironmentNotFound, envName)
 		}
@@ -149,7 +161,9 @@ func LoadEnvironmentByName(db gorp.SqlExecutor, projectKey, envName string) (*sd
 // LoadByWorkflowID loads environments from database for a given workflow id
 func LoadByWorkflowID(db gorp.SqlExecutor, workflowID int64) ([]sdk.Environment, error) {
 	envs := []sdk.Environment{}
-	query := `SELECT DISTINCT environment.* FROM environment
+	query := `SELECT DISTINCT environment.id, environment.name, environment.project_id, environment.created,
+	environment.last_modified, environment.from_repository
+	FROM environment
 	JOIN w_node_context ON w_node_context.environment_id = environment.id
 	JOIN w_node ON w_node.id = w_node_context.node_id
 	JOIN workflow ON workflow.id = w_node.workflow_id
@@ -197,14 +211,20 @@ func loadDependencies(db gorp.SqlExecutor, env *sdk.Environment) error {
 
 // InsertEnvironment Insert new environment
 func InsertEnvironment(db gorp.SqlExecutor, env *sdk.Environment) error {
-	query := `INSERT INTO environment (name, project_id, from_repository) VALUES($1, $2, $3) RETURNING id, created, last_modified`
+	query := `INSERT INTO environment (name, project_id, from_repository, protection) VALUES($1, $2, $3, $4) RETURNING id, created, last_modified`
 
 	rx := sdk.NamePatternRegex
 	if !rx.MatchString(env.Name) {
 		return sdk.NewErrorFrom(sdk.ErrInvalidName, "environment name should match pattern %s", sdk.NamePattern)
 	}
 
-	err := db.QueryRow(query, env.Name, env.ProjectID, env.FromRepository).Scan(&env.ID, &env.Created, &env.LastModified)
+	if env.Protection != nil {
+		if err := env.Protection.IsValid(); err != nil {
+			return err
+		}
+	}
+
+	err := db.QueryRow(query, env.Name, env.ProjectID, env.FromRepository, env.Protection).Scan(&env.ID, &env.Created, &env.LastModified)
 	if err != nil {
 		pqerr, ok := err.(*pq.Error)
 		if ok {
@@ -224,9 +244,32 @@ func UpdateEnvironment(db gorp.SqlExecutor, env *sdk.Environment) error {
 		return sdk.NewErrorFrom(sdk.ErrInvalidName, "environment name should match pattern %s", sdk.NamePattern)
 	}
 
+	if env.Protection != nil {
+		if err := env.Protection.IsValid(); err != nil {
+			return err
+		}
+	}
+
+	env.LastModified = time.Now()
+	query := `UPDATE environment SET name=$1, from_repository=$2, last_modified=$3, protection=$4 WHERE id=$5`
+	if _, err := db.Exec(query, env.Name, env.FromRepository, env.LastModified, env.Protection, env.ID); err != nil {
+		return sdk.WithStack(err)
+	}
+	return nil
+}
+
+// UpdateProtection updates the protection rules of the given environment, a nil protection removes all rules.
+func UpdateProtection(db gorp.SqlExecutor, env *sdk.Environment, protection *sdk.EnvironmentProtection) error {
+	if protection != nil {
+		if err := protection.IsValid(); err != nil {
+			return err
+		}
+	}
+
+	env.Protection = protection
 	env.LastModified = time.Now()
-	query := `UPDATE environment SET name=$1, from_repository=$2, last_modified=$3 WHERE id=$4`
-	if _, err := db.Exec(query, env.Name, env.FromRepository, env.LastModified, env.ID); err != nil {
+	query := `UPDATE environment SET last_modified=$1, protection=$2 WHERE id=$3`
+	if _, err := db.Exec(query, env.LastModified, env.Protection, env.ID); err != nil {
 		return sdk.WithStack(err)
 	}
 	return nil
@@ -68,6 +68,8 @@ func ParseAndImport(ctx context.Context, db gorpmapper.SqlExecutorWithTx, proj s
 	env.FromRepository = opts.FromRepository
 	if exist {
 		env.ID = oldEnv.ID
+		// Protection is not part of the environment file, keep the rules set from the API
+		env.Protection = oldEnv.Protection
 	}
 
 	envSecrets := make([]sdk.Variable, 0)
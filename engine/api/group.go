@@ -76,6 +76,25 @@ func (api *API) getGroupHandler() service.Handler {
 	}
 }
 
+func (api *API) getGroupAuditsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		name := vars["permGroupName"]
+
+		g, err := group.LoadByName(ctx, api.mustDB(), name)
+		if err != nil {
+			return err
+		}
+
+		audits, err := group.LoadAuditsByGroupID(ctx, api.mustDB(), g.ID, 100)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, audits, http.StatusOK)
+	}
+}
+
 func (api *API) postGroupHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		var newGroup sdk.Group
@@ -108,6 +127,7 @@ func (api *API) postGroupHandler() service.Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "cannot commit tx")
 		}
+		addAuditTarget(ctx, "group_name", newGroup.Name)
 
 		if err := group.LoadOptions.Default(ctx, api.mustDB(), &newGroup); err != nil {
 			return err
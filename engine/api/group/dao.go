@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-gorp/gorp"
+	"github.com/lib/pq"
 	"github.com/rockbears/log"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
@@ -98,6 +99,16 @@ func LoadAllByIDs(ctx context.Context, db gorp.SqlExecutor, ids []int64, opts ..
 	return getAll(ctx, db, query, opts...)
 }
 
+// LoadAllByNames returns all groups from database for given names.
+func LoadAllByNames(ctx context.Context, db gorp.SqlExecutor, names []string, opts ...LoadOptionFunc) (sdk.Groups, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM "group"
+    WHERE name = ANY($1)
+  `).Args(pq.StringArray(names))
+	return getAll(ctx, db, query, opts...)
+}
+
 // LoadAllByUserID returns all groups from database for given user id.
 func LoadAllByUserID(ctx context.Context, db gorp.SqlExecutor, userID string, opts ...LoadOptionFunc) (sdk.Groups, error) {
 	query := gorpmapping.NewQuery(`
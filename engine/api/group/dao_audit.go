@@ -0,0 +1,31 @@
+package group
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertAudit for group synchronisation in database.
+func InsertAudit(db gorp.SqlExecutor, a *sdk.AuditGroupSync) error {
+	return sdk.WrapError(gorpmapping.Insert(db, a), "unable to insert audit for group %s and user %s", a.GroupName, a.Username)
+}
+
+// LoadAuditsByGroupID returns latest synchronisation audits for a group.
+func LoadAuditsByGroupID(ctx context.Context, db gorp.SqlExecutor, groupID int64, limit int64) ([]sdk.AuditGroupSync, error) {
+	var as []sdk.AuditGroupSync
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM group_sync_audit
+    WHERE group_id = $1
+    ORDER BY created DESC
+    LIMIT $2
+  `).Args(groupID, limit)
+	if err := gorpmapping.GetAll(ctx, db, query, &as); err != nil {
+		return nil, sdk.WrapError(err, "cannot get group synchronisation audits")
+	}
+	return as, nil
+}
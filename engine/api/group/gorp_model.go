@@ -101,5 +101,6 @@ func init() {
 		gorpmapping.New(LinkGroupUser{}, "group_authentified_user", true, "id"),
 		gorpmapping.New(LinkGroupProject{}, "project_group", true, "id"),
 		gorpmapping.New(LinkWorkflowGroupPermission{}, "workflow_perm", false),
+		gorpmapping.New(sdk.AuditGroupSync{}, "group_sync_audit", true, "id"),
 	)
 }
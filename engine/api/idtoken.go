@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ovh/cds/engine/api/idtoken"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) getOpenIDConfigurationHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		i := idtoken.Get()
+		if i == nil {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		return service.WriteJSON(w, i.OpenIDConfiguration(), http.StatusOK)
+	}
+}
+
+func (api *API) getJWKSHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		i := idtoken.Get()
+		if i == nil {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		return service.WriteJSON(w, i.JWKS(), http.StatusOK)
+	}
+}
+
+func (api *API) postWorkflowJobIDTokenHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if isWorker := isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+
+		i := idtoken.Get()
+		if i == nil {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "identity tokens are not enabled on this CDS instance")
+		}
+
+		id, err := requestVarInt(r, "permJobID")
+		if err != nil {
+			return err
+		}
+
+		var req sdk.IDTokenRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+		if err := req.IsValid(); err != nil {
+			return err
+		}
+
+		nodeRun, err := workflow.LoadNodeRunByNodeJobID(api.mustDB(), id, workflow.LoadRunOptions{
+			DisableDetailledNodeRun: true,
+		})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load node run for job %d", id)
+		}
+		run, err := workflow.LoadRunByID(api.mustDB(), nodeRun.WorkflowRunID, workflow.LoadRunOptions{
+			DisableDetailledNodeRun: true,
+		})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow run %d", nodeRun.WorkflowRunID)
+		}
+
+		claims := sdk.IDTokenClaims{
+			ProjectKey:       run.Workflow.ProjectKey,
+			WorkflowName:     run.Workflow.Name,
+			WorkflowNodeName: nodeRun.WorkflowNodeName,
+			GitRepository:    nodeRun.VCSRepository,
+			GitRef:           sdk.IDTokenGitRef(nodeRun.VCSBranch, nodeRun.VCSTag),
+			GitHash:          nodeRun.VCSHash,
+			RunNumber:        nodeRun.Number,
+			JobID:            id,
+		}
+		if n := run.Workflow.WorkflowData.NodeByID(nodeRun.WorkflowNodeID); n != nil && n.Context != nil && n.Context.EnvironmentID != 0 {
+			claims.EnvironmentName = n.Context.EnvironmentName
+			if env, has := run.Workflow.Environments[n.Context.EnvironmentID]; has {
+				claims.EnvironmentName = env.Name
+			}
+		}
+
+		token, err := i.Sign(claims, sdk.IDTokenSubject(claims.ProjectKey, claims.WorkflowName, claims.WorkflowNodeName), req.Audience, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, token, http.StatusOK)
+	}
+}
@@ -0,0 +1,109 @@
+// Package idtoken issues OpenID Connect identity tokens for jobs, they can be exchanged by a job
+// for cloud or Vault credentials without storing static secrets in CDS.
+package idtoken
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	jose "gopkg.in/square/go-jose.v2"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var issuer *Issuer
+
+// Issuer signs identity tokens with its own RSA key.
+type Issuer struct {
+	url      string
+	key      *rsa.PrivateKey
+	keyID    string
+	duration time.Duration
+}
+
+// NewIssuer returns an issuer for given url and PEM encoded RSA private key.
+func NewIssuer(url string, k []byte, duration time.Duration) (*Issuer, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(k)
+	if err != nil {
+		return nil, sdk.WrapError(err, "invalid identity token signing key")
+	}
+	thumbprint, err := (&jose.JSONWebKey{Key: &key.PublicKey}).Thumbprint(crypto.SHA256)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return &Issuer{
+		url:      url,
+		key:      key,
+		keyID:    base64.RawURLEncoding.EncodeToString(thumbprint),
+		duration: duration,
+	}, nil
+}
+
+// Init the package with the issuer used by API handlers.
+func Init(url string, k []byte, duration time.Duration) error {
+	i, err := NewIssuer(url, k, duration)
+	if err != nil {
+		return err
+	}
+	issuer = i
+	return nil
+}
+
+// Get returns the issuer set by Init, or nil if identity tokens are disabled.
+func Get() *Issuer {
+	return issuer
+}
+
+// Sign returns a token for given claims, standard claims are set by the issuer.
+func (i *Issuer) Sign(claims sdk.IDTokenClaims, subject, audience string, now time.Time) (sdk.IDToken, error) {
+	expireAt := now.Add(i.duration)
+	claims.StandardClaims = jwt.StandardClaims{
+		Id:        sdk.UUID(),
+		Issuer:    i.url,
+		Subject:   subject,
+		Audience:  audience,
+		IssuedAt:  now.Unix(),
+		NotBefore: now.Unix(),
+		ExpiresAt: expireAt.Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.keyID
+	signed, err := token.SignedString(i.key)
+	if err != nil {
+		return sdk.IDToken{}, sdk.WithStack(err)
+	}
+	return sdk.IDToken{Token: signed, ExpireAt: expireAt}, nil
+}
+
+// JWKS returns the public key set used to verify tokens.
+func (i *Issuer) JWKS() jose.JSONWebKeySet {
+	return jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{
+			Key:       &i.key.PublicKey,
+			KeyID:     i.keyID,
+			Algorithm: string(jose.RS256),
+			Use:       "sig",
+		}},
+	}
+}
+
+// OpenIDConfiguration returns the discovery document for the issuer.
+func (i *Issuer) OpenIDConfiguration() sdk.OpenIDConfiguration {
+	return sdk.OpenIDConfiguration{
+		Issuer:                           i.url,
+		JWKSURI:                          i.url + "/.well-known/jwks.json",
+		ResponseTypesSupported:           []string{"id_token"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{string(jose.RS256)},
+		ScopesSupported:                  []string{"openid"},
+		ClaimsSupported: []string{
+			"sub", "aud", "exp", "iat", "iss", "jti", "nbf",
+			"project_key", "workflow_name", "workflow_node_name", "environment_name",
+			"git_repository", "git_ref", "git_hash", "run_number", "job_id",
+		},
+	}
+}
@@ -0,0 +1,64 @@
+package idtoken_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	jose "gopkg.in/square/go-jose.v2"
+
+	"github.com/ovh/cds/engine/api/idtoken"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/jws"
+)
+
+func TestIssuerSign(t *testing.T) {
+	key, err := jws.NewRandomRSAKey()
+	require.NoError(t, err)
+	pem, err := jws.ExportPrivateKey(key)
+	require.NoError(t, err)
+
+	i, err := idtoken.NewIssuer("https://cds.local/api", pem, 10*time.Minute)
+	require.NoError(t, err)
+
+	now := time.Now()
+	token, err := i.Sign(sdk.IDTokenClaims{
+		ProjectKey:       "PROJ",
+		WorkflowName:     "my-workflow",
+		WorkflowNodeName: "deploy",
+		EnvironmentName:  "production",
+		GitRef:           sdk.IDTokenGitRef("master", ""),
+		RunNumber:        12,
+		JobID:            42,
+	}, sdk.IDTokenSubject("PROJ", "my-workflow", "deploy"), "vault", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(10*time.Minute).Unix(), token.ExpireAt.Unix())
+
+	// The token should be verified with the published key set
+	btes, err := json.Marshal(i.JWKS())
+	require.NoError(t, err)
+	var set jose.JSONWebKeySet
+	require.NoError(t, json.Unmarshal(btes, &set))
+
+	var claims sdk.IDTokenClaims
+	parsed, err := jwt.ParseWithClaims(token.Token, &claims, func(token *jwt.Token) (interface{}, error) {
+		keys := set.Key(token.Header["kid"].(string))
+		require.Len(t, keys, 1)
+		return keys[0].Key, nil
+	})
+	require.NoError(t, err)
+	require.True(t, parsed.Valid)
+
+	assert.Equal(t, "https://cds.local/api", claims.Issuer)
+	assert.Equal(t, "project:PROJ:workflow:my-workflow:node:deploy", claims.Subject)
+	assert.Equal(t, "vault", claims.Audience)
+	assert.Equal(t, "production", claims.EnvironmentName)
+	assert.Equal(t, "refs/heads/master", claims.GitRef)
+	assert.Equal(t, int64(12), claims.RunNumber)
+
+	cfg := i.OpenIDConfiguration()
+	assert.Equal(t, "https://cds.local/api/.well-known/jwks.json", cfg.JWKSURI)
+}
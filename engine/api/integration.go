@@ -67,6 +67,7 @@ func (api *API) postIntegrationModelHandler() service.Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "unable to commit tx")
 		}
+		addAuditTarget(ctx, "name", m.Name)
 
 		if m.Public {
 			go propagatePublicIntegrationModel(ctx, api.mustDB(), api.Cache, *m, getAPIConsumer(ctx))
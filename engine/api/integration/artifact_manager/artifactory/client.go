@@ -73,6 +73,7 @@ func (c *Client) GetFileInfo(repoName string, filePath string) (sdk.FileInfo, er
 	}
 	if resp.Checksums != nil {
 		fi.Md5 = resp.Checksums.Md5
+		fi.Sha256 = resp.Checksums.Sha256
 	}
 	return fi, nil
 }
@@ -17,6 +17,8 @@ var (
 		sdk.OpenstackIntegration,
 		sdk.AWSIntegration,
 		sdk.ArtifactManagerIntegration,
+		sdk.VaultIntegration,
+		sdk.ChatWebhookIntegration,
 	}
 )
 
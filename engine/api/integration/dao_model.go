@@ -53,6 +53,8 @@ func LoadPublicModelsByTypeWithDecryption(db gorp.SqlExecutor, integrationType *
 			q += " AND integration_model.hook = true"
 		case sdk.IntegrationTypeDeployment:
 			q += " AND integration_model.deployment = true"
+		case sdk.IntegrationTypeSecret:
+			q += " AND integration_model.secret = true"
 		}
 	}
 
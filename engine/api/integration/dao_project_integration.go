@@ -163,6 +163,9 @@ func LoadIntegrationsByIDs(db gorp.SqlExecutor, ids []int64) ([]sdk.ProjectInteg
 
 // InsertIntegration inserts a integration
 func InsertIntegration(db gorpmapper.SqlExecutorWithTx, pp *sdk.ProjectIntegration) error {
+	if err := checkSecretIntegration(db, *pp); err != nil {
+		return err
+	}
 	oldConfig := pp.Config.Clone()
 	ppDb := dbProjectIntegration{ProjectIntegration: *pp}
 	if err := gorpmapping.InsertAndSign(context.Background(), db, &ppDb); err != nil {
@@ -176,6 +179,9 @@ func InsertIntegration(db gorpmapper.SqlExecutorWithTx, pp *sdk.ProjectIntegrati
 
 // UpdateIntegration Update a integration
 func UpdateIntegration(db gorpmapper.SqlExecutorWithTx, pp sdk.ProjectIntegration) error {
+	if err := checkSecretIntegration(db, pp); err != nil {
+		return err
+	}
 	var oldConfig *sdk.ProjectIntegration
 
 	givenConfig := pp.Config.Clone()
@@ -204,6 +210,26 @@ func UpdateIntegration(db gorpmapper.SqlExecutorWithTx, pp sdk.ProjectIntegratio
 	return nil
 }
 
+// checkSecretIntegration returns an error if the project already has another secret integration,
+// secret references don't name the integration to read from.
+func checkSecretIntegration(db gorp.SqlExecutor, pp sdk.ProjectIntegration) error {
+	count, err := db.SelectInt(`
+		SELECT COUNT(project_integration.id)
+		FROM project_integration
+		JOIN integration_model ON integration_model.id = project_integration.integration_model_id
+		WHERE project_integration.project_id = $1
+		AND project_integration.id <> $2
+		AND integration_model.secret = true
+		AND EXISTS (SELECT 1 FROM integration_model WHERE id = $3 AND secret = true)`, pp.ProjectID, pp.ID, pp.IntegrationModelID)
+	if err != nil {
+		return sdk.WrapError(err, "cannot count secret integrations of project %d", pp.ProjectID)
+	}
+	if count > 0 {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "only one secret integration is allowed per project")
+	}
+	return nil
+}
+
 // LoadAllIntegrationsForProjectsWithDecryption load all integrations for all given project, with decryption
 func LoadAllIntegrationsForProjectsWithDecryption(ctx context.Context, db gorp.SqlExecutor, projIDs []int64) (map[int64][]sdk.ProjectIntegration, error) {
 	return loadAllIntegrationsForProjects(ctx, db, projIDs, gorpmapping.GetOptions.WithDecryption)
@@ -1,6 +1,11 @@
 package keys
 
-import "github.com/ovh/cds/sdk"
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
 
 func GenerateKey(name string, t sdk.KeyType) (sdk.Key, error) {
 	switch t {
@@ -12,3 +17,56 @@ func GenerateKey(name string, t sdk.KeyType) (sdk.Key, error) {
 		return sdk.Key{}, sdk.WrapError(sdk.ErrUnknownKeyType, "unknown key of type: %s", t)
 	}
 }
+
+// GenerateKeyWithAlgorithm generates a new key, given algorithm is only used for ssh keys
+func GenerateKeyWithAlgorithm(name string, t sdk.KeyType, algo sdk.SSHKeyAlgorithm) (sdk.Key, error) {
+	if t == sdk.KeyTypeSSH {
+		return GenerateSSHKeyWithAlgorithm(name, algo)
+	}
+	return GenerateKey(name, t)
+}
+
+// ImportKey computes the public part and the key id of an existing private key
+func ImportKey(name string, t sdk.KeyType, private string) (sdk.Key, error) {
+	k := sdk.Key{
+		Name:    name,
+		Type:    t,
+		Private: private,
+	}
+
+	switch t {
+	case sdk.KeyTypePGP:
+		pgpEntity, err := GetOpenPGPEntity(strings.NewReader(private))
+		if err != nil {
+			return k, sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unable to read PGP entity from private key"))
+		}
+		pubReader, err := generatePGPPublicKey(pgpEntity)
+		if err != nil {
+			return k, sdk.WrapError(err, "unable to generate pgp public key")
+		}
+		pubBytes, err := ioutil.ReadAll(pubReader)
+		if err != nil {
+			return k, sdk.WrapError(err, "unable to read pgp public key")
+		}
+		k.Public = string(pubBytes)
+		k.KeyID = pgpEntity.PrimaryKey.KeyIdShortString()
+	case sdk.KeyTypeSSH:
+		privKey, err := getSSHPrivateKey(strings.NewReader(private))
+		if err != nil {
+			return k, err
+		}
+		pubReader, err := getSSHPublicKey(name, privKey)
+		if err != nil {
+			return k, sdk.WrapError(err, "unable to generate ssh public key")
+		}
+		pubBytes, err := ioutil.ReadAll(pubReader)
+		if err != nil {
+			return k, sdk.WrapError(err, "unable to read ssh public key")
+		}
+		k.Public = string(pubBytes)
+	default:
+		return k, sdk.WithStack(sdk.ErrUnknownKeyType)
+	}
+
+	return k, nil
+}
@@ -2,11 +2,11 @@ package keys
 
 import (
 	"bytes"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
-	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -44,63 +44,44 @@ func generateSSHKeyPair(keyname string) (pub io.Reader, priv io.Reader, err erro
 	return pubkey, privb, err
 }
 
-//getSSHPrivateKey returns the RSA private key
-func getSSHPrivateKey(r io.Reader) (*rsa.PrivateKey, error) {
+//getSSHPrivateKey returns the private key, it can be a RSA, ECDSA or ed25519 key
+func getSSHPrivateKey(r io.Reader) (interface{}, error) {
 	privBytes, errr := ioutil.ReadAll(r)
 	if errr != nil {
 		return nil, sdk.WrapError(errr, "getSSHPrivateKey> Unable to read private key")
 	}
 
-	privBlock, _ := pem.Decode(privBytes)
-	if privBlock == nil {
-		return nil, sdk.WrapError(errors.New("No Block found"), "getSSHPrivateKey> Unable to decode PEM private key")
-	}
-	if privBlock.Type != "RSA PRIVATE KEY" {
-		return nil, sdk.WrapError(errors.New("Unsupported Key type"), "getSSHPrivateKey> Unable to decode PEM private key")
-	}
-	//Parse the block
-	key, err := x509.ParsePKCS1PrivateKey(privBlock.Bytes)
+	key, err := ssh.ParseRawPrivateKey(privBytes)
 	if err != nil {
-		return nil, sdk.WrapError(err, "Unable to parse PKCS1 private key")
+		if _, ok := err.(*ssh.PassphraseMissingError); ok {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "private key protected by a passphrase are not supported")
+		}
+		return nil, sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unable to parse ssh private key"))
+	}
+
+	// ed25519 keys are returned as pointer by the ssh package but only the value implements crypto.Signer
+	if k, ok := key.(*ed25519.PrivateKey); ok {
+		return *k, nil
 	}
 
 	return key, nil
 }
 
 //getSSHPublicKey returns the public key from a private key
-func getSSHPublicKey(name string, privateKey *rsa.PrivateKey) (io.Reader, error) {
+func getSSHPublicKey(name string, privateKey interface{}) (io.Reader, error) {
 	// generate and write public key
-	pubkey, err := ssh.NewPublicKey(&privateKey.PublicKey)
+	signer, err := ssh.NewSignerFromKey(privateKey)
 	if err != nil {
-		return nil, err
+		return nil, sdk.WithStack(err)
 	}
 
-	pub := string(ssh.MarshalAuthorizedKey(pubkey))
+	pub := string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
 	// add label to public key
 	pub = fmt.Sprintf("%s %s@cds", pub, name)
 	return strings.NewReader(pub), nil
 }
 
-// GenerateSSHKey Generate a new ssh key
+// GenerateSSHKey Generate a new RSA ssh key
 func GenerateSSHKey(name string) (sdk.Key, error) {
-	k := sdk.Key{
-		Name: name,
-		Type: sdk.KeyTypeSSH,
-	}
-	pubR, privR, errGenerate := generateSSHKeyPair(name)
-	if errGenerate != nil {
-		return k, sdk.WrapError(errGenerate, "getSSHPublicKey> Cannot generate sshKey")
-	}
-	pub, errPub := ioutil.ReadAll(pubR)
-	if errPub != nil {
-		return k, sdk.WrapError(errPub, "getSSHPublicKey> Unable to read public key")
-	}
-
-	priv, errPriv := ioutil.ReadAll(privR)
-	if errPriv != nil {
-		return k, sdk.WrapError(errPriv, "getSSHPublicKey> Unable to read private key")
-	}
-	k.Public = string(pub)
-	k.Private = string(priv)
-	return k, nil
+	return GenerateSSHKeyWithAlgorithm(name, sdk.SSHKeyAlgorithmRSA)
 }
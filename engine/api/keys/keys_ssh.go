@@ -0,0 +1,174 @@
+package keys
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// generateED25519KeyPair generates an ed25519 private / public key, the private key is encoded in the OpenSSH format
+func generateED25519KeyPair(keyname string) (pub io.Reader, priv io.Reader, err error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, sdk.WithStack(err)
+	}
+
+	privBytes, err := marshalED25519PrivateKey(privateKey, keyname+"@cds")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var privb = new(bytes.Buffer)
+	if err := pem.Encode(privb, &pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: privBytes}); err != nil {
+		return nil, nil, sdk.WithStack(err)
+	}
+
+	pubkey, err := getSSHPublicKey(keyname, privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pubkey, privb, nil
+}
+
+// generateECDSAKeyPair generates an ECDSA private / public key on the P-256 curve
+func generateECDSAKeyPair(keyname string) (pub io.Reader, priv io.Reader, err error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, sdk.WithStack(err)
+	}
+
+	privBytes, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		return nil, nil, sdk.WithStack(err)
+	}
+
+	var privb = new(bytes.Buffer)
+	if err := pem.Encode(privb, &pem.Block{Type: "EC PRIVATE KEY", Bytes: privBytes}); err != nil {
+		return nil, nil, sdk.WithStack(err)
+	}
+
+	pubkey, err := getSSHPublicKey(keyname, privateKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pubkey, privb, nil
+}
+
+// marshalED25519PrivateKey encodes an unencrypted ed25519 private key in the OpenSSH format.
+// See https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.key
+func marshalED25519PrivateKey(privateKey ed25519.PrivateKey, comment string) ([]byte, error) {
+	publicKey, err := ssh.NewPublicKey(privateKey.Public())
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	var check [4]byte
+	if _, err := rand.Read(check[:]); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	checkInt := binary.BigEndian.Uint32(check[:])
+
+	pk := struct {
+		Check1  uint32
+		Check2  uint32
+		Keytype string
+		Pub     []byte
+		Priv    []byte
+		Comment string
+		Pad     []byte `ssh:"rest"`
+	}{
+		Check1:  checkInt,
+		Check2:  checkInt,
+		Keytype: ssh.KeyAlgoED25519,
+		Pub:     privateKey.Public().(ed25519.PublicKey),
+		Priv:    privateKey,
+		Comment: comment,
+	}
+
+	// The private key block is padded to the cipher block size, which is 8 for unencrypted keys
+	blockLen := len(ssh.Marshal(pk))
+	for i := 0; (blockLen+i)%8 != 0; i++ {
+		pk.Pad = append(pk.Pad, byte(i+1))
+	}
+
+	w := struct {
+		CipherName   string
+		KdfName      string
+		KdfOpts      string
+		NumKeys      uint32
+		PubKey       []byte
+		PrivKeyBlock []byte
+	}{
+		CipherName:   "none",
+		KdfName:      "none",
+		NumKeys:      1,
+		PubKey:       publicKey.Marshal(),
+		PrivKeyBlock: ssh.Marshal(pk),
+	}
+
+	return append([]byte("openssh-key-v1\x00"), ssh.Marshal(w)...), nil
+}
+
+// GenerateSSHKeyWithAlgorithm generates a new ssh key with given algorithm, default is RSA
+func GenerateSSHKeyWithAlgorithm(name string, algo sdk.SSHKeyAlgorithm) (sdk.Key, error) {
+	k := sdk.Key{
+		Name: name,
+		Type: sdk.KeyTypeSSH,
+	}
+
+	var pubR, privR io.Reader
+	var err error
+	switch algo {
+	case "", sdk.SSHKeyAlgorithmRSA:
+		pubR, privR, err = generateSSHKeyPair(name)
+	case sdk.SSHKeyAlgorithmED25519:
+		pubR, privR, err = generateED25519KeyPair(name)
+	case sdk.SSHKeyAlgorithmECDSA:
+		pubR, privR, err = generateECDSAKeyPair(name)
+	default:
+		return k, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unknown ssh key algorithm: %s", algo)
+	}
+	if err != nil {
+		return k, sdk.WrapError(err, "cannot generate %s ssh key", algo)
+	}
+
+	pub, err := ioutil.ReadAll(pubR)
+	if err != nil {
+		return k, sdk.WrapError(err, "unable to read public key")
+	}
+	priv, err := ioutil.ReadAll(privR)
+	if err != nil {
+		return k, sdk.WrapError(err, "unable to read private key")
+	}
+	k.Public = string(pub)
+	k.Private = string(priv)
+	return k, nil
+}
+
+// SSHSigner returns a signer from a ssh private key
+func SSHSigner(private string) (crypto.Signer, error) {
+	key, err := getSSHPrivateKey(strings.NewReader(private))
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported ssh key type %T", key)
+	}
+	return signer, nil
+}
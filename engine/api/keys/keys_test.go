@@ -7,8 +7,11 @@ import (
 	"testing"
 
 	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/sdk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestGenerateSSHKeyPair(t *testing.T) {
@@ -100,3 +103,32 @@ func TestGenerateGPGKeyPair(t *testing.T) {
 	t.Logf(string(pub2))
 	assert.Equal(t, string([]byte(k.Public)), string(pub2))
 }
+
+func TestGenerateSSHKeyWithAlgorithm(t *testing.T) {
+	for _, algo := range []sdk.SSHKeyAlgorithm{sdk.SSHKeyAlgorithmRSA, sdk.SSHKeyAlgorithmED25519, sdk.SSHKeyAlgorithmECDSA} {
+		k, err := GenerateSSHKeyWithAlgorithm("foo", algo)
+		require.NoError(t, err, algo)
+
+		signer, err := ssh.ParsePrivateKey([]byte(k.Private))
+		require.NoError(t, err, algo)
+		pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k.Public))
+		require.NoError(t, err, algo)
+		assert.Equal(t, pub.Marshal(), signer.PublicKey().Marshal(), algo)
+
+		imported, err := ImportKey("foo", sdk.KeyTypeSSH, k.Private)
+		require.NoError(t, err, algo)
+		assert.Equal(t, k.Public, imported.Public, algo)
+
+		cryptoSigner, err := SSHSigner(k.Private)
+		require.NoError(t, err, algo)
+		cryptoPub, err := ssh.NewPublicKey(cryptoSigner.Public())
+		require.NoError(t, err, algo)
+		assert.Equal(t, pub.Marshal(), cryptoPub.Marshal(), algo)
+	}
+
+	_, err := GenerateSSHKeyWithAlgorithm("foo", "dsa")
+	require.Error(t, err)
+
+	_, err = ImportKey("foo", sdk.KeyTypeSSH, "not a key")
+	require.Error(t, err)
+}
@@ -2,8 +2,6 @@ package keys
 
 import (
 	"context"
-	"io/ioutil"
-	"strings"
 
 	"github.com/go-gorp/gorp"
 	"github.com/rockbears/log"
@@ -24,43 +22,11 @@ func Parse(db gorp.SqlExecutor, projID int64, kname string, kval exportentities.
 		if err != nil {
 			return nil, sdk.WrapError(err, "Unable to decrypt secret")
 		}
-		k.Private = privateKey
-
-		switch k.Type {
-		//Compute PGP Keys
-		case sdk.KeyTypePGP:
-			pgpEntity, errPGPEntity := GetOpenPGPEntity(strings.NewReader(k.Private))
-			if errPGPEntity != nil {
-				return nil, sdk.WrapError(errPGPEntity, "keys.Parse> Unable to read PGP Entity from private key")
-			}
-			pubReader, errPub := generatePGPPublicKey(pgpEntity)
-			if errPub != nil {
-				return nil, sdk.WrapError(errPub, "keys.Parse> Unable to generate pgp public key")
-			}
-			pubBytes, errReadPub := ioutil.ReadAll(pubReader)
-			if errReadPub != nil {
-				return nil, sdk.WrapError(errReadPub, "keys.Parse> Unable to read pgp public key")
-			}
-			k.Public = string(pubBytes)
-			k.KeyID = pgpEntity.PrimaryKey.KeyIdShortString()
-		//Compute SSH Keys
-		case sdk.KeyTypeSSH:
-			privKey, errPrivKey := getSSHPrivateKey(strings.NewReader(privateKey))
-			if errPrivKey != nil {
-				return nil, sdk.WrapError(errPrivKey, "keys.Parse> Unable to read RSA private key")
-			}
-			pubReader, errPub := getSSHPublicKey(kname, privKey)
-			if errPub != nil {
-				return nil, sdk.WrapError(errPub, "keys.Parse> Unable to generate ssh public key")
-			}
-			pubBytes, errReadPub := ioutil.ReadAll(pubReader)
-			if errReadPub != nil {
-				return nil, sdk.WrapError(errReadPub, "keys.Parse> Unable to read ssh public key")
-			}
-			k.Public = string(pubBytes)
-		default:
-			return nil, sdk.ErrUnknownKeyType
+		kk, err := ImportKey(kname, k.Type, privateKey)
+		if err != nil {
+			return nil, sdk.WrapError(err, "keys.Parse> Unable to import key %s", kname)
 		}
+		k = &kk
 	} else if kval.Regen == nil || *kval.Regen == true {
 		ktemp, err := GenerateKey(kname, k.Type)
 		if err != nil {
@@ -0,0 +1,69 @@
+package migrate
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/sdk"
+)
+
+// ProvenanceKeys moves the provenance signing keys out of the project keys, so they are not given to the jobs anymore.
+func ProvenanceKeys(ctx context.Context, dbFunc func() *gorp.DbMap) error {
+	log.Info(ctx, "starting provenance keys migration")
+	defer log.Info(ctx, "ending provenance keys migration")
+
+	var keys []struct {
+		ProjectID  int64  `db:"project_id"`
+		ProjectKey string `db:"projectkey"`
+	}
+	if _, err := dbFunc().Select(&keys, `
+		SELECT project_key.project_id, project.projectkey
+		FROM project_key
+		JOIN project ON project.id = project_key.project_id
+		WHERE project_key.name = 'proj-provenance-' || lower(project.projectkey)`); err != nil {
+		return sdk.WrapError(err, "unable to load provenance keys")
+	}
+
+	for _, k := range keys {
+		if err := migrateProvenanceKey(ctx, dbFunc(), k.ProjectID, k.ProjectKey); err != nil {
+			ctx := sdk.ContextWithStacktrace(ctx, err)
+			log.Error(ctx, "unable to migrate provenance key of project %s: %v", k.ProjectKey, err)
+		}
+	}
+	return nil
+}
+
+func migrateProvenanceKey(ctx context.Context, db *gorp.DbMap, projectID int64, projectKey string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	keyName := sdk.GenerateProjectProvenanceKeyName(projectKey)
+	k, err := project.LoadKey(tx, projectID, keyName)
+	if err != nil {
+		return err
+	}
+	// The key could have been generated again after a previous migration
+	if _, err := project.LoadProvenanceKey(ctx, tx, projectID); err != nil {
+		if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+		if err := project.InsertProvenanceKey(ctx, tx, &sdk.ProjectProvenanceKey{
+			ProjectID: projectID,
+			Name:      k.Name,
+			Public:    k.Public,
+			Private:   k.Private,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := project.DeleteProjectKey(tx, projectID, keyName); err != nil {
+		return err
+	}
+	return sdk.WithStack(tx.Commit())
+}
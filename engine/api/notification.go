@@ -27,6 +27,24 @@ func (api *API) getUserNotificationTypeHandler() service.Handler {
 				SendToGroups: &sdk.False,
 				Template:     &sdk.UserNotificationTemplateJabber,
 			},
+			sdk.SlackUserNotification: {
+				OnSuccess: sdk.UserNotificationChange,
+				OnFailure: sdk.UserNotificationAlways,
+				OnStart:   &sdk.False,
+				Template:  &sdk.UserNotificationTemplateChat,
+			},
+			sdk.MattermostUserNotification: {
+				OnSuccess: sdk.UserNotificationChange,
+				OnFailure: sdk.UserNotificationAlways,
+				OnStart:   &sdk.False,
+				Template:  &sdk.UserNotificationTemplateChat,
+			},
+			sdk.TeamsUserNotification: {
+				OnSuccess: sdk.UserNotificationChange,
+				OnFailure: sdk.UserNotificationAlways,
+				OnStart:   &sdk.False,
+				Template:  &sdk.UserNotificationTemplateChat,
+			},
 			sdk.VCSUserNotification: {
 				Template: &sdk.UserNotificationTemplate{
 					Body: sdk.DefaultWorkflowNodeRunReport,
@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+)
+
+// SendApprovalRequest sends a mail to the members of the approval groups of a node run that is waiting for approval.
+func SendApprovalRequest(ctx context.Context, db gorp.SqlExecutor, projectKey, workflowName string, nr sdk.WorkflowNodeRun) {
+	if !nr.Approval.IsPending() {
+		return
+	}
+
+	groups, err := group.LoadAllByNames(ctx, db, append(append([]string{}, nr.Approval.Groups...), nr.Approval.EnvironmentGroups...))
+	if err != nil {
+		log.Error(ctx, "notification.SendApprovalRequest> unable to load approval groups: %v", err)
+		return
+	}
+	links, err := group.LoadLinksGroupUserForGroupIDs(ctx, db, groups.ToIDs())
+	if err != nil {
+		log.Error(ctx, "notification.SendApprovalRequest> unable to load approval groups members: %v", err)
+		return
+	}
+	userIDs := make([]string, 0, len(links))
+	for _, l := range links {
+		userIDs = append(userIDs, l.AuthentifiedUserID)
+	}
+	contacts, err := user.LoadContactsByUserIDs(ctx, db, userIDs)
+	if err != nil {
+		log.Error(ctx, "notification.SendApprovalRequest> unable to load approval groups members contacts: %v", err)
+		return
+	}
+
+	notif := sdk.EventNotif{
+		Subject: fmt.Sprintf("CDS %s/%s#%d.%d - approval required for %s", projectKey, workflowName, nr.Number, nr.SubNumber, nr.WorkflowNodeName),
+		Body: fmt.Sprintf("The pipeline %s of workflow %s/%s is waiting for %d approval(s).\nApprove or reject it from %s/project/%s/workflow/%s/run/%d or with the command: cdsctl workflow approve %s %s %d %s",
+			nr.WorkflowNodeName, projectKey, workflowName, nr.Approval.MinRequiredApprovals(), uiURL, projectKey, workflowName, nr.Number, projectKey, workflowName, nr.Number, nr.WorkflowNodeName),
+	}
+	for _, c := range contacts {
+		if c.Type == sdk.UserContactTypeEmail && !sdk.IsInArray(c.Value, nr.Approval.ForbiddenEmails) {
+			notif.Recipients = append(notif.Recipients, c.Value)
+		}
+	}
+	removeDuplicates(&notif.Recipients)
+
+	go sendMailNotif(ctx, notif)
+}
@@ -0,0 +1,353 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/sdk"
+)
+
+var chatHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// chatMessage is the content of a chat notification, it is formatted for each chat platform.
+type chatMessage struct {
+	Title      string
+	Text       string
+	URL        string
+	Status     string
+	Author     string
+	Branch     string
+	Commit     string
+	FailedJobs []chatLink
+	// Set if the node run is waiting for approval
+	ApprovalURL string
+}
+
+type chatLink struct {
+	Name string
+	URL  string
+}
+
+type chatFact struct {
+	Name  string
+	Value string
+}
+
+// newChatMessage returns the chat message for a node run, title and text are the interpolated notification template.
+func newChatMessage(notif sdk.EventNotif, params map[string]string, projectKey, workflowName string, nr sdk.WorkflowNodeRun) chatMessage {
+	nodeRunURL := fmt.Sprintf("%s/project/%s/workflow/%s/run/%d/node/%d?name=%s", uiURL, projectKey, workflowName, nr.Number, nr.ID, nr.WorkflowNodeName)
+	m := chatMessage{
+		Title:  notif.Subject,
+		Text:   notif.Body,
+		URL:    nodeRunURL,
+		Status: nr.Status,
+		Author: params[paramsAuthorName],
+		Branch: params["git.branch"],
+		Commit: params["git.hash"],
+	}
+	if len(m.Commit) > 8 {
+		m.Commit = m.Commit[:8]
+	}
+	if author := params["git.author"]; author != "" {
+		m.Author = author
+	}
+
+	for _, s := range nr.Stages {
+		for _, rj := range s.RunJobs {
+			if rj.Status != sdk.StatusFail {
+				continue
+			}
+			m.FailedJobs = append(m.FailedJobs, chatLink{
+				Name: rj.Job.Action.Name,
+				URL:  fmt.Sprintf("%s&stageId=%d&actionId=%d", nodeRunURL, s.ID, rj.Job.PipelineActionID),
+			})
+		}
+	}
+
+	if nr.Approval.IsPending() {
+		m.ApprovalURL = nodeRunURL
+	}
+	return m
+}
+
+func (m chatMessage) color() string {
+	switch m.Status {
+	case sdk.StatusSuccess:
+		return "#21BA45"
+	case sdk.StatusFail:
+		return "#FF4F60"
+	case sdk.StatusStopped:
+		return "#767676"
+	}
+	return "#4A90E2"
+}
+
+// markdown returns the text and the failed jobs of the message, links are formatted with given func.
+func (m chatMessage) markdown(link func(l chatLink) string) string {
+	var buf bytes.Buffer
+	if m.Text != "" {
+		buf.WriteString(m.Text + "\n")
+	}
+	if len(m.FailedJobs) > 0 {
+		buf.WriteString("Failed jobs:")
+		for _, j := range m.FailedJobs {
+			buf.WriteString(" " + link(j))
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+func (m chatMessage) facts() []chatFact {
+	var facts []chatFact
+	for _, f := range []chatFact{{"Status", m.Status}, {"Author", m.Author}, {"Branch", m.Branch}, {"Commit", m.Commit}} {
+		if f.Value != "" {
+			facts = append(facts, f)
+		}
+	}
+	return facts
+}
+
+// buttons returns the links displayed as buttons on platforms that support them in incoming webhooks.
+func (m chatMessage) buttons() []chatLink {
+	buttons := []chatLink{{Name: "Open in CDS", URL: m.URL}}
+	if m.ApprovalURL != "" {
+		buttons = append(buttons, chatLink{Name: "Approve or reject", URL: m.ApprovalURL})
+	}
+	return buttons
+}
+
+// slackPayload returns a Slack message using blocks, see https://api.slack.com/messaging/webhooks.
+func slackPayload(m chatMessage) interface{} {
+	slackLink := func(l chatLink) string { return fmt.Sprintf("<%s|%s>", l.URL, l.Name) }
+
+	type text struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	type element struct {
+		Type string `json:"type"`
+		Text text   `json:"text"`
+		URL  string `json:"url"`
+	}
+	type block struct {
+		Type     string    `json:"type"`
+		Text     *text     `json:"text,omitempty"`
+		Fields   []text    `json:"fields,omitempty"`
+		Elements []element `json:"elements,omitempty"`
+	}
+
+	blocks := []block{{
+		Type: "section",
+		Text: &text{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", slackLink(chatLink{Name: m.Title, URL: m.URL}), m.markdown(slackLink))},
+	}}
+	var fields []text
+	for _, f := range m.facts() {
+		fields = append(fields, text{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", f.Name, f.Value)})
+	}
+	if len(fields) > 0 {
+		blocks = append(blocks, block{Type: "section", Fields: fields})
+	}
+	var buttons []element
+	for _, b := range m.buttons() {
+		buttons = append(buttons, element{Type: "button", Text: text{Type: "plain_text", Text: b.Name}, URL: b.URL})
+	}
+	if len(buttons) > 0 {
+		blocks = append(blocks, block{Type: "actions", Elements: buttons})
+	}
+
+	return struct {
+		Text   string  `json:"text"`
+		Blocks []block `json:"blocks"`
+	}{
+		Text:   m.Title,
+		Blocks: blocks,
+	}
+}
+
+// mattermostPayload returns a Mattermost message with an attachment, see https://docs.mattermost.com/developer/message-attachments.html.
+// Buttons of incoming webhooks require an integration endpoint so the approval is added as a link.
+func mattermostPayload(m chatMessage) interface{} {
+	mdLink := func(l chatLink) string { return fmt.Sprintf("[%s](%s)", l.Name, l.URL) }
+
+	type field struct {
+		Short bool   `json:"short"`
+		Title string `json:"title"`
+		Value string `json:"value"`
+	}
+	type attachment struct {
+		Fallback  string  `json:"fallback"`
+		Color     string  `json:"color"`
+		Title     string  `json:"title"`
+		TitleLink string  `json:"title_link"`
+		Text      string  `json:"text"`
+		Fields    []field `json:"fields,omitempty"`
+	}
+
+	text := m.markdown(mdLink)
+	if m.ApprovalURL != "" {
+		text += mdLink(chatLink{Name: "Approve or reject", URL: m.ApprovalURL}) + "\n"
+	}
+	a := attachment{
+		Fallback:  m.Title,
+		Color:     m.color(),
+		Title:     m.Title,
+		TitleLink: m.URL,
+		Text:      text,
+	}
+	for _, f := range m.facts() {
+		a.Fields = append(a.Fields, field{Short: true, Title: f.Name, Value: f.Value})
+	}
+
+	return struct {
+		Attachments []attachment `json:"attachments"`
+	}{
+		Attachments: []attachment{a},
+	}
+}
+
+// teamsPayload returns a Microsoft Teams message card, see https://docs.microsoft.com/en-us/outlook/actionable-messages/message-card-reference.
+func teamsPayload(m chatMessage) interface{} {
+	type fact struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	type section struct {
+		Text  string `json:"text,omitempty"`
+		Facts []fact `json:"facts,omitempty"`
+	}
+	type target struct {
+		OS  string `json:"os"`
+		URI string `json:"uri"`
+	}
+	type action struct {
+		Type    string   `json:"@type"`
+		Name    string   `json:"name"`
+		Targets []target `json:"targets"`
+	}
+
+	s := section{Text: m.markdown(func(l chatLink) string { return fmt.Sprintf("[%s](%s)", l.Name, l.URL) })}
+	for _, f := range m.facts() {
+		s.Facts = append(s.Facts, fact{Name: f.Name, Value: f.Value})
+	}
+	var actions []action
+	for _, b := range m.buttons() {
+		actions = append(actions, action{Type: "OpenUri", Name: b.Name, Targets: []target{{OS: "default", URI: b.URL}}})
+	}
+
+	return struct {
+		Type            string    `json:"@type"`
+		Context         string    `json:"@context"`
+		ThemeColor      string    `json:"themeColor"`
+		Summary         string    `json:"summary"`
+		Title           string    `json:"title"`
+		Sections        []section `json:"sections"`
+		PotentialAction []action  `json:"potentialAction,omitempty"`
+	}{
+		Type:            "MessageCard",
+		Context:         "https://schema.org/extensions",
+		ThemeColor:      m.color()[1:],
+		Summary:         m.Title,
+		Title:           m.Title,
+		Sections:        []section{s},
+		PotentialAction: actions,
+	}
+}
+
+func chatPayload(notifType string, m chatMessage) (interface{}, error) {
+	switch notifType {
+	case sdk.SlackUserNotification:
+		return slackPayload(m), nil
+	case sdk.MattermostUserNotification:
+		return mattermostPayload(m), nil
+	case sdk.TeamsUserNotification:
+		return teamsPayload(m), nil
+	}
+	return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid chat notification type %s", notifType)
+}
+
+// postChatNotif posts the message on a chat incoming webhook.
+func postChatNotif(ctx context.Context, webhookURL, notifType string, m chatMessage) error {
+	payload, err := chatPayload(notifType, m)
+	if err != nil {
+		return err
+	}
+	btes, err := json.Marshal(payload)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(btes))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := chatHTTPClient.Do(req)
+	if err != nil {
+		return sdk.WrapError(err, "unable to post %s notification", notifType)
+	}
+	defer resp.Body.Close() // nolint
+	if resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return sdk.WithStack(fmt.Errorf("unable to post %s notification: HTTP %d %s", notifType, resp.StatusCode, string(body)))
+	}
+	return nil
+}
+
+// SendChatApprovalRequest posts on the chat notifications of a node run that is waiting for approval.
+func SendChatApprovalRequest(ctx context.Context, db gorp.SqlExecutor, projectKey, workflowName string, notifs []sdk.WorkflowNotification, nr sdk.WorkflowNodeRun) {
+	if !nr.Approval.IsPending() {
+		return
+	}
+	params := sdk.ParametersToMap(nr.BuildParameters)
+	for _, notif := range notifs {
+		if !sdk.IsChatUserNotification(notif.Type) || !sdk.IsInArray(nr.WorkflowNodeName, notif.SourceNodeRefs) {
+			continue
+		}
+		if !checkConditions(ctx, notif.Settings.Conditions, nr.BuildParameters) {
+			continue
+		}
+		webhookURL, err := chatWebhookURL(db, projectKey, notif.Integration)
+		if err != nil {
+			log.Error(ctx, "notification.SendChatApprovalRequest> %v", err)
+			continue
+		}
+		e := sdk.EventNotif{
+			Subject: fmt.Sprintf("%s/%s#%d.%d - approval required for %s", projectKey, workflowName, nr.Number, nr.SubNumber, nr.WorkflowNodeName),
+			Body:    fmt.Sprintf("The pipeline %s is waiting for %d approval(s)", nr.WorkflowNodeName, nr.Approval.MinRequiredApprovals()),
+		}
+		go sendChatNotif(ctx, webhookURL, notif.Type, newChatMessage(e, params, projectKey, workflowName, nr))
+	}
+}
+
+// chatWebhookURL returns the incoming webhook URL of a chat integration of the project.
+func chatWebhookURL(db gorp.SqlExecutor, projectKey, integrationName string) (string, error) {
+	pi, err := integration.LoadProjectIntegrationByNameWithClearPassword(db, projectKey, integrationName)
+	if err != nil {
+		return "", sdk.WrapError(err, "unable to load integration %s", integrationName)
+	}
+	if pi.Model.Name != sdk.ChatWebhookIntegrationModel {
+		return "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "integration %s is not a %s integration", integrationName, sdk.ChatWebhookIntegrationModel)
+	}
+	webhookURL := pi.Config[sdk.ChatWebhookConfigURL].Value
+	if webhookURL == "" {
+		return "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing webhook url on integration %s", integrationName)
+	}
+	return webhookURL, nil
+}
+
+// sendChatNotif posts the message on the webhook of the chat integration of the notification.
+func sendChatNotif(ctx context.Context, webhookURL, notifType string, m chatMessage) {
+	log.Info(ctx, "notification.sendChatNotif> Send %s notif '%s'", notifType, m.Title)
+	if err := postChatNotif(ctx, webhookURL, notifType, m); err != nil {
+		log.Error(ctx, "notification.sendChatNotif> %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestPostChatNotif(t *testing.T) {
+	Init("https://cds.local")
+
+	nr := sdk.WorkflowNodeRun{
+		ID:               12,
+		Number:           42,
+		WorkflowNodeName: "deploy",
+		Status:           sdk.StatusFail,
+		Stages: []sdk.Stage{{
+			ID: 1,
+			RunJobs: []sdk.WorkflowNodeJobRun{
+				{Status: sdk.StatusSuccess, Job: sdk.ExecutedJob{Job: sdk.Job{PipelineActionID: 2, Action: sdk.Action{Name: "lint"}}}},
+				{Status: sdk.StatusFail, Job: sdk.ExecutedJob{Job: sdk.Job{PipelineActionID: 3, Action: sdk.Action{Name: "test"}}}},
+			},
+		}},
+	}
+	params := map[string]string{
+		"git.author": "john.doe",
+		"git.branch": "master",
+		"git.hash":   "8c4d7b9f1f5cdb2b",
+	}
+	m := newChatMessage(sdk.EventNotif{Subject: "MYPROJ/my-workflow#42 deploy Fail", Body: "fix bug"}, params, "MYPROJ", "my-workflow", nr)
+	require.Equal(t, "https://cds.local/project/MYPROJ/workflow/my-workflow/run/42/node/12?name=deploy", m.URL)
+	require.Equal(t, []chatLink{{Name: "test", URL: m.URL + "&stageId=1&actionId=3"}}, m.FailedJobs)
+	require.Equal(t, "8c4d7b9f", m.Commit)
+	require.Equal(t, "john.doe", m.Author)
+
+	var received map[string]json.RawMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		btes, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = nil
+		require.NoError(t, json.Unmarshal(btes, &received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// Slack
+	require.NoError(t, postChatNotif(context.TODO(), srv.URL, sdk.SlackUserNotification, m))
+	var blocks []struct {
+		Type     string `json:"type"`
+		Elements []struct {
+			URL string `json:"url"`
+		} `json:"elements"`
+	}
+	require.NoError(t, json.Unmarshal(received["blocks"], &blocks))
+	require.Len(t, blocks, 3)
+	require.Equal(t, "actions", blocks[2].Type)
+	require.Len(t, blocks[2].Elements, 1)
+
+	// Mattermost
+	require.NoError(t, postChatNotif(context.TODO(), srv.URL, sdk.MattermostUserNotification, m))
+	var attachments []struct {
+		Color     string `json:"color"`
+		TitleLink string `json:"title_link"`
+		Text      string `json:"text"`
+	}
+	require.NoError(t, json.Unmarshal(received["attachments"], &attachments))
+	require.Len(t, attachments, 1)
+	require.Equal(t, "#FF4F60", attachments[0].Color)
+	require.Equal(t, m.URL, attachments[0].TitleLink)
+	require.Contains(t, attachments[0].Text, "[test]("+m.URL+"&stageId=1&actionId=3)")
+
+	// Teams, with an approval button
+	nr.Status = sdk.StatusWaiting
+	nr.Approval = &sdk.WorkflowNodeRunApproval{Status: sdk.ApprovalStatusPending}
+	m = newChatMessage(sdk.EventNotif{Subject: "approval required"}, params, "MYPROJ", "my-workflow", nr)
+	require.NoError(t, postChatNotif(context.TODO(), srv.URL, sdk.TeamsUserNotification, m))
+	require.Equal(t, `"MessageCard"`, string(received["@type"]))
+	var actions []struct {
+		Name string `json:"name"`
+	}
+	require.NoError(t, json.Unmarshal(received["potentialAction"], &actions))
+	require.Len(t, actions, 2)
+	require.Equal(t, "Approve or reject", actions[1].Name)
+
+	require.Error(t, postChatNotif(context.TODO(), srv.URL, sdk.JabberUserNotification, m))
+
+	// Errors from the webhook are returned
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("no_team"))
+	}))
+	defer failing.Close()
+	err := postChatNotif(context.TODO(), failing.URL, sdk.SlackUserNotification, m)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no_team")
+}
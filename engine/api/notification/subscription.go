@@ -0,0 +1,234 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/mail"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/sdk"
+)
+
+// SendUserSubscriptions notifies the users that subscribed to an ended workflow run, the event
+// is sent immediately or stored for the next digest of the subscription.
+func SendUserSubscriptions(ctx context.Context, db gorp.SqlExecutor, store cache.Store, wr sdk.WorkflowRun) {
+	if !sdk.StatusIsTerminated(wr.Status) {
+		return
+	}
+	e := sdk.NewUserNotificationEvent(wr)
+
+	subs, err := user.LoadNotificationSubscriptionsByProjectKey(ctx, db, e.ProjectKey)
+	if err != nil {
+		log.Error(ctx, "notification.SendUserSubscriptions> %v", err)
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+
+	users, err := subscriptionReaders(ctx, db, store, wr.ProjectID, subs)
+	if err != nil {
+		log.Error(ctx, "notification.SendUserSubscriptions> %v", err)
+		return
+	}
+
+	for _, s := range subs {
+		u, has := users[s.AuthentifiedUserID]
+		if !has || !s.Match(u.Username, e) {
+			continue
+		}
+		if s.Mode == sdk.UserSubscriptionModeImmediate {
+			go sendSubscriptionNotif(ctx, db, s, []sdk.UserNotificationEvent{e})
+			continue
+		}
+		se := e
+		se.SubscriptionID = s.ID
+		if err := user.InsertNotificationEvent(db, &se); err != nil {
+			log.Error(ctx, "notification.SendUserSubscriptions> %v", err)
+		}
+	}
+}
+
+// subscriptionReaders returns the owners of given subscriptions that can read the project.
+func subscriptionReaders(ctx context.Context, db gorp.SqlExecutor, store cache.Store, projectID int64, subs []sdk.UserNotificationSubscription) (map[string]sdk.AuthentifiedUser, error) {
+	ids := make([]string, 0, len(subs))
+	for _, s := range subs {
+		ids = append(ids, s.AuthentifiedUserID)
+	}
+	us, err := user.LoadAllByIDs(ctx, db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	proj, err := project.LoadByID(db, projectID, project.LoadOptions.WithGroups)
+	if err != nil {
+		return nil, err
+	}
+	var openToAll bool
+	for _, g := range proj.ProjectGroups {
+		if group.DefaultGroup != nil && group.DefaultGroup.ID == g.Group.ID {
+			openToAll = true
+		}
+	}
+	readerIDs, err := projectPermissionUserIDs(ctx, db, store, projectID, sdk.PermissionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]sdk.AuthentifiedUser, len(us))
+	for _, u := range us {
+		if openToAll || u.Ring == sdk.UserRingAdmin || u.Ring == sdk.UserRingMaintainer || sdk.IsInArray(u.ID, readerIDs) {
+			res[u.ID] = u
+		}
+	}
+	return res, nil
+}
+
+// SendUserNotificationDigests sends the digests of subscriptions whose period is over. The pending events of a
+// subscription are removed in a transaction that is committed before sending its digest, so a digest is never sent twice.
+func SendUserNotificationDigests(ctx context.Context, db *gorp.DbMap) error {
+	now := time.Now()
+	ids, err := user.LoadNotificationSubscriptionIDsWithDigestDue(db, now)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := sendUserNotificationDigest(ctx, db, id, now); err != nil {
+			log.Error(ctx, "notification.SendUserNotificationDigests> unable to send digest for subscription %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+func sendUserNotificationDigest(ctx context.Context, db *gorp.DbMap, id int64, now time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	s, err := user.LoadAndLockNotificationSubscriptionWithDigestDue(ctx, tx, id, now)
+	if err != nil {
+		// The digest was sent by another API instance
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	events, err := user.LoadNotificationEventsBySubscriptionID(ctx, tx, s.ID)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	if err := user.DeleteNotificationEventsBySubscriptionID(tx, s.ID, events[len(events)-1].ID); err != nil {
+		return err
+	}
+	if err := user.UpdateNotificationSubscriptionLastDigest(tx, s.ID, now); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	sendSubscriptionNotif(ctx, db, *s, events)
+	return nil
+}
+
+func sendSubscriptionNotif(ctx context.Context, db gorp.SqlExecutor, s sdk.UserNotificationSubscription, events []sdk.UserNotificationEvent) {
+	subject, lines := subscriptionNotifContent(events)
+	switch s.Channel {
+	case sdk.UserSubscriptionChannelEmail:
+		email, err := subscriptionEmail(ctx, db, s.AuthentifiedUserID)
+		if err != nil {
+			log.Error(ctx, "notification.sendSubscriptionNotif> %v", err)
+			return
+		}
+		body := bytes.NewBufferString("")
+		for _, l := range lines {
+			body.WriteString(l.Name + ": " + l.URL + "\n")
+		}
+		if err := mail.SendEmail(ctx, subject, body, email, false); err != nil {
+			log.Error(ctx, "notification.sendSubscriptionNotif> error while sending mail: %v", err)
+		}
+	case sdk.UserSubscriptionChannelChat:
+		if err := postChatNotif(ctx, s.WebhookURL, s.ChatType, subscriptionChatMessage(subject, lines, events)); err != nil {
+			log.Error(ctx, "notification.sendSubscriptionNotif> %v", err)
+		}
+	}
+}
+
+// subscriptionNotifContent returns the subject of the notification and a link per workflow run.
+func subscriptionNotifContent(events []sdk.UserNotificationEvent) (string, []chatLink) {
+	lines := make([]chatLink, 0, len(events))
+	var failed int
+	for _, e := range events {
+		if e.Status == sdk.StatusFail {
+			failed++
+		}
+		name := fmt.Sprintf("%s/%s#%d %s", e.ProjectKey, e.WorkflowName, e.RunNumber, e.Status)
+		if e.Branch != "" {
+			name += " on " + e.Branch
+		}
+		lines = append(lines, chatLink{
+			Name: name,
+			URL:  fmt.Sprintf("%s/project/%s/workflow/%s/run/%d", uiURL, e.ProjectKey, e.WorkflowName, e.RunNumber),
+		})
+	}
+	if len(events) == 1 {
+		return "[CDS] " + lines[0].Name, lines
+	}
+	return fmt.Sprintf("[CDS] Digest: %d workflow runs, %d failed", len(events), failed), lines
+}
+
+func subscriptionChatMessage(subject string, lines []chatLink, events []sdk.UserNotificationEvent) chatMessage {
+	if len(events) == 1 {
+		e := events[0]
+		m := chatMessage{
+			Title:  subject,
+			URL:    lines[0].URL,
+			Status: e.Status,
+			Author: e.TriggeredBy,
+			Branch: e.Branch,
+			Commit: e.Commit,
+		}
+		if len(m.Commit) > 8 {
+			m.Commit = m.Commit[:8]
+		}
+		return m
+	}
+
+	m := chatMessage{Title: subject, Status: sdk.StatusSuccess}
+	var buf bytes.Buffer
+	for i, l := range lines {
+		buf.WriteString(l.Name + " " + l.URL + "\n")
+		if events[i].Status == sdk.StatusFail {
+			m.Status = sdk.StatusFail
+		}
+	}
+	m.Text = buf.String()
+	return m
+}
+
+// subscriptionEmail returns the primary email of the user.
+func subscriptionEmail(ctx context.Context, db gorp.SqlExecutor, userID string) (string, error) {
+	contacts, err := user.LoadContactsByUserIDs(ctx, db, []string{userID})
+	if err != nil {
+		return "", err
+	}
+	for _, c := range contacts {
+		if c.Type == sdk.UserContactTypeEmail && c.Primary {
+			return c.Value, nil
+		}
+	}
+	return "", sdk.NewErrorFrom(sdk.ErrNotFound, "no primary email for user %s", userID)
+}
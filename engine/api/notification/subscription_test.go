@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSubscriptionNotifContent(t *testing.T) {
+	Init("https://cds.local")
+
+	events := []sdk.UserNotificationEvent{
+		{ProjectKey: "MYPROJ", WorkflowName: "my-workflow", RunNumber: 12, Status: sdk.StatusFail, Branch: "master", Commit: "8c4d7b9f1f5cdb2b", TriggeredBy: "john.doe"},
+	}
+	subject, lines := subscriptionNotifContent(events)
+	require.Equal(t, "[CDS] MYPROJ/my-workflow#12 Fail on master", subject)
+	m := subscriptionChatMessage(subject, lines, events)
+	require.Equal(t, "https://cds.local/project/MYPROJ/workflow/my-workflow/run/12", m.URL)
+	require.Equal(t, "8c4d7b9f", m.Commit)
+	require.Equal(t, "john.doe", m.Author)
+
+	// Digest
+	events = append(events, sdk.UserNotificationEvent{ProjectKey: "MYPROJ", WorkflowName: "other", RunNumber: 3, Status: sdk.StatusSuccess})
+	subject, lines = subscriptionNotifContent(events)
+	require.Equal(t, "[CDS] Digest: 2 workflow runs, 1 failed", subject)
+	m = subscriptionChatMessage(subject, lines, events)
+	require.Equal(t, sdk.StatusFail, m.Status)
+	require.Contains(t, m.Text, "MYPROJ/other#3 Success https://cds.local/project/MYPROJ/workflow/other/run/3")
+}
+
+func TestSendUserNotificationDigests(t *testing.T) {
+	db, factory, _ := test.SetupPGWithFactory(t)
+	dbMap := factory.GetDBMap(gorpmapping.Mapper)()
+	Init("https://cds.local")
+
+	var posts int32
+	var statusCode int32 = http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&posts, 1)
+		w.WriteHeader(int(atomic.LoadInt32(&statusCode)))
+	}))
+	defer srv.Close()
+
+	u, _ := assets.InsertLambdaUser(t, db)
+	s := sdk.UserNotificationSubscription{
+		AuthentifiedUserID: u.ID,
+		TriggeredByMe:      true,
+		Channel:            sdk.UserSubscriptionChannelChat,
+		ChatType:           sdk.SlackUserNotification,
+		WebhookURL:         srv.URL,
+		Mode:               sdk.UserSubscriptionModeHourly,
+	}
+	require.NoError(t, user.InsertNotificationSubscription(context.TODO(), db, &s))
+	insertEvent := func() {
+		require.NoError(t, user.InsertNotificationEvent(db, &sdk.UserNotificationEvent{
+			SubscriptionID: s.ID,
+			ProjectKey:     "MYPROJ",
+			WorkflowName:   "my-workflow",
+			RunNumber:      1,
+			Status:         sdk.StatusSuccess,
+		}))
+	}
+	insertEvent()
+	insertEvent()
+
+	// The period of a subscription that never sent a digest starts at its creation
+	_, err := db.Exec("UPDATE user_notification_subscription SET last_digest = NULL WHERE id = $1", s.ID)
+	require.NoError(t, err)
+	require.NoError(t, SendUserNotificationDigests(context.TODO(), dbMap))
+	require.Equal(t, int32(0), atomic.LoadInt32(&posts))
+
+	_, err = db.Exec("UPDATE user_notification_subscription SET created = $1 WHERE id = $2", time.Now().Add(-2*time.Hour), s.ID)
+	require.NoError(t, err)
+	require.NoError(t, SendUserNotificationDigests(context.TODO(), dbMap))
+	require.Equal(t, int32(1), atomic.LoadInt32(&posts))
+	events, err := user.LoadNotificationEventsBySubscriptionID(context.TODO(), db, s.ID)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	// The next digest waits for the end of the period
+	insertEvent()
+	require.NoError(t, SendUserNotificationDigests(context.TODO(), dbMap))
+	require.Equal(t, int32(1), atomic.LoadInt32(&posts))
+
+	// Events are removed before sending, a failed digest is not sent again
+	atomic.StoreInt32(&statusCode, http.StatusInternalServerError)
+	require.NoError(t, user.UpdateNotificationSubscriptionLastDigest(db, s.ID, time.Now().Add(-2*time.Hour)))
+	require.NoError(t, SendUserNotificationDigests(context.TODO(), dbMap))
+	require.Equal(t, int32(2), atomic.LoadInt32(&posts))
+	require.NoError(t, user.UpdateNotificationSubscriptionLastDigest(db, s.ID, time.Now().Add(-2*time.Hour)))
+	require.NoError(t, SendUserNotificationDigests(context.TODO(), dbMap))
+	require.Equal(t, int32(2), atomic.LoadInt32(&posts))
+}
@@ -130,6 +130,23 @@ func GetUserWorkflowEvents(ctx context.Context, db gorp.SqlExecutor, store cache
 				}
 				log.Debug(ctx, "GetUserWorkflowEvents> will send mail notifications: %+v", notif)
 				go sendMailNotif(ctx, notif)
+
+			case sdk.SlackUserNotification, sdk.MattermostUserNotification, sdk.TeamsUserNotification:
+				webhookURL, err := chatWebhookURL(db, projectKey, notif.Integration)
+				if err != nil {
+					log.Error(ctx, "notification[%s].GetUserWorkflowEvents> %v", notif.Type, err)
+					break
+				}
+				settings := notif.Settings
+				if settings.Template == nil {
+					settings.Template = &sdk.UserNotificationTemplateChat
+				}
+				e, err := getWorkflowEvent(&settings, params)
+				if err != nil {
+					log.Error(ctx, "notification.GetUserWorkflowEvents> unable to handle event %+v: %v", notif.Settings, err)
+					break
+				}
+				go sendChatNotif(ctx, webhookURL, notif.Type, newChatMessage(e, params, projectKey, workflowName, nr))
 			}
 		}
 	}
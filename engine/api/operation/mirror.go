@@ -0,0 +1,95 @@
+package operation
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk"
+)
+
+// RefreshRepositoryMirror asks all the repositories services to create or refresh the mirror of a repository.
+func RefreshRepositoryMirror(ctx context.Context, db gorp.SqlExecutor, prj sdk.Project, repoURL string, strategy sdk.RepositoryStrategy) error {
+	srvs, err := services.LoadAllByType(ctx, db, sdk.TypeRepositories)
+	if err != nil {
+		return sdk.WrapError(err, "unable to found repositories service")
+	}
+	if err := setRepositoryStrategyCredentials(prj, &strategy); err != nil {
+		return err
+	}
+
+	m := sdk.RepositoryMirror{ProjectKey: prj.Key, URL: repoURL, RepositoryStrategy: strategy}
+	// Each repositories service maintains its own mirrors
+	for i := range srvs {
+		_, code, err := services.NewClient(db, srvs[i:i+1]).DoJSONRequest(ctx, http.MethodPost, "/mirrors", m, nil)
+		if err != nil && code != http.StatusNotImplemented {
+			log.Warn(ctx, "unable to refresh mirror of %s on service %s: %v", repoURL, srvs[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// LoadRepositoryMirrorService returns the repositories service that serves the mirror with given id.
+func LoadRepositoryMirrorService(ctx context.Context, db gorp.SqlExecutor, id string) (*sdk.Service, error) {
+	srvs, err := services.LoadAllByType(ctx, db, sdk.TypeRepositories)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to found repositories service")
+	}
+	// Always use the same service for a mirror to get consistent refs and packs during a clone
+	sort.Slice(srvs, func(i, j int) bool { return srvs[i].ID < srvs[j].ID })
+	for i := range srvs {
+		if _, _, err := services.NewClient(db, srvs[i:i+1]).DoJSONRequest(ctx, http.MethodGet, "/mirrors/"+id, nil, nil); err == nil {
+			return &srvs[i], nil
+		}
+	}
+	return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "no mirror available")
+}
+
+// ProxyRepositoryMirror forwards a git smart http request to the repositories service that serves the mirror.
+func ProxyRepositoryMirror(ctx context.Context, db gorp.SqlExecutor, w http.ResponseWriter, r *http.Request, id, subPath string) error {
+	srv, err := LoadRepositoryMirrorService(ctx, db, id)
+	if err != nil {
+		return err
+	}
+
+	// Upload pack requests only contain wanted and known refs, they are small enough to be buffered
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	header := http.Header{}
+	for _, k := range []string{"Accept", "Content-Type", "Content-Encoding", "Git-Protocol"} {
+		if v := r.Header.Get(k); v != "" {
+			header.Set(k, v)
+		}
+	}
+	path := "/mirrors/" + id + subPath
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+
+	resp, err := services.DoStreamRequest(ctx, *srv, r.Method, path, bytes.NewReader(body), header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint
+	if resp.StatusCode >= 400 {
+		return sdk.NewErrorFrom(sdk.ErrUnknownError, "mirror request failed with status code %d", resp.StatusCode)
+	}
+
+	for _, k := range []string{"Content-Type", "Cache-Control", "Expires", "Pragma"} {
+		if v := resp.Header.Get(k); v != "" {
+			w.Header().Set(k, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, err = io.Copy(w, resp.Body)
+	return sdk.WithStack(err)
+}
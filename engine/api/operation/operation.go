@@ -82,7 +82,10 @@ func PushOperation(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store c
 	return pushOperation(ctx, db, store, proj, data, ope)
 }
 
-func PushOperationUpdate(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, data exportentities.WorkflowComponents, vcsServerName, repoFullname, branch, message string, vcsStrategy sdk.RepositoryStrategy, u sdk.Identifiable) (*sdk.Operation, error) {
+// PushOperationUpdate updates the as code files of an existing workflow, files are written in the workflow root
+// of given from repository value.
+func PushOperationUpdate(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, data exportentities.WorkflowComponents, vcsServerName, repoFullname, fromRepository, branch, message string, vcsStrategy sdk.RepositoryStrategy, u sdk.Identifiable) (*sdk.Operation, error) {
+	_, root := sdk.SplitAsCodeFromRepository(fromRepository)
 	ope := sdk.Operation{
 		VCSServer:          vcsServerName,
 		RepoFullName:       repoFullname,
@@ -94,6 +97,9 @@ func PushOperationUpdate(ctx context.Context, db gorpmapper.SqlExecutorWithTx, s
 				Update:     true,
 			},
 		},
+		LoadFiles: sdk.OperationLoadFiles{
+			Root: root,
+		},
 	}
 	ope.User.Email = u.GetEmail()
 	ope.User.Fullname = u.GetFullname()
@@ -102,30 +108,38 @@ func PushOperationUpdate(ctx context.Context, db gorpmapper.SqlExecutorWithTx, s
 	return pushOperation(ctx, db, store, proj, data, ope)
 }
 
-// PostRepositoryOperation creates a new repository operation
-func PostRepositoryOperation(ctx context.Context, db gorp.SqlExecutor, prj sdk.Project, ope *sdk.Operation, multipartData *services.MultiPartData) error {
-	srvs, err := services.LoadAllByType(ctx, db, sdk.TypeRepositories)
-	if err != nil {
-		return sdk.WrapError(err, "Unable to found repositories service")
-	}
-
-	if ope.RepositoryStrategy.ConnectionType == "ssh" {
+// setRepositoryStrategyCredentials sets the ssh key of the project used by a strategy, or removes it for https strategies.
+func setRepositoryStrategyCredentials(prj sdk.Project, strategy *sdk.RepositoryStrategy) error {
+	if strategy.ConnectionType == "ssh" {
 		found := false
 		for _, k := range prj.Keys {
-			if k.Name == ope.RepositoryStrategy.SSHKey {
-				ope.RepositoryStrategy.SSHKeyContent = k.Private
+			if k.Name == strategy.SSHKey {
+				strategy.SSHKeyContent = k.Private
 				found = true
 				break
 			}
 		}
 		if !found {
-			return sdk.WithStack(fmt.Errorf("unable to find key %s on project %s", ope.RepositoryStrategy.SSHKey, prj.Key))
+			return sdk.WithStack(fmt.Errorf("unable to find key %s on project %s", strategy.SSHKey, prj.Key))
 		}
-		ope.RepositoryStrategy.User = ""
-		ope.RepositoryStrategy.Password = ""
+		strategy.User = ""
+		strategy.Password = ""
 	} else {
-		ope.RepositoryStrategy.SSHKey = ""
-		ope.RepositoryStrategy.SSHKeyContent = ""
+		strategy.SSHKey = ""
+		strategy.SSHKeyContent = ""
+	}
+	return nil
+}
+
+// PostRepositoryOperation creates a new repository operation
+func PostRepositoryOperation(ctx context.Context, db gorp.SqlExecutor, prj sdk.Project, ope *sdk.Operation, multipartData *services.MultiPartData) error {
+	srvs, err := services.LoadAllByType(ctx, db, sdk.TypeRepositories)
+	if err != nil {
+		return sdk.WrapError(err, "Unable to found repositories service")
+	}
+
+	if err := setRepositoryStrategyCredentials(prj, &ope.RepositoryStrategy); err != nil {
+		return err
 	}
 
 	if multipartData == nil {
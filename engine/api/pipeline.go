@@ -119,7 +119,7 @@ func (api *API) updateAsCodePipelineHandler() service.Handler {
 			Pipelines: []exportentities.PipelineV1{wpi},
 		}
 
-		ope, err := operation.PushOperationUpdate(ctx, tx, api.Cache, *proj, wp, rootApp.VCSServer, rootApp.RepositoryFullname, branch, message, rootApp.RepositoryStrategy, u)
+		ope, err := operation.PushOperationUpdate(ctx, tx, api.Cache, *proj, wp, rootApp.VCSServer, rootApp.RepositoryFullname, rootApp.FromRepository, branch, message, rootApp.RepositoryStrategy, u)
 		if err != nil {
 			return err
 		}
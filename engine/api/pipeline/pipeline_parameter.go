@@ -119,3 +119,40 @@ func DeleteAllParameterFromPipeline(db gorp.SqlExecutor, pipelineID int64) error
 	_, err := db.Exec(query, pipelineID)
 	return sdk.WrapError(err, "unable to delete all parameters")
 }
+
+// LoadNamesUsingKey returns the names of the pipelines of given project that reference a key
+// as a parameter default value or as a job step parameter value.
+func LoadNamesUsingKey(db gorp.SqlExecutor, projectKey string, keyName string) ([]string, error) {
+	query := `
+	WITH RECURSIVE parent(pipName, id, child_id, value) as (
+		SELECT pipeline.name, action_edge.id as id, action_edge.child_id as child_id, action_edge_parameter.value
+		FROM pipeline
+		JOIN pipeline_stage on pipeline_stage.pipeline_id = pipeline.id
+		JOIN pipeline_action on pipeline_action.pipeline_stage_id = pipeline_stage.id
+		JOIN project on project.id = pipeline.project_id
+		JOIN action on action.id = pipeline_action.action_id
+		LEFT JOIN action_edge ON action_edge.parent_id = action.id
+		LEFT JOIN action_edge_parameter on action_edge_parameter.action_edge_id = action_edge.id
+		WHERE project.projectkey = $1 AND action_edge.id IS NOT NULL
+
+		UNION
+
+		SELECT p.pipName, c.id, c.child_id, action_edge_parameter.value FROM parent as p, action_edge as c
+		LEFT JOIN action_edge_parameter ON action_edge_parameter.action_edge_id = c.id
+		WHERE p.child_id = c.parent_id
+	)
+	SELECT pipName FROM parent WHERE value = $2
+	UNION
+	SELECT pipeline.name
+	FROM pipeline_parameter
+	JOIN pipeline ON pipeline.id = pipeline_parameter.pipeline_id
+	JOIN project ON project.id = pipeline.project_id
+	WHERE project.projectkey = $1 AND pipeline_parameter.type = ANY($3) AND pipeline_parameter.value = $2
+	ORDER BY 1`
+
+	var names []string
+	if _, err := db.Select(&names, query, projectKey, keyName, pq.StringArray([]string{sdk.KeySSHParameter, sdk.KeyPGPParameter, sdk.KeyParameter})); err != nil {
+		return nil, sdk.WrapError(err, "unable to load pipelines using key %s", keyName)
+	}
+	return names, nil
+}
@@ -84,6 +84,7 @@ func (api *API) getProjectsHandler_FilterByRepo(ctx context.Context, w http.Resp
 			return err
 		}
 	}
+	projects = filterProjectsByConsumerRestrictions(ctx, projects)
 
 	pKeys := projects.Keys()
 	perms, err := permission.LoadProjectMaxLevelPermission(ctx, api.mustDB(), pKeys, getAPIConsumer(ctx).GetGroupIDs())
@@ -114,6 +115,21 @@ func (api *API) getProjectsHandler_FilterByRepo(ctx context.Context, w http.Resp
 	return service.WriteJSON(w, projects, http.StatusOK)
 }
 
+// filterProjectsByConsumerRestrictions removes the projects that are not allowed by the consumer's resource restrictions.
+func filterProjectsByConsumerRestrictions(ctx context.Context, projects sdk.Projects) sdk.Projects {
+	c := getAPIConsumer(ctx)
+	if c == nil || len(c.ResourceRestrictions) == 0 {
+		return projects
+	}
+	res := make(sdk.Projects, 0, len(projects))
+	for i := range projects {
+		if c.ResourceRestrictions.Level(projects[i].Key, "") >= sdk.PermissionRead {
+			res = append(res, projects[i])
+		}
+	}
+	return res
+}
+
 func (api *API) getProjectsHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		withPermissions := r.FormValue("permission")
@@ -172,6 +188,7 @@ func (api *API) getProjectsHandler() service.Handler {
 		if err != nil {
 			return err
 		}
+		projects = filterProjectsByConsumerRestrictions(ctx, projects)
 
 		var groupIDs []int64
 		var admin bool
@@ -598,6 +615,7 @@ func (api *API) postProjectHandler() service.Handler {
 			return sdk.WithStack(err)
 		}
 
+		addAuditTarget(ctx, "project_key", p.Key)
 		event.PublishAddProject(ctx, &p, consumer)
 
 		proj, err := project.Load(ctx, api.mustDB(), p.Key,
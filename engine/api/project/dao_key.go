@@ -22,6 +22,16 @@ func InsertKey(db gorpmapper.SqlExecutorWithTx, key *sdk.ProjectKey) error {
 	return nil
 }
 
+// UpdateKey updates a project key in database
+func UpdateKey(ctx context.Context, db gorpmapper.SqlExecutorWithTx, key *sdk.ProjectKey) error {
+	var dbProjKey = dbProjectKey{ProjectKey: *key}
+	if err := gorpmapping.UpdateAndSign(ctx, db, &dbProjKey); err != nil {
+		return err
+	}
+	*key = dbProjKey.ProjectKey
+	return nil
+}
+
 func getAllKeys(db gorp.SqlExecutor, query gorpmapping.Query) ([]sdk.ProjectKey, error) {
 	var ctx = context.Background()
 	var res []dbProjectKey
@@ -0,0 +1,56 @@
+package project
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func getProvenanceKey(ctx context.Context, db gorp.SqlExecutor, query gorpmapping.Query, opts ...gorpmapping.GetOptionFunc) (*sdk.ProjectProvenanceKey, error) {
+	var k dbProjectProvenanceKey
+	found, err := gorpmapping.Get(ctx, db, query, &k, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	isValid, err := gorpmapping.CheckSignature(k, k.Signature)
+	if err != nil {
+		return nil, err
+	}
+	if !isValid {
+		log.Error(ctx, "project.getProvenanceKey> project provenance key %d data corrupted", k.ID)
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &k.ProjectProvenanceKey, nil
+}
+
+// LoadProvenanceKey loads the provenance key of a project without its private part.
+func LoadProvenanceKey(ctx context.Context, db gorp.SqlExecutor, projectID int64) (*sdk.ProjectProvenanceKey, error) {
+	query := gorpmapping.NewQuery("SELECT * FROM project_provenance_key WHERE project_id = $1").Args(projectID)
+	return getProvenanceKey(ctx, db, query)
+}
+
+// LoadProvenanceKeyWithDecryption loads the provenance key of a project with its private part.
+func LoadProvenanceKeyWithDecryption(ctx context.Context, db gorp.SqlExecutor, projectID int64) (*sdk.ProjectProvenanceKey, error) {
+	query := gorpmapping.NewQuery("SELECT * FROM project_provenance_key WHERE project_id = $1").Args(projectID)
+	return getProvenanceKey(ctx, db, query, gorpmapping.GetOptions.WithDecryption)
+}
+
+// InsertProvenanceKey inserts the provenance key of a project.
+func InsertProvenanceKey(ctx context.Context, db gorpmapper.SqlExecutorWithTx, key *sdk.ProjectProvenanceKey) error {
+	key.Created = time.Now()
+	var dbKey = dbProjectProvenanceKey{ProjectProvenanceKey: *key}
+	if err := gorpmapping.InsertAndSign(ctx, db, &dbKey); err != nil {
+		return err
+	}
+	*key = dbKey.ProjectProvenanceKey
+	return nil
+}
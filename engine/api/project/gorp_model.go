@@ -25,6 +25,18 @@ func (e dbProjectKey) Canonical() gorpmapper.CanonicalForms {
 	}
 }
 
+type dbProjectProvenanceKey struct {
+	gorpmapper.SignedEntity
+	sdk.ProjectProvenanceKey
+}
+
+func (e dbProjectProvenanceKey) Canonical() gorpmapper.CanonicalForms {
+	var _ = []interface{}{e.ProjectID, e.ID, e.Name, e.Public}
+	return gorpmapper.CanonicalForms{
+		"{{print .ProjectID}}{{print .ID}}{{.Name}}{{.Public}}",
+	}
+}
+
 type dbLabel sdk.Label
 
 type dbProjectVariable struct {
@@ -87,6 +99,7 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(dbProject{}, "project", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbProjectVariableAudit{}, "project_variable_audit", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbProjectKey{}, "project_key", true, "id"))
+	gorpmapping.Register(gorpmapping.New(dbProjectProvenanceKey{}, "project_provenance_key", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbLabel{}, "project_label", true, "id"))
 	gorpmapping.Register(gorpmapping.New(dbProjectVariable{}, "project_variable", true, "id"))
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/ovh/cds/engine/api/integration"
 	"github.com/ovh/cds/engine/api/plugin"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 )
@@ -49,6 +50,29 @@ func (api *API) getProjectIntegrationHandler() service.Handler {
 	}
 }
 
+func (api *API) getProjectIntegrationAuditsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars[permProjectKey]
+		integrationName := vars["integrationName"]
+
+		integ, err := integration.LoadProjectIntegrationByName(api.mustDB(), projectKey, integrationName)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load integration %s/%s", projectKey, integrationName)
+		}
+		if !integ.Model.Secret {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "audits are only available for secret integrations")
+		}
+
+		audits, err := secret.LoadAuditsByProjectIntegrationID(ctx, api.mustDB(), integ.ID, 100)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, audits, http.StatusOK)
+	}
+}
+
 func (api *API) putProjectIntegrationHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -260,6 +284,7 @@ func (api *API) postProjectIntegrationHandler() service.Handler {
 			return sdk.WithStack(err)
 		}
 
+		addAuditTarget(ctx, "integration_name", pp.Name)
 		event.PublishAddProjectIntegration(ctx, p, pp, getAPIConsumer(ctx))
 
 		return service.WriteJSON(w, pp, http.StatusOK)
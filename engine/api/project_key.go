@@ -2,14 +2,21 @@ package api
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 
+	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/engine/service"
 	"github.com/ovh/cds/sdk"
 )
@@ -96,13 +103,15 @@ func (api *API) addKeyInProjectHandler() service.Handler {
 			newKey.Name = "proj-" + newKey.Name
 		}
 
-		k, err := keys.GenerateKey(newKey.Name, newKey.Type)
+		k, err := newKeyContent(newKey.Name, newKey.Type, newKey.Algorithm, newKey.Private)
 		if err != nil {
 			return err
 		}
 		newKey.Private = k.Private
 		newKey.Public = k.Public
 		newKey.KeyID = k.KeyID
+		newKey.Successor = ""
+		newKey.RetireAt = nil
 
 		tx, errT := api.mustDB().Begin()
 		if errT != nil {
@@ -123,3 +132,266 @@ func (api *API) addKeyInProjectHandler() service.Handler {
 		return service.WriteJSON(w, newKey, http.StatusOK)
 	}
 }
+
+func loadProjectKeyWithPrivateContent(db gorp.SqlExecutor, projectID int64, keyName string) (*sdk.ProjectKey, error) {
+	ks, err := project.LoadAllKeysWithPrivateContent(db, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ks {
+		if ks[i].Name == keyName {
+			return &ks[i], nil
+		}
+	}
+	return nil, sdk.WithStack(sdk.ErrKeyNotFound)
+}
+
+func (api *API) postRotateKeyInProjectHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		keyName := vars["name"]
+
+		var req sdk.KeyRotationRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		p, err := project.Load(ctx, api.mustDB(), key, project.LoadOptions.WithKeys)
+		if err != nil {
+			return err
+		}
+
+		oldKey, err := loadProjectKeyWithPrivateContent(api.mustDB(), p.ID, keyName)
+		if err != nil {
+			return err
+		}
+		if oldKey.Successor != "" {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "key %s has already been rotated to %s", oldKey.Name, oldKey.Successor)
+		}
+
+		successorName, retireAt, err := checkKeyRotationRequest(req, oldKey.Name, "proj-")
+		if err != nil {
+			return err
+		}
+		for _, k := range p.Keys {
+			if k.Name == successorName {
+				return sdk.NewErrorFrom(sdk.ErrKeyAlreadyExist, "key %s already exists", successorName)
+			}
+		}
+
+		k, err := newKeyContent(successorName, oldKey.Type, req.Algorithm, req.Private)
+		if err != nil {
+			return err
+		}
+		newKey := sdk.ProjectKey{
+			Name:      successorName,
+			Type:      oldKey.Type,
+			Public:    k.Public,
+			Private:   k.Private,
+			KeyID:     k.KeyID,
+			ProjectID: p.ID,
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		if err := project.InsertKey(tx, &newKey); err != nil {
+			return sdk.WrapError(err, "cannot insert project key")
+		}
+
+		oldKey.Successor = newKey.Name
+		oldKey.RetireAt = &retireAt
+		if err := project.UpdateKey(ctx, tx, oldKey); err != nil {
+			return sdk.WrapError(err, "cannot update project key %s", oldKey.Name)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		event.PublishAddProjectKey(ctx, p, newKey, getAPIConsumer(ctx))
+
+		return service.WriteJSON(w, newKey, http.StatusOK)
+	}
+}
+
+func (api *API) getKeyUsageInProjectHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		keyName := vars["name"]
+
+		p, err := project.Load(ctx, api.mustDB(), key, project.LoadOptions.WithKeys)
+		if err != nil {
+			return err
+		}
+		k := p.GetKey(keyName)
+		if k == nil {
+			return sdk.WithStack(sdk.ErrKeyNotFound)
+		}
+
+		usage, err := loadKeyUsage(ctx, api.mustDB(), key, keyName)
+		if err != nil {
+			return err
+		}
+		usage.Successor = k.Successor
+		usage.RetireAt = k.RetireAt
+
+		return service.WriteJSON(w, usage, http.StatusOK)
+	}
+}
+
+func (api *API) postRetireKeyInProjectHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+		keyName := vars["name"]
+		force := service.FormBool(r, "force")
+
+		p, err := project.Load(ctx, api.mustDB(), key, project.LoadOptions.WithKeys)
+		if err != nil {
+			return err
+		}
+		k := p.GetKey(keyName)
+		if k == nil {
+			return sdk.WithStack(sdk.ErrKeyNotFound)
+		}
+
+		if err := checkKeyRetirement(k.Name, k.RetireAt); err != nil {
+			return err
+		}
+
+		usage, err := loadKeyUsage(ctx, api.mustDB(), key, keyName)
+		if err != nil {
+			return err
+		}
+		if usage.IsUsed() && !force {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "key %s is still used by applications %v, pipelines %v", keyName, usage.Applications, usage.Pipelines)
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		if err := project.DeleteProjectKey(tx, p.ID, keyName); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		event.PublishDeleteProjectKey(ctx, p, *k, getAPIConsumer(ctx))
+
+		return nil
+	}
+}
+
+// newKeyContent imports the given private key or generates a new key if empty.
+func newKeyContent(name string, t sdk.KeyType, algo sdk.SSHKeyAlgorithm, private string) (sdk.Key, error) {
+	if private != "" {
+		return keys.ImportKey(name, t, private)
+	}
+	return keys.GenerateKeyWithAlgorithm(name, t, algo)
+}
+
+// checkKeyRotationRequest returns the name of the successor key and the end of the overlap period.
+func checkKeyRotationRequest(req sdk.KeyRotationRequest, keyName, prefix string) (string, time.Time, error) {
+	successorName := req.SuccessorName
+	if successorName == "" {
+		successorName = fmt.Sprintf("%s-%s", keyName, time.Now().Format("20060102150405"))
+	}
+	if !sdk.NamePatternRegex.MatchString(successorName) {
+		return "", time.Time{}, sdk.NewErrorFrom(sdk.ErrInvalidKeyPattern, "key name %s do not respect pattern %s", successorName, sdk.NamePattern)
+	}
+	if !strings.HasPrefix(successorName, prefix) {
+		successorName = prefix + successorName
+	}
+	if successorName == keyName {
+		return "", time.Time{}, sdk.NewErrorFrom(sdk.ErrWrongRequest, "successor key must have a different name")
+	}
+
+	if req.OverlapHours < 0 {
+		return "", time.Time{}, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid overlap period")
+	}
+	overlap := sdk.DefaultKeyRotationOverlap
+	if req.OverlapHours > 0 {
+		overlap = time.Duration(req.OverlapHours) * time.Hour
+	}
+
+	return successorName, time.Now().Add(overlap), nil
+}
+
+// checkKeyRetirement returns an error if the overlap period of a rotated key is not over.
+func checkKeyRetirement(keyName string, retireAt *time.Time) error {
+	if retireAt != nil && time.Now().Before(*retireAt) {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "key %s can't be retired before the end of its overlap period (%s)", keyName, retireAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// loadKeyUsage returns the applications, pipelines and workflows of a project that reference given key.
+func loadKeyUsage(ctx context.Context, db gorp.SqlExecutor, projectKey, keyName string) (sdk.KeyUsage, error) {
+	usage := sdk.KeyUsage{
+		Key:          keyName,
+		Applications: []string{},
+		Pipelines:    []string{},
+		Workflows:    []sdk.WorkflowName{},
+	}
+
+	apps, err := application.LoadAll(db, projectKey)
+	if err != nil {
+		return usage, err
+	}
+
+	workflows := make(map[int64]sdk.WorkflowName)
+	for _, app := range apps {
+		if app.RepositoryStrategy.SSHKey != keyName && app.RepositoryStrategy.PGPKey != keyName {
+			continue
+		}
+		usage.Applications = append(usage.Applications, app.Name)
+		wfs, err := workflow.LoadByApplicationName(ctx, db, projectKey, app.Name)
+		if err != nil {
+			return usage, err
+		}
+		for _, wf := range wfs {
+			workflows[wf.ID] = wf
+		}
+	}
+
+	pipNames, err := pipeline.LoadNamesUsingKey(db, projectKey, keyName)
+	if err != nil {
+		return usage, err
+	}
+	usage.Pipelines = append(usage.Pipelines, pipNames...)
+	for _, pipName := range pipNames {
+		wfs, err := workflow.LoadByPipelineName(ctx, db, projectKey, pipName)
+		if err != nil {
+			return usage, err
+		}
+		for _, wf := range wfs {
+			workflows[wf.ID] = wf
+		}
+	}
+
+	wfs, err := workflow.LoadByKeyInDefaultPipelineParameters(ctx, db, projectKey, keyName)
+	if err != nil {
+		return usage, err
+	}
+	for _, wf := range wfs {
+		workflows[wf.ID] = wf
+	}
+
+	for _, wf := range workflows {
+		usage.Workflows = append(usage.Workflows, wf)
+	}
+	sort.Slice(usage.Workflows, func(i, j int) bool { return usage.Workflows[i].Name < usage.Workflows[j].Name })
+
+	return usage, nil
+}
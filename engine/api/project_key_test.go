@@ -2,17 +2,23 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/ovh/cds/engine/api/authentication"
 	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_getKeysInProjectHandler(t *testing.T) {
@@ -142,3 +148,147 @@ func Test_addKeyInProjectHandler(t *testing.T) {
 
 	assert.Equal(t, proj.ID, key.ProjectID)
 }
+
+func Test_rotateKeyInProjectHandler(t *testing.T) {
+	api, db, router := newTestAPI(t)
+
+	u, pass := assets.InsertAdminUser(t, db)
+
+	pkey := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, pkey, pkey)
+
+	kssh, err := keys.GenerateSSHKey("proj-mykey")
+	test.NoError(t, err)
+	k := &sdk.ProjectKey{
+		Name:      kssh.Name,
+		Type:      kssh.Type,
+		Public:    kssh.Public,
+		Private:   kssh.Private,
+		ProjectID: proj.ID,
+	}
+	test.NoError(t, project.InsertKey(db, k))
+
+	// A workflow gives the key to a pipeline parameter
+	pip := sdk.Pipeline{ProjectID: proj.ID, ProjectKey: proj.Key, Name: sdk.RandomString(10)}
+	test.NoError(t, pipeline.InsertPipeline(db, &pip))
+	param := sdk.Parameter{Name: "deployKey", Type: sdk.KeySSHParameter}
+	test.NoError(t, pipeline.InsertParameterInPipeline(db, pip.ID, &param))
+	pip.Parameter = []sdk.Parameter{param}
+	wf := &sdk.Workflow{
+		Name:       sdk.RandomString(10),
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "root",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID:                pip.ID,
+					DefaultPipelineParameters: []sdk.Parameter{{Name: "deployKey", Value: k.Name}},
+				},
+			},
+		},
+		Pipelines: map[int64]sdk.Pipeline{pip.ID: pip},
+	}
+	test.NoError(t, workflow.Insert(context.TODO(), db, api.Cache, *proj, wf))
+	localConsumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	test.NoError(t, err)
+
+	vars := map[string]string{
+		"permProjectKey": proj.Key,
+		"name":           k.Name,
+	}
+
+	// Rotate the key with an ed25519 successor
+	jsonBody, _ := json.Marshal(sdk.KeyRotationRequest{SuccessorName: "mykey-v2", Algorithm: sdk.SSHKeyAlgorithmED25519})
+	uri := router.GetRoute("POST", api.postRotateKeyInProjectHandler, vars)
+	req, err := http.NewRequest("POST", uri, bytes.NewBuffer(jsonBody))
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var successor sdk.ProjectKey
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &successor))
+	assert.Equal(t, "proj-mykey-v2", successor.Name)
+	assert.Contains(t, successor.Public, "ssh-ed25519 ")
+
+	// Both keys are valid during the overlap period
+	ks, err := project.LoadAllKeys(db, proj.ID)
+	test.NoError(t, err)
+	assert.Len(t, ks, 2)
+	for _, pk := range ks {
+		if pk.Name == k.Name {
+			assert.Equal(t, successor.Name, pk.Successor)
+			assert.NotNil(t, pk.RetireAt)
+		}
+	}
+
+	// A key can't be rotated twice
+	req, err = http.NewRequest("POST", uri, bytes.NewBuffer(jsonBody))
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	// Get key usage then retire the old key
+	uri = router.GetRoute("GET", api.getKeyUsageInProjectHandler, vars)
+	req, err = http.NewRequest("GET", uri, nil)
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	var usage sdk.KeyUsage
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &usage))
+	assert.Equal(t, successor.Name, usage.Successor)
+	require.Len(t, usage.Workflows, 1)
+	assert.Equal(t, wf.Name, usage.Workflows[0].Name)
+	assert.Empty(t, usage.Pipelines)
+
+	// The key can't be retired during the overlap period
+	uri = router.GetRoute("POST", api.postRetireKeyInProjectHandler, vars)
+	req, err = http.NewRequest("POST", uri, nil)
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	// At the end of the overlap period the key is not given to the jobs anymore
+	oldKey, err := loadProjectKeyWithPrivateContent(db, proj.ID, k.Name)
+	test.NoError(t, err)
+	retireAt := time.Now().Add(-time.Minute)
+	oldKey.RetireAt = &retireAt
+	test.NoError(t, project.UpdateKey(context.TODO(), db, oldKey))
+
+	wr, err := workflow.CreateRun(db.DbMap, wf, sdk.WorkflowRunPostHandlerOption{AuthConsumerID: localConsumer.ID})
+	test.NoError(t, err)
+	test.NoError(t, saveWorkflowRunSecrets(context.TODO(), db.DbMap, proj.ID, *wr, &workflow.PushSecrets{}))
+	secrets, err := workflow.LoadDecryptSecrets(context.TODO(), db, wr, nil)
+	test.NoError(t, err)
+	assert.Nil(t, sdk.VariableFind(secrets, "cds.key."+k.Name+".priv"))
+	assert.NotNil(t, sdk.VariableFind(secrets, "cds.key."+successor.Name+".priv"))
+
+	// The key is still used by the workflow
+	req, err = http.NewRequest("POST", uri, nil)
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	req, err = http.NewRequest("POST", uri+"?force=true", nil)
+	test.NoError(t, err)
+	assets.AuthentifyRequest(t, req, u, pass)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 204, w.Code)
+
+	ks, err = project.LoadAllKeys(db, proj.ID)
+	test.NoError(t, err)
+	assert.Len(t, ks, 1)
+}
@@ -293,7 +293,7 @@ func Test_getProjectsHandler_AsProvider(t *testing.T) {
 	require.NoError(t, err)
 
 	_, jws, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), sdk.RandomString(10), 0, localConsumer, admin.GetGroupIDs(),
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject), nil, nil)
 	require.NoError(t, err)
 
 	u, _ := assets.InsertLambdaUser(t, db)
@@ -323,7 +323,7 @@ func Test_getprojectsHandler_AsProviderWithRequestedUsername(t *testing.T) {
 	require.NoError(t, err)
 
 	_, jws, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), sdk.RandomString(10), 0, localConsumer, admin.GetGroupIDs(),
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject), nil, nil)
 
 	u, _ := assets.InsertLambdaUser(t, db)
 
@@ -435,7 +435,7 @@ func Test_getProjectsHandler_FilterByRepo(t *testing.T) {
 	require.NoError(t, err)
 
 	_, jws, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), sdk.RandomString(10), 0, localConsumer, admin.GetGroupIDs(),
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject), nil, nil)
 
 	u, _ := assets.InsertLambdaUser(t, db)
 
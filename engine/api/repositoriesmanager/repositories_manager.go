@@ -586,6 +586,14 @@ func (c *vcsClient) ListStatuses(ctx context.Context, repo string, ref string) (
 	return statuses, nil
 }
 
+func (c *vcsClient) SetInsightReport(ctx context.Context, repo string, report sdk.VCSInsightReport) error {
+	path := fmt.Sprintf("/vcs/%s/repos/%s/commits/%s/reports", c.name, repo, report.Revision)
+	if _, err := c.doJSONRequest(ctx, "POST", path, report, nil); err != nil {
+		return sdk.WrapError(err, "unable to set insight report %s on repository %s from %s", report.Key, repo, c.name)
+	}
+	return nil
+}
+
 func (c *vcsClient) GrantWritePermission(ctx context.Context, repo string) error {
 	path := fmt.Sprintf("/vcs/%s/repos/%s/grant", c.name, repo)
 	if _, err := c.doJSONRequest(ctx, "POST", path, nil, nil); err != nil {
@@ -4,6 +4,7 @@ import (
 	"compress/gzip"
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"reflect"
 	"regexp"
@@ -54,6 +55,7 @@ type Router struct {
 	DefaultAuthMiddleware service.Middleware
 	PostAuthMiddlewares   []service.Middleware
 	PostMiddlewares       []service.Middleware
+	AuditFunc             func(ctx context.Context, req *http.Request, rc *service.HandlerConfig, statusCode int)
 	mapRouterConfigs      map[string]*service.RouterConfig
 	panicked              bool
 	nbPanic               int
@@ -352,6 +354,9 @@ func (r *Router) handle(uri string, scope HandlerScope, handlers ...*service.Han
 			clientIP = req.RemoteAddr
 		}
 
+		// The source ip is the one checked against consumer allowed networks, it can't be set by the client
+		ctx = context.WithValue(ctx, contextSourceIP, r.sourceIP(req))
+
 		// Prepare logging fields
 		ctx = context.WithValue(ctx, cdslog.Method, req.Method)
 		ctx = context.WithValue(ctx, cdslog.Route, cleanURL)
@@ -369,6 +374,11 @@ func (r *Router) handle(uri string, scope HandlerScope, handlers ...*service.Han
 			ctx = context.WithValue(ctx, f, v)
 		}
 
+		// Handlers can add data to the audit of the request
+		if r.AuditFunc != nil {
+			ctx = context.WithValue(ctx, contextAuditInfo, &auditInfo{target: sdk.AuditLogTarget{}})
+		}
+
 		// By default track all request as not sudo, TrackSudo will be enabled when required
 		SetTracker(responseWriter, cdslog.Sudo, false)
 
@@ -395,6 +405,11 @@ func (r *Router) handle(uri string, scope HandlerScope, handlers ...*service.Han
 				ctx = context.WithValue(ctx, k, v)
 			}
 
+			// The audit is done for all requests, including the ones rejected by middlewares
+			if r.AuditFunc != nil {
+				r.AuditFunc(ctx, req, rc, responseWriter.statusCode)
+			}
+
 			log.Info(ctx, "%s | END   | %s [%s] | [%d]", req.Method, req.URL, rc.Name, responseWriter.statusCode)
 
 			telemetry.RecordFloat64(ctx, ServerLatency, float64(latency)/float64(time.Millisecond))
@@ -526,6 +541,18 @@ func (r *Router) PUT(h service.HandlerFunc, cfg ...service.HandlerConfigParam) *
 	return &rc
 }
 
+// PATCH will set given handler only for PATCH request
+func (r *Router) PATCH(h service.HandlerFunc, cfg ...service.HandlerConfigParam) *service.HandlerConfig {
+	var rc service.HandlerConfig
+	rc.Handler = h()
+	rc.Method = "PATCH"
+	rc.PermissionLevel = sdk.PermissionReadWriteExecute
+	for _, c := range cfg {
+		c(&rc)
+	}
+	return &rc
+}
+
 // DELETE will set given handler only for DELETE request
 func (r *Router) DELETE(h service.HandlerFunc, cfg ...service.HandlerConfigParam) *service.HandlerConfig {
 	var rc service.HandlerConfig
@@ -546,6 +573,40 @@ func MaintenanceAware() service.HandlerConfigParam {
 	return f
 }
 
+// sourceIP returns the ip address of the client that sent the request. The forwarded header is only used if the
+// request comes from a trusted proxy, from right to left the first address that is not a trusted proxy is returned.
+func (r *Router) sourceIP(req *http.Request) net.IP {
+	var forwarded string
+	if r.Config.HeaderXForwardedFor != "" {
+		forwarded = strings.Join(req.Header.Values(r.Config.HeaderXForwardedFor), ",")
+	}
+	return requestSourceIP(req.RemoteAddr, forwarded, r.Config.TrustedProxies)
+}
+
+func requestSourceIP(remoteAddr, forwarded string, trustedProxies []string) net.IP {
+	isTrusted := func(ip net.IP) bool {
+		for _, s := range trustedProxies {
+			if _, n, err := net.ParseCIDR(s); err == nil && n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	ip := sdk.ParseRemoteIP(remoteAddr)
+	if ip == nil || forwarded == "" || !isTrusted(ip) {
+		return ip
+	}
+	addrs := strings.Split(forwarded, ",")
+	for i := len(addrs) - 1; i >= 0; i-- {
+		ip = sdk.ParseRemoteIP(addrs[i])
+		if ip == nil || !isTrusted(ip) {
+			return ip
+		}
+	}
+	return ip
+}
+
 // NotFoundHandler is called by default by Mux is any matching handler has been found
 func (r *Router) NotFoundHandler(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
@@ -8,4 +8,6 @@ const (
 	contextConsumer
 	contextDriverManifest
 	contextDate
+	contextAuditInfo
+	contextSourceIP
 )
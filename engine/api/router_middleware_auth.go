@@ -2,7 +2,9 @@ package api
 
 import (
 	"context"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
@@ -10,6 +12,7 @@ import (
 	"github.com/rockbears/log"
 
 	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/authentication/builtin"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/worker"
@@ -28,6 +31,14 @@ func (api *API) jwtMiddleware(ctx context.Context, w http.ResponseWriter, req *h
 	ctx, end := telemetry.Span(ctx, "router.jwtMiddleware")
 	defer end()
 
+	// SCIM routes are called with the signin token of a builtin consumer that is not a session JWT,
+	// it will be checked by the SCIM auth middleware
+	if isSCIMRoute(rc) {
+		if _, _, err := builtin.CheckSigninConsumerToken(strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")); err == nil {
+			return ctx, nil
+		}
+	}
+
 	return service.JWTMiddleware(ctx, w, req, rc, authentication.VerifyJWT)
 }
 
@@ -153,6 +164,9 @@ func (api *API) authOptionalMiddleware(ctx context.Context, w http.ResponseWrite
 	if consumer.Disabled {
 		return ctx, sdk.WrapError(sdk.ErrUnauthorized, "consumer (%s) is disabled", consumer.ID)
 	}
+	if err := checkConsumerAllowedIP(ctx, consumer); err != nil {
+		return ctx, err
+	}
 
 	// If the driver was disabled for the consumer that was found, ignore it
 	var driverManifest *sdk.AuthDriverManifest
@@ -223,6 +237,15 @@ func (api *API) authOptionalMiddleware(ctx context.Context, w http.ResponseWrite
 	return ctx, nil
 }
 
+// checkConsumerAllowedIP returns an error if the request doesn't come from a network allowed for the consumer.
+func checkConsumerAllowedIP(ctx context.Context, consumer *sdk.AuthConsumer) error {
+	sourceIP, _ := ctx.Value(contextSourceIP).(net.IP)
+	if !consumer.AllowedCIDRs.ContainsIP(sourceIP) {
+		return sdk.WrapError(sdk.ErrUnauthorized, "consumer (%s) is not allowed from %s", consumer.ID, sourceIP)
+	}
+	return nil
+}
+
 func (api *API) xsrfMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, error) {
 	ctx, end := telemetry.Span(ctx, "router.xsrfMiddleware")
 	defer end()
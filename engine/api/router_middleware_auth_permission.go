@@ -55,6 +55,16 @@ func (api *API) checkPermission(ctx context.Context, w http.ResponseWriter, rout
 	return nil
 }
 
+// isAllowedByConsumerRestrictions returns false if the consumer's resource restrictions don't allow the required
+// permission level on given project or workflow.
+func isAllowedByConsumerRestrictions(ctx context.Context, projectKey, workflowName string, requiredPerm int) bool {
+	c := getAPIConsumer(ctx)
+	if c == nil {
+		return true
+	}
+	return c.ResourceRestrictions.Level(projectKey, workflowName) >= requiredPerm
+}
+
 func (api *API) checkJobIDPermissions(ctx context.Context, w http.ResponseWriter, jobID string, perm int, routeVars map[string]string) error {
 	ctx, end := telemetry.Span(ctx, "api.checkJobIDPermissions")
 	defer end()
@@ -130,6 +140,15 @@ func (api *API) checkProjectPermissions(ctx context.Context, w http.ResponseWrit
 		return err
 	}
 
+	// If the consumer was restricted to some resources, even an admin can't go beyond the restrictions
+	if !isAllowedByConsumerRestrictions(ctx, projectKey, "", requiredPerm) {
+		log.Debug(ctx, "checkProjectPermissions> %s(%s) is restricted for %s", getAPIConsumer(ctx).Name, getAPIConsumer(ctx).ID, projectKey)
+		if requiredPerm == sdk.PermissionRead {
+			return sdk.WrapError(sdk.ErrNoProject, "not authorized for project %s", projectKey)
+		}
+		return sdk.WrapError(sdk.ErrForbidden, "not authorized for project %s", projectKey)
+	}
+
 	perms, err := permission.LoadProjectMaxLevelPermission(ctx, api.mustDB(), []string{projectKey}, getAPIConsumer(ctx).GetGroupIDs())
 	if err != nil {
 		return sdk.WrapError(err, "cannot get max project permissions for %s", projectKey)
@@ -200,6 +219,12 @@ func (api *API) checkWorkflowPermissions(ctx context.Context, w http.ResponseWri
 		return sdk.WithStack(sdk.ErrNotFound)
 	}
 
+	// If the consumer was restricted to some resources, even an admin can't go beyond the restrictions
+	if !isAllowedByConsumerRestrictions(ctx, projectKey, workflowName, perm) {
+		log.Debug(ctx, "checkWorkflowPermissions> %s is restricted for %s/%s", getAPIConsumer(ctx).ID, projectKey, workflowName)
+		return sdk.WrapError(sdk.ErrForbidden, "not authorized for workflow %s/%s", projectKey, workflowName)
+	}
+
 	perms, err := permission.LoadWorkflowMaxLevelPermission(ctx, api.mustDB(), projectKey, []string{workflowName}, getAPIConsumer(ctx).GetGroupIDs())
 	if err != nil {
 		return sdk.NewError(sdk.ErrForbidden, err)
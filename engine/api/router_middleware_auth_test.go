@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -57,7 +58,7 @@ func Test_authMiddleware_WithAuthConsumerDisabled(t *testing.T) {
 	require.NoError(t, err)
 
 	builtinConsumer, _, err := builtin.NewConsumer(context.TODO(), db, "builtin", "", 0, localConsumer, []int64{g.ID},
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopes...))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopes...), nil, nil)
 	require.NoError(t, err)
 	builtinSession, err := authentication.NewSession(context.TODO(), db, builtinConsumer, time.Second*5)
 	require.NoError(t, err)
@@ -214,7 +215,7 @@ func Test_authMiddleware_WithAuthConsumerScoped(t *testing.T) {
 		{
 			Scope: sdk.AuthConsumerScopeAdmin,
 		},
-	})
+	}, nil, nil)
 	require.NoError(t, err)
 	builtinSession, err := authentication.NewSession(context.TODO(), db, builtinConsumer, time.Second*5)
 	require.NoError(t, err)
@@ -286,3 +287,67 @@ func Test_authMiddleware_WithAuthConsumerScoped(t *testing.T) {
 	_, err = api.authMiddleware(ctx, w, req, configHandler5)
 	assert.NoError(t, err, "no error should be returned because consumer can access any routes for scope Admin")
 }
+
+func Test_authMiddleware_WithAuthConsumerRestricted(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	projA := assets.InsertTestProject(t, db, api.Cache, sdk.RandomString(10), sdk.RandomString(10))
+	projB := assets.InsertTestProject(t, db, api.Cache, sdk.RandomString(10), sdk.RandomString(10))
+	wfA1 := assets.InsertTestWorkflow(t, db, api.Cache, projA, sdk.RandomString(10))
+	assets.InsertTestWorkflow(t, db, api.Cache, projA, sdk.RandomString(10))
+	assets.InsertTestWorkflow(t, db, api.Cache, projB, sdk.RandomString(10))
+
+	u, _ := assets.InsertLambdaUser(t, db, &projA.ProjectGroups[0].Group, &projB.ProjectGroups[0].Group)
+	localConsumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	require.NoError(t, err)
+
+	// The consumer is restricted to a workflow of project A
+	builtinConsumer, _, err := builtin.NewConsumer(context.TODO(), db, "builtin", "", 0, localConsumer, nil,
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject, sdk.AuthConsumerScopeUser), sdk.AuthConsumerResourceRestrictions{
+			{ProjectKey: projA.Key, WorkflowName: wfA1.Name, Permission: sdk.PermissionReadWriteExecute},
+		}, nil)
+	require.NoError(t, err)
+	builtinSession, err := authentication.NewSession(context.TODO(), db, builtinConsumer, time.Minute)
+	require.NoError(t, err)
+	jwt, err := authentication.NewSessionJWT(builtinSession, "")
+	require.NoError(t, err)
+
+	// Project B is not reachable even if the user is a member of its group
+	uri := api.Router.GetRoute(http.MethodGet, api.getProjectHandler, map[string]string{"permProjectKey": projB.Key})
+	req := assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodGet, uri, nil)
+	w := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code, "restricted consumer should not access project B")
+
+	uri = api.Router.GetRoute(http.MethodGet, api.getProjectHandler, map[string]string{"permProjectKey": projA.Key})
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodGet, uri, nil)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "restricted consumer should read project A")
+
+	// Search only returns the allowed workflow
+	uri = api.Router.GetRoute(http.MethodGet, api.getSearchWorkflowHandler, nil)
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodGet, uri, nil)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var ws []sdk.Workflow
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &ws))
+	require.Len(t, ws, 1)
+	assert.Equal(t, wfA1.Name, ws[0].Name)
+
+	// Navbar hides the other projects and workflows
+	uri = api.Router.GetRoute(http.MethodGet, api.getNavbarHandler, nil)
+	req = assets.NewJWTAuthentifiedRequest(t, jwt, http.MethodGet, uri, nil)
+	w = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var data []sdk.NavbarProjectData
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &data))
+	for _, d := range data {
+		assert.Equal(t, projA.Key, d.Key)
+		if d.WorkflowName != "" {
+			assert.Equal(t, wfA1.Name, d.WorkflowName)
+		}
+	}
+}
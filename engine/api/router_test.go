@@ -54,3 +54,46 @@ func Test_computeScopeDetails(t *testing.T) {
 		}
 	}
 }
+
+func Test_sourceIP(t *testing.T) {
+	r := &Router{Config: service.HTTPRouterConfiguration{
+		HeaderXForwardedFor: "X-Forwarded-For",
+		TrustedProxies:      []string{"192.168.1.0/24"},
+	}}
+
+	newRequest := func(remoteAddr string, forwarded ...string) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		req.RemoteAddr = remoteAddr
+		for _, f := range forwarded {
+			req.Header.Add("X-Forwarded-For", f)
+		}
+		return req
+	}
+
+	// Forwarded header set by a client that is not a trusted proxy is ignored
+	assert.Equal(t, "203.0.113.5", r.sourceIP(newRequest("203.0.113.5:4242", "10.0.0.1")).String())
+	// From a trusted proxy the last untrusted address is the client, previous ones could have been set by the client
+	assert.Equal(t, "203.0.113.5", r.sourceIP(newRequest("192.168.1.2:4242", "10.0.0.1, 203.0.113.5")).String())
+	assert.Equal(t, "203.0.113.5", r.sourceIP(newRequest("192.168.1.2:4242", "10.0.0.1", "203.0.113.5, 192.168.1.3")).String())
+	assert.Equal(t, "192.168.1.2", r.sourceIP(newRequest("192.168.1.2:4242")).String())
+
+	r.Config.TrustedProxies = nil
+	assert.Equal(t, "192.168.1.2", r.sourceIP(newRequest("192.168.1.2:4242", "10.0.0.1")).String())
+}
+
+func Test_checkConsumerAllowedIPWithSpoofedHeader(t *testing.T) {
+	r := &Router{Config: service.HTTPRouterConfiguration{HeaderXForwardedFor: "X-Forwarded-For"}}
+	consumer := &sdk.AuthConsumer{ID: "consumer", AllowedCIDRs: sdk.AuthConsumerAllowedCIDRs{"10.0.0.0/8"}}
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	req.RemoteAddr = "203.0.113.5:4242"
+	req.Header.Set("X-Forwarded-For", "10.0.0.1")
+	ctx := context.WithValue(context.TODO(), contextSourceIP, r.sourceIP(req))
+	require.Error(t, checkConsumerAllowedIP(ctx, consumer), "spoofed forwarded header should not allow the consumer")
+
+	req.RemoteAddr = "10.1.2.3:4242"
+	ctx = context.WithValue(context.TODO(), contextSourceIP, r.sourceIP(req))
+	require.NoError(t, checkConsumerAllowedIP(ctx, consumer))
+}
@@ -0,0 +1,684 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/authentication/builtin"
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/scim"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	cdslog "github.com/ovh/cds/sdk/log"
+	"github.com/ovh/cds/sdk/telemetry"
+)
+
+const scimMaxResults = 200
+
+func (api *API) scimBaseURL() string {
+	return api.Config.URL.API + "/scim/v2"
+}
+
+// isSCIMRoute returns true for routes that accept only the SCIM scope.
+func isSCIMRoute(rc *service.HandlerConfig) bool {
+	return len(rc.AllowedScopes) == 1 && rc.AllowedScopes[0] == sdk.AuthConsumerScopeSCIM
+}
+
+// authSCIMMiddleware allows identity providers to call SCIM routes with the signin token of a builtin consumer.
+// The consumer should have the SCIM scope and belong to an admin, an admin session can also be used.
+func (api *API) authSCIMMiddleware(ctx context.Context, w http.ResponseWriter, req *http.Request, rc *service.HandlerConfig) (context.Context, error) {
+	ctx, end := telemetry.Span(ctx, "router.authSCIMMiddleware")
+	defer end()
+
+	// A session JWT was given, only admins are allowed
+	if _, ok := ctx.Value(service.ContextJWT).(*jwt.Token); ok {
+		return api.authAdminMiddleware(ctx, w, req, rc)
+	}
+
+	signinToken := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if signinToken == "" || signinToken == req.Header.Get("Authorization") {
+		return ctx, sdk.WithStack(sdk.ErrUnauthorized)
+	}
+	consumerID, _, err := builtin.CheckSigninConsumerToken(signinToken)
+	if err != nil {
+		return ctx, sdk.NewErrorWithStack(err, sdk.ErrUnauthorized)
+	}
+	consumer, err := authentication.LoadConsumerByID(ctx, api.mustDB(), consumerID,
+		authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	if err != nil {
+		return ctx, sdk.NewErrorWithStack(err, sdk.ErrUnauthorized)
+	}
+	if consumer.Type != sdk.ConsumerBuiltin {
+		return ctx, sdk.WrapError(sdk.ErrUnauthorized, "consumer (%s) is not a builtin consumer", consumer.ID)
+	}
+	if _, err := builtin.CheckSigninConsumerTokenIssuedAt(ctx, signinToken, consumer); err != nil {
+		return ctx, sdk.NewErrorWithStack(err, sdk.ErrUnauthorized)
+	}
+	if consumer.Disabled {
+		return ctx, sdk.WrapError(sdk.ErrUnauthorized, "consumer (%s) is disabled", consumer.ID)
+	}
+	if err := checkConsumerAllowedIP(ctx, consumer); err != nil {
+		return ctx, err
+	}
+
+	// The SCIM scope should be given explicitly, a consumer with all scopes is not allowed
+	var hasScope bool
+	for _, s := range consumer.ScopeDetails {
+		hasScope = hasScope || s.Scope == sdk.AuthConsumerScopeSCIM
+	}
+	if !hasScope {
+		return ctx, sdk.WrapError(sdk.ErrUnauthorized, "consumer (%s) doesn't have the %s scope", consumer.ID, sdk.AuthConsumerScopeSCIM)
+	}
+	if !consumer.Admin() {
+		return ctx, sdk.WithStack(sdk.ErrForbidden)
+	}
+
+	driver, ok := api.AuthenticationDrivers[consumer.Type]
+	if !ok {
+		return ctx, sdk.WrapError(sdk.ErrUnauthorized, "consumer driver (%s) was not found", consumer.Type)
+	}
+	m := driver.GetManifest()
+	ctx = context.WithValue(ctx, contextDriverManifest, &m)
+	ctx = context.WithValue(ctx, contextConsumer, consumer)
+
+	ctx = context.WithValue(ctx, cdslog.AuthUsername, consumer.AuthentifiedUser.Username)
+	SetTracker(w, cdslog.AuthUsername, consumer.AuthentifiedUser.Username)
+	ctx = context.WithValue(ctx, cdslog.AuthUserID, consumer.AuthentifiedUserID)
+	SetTracker(w, cdslog.AuthUserID, consumer.AuthentifiedUserID)
+	ctx = context.WithValue(ctx, cdslog.AuthConsumerID, consumer.ID)
+	SetTracker(w, cdslog.AuthConsumerID, consumer.ID)
+
+	return ctx, nil
+}
+
+// scimPage returns the page for startIndex and count query params, startIndex starts at 1.
+func scimPage(r *http.Request, total int) (int, int) {
+	startIndex := service.FormInt(r, "startIndex")
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count := scimMaxResults
+	if r.FormValue("count") != "" {
+		count = service.FormInt(r, "count")
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count > scimMaxResults {
+		count = scimMaxResults
+	}
+	if startIndex-1 > total {
+		startIndex = total + 1
+	}
+	if startIndex-1+count > total {
+		count = total - startIndex + 1
+	}
+	return startIndex, count
+}
+
+func (api *API) getSCIMServiceProviderConfigHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return service.WriteJSON(w, sdk.SCIMServiceProviderConfig{
+			Schemas:        []string{sdk.SCIMSchemaServiceProviderConfig},
+			Patch:          sdk.SCIMSupported{Supported: true},
+			Filter:         sdk.SCIMFilterSupported{Supported: true, MaxResults: scimMaxResults},
+			ChangePassword: sdk.SCIMSupported{},
+			AuthenticationSchemes: []sdk.SCIMAuthentication{{
+				Type:        "oauthbearertoken",
+				Name:        "OAuth Bearer Token",
+				Description: "Signin token of a CDS builtin consumer with the SCIM scope",
+			}},
+		}, http.StatusOK)
+	}
+}
+
+func (api *API) getSCIMUsersHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		filter, err := sdk.ParseSCIMFilter(r.FormValue("filter"))
+		if err != nil {
+			return err
+		}
+
+		var us sdk.AuthentifiedUsers
+		if filter != nil && strings.EqualFold(filter.Attribute, "userName") {
+			u, err := user.LoadByUsername(ctx, api.mustDB(), filter.Value, user.LoadOptions.WithContacts)
+			if err != nil && !sdk.ErrorIs(err, sdk.ErrUserNotFound) {
+				return err
+			}
+			if u != nil {
+				us = append(us, *u)
+			}
+		} else if filter != nil && strings.EqualFold(filter.Attribute, "externalId") {
+			l, err := scim.LoadUserLinkByExternalID(ctx, api.mustDB(), filter.Value)
+			if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return err
+			}
+			if l != nil {
+				u, err := user.LoadByID(ctx, api.mustDB(), l.AuthentifiedUserID, user.LoadOptions.WithContacts)
+				if err != nil {
+					return err
+				}
+				us = append(us, *u)
+			}
+		} else if filter != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported filter on attribute %s", filter.Attribute)
+		} else {
+			us, err = user.LoadAll(ctx, api.mustDB(), user.LoadOptions.WithContacts)
+			if err != nil {
+				return err
+			}
+		}
+
+		total := len(us)
+		startIndex, count := scimPage(r, total)
+		us = us[startIndex-1 : startIndex-1+count]
+
+		if err := api.scimLoadUsersGroups(ctx, us); err != nil {
+			return err
+		}
+
+		links, err := scim.LoadUserLinksByUserIDs(ctx, api.mustDB(), us.IDs())
+		if err != nil {
+			return err
+		}
+		mLinks := make(map[string]scim.UserLink, len(links))
+		for i := range links {
+			mLinks[links[i].AuthentifiedUserID] = links[i]
+		}
+
+		resources := make([]sdk.SCIMUser, 0, len(us))
+		for i := range us {
+			l, ok := mLinks[us[i].ID]
+			if !ok {
+				l = scim.UserLink{AuthentifiedUserID: us[i].ID, Active: true}
+			}
+			resources = append(resources, scim.NewUser(us[i], l, api.scimBaseURL()))
+		}
+
+		return service.WriteJSON(w, sdk.SCIMListResponse{
+			Schemas:      []string{sdk.SCIMSchemaListResponse},
+			TotalResults: total,
+			StartIndex:   startIndex,
+			ItemsPerPage: len(resources),
+			Resources:    resources,
+		}, http.StatusOK)
+	}
+}
+
+// scimLoadUsersGroups sets the groups of given users.
+func (api *API) scimLoadUsersGroups(ctx context.Context, us sdk.AuthentifiedUsers) error {
+	links, err := group.LoadLinksGroupUserForUserIDs(ctx, api.mustDB(), us.IDs())
+	if err != nil {
+		return err
+	}
+	groups, err := group.LoadAllByIDs(ctx, api.mustDB(), links.ToGroupIDs())
+	if err != nil {
+		return err
+	}
+	mGroups := groups.ToMap()
+	mUsers := make(map[string]*sdk.AuthentifiedUser, len(us))
+	for i := range us {
+		mUsers[us[i].ID] = &us[i]
+	}
+	for _, l := range links {
+		u, ok := mUsers[l.AuthentifiedUserID]
+		if !ok {
+			continue
+		}
+		if g, ok := mGroups[l.GroupID]; ok {
+			u.Groups = append(u.Groups, g)
+		}
+	}
+	return nil
+}
+
+func (api *API) writeSCIMUser(ctx context.Context, w http.ResponseWriter, userID string, status int) error {
+	u, err := user.LoadByID(ctx, api.mustDB(), userID, user.LoadOptions.WithContacts)
+	if err != nil {
+		return err
+	}
+	u.Groups, err = group.LoadAllByUserID(ctx, api.mustDB(), u.ID)
+	if err != nil {
+		return err
+	}
+	l, err := scim.LoadUserLinkByUserID(ctx, api.mustDB(), u.ID)
+	if err != nil {
+		return err
+	}
+	return service.WriteJSON(w, scim.NewUser(*u, *l, api.scimBaseURL()), status)
+}
+
+func (api *API) postSCIMUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var data sdk.SCIMUser
+		if err := service.UnmarshalBody(r, &data); err != nil {
+			return err
+		}
+		if err := data.IsValid(); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		existing, err := user.LoadByUsername(ctx, tx, data.UserName)
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrUserNotFound) {
+			return err
+		}
+		if existing != nil {
+			return sdk.NewErrorFrom(sdk.ErrConflictData, "a user already exists for username %s", data.UserName)
+		}
+		contact, err := user.LoadContactByTypeAndValue(ctx, tx, sdk.UserContactTypeEmail, data.PrimaryEmail())
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+		if contact != nil {
+			return sdk.NewErrorFrom(sdk.ErrConflictData, "a user already exists for email %s", data.PrimaryEmail())
+		}
+
+		u := sdk.AuthentifiedUser{
+			Ring:     sdk.UserRingUser,
+			Username: data.UserName,
+			Fullname: data.Fullname(),
+		}
+		if err := user.Insert(ctx, tx, &u); err != nil {
+			return err
+		}
+		if err := user.InsertContact(ctx, tx, &sdk.UserContact{
+			Primary:  true,
+			Type:     sdk.UserContactTypeEmail,
+			UserID:   u.ID,
+			Value:    data.PrimaryEmail(),
+			Verified: true,
+		}); err != nil {
+			return err
+		}
+		if err := group.CheckUserInDefaultGroup(ctx, tx, u.ID); err != nil {
+			return err
+		}
+
+		l := scim.UserLink{AuthentifiedUserID: u.ID, ExternalID: data.ExternalID, Active: true}
+		if err := scim.UpsertUserLink(ctx, tx, &l); err != nil {
+			return err
+		}
+		if err := scim.SetActive(ctx, tx, &l, data.IsActive()); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		return api.writeSCIMUser(ctx, w, u.ID, http.StatusCreated)
+	}
+}
+
+func (api *API) getSCIMUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		return api.writeSCIMUser(ctx, w, vars["scimUserID"], http.StatusOK)
+	}
+}
+
+// updateSCIMUser loads the user for given id then applies the changes returned by given func.
+func (api *API) updateSCIMUser(ctx context.Context, userID string, f func(current sdk.SCIMUser) (sdk.SCIMUser, error)) error {
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	u, err := user.LoadByID(ctx, tx, userID, user.LoadOptions.WithContacts)
+	if err != nil {
+		return err
+	}
+	l, err := scim.LoadUserLinkByUserID(ctx, tx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := f(scim.NewUser(*u, *l, api.scimBaseURL()))
+	if err != nil {
+		return err
+	}
+
+	// An admin can't deactivate itself
+	if !data.IsActive() && u.ID == getAPIConsumer(ctx).AuthentifiedUserID {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "can't deactivate the user used for SCIM provisioning")
+	}
+
+	if err := scim.UpdateUser(ctx, tx, u, l, data); err != nil {
+		return err
+	}
+
+	return sdk.WithStack(tx.Commit())
+}
+
+func (api *API) putSCIMUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		userID := vars["scimUserID"]
+
+		var data sdk.SCIMUser
+		if err := service.UnmarshalBody(r, &data); err != nil {
+			return err
+		}
+
+		if err := api.updateSCIMUser(ctx, userID, func(_ sdk.SCIMUser) (sdk.SCIMUser, error) {
+			return data, nil
+		}); err != nil {
+			return err
+		}
+
+		return api.writeSCIMUser(ctx, w, userID, http.StatusOK)
+	}
+}
+
+func (api *API) patchSCIMUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		userID := vars["scimUserID"]
+
+		var data sdk.SCIMPatchRequest
+		if err := service.UnmarshalBody(r, &data); err != nil {
+			return err
+		}
+
+		if err := api.updateSCIMUser(ctx, userID, func(current sdk.SCIMUser) (sdk.SCIMUser, error) {
+			return current, scim.ApplyUserPatch(&current, data.Operations)
+		}); err != nil {
+			return err
+		}
+
+		return api.writeSCIMUser(ctx, w, userID, http.StatusOK)
+	}
+}
+
+func (api *API) deleteSCIMUserHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		userID := vars["scimUserID"]
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		u, err := user.LoadByID(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		// We can't delete the last admin
+		if u.Ring == sdk.UserRingAdmin {
+			count, err := user.CountAdmin(tx)
+			if err != nil {
+				return err
+			}
+			if count < 2 {
+				return sdk.NewErrorFrom(sdk.ErrForbidden, "can't remove the last admin")
+			}
+		}
+
+		if err := user.DeleteByID(tx, u.ID); err != nil {
+			return sdk.WrapError(err, "cannot delete user")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		// No content is returned, the status will be set to 204 by the router
+		return nil
+	}
+}
+
+func (api *API) getSCIMGroupsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		filter, err := sdk.ParseSCIMFilter(r.FormValue("filter"))
+		if err != nil {
+			return err
+		}
+
+		var gs sdk.Groups
+		if filter != nil && strings.EqualFold(filter.Attribute, "displayName") {
+			g, err := group.LoadByName(ctx, api.mustDB(), filter.Value, group.LoadOptions.WithMembers)
+			if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return err
+			}
+			if g != nil {
+				gs = append(gs, *g)
+			}
+		} else if filter != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported filter on attribute %s", filter.Attribute)
+		} else {
+			gs, err = group.LoadAll(ctx, api.mustDB(), group.LoadOptions.WithMembers)
+			if err != nil {
+				return err
+			}
+		}
+
+		total := len(gs)
+		startIndex, count := scimPage(r, total)
+		gs = gs[startIndex-1 : startIndex-1+count]
+
+		resources := make([]sdk.SCIMGroup, 0, len(gs))
+		for i := range gs {
+			// Members are not returned when the identity provider asks to exclude them
+			if strings.EqualFold(r.FormValue("excludedAttributes"), "members") {
+				gs[i].Members = nil
+			}
+			resources = append(resources, scim.NewGroup(gs[i], api.scimBaseURL()))
+		}
+
+		return service.WriteJSON(w, sdk.SCIMListResponse{
+			Schemas:      []string{sdk.SCIMSchemaListResponse},
+			TotalResults: total,
+			StartIndex:   startIndex,
+			ItemsPerPage: len(resources),
+			Resources:    resources,
+		}, http.StatusOK)
+	}
+}
+
+func loadSCIMGroup(ctx context.Context, db gorpmapper.SqlExecutorWithTx, id string) (*sdk.Group, error) {
+	groupID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "invalid group id %s", id)
+	}
+	return group.LoadByID(ctx, db, groupID, group.LoadOptions.WithMembers)
+}
+
+func (api *API) writeSCIMGroup(ctx context.Context, w http.ResponseWriter, groupID int64, status int) error {
+	g, err := group.LoadByID(ctx, api.mustDB(), groupID, group.LoadOptions.WithMembers)
+	if err != nil {
+		return err
+	}
+	return service.WriteJSON(w, scim.NewGroup(*g, api.scimBaseURL()), status)
+}
+
+func (api *API) postSCIMGroupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var data sdk.SCIMGroup
+		if err := service.UnmarshalBody(r, &data); err != nil {
+			return err
+		}
+
+		newGroup := sdk.Group{Name: data.DisplayName}
+		if err := newGroup.IsValid(); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		existingGroup, err := group.LoadByName(ctx, tx, newGroup.Name)
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+		if existingGroup != nil {
+			return sdk.WithStack(sdk.ErrGroupPresent)
+		}
+
+		// Members are managed by the identity provider, so the caller is not added as group admin
+		if err := group.Insert(ctx, tx, &newGroup); err != nil {
+			return err
+		}
+		if err := scim.UpdateGroup(ctx, tx, &newGroup, data); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		return api.writeSCIMGroup(ctx, w, newGroup.ID, http.StatusCreated)
+	}
+}
+
+func (api *API) getSCIMGroupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		g, err := loadSCIMGroup(ctx, tx, vars["scimGroupID"])
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, scim.NewGroup(*g, api.scimBaseURL()), http.StatusOK)
+	}
+}
+
+// updateSCIMGroup loads the group for given id then applies the changes returned by given func.
+func (api *API) updateSCIMGroup(ctx context.Context, id string, f func(current sdk.SCIMGroup) (sdk.SCIMGroup, error)) (int64, error) {
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return 0, sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	g, err := loadSCIMGroup(ctx, tx, id)
+	if err != nil {
+		return 0, err
+	}
+	if group.IsDefaultGroupID(g.ID) {
+		return 0, sdk.NewErrorFrom(sdk.ErrForbidden, "can't update the default group")
+	}
+
+	data, err := f(scim.NewGroup(*g, api.scimBaseURL()))
+	if err != nil {
+		return 0, err
+	}
+	if err := scim.UpdateGroup(ctx, tx, g, data); err != nil {
+		return 0, err
+	}
+
+	return g.ID, sdk.WithStack(tx.Commit())
+}
+
+func (api *API) putSCIMGroupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		var data sdk.SCIMGroup
+		if err := service.UnmarshalBody(r, &data); err != nil {
+			return err
+		}
+
+		groupID, err := api.updateSCIMGroup(ctx, vars["scimGroupID"], func(_ sdk.SCIMGroup) (sdk.SCIMGroup, error) {
+			return data, nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return api.writeSCIMGroup(ctx, w, groupID, http.StatusOK)
+	}
+}
+
+func (api *API) patchSCIMGroupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		var data sdk.SCIMPatchRequest
+		if err := service.UnmarshalBody(r, &data); err != nil {
+			return err
+		}
+
+		groupID, err := api.updateSCIMGroup(ctx, vars["scimGroupID"], func(current sdk.SCIMGroup) (sdk.SCIMGroup, error) {
+			return current, scim.ApplyGroupPatch(&current, data.Operations)
+		})
+		if err != nil {
+			return err
+		}
+
+		return api.writeSCIMGroup(ctx, w, groupID, http.StatusOK)
+	}
+}
+
+func (api *API) deleteSCIMGroupHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		g, err := loadSCIMGroup(ctx, tx, vars["scimGroupID"])
+		if err != nil {
+			return err
+		}
+		if group.IsDefaultGroupID(g.ID) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "can't delete the default group")
+		}
+
+		projPerms, err := project.LoadPermissions(tx, g.ID)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load projects for group")
+		}
+
+		// Remove the group from all consumers
+		if err := authentication.ConsumerRemoveGroup(ctx, tx, g); err != nil {
+			return err
+		}
+		if err := group.Delete(ctx, tx, g); err != nil {
+			return sdk.WrapError(err, "cannot delete group")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		for _, pg := range projPerms {
+			event.PublishDeleteProjectPermission(ctx, &pg.Project, sdk.GroupPermission{Group: *g})
+		}
+
+		// No content is returned, the status will be set to 204 by the router
+		return nil
+	}
+}
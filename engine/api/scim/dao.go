@@ -0,0 +1,94 @@
+package scim
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func getAllUserLinks(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query) ([]UserLink, error) {
+	var ls []UserLink
+	if err := gorpmapping.GetAll(ctx, db, q, &ls); err != nil {
+		return nil, sdk.WrapError(err, "cannot get scim users")
+	}
+
+	verified := make([]UserLink, 0, len(ls))
+	for i := range ls {
+		isValid, err := gorpmapping.CheckSignature(ls[i], ls[i].Signature)
+		if err != nil {
+			return nil, err
+		}
+		if !isValid {
+			log.Error(ctx, "scim.getAllUserLinks> scim_user %s data corrupted", ls[i].AuthentifiedUserID)
+			continue
+		}
+		verified = append(verified, ls[i])
+	}
+	return verified, nil
+}
+
+// LoadUserLinksByUserIDs returns scim data for given user ids.
+func LoadUserLinksByUserIDs(ctx context.Context, db gorp.SqlExecutor, userIDs []string) ([]UserLink, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM scim_user
+    WHERE authentified_user_id = ANY(string_to_array($1, ','))
+  `).Args(gorpmapping.IDStringsToQueryString(userIDs))
+	return getAllUserLinks(ctx, db, query)
+}
+
+// LoadUserLinkByExternalID returns scim data for given identity provider id.
+func LoadUserLinkByExternalID(ctx context.Context, db gorp.SqlExecutor, externalID string) (*UserLink, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM scim_user
+    WHERE external_id = $1
+  `).Args(externalID)
+	ls, err := getAllUserLinks(ctx, db, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(ls) == 0 {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &ls[0], nil
+}
+
+// LoadUserLinkByUserID returns scim data for given user id, a default active link is returned for users that were not
+// provisioned by an identity provider.
+func LoadUserLinkByUserID(ctx context.Context, db gorp.SqlExecutor, userID string) (*UserLink, error) {
+	ls, err := LoadUserLinksByUserIDs(ctx, db, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(ls) == 0 {
+		return &UserLink{AuthentifiedUserID: userID, Active: true}, nil
+	}
+	return &ls[0], nil
+}
+
+// IsUserDeactivated returns true if the user was deactivated by an identity provider.
+func IsUserDeactivated(ctx context.Context, db gorp.SqlExecutor, userID string) (bool, error) {
+	l, err := LoadUserLinkByUserID(ctx, db, userID)
+	if err != nil {
+		return false, err
+	}
+	return !l.Active, nil
+}
+
+// UpsertUserLink inserts or updates given scim data.
+func UpsertUserLink(ctx context.Context, db gorpmapper.SqlExecutorWithTx, l *UserLink) error {
+	now := time.Now()
+	l.LastModified = now
+	if l.Created.IsZero() {
+		l.Created = now
+		return sdk.WrapError(gorpmapping.InsertAndSign(ctx, db, l), "unable to insert scim user %s", l.AuthentifiedUserID)
+	}
+	return sdk.WrapError(gorpmapping.UpdateAndSign(ctx, db, l), "unable to update scim user %s", l.AuthentifiedUserID)
+}
@@ -0,0 +1,29 @@
+package scim
+
+import (
+	"time"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/gorpmapper"
+)
+
+// UserLink struct for database entity of scim_user table, it stores data given by the identity provider for a user.
+type UserLink struct {
+	AuthentifiedUserID string    `db:"authentified_user_id"`
+	ExternalID         string    `db:"external_id"`
+	Active             bool      `db:"active"`
+	Created            time.Time `db:"created"`
+	LastModified       time.Time `db:"last_modified"`
+	gorpmapper.SignedEntity
+}
+
+func (l UserLink) Canonical() gorpmapper.CanonicalForms {
+	_ = []interface{}{l.AuthentifiedUserID, l.ExternalID, l.Active} // Checks that fields exists at compilation
+	return []gorpmapper.CanonicalForm{
+		"{{.AuthentifiedUserID}}{{.ExternalID}}{{print .Active}}",
+	}
+}
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(UserLink{}, "scim_user", false, "authentified_user_id"))
+}
@@ -0,0 +1,361 @@
+// Package scim maps SCIM 2.0 resources pushed by an identity provider to CDS users and groups.
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+// NewUser returns the SCIM resource for given user, contacts and groups should be loaded.
+func NewUser(u sdk.AuthentifiedUser, l UserLink, baseURL string) sdk.SCIMUser {
+	res := sdk.SCIMUser{
+		Schemas:     []string{sdk.SCIMSchemaUser},
+		ID:          u.ID,
+		ExternalID:  l.ExternalID,
+		UserName:    u.Username,
+		Name:        &sdk.SCIMName{Formatted: u.Fullname},
+		DisplayName: u.Fullname,
+		Active:      &l.Active,
+		Meta: &sdk.SCIMMeta{
+			ResourceType: "User",
+			Created:      &u.Created,
+			Location:     baseURL + "/Users/" + u.ID,
+		},
+	}
+	if !l.LastModified.IsZero() {
+		res.Meta.LastModified = &l.LastModified
+	}
+	for _, c := range u.Contacts {
+		if c.Type == sdk.UserContactTypeEmail {
+			res.Emails = append(res.Emails, sdk.SCIMEmail{Value: c.Value, Type: "work", Primary: c.Primary})
+		}
+	}
+	for _, g := range u.Groups {
+		id := strconv.FormatInt(g.ID, 10)
+		res.Groups = append(res.Groups, sdk.SCIMMember{Value: id, Display: g.Name, Ref: baseURL + "/Groups/" + id})
+	}
+	return res
+}
+
+// NewGroup returns the SCIM resource for given group, members should be loaded.
+func NewGroup(g sdk.Group, baseURL string) sdk.SCIMGroup {
+	id := strconv.FormatInt(g.ID, 10)
+	res := sdk.SCIMGroup{
+		Schemas:     []string{sdk.SCIMSchemaGroup},
+		ID:          id,
+		DisplayName: g.Name,
+		Members:     make([]sdk.SCIMMember, 0, len(g.Members)),
+		Meta: &sdk.SCIMMeta{
+			ResourceType: "Group",
+			Location:     baseURL + "/Groups/" + id,
+		},
+	}
+	for _, m := range g.Members {
+		res.Members = append(res.Members, sdk.SCIMMember{Value: m.ID, Display: m.Username, Ref: baseURL + "/Users/" + m.ID})
+	}
+	return res
+}
+
+// SetActive activates or deactivates a user, consumers and sessions of a deactivated user are revoked.
+func SetActive(ctx context.Context, db gorpmapper.SqlExecutorWithTx, l *UserLink, active bool) error {
+	if l.Active == active && !l.Created.IsZero() {
+		return nil
+	}
+	l.Active = active
+	if err := UpsertUserLink(ctx, db, l); err != nil {
+		return err
+	}
+	if active {
+		return authentication.ConsumerReactivateForUser(ctx, db, l.AuthentifiedUserID)
+	}
+	return authentication.ConsumerDeactivateForUser(ctx, db, l.AuthentifiedUserID)
+}
+
+// UpdateUser updates the user, its primary email and its scim data from given resource.
+func UpdateUser(ctx context.Context, db gorpmapper.SqlExecutorWithTx, u *sdk.AuthentifiedUser, l *UserLink, r sdk.SCIMUser) error {
+	if err := r.IsValid(); err != nil {
+		return err
+	}
+
+	if r.UserName != u.Username || r.Fullname() != u.Fullname {
+		if r.UserName != u.Username {
+			existing, err := user.LoadByUsername(ctx, db, r.UserName)
+			if err != nil && !sdk.ErrorIs(err, sdk.ErrUserNotFound) {
+				return err
+			}
+			if existing != nil {
+				return sdk.NewErrorFrom(sdk.ErrConflictData, "a user already exists for username %s", r.UserName)
+			}
+		}
+		u.Username = r.UserName
+		u.Fullname = r.Fullname()
+		if err := user.Update(ctx, db, u); err != nil {
+			return err
+		}
+	}
+
+	if err := setPrimaryEmail(ctx, db, u, r.PrimaryEmail()); err != nil {
+		return err
+	}
+
+	if l.ExternalID != r.ExternalID || l.Created.IsZero() {
+		l.ExternalID = r.ExternalID
+		if err := UpsertUserLink(ctx, db, l); err != nil {
+			return err
+		}
+	}
+
+	return SetActive(ctx, db, l, r.IsActive())
+}
+
+func setPrimaryEmail(ctx context.Context, db gorpmapper.SqlExecutorWithTx, u *sdk.AuthentifiedUser, email string) error {
+	contacts, err := user.LoadContactsByUserIDs(ctx, db, []string{u.ID})
+	if err != nil {
+		return err
+	}
+	for i := range contacts {
+		if contacts[i].Type == sdk.UserContactTypeEmail && contacts[i].Primary {
+			if contacts[i].Value == email {
+				return nil
+			}
+			contacts[i].Value = email
+			return user.UpdateContact(ctx, db, &contacts[i])
+		}
+	}
+	return user.InsertContact(ctx, db, &sdk.UserContact{
+		Primary:  true,
+		Type:     sdk.UserContactTypeEmail,
+		UserID:   u.ID,
+		Value:    email,
+		Verified: true,
+	})
+}
+
+// ApplyUserPatch applies patch operations on given user resource.
+// Unsupported attributes are ignored because identity providers send attributes that CDS doesn't store.
+func ApplyUserPatch(u *sdk.SCIMUser, ops []sdk.SCIMPatchOperation) error {
+	for _, op := range ops {
+		switch strings.ToLower(op.Op) {
+		case sdk.SCIMPatchOpAdd, sdk.SCIMPatchOpReplace:
+		default:
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported operation %s on user", op.Op)
+		}
+		if op.Path != "" {
+			if err := setUserAttribute(u, op.Path, op.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &values); err != nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid value for operation without path")
+		}
+		for k, v := range values {
+			if err := setUserAttribute(u, k, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var scimEmailValuePathRegexp = regexp.MustCompile(`(?i)^emails\[type eq "[a-z]+"\]\.value$`)
+
+func setUserAttribute(u *sdk.SCIMUser, path string, value json.RawMessage) error {
+	var err error
+	if u.Name == nil {
+		u.Name = &sdk.SCIMName{}
+	}
+	switch p := strings.ToLower(path); {
+	case p == "active":
+		active, err := sdk.SCIMBool(value)
+		if err != nil {
+			return err
+		}
+		u.Active = &active
+		return nil
+	case p == "username":
+		err = json.Unmarshal(value, &u.UserName)
+	case p == "displayname":
+		err = json.Unmarshal(value, &u.DisplayName)
+	case p == "externalid":
+		err = json.Unmarshal(value, &u.ExternalID)
+	case p == "name":
+		err = json.Unmarshal(value, u.Name)
+	case p == "name.formatted":
+		err = json.Unmarshal(value, &u.Name.Formatted)
+	case p == "name.givenname":
+		err = json.Unmarshal(value, &u.Name.GivenName)
+	case p == "name.familyname":
+		err = json.Unmarshal(value, &u.Name.FamilyName)
+	case p == "emails":
+		err = json.Unmarshal(value, &u.Emails)
+	case scimEmailValuePathRegexp.MatchString(p):
+		var email string
+		err = json.Unmarshal(value, &email)
+		u.Emails = []sdk.SCIMEmail{{Value: email, Type: "work", Primary: true}}
+	}
+	if err != nil {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid value for %s", path)
+	}
+	return nil
+}
+
+var scimMemberPathRegexp = regexp.MustCompile(`(?i)^members\[value eq "([^"]+)"\]$`)
+
+// ApplyGroupPatch applies patch operations on given group resource.
+func ApplyGroupPatch(g *sdk.SCIMGroup, ops []sdk.SCIMPatchOperation) error {
+	for _, op := range ops {
+		path := op.Path
+		value := op.Value
+		// Operation without path contains the attributes to replace
+		if path == "" {
+			var values map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid value for operation without path")
+			}
+			for k, v := range values {
+				if err := ApplyGroupPatch(g, []sdk.SCIMPatchOperation{{Op: op.Op, Path: k, Value: v}}); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if m := scimMemberPathRegexp.FindStringSubmatch(path); m != nil {
+			path = "members"
+			value, _ = json.Marshal([]sdk.SCIMMember{{Value: m[1]}})
+		}
+
+		switch strings.ToLower(path) {
+		case "displayname":
+			if err := json.Unmarshal(value, &g.DisplayName); err != nil {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid value for %s", op.Path)
+			}
+		case "externalid":
+			// Not stored
+		case "members":
+			var members []sdk.SCIMMember
+			if len(value) > 0 {
+				if err := json.Unmarshal(value, &members); err != nil {
+					return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid value for %s", op.Path)
+				}
+			}
+			switch strings.ToLower(op.Op) {
+			case sdk.SCIMPatchOpAdd:
+				g.Members = append(g.Members, members...)
+			case sdk.SCIMPatchOpReplace:
+				g.Members = members
+			case sdk.SCIMPatchOpRemove:
+				// Remove without value removes all members
+				if len(members) == 0 {
+					g.Members = nil
+					continue
+				}
+				filtered := make([]sdk.SCIMMember, 0, len(g.Members))
+				for _, gm := range g.Members {
+					var removed bool
+					for _, m := range members {
+						removed = removed || m.Value == gm.Value
+					}
+					if !removed {
+						filtered = append(filtered, gm)
+					}
+				}
+				g.Members = filtered
+			default:
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported operation %s on group", op.Op)
+			}
+		default:
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "unsupported path %s on group", op.Path)
+		}
+	}
+	return nil
+}
+
+// UpdateGroup renames the group and sets its members from given resource, members of given group should be loaded.
+func UpdateGroup(ctx context.Context, db gorpmapper.SqlExecutorWithTx, g *sdk.Group, r sdk.SCIMGroup) error {
+	if r.DisplayName != "" && r.DisplayName != g.Name {
+		newGroup := sdk.Group{ID: g.ID, Name: r.DisplayName}
+		if err := newGroup.IsValid(); err != nil {
+			return err
+		}
+		existing, err := group.LoadByName(ctx, db, newGroup.Name)
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+		if existing != nil {
+			return sdk.WithStack(sdk.ErrGroupPresent)
+		}
+		if err := group.Update(ctx, db, &newGroup); err != nil {
+			return err
+		}
+		g.Name = newGroup.Name
+	}
+
+	var wanted []string
+	for _, m := range r.Members {
+		if !sdk.IsInArray(m.Value, wanted) {
+			wanted = append(wanted, m.Value)
+		}
+	}
+
+	var existing []string
+	for _, m := range g.Members {
+		existing = append(existing, m.ID)
+		if sdk.IsInArray(m.ID, wanted) {
+			continue
+		}
+		if err := removeMember(ctx, db, g, m.ID); err != nil {
+			return err
+		}
+	}
+	for _, id := range wanted {
+		if sdk.IsInArray(id, existing) {
+			continue
+		}
+		if err := addMember(ctx, db, g, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func addMember(ctx context.Context, db gorpmapper.SqlExecutorWithTx, g *sdk.Group, userID string) error {
+	u, err := user.LoadByID(ctx, db, userID)
+	if err != nil {
+		return sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid member %s", userID))
+	}
+	if err := group.InsertLinkGroupUser(ctx, db, &group.LinkGroupUser{
+		GroupID:            g.ID,
+		AuthentifiedUserID: u.ID,
+	}); err != nil {
+		return err
+	}
+	return authentication.ConsumerRestoreInvalidatedGroupForUser(ctx, db, g.ID, u.ID)
+}
+
+func removeMember(ctx context.Context, db gorpmapper.SqlExecutorWithTx, g *sdk.Group, userID string) error {
+	link, err := group.LoadLinkGroupUserForGroupIDAndUserID(ctx, db, g.ID, userID)
+	if err != nil {
+		return err
+	}
+	if err := group.DeleteLinkGroupUser(db, link); err != nil {
+		return err
+	}
+	u, err := user.LoadByID(ctx, db, userID)
+	if err != nil {
+		return err
+	}
+	return authentication.ConsumerInvalidateGroupForUser(ctx, db, g, u)
+}
@@ -0,0 +1,60 @@
+package scim_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/scim"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestApplyUserPatch(t *testing.T) {
+	u := sdk.SCIMUser{
+		UserName: "john.doe",
+		Emails:   []sdk.SCIMEmail{{Value: "john.doe@example.com", Primary: true}},
+	}
+
+	assert.True(t, u.IsActive())
+
+	var ops []sdk.SCIMPatchOperation
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"op": "Replace", "path": "active", "value": "False"},
+		{"op": "replace", "path": "emails[type eq \"work\"].value", "value": "jdoe@example.com"},
+		{"op": "add", "value": {"displayName": "John Doe", "title": "Developer"}}
+	]`), &ops))
+	require.NoError(t, scim.ApplyUserPatch(&u, ops))
+
+	assert.False(t, u.IsActive())
+	assert.Equal(t, "jdoe@example.com", u.PrimaryEmail())
+	assert.Equal(t, "John Doe", u.Fullname())
+	assert.Equal(t, "john.doe", u.UserName)
+
+	require.Error(t, scim.ApplyUserPatch(&u, []sdk.SCIMPatchOperation{{Op: "remove", Path: "active"}}))
+}
+
+func TestApplyGroupPatch(t *testing.T) {
+	g := sdk.SCIMGroup{
+		DisplayName: "team-a",
+		Members:     []sdk.SCIMMember{{Value: "1"}, {Value: "2"}},
+	}
+
+	var ops []sdk.SCIMPatchOperation
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"op": "add", "path": "members", "value": [{"value": "3"}, {"value": "4"}]},
+		{"op": "remove", "path": "members[value eq \"1\"]"},
+		{"op": "Remove", "path": "members", "value": [{"value": "4"}]},
+		{"op": "replace", "value": {"displayName": "team-b"}}
+	]`), &ops))
+	require.NoError(t, scim.ApplyGroupPatch(&g, ops))
+
+	assert.Equal(t, "team-b", g.DisplayName)
+	assert.Equal(t, []sdk.SCIMMember{{Value: "2"}, {Value: "3"}}, g.Members)
+
+	require.NoError(t, scim.ApplyGroupPatch(&g, []sdk.SCIMPatchOperation{{Op: "remove", Path: "members"}}))
+	assert.Len(t, g.Members, 0)
+
+	require.Error(t, scim.ApplyGroupPatch(&g, []sdk.SCIMPatchOperation{{Op: "add", Path: "owners"}}))
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/authentication/builtin"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+// newSCIMConsumerToken returns the signin token of a builtin consumer with given scopes for given user.
+func newSCIMConsumerToken(t *testing.T, db gorpmapper.SqlExecutorWithTx, u *sdk.AuthentifiedUser, scopes ...sdk.AuthConsumerScope) string {
+	localConsumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	require.NoError(t, err)
+	_, jws, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), sdk.RandomString(10), time.Hour, localConsumer, u.GetGroupIDs(),
+		sdk.NewAuthConsumerScopeDetails(scopes...), nil, nil)
+	require.NoError(t, err)
+	return jws
+}
+
+func doSCIMRequest(t *testing.T, api *API, token, method, uri string, body interface{}) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req, err := http.NewRequest(method, uri, &buf)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/scim+json")
+	rec := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func Test_authSCIMMiddleware(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	admin, _ := assets.InsertAdminUser(t, db)
+	lambda, _ := assets.InsertLambdaUser(t, db)
+	uri := api.Router.GetRoute(http.MethodGet, api.getSCIMServiceProviderConfigHandler, nil)
+	require.NotEmpty(t, uri)
+
+	rec := doSCIMRequest(t, api, newSCIMConsumerToken(t, db, admin, sdk.AuthConsumerScopeSCIM), http.MethodGet, uri, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	// The SCIM scope should be given explicitly
+	rec = doSCIMRequest(t, api, newSCIMConsumerToken(t, db, admin, sdk.AuthConsumerScopeUser), http.MethodGet, uri, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, rec.Body.String())
+
+	// Only admins can provision users and groups
+	rec = doSCIMRequest(t, api, newSCIMConsumerToken(t, db, lambda, sdk.AuthConsumerScopeSCIM), http.MethodGet, uri, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	rec = doSCIMRequest(t, api, "invalid", http.MethodGet, uri, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, rec.Body.String())
+}
+
+func Test_postSCIMUserHandler(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	admin, _ := assets.InsertAdminUser(t, db)
+	token := newSCIMConsumerToken(t, db, admin, sdk.AuthConsumerScopeSCIM)
+	uri := api.Router.GetRoute(http.MethodPost, api.postSCIMUserHandler, nil)
+	require.NotEmpty(t, uri)
+
+	username := sdk.RandomString(10)
+	rec := doSCIMRequest(t, api, token, http.MethodPost, uri, sdk.SCIMUser{
+		Schemas:    []string{sdk.SCIMSchemaUser},
+		ExternalID: "ext-" + username,
+		UserName:   username,
+		Emails:     []sdk.SCIMEmail{{Value: username + "@example.com", Primary: true}},
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created sdk.SCIMUser
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, username, created.UserName)
+	assert.Equal(t, "ext-"+username, created.ExternalID)
+	require.NotNil(t, created.Active)
+	assert.True(t, *created.Active, "a user without active attribute should be active")
+
+	// Username should be unique
+	rec = doSCIMRequest(t, api, token, http.MethodPost, uri, sdk.SCIMUser{
+		UserName: username,
+		Emails:   []sdk.SCIMEmail{{Value: sdk.RandomString(10) + "@example.com"}},
+	})
+	assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+}
+
+func Test_getSCIMUsersHandler(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	admin, _ := assets.InsertAdminUser(t, db)
+	token := newSCIMConsumerToken(t, db, admin, sdk.AuthConsumerScopeSCIM)
+	g := assets.InsertGroup(t, db)
+	lambda, _ := assets.InsertLambdaUser(t, db, g)
+
+	uri := api.Router.GetRoute(http.MethodGet, api.getSCIMUsersHandler, nil)
+	require.NotEmpty(t, uri)
+
+	rec := doSCIMRequest(t, api, token, http.MethodGet, uri+`?filter=userName+eq+"`+lambda.Username+`"`, nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var res struct {
+		TotalResults int            `json:"totalResults"`
+		Resources    []sdk.SCIMUser `json:"Resources"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Equal(t, 1, res.TotalResults)
+	require.Len(t, res.Resources, 1)
+	assert.Equal(t, lambda.ID, res.Resources[0].ID)
+	var groupNames []string
+	for _, m := range res.Resources[0].Groups {
+		groupNames = append(groupNames, m.Display)
+	}
+	assert.Contains(t, groupNames, g.Name, "groups should be loaded when listing users")
+
+	rec = doSCIMRequest(t, api, token, http.MethodGet, uri+`?filter=title+eq+"developer"`, nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+}
+
+func Test_patchSCIMUserHandlerDeactivate(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	admin, _ := assets.InsertAdminUser(t, db)
+	token := newSCIMConsumerToken(t, db, admin, sdk.AuthConsumerScopeSCIM)
+	g := assets.InsertGroup(t, db)
+	lambda, _ := assets.InsertLambdaUser(t, db, g)
+
+	localConsumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, lambda.ID)
+	require.NoError(t, err)
+	session, err := authentication.NewSession(context.TODO(), db, localConsumer, time.Hour)
+	require.NoError(t, err)
+
+	uri := api.Router.GetRoute(http.MethodPatch, api.patchSCIMUserHandler, map[string]string{"scimUserID": lambda.ID})
+	require.NotEmpty(t, uri)
+	var ops []sdk.SCIMPatchOperation
+	require.NoError(t, json.Unmarshal([]byte(`[{"op": "replace", "path": "active", "value": false}]`), &ops))
+	rec := doSCIMRequest(t, api, token, http.MethodPatch, uri, sdk.SCIMPatchRequest{
+		Schemas:    []string{sdk.SCIMSchemaPatchOp},
+		Operations: ops,
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var updated sdk.SCIMUser
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.False(t, updated.IsActive())
+
+	// Consumers of a deactivated user are disabled and its sessions revoked
+	localConsumer, err = authentication.LoadConsumerByID(context.TODO(), db, localConsumer.ID)
+	require.NoError(t, err)
+	assert.True(t, localConsumer.Disabled)
+	assert.True(t, localConsumer.Warnings.Contains(sdk.WarningUserDeactivated))
+	_, err = authentication.LoadSessionByID(context.TODO(), db, session.ID)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrNotFound), "session should be revoked")
+
+	// Group membership is kept to restore permissions on reactivation
+	links, err := group.LoadLinksGroupUserForUserIDs(context.TODO(), db, []string{lambda.ID})
+	require.NoError(t, err)
+	assert.NotEmpty(t, links)
+
+	require.NoError(t, json.Unmarshal([]byte(`[{"op": "replace", "value": {"active": true}}]`), &ops))
+	rec = doSCIMRequest(t, api, token, http.MethodPatch, uri, sdk.SCIMPatchRequest{
+		Schemas:    []string{sdk.SCIMSchemaPatchOp},
+		Operations: ops,
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	localConsumer, err = authentication.LoadConsumerByID(context.TODO(), db, localConsumer.ID)
+	require.NoError(t, err)
+	assert.False(t, localConsumer.Disabled)
+}
@@ -0,0 +1,31 @@
+package secret
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertAudit for secret reference in database.
+func InsertAudit(db gorp.SqlExecutor, a *sdk.AuditSecretReference) error {
+	return sdk.WrapError(gorpmapping.Insert(db, a), "unable to insert audit for secret reference %s", a.Path)
+}
+
+// LoadAuditsByProjectIntegrationID returns latest secret reference audits for an integration.
+func LoadAuditsByProjectIntegrationID(ctx context.Context, db gorp.SqlExecutor, projectIntegrationID int64, limit int64) ([]sdk.AuditSecretReference, error) {
+	var as []sdk.AuditSecretReference
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM secret_reference_audit
+    WHERE project_integration_id = $1
+    ORDER BY created DESC
+    LIMIT $2
+  `).Args(projectIntegrationID, limit)
+	if err := gorpmapping.GetAll(ctx, db, query, &as); err != nil {
+		return nil, sdk.WrapError(err, "cannot get secret reference audits")
+	}
+	return as, nil
+}
@@ -0,0 +1,10 @@
+package secret
+
+import (
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(sdk.AuditSecretReference{}, "secret_reference_audit", true, "id"))
+}
@@ -0,0 +1,146 @@
+package secret
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/integration"
+	"github.com/ovh/cds/sdk"
+)
+
+// JobInfo identifies the job that reads secret references.
+type JobInfo struct {
+	ProjectID     int64
+	WorkflowRunID int64
+	JobID         int64
+	WorkerName    string
+}
+
+// Resolve replaces secret references in password variables by their values read from given integration.
+// Variables that can't be resolved are removed and an error is returned as a spawn info.
+func Resolve(ctx context.Context, integ sdk.ProjectIntegration, reader Reader, job JobInfo, secrets []sdk.Variable) ([]sdk.Variable, []sdk.AuditSecretReference, []sdk.SpawnInfo) {
+	var audits []sdk.AuditSecretReference
+	var infos []sdk.SpawnInfo
+	res := make([]sdk.Variable, 0, len(secrets))
+	for _, s := range secrets {
+		if s.Type != sdk.SecretVariable || !sdk.IsSecretReference(s.Value) {
+			res = append(res, s)
+			continue
+		}
+
+		audit := sdk.AuditSecretReference{
+			AuditCommon: sdk.AuditCommon{
+				TriggeredBy: job.WorkerName,
+				Created:     time.Now(),
+				EventType:   sdk.AuditSecretReferenceRead,
+			},
+			ProjectID:            job.ProjectID,
+			ProjectIntegrationID: integ.ID,
+			WorkflowRunID:        job.WorkflowRunID,
+			WorkflowNodeJobRunID: job.JobID,
+			VariableName:         s.Name,
+			Path:                 s.Value,
+		}
+
+		value, err := read(reader, s.Value)
+		if err != nil {
+			log.Warn(ctx, "secret.Resolve> unable to resolve variable %s for job %d: %v", s.Name, job.JobID, err)
+			audit.EventType = sdk.AuditSecretReferenceError
+			audits = append(audits, audit)
+			infos = append(infos, spawnInfo(s.Name, err))
+			continue
+		}
+		audits = append(audits, audit)
+
+		s.Value = value
+		res = append(res, s)
+	}
+	return res, audits, infos
+}
+
+func read(reader Reader, value string) (string, error) {
+	if reader == nil {
+		return "", sdk.NewErrorFrom(sdk.ErrNotFound, "no secret integration found in project")
+	}
+	ref, err := sdk.ParseSecretReference(value)
+	if err != nil {
+		return "", err
+	}
+	return reader.Read(ref)
+}
+
+// errorReader fails to read any reference when no reader can be built for the project.
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read(sdk.SecretReference) (string, error) {
+	return "", r.err
+}
+
+func spawnInfo(name string, err error) sdk.SpawnInfo {
+	msg := sdk.SpawnMsg{
+		ID:   sdk.MsgSpawnInfoSecretReferenceError.ID,
+		Args: []interface{}{name, sdk.ExtractHTTPError(err).Error()},
+		Type: sdk.MsgSpawnInfoSecretReferenceError.Type,
+	}
+	return sdk.SpawnInfo{
+		APITime:     time.Now(),
+		RemoteTime:  time.Now(),
+		Message:     msg,
+		UserMessage: msg.DefaultUserMessage(),
+	}
+}
+
+// ResolveJobSecrets resolves secret references of a job with the secret integration of its project,
+// reads are audited. Resolved values are only returned and must not be stored, a spawn info is returned for each
+// reference that can't be resolved.
+func ResolveJobSecrets(ctx context.Context, db gorp.SqlExecutor, job JobInfo, secrets []sdk.Variable) ([]sdk.Variable, []sdk.SpawnInfo, error) {
+	var hasReference bool
+	for _, s := range secrets {
+		if s.Type == sdk.SecretVariable && sdk.IsSecretReference(s.Value) {
+			hasReference = true
+			break
+		}
+	}
+	if !hasReference {
+		return secrets, nil, nil
+	}
+
+	integs, err := integration.LoadIntegrationsByProjectIDWithClearPassword(db, job.ProjectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var secretIntegs []sdk.ProjectIntegration
+	for i := range integs {
+		if integs[i].Model.Secret {
+			secretIntegs = append(secretIntegs, integs[i])
+		}
+	}
+
+	var integ sdk.ProjectIntegration
+	var reader Reader
+	switch len(secretIntegs) {
+	case 0:
+	case 1:
+		integ = secretIntegs[0]
+		reader, err = NewVaultReader(integ)
+		if err != nil {
+			log.Warn(ctx, "secret.ResolveJobSecrets> %v", err)
+			reader = errorReader{err: err}
+		}
+	default:
+		reader = errorReader{err: sdk.NewErrorFrom(sdk.ErrWrongRequest, "%d secret integrations found in project, only one is allowed", len(secretIntegs))}
+	}
+
+	res, audits, infos := Resolve(ctx, integ, reader, job, secrets)
+	for i := range audits {
+		if err := InsertAudit(db, &audits[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return res, infos, nil
+}
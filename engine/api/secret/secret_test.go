@@ -0,0 +1,100 @@
+package secret_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/secret"
+	"github.com/ovh/cds/sdk"
+)
+
+// newVaultServer returns a stand-in for a Vault dev server with a KV version 2 and a KV version 1 secrets engines.
+func newVaultServer(t *testing.T, token string) *httptest.Server {
+	secrets := map[string]interface{}{
+		"/v1/kv/data/team/db": map[string]interface{}{
+			"data":     map[string]interface{}{"password": "db-password"},
+			"metadata": map[string]interface{}{"version": 3},
+		},
+		"/v1/secret/team/api": map[string]interface{}{
+			"token": "api-token",
+		},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		data, ok := secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"data": data}))
+	}))
+}
+
+func TestResolve(t *testing.T) {
+	srv := newVaultServer(t, "my-token")
+	defer srv.Close()
+
+	integ := sdk.ProjectIntegration{
+		ID:    1,
+		Name:  "vault",
+		Model: sdk.VaultIntegration,
+		Config: sdk.IntegrationConfig{
+			sdk.VaultConfigURL:   {Value: srv.URL},
+			sdk.VaultConfigToken: {Value: "my-token", Type: sdk.IntegrationConfigTypePassword},
+		},
+	}
+	reader, err := secret.NewVaultReader(integ)
+	require.NoError(t, err)
+
+	job := secret.JobInfo{ProjectID: 1, WorkflowRunID: 2, JobID: 3, WorkerName: "my-worker"}
+	secrets := []sdk.Variable{
+		{Name: "cds.proj.db", Type: sdk.SecretVariable, Value: "vault://kv/data/team/db#password"},
+		{Name: "cds.app.api", Type: sdk.SecretVariable, Value: "vault://secret/team/api#token"},
+		{Name: "cds.env.static", Type: sdk.SecretVariable, Value: "static-password"},
+		{Name: "cds.env.missing", Type: sdk.SecretVariable, Value: "vault://kv/data/team/unknown#password"},
+		{Name: "cds.env.nokey", Type: sdk.SecretVariable, Value: "vault://kv/data/team/db"},
+	}
+
+	res, audits, infos := secret.Resolve(context.TODO(), integ, reader, job, secrets)
+	require.Len(t, res, 3)
+	assert.Equal(t, "db-password", res[0].Value)
+	assert.Equal(t, "api-token", res[1].Value)
+	assert.Equal(t, "static-password", res[2].Value)
+
+	require.Len(t, infos, 2)
+	assert.Equal(t, sdk.MsgSpawnInfoSecretReferenceError.ID, infos[0].Message.ID)
+	assert.Contains(t, infos[0].UserMessage, "cds.env.missing")
+	assert.Contains(t, infos[1].UserMessage, "cds.env.nokey")
+
+	require.Len(t, audits, 4)
+	assert.Equal(t, sdk.AuditSecretReferenceRead, audits[0].EventType)
+	assert.Equal(t, "vault://kv/data/team/db#password", audits[0].Path)
+	assert.Equal(t, "my-worker", audits[0].TriggeredBy)
+	assert.Equal(t, int64(3), audits[0].WorkflowNodeJobRunID)
+	assert.Equal(t, sdk.AuditSecretReferenceError, audits[2].EventType)
+
+	// With a wrong token all references fail
+	integ.Config[sdk.VaultConfigToken] = sdk.IntegrationConfigValue{Value: "wrong-token"}
+	reader, err = secret.NewVaultReader(integ)
+	require.NoError(t, err)
+	res, _, infos = secret.Resolve(context.TODO(), integ, reader, job, secrets[:1])
+	assert.Len(t, res, 0)
+	assert.Len(t, infos, 1)
+
+	// Without integration, references can't be resolved
+	res, _, infos = secret.Resolve(context.TODO(), sdk.ProjectIntegration{}, nil, job, secrets[:1])
+	assert.Len(t, res, 0)
+	require.Len(t, infos, 1)
+	assert.Contains(t, infos[0].UserMessage, "no secret integration found")
+}
@@ -0,0 +1,62 @@
+// Package secret resolves password variables that reference secrets stored in an external secret manager.
+package secret
+
+import (
+	"fmt"
+	"strings"
+
+	vault "github.com/hashicorp/vault/api"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Reader reads the value of a secret reference.
+type Reader interface {
+	Read(ref sdk.SecretReference) (string, error)
+}
+
+type vaultReader struct {
+	client *vault.Client
+}
+
+// NewVaultReader returns a reader for a project integration that uses the Vault model, the config should be decrypted.
+func NewVaultReader(integ sdk.ProjectIntegration) (Reader, error) {
+	cfg := vault.DefaultConfig()
+	cfg.Address = integ.Config[sdk.VaultConfigURL].Value
+	if cfg.Address == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing url in integration %s", integ.Name)
+	}
+	client, err := vault.NewClient(cfg)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to create vault client for integration %s", integ.Name)
+	}
+	// Do not read VAULT_TOKEN from the API environment
+	client.SetToken(integ.Config[sdk.VaultConfigToken].Value)
+	if ns := integ.Config[sdk.VaultConfigNamespace].Value; ns != "" {
+		client.SetNamespace(ns)
+	}
+	return &vaultReader{client: client}, nil
+}
+
+// Read returns the value for given reference, KV version 1 and 2 secrets engines are supported.
+func (v *vaultReader) Read(ref sdk.SecretReference) (string, error) {
+	s, err := v.client.Logical().Read(ref.Path)
+	if err != nil {
+		return "", sdk.NewErrorFrom(sdk.ErrUnknownError, "unable to read secret %s: %v", ref.Path, err)
+	}
+	if s == nil || s.Data == nil {
+		return "", sdk.NewErrorFrom(sdk.ErrNotFound, "no secret found at %s", ref.Path)
+	}
+
+	data := s.Data
+	// With KV version 2, values are stored in a data field
+	if d, ok := s.Data["data"].(map[string]interface{}); ok && strings.Contains(ref.Path, "/data/") {
+		data = d
+	}
+
+	value, ok := data[ref.Key]
+	if !ok || value == nil {
+		return "", sdk.NewErrorFrom(sdk.ErrNotFound, "no key %s found in secret %s", ref.Key, ref.Path)
+	}
+	return fmt.Sprintf("%v", value), nil
+}
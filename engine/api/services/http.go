@@ -333,3 +333,46 @@ func doRequestFromURL(ctx context.Context, method string, callURL *url.URL, read
 
 	return nil, resp.Header, resp.StatusCode, sdk.WithStack(fmt.Errorf("request failed with status code: %d", resp.StatusCode))
 }
+
+// StreamHTTPClient is used for requests that stream their response, it has no global timeout.
+var StreamHTTPClient cdsclient.HTTPClient
+
+// DoStreamRequest performs a signed http request on a service and returns the response, the caller must close its body.
+func DoStreamRequest(ctx context.Context, srv sdk.Service, method, path string, body io.Reader, header http.Header) (*http.Response, error) {
+	if StreamHTTPClient == nil {
+		StreamHTTPClient = cdsclient.NewHTTPClient(0, false)
+	}
+	if HTTPSigner == nil {
+		HTTPSigner = httpsig.NewRSASHA256Signer(authentication.GetIssuerName(), authentication.GetSigningKey(), []string{"(request-target)", "host", "date"})
+	}
+
+	callURL, err := url.ParseRequestURI(srv.HTTPURL + path)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	req, err := http.NewRequest(method, callURL.String(), body)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	requestID := cdslog.ContextValue(ctx, cdslog.RequestID)
+	if requestID != "" {
+		req.Header.Set(cdslog.HeaderRequestID, requestID)
+	}
+
+	// Sign the http request with API private RSA Key
+	if err := HTTPSigner.Sign(req); err != nil {
+		return nil, sdk.WrapError(err, "request signature failed")
+	}
+
+	resp, err := StreamHTTPClient.Do(req)
+	if err != nil {
+		return nil, sdk.WrapError(err, "request failed")
+	}
+	return resp, nil
+}
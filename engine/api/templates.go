@@ -109,7 +109,11 @@ func (api *API) postTemplateHandler() service.Handler {
 		}
 
 		// execute template with no instance only to check if parsing is ok
-		if _, err := workflowtemplate.Parse(data); err != nil {
+		resolved, err := workflowtemplate.ResolveDependencies(ctx, api.mustDB(), data)
+		if err != nil {
+			return err
+		}
+		if _, err := workflowtemplate.Parse(*resolved); err != nil {
 			return err
 		}
 
@@ -255,7 +259,11 @@ func (api *API) putTemplateHandler() service.Handler {
 		clone.Update(data)
 
 		// execute template with no instance only to check if golang template parsing is ok
-		if _, err := workflowtemplate.Parse(clone); err != nil {
+		resolved, err := workflowtemplate.ResolveDependencies(ctx, tx, clone)
+		if err != nil {
+			return err
+		}
+		if _, err := workflowtemplate.Parse(*resolved); err != nil {
 			return err
 		}
 
@@ -394,6 +402,23 @@ func (api *API) postTemplateApplyHandler() service.Handler {
 			},
 		}
 
+		// the generated workflow can be pinned to a range of template releases
+		if versionRange := FormString(r, "version"); versionRange != "" {
+			if _, err := sdk.ParseWorkflowTemplateVersionRange(versionRange); err != nil {
+				return err
+			}
+			data.Template.From = wt.Path() + "@" + versionRange
+		} else if !req.Detached {
+			// without given range, keep the range of releases that the existing instance is pinned to
+			wti, err := workflowtemplate.LoadInstanceByTemplateIDAndProjectIDAndRequestWorkflowName(ctx, api.mustDB(), wt.ID, p.ID, req.WorkflowName)
+			if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return err
+			}
+			if wti != nil && wti.VersionRange != "" {
+				data.Template.From = wt.Path() + "@" + wti.VersionRange
+			}
+		}
+
 		if !withImport && !req.Detached {
 			buf := new(bytes.Buffer)
 			if err := exportentities.TarWorkflowComponents(ctx, data, buf); err != nil {
@@ -437,7 +462,7 @@ func (api *API) postTemplateApplyHandler() service.Handler {
 					}
 					defer tx.Rollback() // nolint
 
-					ope, err := operation.PushOperationUpdate(ctx, tx, api.Cache, *p, data, rootApp.VCSServer, rootApp.RepositoryFullname, branch, message, rootApp.RepositoryStrategy, consumer)
+					ope, err := operation.PushOperationUpdate(ctx, tx, api.Cache, *p, data, rootApp.VCSServer, rootApp.RepositoryFullname, rootApp.FromRepository, branch, message, rootApp.RepositoryStrategy, consumer)
 					if err != nil {
 						return err
 					}
@@ -642,6 +667,10 @@ func (api *API) postTemplateBulkHandler() service.Handler {
 						}
 						continue
 					}
+					// keep the range of releases that the existing instance is pinned to
+					if wti != nil && wti.VersionRange != "" {
+						data.Template.From = wt.Path() + "@" + wti.VersionRange
+					}
 					if wti != nil && wti.WorkflowID != nil {
 						existingWorkflow, err := workflow.LoadByID(ctx, api.mustDB(), api.Cache, *p, *wti.WorkflowID, workflow.LoadOptions{})
 						if err != nil {
@@ -687,7 +716,7 @@ func (api *API) postTemplateBulkHandler() service.Handler {
 								}
 								continue
 							}
-							ope, err := operation.PushOperationUpdate(ctx, tx, api.Cache, *p, data, rootApp.VCSServer, rootApp.RepositoryFullname, branch, message, rootApp.RepositoryStrategy, consumer)
+							ope, err := operation.PushOperationUpdate(ctx, tx, api.Cache, *p, data, rootApp.VCSServer, rootApp.RepositoryFullname, rootApp.FromRepository, branch, message, rootApp.RepositoryStrategy, consumer)
 							if err != nil {
 								tx.Rollback() // nolint
 								if errD := errorDefer(err); errD != nil {
@@ -1057,6 +1086,23 @@ func (api *API) getTemplateUsageHandler() service.Handler {
 			return sdk.WrapError(err, "cannot load templates")
 		}
 
+		// templates that extend or import the given one are also impacted by its changes
+		withTemplates := service.FormBool(r, "withTemplates")
+		var dependents []sdk.WorkflowTemplate
+		if withTemplates {
+			dependents, err = workflowtemplate.LoadDependents(ctx, api.mustDB(), *wt)
+			if err != nil {
+				return err
+			}
+			for i := range dependents {
+				dwfs, err := workflow.LoadByWorkflowTemplateID(ctx, api.mustDB(), dependents[i].ID)
+				if err != nil {
+					return sdk.WrapError(err, "cannot load workflows for template %s", dependents[i].Path())
+				}
+				wfs = append(wfs, dwfs...)
+			}
+		}
+
 		if !isMaintainer(ctx) {
 			consumer := getAPIConsumer(ctx)
 
@@ -1077,8 +1123,284 @@ func (api *API) getTemplateUsageHandler() service.Handler {
 				}
 			}
 			wfs = filteredWorkflow
+
+			// filter dependent templates by user's groups
+			filteredTemplates := []sdk.WorkflowTemplate{}
+			for i := range dependents {
+				if dependents[i].GroupID == group.SharedInfraGroup.ID || sdk.IsInInt64Array(dependents[i].GroupID, consumer.GetGroupIDs()) {
+					filteredTemplates = append(filteredTemplates, dependents[i])
+				}
+			}
+			dependents = filteredTemplates
+		}
+
+		if withTemplates {
+			if dependents == nil {
+				dependents = []sdk.WorkflowTemplate{}
+			}
+			return service.WriteJSON(w, sdk.WorkflowTemplateUsage{
+				Workflows: wfs,
+				Templates: dependents,
+			}, http.StatusOK)
 		}
 
 		return service.WriteJSON(w, wfs, http.StatusOK)
 	}
 }
+
+func (api *API) getTemplateReleasesHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		groupName := vars["permGroupName"]
+		templateSlug := vars["permTemplateSlug"]
+
+		g, err := group.LoadByName(ctx, api.mustDB(), groupName)
+		if err != nil {
+			return err
+		}
+
+		wt, err := workflowtemplate.LoadBySlugAndGroupID(ctx, api.mustDB(), templateSlug, g.ID)
+		if err != nil {
+			return err
+		}
+
+		rs, err := workflowtemplate.LoadReleasesByTemplateID(ctx, api.mustDB(), wt.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, rs, http.StatusOK)
+	}
+}
+
+func (api *API) postTemplateReleaseHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		groupName := vars["permGroupName"]
+		templateSlug := vars["permTemplateSlug"]
+
+		g, err := group.LoadByName(ctx, api.mustDB(), groupName, group.LoadOptions.WithMembers)
+		if err != nil {
+			return err
+		}
+
+		if !isGroupAdmin(ctx, g) {
+			if isAdmin(ctx) {
+				trackSudo(ctx, w)
+			} else {
+				return sdk.WithStack(sdk.ErrForbidden)
+			}
+		}
+
+		wt, err := workflowtemplate.LoadBySlugAndGroupID(ctx, api.mustDB(), templateSlug, g.ID)
+		if err != nil {
+			return err
+		}
+
+		var data sdk.WorkflowTemplateRelease
+		if err := service.UnmarshalBody(r, &data); err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		rel, err := workflowtemplate.CreateRelease(ctx, tx, *wt, data.Version, data.Changelog, getAPIConsumer(ctx))
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		return service.WriteJSON(w, rel, http.StatusOK)
+	}
+}
+
+func (api *API) postTemplateInstanceUpgradeHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		groupName := vars["groupName"]
+		templateSlug := vars["templateSlug"]
+
+		g, err := group.LoadByName(ctx, api.mustDB(), groupName, group.LoadOptions.WithMembers)
+		if err != nil {
+			return err
+		}
+		if !(isGroupMember(ctx, g) || isMaintainer(ctx)) {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+
+		wt, err := workflowtemplate.LoadBySlugAndGroupID(ctx, api.mustDB(), templateSlug, g.ID, workflowtemplate.LoadOptions.Default)
+		if err != nil {
+			return err
+		}
+
+		var ps []sdk.Project
+		if isMaintainer(ctx) {
+			ps, err = project.LoadAll(ctx, api.mustDB(), api.Cache)
+		} else {
+			ps, err = project.LoadAllByGroupIDs(ctx, api.mustDB(), api.Cache, getAPIConsumer(ctx).GetGroupIDs())
+		}
+		if err != nil {
+			return err
+		}
+
+		instanceID, err := requestVarInt(r, "instanceID")
+		if err != nil {
+			return err
+		}
+
+		wti, err := workflowtemplate.LoadInstanceByIDForTemplateIDAndProjectIDs(ctx, api.mustDB(), instanceID, wt.ID, sdk.ProjectsToIDs(ps))
+		if err != nil {
+			return err
+		}
+		if wti == nil {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no workflow template instance found")
+		}
+
+		var req sdk.WorkflowTemplateUpgradeRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+		if req.VersionRange == "" {
+			req.VersionRange = wti.VersionRange
+		}
+		if req.VersionRange == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing version range for template instance upgrade")
+		}
+
+		res, target, err := workflowtemplate.ComputeUpgrade(ctx, api.mustDB(), *wt, *wti, req.VersionRange)
+		if err != nil {
+			return err
+		}
+		if req.DryRun {
+			return service.WriteJSON(w, res, http.StatusOK)
+		}
+		if wti.WorkflowID == nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "template instance is not linked to a workflow")
+		}
+
+		var projectKey string
+		for i := range ps {
+			if ps[i].ID == wti.ProjectID {
+				projectKey = ps[i].Key
+				break
+			}
+		}
+
+		consumer := getAPIConsumer(ctx)
+		if err := api.checkProjectPermissions(ctx, w, projectKey, sdk.PermissionReadWriteExecute, nil); err != nil {
+			if !isAdmin(ctx) {
+				return sdk.NewErrorFrom(sdk.ErrForbidden, "write permission on project required to upgrade template instance.")
+			}
+			trackSudo(ctx, w)
+		}
+
+		p, err := project.Load(ctx, api.mustDB(), projectKey,
+			project.LoadOptions.WithGroups,
+			project.LoadOptions.WithApplications,
+			project.LoadOptions.WithEnvironments,
+			project.LoadOptions.WithPipelines,
+			project.LoadOptions.WithApplicationWithDeploymentStrategies,
+			project.LoadOptions.WithIntegrations,
+			project.LoadOptions.WithClearKeys,
+		)
+		if err != nil {
+			return err
+		}
+
+		existingWorkflow, err := workflow.LoadByID(ctx, api.mustDB(), api.Cache, *p, *wti.WorkflowID, workflow.LoadOptions{})
+		if err != nil {
+			return err
+		}
+
+		data := exportentities.WorkflowComponents{
+			Template: exportentities.TemplateInstance{
+				Name:       wti.Request.WorkflowName,
+				From:       wt.Path() + "@" + req.VersionRange,
+				Parameters: wti.Request.Parameters,
+			},
+		}
+
+		// For as code workflows the upgrade is proposed with a pull request on the workflow repository
+		if existingWorkflow.FromRepository != "" {
+			if existingWorkflow.WorkflowData.Node.Context == nil || existingWorkflow.WorkflowData.Node.Context.ApplicationID == 0 {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "cannot find the root application of the workflow")
+			}
+			rootApp, err := application.LoadByIDWithClearVCSStrategyPassword(api.mustDB(), existingWorkflow.WorkflowData.Node.Context.ApplicationID)
+			if err != nil {
+				return err
+			}
+
+			if req.Branch == "" {
+				req.Branch = fmt.Sprintf("cds-template-%s-%s", wt.Slug, target.Version)
+			}
+			if req.Message == "" {
+				req.Message = fmt.Sprintf("Upgrade workflow template %s from %s to %s", wt.Path(), res.FromVersion, target.Version)
+			}
+
+			tx, err := api.mustDB().Begin()
+			if err != nil {
+				return sdk.WithStack(err)
+			}
+			defer tx.Rollback() // nolint
+
+			ope, err := operation.PushOperationUpdate(ctx, tx, api.Cache, *p, data, rootApp.VCSServer, rootApp.RepositoryFullname, rootApp.FromRepository, req.Branch, req.Message, rootApp.RepositoryStrategy, consumer)
+			if err != nil {
+				return err
+			}
+
+			if err := tx.Commit(); err != nil {
+				return sdk.WithStack(err)
+			}
+
+			api.GoRoutines.Exec(context.Background(), fmt.Sprintf("UpdateAsCodeResult-%s", ope.UUID), func(ctx context.Context) {
+				ed := ascode.EntityData{
+					Name:          existingWorkflow.Name,
+					ID:            existingWorkflow.ID,
+					Type:          ascode.WorkflowEvent,
+					FromRepo:      existingWorkflow.FromRepository,
+					OperationUUID: ope.UUID,
+				}
+				ascode.UpdateAsCodeResult(ctx, api.mustDB(), api.Cache, api.GoRoutines, *p, *existingWorkflow, *rootApp, ed, consumer)
+			})
+
+			res.Operation = &sdk.Operation{
+				UUID:   ope.UUID,
+				Status: ope.Status,
+			}
+			return service.WriteJSON(w, res, http.StatusOK)
+		}
+
+		_, newInstance, err := workflowtemplate.CheckAndExecuteTemplate(ctx, api.mustDB(), api.Cache, *consumer, *p, &data,
+			workflowtemplate.TemplateRequestModifiers.DefaultKeys(*p))
+		if err != nil {
+			return err
+		}
+
+		_, wkf, oldWkf, _, err := workflow.Push(ctx, api.mustDB(), api.Cache, p, data, nil, consumer, project.DecryptWithBuiltinKey)
+		if err != nil {
+			return sdk.WrapError(err, "cannot push generated workflow")
+		}
+		if err := workflowtemplate.UpdateTemplateInstanceWithWorkflow(ctx, api.mustDB(), *wkf, *consumer, newInstance); err != nil {
+			return err
+		}
+
+		if oldWkf != nil {
+			event.PublishWorkflowUpdate(ctx, p.Key, *wkf, *oldWkf, consumer)
+		} else {
+			event.PublishWorkflowAdd(ctx, p.Key, *wkf, consumer)
+		}
+
+		res.Applied = true
+		return service.WriteJSON(w, res, http.StatusOK)
+	}
+}
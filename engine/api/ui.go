@@ -23,6 +23,18 @@ func (api *API) getNavbarHandler() service.Handler {
 		if err != nil {
 			return err
 		}
+
+		// Hide the resources that are not allowed by the consumer's resource restrictions
+		if len(consumer.ResourceRestrictions) > 0 {
+			filtered := make([]sdk.NavbarProjectData, 0, len(data))
+			for i := range data {
+				if consumer.ResourceRestrictions.Level(data[i].Key, data[i].WorkflowName) >= sdk.PermissionRead {
+					filtered = append(filtered, data[i])
+				}
+			}
+			data = filtered
+		}
+
 		return service.WriteJSON(w, data, http.StatusOK)
 	}
 }
@@ -0,0 +1,188 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func getNotificationSubscriptions(ctx context.Context, db gorp.SqlExecutor, q gorpmapping.Query, opts ...gorpmapping.GetOptionFunc) ([]sdk.UserNotificationSubscription, error) {
+	ss := []userNotificationSubscription{}
+	if err := gorpmapping.GetAll(ctx, db, q, &ss, opts...); err != nil {
+		return nil, sdk.WrapError(err, "cannot get user notification subscriptions")
+	}
+
+	// Check signature of data, if invalid do not return it
+	verified := make([]sdk.UserNotificationSubscription, 0, len(ss))
+	for i := range ss {
+		isValid, err := gorpmapping.CheckSignature(ss[i], ss[i].Signature)
+		if err != nil {
+			return nil, err
+		}
+		if !isValid {
+			log.Error(ctx, "user.getNotificationSubscriptions> user notification subscription %d data corrupted", ss[i].ID)
+			continue
+		}
+		verified = append(verified, ss[i].UserNotificationSubscription)
+	}
+	return verified, nil
+}
+
+// LoadNotificationSubscriptionsByUserID returns the notification subscriptions of a user, without webhook urls.
+func LoadNotificationSubscriptionsByUserID(ctx context.Context, db gorp.SqlExecutor, userID string) ([]sdk.UserNotificationSubscription, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT *
+		FROM user_notification_subscription
+		WHERE authentified_user_id = $1
+		ORDER BY id ASC
+	`).Args(userID)
+	return getNotificationSubscriptions(ctx, db, query)
+}
+
+// LoadNotificationSubscriptionByID returns a notification subscription of a user, with its webhook url.
+func LoadNotificationSubscriptionByID(ctx context.Context, db gorp.SqlExecutor, userID string, id int64) (*sdk.UserNotificationSubscription, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT *
+		FROM user_notification_subscription
+		WHERE authentified_user_id = $1 AND id = $2
+	`).Args(userID, id)
+	ss, err := getNotificationSubscriptions(ctx, db, query, gorpmapping.GetOptions.WithDecryption)
+	if err != nil {
+		return nil, err
+	}
+	if len(ss) == 0 {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &ss[0], nil
+}
+
+// LoadNotificationSubscriptionsByProjectKey returns the subscriptions that could match a workflow run of given project,
+// with their webhook urls.
+func LoadNotificationSubscriptionsByProjectKey(ctx context.Context, db gorp.SqlExecutor, projectKey string) ([]sdk.UserNotificationSubscription, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT *
+		FROM user_notification_subscription
+		WHERE project_key = $1 OR project_key = ''
+		ORDER BY id ASC
+	`).Args(projectKey)
+	return getNotificationSubscriptions(ctx, db, query, gorpmapping.GetOptions.WithDecryption)
+}
+
+// digestDueCondition matches the digest subscriptions whose period is over and that have pending events,
+// the period of a subscription that never sent a digest starts at its creation.
+const digestDueCondition = `(
+			(mode = $1 AND COALESCE(last_digest, created) <= $3 - interval '1 hour')
+			OR (mode = $2 AND COALESCE(last_digest, created) <= $3 - interval '1 day')
+		)
+		AND EXISTS (SELECT 1 FROM user_notification_event WHERE subscription_id = user_notification_subscription.id)`
+
+// LoadNotificationSubscriptionIDsWithDigestDue returns the ids of the digest subscriptions whose period is over
+// and that have pending events.
+func LoadNotificationSubscriptionIDsWithDigestDue(db gorp.SqlExecutor, now time.Time) ([]int64, error) {
+	var ids []int64
+	if _, err := db.Select(&ids, `
+		SELECT id
+		FROM user_notification_subscription
+		WHERE `+digestDueCondition+`
+		ORDER BY id ASC
+	`, sdk.UserSubscriptionModeHourly, sdk.UserSubscriptionModeDaily, now); err != nil {
+		return nil, sdk.WrapError(err, "cannot load notification subscriptions with digest due")
+	}
+	return ids, nil
+}
+
+// LoadAndLockNotificationSubscriptionWithDigestDue returns the digest subscription for given id if its period
+// is still over, the row is locked until the end of the transaction. A subscription locked by another transaction
+// is skipped and a not found error is returned.
+func LoadAndLockNotificationSubscriptionWithDigestDue(ctx context.Context, db gorp.SqlExecutor, id int64, now time.Time) (*sdk.UserNotificationSubscription, error) {
+	query := gorpmapping.NewQuery(`
+		SELECT *
+		FROM user_notification_subscription
+		WHERE id = $4 AND `+digestDueCondition+`
+		FOR UPDATE SKIP LOCKED
+	`).Args(sdk.UserSubscriptionModeHourly, sdk.UserSubscriptionModeDaily, now, id)
+	ss, err := getNotificationSubscriptions(ctx, db, query, gorpmapping.GetOptions.WithDecryption)
+	if err != nil {
+		return nil, err
+	}
+	if len(ss) == 0 {
+		return nil, sdk.WithStack(sdk.ErrNotFound)
+	}
+	return &ss[0], nil
+}
+
+// InsertNotificationSubscription in database.
+func InsertNotificationSubscription(ctx context.Context, db gorpmapper.SqlExecutorWithTx, s *sdk.UserNotificationSubscription) error {
+	s.Created = time.Now()
+	s.LastDigest = s.Created
+	dbs := userNotificationSubscription{UserNotificationSubscription: *s}
+	if err := gorpmapping.InsertAndSign(ctx, db, &dbs); err != nil {
+		return sdk.WrapError(err, "unable to insert notification subscription for user %s", s.AuthentifiedUserID)
+	}
+	*s = dbs.UserNotificationSubscription
+	return nil
+}
+
+// UpdateNotificationSubscription in database.
+func UpdateNotificationSubscription(ctx context.Context, db gorpmapper.SqlExecutorWithTx, s *sdk.UserNotificationSubscription) error {
+	dbs := userNotificationSubscription{UserNotificationSubscription: *s}
+	if err := gorpmapping.UpdateAndSign(ctx, db, &dbs); err != nil {
+		return err
+	}
+	*s = dbs.UserNotificationSubscription
+	return nil
+}
+
+// UpdateNotificationSubscriptionLastDigest sets the date of the last digest sent for a subscription.
+func UpdateNotificationSubscriptionLastDigest(db gorp.SqlExecutor, id int64, lastDigest time.Time) error {
+	_, err := db.Exec("UPDATE user_notification_subscription SET last_digest = $1 WHERE id = $2", lastDigest, id)
+	return sdk.WithStack(err)
+}
+
+// DeleteNotificationSubscription in database, pending events are deleted with it.
+func DeleteNotificationSubscription(db gorp.SqlExecutor, s sdk.UserNotificationSubscription) error {
+	dbs := userNotificationSubscription{UserNotificationSubscription: s}
+	return sdk.WrapError(gorpmapping.Delete(db, &dbs), "unable to delete notification subscription %d", s.ID)
+}
+
+// InsertNotificationEvent stores an event until the next digest of its subscription.
+func InsertNotificationEvent(db gorp.SqlExecutor, e *sdk.UserNotificationEvent) error {
+	e.Created = time.Now()
+	dbe := userNotificationEvent{UserNotificationEvent: *e}
+	if err := gorpmapping.Insert(db, &dbe); err != nil {
+		return sdk.WrapError(err, "unable to insert notification event for subscription %d", e.SubscriptionID)
+	}
+	*e = dbe.UserNotificationEvent
+	return nil
+}
+
+// LoadNotificationEventsBySubscriptionID returns the pending events of a subscription.
+func LoadNotificationEventsBySubscriptionID(ctx context.Context, db gorp.SqlExecutor, subscriptionID int64) ([]sdk.UserNotificationEvent, error) {
+	var es []userNotificationEvent
+	query := gorpmapping.NewQuery(`
+		SELECT *
+		FROM user_notification_event
+		WHERE subscription_id = $1
+		ORDER BY id ASC
+	`).Args(subscriptionID)
+	if err := gorpmapping.GetAll(ctx, db, query, &es); err != nil {
+		return nil, sdk.WrapError(err, "cannot get notification events")
+	}
+	res := make([]sdk.UserNotificationEvent, len(es))
+	for i := range es {
+		res[i] = es[i].UserNotificationEvent
+	}
+	return res, nil
+}
+
+// DeleteNotificationEventsBySubscriptionID removes the events sent in a digest.
+func DeleteNotificationEventsBySubscriptionID(db gorp.SqlExecutor, subscriptionID int64, maxID int64) error {
+	_, err := db.Exec("DELETE FROM user_notification_event WHERE subscription_id = $1 AND id <= $2", subscriptionID, maxID)
+	return sdk.WithStack(err)
+}
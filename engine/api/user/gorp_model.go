@@ -28,7 +28,24 @@ func (c userContact) Canonical() gorpmapper.CanonicalForms {
 	}
 }
 
+type userNotificationSubscription struct {
+	sdk.UserNotificationSubscription
+	gorpmapper.SignedEntity
+}
+
+func (s userNotificationSubscription) Canonical() gorpmapper.CanonicalForms {
+	return []gorpmapper.CanonicalForm{
+		"{{.ID}}{{.AuthentifiedUserID}}{{.ProjectKey}}{{.WorkflowName}}{{.Branch}}{{.TriggeredByMe}}{{.Channel}}{{.ChatType}}{{.Mode}}",
+	}
+}
+
+type userNotificationEvent struct {
+	sdk.UserNotificationEvent
+}
+
 func init() {
 	gorpmapping.Register(gorpmapping.New(authentifiedUser{}, "authentified_user", false, "id"))
 	gorpmapping.Register(gorpmapping.New(userContact{}, "user_contact", true, "id"))
+	gorpmapping.Register(gorpmapping.New(userNotificationSubscription{}, "user_notification_subscription", true, "id"))
+	gorpmapping.Register(gorpmapping.New(userNotificationEvent{}, "user_notification_event", true, "id"))
 }
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/notification"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) loadUserForNotificationSubscription(ctx context.Context, r *http.Request) (*sdk.AuthentifiedUser, error) {
+	username := mux.Vars(r)["permUsername"]
+	if username == "me" {
+		return user.LoadByID(ctx, api.mustDB(), getAPIConsumer(ctx).AuthentifiedUserID)
+	}
+	return user.LoadByUsername(ctx, api.mustDB(), username)
+}
+
+func (api *API) getUserNotificationSubscriptionsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u, err := api.loadUserForNotificationSubscription(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		subs, err := user.LoadNotificationSubscriptionsByUserID(ctx, api.mustDB(), u.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, subs, http.StatusOK)
+	}
+}
+
+func (api *API) postUserNotificationSubscriptionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		u, err := api.loadUserForNotificationSubscription(ctx, r)
+		if err != nil {
+			return err
+		}
+		if u.ID != getAPIConsumer(ctx).AuthentifiedUserID {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "a user can't subscribe to notifications for someone else")
+		}
+
+		var s sdk.UserNotificationSubscription
+		if err := service.UnmarshalBody(r, &s); err != nil {
+			return err
+		}
+		s.AuthentifiedUserID = u.ID
+		if err := s.IsValid(); err != nil {
+			return err
+		}
+		if s.ProjectKey != "" {
+			if err := api.checkProjectPermissions(ctx, w, s.ProjectKey, sdk.PermissionRead, nil); err != nil {
+				return err
+			}
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		if err := user.InsertNotificationSubscription(ctx, tx, &s); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		s.WebhookURL = ""
+		return service.WriteJSON(w, s, http.StatusCreated)
+	}
+}
+
+func (api *API) putUserNotificationSubscriptionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "subscriptionID")
+		if err != nil {
+			return err
+		}
+		u, err := api.loadUserForNotificationSubscription(ctx, r)
+		if err != nil {
+			return err
+		}
+		if u.ID != getAPIConsumer(ctx).AuthentifiedUserID {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "a user can't update the subscriptions of someone else")
+		}
+
+		old, err := user.LoadNotificationSubscriptionByID(ctx, api.mustDB(), u.ID, id)
+		if err != nil {
+			return err
+		}
+
+		var s sdk.UserNotificationSubscription
+		if err := service.UnmarshalBody(r, &s); err != nil {
+			return err
+		}
+		s.ID = old.ID
+		s.AuthentifiedUserID = old.AuthentifiedUserID
+		s.Created = old.Created
+		s.LastDigest = old.LastDigest
+		// The webhook url is never returned by the API, keep the stored one if not given
+		if s.WebhookURL == "" && s.Channel == old.Channel {
+			s.WebhookURL = old.WebhookURL
+		}
+		if err := s.IsValid(); err != nil {
+			return err
+		}
+		if s.ProjectKey != "" {
+			if err := api.checkProjectPermissions(ctx, w, s.ProjectKey, sdk.PermissionRead, nil); err != nil {
+				return err
+			}
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		if err := user.UpdateNotificationSubscription(ctx, tx, &s); err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		s.WebhookURL = ""
+		return service.WriteJSON(w, s, http.StatusOK)
+	}
+}
+
+func (api *API) deleteUserNotificationSubscriptionHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "subscriptionID")
+		if err != nil {
+			return err
+		}
+		u, err := api.loadUserForNotificationSubscription(ctx, r)
+		if err != nil {
+			return err
+		}
+
+		s, err := user.LoadNotificationSubscriptionByID(ctx, api.mustDB(), u.ID, id)
+		if err != nil {
+			return err
+		}
+		if err := user.DeleteNotificationSubscription(api.mustDB(), *s); err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, nil, http.StatusOK)
+	}
+}
+
+// UserNotificationDigest sends the digests of the user notification subscriptions.
+func (api *API) UserNotificationDigest(ctx context.Context, tick time.Duration) error {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := notification.SendUserNotificationDigests(ctx, api.mustDB()); err != nil {
+				log.Error(ctx, "UserNotificationDigest> %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_userNotificationSubscriptionHandlers(t *testing.T) {
+	api, db, _ := newTestAPI(t)
+
+	u, jwtRaw := assets.InsertLambdaUser(t, db)
+	projKey := sdk.RandomString(10)
+	assets.InsertTestProject(t, db, api.Cache, projKey, projKey)
+
+	// The user can't read the project
+	uri := api.Router.GetRoute(http.MethodPost, api.postUserNotificationSubscriptionHandler, map[string]string{
+		"permUsername": u.Username,
+	})
+	require.NotEmpty(t, uri)
+	s := sdk.UserNotificationSubscription{
+		ProjectKey: projKey,
+		Channel:    sdk.UserSubscriptionChannelChat,
+		ChatType:   sdk.SlackUserNotification,
+		WebhookURL: "https://hooks.slack.com/services/xxx",
+		Mode:       sdk.UserSubscriptionModeHourly,
+	}
+	req := assets.NewJWTAuthentifiedRequest(t, jwtRaw, http.MethodPost, uri, s)
+	rec := httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	s.ProjectKey = ""
+	s.TriggeredByMe = true
+	req = assets.NewJWTAuthentifiedRequest(t, jwtRaw, http.MethodPost, uri, s)
+	rec = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	var created sdk.UserNotificationSubscription
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	require.Empty(t, created.WebhookURL)
+
+	stored, err := user.LoadNotificationSubscriptionByID(context.TODO(), db, u.ID, created.ID)
+	require.NoError(t, err)
+	require.Equal(t, "https://hooks.slack.com/services/xxx", stored.WebhookURL)
+
+	uri = api.Router.GetRoute(http.MethodGet, api.getUserNotificationSubscriptionsHandler, map[string]string{
+		"permUsername": u.Username,
+	})
+	req = assets.NewJWTAuthentifiedRequest(t, jwtRaw, http.MethodGet, uri, nil)
+	rec = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var subs []sdk.UserNotificationSubscription
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &subs))
+	require.Len(t, subs, 1)
+
+	uri = api.Router.GetRoute(http.MethodDelete, api.deleteUserNotificationSubscriptionHandler, map[string]string{
+		"permUsername":   u.Username,
+		"subscriptionID": strconv.FormatInt(created.ID, 10),
+	})
+	req = assets.NewJWTAuthentifiedRequest(t, jwtRaw, http.MethodDelete, uri, nil)
+	rec = httptest.NewRecorder()
+	api.Router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
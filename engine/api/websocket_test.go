@@ -33,7 +33,7 @@ func Test_websocketWrongFilters(t *testing.T) {
 	require.NoError(t, err)
 
 	_, jws, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), sdk.RandomString(10), 0, localConsumer, u.GetGroupIDs(),
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject), nil, nil)
 
 	chanMessageReceived := make(chan sdk.WebsocketEvent)
 	chanMessageToSend := make(chan []sdk.WebsocketFilter)
@@ -187,7 +187,7 @@ func Test_websocketDeconnection(t *testing.T) {
 	require.NoError(t, workflow.Insert(context.TODO(), db, api.Cache, *proj, &w))
 
 	_, jws, err := builtin.NewConsumer(context.TODO(), db, sdk.RandomString(10), sdk.RandomString(10), 0, localConsumer, u.GetGroupIDs(),
-		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject))
+		sdk.NewAuthConsumerScopeDetails(sdk.AuthConsumerScopeProject), nil, nil)
 
 	// Open websocket
 	client := cdsclient.New(cdsclient.Config{
@@ -56,6 +56,7 @@ func (api *API) getWorkflowsHandler() service.Handler {
 		if err != nil {
 			return err
 		}
+		ws = filterWorkflowsByConsumerRestrictions(ctx, ws)
 
 		ids := ws.IDs()
 		perms, err := permission.LoadWorkflowMaxLevelPermissionByWorkflowIDs(ctx, api.mustDB(), ids, groupIDS)
@@ -885,6 +886,21 @@ func (api *API) getWorkflowNotificationsConditionsHandler() service.Handler {
 	}
 }
 
+// filterWorkflowsByConsumerRestrictions removes the workflows that are not allowed by the consumer's resource restrictions.
+func filterWorkflowsByConsumerRestrictions(ctx context.Context, ws sdk.Workflows) sdk.Workflows {
+	c := getAPIConsumer(ctx)
+	if c == nil || len(c.ResourceRestrictions) == 0 {
+		return ws
+	}
+	res := make(sdk.Workflows, 0, len(ws))
+	for i := range ws {
+		if c.ResourceRestrictions.Level(ws[i].ProjectKey, ws[i].Name) >= sdk.PermissionRead {
+			res = append(res, ws[i])
+		}
+	}
+	return res
+}
+
 func (api *API) getSearchWorkflowHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		var dao workflow.WorkflowDAO
@@ -905,6 +921,7 @@ func (api *API) getSearchWorkflowHandler() service.Handler {
 		if err != nil {
 			return err
 		}
+		ws = filterWorkflowsByConsumerRestrictions(ctx, ws)
 
 		ids := ws.IDs()
 		perms, err := permission.LoadWorkflowMaxLevelPermissionByWorkflowIDs(ctx, api.mustDB(), ids, groupIDS)
@@ -393,6 +393,9 @@ func Insert(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.St
 		if n.Type == sdk.VCSUserNotification {
 			customVcsNotif = true
 		}
+		if err := checkNotificationIntegration(db, proj.Key, *n); err != nil {
+			return err
+		}
 		if err := InsertNotification(db, w, n); err != nil {
 			return sdk.WrapError(err, "Unable to insert update workflow(%d) notification (%#v)", w.ID, n)
 		}
@@ -687,6 +690,9 @@ func Update(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.St
 	// Insert notifications
 	for i := range wf.Notifications {
 		n := &wf.Notifications[i]
+		if err := checkNotificationIntegration(db, proj.Key, *n); err != nil {
+			return err
+		}
 		if err := InsertNotification(db, wf, n); err != nil {
 			return sdk.WrapError(err, "Unable to update workflow(%d) notification (%#v)", wf.ID, n)
 		}
@@ -777,6 +783,9 @@ func CompleteWorkflow(ctx context.Context, db gorp.SqlExecutor, w *sdk.Workflow,
 		if err := checkHooks(db, w, n); err != nil {
 			return err
 		}
+		if err := checkApproval(ctx, db, n); err != nil {
+			return err
+		}
 		if err := checkOutGoingHook(db, w, n); err != nil {
 			return err
 		}
@@ -788,6 +797,10 @@ func CompleteWorkflow(ctx context.Context, db gorp.SqlExecutor, w *sdk.Workflow,
 		}
 	}
 
+	if err := checkPreview(w); err != nil {
+		return err
+	}
+
 	w.Normalize()
 
 	return nil
@@ -1005,6 +1018,29 @@ func checkIntegration(proj sdk.Project, w *sdk.Workflow) error {
 }
 
 // CheckEnvironment checks environment data
+func checkApproval(ctx context.Context, db gorp.SqlExecutor, n *sdk.Node) error {
+	if n.Context.Approval == nil {
+		return nil
+	}
+	if n.Type != sdk.NodeTypePipeline {
+		return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "approval can only be set on pipeline node %s", n.Name)
+	}
+	if err := n.Context.Approval.IsValid(); err != nil {
+		return err
+	}
+	groups, err := group.LoadAllByNames(ctx, db, n.Context.Approval.Groups)
+	if err != nil {
+		return err
+	}
+	names := groups.ToNames()
+	for _, name := range n.Context.Approval.Groups {
+		if !sdk.IsInArray(name, names) {
+			return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "unknown approval group %s on node %s", name, n.Name)
+		}
+	}
+	return nil
+}
+
 func checkEnvironment(db gorp.SqlExecutor, proj sdk.Project, w *sdk.Workflow, n *sdk.Node) error {
 	if n.Context.EnvironmentID != 0 {
 		env, ok := w.Environments[n.Context.EnvironmentID]
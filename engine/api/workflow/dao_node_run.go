@@ -51,7 +51,9 @@ workflow_node_run.uuid,
 workflow_node_run.outgoinghook,
 workflow_node_run.hook_execution_timestamp,
 workflow_node_run.execution_id,
-workflow_node_run.callback
+workflow_node_run.callback,
+workflow_node_run.approval,
+workflow_node_run.protection_violation
 `
 
 const nodeRunTestsField string = ", workflow_node_run.tests"
@@ -403,6 +405,16 @@ func fromDBNodeRun(rr NodeRun, opts LoadRunOptions) (*sdk.WorkflowNodeRun, error
 		}
 	}
 
+	if rr.Approval.Valid {
+		if err := gorpmapping.JSONNullString(rr.Approval, &r.Approval); err != nil {
+			return nil, sdk.WrapError(err, "fromDBNodeRun>Error loading node run %d: Approval", r.ID)
+		}
+	}
+
+	if rr.ProtectionViolation.Valid {
+		r.ProtectionViolation = rr.ProtectionViolation.String
+	}
+
 	return r, nil
 }
 
@@ -528,6 +540,19 @@ func makeDBNodeRun(n sdk.WorkflowNodeRun) (*NodeRun, error) {
 	}
 	nodeRunDB.OutgoingHook = oh
 
+	if n.Approval != nil {
+		s, err := gorpmapping.JSONToNullString(n.Approval)
+		if err != nil {
+			return nil, sdk.WrapError(err, "makeDBNodeRun> unable to get json from approval")
+		}
+		nodeRunDB.Approval = s
+	}
+
+	if n.ProtectionViolation != "" {
+		nodeRunDB.ProtectionViolation.Valid = true
+		nodeRunDB.ProtectionViolation.String = n.ProtectionViolation
+	}
+
 	return nodeRunDB, nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"github.com/go-gorp/gorp"
 	"github.com/lib/pq"
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/integration"
 	"github.com/ovh/cds/sdk"
 )
 
@@ -29,11 +30,12 @@ func LoadNotificationsByWorkflowIDs(db gorp.SqlExecutor, ids []int64) (map[int64
 		ORDER BY workflow_notification.workflow_id`
 
 	var dbNotifs = []struct {
-		ID         int64                        `db:"id"`
-		WorkflowID int64                        `db:"workflow_id"`
-		NodeIDs    pq.Int64Array                `db:"node_ids"`
-		Type       string                       `db:"type"`
-		Settings   sdk.UserNotificationSettings `db:"settings"`
+		ID          int64                        `db:"id"`
+		WorkflowID  int64                        `db:"workflow_id"`
+		NodeIDs     pq.Int64Array                `db:"node_ids"`
+		Type        string                       `db:"type"`
+		Settings    sdk.UserNotificationSettings `db:"settings"`
+		Integration string                       `db:"integration"`
 	}{}
 
 	if _, err := db.Select(&dbNotifs, query, pq.Int64Array(ids)); err != nil {
@@ -48,11 +50,12 @@ func LoadNotificationsByWorkflowIDs(db gorp.SqlExecutor, ids []int64) (map[int64
 	for _, n := range dbNotifs {
 		arrayNotif := mapNotifs[n.WorkflowID]
 		notif := sdk.WorkflowNotification{
-			ID:         n.ID,
-			Settings:   n.Settings,
-			NodeIDs:    n.NodeIDs,
-			Type:       n.Type,
-			WorkflowID: n.WorkflowID,
+			ID:          n.ID,
+			Settings:    n.Settings,
+			NodeIDs:     n.NodeIDs,
+			Type:        n.Type,
+			WorkflowID:  n.WorkflowID,
+			Integration: n.Integration,
 		}
 		// Need the node_name for references...
 		arrayNotif = append(arrayNotif, notif)
@@ -67,6 +70,10 @@ func InsertNotification(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.WorkflowNot
 	n.ID = 0
 	n.NodeIDs = nil
 
+	if !sdk.IsChatUserNotification(n.Type) {
+		n.Integration = ""
+	}
+
 	for _, s := range n.SourceNodeRefs {
 		nodeFoundRef := w.WorkflowData.NodeByName(s)
 		if nodeFoundRef == nil || nodeFoundRef.ID == 0 {
@@ -94,6 +101,27 @@ func InsertNotification(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.WorkflowNot
 	return nil
 }
 
+// checkNotificationIntegration checks that a chat notification is linked to a chat webhook integration of the project.
+func checkNotificationIntegration(db gorp.SqlExecutor, projectKey string, n sdk.WorkflowNotification) error {
+	if !sdk.IsChatUserNotification(n.Type) {
+		return nil
+	}
+	if n.Integration == "" {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "notification of type %s must be linked to an integration", n.Type)
+	}
+	pi, err := integration.LoadProjectIntegrationByName(db, projectKey, n.Integration)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "integration %s not found for notification of type %s", n.Integration, n.Type)
+		}
+		return err
+	}
+	if pi.Model.Name != sdk.ChatWebhookIntegrationModel {
+		return sdk.NewErrorFrom(sdk.ErrWrongRequest, "integration %s is not a %s integration", n.Integration, sdk.ChatWebhookIntegrationModel)
+	}
+	return nil
+}
+
 // PostInsert is a db hook
 func (no *Notification) PostInsert(db gorp.SqlExecutor) error {
 	b, err := gorpmapping.JSONToNullString(no.Settings)
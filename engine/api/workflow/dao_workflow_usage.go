@@ -67,3 +67,19 @@ func LoadByWorkflowTemplateID(ctx context.Context, db gorp.SqlExecutor, template
 	_, err := db.Select(&result, query, templateID)
 	return result, sdk.WithStack(err)
 }
+
+// LoadByKeyInDefaultPipelineParameters loads the workflows of a project for which a node sets given key as a default pipeline parameter
+func LoadByKeyInDefaultPipelineParameters(ctx context.Context, db gorp.SqlExecutor, projectKey string, keyName string) ([]sdk.WorkflowName, error) {
+	query := `SELECT distinct workflow.*, project.projectkey as "project_key", project.id as "project_id"
+	from workflow
+	join project on project.id = workflow.project_id
+	join w_node on w_node.workflow_id = workflow.id
+	join w_node_context on w_node_context.node_id = w_node.id
+	where project.projectkey = $1
+	and w_node_context.default_pipeline_parameters @> jsonb_build_array(jsonb_build_object('value', $2::text))
+	and workflow.to_delete = false
+	order by workflow.name asc`
+	var result []sdk.WorkflowName // This struct is not registered as a gorpmapping entity so we can't use gorpmapping.Query
+	_, err := db.Select(&result, query, projectKey, keyName)
+	return result, sdk.WithStack(err)
+}
@@ -314,6 +314,13 @@ func executeNodeRun(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store
 				return report, err
 			}
 		}
+
+		// If current node deploys or tears down a preview environment, save its state
+		if node != nil && node.Context != nil && node.Context.Preview != "" {
+			if err := updatePreviewEnvironment(ctx, db, node, workflowNodeRun); err != nil {
+				return report, err
+			}
+		}
 	}
 	return report, nil
 }
@@ -330,10 +337,11 @@ func releaseMutex(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store ca
     WHERE workflow.id = $1
       AND workflow_node_run.workflow_node_name = $2
       AND workflow_node_run.status = $3
+      AND coalesce(workflow_node_run.approval->>'status', '') <> $4
     ORDER BY workflow_run.num ASC
     LIMIT 1
   `
-	waitingRunID, err := db.SelectInt(mutexQuery, workflowID, nodeName, string(sdk.StatusWaiting))
+	waitingRunID, err := db.SelectInt(mutexQuery, workflowID, nodeName, string(sdk.StatusWaiting), sdk.ApprovalStatusPending)
 	if err != nil && err != sql.ErrNoRows {
 		err = sdk.WrapError(err, "unable to load mutex-locked workflow node run id")
 		ctx = sdk.ContextWithStacktrace(ctx, err)
@@ -0,0 +1,7 @@
+package workflow
+
+// Unexported functions used by workflow_test package
+var (
+	IsPreviewNodeSkipped     = isPreviewNodeSkipped
+	UpdatePreviewEnvironment = updatePreviewEnvironment
+)
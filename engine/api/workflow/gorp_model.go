@@ -73,6 +73,8 @@ type NodeRun struct {
 	HookExecutionTimestamp sql.NullInt64  `db:"hook_execution_timestamp"`
 	ExecutionID            sql.NullString `db:"execution_id"`
 	Callback               sql.NullString `db:"callback"`
+	Approval               sql.NullString `db:"approval"`
+	ProtectionViolation    sql.NullString `db:"protection_violation"`
 }
 
 // JobRun is a gorp wrapper around sdk.WorkflowNodeJobRun
@@ -0,0 +1,175 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/telemetry"
+)
+
+// LoadNodeRunIDsWithExpiredApproval returns ids of the node runs waiting for an approval that has expired.
+func LoadNodeRunIDsWithExpiredApproval(db gorp.SqlExecutor) ([]int64, error) {
+	query := `
+	SELECT id
+	FROM workflow_node_run
+	WHERE status = $1
+	AND approval->>'status' = $2
+	AND (approval->>'expire_at')::TIMESTAMP WITH TIME ZONE < NOW()
+	`
+	var ids []int64
+	if _, err := db.Select(&ids, query, sdk.StatusWaiting, sdk.ApprovalStatusPending); err != nil {
+		return nil, sdk.WrapError(err, "unable to load node runs with expired approval")
+	}
+	return ids, nil
+}
+
+// DecideNodeRunApproval records the decision of a reviewer, member of given groups, on a node run waiting for approval.
+// The node run is executed when enough approvals were given and stopped when it is rejected.
+func DecideNodeRunApproval(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun, u sdk.AuthentifiedUser, groupNames []string, approved bool, comment string) (*ProcessorReport, error) {
+	var end func()
+	ctx, end = telemetry.Span(ctx, "workflow.DecideNodeRunApproval")
+	defer end()
+
+	if nr.Approval == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "node run %d has no approval", nr.ID)
+	}
+
+	nr.Approval.AddDecision(u, groupNames, approved, comment, time.Now())
+
+	switch nr.Approval.Status {
+	case sdk.ApprovalStatusRejected:
+		return stopNodeRunApproval(ctx, db, store, proj, wr, nr, sdk.SpawnMsgNew(*sdk.MsgWorkflowNodeApprovalRejected, nr.WorkflowNodeName, u.Username))
+	case sdk.ApprovalStatusApproved:
+		return executeApprovedNodeRun(ctx, db, store, proj, wr, nr, u)
+	}
+
+	report := new(ProcessorReport)
+	if err := UpdateNodeRun(db, nr); err != nil {
+		return nil, sdk.WrapError(err, "unable to update node run %d", nr.ID)
+	}
+	report.Add(ctx, *nr)
+	return report, nil
+}
+
+// loadTriggeredByUserID returns the ID of the CDS user that triggered the node run: the user of a manual run
+// of the node or of one of its parents, else the user of the consumer that started the workflow run.
+// Runs started by a hook have no user.
+func loadTriggeredByUserID(ctx context.Context, db gorp.SqlExecutor, wr *sdk.WorkflowRun, parents []*sdk.WorkflowNodeRun, manual *sdk.WorkflowNodeRunManual) (string, error) {
+	if manual != nil && manual.UserID != "" {
+		return manual.UserID, nil
+	}
+	for _, p := range parents {
+		if p.Manual != nil && p.Manual.UserID != "" {
+			return p.Manual.UserID, nil
+		}
+	}
+	if wr.ToCraftOpts == nil || wr.ToCraftOpts.Hook != nil || wr.ToCraftOpts.AuthConsumerID == "" {
+		return "", nil
+	}
+	c, err := authentication.LoadConsumerByID(ctx, db, wr.ToCraftOpts.AuthConsumerID)
+	if err != nil {
+		return "", err
+	}
+	return c.AuthentifiedUserID, nil
+}
+
+// ExpireNodeRunApproval stops a node run which approval has expired.
+func ExpireNodeRunApproval(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun) (*ProcessorReport, error) {
+	if !nr.Approval.IsPending() {
+		return nil, nil
+	}
+	nr.Approval.Status = sdk.ApprovalStatusExpired
+	return stopNodeRunApproval(ctx, db, store, proj, wr, nr, sdk.SpawnMsgNew(*sdk.MsgWorkflowNodeApprovalExpired, nr.WorkflowNodeName))
+}
+
+func executeApprovedNodeRun(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun, u sdk.AuthentifiedUser) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+	if err := UpdateNodeRun(db, nr); err != nil {
+		return nil, sdk.WrapError(err, "unable to update node run %d", nr.ID)
+	}
+	report.Add(ctx, *nr)
+
+	AddWorkflowRunInfo(wr, sdk.SpawnMsgNew(*sdk.MsgWorkflowNodeApprovalApproved, nr.WorkflowNodeName, u.Username))
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return nil, sdk.WrapError(err, "unable to update workflow run")
+	}
+
+	n := wr.Workflow.WorkflowData.NodeByID(nr.WorkflowNodeID)
+	if n != nil && n.Context != nil {
+		// Deployment windows may have closed while waiting for approvals
+		_, reason, err := checkEnvironmentProtection(ctx, db, store, proj.Key, wr, n, wr.Workflow.Applications[n.Context.ApplicationID], nr)
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			nr.ProtectionViolation = reason
+			r, err := stopNodeRunApproval(ctx, db, store, proj, wr, nr, sdk.SpawnMsgNew(*sdk.MsgWorkflowNodeEnvironmentProtected, n.Name, wr.Workflow.Environments[n.Context.EnvironmentID].Name, reason))
+			report.Merge(ctx, r)
+			return report, err
+		}
+
+		locked, err := checkNodeRunMutex(ctx, db, wr, n, nr)
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			// The node run will be executed when the mutex is released
+			return report, nil
+		}
+	}
+
+	r, err := executeNodeRun(ctx, db, store, proj, nr)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to execute node run %d", nr.ID)
+	}
+	report.Merge(ctx, r)
+	return report, nil
+}
+
+func stopNodeRunApproval(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj sdk.Project, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun, info sdk.SpawnMsg) (*ProcessorReport, error) {
+	report := new(ProcessorReport)
+
+	stopWorkflowNodeRunStages(ctx, db, nr)
+	nr.Status = sdk.StatusStopped
+	nr.Done = time.Now()
+	if err := UpdateNodeRun(db, nr); err != nil {
+		return nil, sdk.WrapError(err, "unable to update node run %d", nr.ID)
+	}
+	report.Add(ctx, *nr)
+
+	AddWorkflowRunInfo(wr, info)
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return nil, sdk.WrapError(err, "unable to update workflow run")
+	}
+
+	// Reload the workflow run to compute its status with the stopped node run
+	updatedRun, err := LoadRunByID(db, wr.ID, LoadRunOptions{DisableDetailledNodeRun: true})
+	if err != nil {
+		return nil, err
+	}
+	r, err := ResyncWorkflowRunStatus(ctx, db, updatedRun)
+	if err != nil {
+		return nil, sdk.WrapError(err, "unable to resync workflow run status")
+	}
+	report.Merge(ctx, r)
+
+	// If current node has a mutex, we want to trigger another node run that can be waiting for the mutex
+	n := wr.Workflow.WorkflowData.NodeByID(nr.WorkflowNodeID)
+	if n != nil && n.Context != nil && n.Context.Mutex {
+		r, err := releaseMutex(ctx, db, store, proj, nr.WorkflowID, nr.WorkflowNodeName)
+		report.Merge(ctx, r)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	log.Debug(ctx, "node run %d stopped: approval %s", nr.ID, nr.Approval.Status)
+	return report, nil
+}
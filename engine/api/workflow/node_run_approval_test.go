@@ -0,0 +1,128 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/authentication"
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestDecideNodeRunApproval(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+
+	u, _ := assets.InsertAdminUser(t, db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+	reviewers := assets.InsertTestGroup(t, db, sdk.RandomString(10))
+	reviewer1, _ := assets.InsertLambdaUser(t, db, reviewers)
+	reviewer2, _ := assets.InsertLambdaUser(t, db, reviewers)
+
+	pip := sdk.Pipeline{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       "pip1",
+	}
+	require.NoError(t, pipeline.InsertPipeline(db, &pip))
+	s := sdk.NewStage("stage 1")
+	s.Enabled = true
+	s.PipelineID = pip.ID
+	require.NoError(t, pipeline.InsertStage(db, s))
+	j := &sdk.Job{
+		Enabled: true,
+		Action: sdk.Action{
+			Enabled: true,
+		},
+	}
+	require.NoError(t, pipeline.InsertJob(db, j, s.ID, &pip))
+
+	proj, _ = project.LoadByID(db, proj.ID, project.LoadOptions.WithApplications, project.LoadOptions.WithPipelines, project.LoadOptions.WithEnvironments, project.LoadOptions.WithGroups)
+
+	w := sdk.Workflow{
+		Name:       "test_approval",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "deploy",
+				Ref:  "deploy",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID: pip.ID,
+					Approval: &sdk.NodeApproval{
+						Groups:             []string{reviewers.Name},
+						RequiredApprovals:  2,
+						ForbidSelfApproval: true,
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, workflow.Insert(context.TODO(), db, cache, *proj, &w))
+	w1, err := workflow.Load(context.TODO(), db, cache, *proj, w.Name, workflow.LoadOptions{DeepPipeline: true})
+	require.NoError(t, err)
+
+	consumer, err := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	require.NoError(t, err)
+
+	startRun := func() (*sdk.WorkflowRun, *sdk.WorkflowNodeRun) {
+		wr, err := workflow.CreateRun(db.DbMap, w1, sdk.WorkflowRunPostHandlerOption{AuthConsumerID: consumer.ID})
+		require.NoError(t, err)
+		wr.Workflow = *w1
+		_, err = workflow.StartWorkflowRun(context.TODO(), db, cache, *proj, wr, &sdk.WorkflowRunPostHandlerOption{
+			Manual: &sdk.WorkflowNodeRunManual{},
+		}, *consumer, nil)
+		require.NoError(t, err)
+
+		wr, err = workflow.LoadRunByID(db, wr.ID, workflow.LoadRunOptions{})
+		require.NoError(t, err)
+		require.Len(t, wr.WorkflowNodeRuns[w1.WorkflowData.Node.ID], 1)
+		nr := wr.WorkflowNodeRuns[w1.WorkflowData.Node.ID][0]
+		return wr, &nr
+	}
+
+	// The node run waits for the approvals, the user that triggered the run can't approve
+	wr, nr := startRun()
+	require.Equal(t, sdk.StatusWaiting, nr.Status)
+	require.True(t, nr.Approval.IsPending())
+	assert.Equal(t, []string{u.ID}, nr.Approval.ForbiddenUserIDs)
+	require.Error(t, nr.Approval.CanDecide(*u, nil, []string{reviewers.Name}), "user that triggered the run can't approve")
+	require.NoError(t, nr.Approval.CanDecide(*reviewer1, nil, []string{reviewers.Name}))
+
+	_, err = workflow.DecideNodeRunApproval(context.TODO(), db, cache, *proj, wr, nr, *reviewer1, []string{reviewers.Name}, true, "lgtm")
+	require.NoError(t, err)
+	nr, err = workflow.LoadNodeRunByID(db, nr.ID, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	require.Equal(t, sdk.StatusWaiting, nr.Status, "one approval is missing")
+	require.True(t, nr.Approval.IsPending())
+	require.Error(t, nr.Approval.CanDecide(*reviewer1, nil, []string{reviewers.Name}), "reviewer already approved")
+
+	_, err = workflow.DecideNodeRunApproval(context.TODO(), db, cache, *proj, wr, nr, *reviewer2, []string{reviewers.Name}, true, "")
+	require.NoError(t, err)
+	nr, err = workflow.LoadNodeRunByID(db, nr.ID, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	require.Equal(t, sdk.ApprovalStatusApproved, nr.Approval.Status)
+	require.Len(t, nr.Approval.Decisions, 2)
+	assert.NotEqual(t, sdk.StatusWaiting, nr.Status, "jobs should be executed")
+
+	// A rejection stops the node run and the workflow run
+	wr, nr = startRun()
+	_, err = workflow.DecideNodeRunApproval(context.TODO(), db, cache, *proj, wr, nr, *reviewer1, []string{reviewers.Name}, false, "not now")
+	require.NoError(t, err)
+	nr, err = workflow.LoadNodeRunByID(db, nr.ID, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	require.Equal(t, sdk.ApprovalStatusRejected, nr.Approval.Status)
+	require.Equal(t, sdk.StatusStopped, nr.Status)
+	wr, err = workflow.LoadRunByID(db, wr.ID, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusStopped, wr.Status)
+}
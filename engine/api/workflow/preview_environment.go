@@ -0,0 +1,297 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/interpolate"
+)
+
+// checkPreview checks the preview nodes of the workflow: at most one deploy and one teardown pipeline node, both on
+// the same application. The environment of the deploy node is the template of the preview environments.
+func checkPreview(w *sdk.Workflow) error {
+	var deploy, teardown *sdk.Node
+	for _, n := range w.WorkflowData.Array() {
+		if n.Context == nil || n.Context.Preview == "" {
+			continue
+		}
+		if err := sdk.IsValidNodePreview(n.Context.Preview); err != nil {
+			return err
+		}
+		if n.Type != sdk.NodeTypePipeline || n.Name == w.WorkflowData.Node.Name {
+			return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "preview can only be set on a pipeline node that is not the root, invalid node %s", n.Name)
+		}
+		if n.Context.ApplicationID == 0 {
+			return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "an application is required on preview node %s", n.Name)
+		}
+		switch n.Context.Preview {
+		case sdk.NodePreviewDeploy:
+			if deploy != nil {
+				return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "only one preview deploy node is allowed, found %s and %s", deploy.Name, n.Name)
+			}
+			if n.Context.EnvironmentID == 0 {
+				return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "an environment is required on preview deploy node %s", n.Name)
+			}
+			deploy = n
+		case sdk.NodePreviewTeardown:
+			if teardown != nil {
+				return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "only one preview teardown node is allowed, found %s and %s", teardown.Name, n.Name)
+			}
+			teardown = n
+		}
+	}
+	if teardown != nil && (deploy == nil || deploy.Context.ApplicationID != teardown.Context.ApplicationID) {
+		return sdk.NewErrorFrom(sdk.ErrWorkflowInvalid, "preview teardown node %s requires a preview deploy node on the same application", teardown.Name)
+	}
+	return nil
+}
+
+func previewNode(w *sdk.Workflow, preview string) *sdk.Node {
+	for _, n := range w.WorkflowData.Array() {
+		if n.Context != nil && n.Context.Preview == preview {
+			return n
+		}
+	}
+	return nil
+}
+
+// nodeAndAncestorIDs returns the ids of the node and of all its ancestors.
+func nodeAndAncestorIDs(w *sdk.WorkflowData, n *sdk.Node) []int64 {
+	ids := []int64{n.ID}
+	for _, id := range n.Ancestors(*w) {
+		if parent := w.NodeByID(id); parent != nil {
+			ids = append(ids, nodeAndAncestorIDs(w, parent)...)
+		}
+	}
+	return ids
+}
+
+// isPreviewNodeSkipped returns true if the node is a preview node that doesn't match the preview action of the run.
+// To tear down a preview environment only the teardown node and its ancestors are run.
+func isPreviewNodeSkipped(wr *sdk.WorkflowRun, n *sdk.Node, params []sdk.Parameter) bool {
+	action := sdk.ParameterValue(params, sdk.PreviewParamAction)
+	if n.Context.Preview != "" {
+		return action != n.Context.Preview
+	}
+	if action == sdk.NodePreviewTeardown {
+		if teardown := previewNode(&wr.Workflow, sdk.NodePreviewTeardown); teardown != nil {
+			return !sdk.IsInInt64Array(n.ID, nodeAndAncestorIDs(&wr.Workflow.WorkflowData, teardown))
+		}
+	}
+	return false
+}
+
+// previewRepository returns the repository of the root application of the workflow, or the one of the preview node
+// application if the root node has no application.
+func previewRepository(w *sdk.Workflow, n *sdk.Node) string {
+	if root := w.WorkflowData.Node.Context; root != nil && root.ApplicationID != 0 {
+		return w.Applications[root.ApplicationID].RepositoryFullname
+	}
+	return w.Applications[n.Context.ApplicationID].RepositoryFullname
+}
+
+// PreparePreviewEnvironment creates or refreshes the preview environment of the pull request that triggered the run,
+// or loads it for a teardown. The preview node of the run snapshot is moved on the preview environment and the
+// preview parameters are added to the hook payload, so the preview node will be the only one to run in it. A deploy
+// run builds the head of the pull request.
+func PreparePreviewEnvironment(ctx context.Context, db gorpmapper.SqlExecutorWithTx, wr *sdk.WorkflowRun, hookEvent *sdk.WorkflowNodeRunHookEvent, u sdk.Identifiable) error {
+	e := sdk.PreviewEventFromPayload(hookEvent.Payload)
+	if e == nil {
+		return nil
+	}
+	n := previewNode(&wr.Workflow, e.Action)
+	if n == nil {
+		return nil
+	}
+
+	// The hook payload is not trusted, the code of a fork must never run with the variables of the template environment
+	if e.Action == sdk.NodePreviewDeploy {
+		repo := previewRepository(&wr.Workflow, n)
+		if repo == "" || !strings.EqualFold(e.HeadRepository, repo) {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "preview environment can't be deployed from repository %q", e.HeadRepository)
+		}
+	}
+
+	p, err := application.LoadPreviewEnvironment(db, wr.WorkflowID, n.Context.ApplicationID, e.PullRequestID)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return err
+	}
+
+	var env *sdk.Environment
+	switch e.Action {
+	case sdk.NodePreviewDeploy:
+		env, err = deployPreviewEnvironment(db, wr, n, p, *e, u)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			p = &sdk.PreviewEnvironment{
+				ApplicationID: n.Context.ApplicationID,
+				WorkflowID:    wr.WorkflowID,
+				EnvironmentID: env.ID,
+				PullRequestID: e.PullRequestID,
+			}
+		}
+		p.Branch = e.Branch
+		p.Hash = e.Hash
+		p.Status = sdk.PreviewEnvironmentStatusDeploying
+		AddWorkflowRunInfo(wr, sdk.SpawnMsgNew(*sdk.MsgWorkflowPreviewEnvironmentDeploy, n.Name, env.Name, e.PullRequestID))
+	case sdk.NodePreviewTeardown:
+		if p == nil {
+			log.Info(ctx, "no preview environment to tear down for pull request %d of workflow %d", e.PullRequestID, wr.WorkflowID)
+			return nil
+		}
+		env, err = environment.LoadEnvironmentByID(db, p.EnvironmentID)
+		if err != nil {
+			return err
+		}
+		p.Status = sdk.PreviewEnvironmentStatusTearingDown
+		AddWorkflowRunInfo(wr, sdk.SpawnMsgNew(*sdk.MsgWorkflowPreviewEnvironmentTeardown, n.Name, env.Name, e.PullRequestID))
+	}
+
+	p.WorkflowRunNumber = wr.Number
+	if p.ID == 0 {
+		err = application.InsertPreviewEnvironment(db, p)
+	} else {
+		err = application.UpdatePreviewEnvironment(db, p)
+	}
+	if err != nil {
+		return err
+	}
+
+	wr.Workflow.InitMaps()
+	wr.Workflow.Environments[env.ID] = *env
+	n.Context.EnvironmentID = env.ID
+	n.Context.EnvironmentName = env.Name
+	for k, v := range e.Params() {
+		hookEvent.Payload[k] = v
+	}
+	// A preview is deployed from the head of the pull request
+	if e.Action == sdk.NodePreviewDeploy {
+		hookEvent.Payload[tagGitBranch] = e.Branch
+		hookEvent.Payload[tagGitHash] = e.Hash
+		hookEvent.Payload[tagGitHashShort] = sdk.StringFirstN(e.Hash, 7)
+	}
+	return nil
+}
+
+// deployPreviewEnvironment creates the preview environment from the environment of the deploy node, or refreshes
+// its variables and protection if it already exists. Variables are interpolated with the pull request number and branch.
+func deployPreviewEnvironment(db gorpmapper.SqlExecutorWithTx, wr *sdk.WorkflowRun, n *sdk.Node, p *sdk.PreviewEnvironment, e sdk.PreviewEvent, u sdk.Identifiable) (*sdk.Environment, error) {
+	tmpl, has := wr.Workflow.Environments[n.Context.EnvironmentID]
+	if !has {
+		return nil, sdk.NewErrorFrom(sdk.ErrEnvironmentNotFound, "unable to find environment %d of preview node %s", n.Context.EnvironmentID, n.Name)
+	}
+
+	// The preview environment holds the secrets of the template, it is protected by the same rules
+	protection, err := environment.LoadProtectionByID(db, tmpl.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var env *sdk.Environment
+	if p != nil {
+		env, err = environment.LoadEnvironmentByID(db, p.EnvironmentID)
+		if err != nil {
+			return nil, err
+		}
+		if err := environment.UpdateProtection(db, env, protection); err != nil {
+			return nil, err
+		}
+		if err := environment.DeleteAllVariables(db, env.ID); err != nil {
+			return nil, err
+		}
+	} else {
+		name, err := previewEnvironmentName(db, wr, tmpl, e)
+		if err != nil {
+			return nil, err
+		}
+		env = &sdk.Environment{
+			Name:       name,
+			ProjectID:  wr.ProjectID,
+			Protection: protection,
+		}
+		if err := environment.InsertEnvironment(db, env); err != nil {
+			return nil, sdk.WrapError(err, "unable to create preview environment %s", env.Name)
+		}
+	}
+
+	vars, err := environment.LoadAllVariablesWithDecrytion(db, tmpl.ID)
+	if err != nil {
+		return nil, err
+	}
+	params := e.Params()
+	for _, v := range vars {
+		v.ID = 0
+		v.Value, err = interpolate.Do(v.Value, params)
+		if err != nil {
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unable to interpolate variable %s of environment %s: %v", v.Name, tmpl.Name, err)
+		}
+		if err := environment.InsertVariable(db, env.ID, &v, u); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reload the environment to get its variables without secret values
+	return environment.LoadEnvironmentByID(db, env.ID)
+}
+
+// previewEnvironmentName returns a name for a new preview environment. If an environment of the project already
+// has the name, ie. created by a user, a suffix is added.
+func previewEnvironmentName(db gorp.SqlExecutor, wr *sdk.WorkflowRun, tmpl sdk.Environment, e sdk.PreviewEvent) (string, error) {
+	base := sdk.PreviewEnvironmentName(tmpl.Name, wr.Workflow.Name, e.PullRequestID)
+	name := base
+	for i := 2; ; i++ {
+		exist, err := environment.Exists(db, wr.Workflow.ProjectKey, name)
+		if err != nil {
+			return "", sdk.WithStack(err)
+		}
+		if !exist {
+			return name, nil
+		}
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// updatePreviewEnvironment saves the result of a preview node run. The preview environment is deleted when its
+// teardown succeeded.
+func updatePreviewEnvironment(ctx context.Context, db gorp.SqlExecutor, n *sdk.Node, nr *sdk.WorkflowNodeRun) error {
+	p, err := application.LoadPreviewEnvironmentByEnvironmentID(db, n.Context.EnvironmentID)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	// A newer run is deploying or tearing down the environment
+	if p.WorkflowRunNumber != nr.Number {
+		return nil
+	}
+
+	success := nr.Status == sdk.StatusSuccess
+	switch n.Context.Preview {
+	case sdk.NodePreviewDeploy:
+		p.Status = sdk.PreviewEnvironmentStatusDeployed
+		if !success {
+			p.Status = sdk.PreviewEnvironmentStatusDeployFailed
+		}
+	case sdk.NodePreviewTeardown:
+		if success {
+			log.Info(ctx, "deleting preview environment %s of pull request %d", p.EnvironmentName, p.PullRequestID)
+			if err := application.DeletePreviewEnvironment(db, p.ID); err != nil {
+				return err
+			}
+			return environment.DeleteEnvironment(db, p.EnvironmentID)
+		}
+		p.Status = sdk.PreviewEnvironmentStatusTeardownFailed
+	}
+	return application.UpdatePreviewEnvironment(db, p)
+}
@@ -0,0 +1,281 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/engine/gorpmapper"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestIsPreviewNodeSkipped(t *testing.T) {
+	wr := &sdk.WorkflowRun{
+		Workflow: sdk.Workflow{
+			WorkflowData: sdk.WorkflowData{
+				Node: sdk.Node{
+					ID:      1,
+					Name:    "build",
+					Context: &sdk.NodeContext{},
+					Triggers: []sdk.NodeTrigger{
+						{ChildNode: sdk.Node{ID: 2, Name: "deploy", Context: &sdk.NodeContext{Preview: sdk.NodePreviewDeploy}}},
+						{ChildNode: sdk.Node{ID: 3, Name: "teardown", Context: &sdk.NodeContext{Preview: sdk.NodePreviewTeardown}}},
+						{ChildNode: sdk.Node{ID: 4, Name: "tests", Context: &sdk.NodeContext{}}},
+					},
+				},
+			},
+		},
+	}
+	nodes := map[string]*sdk.Node{}
+	for _, n := range wr.Workflow.WorkflowData.Array() {
+		nodes[n.Name] = n
+	}
+	params := func(action string) []sdk.Parameter {
+		if action == "" {
+			return nil
+		}
+		return []sdk.Parameter{{Name: sdk.PreviewParamAction, Type: sdk.StringParameter, Value: action}}
+	}
+
+	tests := []struct {
+		action  string
+		skipped []string
+	}{
+		// A push runs all the nodes but the preview ones
+		{action: "", skipped: []string{"deploy", "teardown"}},
+		{action: sdk.NodePreviewDeploy, skipped: []string{"teardown"}},
+		// A teardown only runs the teardown node and its ancestors
+		{action: sdk.NodePreviewTeardown, skipped: []string{"deploy", "tests"}},
+	}
+	for _, tt := range tests {
+		for name, n := range nodes {
+			require.Equal(t, sdk.IsInArray(name, tt.skipped), workflow.IsPreviewNodeSkipped(wr, n, params(tt.action)), "action %q node %s", tt.action, name)
+		}
+	}
+}
+
+func insertTestPreviewWorkflow(t *testing.T, db gorpmapper.SqlExecutorWithTx, store cache.Store, proj *sdk.Project, u *sdk.AuthentifiedUser) (*sdk.Workflow, *sdk.Environment) {
+	pip := sdk.Pipeline{ProjectID: proj.ID, ProjectKey: proj.Key, Name: sdk.RandomString(10)}
+	require.NoError(t, pipeline.InsertPipeline(db, &pip))
+
+	app := sdk.Application{Name: sdk.RandomString(10), ProjectID: proj.ID, ProjectKey: proj.Key, RepositoryFullname: "org/repo"}
+	require.NoError(t, application.Insert(db, *proj, &app))
+
+	tmpl := sdk.Environment{
+		Name:       "staging",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Protection: &sdk.EnvironmentProtection{Branches: []string{"feat/*"}},
+	}
+	require.NoError(t, environment.InsertEnvironment(db, &tmpl))
+	require.NoError(t, environment.InsertVariable(db, tmpl.ID, &sdk.EnvironmentVariable{
+		Name:  "url",
+		Type:  sdk.StringVariable,
+		Value: "https://{{.cds.preview.branch.slug}}.preview.local",
+	}, u))
+	require.NoError(t, environment.InsertVariable(db, tmpl.ID, &sdk.EnvironmentVariable{
+		Name:  "password",
+		Type:  sdk.SecretVariable,
+		Value: "s3cr3t",
+	}, u))
+
+	w := sdk.Workflow{
+		Name:       sdk.RandomString(10),
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		WorkflowData: sdk.WorkflowData{
+			Node: sdk.Node{
+				Name:    "build",
+				Ref:     "build",
+				Type:    sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{PipelineID: pip.ID},
+				Triggers: []sdk.NodeTrigger{
+					{ChildNode: sdk.Node{
+						Name: "deploy",
+						Ref:  "deploy",
+						Type: sdk.NodeTypePipeline,
+						Context: &sdk.NodeContext{
+							PipelineID:    pip.ID,
+							ApplicationID: app.ID,
+							EnvironmentID: tmpl.ID,
+							Preview:       sdk.NodePreviewDeploy,
+						},
+					}},
+					{ChildNode: sdk.Node{
+						Name: "teardown",
+						Ref:  "teardown",
+						Type: sdk.NodeTypePipeline,
+						Context: &sdk.NodeContext{
+							PipelineID:    pip.ID,
+							ApplicationID: app.ID,
+							Preview:       sdk.NodePreviewTeardown,
+						},
+					}},
+				},
+			},
+		},
+		Pipelines:    map[int64]sdk.Pipeline{pip.ID: pip},
+		Applications: map[int64]sdk.Application{app.ID: app},
+		Environments: map[int64]sdk.Environment{tmpl.ID: tmpl},
+	}
+	require.NoError(t, workflow.Insert(context.TODO(), db, store, *proj, &w))
+	return &w, &tmpl
+}
+
+func newPreviewRun(w *sdk.Workflow, number int64) *sdk.WorkflowRun {
+	return &sdk.WorkflowRun{
+		ProjectID:  w.ProjectID,
+		WorkflowID: w.ID,
+		Number:     number,
+		Workflow:   *w,
+	}
+}
+
+func TestPreparePreviewEnvironment(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(t, db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+	w, tmpl := insertTestPreviewWorkflow(t, db, cache, proj, u)
+
+	// Opening a pull request creates the preview environment from the template
+	wr := newPreviewRun(w, 1)
+	hookEvent := &sdk.WorkflowNodeRunHookEvent{Payload: map[string]string{
+		"git.hook": "pr:opened", "git.pr.id": "42", "git.branch": "feat/Login", "git.hash": "abcdef", "git.repository": "org/repo",
+	}}
+	require.NoError(t, workflow.PreparePreviewEnvironment(context.TODO(), db, wr, hookEvent, u))
+
+	deploy := wr.Workflow.WorkflowData.NodeByName("deploy")
+	require.NotEqual(t, tmpl.ID, deploy.Context.EnvironmentID)
+	require.Equal(t, "staging-"+w.Name+"-pr-42", deploy.Context.EnvironmentName)
+	require.Equal(t, sdk.NodePreviewDeploy, hookEvent.Payload[sdk.PreviewParamAction])
+	require.Equal(t, "42", hookEvent.Payload[sdk.PreviewParamPullRequest])
+
+	env, err := environment.LoadEnvironmentByID(db, deploy.Context.EnvironmentID)
+	require.NoError(t, err)
+	require.NotNil(t, env.Protection)
+	require.Equal(t, []string{"feat/*"}, env.Protection.Branches)
+	vars, err := environment.LoadAllVariablesWithDecrytion(db, env.ID)
+	require.NoError(t, err)
+	values := map[string]string{}
+	for _, v := range vars {
+		values[v.Name] = v.Value
+	}
+	require.Equal(t, map[string]string{"url": "https://feat-login.preview.local", "password": "s3cr3t"}, values)
+
+	p, err := application.LoadPreviewEnvironmentByEnvironmentID(db, env.ID)
+	require.NoError(t, err)
+	require.Equal(t, sdk.PreviewEnvironmentStatusDeploying, p.Status)
+	require.Equal(t, int64(1), p.WorkflowRunNumber)
+	require.Equal(t, "abcdef", p.Hash)
+
+	// Updating the pull request reuses the preview environment
+	wr2 := newPreviewRun(w, 2)
+	hookEvent = &sdk.WorkflowNodeRunHookEvent{Payload: map[string]string{
+		"git.hook": "pr:from_ref_updated", "git.pr.id": "42", "git.branch": "feat/Login", "git.hash": "123456", "git.repository": "org/repo",
+	}}
+	require.NoError(t, workflow.PreparePreviewEnvironment(context.TODO(), db, wr2, hookEvent, u))
+	require.Equal(t, env.ID, wr2.Workflow.WorkflowData.NodeByName("deploy").Context.EnvironmentID)
+	p, err = application.LoadPreviewEnvironmentByEnvironmentID(db, env.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), p.WorkflowRunNumber)
+	require.Equal(t, "123456", p.Hash)
+
+	// Another pull request gets its own environment, a suffix is added if the name is already used
+	conflict := sdk.Environment{Name: "staging-" + w.Name + "-pr-43", ProjectID: proj.ID}
+	require.NoError(t, environment.InsertEnvironment(db, &conflict))
+	wr3 := newPreviewRun(w, 3)
+	hookEvent = &sdk.WorkflowNodeRunHookEvent{Payload: map[string]string{
+		"git.hook": "pr:opened", "git.pr.id": "43", "git.branch": "feat/other", "git.hash": "fedcba", "git.repository": "org/repo",
+	}}
+	require.NoError(t, workflow.PreparePreviewEnvironment(context.TODO(), db, wr3, hookEvent, u))
+	require.Equal(t, "staging-"+w.Name+"-pr-43-2", wr3.Workflow.WorkflowData.NodeByName("deploy").Context.EnvironmentName)
+
+	// The run deploying a preview builds the head of the pull request
+	wr5 := newPreviewRun(w, 5)
+	hookEvent = &sdk.WorkflowNodeRunHookEvent{Payload: map[string]string{
+		"git.hook": "pull_request", "git.pr.action": "opened", "git.pr.id": "44", "git.repository": "org/repo",
+		"git.pr.head.repository": "org/repo", "git.pr.head.branch": "feat/github", "git.pr.head.hash": "0123456789",
+	}}
+	require.NoError(t, workflow.PreparePreviewEnvironment(context.TODO(), db, wr5, hookEvent, u))
+	require.Equal(t, "feat/github", hookEvent.Payload["git.branch"])
+	require.Equal(t, "0123456789", hookEvent.Payload["git.hash"])
+	require.Equal(t, "0123456", hookEvent.Payload["git.hash.short"])
+
+	// A pull request from a fork is rejected even if the hook payload doesn't tell it is a fork
+	wr6 := newPreviewRun(w, 6)
+	hookEvent = &sdk.WorkflowNodeRunHookEvent{Payload: map[string]string{
+		"git.hook": "pr:opened", "git.pr.id": "45", "git.branch": "feat/fork", "git.hash": "abcdef", "git.repository": "someone/repo",
+	}}
+	err = workflow.PreparePreviewEnvironment(context.TODO(), db, wr6, hookEvent, u)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrForbidden))
+	require.Equal(t, tmpl.ID, wr6.Workflow.WorkflowData.NodeByName("deploy").Context.EnvironmentID)
+
+	// A push is not a preview event
+	wr4 := newPreviewRun(w, 4)
+	hookEvent = &sdk.WorkflowNodeRunHookEvent{Payload: map[string]string{"git.hook": "repo:refs_changed", "git.branch": "master"}}
+	require.NoError(t, workflow.PreparePreviewEnvironment(context.TODO(), db, wr4, hookEvent, u))
+	require.Equal(t, tmpl.ID, wr4.Workflow.WorkflowData.NodeByName("deploy").Context.EnvironmentID)
+	require.Empty(t, hookEvent.Payload[sdk.PreviewParamAction])
+}
+
+func TestUpdatePreviewEnvironment(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(t, db)
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+	w, _ := insertTestPreviewWorkflow(t, db, cache, proj, u)
+
+	wr := newPreviewRun(w, 1)
+	hookEvent := &sdk.WorkflowNodeRunHookEvent{Payload: map[string]string{
+		"git.hook": "pr:opened", "git.pr.id": "7", "git.branch": "feat/a", "git.hash": "abcdef", "git.repository": "org/repo",
+	}}
+	require.NoError(t, workflow.PreparePreviewEnvironment(context.TODO(), db, wr, hookEvent, u))
+	deploy := wr.Workflow.WorkflowData.NodeByName("deploy")
+	envID := deploy.Context.EnvironmentID
+
+	loadStatus := func() string {
+		p, err := application.LoadPreviewEnvironmentByEnvironmentID(db, envID)
+		require.NoError(t, err)
+		return p.Status
+	}
+
+	// The result of an older run is ignored
+	require.NoError(t, workflow.UpdatePreviewEnvironment(context.TODO(), db, deploy, &sdk.WorkflowNodeRun{Number: 0, Status: sdk.StatusFail}))
+	require.Equal(t, sdk.PreviewEnvironmentStatusDeploying, loadStatus())
+
+	require.NoError(t, workflow.UpdatePreviewEnvironment(context.TODO(), db, deploy, &sdk.WorkflowNodeRun{Number: 1, Status: sdk.StatusFail}))
+	require.Equal(t, sdk.PreviewEnvironmentStatusDeployFailed, loadStatus())
+	require.NoError(t, workflow.UpdatePreviewEnvironment(context.TODO(), db, deploy, &sdk.WorkflowNodeRun{Number: 1, Status: sdk.StatusSuccess}))
+	require.Equal(t, sdk.PreviewEnvironmentStatusDeployed, loadStatus())
+
+	// Merging the pull request tears down the preview environment
+	wr2 := newPreviewRun(w, 2)
+	hookEvent = &sdk.WorkflowNodeRunHookEvent{Payload: map[string]string{
+		"git.hook": "pr:merged", "git.pr.id": "7", "git.branch": "master", "git.branch.before": "feat/a",
+	}}
+	require.NoError(t, workflow.PreparePreviewEnvironment(context.TODO(), db, wr2, hookEvent, u))
+	teardown := wr2.Workflow.WorkflowData.NodeByName("teardown")
+	require.Equal(t, envID, teardown.Context.EnvironmentID)
+	require.Equal(t, sdk.PreviewEnvironmentStatusTearingDown, loadStatus())
+
+	// A failed teardown keeps the environment so the teardown pipeline can be restarted
+	require.NoError(t, workflow.UpdatePreviewEnvironment(context.TODO(), db, teardown, &sdk.WorkflowNodeRun{Number: 2, Status: sdk.StatusFail}))
+	require.Equal(t, sdk.PreviewEnvironmentStatusTeardownFailed, loadStatus())
+
+	// A successful restart deletes it
+	require.NoError(t, workflow.UpdatePreviewEnvironment(context.TODO(), db, teardown, &sdk.WorkflowNodeRun{Number: 2, Status: sdk.StatusSuccess}))
+	_, err := application.LoadPreviewEnvironmentByEnvironmentID(db, envID)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+	_, err = environment.LoadEnvironmentByID(db, envID)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrEnvironmentNotFound))
+}
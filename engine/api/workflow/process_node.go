@@ -9,6 +9,7 @@ import (
 
 	"github.com/rockbears/log"
 
+	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/gorpmapper"
@@ -257,6 +258,12 @@ func processNode(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cac
 		setValuesGitInBuildParameters(nr, *vcsInf)
 	}
 
+	// PREVIEW
+	if isPreviewNodeSkipped(wr, n, nr.BuildParameters) {
+		log.Debug(ctx, "Node %s skipped by preview on processNode %d/%d", n.Name, wr.ID, n.ID)
+		return nil, false, nil
+	}
+
 	// CONDITION
 	if !checkCondition(ctx, wr, n.Context.Conditions, nr.BuildParameters) {
 		log.Debug(ctx, "Conditions failed on processNode %d/%d", wr.ID, n.ID)
@@ -313,6 +320,28 @@ func processNode(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cac
 		}
 	}
 
+	// ENVIRONMENT PROTECTION AND APPROVAL
+	if nr.Status == sdk.StatusWaiting {
+		protection, reason, err := checkEnvironmentProtection(ctx, db, store, proj.Key, wr, n, app, nr)
+		if err != nil {
+			return nil, false, err
+		}
+		if reason != "" {
+			refuseNodeRun(wr, n, nr, reason)
+		} else {
+			if n.Context.Approval != nil {
+				triggeredByUserID, err := loadTriggeredByUserID(ctx, db, wr, parents, manual)
+				if err != nil {
+					return nil, false, err
+				}
+				nr.Approval = sdk.NewWorkflowNodeRunApproval(*n.Context.Approval, triggeredByUserID, nr.BuildParameters, time.Now())
+			}
+			if protection != nil {
+				nr.Approval = protection.AddApprovalGate(nr.Approval, time.Now())
+			}
+		}
+	}
+
 	if err := insertWorkflowNodeRun(db, nr); err != nil {
 		return nil, false, sdk.WrapError(err, "unable to insert run (node id : %d, node name : %s, subnumber : %d)", nr.WorkflowNodeID, nr.WorkflowNodeName, nr.SubNumber)
 	}
@@ -348,41 +377,25 @@ func processNode(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cac
 		return nil, false, sdk.WrapError(err, "unable to update workflow run")
 	}
 
-	//Check the context.mutex to know if we are allowed to run it
-	if n.Context.Mutex {
-		//Check if there are previous waiting or builing workflownoderun
-		// with the same workflow_node_name for the same workflow
-
-		// in this sql, we use 'and workflow_node_run.id < $2' and not and workflow_node_run.id <> $2
-		// we check if there is a previous build in waiting status
-		// and or if there is another build (never or not) with building status
-		mutexQuery := `select count(1)
-		from workflow_node_run
-		join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
-		join workflow on workflow.id = workflow_run.workflow_id
-		where workflow.id = $1
-		and workflow_node_run.workflow_node_name = $3
-		and (
-			(workflow_node_run.id < $2 and workflow_node_run.status = $4)
-			or
-			(workflow_node_run.id <> $2 and workflow_node_run.status = $5)
-		)`
-		nbMutex, err := db.SelectInt(mutexQuery, n.WorkflowID, nr.ID, n.Name, sdk.StatusWaiting, sdk.StatusBuilding)
-		if err != nil {
-			return nil, false, sdk.WrapError(err, "unable to check mutexes")
+	//Check the context.approval to know if reviewers have to approve the node run before executing it
+	if nr.Approval.IsPending() {
+		log.Debug(ctx, "Noderun %s processed but not executed because it is waiting for approval", n.Name)
+		AddWorkflowRunInfo(wr, sdk.SpawnMsgNew(*sdk.MsgWorkflowNodeApprovalWaiting, n.Name, nr.Approval.MinRequiredApprovals()))
+		if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+			return nil, false, sdk.WrapError(err, "unable to update workflow run")
 		}
-		if nbMutex > 0 {
-			log.Debug(ctx, "Noderun %s processed but not executed because of mutex", n.Name)
-			AddWorkflowRunInfo(wr, sdk.SpawnMsgNew(*sdk.MsgWorkflowNodeMutex, n.Name))
-			if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
-				return nil, false, sdk.WrapError(err, "unable to update workflow run")
-			}
+		return report, true, nil
+	}
 
-			// Mutex is locked, but it is as the workflow is ok to be run (conditions ok).
-			// it's ok exit without error
-			return report, true, nil
-		}
-		//Mutex is free, continue
+	//Check the context.mutex to know if we are allowed to run it
+	locked, err := checkNodeRunMutex(ctx, db, wr, n, nr)
+	if err != nil {
+		return nil, false, err
+	}
+	if locked {
+		// Mutex is locked, but it is as the workflow is ok to be run (conditions ok).
+		// it's ok exit without error
+		return report, true, nil
 	}
 
 	//Execute the node run !
@@ -394,6 +407,145 @@ func processNode(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cac
 	return report, true, nil
 }
 
+// checkEnvironmentProtection returns the reason why the node run can't be started on the environment of the node.
+// The protection rules are loaded from the database because they may have changed since the workflow run started.
+func checkEnvironmentProtection(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, projectKey string, wr *sdk.WorkflowRun, n *sdk.Node, app sdk.Application, nr *sdk.WorkflowNodeRun) (*sdk.EnvironmentProtection, string, error) {
+	if n.Context == nil || n.Context.EnvironmentID == 0 {
+		return nil, "", nil
+	}
+	protection, err := environment.LoadProtectionByID(db, n.Context.EnvironmentID)
+	if err != nil {
+		return nil, "", sdk.WrapError(err, "unable to load protection of environment %d", n.Context.EnvironmentID)
+	}
+	if protection == nil {
+		return nil, "", nil
+	}
+	if reason := protection.Check(wr.Workflow.Name, nr.VCSBranch, nr.VCSTag, time.Now()); reason != "" {
+		return protection, reason, nil
+	}
+	if len(protection.Branches) == 0 && len(protection.Tags) == 0 {
+		return protection, "", nil
+	}
+
+	// Pipelines without application are checked against the repository of the root application
+	if app.ID == 0 && wr.Workflow.WorkflowData.Node.Context != nil {
+		app = wr.Workflow.Applications[wr.Workflow.WorkflowData.Node.Context.ApplicationID]
+	}
+	// The tag is checked only if it is the ref allowed by the rules, otherwise the branch is
+	reason, err := checkProtectedGitRef(ctx, db, store, projectKey, app, nr, protection.AllowsTag(nr.VCSTag))
+	return protection, reason, err
+}
+
+// checkProtectedGitRef returns the reason why the git values of the node run can't be trusted by the branch and tag
+// rules of an environment protection. These values come from the run payload, so the commit must be the one of the
+// tag or be on the branch of the application repository, forks are refused.
+func checkProtectedGitRef(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, projectKey string, app sdk.Application, nr *sdk.WorkflowNodeRun, checkTag bool) (string, error) {
+	if app.VCSServer == "" || app.RepositoryFullname == "" {
+		return "git branch or tag can't be checked without an application repository", nil
+	}
+	if !strings.EqualFold(nr.VCSRepository, app.RepositoryFullname) {
+		return fmt.Sprintf("git repository %s is not allowed", nr.VCSRepository), nil
+	}
+	if nr.VCSHash == "" {
+		return "a git commit is required", nil
+	}
+
+	vcsServer, err := repositoriesmanager.LoadProjectVCSServerLinkByProjectKeyAndVCSServerName(ctx, db, projectKey, app.VCSServer)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot get vcs server %s for project %s", app.VCSServer, projectKey)
+	}
+	client, err := repositoriesmanager.AuthorizedClient(ctx, db, store, projectKey, vcsServer)
+	if err != nil {
+		return "", sdk.WrapError(err, "cannot get client")
+	}
+
+	if checkTag {
+		tags, err := client.Tags(ctx, app.RepositoryFullname)
+		if err != nil {
+			return "", err
+		}
+		for _, t := range tags {
+			if t.Tag == nr.VCSTag {
+				if t.Hash != nr.VCSHash {
+					return fmt.Sprintf("git commit %s is not the one of tag %s", nr.VCSHash, nr.VCSTag), nil
+				}
+				return "", nil
+			}
+		}
+		return fmt.Sprintf("git tag %s not found", nr.VCSTag), nil
+	}
+
+	branch, err := client.Branch(ctx, app.RepositoryFullname, nr.VCSBranch)
+	if err != nil || branch == nil {
+		log.Warn(ctx, "checkProtectedGitRef> unable to get branch %s of %s: %v", nr.VCSBranch, app.RepositoryFullname, err)
+		return fmt.Sprintf("git branch %s not found", nr.VCSBranch), nil
+	}
+	if branch.LatestCommit == nr.VCSHash {
+		return "", nil
+	}
+	// The commit is on the branch if it has no commit that is not reachable from the head of the branch
+	if _, err := client.Commit(ctx, app.RepositoryFullname, nr.VCSHash); err != nil {
+		return fmt.Sprintf("git commit %s not found", nr.VCSHash), nil
+	}
+	commits, err := client.CommitsBetweenRefs(ctx, app.RepositoryFullname, branch.LatestCommit, nr.VCSHash)
+	if err != nil {
+		return "", err
+	}
+	if len(commits) > 0 {
+		return fmt.Sprintf("git commit %s is not on branch %s", nr.VCSHash, nr.VCSBranch), nil
+	}
+	return "", nil
+}
+
+// refuseNodeRun fails a node run that doesn't respect the protection rules of its environment.
+func refuseNodeRun(wr *sdk.WorkflowRun, n *sdk.Node, nr *sdk.WorkflowNodeRun, reason string) {
+	nr.Status = sdk.StatusFail
+	nr.Done = time.Now()
+	nr.ProtectionViolation = reason
+	AddWorkflowRunInfo(wr, sdk.SpawnMsgNew(*sdk.MsgWorkflowNodeEnvironmentProtected, n.Name, wr.Workflow.Environments[n.Context.EnvironmentID].Name, reason))
+}
+
+// checkNodeRunMutex returns true if the node has a mutex that is locked by another node run.
+func checkNodeRunMutex(ctx context.Context, db gorpmapper.SqlExecutorWithTx, wr *sdk.WorkflowRun, n *sdk.Node, nr *sdk.WorkflowNodeRun) (bool, error) {
+	if !n.Context.Mutex {
+		return false, nil
+	}
+
+	//Check if there are previous waiting or builing workflownoderun
+	// with the same workflow_node_name for the same workflow
+
+	// in this sql, we use 'and workflow_node_run.id < $2' and not and workflow_node_run.id <> $2
+	// we check if there is a previous build in waiting status
+	// and or if there is another build (never or not) with building status
+	// node runs waiting for an approval don't lock the mutex
+	mutexQuery := `select count(1)
+	from workflow_node_run
+	join workflow_run on workflow_run.id = workflow_node_run.workflow_run_id
+	join workflow on workflow.id = workflow_run.workflow_id
+	where workflow.id = $1
+	and workflow_node_run.workflow_node_name = $3
+	and (
+		(workflow_node_run.id < $2 and workflow_node_run.status = $4 and coalesce(workflow_node_run.approval->>'status', '') <> $6)
+		or
+		(workflow_node_run.id <> $2 and workflow_node_run.status = $5)
+	)`
+	nbMutex, err := db.SelectInt(mutexQuery, n.WorkflowID, nr.ID, n.Name, sdk.StatusWaiting, sdk.StatusBuilding, sdk.ApprovalStatusPending)
+	if err != nil {
+		return false, sdk.WrapError(err, "unable to check mutexes")
+	}
+	if nbMutex == 0 {
+		//Mutex is free, continue
+		return false, nil
+	}
+
+	log.Debug(ctx, "Noderun %s processed but not executed because of mutex", n.Name)
+	AddWorkflowRunInfo(wr, sdk.SpawnMsgNew(*sdk.MsgWorkflowNodeMutex, n.Name))
+	if err := UpdateWorkflowRun(ctx, db, wr); err != nil {
+		return false, sdk.WrapError(err, "unable to update workflow run")
+	}
+	return true, nil
+}
+
 func getParentsStatus(wr *sdk.WorkflowRun, parents []*sdk.WorkflowNodeRun) string {
 	for _, p := range parents {
 		for _, v := range wr.WorkflowNodeRuns {
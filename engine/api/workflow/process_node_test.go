@@ -17,6 +17,7 @@ import (
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/authentication"
 	"github.com/ovh/cds/engine/api/bootstrap"
+	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/integration"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
@@ -2899,3 +2900,226 @@ func writeError(w *http.Response, err error) (*http.Response, error) {
 	w.StatusCode = sdkErr.Status
 	return w, sdkErr
 }
+
+func TestManualRunRefusedByEnvironmentProtection(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(t, db)
+
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+	pip := createEmptyPipeline(t, db, cache, proj, u)
+
+	env := sdk.Environment{
+		Name:       "production",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Protection: &sdk.EnvironmentProtection{Workflows: []string{"deploy-api"}},
+	}
+	require.NoError(t, environment.InsertEnvironment(db, &env))
+
+	w := sdk.Workflow{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       sdk.RandomString(10),
+		WorkflowData: sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "deploy",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID:    pip.ID,
+					EnvironmentID: env.ID,
+				},
+			},
+		},
+		Pipelines: map[int64]sdk.Pipeline{
+			pip.ID: *pip,
+		},
+		Environments: map[int64]sdk.Environment{
+			env.ID: env,
+		},
+	}
+	require.NoError(t, workflow.Insert(context.TODO(), db, cache, *proj, &w))
+
+	consumer, _ := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	opts := &sdk.WorkflowRunPostHandlerOption{
+		Manual:         &sdk.WorkflowNodeRunManual{},
+		AuthConsumerID: consumer.ID,
+	}
+	wr, err := workflow.CreateRun(db.DbMap, &w, *opts)
+	require.NoError(t, err)
+	wr.Workflow = w
+
+	_, err = workflow.StartWorkflowRun(context.TODO(), db, cache, *proj, wr, opts, *consumer, nil)
+	require.NoError(t, err)
+
+	// The node run is recorded as failed with the reason of the refusal
+	require.Len(t, wr.WorkflowNodeRuns[w.WorkflowData.Node.ID], 1)
+	nr, err := workflow.LoadNodeRunByID(db, wr.WorkflowNodeRuns[w.WorkflowData.Node.ID][0].ID, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusFail, nr.Status)
+	assert.Equal(t, "workflow "+w.Name+" is not allowed", nr.ProtectionViolation)
+	assert.Nil(t, nr.Approval)
+
+	run, err := workflow.LoadRunByID(db, wr.ID, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusFail, run.Status)
+	var found bool
+	for _, info := range run.Infos {
+		if info.Message.ID == sdk.MsgWorkflowNodeEnvironmentProtected.ID {
+			found = true
+		}
+	}
+	assert.True(t, found, "a run info should explain why the node run was refused")
+}
+
+// Payload: protected branch with a commit that is not on this branch
+func TestManualRunRefusedByEnvironmentProtectionOnCommitNotOnBranch(t *testing.T) {
+	db, cache := test.SetupPG(t, bootstrap.InitiliazeDB)
+	u, _ := assets.InsertAdminUser(t, db)
+
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, cache, key, key)
+	vcsServer := sdk.ProjectVCSServerLink{
+		ProjectID: proj.ID,
+		Name:      "github",
+	}
+	vcsServer.Set("token", "foo")
+	vcsServer.Set("secret", "bar")
+	require.NoError(t, repositoriesmanager.InsertProjectVCSServerLink(context.TODO(), db, &vcsServer))
+
+	srvs, err := services.LoadAll(context.Background(), db)
+	require.NoError(t, err)
+	for _, srv := range srvs {
+		require.NoError(t, services.Delete(db, &srv))
+	}
+
+	mockVCSSservice, _ := assets.InsertService(t, db, "TestManualRunRefusedByEnvironmentProtectionOnCommitNotOnBranch", sdk.TypeVCS)
+	defer func() {
+		services.Delete(db, mockVCSSservice)
+	}()
+
+	//This is a mock for the vcs service
+	services.HTTPClient = mock(
+		func(r *http.Request) (*http.Response, error) {
+			body := new(bytes.Buffer)
+			w := new(http.Response)
+			enc := json.NewEncoder(body)
+			w.Body = ioutil.NopCloser(body)
+
+			switch r.URL.String() {
+			case "/vcs/github/repos/sguiheux/demo":
+				repo := sdk.VCSRepo{
+					Name:         "demo",
+					ID:           "123",
+					Fullname:     "sguiheux/demo",
+					HTTPCloneURL: "https://github.com/sguiheux/demo.git",
+					SSHCloneURL:  "git://github.com/sguiheux/demo.git",
+				}
+				if err := enc.Encode(repo); err != nil {
+					return writeError(w, err)
+				}
+			case "/vcs/github/repos/sguiheux/demo/branches":
+				b := sdk.VCSBranch{
+					Default:      true,
+					DisplayID:    "master",
+					LatestCommit: "mastercommit",
+				}
+				if err := enc.Encode([]sdk.VCSBranch{b}); err != nil {
+					return writeError(w, err)
+				}
+			case "/vcs/github/repos/sguiheux/demo/branches/?branch=master":
+				b := sdk.VCSBranch{
+					Default:      true,
+					DisplayID:    "master",
+					LatestCommit: "mastercommit",
+				}
+				if err := enc.Encode(b); err != nil {
+					return writeError(w, err)
+				}
+			case "/vcs/github/repos/sguiheux/demo/commits/featurecommit":
+				c := sdk.VCSCommit{
+					Author: sdk.VCSAuthor{
+						Name:  "steven.guiheux",
+						Email: "sg@foo.bar",
+					},
+					Hash:      "featurecommit",
+					Message:   "unreviewed commit",
+					Timestamp: time.Now().Unix(),
+				}
+				if err := enc.Encode(c); err != nil {
+					return writeError(w, err)
+				}
+			// The feature commit is not reachable from the head of master
+			case "/vcs/github/repos/sguiheux/demo/commits?base=mastercommit&head=featurecommit":
+				if err := enc.Encode([]sdk.VCSCommit{{Hash: "featurecommit"}}); err != nil {
+					return writeError(w, err)
+				}
+			default:
+				t.Fatalf("UNKNOWN ROUTE: %s", r.URL.String())
+			}
+
+			return w, nil
+		},
+	)
+
+	pip := createEmptyPipeline(t, db, cache, proj, u)
+	app := createApplication1(t, db, cache, proj, u)
+
+	env := sdk.Environment{
+		Name:       "production",
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Protection: &sdk.EnvironmentProtection{Branches: []string{"master"}},
+	}
+	require.NoError(t, environment.InsertEnvironment(db, &env))
+
+	w := sdk.Workflow{
+		ProjectID:  proj.ID,
+		ProjectKey: proj.Key,
+		Name:       sdk.RandomString(10),
+		WorkflowData: sdk.WorkflowData{
+			Node: sdk.Node{
+				Name: "deploy",
+				Type: sdk.NodeTypePipeline,
+				Context: &sdk.NodeContext{
+					PipelineID:    pip.ID,
+					ApplicationID: app.ID,
+					EnvironmentID: env.ID,
+				},
+			},
+		},
+		Applications: map[int64]sdk.Application{
+			app.ID: *app,
+		},
+		Pipelines: map[int64]sdk.Pipeline{
+			pip.ID: *pip,
+		},
+		Environments: map[int64]sdk.Environment{
+			env.ID: env,
+		},
+	}
+	require.NoError(t, workflow.Insert(context.TODO(), db, cache, *proj, &w))
+
+	consumer, _ := authentication.LoadConsumerByTypeAndUserID(context.TODO(), db, sdk.ConsumerLocal, u.ID, authentication.LoadConsumerOptions.WithAuthentifiedUser)
+	opts := &sdk.WorkflowRunPostHandlerOption{
+		Manual: &sdk.WorkflowNodeRunManual{
+			Payload: map[string]string{
+				"git.branch": "master",
+				"git.hash":   "featurecommit",
+			},
+		},
+		AuthConsumerID: consumer.ID,
+	}
+	wr, err := workflow.CreateRun(db.DbMap, &w, *opts)
+	require.NoError(t, err)
+	wr.Workflow = w
+
+	_, err = workflow.StartWorkflowRun(context.TODO(), db, cache, *proj, wr, opts, *consumer, nil)
+	require.NoError(t, err)
+
+	require.Len(t, wr.WorkflowNodeRuns[w.WorkflowData.Node.ID], 1)
+	nr, err := workflow.LoadNodeRunByID(db, wr.WorkflowNodeRuns[w.WorkflowData.Node.ID][0].ID, workflow.LoadRunOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusFail, nr.Status)
+	assert.Equal(t, "git commit featurecommit is not on branch master", nr.ProtectionViolation)
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"path/filepath"
+	"sort"
 
 	"github.com/fsamin/go-dump"
 	"github.com/go-gorp/gorp"
@@ -66,6 +67,13 @@ func CreateFromRepository(ctx context.Context, db *gorp.DbMap, store cache.Store
 		return nil, nil, sdk.NewError(sdk.ErrRepoAnalyzeFailed, err)
 	}
 
+	// Nothing changed under the workflow root, the workflow stored in database is up to date
+	if ope.LoadFiles.Unchanged {
+		log.Info(ctx, "workflow %s/%s is up to date with root %s", p.Key, wf.Name, ope.LoadFiles.Root)
+		secrets, err := RetrieveSecrets(db, *wf)
+		return secrets, nil, err
+	}
+
 	var uuid string
 	if opts.Hook != nil {
 		uuid = opts.Hook.WorkflowNodeHookUUID
@@ -100,7 +108,7 @@ func extractWorkflow(ctx context.Context, db *gorp.DbMap, store cache.Store, p *
 		RepositoryName:     ope.RepoFullName,
 		RepositoryStrategy: ope.RepositoryStrategy,
 		Branch:             ope.Setup.Checkout.Branch,
-		FromRepository:     ope.RepositoryInfo.FetchURL,
+		FromRepository:     sdk.NewAsCodeFromRepository(ope.RepositoryInfo.FetchURL, ope.LoadFiles.Root),
 		IsDefaultBranch:    ope.Setup.Checkout.Tag == "" && ope.Setup.Checkout.Branch == ope.RepositoryInfo.DefaultBranch,
 		HookUUID:           hookUUID,
 		OldWorkflow:        *wf,
@@ -177,16 +185,33 @@ func ReadCDSFiles(files map[string][]byte) (*tar.Reader, error) {
 	return tar.NewReader(buf), nil
 }
 
+// SplitCDSFilesByRoot groups CDS files by workflow root, roots are returned sorted.
+func SplitCDSFilesByRoot(files map[string][]byte) ([]string, map[string]map[string][]byte) {
+	res := make(map[string]map[string][]byte)
+	var roots []string
+	for fname, fcontent := range files {
+		root := sdk.AsCodeRootFromFile(fname)
+		if _, has := res[root]; !has {
+			res[root] = make(map[string][]byte)
+			roots = append(roots, root)
+		}
+		res[root][fname] = fcontent
+	}
+	sort.Strings(roots)
+	return roots, res
+}
+
 func createOperationRequest(w sdk.Workflow, opts sdk.WorkflowRunPostHandlerOption) (sdk.Operation, error) {
 	ope := sdk.Operation{}
 	if w.WorkflowData.Node.Context.ApplicationID == 0 {
 		return ope, sdk.WrapError(sdk.ErrNotFound, "workflow node root does not have a application context")
 	}
 	app := w.Applications[w.WorkflowData.Node.Context.ApplicationID]
+	url, root := sdk.SplitAsCodeFromRepository(w.FromRepository)
 	ope = sdk.Operation{
 		VCSServer:          app.VCSServer,
 		RepoFullName:       app.RepositoryFullname,
-		URL:                w.FromRepository,
+		URL:                url,
 		RepositoryStrategy: app.RepositoryStrategy,
 		Setup: sdk.OperationSetup{
 			Checkout: sdk.OperationCheckout{
@@ -195,7 +220,8 @@ func createOperationRequest(w sdk.Workflow, opts sdk.WorkflowRunPostHandlerOptio
 			},
 		},
 		LoadFiles: sdk.OperationLoadFiles{
-			Pattern: WorkflowAsCodePattern,
+			Pattern: sdk.AsCodeFilesPattern(root),
+			Root:    root,
 		},
 	}
 
@@ -204,6 +230,10 @@ func createOperationRequest(w sdk.Workflow, opts sdk.WorkflowRunPostHandlerOptio
 		tag = opts.Hook.Payload[tagGitTag]
 		branch = opts.Hook.Payload[tagGitBranch]
 		commit = opts.Hook.Payload[tagGitHash]
+		// On a push, files of a workflow root are only reloaded if something changed under the root
+		if root != "" {
+			ope.LoadFiles.ChangedSince = opts.Hook.Payload[tagGitHashBefore]
+		}
 	}
 	if opts.Manual != nil {
 		e := dump.NewDefaultEncoder()
@@ -16,6 +16,7 @@ const (
 	tagEnvironment   = "environment"
 	tagGitHash       = "git.hash"
 	tagGitHashShort  = "git.hash.short"
+	tagGitHashBefore = "git.hash.before"
 	tagGitRepository = "git.repository"
 	tagGitBranch     = "git.branch"
 	tagGitTag        = "git.tag"
@@ -123,6 +124,7 @@ func StartWorkflowRun(ctx context.Context, db gorpmapper.SqlExecutorWithTx, stor
 		opts.Manual.Username = u.GetUsername()
 		opts.Manual.Email = u.GetEmail()
 		opts.Manual.Fullname = u.GetFullname()
+		opts.Manual.UserID = u.AuthentifiedUserID
 
 		if len(opts.FromNodeIDs) > 0 && len(wr.WorkflowNodeRuns) > 0 {
 			// MANUAL RUN FROM NODE
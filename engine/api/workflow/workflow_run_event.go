@@ -2,12 +2,14 @@ package workflow
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"sort"
 	"time"
 
 	"github.com/go-gorp/gorp"
+	"github.com/ovh/venom"
 	"github.com/rockbears/log"
 
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
@@ -176,6 +178,11 @@ loopNotif:
 		return sdk.WithStack(err)
 	}
 
+	// Insight reports are not supported by all VCS servers, an error should not abort the delivery of statuses and comments
+	if err := e.sendVCSInsightReport(ctx, db, proj.Key, wr, &nodeRun, notif); err != nil {
+		log.Error(ctx, "SendVCSEvent> unable to send insight report for node run %d: %v", nodeRun.ID, err)
+	}
+
 	return nil
 }
 
@@ -382,3 +389,142 @@ func (e *VCSEventMessenger) sendVCSPullRequestComment(ctx context.Context, db go
 	}
 	return nil
 }
+
+// sendVCSInsightReport pushes tests, coverage and vulnerabilities results of a terminated node run on its commit.
+func (e *VCSEventMessenger) sendVCSInsightReport(ctx context.Context, db gorp.SqlExecutor, projectKey string, wr sdk.WorkflowRun, nodeRun *sdk.WorkflowNodeRun, notif *sdk.WorkflowNotification) error {
+	if notif == nil || notif.Settings.Template == nil || (notif.Settings.Template.DisableReport != nil && *notif.Settings.Template.DisableReport) {
+		return nil
+	}
+
+	node := wr.Workflow.WorkflowData.NodeByID(nodeRun.WorkflowNodeID)
+	if !node.IsLinkedToRepo(&wr.Workflow) {
+		return nil
+	}
+	app := wr.Workflow.Applications[node.Context.ApplicationID]
+
+	if nodeRun.Tests == nil {
+		nr, err := LoadNodeRunByID(db, nodeRun.ID, LoadRunOptions{WithTests: true})
+		if err != nil && sdk.Cause(err) != sql.ErrNoRows {
+			return err
+		}
+		if nr != nil {
+			nodeRun.Tests = nr.Tests
+		}
+	}
+
+	var cov *sdk.WorkflowNodeRunCoverage
+	c, err := LoadCoverageReport(db, nodeRun.ID)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return err
+	}
+	if err == nil {
+		cov = &c
+	}
+
+	vulns, err := loadVulnerabilityReport(db, nodeRun.ID)
+	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return err
+	}
+
+	report := NewVCSInsightReport(projectKey, wr.Workflow.Name, *nodeRun, cov, vulns)
+	if report == nil {
+		return nil
+	}
+
+	log.Debug(ctx, "Send insight report %s for node run %d", report.Key, nodeRun.ID)
+	return e.vcsClient.SetInsightReport(ctx, app.RepositoryFullname, *report)
+}
+
+// NewVCSInsightReport returns the insight report of given node run, or nil if the node run has no tests, coverage or
+// vulnerabilities results.
+func NewVCSInsightReport(projectKey, workflowName string, nodeRun sdk.WorkflowNodeRun, cov *sdk.WorkflowNodeRunCoverage, vulns *sdk.WorkflowNodeRunVulnerabilityReport) *sdk.VCSInsightReport {
+	if nodeRun.Tests == nil && cov == nil && vulns == nil {
+		return nil
+	}
+
+	report := sdk.VCSInsightReport{
+		Key: sdk.VCSInsightReportKey(projectKey, workflowName, nodeRun.WorkflowNodeName),
+		Title: sdk.VCSCommitStatusDescription(projectKey, workflowName, sdk.EventRunWorkflowNode{
+			NodeName: nodeRun.WorkflowNodeName,
+		}),
+		Reporter: "CDS",
+		Result:   sdk.VCSInsightReportResultPass,
+		Revision: nodeRun.VCSHash,
+		Branch:   nodeRun.VCSBranch,
+	}
+	if p := sdk.ParameterFind(nodeRun.BuildParameters, "cds.ui.pipeline.run"); p != nil {
+		report.Link = p.Value
+	}
+	if nodeRun.Status == sdk.StatusFail || nodeRun.Status == sdk.StatusStopped {
+		report.Result = sdk.VCSInsightReportResultFail
+	}
+
+	if nodeRun.Tests != nil {
+		report.Tests = &sdk.VCSInsightTests{
+			Total:   nodeRun.Tests.Total,
+			OK:      nodeRun.Tests.TotalOK,
+			KO:      nodeRun.Tests.TotalKO,
+			Skipped: nodeRun.Tests.TotalSkipped,
+		}
+		if nodeRun.Tests.TotalKO > 0 {
+			report.Result = sdk.VCSInsightReportResultFail
+		}
+		for _, ts := range nodeRun.Tests.TestSuites {
+			for _, tc := range ts.TestCases {
+				failures := make([]venom.Failure, 0, len(tc.Failures)+len(tc.Errors))
+				failures = append(failures, tc.Failures...)
+				failures = append(failures, tc.Errors...)
+				if len(failures) == 0 {
+					continue
+				}
+				message := failures[0].Message
+				if message == "" {
+					message = failures[0].Value
+				}
+				if len(message) > 500 {
+					message = message[:497] + "..."
+				}
+				report.Annotations = append(report.Annotations, sdk.VCSInsightAnnotation{
+					Severity: sdk.VCSInsightAnnotationSeverityHigh,
+					Type:     sdk.VCSInsightAnnotationTypeBug,
+					Title:    fmt.Sprintf("%s / %s", ts.Name, tc.Name),
+					Message:  message,
+					Link:     report.Link,
+				})
+			}
+		}
+	}
+
+	if cov != nil {
+		report.Coverage = &sdk.VCSInsightCoverage{
+			TotalLines:   cov.Report.TotalLines,
+			CoveredLines: cov.Report.CoveredLines,
+		}
+	}
+
+	if vulns != nil {
+		report.Vulnerabilities = vulns.Report.Summary
+		for _, v := range vulns.Report.Vulnerabilities {
+			if v.Ignored {
+				continue
+			}
+			title := v.Title
+			if v.CVE != "" {
+				title = fmt.Sprintf("%s %s", v.CVE, v.Title)
+			}
+			message := fmt.Sprintf("%s %s", v.Component, v.Version)
+			if v.FixIn != "" {
+				message += fmt.Sprintf(" (fixed in %s)", v.FixIn)
+			}
+			report.Annotations = append(report.Annotations, sdk.VCSInsightAnnotation{
+				Severity: sdk.VulnerabilitySeverityToInsight(v.Severity),
+				Type:     sdk.VCSInsightAnnotationTypeVulnerability,
+				Title:    title,
+				Message:  message,
+				Link:     v.Link,
+			})
+		}
+	}
+
+	return &report
+}
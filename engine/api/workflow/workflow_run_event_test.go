@@ -10,7 +10,10 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/ovh/venom"
+	"github.com/sguiheux/go-coverage"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
@@ -570,3 +573,51 @@ func TestResyncCommitStatusCommitCache(t *testing.T) {
 	err := e.SendVCSEvent(ctx, db.DbMap, cache, *proj, *wr, wr.WorkflowNodeRuns[1][0])
 	assert.NoError(t, err)
 }
+
+func TestNewVCSInsightReport(t *testing.T) {
+	nodeRun := sdk.WorkflowNodeRun{
+		WorkflowNodeName: "build",
+		Status:           sdk.StatusSuccess,
+		VCSHash:          "6c3efde",
+		VCSBranch:        "feat/foo",
+		BuildParameters: []sdk.Parameter{
+			{Name: "cds.ui.pipeline.run", Type: sdk.StringParameter, Value: "https://cds/run/1"},
+		},
+	}
+	require.Nil(t, workflow.NewVCSInsightReport("PROJ", "my-workflow", nodeRun, nil, nil))
+
+	nodeRun.Tests = &venom.Tests{
+		Total: 2, TotalOK: 1, TotalKO: 1,
+		TestSuites: []venom.TestSuite{{
+			Name: "suite",
+			TestCases: []venom.TestCase{
+				{Name: "ok"},
+				{Name: "ko", Failures: []venom.Failure{{Message: "expected 1, got 2"}}},
+			},
+		}},
+	}
+	cov := &sdk.WorkflowNodeRunCoverage{Report: coverage.Report{TotalLines: 10, CoveredLines: 8}}
+	vulns := &sdk.WorkflowNodeRunVulnerabilityReport{Report: sdk.WorkflowNodeRunVulnerability{
+		Summary: map[string]int64{sdk.SeverityHigh: 1},
+		Vulnerabilities: []sdk.Vulnerability{
+			{Title: "prototype pollution", CVE: "CVE-1", Component: "lodash", Version: "4.0.0", FixIn: "4.17.21", Severity: sdk.SeverityHigh},
+			{Title: "ignored", Severity: sdk.SeverityLow, Ignored: true},
+		},
+	}}
+
+	report := workflow.NewVCSInsightReport("PROJ", "my-workflow", nodeRun, cov, vulns)
+	require.NotNil(t, report)
+	assert.Equal(t, "cds.PROJ.my-workflow.build", report.Key)
+	assert.Equal(t, "CDS/PROJ-my-workflow-build", report.Title)
+	assert.Equal(t, sdk.VCSInsightReportResultFail, report.Result)
+	assert.Equal(t, "6c3efde", report.Revision)
+	assert.Equal(t, "https://cds/run/1", report.Link)
+	assert.Equal(t, &sdk.VCSInsightTests{Total: 2, OK: 1, KO: 1}, report.Tests)
+	assert.Equal(t, 80.0, report.Coverage.Percent())
+	require.Len(t, report.Annotations, 2)
+	assert.Equal(t, "suite / ko", report.Annotations[0].Title)
+	assert.Equal(t, "expected 1, got 2", report.Annotations[0].Message)
+	assert.Equal(t, sdk.VCSInsightAnnotationSeverityHigh, report.Annotations[1].Severity)
+	assert.Equal(t, "CVE-1 prototype pollution", report.Annotations[1].Title)
+	assert.Equal(t, "lodash 4.0.0 (fixed in 4.17.21)", report.Annotations[1].Message)
+}
@@ -0,0 +1,138 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// NewRunResultProvenance returns the SLSA provenance statement of a run result produced by given job.
+func NewRunResultProvenance(builderID string, wr sdk.WorkflowRun, nr sdk.WorkflowNodeRun, job sdk.WorkflowNodeJobRun, result sdk.WorkflowRunResult) (*sdk.InTotoStatement, error) {
+	name, digest, err := result.GetNameAndSHA256()
+	if err != nil {
+		return nil, err
+	}
+	if digest == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrInvalidData, "missing sha256 digest for run result %s", name)
+	}
+
+	n := wr.Workflow.WorkflowData.NodeByID(nr.WorkflowNodeID)
+	if n == nil || n.Context == nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "unable to find node %d in workflow run %d", nr.WorkflowNodeID, wr.ID)
+	}
+	pip, has := wr.Workflow.Pipelines[n.Context.PipelineID]
+	if !has {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "unable to find pipeline %d in workflow run %d", n.Context.PipelineID, wr.ID)
+	}
+
+	workflowDefinition, err := json.Marshal(wr.Workflow.WorkflowData)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	pipelineDefinition, err := json.Marshal(pip)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+
+	// Secrets are never part of the attestation
+	params := make(map[string]string, len(nr.BuildParameters))
+	for _, p := range nr.BuildParameters {
+		if sdk.NeedPlaceholder(p.Type) {
+			continue
+		}
+		params[p.Name] = p.Value
+	}
+
+	invocationID := sdk.ParameterValue(nr.BuildParameters, "cds.ui.pipeline.run")
+	if invocationID == "" {
+		invocationID = fmt.Sprintf("%s/%s/%d.%d/%d", wr.Workflow.ProjectKey, wr.Workflow.Name, wr.Number, nr.SubNumber, job.ID)
+	}
+
+	provenance := sdk.SLSAProvenance{
+		Builder:   sdk.SLSABuilder{ID: builderID},
+		BuildType: sdk.ProvenanceBuildType,
+		Invocation: sdk.SLSAInvocation{
+			ConfigSource: sdk.SLSAConfigSource{
+				EntryPoint: fmt.Sprintf("%s/%s", wr.Workflow.Name, pip.Name),
+			},
+			Parameters: params,
+			Environment: sdk.SLSAProvenanceBuildEnv{
+				ProjectKey:             wr.Workflow.ProjectKey,
+				WorkflowName:           wr.Workflow.Name,
+				WorkflowDefinitionHash: sdk.SHA256Hex(workflowDefinition),
+				PipelineName:           pip.Name,
+				PipelineDefinitionHash: sdk.SHA256Hex(pipelineDefinition),
+				RunNumber:              wr.Number,
+				RunSubNumber:           nr.SubNumber,
+				NodeRunID:              nr.ID,
+				JobName:                job.Job.Action.Name,
+				JobRunID:               job.ID,
+				WorkerModel:            job.Model,
+				WorkerModelType:        job.ModelType,
+			},
+		},
+		Metadata: sdk.SLSAMetadata{
+			BuildInvocationID: invocationID,
+		},
+	}
+	if !nr.Start.IsZero() {
+		start := nr.Start
+		provenance.Metadata.BuildStartedOn = &start
+	}
+
+	// The git repository is the source of the build
+	gitURL := sdk.ParameterValue(nr.BuildParameters, "git.http_url")
+	if gitURL == "" {
+		gitURL = sdk.ParameterValue(nr.BuildParameters, "git.url")
+	}
+	if gitURL == "" {
+		gitURL = nr.VCSRepository
+	}
+	if gitURL != "" && nr.VCSHash != "" {
+		gitDigest := map[string]string{"sha1": nr.VCSHash}
+		uri := "git+" + gitURL
+		if ref := sdk.IDTokenGitRef(nr.VCSBranch, nr.VCSTag); ref != "" {
+			provenance.Invocation.ConfigSource.URI = uri + "@" + ref
+		} else {
+			provenance.Invocation.ConfigSource.URI = uri
+		}
+		provenance.Invocation.ConfigSource.Digest = gitDigest
+		provenance.Materials = append(provenance.Materials, sdk.SLSAMaterial{URI: uri, Digest: gitDigest})
+	}
+
+	return &sdk.InTotoStatement{
+		Type:          sdk.InTotoStatementType,
+		PredicateType: sdk.SLSAProvenancePredicateV2,
+		Subject:       []sdk.InTotoSubject{{Name: name, Digest: map[string]string{"sha256": digest}}},
+		Predicate:     provenance,
+	}, nil
+}
+
+// AddProvenanceResult inserts the run result of a provenance attestation stored in CDN.
+func AddProvenanceResult(ctx context.Context, db *gorp.DbMap, runResult *sdk.WorkflowRunResult) error {
+	if runResult.Type != sdk.WorkflowRunResultTypeProvenance {
+		return sdk.NewErrorFrom(sdk.ErrInvalidData, "invalid result type %s", runResult.Type)
+	}
+	provenance, err := runResult.GetProvenance()
+	if err != nil {
+		return err
+	}
+	if err := provenance.IsValid(); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() //nolint
+
+	if err := insertResult(tx, runResult); err != nil {
+		return err
+	}
+	return sdk.WithStack(tx.Commit())
+}
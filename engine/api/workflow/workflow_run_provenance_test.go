@@ -0,0 +1,64 @@
+package workflow_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestNewRunResultProvenance(t *testing.T) {
+	wr := sdk.WorkflowRun{
+		ID:     1,
+		Number: 42,
+		Workflow: sdk.Workflow{
+			Name:       "my-workflow",
+			ProjectKey: "MYPROJ",
+			WorkflowData: sdk.WorkflowData{
+				Node: sdk.Node{ID: 10, Name: "build", Context: &sdk.NodeContext{PipelineID: 100}},
+			},
+			Pipelines: map[int64]sdk.Pipeline{100: {ID: 100, Name: "build"}},
+		},
+	}
+	nr := sdk.WorkflowNodeRun{
+		ID:             1000,
+		WorkflowNodeID: 10,
+		SubNumber:      1,
+		VCSBranch:      "master",
+		VCSHash:        "8c4d7b9f",
+		BuildParameters: []sdk.Parameter{
+			{Name: "git.http_url", Type: sdk.StringParameter, Value: "https://github.com/ovh/cds.git"},
+			{Name: "cds.version", Type: sdk.StringParameter, Value: "42"},
+			{Name: "cds.proj.password", Type: sdk.SecretVariable, Value: "secret"},
+		},
+	}
+	job := sdk.WorkflowNodeJobRun{ID: 10000, Model: "shared.infra/go", ModelType: sdk.Docker}
+	job.Job.Action.Name = "Build binary"
+
+	art, err := json.Marshal(sdk.WorkflowRunResultArtifact{Name: "my-binary", SHA256: "abcdef"})
+	require.NoError(t, err)
+	result := sdk.WorkflowRunResult{Type: sdk.WorkflowRunResultTypeArtifact, DataRaw: art}
+
+	s, err := workflow.NewRunResultProvenance("https://cds.local", wr, nr, job, result)
+	require.NoError(t, err)
+	require.Equal(t, []sdk.InTotoSubject{{Name: "my-binary", Digest: map[string]string{"sha256": "abcdef"}}}, s.Subject)
+	require.Equal(t, "https://cds.local", s.Predicate.Builder.ID)
+	require.Equal(t, "git+https://github.com/ovh/cds.git@refs/heads/master", s.Predicate.Invocation.ConfigSource.URI)
+	require.Equal(t, "8c4d7b9f", s.Predicate.Invocation.ConfigSource.Digest["sha1"])
+	require.Equal(t, "my-workflow/build", s.Predicate.Invocation.ConfigSource.EntryPoint)
+	require.Equal(t, "42", s.Predicate.Invocation.Parameters["cds.version"])
+	require.NotContains(t, s.Predicate.Invocation.Parameters, "cds.proj.password")
+	require.Equal(t, "shared.infra/go", s.Predicate.Invocation.Environment.WorkerModel)
+	require.Equal(t, "Build binary", s.Predicate.Invocation.Environment.JobName)
+	require.NotEmpty(t, s.Predicate.Invocation.Environment.PipelineDefinitionHash)
+	require.Equal(t, "MYPROJ/my-workflow/42.1/10000", s.Predicate.Metadata.BuildInvocationID)
+
+	// Results uploaded without digest can't be attested
+	art, err = json.Marshal(sdk.WorkflowRunResultArtifact{Name: "my-binary"})
+	require.NoError(t, err)
+	_, err = workflow.NewRunResultProvenance("https://cds.local", wr, nr, job, sdk.WorkflowRunResult{Type: sdk.WorkflowRunResultTypeArtifact, DataRaw: art})
+	require.Error(t, err)
+}
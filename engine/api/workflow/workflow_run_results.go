@@ -32,6 +32,9 @@ func CanUploadRunResult(ctx context.Context, db *gorp.DbMap, store cache.Store,
 	if sdk.StatusIsTerminated(wr.Status) {
 		return false, sdk.WrapError(sdk.ErrInvalidData, "unable to upload artifact on a terminated run")
 	}
+	if runResultCheck.ResultType == sdk.WorkflowRunResultTypeProvenance {
+		return false, sdk.NewErrorFrom(sdk.ErrForbidden, "provenance attestations can only be generated by CDS")
+	}
 
 	// Check node run
 	var nrs []sdk.WorkflowNodeRun
@@ -203,6 +206,7 @@ func verifyAddResultArtifactManager(ctx context.Context, db gorp.SqlExecutor, st
 	}
 	artResult.Size = fileInfo.Size
 	artResult.MD5 = fileInfo.Md5
+	artResult.SHA256 = fileInfo.Sha256
 	artResult.RepoType = fileInfo.Type
 
 	if err := artResult.IsValid(); err != nil {
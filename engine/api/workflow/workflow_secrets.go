@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/go-gorp/gorp"
 
@@ -54,7 +55,12 @@ func LoadApplicationSecrets(db gorp.SqlExecutor, id int64) ([]sdk.Variable, erro
 		})
 	}
 
+	now := time.Now()
 	for _, k := range appDB.Keys {
+		// Rotated keys can't be used after their overlap period
+		if k.IsRetired(now) {
+			continue
+		}
 		secretsVariables = append(secretsVariables, sdk.Variable{
 			Name:  fmt.Sprintf("cds.key.%s.priv", k.Name),
 			Type:  string(k.Type),
@@ -133,7 +133,7 @@ func (api *API) postWorkflowAsCodeHandler() service.Handler {
 		}
 		defer tx2.Rollback() // nolint
 
-		ope, err := operation.PushOperationUpdate(ctx, tx2, api.Cache, *p, data, rootApp.VCSServer, rootApp.RepositoryFullname, branch, message, rootApp.RepositoryStrategy, u)
+		ope, err := operation.PushOperationUpdate(ctx, tx2, api.Cache, *p, data, rootApp.VCSServer, rootApp.RepositoryFullname, rootApp.FromRepository, branch, message, rootApp.RepositoryStrategy, u)
 		if err != nil {
 			return err
 		}
@@ -18,6 +18,7 @@ func (api *API) WorkflowSendEvent(ctx context.Context, proj sdk.Project, report
 	}
 	for _, wr := range report.Workflows() {
 		event.PublishWorkflowRun(ctx, wr, proj.Key)
+		notification.SendUserSubscriptions(ctx, api.mustDB(), api.Cache, wr)
 	}
 	for _, wnr := range report.Nodes() {
 		wr, err := workflow.LoadRunByID(api.mustDB(), wnr.WorkflowRunID, workflow.LoadRunOptions{
@@ -53,6 +54,10 @@ func (api *API) WorkflowSendEvent(ctx context.Context, proj sdk.Project, report
 		}
 		eventsNotif := notification.GetUserWorkflowEvents(ctx, api.mustDB(), api.Cache, wr.Workflow.ProjectID, wr.Workflow.ProjectKey, workDB.Name, wr.Workflow.Notifications, previousNodeRun, *nr)
 		event.PublishWorkflowNodeRun(ctx, *nr, wr.Workflow, eventsNotif)
+		if nr.Approval.IsPending() && len(nr.Approval.Decisions) == 0 {
+			notification.SendApprovalRequest(ctx, api.mustDB(), wr.Workflow.ProjectKey, workDB.Name, *nr)
+			notification.SendChatApprovalRequest(ctx, api.mustDB(), wr.Workflow.ProjectKey, workDB.Name, wr.Workflow.Notifications, *nr)
+		}
 		e := &workflow.VCSEventMessenger{}
 		if err := e.SendVCSEvent(ctx, api.mustDB(), api.Cache, proj, *wr, wnr); err != nil {
 			log.Warn(ctx, "WorkflowSendEvent> Cannot send vcs notification")
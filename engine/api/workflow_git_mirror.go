@@ -0,0 +1,142 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/application"
+	"github.com/ovh/cds/engine/api/operation"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+// refreshRepositoryMirrors refreshes the mirrors of the repositories of the applications used by given node runs.
+func (api *API) refreshRepositoryMirrors(ctx context.Context, projKey string, nodeRuns []sdk.WorkflowNodeRun) {
+	proj, err := project.Load(ctx, api.mustDB(), projKey, project.LoadOptions.WithClearKeys)
+	if err != nil {
+		log.Error(ctx, "refreshRepositoryMirrors> unable to load project %s: %v", projKey, err)
+		return
+	}
+
+	done := make(map[int64]struct{})
+	for _, nr := range nodeRuns {
+		if nr.ApplicationID == 0 {
+			continue
+		}
+		if _, has := done[nr.ApplicationID]; has {
+			continue
+		}
+		done[nr.ApplicationID] = struct{}{}
+
+		app, err := application.LoadByIDWithClearVCSStrategyPassword(api.mustDB(), nr.ApplicationID)
+		if err != nil {
+			log.Error(ctx, "refreshRepositoryMirrors> unable to load application %d: %v", nr.ApplicationID, err)
+			continue
+		}
+		if app.RepositoryFullname == "" {
+			continue
+		}
+		urlParam := "git.http_url"
+		if app.RepositoryStrategy.ConnectionType == "ssh" {
+			urlParam = "git.url"
+		}
+		p := sdk.ParameterFind(nr.BuildParameters, urlParam)
+		if p == nil || p.Value == "" {
+			continue
+		}
+		if err := operation.RefreshRepositoryMirror(ctx, api.mustDB(), *proj, p.Value, app.RepositoryStrategy); err != nil {
+			log.Error(ctx, "refreshRepositoryMirrors> unable to refresh mirror of %s: %v", p.Value, err)
+		}
+	}
+}
+
+// jobGitMirrorIDs returns the ids of the mirrors that a job is allowed to clone from, they are the
+// mirrors of the repository of the job's application in the job's project.
+func (api *API) jobGitMirrorIDs(ctx context.Context, jobID int64) (map[string]string, error) {
+	j, err := workflow.LoadNodeJobRun(ctx, api.mustDB(), api.Cache, jobID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "job not found")
+	}
+	projKey := sdk.ParameterValue(j.Parameters, "cds.project")
+	ids := make(map[string]string)
+	for _, k := range []string{"git.url", "git.http_url"} {
+		if p := sdk.ParameterFind(j.Parameters, k); p != nil && p.Value != "" {
+			ids[sdk.RepositoryMirrorID(projKey, p.Value)] = p.Value
+		}
+	}
+	return ids, nil
+}
+
+func (api *API) getWorkflowJobGitMirrorHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if isWorker := isWorker(ctx); !isWorker {
+			return sdk.WithStack(sdk.ErrForbidden)
+		}
+		id, err := requestVarInt(r, "permJobID")
+		if err != nil {
+			return err
+		}
+		repoURL := FormString(r, "url")
+		if repoURL == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing repository url")
+		}
+
+		ids, err := api.jobGitMirrorIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+		var mirrorID string
+		for id, u := range ids {
+			if u == repoURL {
+				mirrorID = id
+			}
+		}
+		if mirrorID == "" {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "no mirror for repository %s", repoURL)
+		}
+		if _, err := operation.LoadRepositoryMirrorService(ctx, api.mustDB(), mirrorID); err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, sdk.GitMirror{ID: mirrorID}, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowJobGitMirrorInfoRefsHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return api.proxyWorkflowJobGitMirror(ctx, w, r, "/info/refs")
+	}
+}
+
+func (api *API) postWorkflowJobGitMirrorUploadPackHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		return api.proxyWorkflowJobGitMirror(ctx, w, r, "/git-upload-pack")
+	}
+}
+
+// proxyWorkflowJobGitMirror forwards the git smart http requests of a worker to the mirror of its repository.
+func (api *API) proxyWorkflowJobGitMirror(ctx context.Context, w http.ResponseWriter, r *http.Request, subPath string) error {
+	if isWorker := isWorker(ctx); !isWorker {
+		return sdk.WithStack(sdk.ErrForbidden)
+	}
+	id, err := requestVarInt(r, "permJobID")
+	if err != nil {
+		return err
+	}
+	mirrorID := mux.Vars(r)["mirrorID"]
+
+	ids, err := api.jobGitMirrorIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, has := ids[mirrorID]; !has {
+		return sdk.WithStack(sdk.ErrNotFound)
+	}
+
+	return operation.ProxyRepositoryMirror(ctx, api.mustDB(), w, r, mirrorID, subPath)
+}
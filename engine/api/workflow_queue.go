@@ -25,6 +25,7 @@ import (
 	"github.com/ovh/cds/engine/api/notification"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workermodel"
@@ -108,6 +109,10 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 		pbji := &sdk.WorkflowNodeJobRunData{}
 		report, err := takeJob(ctx, api.mustDB, api.Cache, p, id, workerModelName, pbji, wk, hatcheryName)
 		if err != nil {
+			// The job may have failed while being taken
+			if report != nil {
+				go api.WorkflowSendEvent(context.Background(), *p, report)
+			}
 			return sdk.WrapError(err, "cannot takeJob nodeJobRunID:%d", id)
 		}
 
@@ -137,6 +142,25 @@ func (api *API) postTakeWorkflowJobHandler() service.Handler {
 }
 
 func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store, p *sdk.Project, id int64, workerModel string, wnjri *sdk.WorkflowNodeJobRunData, wk *sdk.Worker, hatcheryName string) (*workflow.ProcessorReport, error) {
+	// Load secrets before starting the tx
+	pbj, err := workflow.LoadNodeJobRun(ctx, dbFunc(), store, id)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load job nodeJobRunID: %d", id)
+	}
+	noderun, err := workflow.LoadNodeRunByID(dbFunc(), pbj.WorkflowNodeRunID, workflow.LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot get node run")
+	}
+	workflowRun, err := workflow.LoadRunByID(dbFunc(), noderun.WorkflowRunID, workflow.LoadRunOptions{})
+	if err != nil {
+		return nil, sdk.WrapError(err, "Unable to load workflow run")
+	}
+
+	secrets, err := workflow.LoadDecryptSecrets(ctx, dbFunc(), workflowRun, noderun)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot load secrets")
+	}
+
 	// Start a tx
 	tx, errBegin := dbFunc().Begin()
 	if errBegin != nil {
@@ -166,6 +190,34 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 		return nil, sdk.WrapError(err, "cannot take job %d", id)
 	}
 
+	// Resolve secret references once the job is taken by this worker, values are only sent to the worker and
+	// reads are audited in the tx of the take
+	secrets, secretInfos, err := secret.ResolveJobSecrets(ctx, tx, secret.JobInfo{
+		ProjectID:     p.ID,
+		WorkflowRunID: workflowRun.ID,
+		JobID:         id,
+		WorkerName:    wk.Name,
+	}, secrets)
+	if err != nil {
+		return nil, sdk.WrapError(err, "cannot resolve secret references")
+	}
+
+	// The job can't run without its secrets, fail it with the reasons in spawn infos
+	if len(secretInfos) > 0 {
+		if err := workflow.AddSpawnInfosNodeJobRun(tx, job.WorkflowNodeRunID, job.ID, secretInfos); err != nil {
+			return nil, sdk.WrapError(err, "cannot save spawn info on node job run %d", job.ID)
+		}
+		r, err := workflow.UpdateNodeJobRunStatus(ctx, tx, store, *p, job, sdk.StatusFail)
+		if err != nil {
+			return nil, sdk.WrapError(err, "cannot update node job run %d status to %s", job.ID, sdk.StatusFail)
+		}
+		report.Merge(ctx, r)
+		if err := tx.Commit(); err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		return report, sdk.NewErrorFrom(sdk.ErrWrongRequest, "unable to resolve secret references of job %d", job.ID)
+	}
+
 	workerKey, err := jws.NewRandomSymmetricKey(32)
 	if err != nil {
 		return nil, err
@@ -177,8 +229,8 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 	}
 	wnjri.SigningKey = base64.StdEncoding.EncodeToString(workerKey)
 
-	// Load the node run
-	noderun, err := workflow.LoadNodeRunByID(tx, job.WorkflowNodeRunID, workflow.LoadRunOptions{})
+	// Reload the node run in the tx
+	noderun, err = workflow.LoadNodeRunByID(tx, job.WorkflowNodeRunID, workflow.LoadRunOptions{})
 	if err != nil {
 		return nil, sdk.WrapError(err, "cannot get node run")
 	}
@@ -191,17 +243,6 @@ func takeJob(ctx context.Context, dbFunc func() *gorp.DbMap, store cache.Store,
 		report.Add(ctx, *noderun)
 	}
 
-	// Load workflow run
-	workflowRun, err := workflow.LoadRunByID(tx, noderun.WorkflowRunID, workflow.LoadRunOptions{})
-	if err != nil {
-		return nil, sdk.WrapError(err, "Unable to load workflow run")
-	}
-
-	secrets, err := workflow.LoadDecryptSecrets(ctx, tx, workflowRun, noderun)
-	if err != nil {
-		return nil, sdk.WrapError(err, "cannot load secrets")
-	}
-
 	// Feed the worker
 	wnjri.ProjectKey = p.Key
 	wnjri.NodeJobRun = *job
@@ -658,6 +699,55 @@ func (api *API) getWorkerCacheLinkHandler() service.Handler {
 	}
 }
 
+// getWorkerCacheLinksHandler returns the worker cache for given key, if there is no cache for this key
+// restore keys are used in the given order to find the most recent cache with a key starting with it.
+func (api *API) getWorkerCacheLinksHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, err := requestVarInt(r, "permJobID")
+		if err != nil {
+			return err
+		}
+		key := FormString(r, "key")
+		if key == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing cache key")
+		}
+
+		p, err := project.LoadProjectByNodeJobRunID(ctx, api.mustDBWithCtx(ctx), api.Cache, id)
+		if err != nil {
+			return err
+		}
+
+		itemsLinks, err := cdn.ListItems(ctx, api.mustDBWithCtx(ctx), sdk.CDNTypeItemWorkerCache, map[string]string{
+			cdn.ParamProjectKey: p.Key,
+			cdn.ParamCacheTag:   key,
+		})
+		if err == nil {
+			return service.WriteJSON(w, itemsLinks, http.StatusOK)
+		}
+		if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+
+		for _, restoreKey := range r.URL.Query()["restoreKey"] {
+			if restoreKey == "" {
+				continue
+			}
+			itemsLinks, err := cdn.ListItems(ctx, api.mustDBWithCtx(ctx), sdk.CDNTypeItemWorkerCache, map[string]string{
+				cdn.ParamProjectKey:     p.Key,
+				cdn.ParamCacheTagPrefix: restoreKey,
+			})
+			if err == nil {
+				return service.WriteJSON(w, itemsLinks, http.StatusOK)
+			}
+			if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+				return err
+			}
+		}
+
+		return sdk.NewErrorFrom(sdk.ErrNotFound, "no cache found for key %q", key)
+	}
+}
+
 func (api *API) postWorkflowJobStepStatusHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		if isWorker := isWorker(ctx); !isWorker {
@@ -1147,6 +1237,15 @@ func (api *API) postWorkflowRunResultsHandler() service.Handler {
 			return err
 		}
 
+		// The provenance is stored in CDN that is waiting for this response, so it can't be generated synchronously
+		if _, digest, _ := runResult.GetNameAndSHA256(); digest != "" {
+			api.GoRoutines.Exec(context.Background(), "generateRunResultProvenance-"+runResult.ID, func(ctx context.Context) {
+				if err := api.generateRunResultProvenance(ctx, *proj, *wr, *nr, runResult); err != nil {
+					log.ErrorWithStackTrace(ctx, sdk.WrapError(err, "unable to generate provenance of run result %s", runResult.ID))
+				}
+			})
+		}
+
 		return nil
 	}
 }
@@ -599,6 +599,54 @@ func Test_postTakeWorkflowJobHandler(t *testing.T) {
 	assert.NotEmpty(t, run.HatcheryName)
 }
 
+func Test_postTakeWorkflowJobHandlerWithUnresolvedSecretReference(t *testing.T) {
+	api, db, router := newTestAPI(t)
+
+	// The project has no secret integration so the reference can't be resolved
+	ctx := testRunWorkflow(t, api, router, func(tt *testing.T, tx gorpmapper.SqlExecutorWithTx, _ *sdk.Pipeline, app *sdk.Application) {
+		require.NoError(tt, application.InsertVariable(tx, app.ID, &sdk.ApplicationVariable{
+			Name:  "db",
+			Type:  sdk.SecretVariable,
+			Value: "vault://kv/data/team/db#password",
+		}, sdk.AuthentifiedUser{Username: "test"}))
+	})
+	testGetWorkflowJobAsWorker(t, api, db, router, &ctx)
+	require.NotNil(t, ctx.job)
+
+	mockCDNService, _, _ := assets.InitCDNService(t, db)
+	defer func() {
+		_ = services.Delete(db, mockCDNService) // nolint
+	}()
+
+	testRegisterWorker(t, api, db, router, &ctx)
+
+	uri := router.GetRoute("POST", api.postTakeWorkflowJobHandler, map[string]string{
+		"key":              ctx.project.Key,
+		"permWorkflowName": ctx.workflow.Name,
+		"id":               fmt.Sprintf("%d", ctx.job.ID),
+	})
+	require.NotEmpty(t, uri)
+	req := assets.NewJWTAuthentifiedRequest(t, ctx.workerToken, "POST", uri, nil)
+	rec := httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	require.Equal(t, 400, rec.Code)
+
+	run, err := workflow.LoadNodeJobRun(context.TODO(), api.mustDB(), api.Cache, ctx.job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sdk.StatusFail, run.Status)
+
+	infos, err := workflow.LoadNodeRunJobInfo(context.TODO(), api.mustDB(), run.WorkflowNodeRunID, run.ID)
+	require.NoError(t, err)
+	var found bool
+	for _, i := range infos {
+		if i.Message.ID == sdk.MsgSpawnInfoSecretReferenceError.ID {
+			found = true
+			assert.Contains(t, i.UserMessage, "cds.app.db")
+		}
+	}
+	assert.True(t, found, "the job should fail with a spawn info about the secret reference")
+}
+
 func Test_postTakeWorkflowInvalidJobHandler(t *testing.T) {
 	api, db, router := newTestAPI(t)
 
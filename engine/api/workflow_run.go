@@ -1047,6 +1047,26 @@ func (api *API) initWorkflowRun(ctx context.Context, projKey string, wf *sdk.Wor
 				infos[i] = msg.ToSpawnMsg()
 			}
 			workflow.AddWorkflowRunInfo(wfRun, infos...)
+
+			// Preview the changes on the pull request of the branch, the workflow from a branch is never persisted
+			if workflowStartedByRepoWebHook {
+				importErr, number := err, wfRun.Number
+				var diff sdk.AsCodeDiff
+				if importErr == nil {
+					diff, err = ascode.ComputeWorkflowDiff(ctx, oldWf, *wf)
+					if err != nil {
+						log.Error(ctx, "unable to compute as code diff for workflow %s/%s: %v", p.Key, oldWf.Name, err)
+					}
+				}
+				api.GoRoutines.Exec(context.Background(), fmt.Sprintf("sendPullRequestPreview-%s-%s-%d", p.Key, oldWf.Name, number), func(ctx context.Context) {
+					if err := ascode.SendPullRequestPreview(ctx, api.mustDB(), api.Cache, *p, oldWf, number,
+						opts.Hook.Payload["git.branch"], opts.Hook.Payload["git.hash"], diff, asCodeInfosMsg, importErr); err != nil {
+						log.Error(ctx, "unable to send as code preview for workflow %s/%s: %v", p.Key, oldWf.Name, err)
+					}
+				})
+				err = importErr
+			}
+
 			if err != nil {
 				r1 := failInitWorkflowRun(ctx, api.mustDB(), wfRun, sdk.WrapError(err, "unable to get workflow from repository"))
 				report.Merge(ctx, r1)
@@ -1080,6 +1100,18 @@ func (api *API) initWorkflowRun(ctx context.Context, projKey string, wf *sdk.Wor
 		return
 	}
 
+	// Pull request events deploy or tear down the preview environment of the pull request
+	if opts.Hook != nil {
+		if h := wf.WorkflowData.Node.GetHook(opts.Hook.WorkflowNodeHookUUID); h != nil && h.HookModelName == sdk.RepositoryWebHookModelName {
+			if err := workflow.PreparePreviewEnvironment(ctx, tx, wfRun, opts.Hook, c); err != nil {
+				_ = tx.Rollback()
+				r := failInitWorkflowRun(ctx, api.mustDB(), wfRun, sdk.WrapError(err, "unable to prepare preview environment for workflow %s/%s", p.Key, wf.Name))
+				report.Merge(ctx, r)
+				return
+			}
+		}
+	}
+
 	r, err := workflow.StartWorkflowRun(ctx, tx, api.Cache, *p, wfRun, &opts, *c, asCodeInfosMsg)
 	report.Merge(ctx, r)
 	if err != nil {
@@ -1096,6 +1128,17 @@ func (api *API) initWorkflowRun(ctx context.Context, projKey string, wf *sdk.Wor
 	}
 	workflow.ResyncNodeRunsWithCommits(ctx, api.mustDB(), api.Cache, *p, report)
 
+	// Refresh the git mirrors on pushes, workers clone from them
+	if opts.Hook != nil {
+		if h := wf.WorkflowData.Node.GetHook(opts.Hook.WorkflowNodeHookUUID); h != nil &&
+			(h.HookModelName == sdk.RepositoryWebHookModelName || h.HookModelName == sdk.GitPollerModelName) {
+			nodeRuns := report.Nodes()
+			api.GoRoutines.Exec(context.Background(), fmt.Sprintf("refreshRepositoryMirrors-%s-%s-%d", p.Key, wf.Name, wfRun.Number), func(ctx context.Context) {
+				api.refreshRepositoryMirrors(ctx, p.Key, nodeRuns)
+			})
+		}
+	}
+
 	_, enabled := featureflipping.IsEnabled(ctx, gorpmapping.Mapper, api.mustDB(), sdk.FeaturePurgeName, map[string]string{"project_key": wf.ProjectKey})
 	if !enabled {
 		// Purge workflow run
@@ -1156,7 +1199,12 @@ func saveWorkflowRunSecrets(ctx context.Context, db *gorp.DbMap, projID int64, w
 		}
 	}
 
+	now := time.Now()
 	for _, k := range p.Keys {
+		// Rotated keys can't be used after their overlap period
+		if k.IsRetired(now) {
+			continue
+		}
 		wrSecret := sdk.WorkflowRunSecret{
 			WorkflowRunID: wr.ID,
 			Context:       workflow.SecretProjContext,
@@ -0,0 +1,166 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+)
+
+func (api *API) postApproveWorkflowNodeRunHandler() service.Handler {
+	return api.decideWorkflowNodeRunApprovalHandler(true)
+}
+
+func (api *API) postRejectWorkflowNodeRunHandler() service.Handler {
+	return api.decideWorkflowNodeRunApprovalHandler(false)
+}
+
+func (api *API) decideWorkflowNodeRunApprovalHandler(approved bool) service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		workflowName := vars["permWorkflowName"]
+		number, err := requestVarInt(r, "number")
+		if err != nil {
+			return err
+		}
+		nodeRunID, err := requestVarInt(r, "nodeRunID")
+		if err != nil {
+			return err
+		}
+
+		var req sdk.WorkflowNodeRunApprovalRequest
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		consumer := getAPIConsumer(ctx)
+		if isWorker(ctx) || isService(ctx) || consumer.AuthentifiedUser == nil {
+			return sdk.NewErrorFrom(sdk.ErrForbidden, "only users can approve or reject a pipeline")
+		}
+
+		p, err := project.Load(ctx, api.mustDB(), key, project.LoadOptions.WithVariables)
+		if err != nil {
+			return sdk.WrapError(err, "cannot load project")
+		}
+
+		// Check that the node run belongs to the workflow run before locking it
+		if _, err := workflow.LoadNodeRun(api.mustDB(), key, workflowName, nodeRunID, workflow.LoadRunOptions{}); err != nil {
+			return sdk.NewErrorWithStack(err, sdk.ErrNotFound)
+		}
+
+		groups, err := group.LoadAllByIDs(ctx, api.mustDB(), consumer.GetGroupIDs())
+		if err != nil {
+			return err
+		}
+
+		tx, err := api.mustDB().Begin()
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer tx.Rollback() // nolint
+
+		nodeRun, err := workflow.LoadAndLockNodeRunByID(ctx, tx, nodeRunID)
+		if err != nil {
+			return err
+		}
+		if nodeRun.Number != number {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		if nodeRun.Approval == nil {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "pipeline %s doesn't require an approval", nodeRun.WorkflowNodeName)
+		}
+		if err := nodeRun.Approval.CanDecide(*consumer.AuthentifiedUser, []string{consumer.GetEmail()}, groups.ToNames()); err != nil {
+			return err
+		}
+
+		workflowRun, err := workflow.LoadRunByID(tx, nodeRun.WorkflowRunID, workflow.LoadRunOptions{})
+		if err != nil {
+			return sdk.WrapError(err, "unable to load workflow run %d", nodeRun.WorkflowRunID)
+		}
+
+		report, err := workflow.DecideNodeRunApproval(ctx, tx, api.Cache, *p, workflowRun, nodeRun, *consumer.AuthentifiedUser, groups.ToNames(), approved, req.Comment)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WithStack(err)
+		}
+
+		api.GoRoutines.Exec(context.Background(), fmt.Sprintf("decideWorkflowNodeRunApprovalHandler-%d", nodeRunID), func(ctx context.Context) {
+			api.WorkflowSendEvent(context.Background(), *p, report)
+		})
+
+		return service.WriteJSON(w, nodeRun, http.StatusOK)
+	}
+}
+
+// WorkflowNodeRunApprovalExpiration stops the node runs which approval has expired.
+func (api *API) WorkflowNodeRunApprovalExpiration(ctx context.Context, tick time.Duration) error {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			ids, err := workflow.LoadNodeRunIDsWithExpiredApproval(api.mustDB())
+			if err != nil {
+				log.Error(ctx, "WorkflowNodeRunApprovalExpiration> %v", err)
+				continue
+			}
+			for _, id := range ids {
+				if err := api.expireWorkflowNodeRunApproval(ctx, id); err != nil {
+					log.Error(ctx, "WorkflowNodeRunApprovalExpiration> unable to expire approval of node run %d: %v", id, err)
+				}
+			}
+		}
+	}
+}
+
+func (api *API) expireWorkflowNodeRunApproval(ctx context.Context, nodeRunID int64) error {
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+
+	nodeRun, err := workflow.LoadAndLockNodeRunByID(ctx, tx, nodeRunID)
+	if err != nil {
+		if sdk.ErrorIs(err, sdk.ErrLocked) {
+			return nil
+		}
+		return err
+	}
+	workflowRun, err := workflow.LoadRunByID(tx, nodeRun.WorkflowRunID, workflow.LoadRunOptions{})
+	if err != nil {
+		return err
+	}
+	p, err := project.LoadByID(tx, workflowRun.ProjectID, project.LoadOptions.WithVariables)
+	if err != nil {
+		return err
+	}
+
+	report, err := workflow.ExpireNodeRunApproval(ctx, tx, api.Cache, *p, workflowRun, nodeRun)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WithStack(err)
+	}
+
+	api.WorkflowSendEvent(context.Background(), *p, report)
+	return nil
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/engine/api/keys"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/engine/cache"
+	"github.com/ovh/cds/engine/service"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdn"
+)
+
+// loadOrCreateProvenanceKey returns the key used to sign provenance attestations of the project, it is generated on
+// first use. It is not a project key so it can't be used by jobs to forge attestations.
+func (api *API) loadOrCreateProvenanceKey(ctx context.Context, proj sdk.Project) (*sdk.ProjectProvenanceKey, error) {
+	lockKey := cache.Key("api:provenanceKey", proj.Key)
+	b, err := api.Cache.Lock(lockKey, time.Minute, 100, 100)
+	if err != nil {
+		return nil, err
+	}
+	if !b {
+		return nil, sdk.WithStack(sdk.ErrLocked)
+	}
+	defer func() {
+		_ = api.Cache.Unlock(lockKey)
+	}()
+
+	k, err := project.LoadProvenanceKeyWithDecryption(ctx, api.mustDB(), proj.ID)
+	if err == nil {
+		return k, nil
+	}
+	if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return nil, err
+	}
+
+	keyName := sdk.GenerateProjectProvenanceKeyName(proj.Key)
+	newKey, err := keys.GenerateSSHKeyWithAlgorithm(keyName, sdk.SSHKeyAlgorithmED25519)
+	if err != nil {
+		return nil, err
+	}
+	provenanceKey := sdk.ProjectProvenanceKey{
+		Name:      keyName,
+		Public:    newKey.Public,
+		Private:   newKey.Private,
+		ProjectID: proj.ID,
+	}
+
+	tx, err := api.mustDB().Begin()
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	defer tx.Rollback() // nolint
+	if err := project.InsertProvenanceKey(ctx, tx, &provenanceKey); err != nil {
+		return nil, sdk.WrapError(err, "cannot insert provenance key for project %s", proj.Key)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	return &provenanceKey, nil
+}
+
+func (api *API) getProjectProvenanceKeyHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars[permProjectKey]
+
+		proj, err := project.Load(ctx, api.mustDB(), key)
+		if err != nil {
+			return err
+		}
+
+		k, err := project.LoadProvenanceKey(ctx, api.mustDB(), proj.ID)
+		if err != nil {
+			return err
+		}
+
+		return service.WriteJSON(w, k, http.StatusOK)
+	}
+}
+
+// generateRunResultProvenance signs the provenance of a run result, stores it in CDN and adds it as a run result.
+func (api *API) generateRunResultProvenance(ctx context.Context, proj sdk.Project, wr sdk.WorkflowRun, nr sdk.WorkflowNodeRun, runResult sdk.WorkflowRunResult) error {
+	job, err := workflow.LoadNodeJobRun(ctx, api.mustDB(), api.Cache, runResult.WorkflowRunJobID)
+	if err != nil {
+		return err
+	}
+
+	statement, err := workflow.NewRunResultProvenance(api.Config.URL.API, wr, nr, *job, runResult)
+	if err != nil {
+		return err
+	}
+
+	projKey, err := api.loadOrCreateProvenanceKey(ctx, proj)
+	if err != nil {
+		return err
+	}
+	signer, err := keys.SSHSigner(projKey.Private)
+	if err != nil {
+		return err
+	}
+	envelope, err := sdk.SignInTotoStatement(*statement, projKey.Name, signer)
+	if err != nil {
+		return err
+	}
+	content, err := json.Marshal(envelope)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	content = append(content, '\n')
+
+	subject := statement.Subject[0].Name
+	sig := cdn.Signature{
+		ProjectKey:   proj.Key,
+		WorkflowID:   wr.WorkflowID,
+		WorkflowName: wr.Workflow.Name,
+		RunID:        wr.ID,
+		RunNumber:    wr.Number,
+		NodeRunID:    nr.ID,
+		NodeRunName:  nr.WorkflowNodeName,
+		JobID:        job.ID,
+		JobName:      job.Job.Action.Name,
+		Worker: &cdn.SignatureWorker{
+			WorkerID:      job.Job.WorkerID,
+			WorkerName:    job.WorkerName,
+			FileName:      subject + sdk.ProvenanceFileSuffix,
+			FilePerm:      0644,
+			RunResultType: string(sdk.WorkflowRunResultTypeProvenance),
+		},
+	}
+	apiRef, err := sdk.NewCDNApiRef(sdk.CDNTypeItemRunResult, sig)
+	if err != nil {
+		return err
+	}
+	hashRef, err := apiRef.ToHash()
+	if err != nil {
+		return err
+	}
+
+	srvs, err := services.LoadAllByType(ctx, api.mustDB(), sdk.TypeCDN)
+	if err != nil {
+		return err
+	}
+	cdnClient := services.NewClient(api.mustDB(), srvs)
+	if _, code, err := cdnClient.DoJSONRequest(ctx, http.MethodPost, "/item/upload/provenance", sdk.CDNProvenanceUpload{Signature: sig, Content: content}, nil); err != nil {
+		return sdk.WrapError(err, "unable to store provenance of %s in CDN (HTTP %d)", subject, code)
+	}
+
+	md5Sum := md5.Sum(content)
+	provenance := sdk.WorkflowRunResultProvenance{
+		Name:       sig.Worker.FileName,
+		Size:       int64(len(content)),
+		MD5:        hex.EncodeToString(md5Sum[:]),
+		CDNRefHash: hashRef,
+		Perm:       sig.Worker.FilePerm,
+		Subject:    subject,
+		KeyName:    projKey.Name,
+	}
+	data, err := json.Marshal(provenance)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	provenanceResult := sdk.WorkflowRunResult{
+		WorkflowRunID:     wr.ID,
+		WorkflowNodeRunID: nr.ID,
+		WorkflowRunJobID:  job.ID,
+		SubNum:            nr.SubNumber,
+		Type:              sdk.WorkflowRunResultTypeProvenance,
+		DataRaw:           data,
+	}
+	if err := workflow.AddProvenanceResult(ctx, api.mustDB(), &provenanceResult); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "provenance of %s signed with key %s for run %d", subject, projKey.Name, wr.ID)
+	return nil
+}
@@ -861,6 +861,11 @@ func Test_postWorkflowRunHandler(t *testing.T) {
 	}
 	require.NoError(t, project.InsertVariable(db, proj.ID, &pwdProject, u))
 
+	// The provenance signing key must not be given to the jobs
+	provenanceKey, err := api.loadOrCreateProvenanceKey(context.TODO(), *proj)
+	require.NoError(t, err)
+	require.NotEmpty(t, provenanceKey.Private)
+
 	//First pipeline
 	pip := sdk.Pipeline{
 		ProjectID:  proj.ID,
@@ -1122,6 +1127,10 @@ func Test_postWorkflowRunHandler(t *testing.T) {
 
 	// Proj key
 	require.NotNil(t, sdk.VariableFind(secrets, "cds.key.proj-sshkey.priv"))
+	require.Nil(t, sdk.VariableFind(secrets, "cds.key."+provenanceKey.Name+".priv"))
+	for _, s := range secrets {
+		require.NotEqual(t, provenanceKey.Private, s.Value, "secret %s should not be the provenance key", s.Name)
+	}
 	// Project password
 	require.NotNil(t, sdk.VariableFind(secrets, "cds.proj.projvar"))
 
@@ -71,6 +71,21 @@ func LoadAllByIDs(ctx context.Context, db gorp.SqlExecutor, ids []int64, opts ..
 	return getAll(ctx, db, query, opts...)
 }
 
+// LoadAllByDependencyPath returns all workflow templates that extend or import fragments from the template with
+// given path (ex: my-group/my-template).
+func LoadAllByDependencyPath(ctx context.Context, db gorp.SqlExecutor, path string, opts ...LoadOptionFunc) ([]sdk.WorkflowTemplate, error) {
+	query := gorpmapping.NewQuery(`
+    SELECT *
+    FROM workflow_template
+    WHERE split_part(extends, '@', 1) = $1
+    OR EXISTS (
+      SELECT 1 FROM jsonb_array_elements(CASE WHEN jsonb_typeof(imports) = 'array' THEN imports ELSE '[]'::jsonb END) AS i
+      WHERE split_part(i->>'from', '@', 1) = $1
+    )
+  `).Args(path)
+	return getAll(ctx, db, query, opts...)
+}
+
 // LoadByID retrieves in database the workflow template with given id.
 func LoadByID(ctx context.Context, db gorp.SqlExecutor, id int64, opts ...LoadOptionFunc) (*sdk.WorkflowTemplate, error) {
 	query := gorpmapping.NewQuery("SELECT * FROM workflow_template WHERE id = $1").Args(id)
@@ -0,0 +1,36 @@
+package workflowtemplate
+
+import (
+	"context"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertRelease for workflow template in database.
+func InsertRelease(db gorp.SqlExecutor, r *sdk.WorkflowTemplateRelease) error {
+	return sdk.WrapError(gorpmapping.Insert(db, r), "unable to insert release %s for workflow template %d", r.Version, r.WorkflowTemplateID)
+}
+
+// LoadReleasesByTemplateID returns all releases for given template id sorted by descending version.
+func LoadReleasesByTemplateID(ctx context.Context, db gorp.SqlExecutor, templateID int64) (sdk.WorkflowTemplateReleases, error) {
+	query := gorpmapping.NewQuery("SELECT * FROM workflow_template_release WHERE workflow_template_id = $1").Args(templateID)
+	var releases []sdk.WorkflowTemplateRelease
+	if err := gorpmapping.GetAll(ctx, db, query, &releases); err != nil {
+		return nil, sdk.WrapError(err, "cannot get workflow template releases")
+	}
+	rs := sdk.WorkflowTemplateReleases(releases)
+	rs.Sort()
+	return rs, nil
+}
+
+// LoadReleaseMatching returns the latest release of the template that matches given version range.
+func LoadReleaseMatching(ctx context.Context, db gorp.SqlExecutor, templateID int64, versionRange string) (*sdk.WorkflowTemplateRelease, error) {
+	rs, err := LoadReleasesByTemplateID(ctx, db, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return rs.LatestMatching(versionRange)
+}
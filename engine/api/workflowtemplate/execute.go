@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"text/template"
+	"text/template/parse"
 
 	yaml "gopkg.in/yaml.v2"
 
@@ -35,6 +36,11 @@ func prepareParams(wt sdk.WorkflowTemplate, r sdk.WorkflowTemplateRequest) inter
 				// safely ignore the error because the value of v has been validated on apply submit
 				_ = sdk.JSONUnmarshal([]byte(v), &res)
 				m[p.Key] = res
+			case sdk.ParameterTypeInteger:
+				// safely ignore the error because the value of v has been validated on apply submit
+				m[p.Key], _ = strconv.ParseInt(v, 10, 64)
+			case sdk.ParameterTypeList:
+				m[p.Key] = sdk.SplitTemplateParameterList(v)
 			default:
 				m[p.Key] = v
 			}
@@ -43,7 +49,8 @@ func prepareParams(wt sdk.WorkflowTemplate, r sdk.WorkflowTemplateRequest) inter
 	return m
 }
 
-func parseTemplate(templateType string, number int, t string) (*template.Template, error) {
+// parseTemplate parses given template value then given blocks that can override the blocks defined in the value.
+func parseTemplate(templateType string, number int, t string, blocks ...string) (*template.Template, error) {
 	var id string
 	switch templateType {
 	case "workflow":
@@ -70,6 +77,14 @@ func parseTemplate(templateType string, number int, t string) (*template.Templat
 			Message: submatch[3],
 		})
 	}
+	for _, b := range blocks {
+		if _, err := tmpl.Parse(b); err != nil {
+			return nil, sdk.WithStack(sdk.WorkflowTemplateError{
+				Type:    "blocks",
+				Message: err.Error(),
+			})
+		}
+	}
 	return tmpl, nil
 }
 
@@ -102,11 +117,37 @@ func Parse(wt sdk.WorkflowTemplate) (sdk.WorkflowTemplateParsed, error) {
 
 	var multiErr sdk.MultiError
 
+	// blocks from parent templates are parsed before the blocks of the template to allow overrides
+	var blocks []string
+	for i, b := range append(append([]string{}, wt.InheritedBlocks...), wt.Blocks) {
+		if b == "" {
+			continue
+		}
+		v, err := decodeTemplateValue(b)
+		if err != nil {
+			return result, err
+		}
+		tmpl, err := parseTemplate("blocks", i, v)
+		if err != nil {
+			multiErr.Append(err)
+			continue
+		}
+		if tmpl.Tree != nil && !parse.IsEmptyTree(tmpl.Tree.Root) {
+			multiErr.Append(sdk.WithStack(sdk.WorkflowTemplateError{
+				Type:    "blocks",
+				Number:  i,
+				Message: "blocks should only contain define actions",
+			}))
+			continue
+		}
+		blocks = append(blocks, v)
+	}
+
 	v, err := decodeTemplateValue(wt.Workflow)
 	if err != nil {
 		return result, err
 	}
-	result.Workflow, err = parseTemplate("workflow", 0, v)
+	result.Workflow, err = parseTemplate("workflow", 0, v, blocks...)
 	if err != nil {
 		multiErr.Append(err)
 	}
@@ -116,7 +157,7 @@ func Parse(wt sdk.WorkflowTemplate) (sdk.WorkflowTemplateParsed, error) {
 		if err != nil {
 			return result, err
 		}
-		result.Pipelines[i], err = parseTemplate("pipeline", i, v)
+		result.Pipelines[i], err = parseTemplate("pipeline", i, v, blocks...)
 		if err != nil {
 			multiErr.Append(err)
 		}
@@ -127,7 +168,7 @@ func Parse(wt sdk.WorkflowTemplate) (sdk.WorkflowTemplateParsed, error) {
 		if err != nil {
 			return result, err
 		}
-		result.Applications[i], err = parseTemplate("application", i, v)
+		result.Applications[i], err = parseTemplate("application", i, v, blocks...)
 		if err != nil {
 			multiErr.Append(err)
 		}
@@ -138,7 +179,7 @@ func Parse(wt sdk.WorkflowTemplate) (sdk.WorkflowTemplateParsed, error) {
 		if err != nil {
 			return result, err
 		}
-		result.Environments[i], err = parseTemplate("environment", i, v)
+		result.Environments[i], err = parseTemplate("environment", i, v, blocks...)
 		if err != nil {
 			multiErr.Append(err)
 		}
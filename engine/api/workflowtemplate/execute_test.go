@@ -240,3 +240,64 @@ name: Environment-[[.id]]
 	require.NoError(t, err)
 	assert.Len(t, res.Environments, 0)
 }
+
+func TestExecuteTemplateWithTypedParameters(t *testing.T) {
+	tmpl := sdk.WorkflowTemplate{
+		Parameters: []sdk.WorkflowTemplateParameter{
+			{Key: "replicas", Type: sdk.ParameterTypeInteger},
+			{Key: "zones", Type: sdk.ParameterTypeList},
+		},
+		Workflow: base64.StdEncoding.EncodeToString([]byte(`
+name: [[.name]]
+version: v2.0
+description: "[[ add .params.replicas 1 ]] replicas in [[ range $i, $z := .params.zones ]][[ if $i ]]-[[ end ]][[ $z ]][[ end ]]"
+workflow:
+  build:
+    pipeline: build`)),
+	}
+
+	res, err := workflowtemplate.Execute(tmpl, sdk.WorkflowTemplateInstance{
+		Request: sdk.WorkflowTemplateRequest{
+			WorkflowName: "my-workflow",
+			Parameters:   map[string]string{"replicas": "2", "zones": "a, b"},
+		},
+	})
+	require.NoError(t, err)
+
+	out, err := yaml.Marshal(res.Workflow)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "description: 3 replicas in a-b")
+}
+
+func TestExecuteTemplateWithBlocks(t *testing.T) {
+	tmpl := sdk.WorkflowTemplate{
+		Workflow: base64.StdEncoding.EncodeToString([]byte(`
+name: [[.name]]
+version: v2.0
+description: "[[block "description" .]]default[[end]] [[block "suffix" .]]none[[end]]"
+workflow:
+  build:
+    pipeline: build`)),
+		InheritedBlocks: []string{
+			base64.StdEncoding.EncodeToString([]byte(`[[define "description"]]from parent[[end]]
+[[define "suffix"]]parent suffix[[end]]`)),
+		},
+		Blocks: base64.StdEncoding.EncodeToString([]byte(`[[define "description"]]from child [[.name]][[end]]`)),
+	}
+
+	res, err := workflowtemplate.Execute(tmpl, sdk.WorkflowTemplateInstance{
+		Request: sdk.WorkflowTemplateRequest{WorkflowName: "my-workflow"},
+	})
+	require.NoError(t, err)
+
+	out, err := yaml.Marshal(res.Workflow)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "description: from child my-workflow parent suffix")
+
+	// blocks should only contain define actions
+	tmpl.Blocks = base64.StdEncoding.EncodeToString([]byte(`name: [[.name]]`))
+	_, err = workflowtemplate.Parse(tmpl)
+	require.Error(t, err)
+	e := sdk.ExtractHTTPError(err)
+	assert.Equal(t, sdk.ErrCannotParseTemplate.ID, e.ID)
+}
@@ -76,7 +76,7 @@ func Pull(ctx context.Context, wt *sdk.WorkflowTemplate, f exportentities.Format
 		}
 		buff := bytes.NewBuffer(data)
 		hdr := &tar.Header{
-			Name: fmt.Sprintf(exportentities.TemplatePipelineName, i+1),
+			Name: exportentities.TemplatePipelineFileName(p, i),
 			Mode: 0644,
 			Size: int64(buff.Len()),
 		}
@@ -95,7 +95,7 @@ func Pull(ctx context.Context, wt *sdk.WorkflowTemplate, f exportentities.Format
 		}
 		buff := bytes.NewBuffer(data)
 		hdr := &tar.Header{
-			Name: fmt.Sprintf(exportentities.TemplateApplicationName, i+1),
+			Name: exportentities.TemplateApplicationFileName(a, i),
 			Mode: 0644,
 			Size: int64(buff.Len()),
 		}
@@ -114,7 +114,7 @@ func Pull(ctx context.Context, wt *sdk.WorkflowTemplate, f exportentities.Format
 		}
 		buff := bytes.NewBuffer(data)
 		hdr := &tar.Header{
-			Name: fmt.Sprintf(exportentities.TemplateEnvironmentName, i+1),
+			Name: exportentities.TemplateEnvironmentFileName(e, i),
 			Mode: 0644,
 			Size: int64(buff.Len()),
 		}
@@ -126,5 +126,24 @@ func Pull(ctx context.Context, wt *sdk.WorkflowTemplate, f exportentities.Format
 		}
 	}
 
+	if wt.Blocks != "" {
+		data, err := base64.StdEncoding.DecodeString(wt.Blocks)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to decode blocks value")
+		}
+		buff := bytes.NewBuffer(data)
+		hdr := &tar.Header{
+			Name: exportentities.TemplateBlocksName,
+			Mode: 0644,
+			Size: int64(buff.Len()),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return sdk.WrapError(err, "Unable to write blocks header %+v", hdr)
+		}
+		if _, err := io.Copy(tw, buff); err != nil {
+			return sdk.WrapError(err, "Unable to copy blocks buffer")
+		}
+	}
+
 	return nil
 }
@@ -15,6 +15,15 @@ func Push(ctx context.Context, db gorp.SqlExecutor, wt *sdk.WorkflowTemplate, u
 	if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
 		return nil, err
 	}
+	// check that dependencies can be resolved and that parsing is ok
+	resolved, err := ResolveDependencies(ctx, db, *wt)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := Parse(*resolved); err != nil {
+		return nil, err
+	}
+
 	if old == nil {
 		wt.Version = 1
 
@@ -37,11 +46,6 @@ func Push(ctx context.Context, db gorp.SqlExecutor, wt *sdk.WorkflowTemplate, u
 	clone := sdk.WorkflowTemplate(*old)
 	clone.Update(*wt)
 
-	// execute template with no instance only to check if parsing is ok
-	if _, err := Parse(clone); err != nil {
-		return nil, err
-	}
-
 	if err := Update(db, &clone); err != nil {
 		return nil, err
 	}
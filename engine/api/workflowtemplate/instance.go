@@ -3,13 +3,16 @@ package workflowtemplate
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/go-gorp/gorp"
 	"github.com/rockbears/log"
 
+	"github.com/ovh/cds/engine/api/environment"
 	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/integration"
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/cache"
 	"github.com/ovh/cds/engine/gorpmapper"
@@ -68,8 +71,9 @@ func requestModifyDefaultKeysfunc(proj sdk.Project) TemplateRequestModifierFunc
 	}
 }
 
-func requestModifyDefaultNameAndRepositories(proj sdk.Project, repoURL string) TemplateRequestModifierFunc {
+func requestModifyDefaultNameAndRepositories(proj sdk.Project, fromRepository string) TemplateRequestModifierFunc {
 	return func(ctx context.Context, db gorpmapper.SqlExecutorWithTx, store cache.Store, wt sdk.WorkflowTemplate, req *sdk.WorkflowTemplateRequest) error {
+		repoURL, root := sdk.SplitAsCodeFromRepository(fromRepository)
 		var repoPath string
 	loopVCSServer:
 		for _, vcs := range proj.VCSServers {
@@ -93,6 +97,10 @@ func requestModifyDefaultNameAndRepositories(proj sdk.Project, repoURL string) T
 
 		splittedPath := strings.Split(repoPath, "/")
 		repoName := splittedPath[len(splittedPath)-1]
+		// In a monorepo, the default workflow name is the name of the workflow root directory
+		if root != "" {
+			repoName = path.Base(root)
+		}
 		if req.WorkflowName == "" {
 			req.WorkflowName = repoName
 		}
@@ -112,6 +120,39 @@ func requestModifyDefaultNameAndRepositories(proj sdk.Project, repoURL string) T
 	}
 }
 
+// CheckParamsReferences checks that environment and integration parameters reference existing entities of the project.
+func CheckParamsReferences(db gorp.SqlExecutor, wt sdk.WorkflowTemplate, req sdk.WorkflowTemplateRequest) error {
+	var errs []sdk.WorkflowTemplateParameterError
+	for _, p := range wt.Parameters {
+		v := req.Parameters[p.Key]
+		if v == "" {
+			continue
+		}
+		switch p.Type {
+		case sdk.ParameterTypeEnvironment:
+			if _, err := environment.LoadEnvironmentByName(db, req.ProjectKey, v); err != nil {
+				if !sdk.ErrorIs(err, sdk.ErrEnvironmentNotFound) {
+					return err
+				}
+				errs = append(errs, sdk.WorkflowTemplateParameterError{Key: p.Key,
+					Message: fmt.Sprintf("Given environment %s for %s does not exist in project %s", v, p.Key, req.ProjectKey)})
+			}
+		case sdk.ParameterTypeIntegration:
+			if _, err := integration.LoadProjectIntegrationByName(db, req.ProjectKey, v); err != nil {
+				if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+					return err
+				}
+				errs = append(errs, sdk.WorkflowTemplateParameterError{Key: p.Key,
+					Message: fmt.Sprintf("Given integration %s for %s does not exist in project %s", v, p.Key, req.ProjectKey)})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return sdk.NewWorkflowTemplateParametersError(errs)
+	}
+	return nil
+}
+
 // CheckAndExecuteTemplate will execute the workflow template if given workflow components contains a template instance.
 // When detached is set this will not create/update any template instance in database (this is useful for workflow ascode branches).
 func CheckAndExecuteTemplate(ctx context.Context, db *gorp.DbMap, store cache.Store, consumer sdk.AuthConsumer, p sdk.Project,
@@ -122,7 +163,7 @@ func CheckAndExecuteTemplate(ctx context.Context, db *gorp.DbMap, store cache.St
 		return allMsgs, nil, nil
 	}
 
-	groupName, templateSlug, templateVersion, err := data.Template.ParseFrom()
+	groupName, templateSlug, templateVersion, versionRange, err := data.Template.ParseFrom()
 	if err != nil {
 		return allMsgs, nil, err
 	}
@@ -161,17 +202,44 @@ func CheckAndExecuteTemplate(ctx context.Context, db *gorp.DbMap, store cache.St
 	if err != nil {
 		return allMsgs, nil, sdk.NewErrorFrom(err, "could not find a template with slug %s in group %s", templateSlug, grp.Name)
 	}
+	// a template path without version keeps the range of releases that the existing instance is pinned to
+	if templateVersion == 0 && versionRange == "" {
+		wti, err := LoadInstanceByTemplateIDAndProjectIDAndRequestWorkflowName(ctx, tx, wt.ID, p.ID, data.Template.Name)
+		if err != nil && !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return allMsgs, nil, err
+		}
+		if wti != nil {
+			versionRange = wti.VersionRange
+		}
+	}
+	var release *sdk.WorkflowTemplateRelease
 	if templateVersion > 0 {
 		wta, err := LoadAuditByTemplateIDAndVersion(ctx, tx, wt.ID, templateVersion)
 		if err != nil {
 			return allMsgs, nil, err
 		}
 		wt = &wta.DataAfter
+	} else if versionRange != "" {
+		release, err = LoadReleaseMatching(ctx, tx, wt.ID, versionRange)
+		if err != nil {
+			return allMsgs, nil, err
+		}
+		wt = &release.Template
 	}
 	if err := LoadOptions.Default(ctx, tx, wt); err != nil {
 		return allMsgs, nil, err
 	}
-	allMsgs = append(allMsgs, sdk.NewMessage(sdk.MsgWorkflowGeneratedFromTemplateVersion, wt.PathWithVersion()))
+	wt, err = ResolveDependencies(ctx, tx, *wt)
+	if err != nil {
+		return allMsgs, nil, err
+	}
+	var releaseVersion string
+	if release != nil {
+		releaseVersion = release.Version
+		allMsgs = append(allMsgs, sdk.NewMessage(sdk.MsgWorkflowGeneratedFromTemplateVersion, wt.Path()+"@"+release.Version))
+	} else {
+		allMsgs = append(allMsgs, sdk.NewMessage(sdk.MsgWorkflowGeneratedFromTemplateVersion, wt.PathWithVersion()))
+	}
 
 	req := sdk.WorkflowTemplateRequest{
 		ProjectKey:   p.Key,
@@ -187,6 +255,9 @@ func CheckAndExecuteTemplate(ctx context.Context, db *gorp.DbMap, store cache.St
 	if err := wt.CheckParams(req); err != nil {
 		return allMsgs, nil, err
 	}
+	if err := CheckParamsReferences(tx, *wt, req); err != nil {
+		return allMsgs, nil, err
+	}
 
 	var result exportentities.WorkflowComponents
 
@@ -196,6 +267,8 @@ func CheckAndExecuteTemplate(ctx context.Context, db *gorp.DbMap, store cache.St
 			ProjectID:               p.ID,
 			WorkflowTemplateID:      wt.ID,
 			WorkflowTemplateVersion: wt.Version,
+			VersionRange:            versionRange,
+			WorkflowTemplateRelease: releaseVersion,
 			Request:                 req,
 		}
 
@@ -222,6 +295,8 @@ func CheckAndExecuteTemplate(ctx context.Context, db *gorp.DbMap, store cache.St
 		clone := sdk.WorkflowTemplateInstance(*wti)
 		old = &clone
 		wti.WorkflowTemplateVersion = wt.Version
+		wti.VersionRange = versionRange
+		wti.WorkflowTemplateRelease = releaseVersion
 		wti.Request = req
 		if err := UpdateInstance(tx, wti); err != nil {
 			return allMsgs, nil, err
@@ -231,6 +306,8 @@ func CheckAndExecuteTemplate(ctx context.Context, db *gorp.DbMap, store cache.St
 			ProjectID:               p.ID,
 			WorkflowTemplateID:      wt.ID,
 			WorkflowTemplateVersion: wt.Version,
+			VersionRange:            versionRange,
+			WorkflowTemplateRelease: releaseVersion,
 			Request:                 req,
 		}
 		// only store the new instance if request is not for a detached workflow
@@ -2,6 +2,8 @@ package workflowtemplate
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/go-gorp/gorp"
 
@@ -65,3 +67,267 @@ func loadGroup(ctx context.Context, db gorp.SqlExecutor, wts ...*sdk.WorkflowTem
 
 	return nil
 }
+
+type templateDependency struct {
+	key  string
+	path string
+}
+
+// ResolveDependencies returns a copy of given template where the content of its parent template and the imported
+// fragments are merged. A template can only depend on templates from its own group or from the shared infra group.
+func ResolveDependencies(ctx context.Context, db gorp.SqlExecutor, wt sdk.WorkflowTemplate) (*sdk.WorkflowTemplate, error) {
+	return resolveDependencies(ctx, db, wt, nil)
+}
+
+func resolveDependencies(ctx context.Context, db gorp.SqlExecutor, wt sdk.WorkflowTemplate, stack []templateDependency) (*sdk.WorkflowTemplate, error) {
+	if wt.Extends == "" && len(wt.Imports) == 0 {
+		return &wt, nil
+	}
+
+	current := templateDependency{key: fmt.Sprintf("%d/%s", wt.GroupID, wt.Slug), path: wt.Slug}
+	if wt.Group != nil {
+		current.path = wt.Path()
+	}
+	for i := range stack {
+		if stack[i].key == current.key {
+			paths := make([]string, 0, len(stack)+1)
+			for _, d := range stack[i:] {
+				paths = append(paths, d.path)
+			}
+			paths = append(paths, current.path)
+			return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "template dependency cycle detected: %s", strings.Join(paths, " -> "))
+		}
+	}
+	stack = append(stack, current)
+
+	res := wt
+	res.Extends = ""
+	res.Imports = nil
+	res.InheritedBlocks = nil
+
+	var importedBlocks, parentBlocks []string
+
+	if wt.Extends != "" {
+		parent, err := loadDependency(ctx, db, wt, wt.Extends)
+		if err != nil {
+			return nil, err
+		}
+		rp, err := resolveDependencies(ctx, db, *parent, stack)
+		if err != nil {
+			return nil, err
+		}
+		parentBlocks = append(append(parentBlocks, rp.InheritedBlocks...), rp.Blocks)
+
+		if res.Workflow == "" {
+			res.Workflow = rp.Workflow
+		}
+		res.Parameters = mergeTemplateParameters(rp.Parameters, wt.Parameters)
+		res.Pipelines = mergeTemplatePipelines(rp.Pipelines, wt.Pipelines)
+		res.Applications = mergeTemplateApplications(rp.Applications, wt.Applications)
+		res.Environments = mergeTemplateEnvironments(rp.Environments, wt.Environments)
+	}
+
+	for _, imp := range wt.Imports {
+		dep, err := loadDependency(ctx, db, wt, imp.From)
+		if err != nil {
+			return nil, err
+		}
+		rd, err := resolveDependencies(ctx, db, *dep, stack)
+		if err != nil {
+			return nil, err
+		}
+		importedBlocks = append(append(importedBlocks, rd.InheritedBlocks...), rd.Blocks)
+
+		// fragments defined in the template take precedence over imported ones
+		var pips sdk.PipelineTemplates
+		for _, name := range imp.Pipelines {
+			p, ok := findTemplatePipeline(rd.Pipelines, name)
+			if !ok {
+				return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "could not find pipeline %s in template %s", name, imp.From)
+			}
+			pips = append(pips, p)
+		}
+		res.Pipelines = mergeTemplatePipelines(pips, res.Pipelines)
+
+		var envs sdk.EnvironmentTemplates
+		for _, name := range imp.Environments {
+			e, ok := findTemplateEnvironment(rd.Environments, name)
+			if !ok {
+				return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "could not find environment %s in template %s", name, imp.From)
+			}
+			envs = append(envs, e)
+		}
+		res.Environments = mergeTemplateEnvironments(envs, res.Environments)
+	}
+
+	for _, b := range append(importedBlocks, parentBlocks...) {
+		if b != "" {
+			res.InheritedBlocks = append(res.InheritedBlocks, b)
+		}
+	}
+
+	return &res, nil
+}
+
+// loadDependency returns the content of the template at given pinned path.
+func loadDependency(ctx context.Context, db gorp.SqlExecutor, wt sdk.WorkflowTemplate, path string) (*sdk.WorkflowTemplate, error) {
+	groupName, templateSlug, version, versionRange, err := sdk.ParseWorkflowTemplatePath(path)
+	if err != nil {
+		return nil, err
+	}
+	if version == 0 && versionRange == "" {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "template dependency %s should be pinned to a version", path)
+	}
+
+	grp, err := group.LoadByName(ctx, db, groupName)
+	if err != nil {
+		return nil, sdk.NewErrorFrom(err, "could not find group for template dependency %s", path)
+	}
+	if grp.ID != wt.GroupID && grp.ID != group.SharedInfraGroup.ID {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "template dependency %s should be in the same group as the template or in %s", path, group.SharedInfraGroup.Name)
+	}
+
+	dep, err := LoadBySlugAndGroupID(ctx, db, templateSlug, grp.ID)
+	if err != nil {
+		return nil, sdk.NewErrorFrom(err, "could not find template dependency %s", path)
+	}
+	if version > 0 {
+		wta, err := LoadAuditByTemplateIDAndVersion(ctx, db, dep.ID, version)
+		if err != nil {
+			return nil, err
+		}
+		dep = &wta.DataAfter
+	} else {
+		rel, err := LoadReleaseMatching(ctx, db, dep.ID, versionRange)
+		if err != nil {
+			return nil, err
+		}
+		dep = &rel.Template
+	}
+	dep.Group = grp
+
+	return dep, nil
+}
+
+func mergeTemplateParameters(base, overrides []sdk.WorkflowTemplateParameter) sdk.WorkflowTemplateParameters {
+	res := append(sdk.WorkflowTemplateParameters{}, base...)
+	for _, o := range overrides {
+		var replaced bool
+		for i := range res {
+			if res[i].Key == o.Key {
+				res[i] = o
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+func mergeTemplatePipelines(base, overrides sdk.PipelineTemplates) sdk.PipelineTemplates {
+	res := append(sdk.PipelineTemplates{}, base...)
+	for _, o := range overrides {
+		var replaced bool
+		for i := range res {
+			if o.Name != "" && res[i].Name == o.Name {
+				res[i] = o
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+func mergeTemplateApplications(base, overrides sdk.ApplicationTemplates) sdk.ApplicationTemplates {
+	res := append(sdk.ApplicationTemplates{}, base...)
+	for _, o := range overrides {
+		var replaced bool
+		for i := range res {
+			if o.Name != "" && res[i].Name == o.Name {
+				res[i] = o
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+func mergeTemplateEnvironments(base, overrides sdk.EnvironmentTemplates) sdk.EnvironmentTemplates {
+	res := append(sdk.EnvironmentTemplates{}, base...)
+	for _, o := range overrides {
+		var replaced bool
+		for i := range res {
+			if o.Name != "" && res[i].Name == o.Name {
+				res[i] = o
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+func findTemplatePipeline(ps sdk.PipelineTemplates, name string) (sdk.PipelineTemplate, bool) {
+	for i := range ps {
+		if ps[i].Name == name {
+			return ps[i], true
+		}
+	}
+	return sdk.PipelineTemplate{}, false
+}
+
+func findTemplateEnvironment(es sdk.EnvironmentTemplates, name string) (sdk.EnvironmentTemplate, bool) {
+	for i := range es {
+		if es[i].Name == name {
+			return es[i], true
+		}
+	}
+	return sdk.EnvironmentTemplate{}, false
+}
+
+// LoadDependents returns all templates that extend or import fragments from given template, directly or through
+// other templates.
+func LoadDependents(ctx context.Context, db gorp.SqlExecutor, wt sdk.WorkflowTemplate) ([]sdk.WorkflowTemplate, error) {
+	var res []sdk.WorkflowTemplate
+	visited := map[int64]struct{}{wt.ID: {}}
+	queue := []sdk.WorkflowTemplate{wt}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current.Group == nil {
+			if err := loadGroup(ctx, db, &current); err != nil {
+				return nil, err
+			}
+		}
+		if current.Group == nil {
+			continue
+		}
+		deps, err := LoadAllByDependencyPath(ctx, db, current.Path(), LoadOptions.Default)
+		if err != nil {
+			return nil, err
+		}
+		for i := range deps {
+			if _, ok := visited[deps[i].ID]; ok {
+				continue
+			}
+			visited[deps[i].ID] = struct{}{}
+			res = append(res, deps[i])
+			queue = append(queue, deps[i])
+		}
+	}
+	return res, nil
+}
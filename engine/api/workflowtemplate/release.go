@@ -0,0 +1,224 @@
+package workflowtemplate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/pmezard/go-difflib/difflib"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// CreateRelease stores a new release for given template with its current content. The release version should be
+// greater than all existing releases versions.
+func CreateRelease(ctx context.Context, db gorp.SqlExecutor, wt sdk.WorkflowTemplate, version, changelog string, u sdk.Identifiable) (*sdk.WorkflowTemplateRelease, error) {
+	r := sdk.WorkflowTemplateRelease{
+		WorkflowTemplateID: wt.ID,
+		Version:            strings.TrimPrefix(strings.TrimSpace(version), "v"),
+		TemplateVersion:    wt.Version,
+		Changelog:          changelog,
+		Author:             u.GetUsername(),
+		Created:            time.Now(),
+	}
+	if err := r.IsValid(); err != nil {
+		return nil, err
+	}
+
+	rs, err := LoadReleasesByTemplateID(ctx, db, wt.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rs) > 0 && !r.SemVer().GT(rs[0].SemVer()) {
+		return nil, sdk.NewErrorFrom(sdk.ErrWrongRequest, "release version %s should be greater than latest release %s", r.Version, rs[0].Version)
+	}
+
+	// store a snapshot of the template without its aggregates
+	r.Template = wt
+	r.Template.Group = nil
+	r.Template.FirstAudit = nil
+	r.Template.LastAudit = nil
+	r.Template.Editable = false
+
+	if err := InsertRelease(db, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// LoadInstanceTemplate returns the template content that was used to generate given instance.
+func LoadInstanceTemplate(ctx context.Context, db gorp.SqlExecutor, wt sdk.WorkflowTemplate, wti sdk.WorkflowTemplateInstance) (*sdk.WorkflowTemplate, error) {
+	if wti.WorkflowTemplateRelease != "" {
+		rs, err := LoadReleasesByTemplateID(ctx, db, wt.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range rs {
+			if rs[i].Version == wti.WorkflowTemplateRelease {
+				return &rs[i].Template, nil
+			}
+		}
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "could not find release %s of template %s", wti.WorkflowTemplateRelease, wt.Path())
+	}
+	if wti.WorkflowTemplateVersion == wt.Version {
+		return &wt, nil
+	}
+	wta, err := LoadAuditByTemplateIDAndVersion(ctx, db, wt.ID, wti.WorkflowTemplateVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &wta.DataAfter, nil
+}
+
+// ComputeUpgrade renders the diff between the current output of given instance and the output of the latest release
+// that matches given version range.
+func ComputeUpgrade(ctx context.Context, db gorp.SqlExecutor, wt sdk.WorkflowTemplate, wti sdk.WorkflowTemplateInstance,
+	versionRange string) (*sdk.WorkflowTemplateUpgradeResult, *sdk.WorkflowTemplateRelease, error) {
+	rs, err := LoadReleasesByTemplateID(ctx, db, wt.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	target, err := rs.LatestMatching(versionRange)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current, err := LoadInstanceTemplate(ctx, db, wt, wti)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	current, err = ResolveDependencies(ctx, db, *current)
+	if err != nil {
+		return nil, nil, err
+	}
+	targetTemplate, err := ResolveDependencies(ctx, db, target.Template)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	before, err := Execute(*current, wti)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := Execute(*targetTemplate, wti)
+	if err != nil {
+		return nil, nil, err
+	}
+	diff, err := Diff(before, after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := sdk.WorkflowTemplateUpgradeResult{
+		FromVersion: wti.WorkflowTemplateRelease,
+		ToVersion:   target.Version,
+		Diff:        diff,
+	}
+	if res.FromVersion == "" {
+		res.FromVersion = fmt.Sprintf("%d", wti.WorkflowTemplateVersion)
+	}
+
+	// changelogs of all releases between current and target ones, from the oldest to the newest
+	for i := len(rs) - 1; i >= 0; i-- {
+		if rs[i].SemVer().GT(target.SemVer()) {
+			continue
+		}
+		if wti.WorkflowTemplateRelease != "" {
+			if !rs[i].SemVer().GT(sdk.WorkflowTemplateRelease{Version: wti.WorkflowTemplateRelease}.SemVer()) {
+				continue
+			}
+		} else if rs[i].TemplateVersion <= wti.WorkflowTemplateVersion {
+			continue
+		}
+		if rs[i].Changelog != "" {
+			res.Changelogs = append(res.Changelogs, fmt.Sprintf("%s: %s", rs[i].Version, rs[i].Changelog))
+		}
+	}
+
+	return &res, target, nil
+}
+
+// Diff returns an unified diff of the files generated for given workflow components.
+func Diff(before, after exportentities.WorkflowComponents) (string, error) {
+	beforeFiles, err := componentsFiles(before)
+	if err != nil {
+		return "", err
+	}
+	afterFiles, err := componentsFiles(after)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(beforeFiles)+len(afterFiles))
+	for name := range beforeFiles {
+		names = append(names, name)
+	}
+	for name := range afterFiles {
+		if _, has := beforeFiles[name]; !has {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fromFile, toFile := "a/"+name, "b/"+name
+		if _, has := beforeFiles[name]; !has {
+			fromFile = "/dev/null"
+		}
+		if _, has := afterFiles[name]; !has {
+			toFile = "/dev/null"
+		}
+		d, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(beforeFiles[name]),
+			B:        difflib.SplitLines(afterFiles[name]),
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  3,
+		})
+		if err != nil {
+			return "", sdk.WithStack(err)
+		}
+		b.WriteString(d)
+	}
+	return b.String(), nil
+}
+
+func componentsFiles(c exportentities.WorkflowComponents) (map[string]string, error) {
+	files := make(map[string]string)
+	add := func(pattern, name string, v interface{}) error {
+		bs, err := yaml.Marshal(v)
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		files[fmt.Sprintf(pattern, name)] = string(bs)
+		return nil
+	}
+	if c.Workflow != nil {
+		if err := add(exportentities.PullWorkflowName, c.Workflow.GetName(), c.Workflow); err != nil {
+			return nil, err
+		}
+	}
+	for _, a := range c.Applications {
+		if err := add(exportentities.PullApplicationName, a.Name, a); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range c.Environments {
+		if err := add(exportentities.PullEnvironmentName, e.Name, e); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range c.Pipelines {
+		if err := add(exportentities.PullPipelineName, p.Name, p); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
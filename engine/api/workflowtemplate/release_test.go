@@ -0,0 +1,48 @@
+package workflowtemplate_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/api/workflowtemplate"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestDiff(t *testing.T) {
+	newTemplate := func(stage string) sdk.WorkflowTemplate {
+		return sdk.WorkflowTemplate{
+			Workflow: base64.StdEncoding.EncodeToString([]byte(`
+name: [[.name]]
+version: v2.0
+workflow:
+  build:
+    pipeline: build`)),
+			Pipelines: []sdk.PipelineTemplate{{
+				Value: base64.StdEncoding.EncodeToString([]byte(`
+version: v1.0
+name: build
+stages:
+- ` + stage)),
+			}},
+		}
+	}
+	wti := sdk.WorkflowTemplateInstance{Request: sdk.WorkflowTemplateRequest{WorkflowName: "my-workflow"}}
+
+	before, err := workflowtemplate.Execute(newTemplate("Compile"), wti)
+	require.NoError(t, err)
+	after, err := workflowtemplate.Execute(newTemplate("Build"), wti)
+	require.NoError(t, err)
+
+	diff, err := workflowtemplate.Diff(before, before)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	diff, err = workflowtemplate.Diff(before, after)
+	require.NoError(t, err)
+	require.Contains(t, diff, "--- a/build.pip.yml\n+++ b/build.pip.yml\n")
+	require.Contains(t, diff, "-- Compile\n")
+	require.Contains(t, diff, "+- Build\n")
+	require.NotContains(t, diff, "my-workflow.yml")
+}
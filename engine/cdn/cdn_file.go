@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
@@ -54,6 +55,17 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 		return err
 	}
 
+	// Worker cache entries are immutable, a new cache has to be pushed with a new tag
+	if itemType == sdk.CDNTypeItemWorkerCache {
+		_, err := item.LoadWorkerCacheItemByProjectAndCacheTag(ctx, s.Mapper, s.mustDBWithCtx(ctx), sig.ProjectKey, sig.Worker.CacheTag)
+		if err == nil {
+			return sdk.NewErrorFrom(sdk.ErrAlreadyExist, "worker cache %q already exists", sig.Worker.CacheTag)
+		}
+		if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+			return err
+		}
+	}
+
 	it := &sdk.CDNItem{
 		APIRef:     apiRef,
 		Type:       itemType,
@@ -95,8 +107,9 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 		return err
 	}
 
-	// Compute md5 and sha512
+	// Compute md5, sha256 and sha512
 	md5Hash := md5.New()
+	sha256Hash := sha256.New()
 	sha512Hash := sha512.New()
 
 	sizeWriter := &SizeWriter{}
@@ -106,7 +119,7 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 	// wraps the Reader object into a new buffered reader to read the files in chunks
 	// and buffering them for performance.
 	mreader := bufio.NewReaderSize(reader, pagesize)
-	multiWriter := io.MultiWriter(md5Hash, sha512Hash, sizeWriter)
+	multiWriter := io.MultiWriter(md5Hash, sha256Hash, sha512Hash, sizeWriter)
 
 	teeReader := io.TeeReader(mreader, multiWriter)
 
@@ -120,6 +133,7 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 	}
 	sha512S := hex.EncodeToString(sha512Hash.Sum(nil))
 	md5S := hex.EncodeToString(md5Hash.Sum(nil))
+	sha256S := hex.EncodeToString(sha256Hash.Sum(nil))
 
 	it.Hash = sha512S
 	it.MD5 = md5S
@@ -155,6 +169,7 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 					Name:       apiRef.ToFilename(),
 					Size:       it.Size,
 					MD5:        it.MD5,
+					SHA256:     sha256S,
 					CDNRefHash: it.APIRefHash,
 					Perm:       runResultApiRef.Perm,
 				}
@@ -163,6 +178,7 @@ func (s *Service) storeFile(ctx context.Context, sig cdn.Signature, reader io.Re
 					Name:       apiRef.ToFilename(),
 					Size:       it.Size,
 					MD5:        it.MD5,
+					SHA256:     sha256S,
 					CDNRefHash: it.APIRefHash,
 					Perm:       runResultApiRef.Perm,
 				}
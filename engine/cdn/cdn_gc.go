@@ -28,6 +28,10 @@ func (s *Service) itemPurge(ctx context.Context) {
 			}
 			return
 		case <-tickPurge.C:
+			if err := s.markIdleWorkerCacheToDelete(ctx); err != nil {
+				ctx = sdk.ContextWithStacktrace(ctx, err)
+				log.Error(ctx, "cdn:ItemPurge: error on markIdleWorkerCacheToDelete: %v", err)
+			}
 			if err := s.cleanItemToDelete(ctx); err != nil {
 				ctx = sdk.ContextWithStacktrace(ctx, err)
 				log.Error(ctx, "cdn:ItemPurge: error on cleanItemToDelete: %v", err)
@@ -85,6 +89,21 @@ func (s *Service) markUnitItemToDeleteByItemID(ctx context.Context, itemID strin
 	return n, sdk.WithStack(tx.Commit())
 }
 
+// markIdleWorkerCacheToDelete evicts worker caches that were not pulled since the configured number of days
+func (s *Service) markIdleWorkerCacheToDelete(ctx context.Context) error {
+	if s.Cfg.WorkerCache.MaxIdleDays <= 0 {
+		return nil
+	}
+	n, err := item.MarkWorkerCacheToDeleteByLastAccess(s.mustDBWithCtx(ctx), s.Cfg.WorkerCache.MaxIdleDays)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Info(ctx, "cdn:purge:item: %d idle worker cache items marked to delete", n)
+	}
+	return nil
+}
+
 func (s *Service) cleanItemToDelete(ctx context.Context) error {
 	offset := 0
 	limit := 1000
@@ -115,6 +115,13 @@ func (s *Service) downloadFile(ctx context.Context, t sdk.CDNItemType, apiRefHas
 	if err := unit.Read(*iu, rc, w); err != nil {
 		return sdk.WithStack(err)
 	}
+
+	// Last access is used to evict worker caches that are not used anymore
+	if t == sdk.CDNTypeItemWorkerCache {
+		if err := item.UpdateLastAccess(s.mustDBWithCtx(ctx), iu.ItemID); err != nil {
+			log.Error(ctx, "downloadFile> %v", err)
+		}
+	}
 	return nil
 }
 
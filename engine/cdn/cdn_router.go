@@ -27,6 +27,7 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Handle("/bulk/item/delete", nil, r.POST(s.bulkDeleteItemsHandler))
 
 	r.Handle("/item/upload", nil, r.POST(s.postUploadHandler, service.OverrideAuth(service.NoAuthMiddleware)))
+	r.Handle("/item/upload/provenance", nil, r.POST(s.postUploadProvenanceHandler))
 	r.Handle("/item/stream", nil, r.GET(s.getItemLogsStreamHandler, service.OverrideAuth(s.validJWTMiddleware)))
 	r.Handle("/item/{type}", nil, r.GET(s.getItemsHandler))
 	r.Handle("/item/{type}/lines", nil, r.GET(s.getItemsAllLogsLinesHandler, service.OverrideAuth(s.validJWTMiddleware)))
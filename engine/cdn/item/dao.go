@@ -180,6 +180,44 @@ func LoadWorkerCacheItemsByProjectAndCacheTag(ctx context.Context, m *gorpmapper
 	return getItems(ctx, m, db, query)
 }
 
+// LoadLastWorkerCacheItemByProjectAndCacheTagPrefix returns the most recent completed worker cache item
+// with a cache tag starting with given prefix
+func LoadLastWorkerCacheItemByProjectAndCacheTagPrefix(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, projKey string, cacheTagPrefix string) (*sdk.CDNItem, error) {
+	query := gorpmapper.NewQuery(`
+		SELECT *
+		FROM item
+		WHERE type = $1
+		AND (api_ref->>'project_key')::text = $2
+		AND left((api_ref->>'cache_tag')::text, length($3)) = $3
+		AND status = $4
+		AND to_delete = false
+		ORDER BY created DESC
+		LIMIT 1
+	`).Args(sdk.CDNTypeItemWorkerCache, projKey, cacheTagPrefix, sdk.CDNStatusItemCompleted)
+	return getItem(ctx, m, db, query)
+}
+
+// UpdateLastAccess sets the last access date of an item to now
+func UpdateLastAccess(db gorp.SqlExecutor, id string) error {
+	_, err := db.Exec("UPDATE item SET last_access = NOW() WHERE id = $1", id)
+	return sdk.WrapError(err, "unable to update last access of item %s", id)
+}
+
+// MarkWorkerCacheToDeleteByLastAccess marks to delete worker cache items that were not accessed for given number of days
+func MarkWorkerCacheToDeleteByLastAccess(db gorp.SqlExecutor, maxIdleDays int64) (int64, error) {
+	res, err := db.Exec(`
+		UPDATE item SET to_delete = true
+		WHERE type = $1
+		AND to_delete = false
+		AND COALESCE(last_access, created) < NOW() - $2 * INTERVAL '1 day'
+	`, sdk.CDNTypeItemWorkerCache, maxIdleDays)
+	if err != nil {
+		return 0, sdk.WrapError(err, "unable to mark idle worker cache items to delete")
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
 // LoadByAPIRefHashAndType load an item by his job id, step order and type
 func LoadByAPIRefHashAndType(ctx context.Context, m *gorpmapper.Mapper, db gorp.SqlExecutor, hash string, itemType sdk.CDNItemType, opts ...gorpmapper.GetOptionFunc) (*sdk.CDNItem, error) {
 	query := gorpmapper.NewQuery(`
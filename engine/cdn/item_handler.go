@@ -261,13 +261,21 @@ func (s *Service) getArtifacts(ctx context.Context, r *http.Request, w http.Resp
 func (s *Service) getWorkerCache(ctx context.Context, r *http.Request, w http.ResponseWriter) error {
 	projectKey := r.FormValue("projectkey")
 	cachetag := r.FormValue("cachetag")
+	cachetagPrefix := r.FormValue("cachetagprefix")
 
-	if projectKey == "" || cachetag == "" {
+	if projectKey == "" || (cachetag == "" && cachetagPrefix == "") {
 		return sdk.WrapError(sdk.ErrWrongRequest, "invalid data to get worker cache")
 	}
-	item, err := item.LoadWorkerCacheItemByProjectAndCacheTag(ctx, s.Mapper, s.mustDBWithCtx(ctx), projectKey, cachetag)
+
+	var it *sdk.CDNItem
+	var err error
+	if cachetag != "" {
+		it, err = item.LoadWorkerCacheItemByProjectAndCacheTag(ctx, s.Mapper, s.mustDBWithCtx(ctx), projectKey, cachetag)
+	} else {
+		it, err = item.LoadLastWorkerCacheItemByProjectAndCacheTagPrefix(ctx, s.Mapper, s.mustDBWithCtx(ctx), projectKey, cachetagPrefix)
+	}
 	if err != nil {
 		return err
 	}
-	return service.WriteJSON(w, []sdk.CDNItem{*item}, http.StatusOK)
+	return service.WriteJSON(w, []sdk.CDNItem{*it}, http.StatusOK)
 }
@@ -1,7 +1,9 @@
 package cdn
 
 import (
+	"bytes"
 	"context"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/ovh/cds/engine/service"
@@ -38,3 +40,20 @@ func (s *Service) postUploadHandler() service.Handler {
 		return nil
 	}
 }
+
+// postUploadProvenanceHandler stores a provenance attestation signed by the API as a run result.
+func (s *Service) postUploadProvenanceHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var req sdk.CDNProvenanceUpload
+		if err := service.UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		if req.Signature.Worker == nil || sdk.WorkflowRunResultType(req.Signature.Worker.RunResultType) != sdk.WorkflowRunResultTypeProvenance {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid provenance signature")
+		}
+
+		// The API is in charge of the run result creation
+		return s.storeFile(ctx, req.Signature, ioutil.NopCloser(bytes.NewReader(req.Content)), StoreFileOptions{DisableApiRunResult: true})
+	}
+}
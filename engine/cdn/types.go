@@ -86,6 +86,9 @@ type Configuration struct {
 	Metrics struct {
 		Frequency int64 `toml:"frequency" default:"30" json:"frequency" comment:"each 30s, metrics are computed"`
 	} `toml:"metrics" comment:"######################\n CDN Metrics Settings \n######################" json:"metrics"`
+	WorkerCache struct {
+		MaxIdleDays int64 `toml:"maxIdleDays" default:"30" json:"maxIdleDays" comment:"Worker caches that were not pulled for this number of days are deleted, 0 to disable"`
+	} `toml:"workerCache" comment:"######################\n CDN Worker Cache Settings \n######################" json:"workerCache"`
 }
 
 type rateLimiter struct {
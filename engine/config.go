@@ -320,6 +320,7 @@ func configSetStartupData(conf *Configuration) (string, error) {
 	if conf.API != nil {
 		conf.API.Auth.RSAPrivateKey = string(apiPrivateKeyPEM)
 		conf.API.Secrets.Key = sdk.RandomString(32)
+		conf.API.Audit.HMACKey = sdk.RandomString(64)
 
 		key, _ := keyloader.GenerateKey("hmac", gorpmapper.KeySignIdentifier, false, time.Now())
 		conf.API.Database.SignatureKey = database.RollingKeyConfig{Cipher: "hmac"}
@@ -114,3 +114,23 @@ func TestHelperProcess(t *testing.T) {
 	t.Log(os.Environ())
 	time.Sleep(30 * time.Second)
 }
+
+func TestHatcheryLocalCanSpawnMemoryRequirement(t *testing.T) {
+	var h = local.New()
+	reqs := []sdk.Requirement{{Name: "mem", Type: sdk.MemoryRequirement, Value: "2048"}}
+
+	require.False(t, h.CanSpawn(context.TODO(), nil, 1, reqs))
+
+	h.Config.Sandbox.Enabled = true
+	require.True(t, h.CanSpawn(context.TODO(), nil, 1, reqs))
+}
+
+func TestHatcheryLocalCanSpawnCPURequirement(t *testing.T) {
+	var h = local.New()
+	reqs := []sdk.Requirement{{Name: "cpu", Type: sdk.CPURequirement, Value: "1.5"}}
+
+	require.False(t, h.CanSpawn(context.TODO(), nil, 1, reqs))
+
+	h.Config.Sandbox.Enabled = true
+	require.True(t, h.CanSpawn(context.TODO(), nil, 1, reqs))
+}
@@ -112,6 +112,10 @@ func (h *HatcheryLocal) CheckConfiguration(cfg interface{}) error {
 	} else if err != nil {
 		return fmt.Errorf("Invalid basedir: %v", err)
 	}
+
+	if err := checkSandboxConfiguration(hconfig.Sandbox); err != nil {
+		return fmt.Errorf("Invalid sandbox configuration: %v", err)
+	}
 	return nil
 }
 
@@ -215,8 +219,14 @@ func (h *HatcheryLocal) CanSpawn(ctx context.Context, _ *sdk.Model, jobID int64,
 	}
 
 	for _, r := range requirements {
-		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
-			log.Debug(ctx, "CanSpawn false service or memory")
+		if r.Type == sdk.ServiceRequirement {
+			log.Debug(ctx, "CanSpawn false service")
+			return false
+		}
+
+		// memory and cpu requirements are applied as cgroup limits when workers are sandboxed
+		if (r.Type == sdk.MemoryRequirement || r.Type == sdk.CPURequirement) && !h.Config.Sandbox.Enabled {
+			log.Debug(ctx, "CanSpawn false %s", r.Type)
 			return false
 		}
 
@@ -373,6 +383,8 @@ func (h *HatcheryLocal) checkRequirement(r sdk.Requirement) (bool, error) {
 		return true, nil
 	case sdk.PluginRequirement:
 		return true, nil
+	case sdk.MemoryRequirement, sdk.CPURequirement:
+		return h.Config.Sandbox.Enabled, nil
 	case sdk.RegionRequirement:
 		if r.Value != h.Configuration().Provision.Region {
 			log.Debug(context.TODO(), "checkRequirement> job with region requirement: cannot spawn. hatchery-region:%s prerequisite:%s", h.Configuration().Provision.Region, r.Value)
@@ -0,0 +1,74 @@
+package local
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	// sandboxInitCommand is the name used to re-execute the hatchery binary as the sandbox init process
+	sandboxInitCommand = "cds-hatchery-local-sandbox-init"
+	// sandboxToolchainDir is the directory, relative to the worker workspace, where the toolchain root is mounted
+	sandboxToolchainDir = ".toolchain"
+)
+
+// sandboxDefaultHostPaths are the host directories mounted read-only in the worker root filesystem when
+// none is given in the configuration
+var sandboxDefaultHostPaths = []string{"/usr", "/bin", "/sbin", "/lib", "/lib64", "/etc"}
+
+// sandboxedWorker holds the resources allocated for a worker started in the sandbox
+type sandboxedWorker struct {
+	name      string
+	workspace string
+	memory    int64
+	cpus      float64
+	cgroup    string
+	syncPipe  *os.File
+	syncRead  *os.File
+	network   *exec.Cmd
+}
+
+// sandboxMemory returns the memory limit in Mo for a worker, the job memory requirement
+// takes precedence over the hatchery default memory
+func (h *HatcheryLocal) sandboxMemory(requirements []sdk.Requirement) (int64, error) {
+	memory := h.Config.Sandbox.DefaultMemory
+	for _, r := range requirements {
+		if r.Type != sdk.MemoryRequirement {
+			continue
+		}
+		m, err := strconv.ParseInt(r.Value, 10, 64)
+		if err != nil {
+			return 0, sdk.NewErrorFrom(sdk.ErrInvalidData, "invalid memory requirement %q", r.Value)
+		}
+		memory = m
+	}
+	return memory, nil
+}
+
+// sandboxCPUs returns the maximum number of CPUs usable by a worker from the job cpu requirement,
+// 0 means no limit
+func sandboxCPUs(requirements []sdk.Requirement) (float64, error) {
+	var cpus float64
+	for _, r := range requirements {
+		if r.Type != sdk.CPURequirement {
+			continue
+		}
+		c, err := strconv.ParseFloat(r.Value, 64)
+		if err != nil || c <= 0 {
+			return 0, sdk.NewErrorFrom(sdk.ErrInvalidData, "invalid cpu requirement %q", r.Value)
+		}
+		cpus = c
+	}
+	return cpus, nil
+}
+
+// sandboxHostPaths returns the host directories mounted read-only in the worker root filesystem
+func (h *HatcheryLocal) sandboxHostPaths() []string {
+	if len(h.Config.Sandbox.HostPaths) > 0 {
+		return h.Config.Sandbox.HostPaths
+	}
+	return sandboxDefaultHostPaths
+}
@@ -0,0 +1,421 @@
+// +build linux
+
+package local
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/rockbears/log"
+	"golang.org/x/sys/unix"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func init() {
+	reexec.Register(sandboxInitCommand, sandboxInit)
+}
+
+// checkSandboxConfiguration checks that the host is able to run sandboxed workers
+func checkSandboxConfiguration(cfg SandboxConfiguration) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.NetworkHelper == "" {
+		return fmt.Errorf("sandbox network helper is mandatory to let workers reach CDS API")
+	}
+	if _, err := exec.LookPath(cfg.NetworkHelper); err != nil {
+		return fmt.Errorf("sandbox network helper %s not found: %v", cfg.NetworkHelper, err)
+	}
+	if cfg.WorkspaceSize <= 0 || cfg.DefaultMemory <= 0 {
+		return fmt.Errorf("sandbox workspace size and default memory must be positive")
+	}
+	if cfg.ToolchainRoot != "" {
+		if ok, err := sdk.DirectoryExists(cfg.ToolchainRoot); !ok || err != nil {
+			return fmt.Errorf("sandbox toolchain root %s is not a directory", cfg.ToolchainRoot)
+		}
+	}
+
+	btes, err := ioutil.ReadFile(filepath.Join(cfg.CgroupRoot, "cgroup.subtree_control"))
+	if err != nil {
+		return fmt.Errorf("invalid sandbox cgroup root %s, a cgroup v2 directory delegated to the hatchery is expected: %v", cfg.CgroupRoot, err)
+	}
+	controllers := strings.Fields(string(btes))
+	if !sdk.IsInArray("memory", controllers) {
+		return fmt.Errorf("memory controller is not enabled in %s/cgroup.subtree_control", cfg.CgroupRoot)
+	}
+	if !sdk.IsInArray("cpu", controllers) {
+		return fmt.Errorf("cpu controller is not enabled in %s/cgroup.subtree_control", cfg.CgroupRoot)
+	}
+	for _, p := range cfg.HostPaths {
+		if !filepath.IsAbs(p) || filepath.Clean(p) == "/" {
+			return fmt.Errorf("invalid sandbox host path %q, an absolute path to a directory other than / is expected", p)
+		}
+	}
+	return nil
+}
+
+// newSandboxedCmd wraps the worker command into the sandbox init process. The init process is started
+// in new namespaces, it waits for the hatchery to setup cgroup and network before executing the worker.
+func (h *HatcheryLocal) newSandboxedCmd(ctx context.Context, spawnArgs hatchery.SpawnArguments, workerCmd *exec.Cmd) (*exec.Cmd, *sandboxedWorker, error) {
+	memory, err := h.sandboxMemory(spawnArgs.Requirements)
+	if err != nil {
+		return nil, nil, err
+	}
+	cpus, err := sandboxCPUs(spawnArgs.Requirements)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	syncReader, syncWriter, err := os.Pipe()
+	if err != nil {
+		return nil, nil, sdk.WithStack(err)
+	}
+
+	args := []string{
+		sandboxInitCommand,
+		"-workspace", workerCmd.Dir,
+		"-workspace-size", strconv.FormatInt(h.Config.Sandbox.WorkspaceSize, 10),
+		"-toolchain", h.Config.Sandbox.ToolchainRoot,
+		"-host-paths", strings.Join(h.sandboxHostPaths(), ","),
+		"--", workerCmd.Path,
+	}
+	args = append(args, workerCmd.Args[1:]...)
+
+	cmd := exec.CommandContext(ctx, "/proc/self/exe")
+	cmd.Args = args
+	cmd.Dir = workerCmd.Dir
+	cmd.Env = workerCmd.Env
+	if h.Config.Sandbox.ToolchainRoot != "" {
+		cmd.Env = sandboxToolchainEnv(cmd.Env, filepath.Join(workerCmd.Dir, sandboxToolchainDir, "bin"))
+	}
+	cmd.ExtraFiles = []*os.File{syncReader}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getuid(), Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getgid(), Size: 1},
+		},
+		GidMappingsEnableSetgroups: false,
+		Pdeathsig:                  syscall.SIGKILL,
+	}
+
+	s := &sandboxedWorker{
+		name:      spawnArgs.WorkerName,
+		workspace: workerCmd.Dir,
+		memory:    memory,
+		cpus:      cpus,
+		cgroup:    filepath.Join(h.Config.Sandbox.CgroupRoot, spawnArgs.WorkerName),
+		syncPipe:  syncWriter,
+		syncRead:  syncReader,
+	}
+	return cmd, s, nil
+}
+
+// sandboxToolchainEnv prepends the toolchain bin directory to the PATH variable
+func sandboxToolchainEnv(env []string, toolchainBin string) []string {
+	for i, e := range env {
+		if strings.HasPrefix(e, "PATH=") {
+			env[i] = "PATH=" + toolchainBin + string(os.PathListSeparator) + strings.TrimPrefix(e, "PATH=")
+			return env
+		}
+	}
+	return append(env, "PATH="+toolchainBin)
+}
+
+// start is called by the hatchery once the sandbox init process is started. It moves the process into
+// its own cgroup, plugs the network namespace then releases the init process.
+func (s *sandboxedWorker) start(ctx context.Context, networkHelper string, pid int) error {
+	defer s.syncPipe.Close() // nolint
+	_ = s.syncRead.Close()
+
+	if err := os.Mkdir(s.cgroup, 0755); err != nil {
+		return sdk.WrapError(err, "unable to create cgroup %s", s.cgroup)
+	}
+	limits := map[string]string{
+		"memory.max":      strconv.FormatInt(s.memory*1024*1024, 10),
+		"memory.swap.max": "0",
+	}
+	if s.cpus > 0 {
+		limits["cpu.max"] = fmt.Sprintf("%d 100000", int64(s.cpus*100000))
+	}
+	for k, v := range limits {
+		if err := ioutil.WriteFile(filepath.Join(s.cgroup, k), []byte(v), 0644); err != nil {
+			if k == "memory.swap.max" && os.IsNotExist(err) {
+				continue
+			}
+			return sdk.WrapError(err, "unable to set %s on cgroup %s", k, s.cgroup)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(s.cgroup, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0644); err != nil {
+		return sdk.WrapError(err, "unable to move worker %s into cgroup %s", s.name, s.cgroup)
+	}
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer readyReader.Close() // nolint
+	s.network = exec.Command(networkHelper, "--configure", "--mtu=65520", "--disable-host-loopback", "--ready-fd=3", strconv.Itoa(pid), "tap0")
+	s.network.ExtraFiles = []*os.File{readyWriter}
+	if err := s.network.Start(); err != nil {
+		readyWriter.Close() // nolint
+		return sdk.WrapError(err, "unable to start network helper for worker %s", s.name)
+	}
+	readyWriter.Close() // nolint
+	if _, err := readyReader.Read(make([]byte, 1)); err != nil {
+		return sdk.WrapError(err, "network helper for worker %s is not ready", s.name)
+	}
+
+	log.Debug(ctx, "hatchery> local> sandbox ready for worker %s (memory: %dMo, cpus: %v)", s.name, s.memory, s.cpus)
+	if _, err := s.syncPipe.Write([]byte{0}); err != nil {
+		return sdk.WrapError(err, "unable to release worker %s", s.name)
+	}
+	return nil
+}
+
+// cleanup releases the sandbox resources once the worker process exited
+func (s *sandboxedWorker) cleanup(ctx context.Context) {
+	// Pipes are already closed if the sandbox was started, otherwise the init process gets EOF and exits
+	_ = s.syncPipe.Close()
+	_ = s.syncRead.Close()
+	if s.network != nil && s.network.Process != nil {
+		_ = s.network.Process.Kill()
+		_ = s.network.Wait()
+	}
+	if err := os.Remove(s.cgroup); err != nil && !os.IsNotExist(err) {
+		log.Warn(ctx, "hatchery> local> unable to remove cgroup %s: %v", s.cgroup, err)
+	}
+	if err := os.RemoveAll(s.workspace); err != nil {
+		log.Warn(ctx, "hatchery> local> unable to remove workspace %s: %v", s.workspace, err)
+	}
+}
+
+// sandboxInit is the entrypoint of the sandbox init process. It runs as root of its user namespace,
+// switches to a minimal root filesystem then executes the worker.
+func sandboxInit() {
+	fs := flag.NewFlagSet(sandboxInitCommand, flag.ExitOnError)
+	workspace := fs.String("workspace", "", "worker workspace")
+	workspaceSize := fs.Int64("workspace-size", 0, "workspace size in Mo")
+	toolchain := fs.String("toolchain", "", "toolchain root")
+	hostPaths := fs.String("host-paths", "", "host directories mounted read-only")
+	_ = fs.Parse(os.Args[1:])
+
+	argv := fs.Args()
+	if len(argv) == 0 {
+		fmt.Fprintln(os.Stderr, "sandbox init: missing worker command")
+		os.Exit(1)
+	}
+
+	if err := sandboxSetup(*workspace, *workspaceSize, *toolchain, strings.Split(*hostPaths, ","), argv[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "sandbox init: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := syscall.Exec(argv[0], argv, os.Environ()); err != nil {
+		fmt.Fprintf(os.Stderr, "sandbox init: unable to exec %s: %v\n", argv[0], err)
+		os.Exit(1)
+	}
+}
+
+// sandboxSetup builds the root filesystem of the worker on a tmpfs then pivots into it. The worker only sees
+// the host paths and its own binary read-only, a private workspace and /tmp, a minimal /dev and its own /proc.
+func sandboxSetup(workspace string, workspaceSize int64, toolchain string, hostPaths []string, workerBinary string) error {
+	// Wait for the hatchery to setup cgroup and network
+	syncPipe := os.NewFile(3, "sync")
+	if _, err := syncPipe.Read(make([]byte, 1)); err != nil {
+		return fmt.Errorf("sandbox not released by the hatchery: %v", err)
+	}
+	syncPipe.Close() // nolint
+
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("unable to make mounts private: %v", err)
+	}
+
+	// The new root is mounted over the workspace directory, it is hidden from the host by the mount namespace
+	root := workspace
+	if err := unix.Mount("tmpfs", root, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "size=16m,mode=0755"); err != nil {
+		return fmt.Errorf("unable to mount root filesystem: %v", err)
+	}
+
+	for _, p := range hostPaths {
+		if p == "" {
+			continue
+		}
+		if err := sandboxMountHostPath(root, p); err != nil {
+			return err
+		}
+	}
+	if err := os.Mkdir(filepath.Join(root, "tmp"), 0755); err != nil && !os.IsExist(err) {
+		return err
+	}
+	if err := unix.Mount("tmpfs", filepath.Join(root, "tmp"), "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, fmt.Sprintf("size=%dm,mode=1777", workspaceSize)); err != nil {
+		return fmt.Errorf("unable to mount /tmp: %v", err)
+	}
+	// The worker binary may already be visible through a host path
+	if _, err := os.Stat(filepath.Join(root, workerBinary)); os.IsNotExist(err) {
+		if err := sandboxBindReadOnly(workerBinary, filepath.Join(root, workerBinary)); err != nil {
+			return fmt.Errorf("unable to mount worker binary: %v", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, workspace), 0755); err != nil {
+		return err
+	}
+	if err := unix.Mount("tmpfs", filepath.Join(root, workspace), "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, fmt.Sprintf("size=%dm,mode=0700", workspaceSize)); err != nil {
+		return fmt.Errorf("unable to mount workspace %s: %v", workspace, err)
+	}
+	if toolchain != "" {
+		if err := sandboxBindReadOnly(toolchain, filepath.Join(root, workspace, sandboxToolchainDir)); err != nil {
+			return fmt.Errorf("unable to mount toolchain %s: %v", toolchain, err)
+		}
+	}
+
+	if err := sandboxMountDev(root); err != nil {
+		return err
+	}
+	// Hide the host processes. The new proc is mounted before leaving the host root: in a user namespace,
+	// mounting proc requires a fully visible proc mount.
+	if err := os.Mkdir(filepath.Join(root, "proc"), 0755); err != nil && !os.IsExist(err) {
+		return err
+	}
+	if err := unix.Mount("proc", filepath.Join(root, "proc"), "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, ""); err != nil {
+		return fmt.Errorf("unable to mount /proc: %v", err)
+	}
+
+	if err := sandboxPivotRoot(root); err != nil {
+		return err
+	}
+	return os.Chdir(workspace)
+}
+
+// sandboxMountHostPath mounts a host directory read-only in the new root, symbolic links like /bin -> usr/bin
+// are copied as is.
+func sandboxMountHostPath(root, p string) error {
+	fi, err := os.Lstat(p)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(root, p)
+	if fi.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(p)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.Symlink(link, target)
+	}
+	if err := sandboxBindReadOnly(p, target); err != nil {
+		return fmt.Errorf("unable to mount host path %s: %v", p, err)
+	}
+	return nil
+}
+
+// sandboxBindReadOnly bind mounts a file or a directory then remounts it read-only
+func sandboxBindReadOnly(source, target string) error {
+	fi, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		if err := os.Mkdir(target, 0755); err != nil && !os.IsExist(err) {
+			return err
+		}
+	} else {
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_RDONLY, 0644)
+		if err != nil {
+			return err
+		}
+		f.Close() // nolint
+	}
+	if err := unix.Mount(source, target, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return err
+	}
+	// Flags locked by the parent mount have to be kept to remount it read-only
+	var st unix.Statfs_t
+	if err := unix.Statfs(source, &st); err != nil {
+		return err
+	}
+	flags := uintptr(unix.MS_BIND | unix.MS_REMOUNT | unix.MS_RDONLY)
+	for stFlag, msFlag := range map[int64]uintptr{unix.ST_NOSUID: unix.MS_NOSUID, unix.ST_NODEV: unix.MS_NODEV, unix.ST_NOEXEC: unix.MS_NOEXEC, unix.ST_RELATIME: unix.MS_RELATIME, unix.ST_NOATIME: unix.MS_NOATIME} {
+		if int64(st.Flags)&stFlag != 0 {
+			flags |= msFlag
+		}
+	}
+	return unix.Mount("", target, "", flags, "")
+}
+
+// sandboxMountDev creates a minimal /dev with the host null, zero, full, random, urandom and tty devices
+func sandboxMountDev(root string) error {
+	dev := filepath.Join(root, "dev")
+	if err := os.Mkdir(dev, 0755); err != nil && !os.IsExist(err) {
+		return err
+	}
+	if err := unix.Mount("tmpfs", dev, "tmpfs", unix.MS_NOSUID|unix.MS_NOEXEC, "size=64k,mode=0755"); err != nil {
+		return fmt.Errorf("unable to mount /dev: %v", err)
+	}
+	for _, d := range []string{"null", "zero", "full", "random", "urandom", "tty"} {
+		target := filepath.Join(dev, d)
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_RDONLY, 0644)
+		if err != nil {
+			return err
+		}
+		f.Close() // nolint
+		if err := unix.Mount(filepath.Join("/dev", d), target, "", unix.MS_BIND, ""); err != nil {
+			return fmt.Errorf("unable to mount /dev/%s: %v", d, err)
+		}
+	}
+	for link, target := range map[string]string{"fd": "/proc/self/fd", "stdin": "/proc/self/fd/0", "stdout": "/proc/self/fd/1", "stderr": "/proc/self/fd/2"} {
+		if err := os.Symlink(target, filepath.Join(dev, link)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sandboxPivotRoot makes the given directory the root filesystem and detaches the host one
+func sandboxPivotRoot(root string) error {
+	if err := os.Chdir(root); err != nil {
+		return err
+	}
+	if err := os.Mkdir(".oldroot", 0700); err != nil {
+		return err
+	}
+	if err := unix.PivotRoot(".", ".oldroot"); err != nil {
+		return fmt.Errorf("unable to pivot root: %v", err)
+	}
+	if err := os.Chdir("/"); err != nil {
+		return err
+	}
+	if err := unix.Unmount("/.oldroot", unix.MNT_DETACH); err != nil {
+		return fmt.Errorf("unable to detach host root filesystem: %v", err)
+	}
+	if err := os.Remove("/.oldroot"); err != nil {
+		return err
+	}
+	// Nothing can be added to the root filesystem once it's built
+	if err := unix.Mount("", "/", "", unix.MS_BIND|unix.MS_REMOUNT|unix.MS_RDONLY|unix.MS_NOSUID|unix.MS_NODEV, ""); err != nil {
+		return fmt.Errorf("unable to remount root filesystem read-only: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// +build linux
+
+package local
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/reexec"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func TestMain(m *testing.M) {
+	// The test binary is re-executed as the sandbox init process
+	if reexec.Init() {
+		return
+	}
+	os.Exit(m.Run())
+}
+
+type testLogger struct {
+	t *testing.T
+}
+
+func (l testLogger) Logf(fmt string, values ...interface{}) {
+	l.t.Logf(strings.TrimSuffix(fmt, "\n"), values...)
+}
+
+func (l testLogger) Errorf(fmt string, values ...interface{}) {
+	l.t.Errorf(fmt, values...)
+}
+
+func (l testLogger) Fatalf(fmt string, values ...interface{}) {
+	l.t.Fatalf(fmt, values...)
+}
+
+func newTestSandboxedWorker(t *testing.T) *sandboxedWorker {
+	syncReader, syncWriter, err := os.Pipe()
+	require.NoError(t, err)
+	return &sandboxedWorker{
+		name:      "my-worker",
+		workspace: filepath.Join(t.TempDir(), "workspace"),
+		cgroup:    filepath.Join(t.TempDir(), "cgroup"),
+		syncPipe:  syncWriter,
+		syncRead:  syncReader,
+	}
+}
+
+func TestSandboxCleanup(t *testing.T) {
+	s := newTestSandboxedWorker(t)
+	require.NoError(t, os.MkdirAll(filepath.Join(s.workspace, "src"), 0755))
+	require.NoError(t, os.Mkdir(s.cgroup, 0755))
+
+	s.cleanup(context.TODO())
+
+	_, err := os.Stat(s.workspace)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(s.cgroup)
+	require.True(t, os.IsNotExist(err))
+	_, err = s.syncPipe.Write([]byte{0})
+	require.Error(t, err, "sync pipe should be closed")
+
+	// Cleanup is called on every spawn path, a second call should not fail
+	s.cleanup(context.TODO())
+}
+
+func TestStartCmdFailureReleasesSandbox(t *testing.T) {
+	h := New()
+	s := newTestSandboxedWorker(t)
+	require.NoError(t, os.MkdirAll(s.workspace, 0755))
+
+	goroutines := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		cmd := exec.Command(filepath.Join(t.TempDir(), "unknown-binary"))
+		require.Error(t, h.startCmd(context.TODO(), "my-worker", cmd, s, testLogger{t}))
+	}
+
+	_, err := os.Stat(s.workspace)
+	require.True(t, os.IsNotExist(err), "workspace should be removed")
+	require.Empty(t, h.workers)
+	// Give some time to any leaked goroutine to show up
+	time.Sleep(100 * time.Millisecond)
+	require.LessOrEqual(t, runtime.NumGoroutine(), goroutines)
+}
+
+func TestSandboxSetup(t *testing.T) {
+	h := New()
+	h.Config.Sandbox.WorkspaceSize = 16
+	h.Config.Sandbox.DefaultMemory = 128
+
+	workspace := t.TempDir()
+	hostDir := t.TempDir()
+	script := strings.Join([]string{
+		"set -ex",
+		`test "$(pwd)" = "` + workspace + `"`,
+		"touch ./file /tmp/file",
+		"test ! -e " + hostDir,
+		"! touch /usr/file 2>/dev/null",
+		"! touch /file 2>/dev/null",
+		"test -c /dev/null",
+		`test "$$" = 1`,
+	}, "\n")
+	workerCmd := exec.Command("/bin/sh", "-c", script)
+	workerCmd.Dir = workspace
+
+	cmd, s, err := h.newSandboxedCmd(context.TODO(), hatchery.SpawnArguments{WorkerName: "my-worker", Requirements: []sdk.Requirement{{Type: sdk.CPURequirement, Value: "0.5"}}}, workerCmd)
+	require.NoError(t, err)
+	require.Equal(t, 0.5, s.cpus)
+	require.Equal(t, int64(128), s.memory)
+	require.Contains(t, cmd.Args, strings.Join(sandboxDefaultHostPaths, ","))
+
+	var output strings.Builder
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Start(); err != nil {
+		if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EINVAL) {
+			t.Skipf("unprivileged user namespaces are not available: %v", err)
+		}
+		require.NoError(t, err)
+	}
+
+	// Release the init process without cgroup and network that require a delegated host setup
+	_ = s.syncRead.Close()
+	_, err = s.syncPipe.Write([]byte{0})
+	require.NoError(t, err)
+	require.NoError(t, cmd.Wait(), output.String())
+
+	// The workspace tmpfs only lives in the sandbox mount namespace
+	_, err = os.Stat(filepath.Join(workspace, "file"))
+	require.True(t, os.IsNotExist(err))
+}
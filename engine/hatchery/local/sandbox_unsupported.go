@@ -0,0 +1,28 @@
+// +build !linux
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+func checkSandboxConfiguration(cfg SandboxConfiguration) error {
+	if cfg.Enabled {
+		return fmt.Errorf("worker sandbox is only supported on linux")
+	}
+	return nil
+}
+
+func (h *HatcheryLocal) newSandboxedCmd(_ context.Context, _ hatchery.SpawnArguments, _ *exec.Cmd) (*exec.Cmd, *sandboxedWorker, error) {
+	return nil, nil, fmt.Errorf("worker sandbox is only supported on linux")
+}
+
+func (s *sandboxedWorker) start(_ context.Context, _ string, _ int) error {
+	return fmt.Errorf("worker sandbox is only supported on linux")
+}
+
+func (s *sandboxedWorker) cleanup(_ context.Context) {}
@@ -13,7 +13,19 @@ import (
 // HatcheryConfiguration is the configuration for local hatchery
 type HatcheryConfiguration struct {
 	service.HatcheryCommonConfiguration `mapstructure:"commonConfiguration" toml:"commonConfiguration" json:"commonConfiguration"`
-	Basedir                             string `mapstructure:"basedir" toml:"basedir" default:"/var/lib/cds-engine" comment:"BaseDir for worker workspace" json:"basedir"`
+	Basedir                             string               `mapstructure:"basedir" toml:"basedir" default:"/var/lib/cds-engine" comment:"BaseDir for worker workspace" json:"basedir"`
+	Sandbox                             SandboxConfiguration `mapstructure:"sandbox" toml:"sandbox" comment:"######################\n Worker sandbox, Linux only \n######################" json:"sandbox"`
+}
+
+// SandboxConfiguration is the configuration of the namespaces sandbox used to isolate local workers
+type SandboxConfiguration struct {
+	Enabled       bool     `mapstructure:"enabled" toml:"enabled" default:"false" commented:"true" comment:"Run workers in user, mount, pid and network namespaces" json:"enabled"`
+	WorkspaceSize int64    `mapstructure:"workspaceSize" toml:"workspaceSize" default:"4096" commented:"true" comment:"Size of the private tmpfs workspace in Mo" json:"workspaceSize"`
+	DefaultMemory int64    `mapstructure:"defaultMemory" toml:"defaultMemory" default:"1024" commented:"true" comment:"Worker default memory in Mo, overridden by the job memory requirement" json:"defaultMemory"`
+	CgroupRoot    string   `mapstructure:"cgroupRoot" toml:"cgroupRoot" default:"/sys/fs/cgroup/cds-hatchery-local" commented:"true" comment:"cgroup v2 directory delegated to the hatchery user, a sub group is created for each worker" json:"cgroupRoot"`
+	ToolchainRoot string   `mapstructure:"toolchainRoot" toml:"toolchainRoot" default:"" commented:"true" comment:"Directory mounted read-only in the worker workspace, its bin directory is added to the worker PATH" json:"toolchainRoot"`
+	HostPaths     []string `mapstructure:"hostPaths" toml:"hostPaths" default:"" commented:"true" comment:"Host directories mounted read-only in the worker root filesystem, default is /usr, /bin, /sbin, /lib, /lib64 and /etc" json:"hostPaths"`
+	NetworkHelper string   `mapstructure:"networkHelper" toml:"networkHelper" default:"slirp4netns" commented:"true" comment:"slirp4netns binary used to give the worker network namespace a user-mode access to the CDS API" json:"networkHelper"`
 }
 
 // HatcheryLocal implements HatcheryMode interface for local usage
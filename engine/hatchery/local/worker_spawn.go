@@ -113,10 +113,19 @@ func (h *HatcheryLocal) SpawnWorker(ctx context.Context, spawnArgs hatchery.Spaw
 		cmd.Env = append(cmd.Env, k+"="+v)
 	}
 
+	var sandbox *sandboxedWorker
+	if h.Config.Sandbox.Enabled {
+		var err error
+		cmd, sandbox, err = h.newSandboxedCmd(ctx, spawnArgs, cmd)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Wait in a goroutine so that when process exits, Wait() update cmd.ProcessState
 	go func() {
 		log.Debug(ctx, "hatchery> local> starting worker: %s", spawnArgs.WorkerName)
-		if err := h.startCmd(spawnArgs.WorkerName, cmd, localWorkerLogger{spawnArgs.WorkerName}); err != nil {
+		if err := h.startCmd(ctx, spawnArgs.WorkerName, cmd, sandbox, localWorkerLogger{spawnArgs.WorkerName}); err != nil {
 			log.Error(ctx, "hatchery> local> %v", err)
 		}
 	}()
@@ -130,7 +139,12 @@ type Logger interface {
 	Fatalf(fmt string, values ...interface{})
 }
 
-func (h *HatcheryLocal) startCmd(name string, cmd *exec.Cmd, logger Logger) error {
+func (h *HatcheryLocal) startCmd(ctx context.Context, name string, cmd *exec.Cmd, sandbox *sandboxedWorker, logger Logger) error {
+	// Sandbox resources are allocated before the command is started, they have to be released on every path
+	if sandbox != nil {
+		defer sandbox.cleanup(ctx)
+	}
+
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		return fmt.Errorf("Failure due to internal error: unable to capture stdout: %v", err)
@@ -138,9 +152,16 @@ func (h *HatcheryLocal) startCmd(name string, cmd *exec.Cmd, logger Logger) erro
 
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
+		stdout.Close() // nolint
 		return fmt.Errorf("Failure due to internal error: unable to capture stderr: %v", err)
 	}
 
+	if err := cmd.Start(); err != nil {
+		stdout.Close() // nolint
+		stderr.Close() // nolint
+		return fmt.Errorf("unable to start command: %v", err)
+	}
+
 	stdoutreader := bufio.NewReader(stdout)
 	stderrreader := bufio.NewReader(stderr)
 
@@ -152,7 +173,6 @@ func (h *HatcheryLocal) startCmd(name string, cmd *exec.Cmd, logger Logger) erro
 				logger.Logf(line)
 			}
 			if err != nil {
-				stdout.Close()
 				close(outchan)
 				return
 			}
@@ -167,21 +187,27 @@ func (h *HatcheryLocal) startCmd(name string, cmd *exec.Cmd, logger Logger) erro
 				logger.Logf(line)
 			}
 			if err != nil {
-				stderr.Close()
 				close(errchan)
 				return
 			}
 		}
 	}()
 
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("unable to start command: %v", err)
+	if sandbox != nil {
+		if err := sandbox.start(ctx, h.Config.Sandbox.NetworkHelper, cmd.Process.Pid); err != nil {
+			_ = cmd.Process.Kill()
+			<-outchan
+			<-errchan
+			_ = cmd.Wait()
+			return fmt.Errorf("unable to start sandbox: %v", err)
+		}
 	}
 
 	h.Lock()
 	h.workers[name] = workerCmd{cmd: cmd, created: time.Now()}
 	h.Unlock()
 
+	// Pipes have to be fully read before calling Wait, it closes them
 	<-outchan
 	<-errchan
 	if err := cmd.Wait(); err != nil {
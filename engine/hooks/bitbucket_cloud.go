@@ -26,6 +26,11 @@ func (s *Service) generatePayloadFromBitbucketCloudRequest(ctx context.Context,
 	getVariableFromBitbucketCloudRepository(payload, request.Repository)
 	getPayloadStringVariable(ctx, payload, request)
 
+	if request.PullRequest != nil {
+		getVariableFromBitbucketCloudPullRequest(payload, request.PullRequest)
+		return append(payloads, payload), nil
+	}
+
 	for _, pushChange := range request.Push.Changes {
 		if pushChange.Closed {
 			if pushChange.Old.Type == "branch" {
@@ -71,6 +76,21 @@ func getVariableFromBitbucketCloudChange(ctx context.Context, payload map[string
 	payload[GIT_HASH_SHORT] = sdk.StringFirstN(change.New.Target.Hash, 7)
 }
 
+// getVariableFromBitbucketCloudPullRequest adds the pull request variables. As for the other repository managers, the
+// head of the pull request is only used by the runs that deploy a preview environment.
+func getVariableFromBitbucketCloudPullRequest(payload map[string]interface{}, pr *BitbucketCloudPullRequest) {
+	payload[PR_ID] = pr.ID
+	payload[PR_STATE] = pr.State
+	payload[PR_TITLE] = pr.Title
+	payload[PR_HEAD_BRANCH] = pr.Source.Branch.Name
+	payload[PR_HEAD_HASH] = pr.Source.Commit.Hash
+	if pr.Source.Repository != nil {
+		payload[PR_HEAD_REPOSITORY] = pr.Source.Repository.FullName
+	}
+	payload[GIT_BRANCH_DEST] = pr.Destination.Branch.Name
+	payload[GIT_HASH_DEST] = pr.Destination.Commit.Hash
+}
+
 func getVariableFromBitbucketCloudRepository(payload map[string]interface{}, repo *BitbucketCloudRepository) {
 	if repo == nil {
 		return
@@ -122,6 +122,7 @@ func getVariableFromBitbucketServerPullRequest(payload map[string]interface{}, p
 	payload[PR_ID] = pr.ID
 	payload[PR_STATE] = pr.State
 	payload[PR_TITLE] = pr.Title
+	payload[PR_HEAD_REPOSITORY] = fmt.Sprintf("%s/%s", pr.FromRef.Repository.Project.Key, pr.FromRef.Repository.Slug)
 
 	if payload[GIT_EVENT] == "pr:merged" {
 		payload[GIT_BRANCH] = pr.ToRef.DisplayID
@@ -47,6 +47,7 @@ func (s *Service) generatePayloadFromGithubRequest(ctx context.Context, t *sdk.T
 
 	getPayloadFromRepository(payload, request.Repository)
 	getPayloadFromCommit(payload, request.HeadCommit)
+	getPayloadFromGithubPullRequest(payload, request.Action, request.PullRequest)
 
 	if len(request.Commits) > 0 {
 		payload[GIT_MESSAGE] = request.Commits[0].Message
@@ -69,6 +70,25 @@ func getPayloadFromRepository(payload map[string]interface{}, repo *GithubReposi
 	payload[GIT_REPOSITORY] = repo.FullName
 }
 
+// getPayloadFromGithubPullRequest adds the pull request variables. The git variables of the payload are left unchanged,
+// the head of the pull request is only used by the runs that deploy a preview environment.
+func getPayloadFromGithubPullRequest(payload map[string]interface{}, action string, pr *GithubPullRequest) {
+	if pr == nil {
+		return
+	}
+	payload[PR_ID] = pr.Number
+	payload[PR_ACTION] = action
+	payload[PR_STATE] = pr.State
+	payload[PR_TITLE] = pr.Title
+	payload[PR_HEAD_BRANCH] = pr.Head.Ref
+	payload[PR_HEAD_HASH] = pr.Head.Sha
+	if pr.Head.Repo != nil {
+		payload[PR_HEAD_REPOSITORY] = pr.Head.Repo.FullName
+	}
+	payload[GIT_BRANCH_DEST] = pr.Base.Ref
+	payload[GIT_HASH_DEST] = pr.Base.Sha
+}
+
 func getPayloadFromCommit(payload map[string]interface{}, commit *GithubCommit) {
 	if commit == nil {
 		return
@@ -55,11 +55,32 @@ func (s *Service) generatePayloadFromGitlabRequest(ctx context.Context, t *sdk.T
 
 	getPayloadFromGitlabProject(payload, request.Project)
 	getPayloadFromGitlabCommit(payload, request.Commits)
+	if request.ObjectKind == "merge_request" {
+		getPayloadFromGitlabMergeRequest(payload, request.ObjectAttributes)
+	}
 	getPayloadStringVariable(ctx, payload, request)
 
 	return payload, nil
 }
 
+// getPayloadFromGitlabMergeRequest adds the merge request variables. The git variables of the payload are left
+// unchanged, the head of the merge request is only used by the runs that deploy a preview environment.
+func getPayloadFromGitlabMergeRequest(payload map[string]interface{}, mr *GitlabMergeRequest) {
+	if mr == nil {
+		return
+	}
+	payload[PR_ID] = mr.IID
+	payload[PR_ACTION] = mr.Action
+	payload[PR_STATE] = mr.State
+	payload[PR_TITLE] = mr.Title
+	payload[PR_HEAD_BRANCH] = mr.SourceBranch
+	payload[PR_HEAD_HASH] = mr.LastCommit.ID
+	if mr.Source != nil {
+		payload[PR_HEAD_REPOSITORY] = mr.Source.PathWithNamespace
+	}
+	payload[GIT_BRANCH_DEST] = mr.TargetBranch
+}
+
 func getPayloadFromGitlabCommit(payload map[string]interface{}, commits []GitlabCommit) {
 	if len(commits) == 0 {
 		return
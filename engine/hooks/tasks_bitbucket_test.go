@@ -413,6 +413,7 @@ func Test_doWebHookExecutionBitbucketPROpened(t *testing.T) {
 	test.Equal(t, "OPEN", hs[0].Payload[PR_STATE])
 	test.Equal(t, "My First PR", hs[0].Payload[PR_TITLE])
 	test.Equal(t, "fork/repo", hs[0].Payload[GIT_REPOSITORY])
+	test.Equal(t, "fork/repo", hs[0].Payload[PR_HEAD_REPOSITORY])
 
 }
 
@@ -69,6 +69,46 @@ func Test_doWebHookExecutionTagGithub(t *testing.T) {
 	require.Equal(t, "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", hs[0].Payload["git.hash"])
 }
 
+func Test_doWebHookExecutionGithubPullRequest(t *testing.T) {
+	log.Factory = log.NewTestingWrapper(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter: {Value: "push;pull_request"},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(githubPREvent),
+			RequestHeader: map[string][]string{
+				GithubHeader: {"pull_request"},
+			},
+			RequestURL: "",
+		},
+	}
+	hs, err := s.doWebHookExecution(context.TODO(), task)
+	test.NoError(t, err)
+
+	require.Equal(t, 1, len(hs))
+	require.Equal(t, "2", hs[0].Payload["git.pr.id"])
+	require.Equal(t, "opened", hs[0].Payload["git.pr.action"])
+	require.Equal(t, "open", hs[0].Payload["git.pr.state"])
+	require.Equal(t, "changes", hs[0].Payload["git.pr.head.branch"])
+	require.Equal(t, "ec26c3e57ca3a959ca5aad62de7213c562f8c821", hs[0].Payload["git.pr.head.hash"])
+	require.Equal(t, "Codertocat/Hello-World", hs[0].Payload["git.pr.head.repository"])
+	require.Equal(t, "master", hs[0].Payload["git.branch.dest"])
+
+	// The git variables of existing pull request webhooks are unchanged
+	require.Equal(t, "Codertocat/Hello-World", hs[0].Payload["git.repository"])
+	_, has := hs[0].Payload["git.branch"]
+	require.False(t, has)
+	_, has = hs[0].Payload["git.hash"]
+	require.False(t, has)
+	_, has = hs[0].Payload["cds.triggered_by.username"]
+	require.False(t, has)
+}
+
 func Test_prEvent(t *testing.T) {
 	loc, _ := time.LoadLocation("UTC")
 
@@ -76,6 +116,11 @@ func Test_prEvent(t *testing.T) {
 	require.NoError(t, json.Unmarshal([]byte(githubPREvent), &request))
 	datePR := time.Time(request.Repository.PushedAt).In(loc)
 	require.Equal(t, "2019-05-15 15:20:32 +0000 UTC", datePR.String())
+	require.NotNil(t, request.PullRequest)
+	require.Equal(t, "opened", request.Action)
+	require.Equal(t, 2, request.PullRequest.Number)
+	require.Equal(t, "changes", request.PullRequest.Head.Ref)
+	require.Equal(t, "master", request.PullRequest.Base.Ref)
 
 	var requestPush GithubWebHookEvent
 	require.NoError(t, json.Unmarshal([]byte(githubPushEvent), &requestPush))
@@ -42,6 +42,79 @@ func Test_doWebHookExecutionGitlab(t *testing.T) {
 	assert.Equal(t, "da1560886d4f094c3e6c9ef40349f7d38b5d27d7", hs[0].Payload["git.hash"])
 }
 
+func Test_doWebHookExecutionGitlabMergeRequest(t *testing.T) {
+	log.Factory = log.NewTestingWrapper(t)
+	s, cancel := setupTestHookService(t)
+	defer cancel()
+	task := &sdk.TaskExecution{
+		UUID: sdk.RandomString(10),
+		Type: TypeRepoManagerWebHook,
+		Config: sdk.WorkflowNodeHookConfig{
+			sdk.HookConfigEventFilter: {Value: string(gitlab.EventTypeMergeRequest)},
+		},
+		WebHook: &sdk.WebHookExecution{
+			RequestBody: []byte(gitlabMergeRequestEvent),
+			RequestHeader: map[string][]string{
+				GitlabHeader: {string(gitlab.EventTypeMergeRequest)},
+			},
+			RequestURL: "",
+		},
+	}
+	hs, err := s.doWebHookExecution(context.TODO(), task)
+	test.NoError(t, err)
+
+	assert.Equal(t, 1, len(hs))
+	assert.Equal(t, "1", hs[0].Payload["git.pr.id"])
+	assert.Equal(t, "open", hs[0].Payload["git.pr.action"])
+	assert.Equal(t, "ms-viewport", hs[0].Payload["git.pr.head.branch"])
+	assert.Equal(t, "da1560886d4f094c3e6c9ef40349f7d38b5d27d7", hs[0].Payload["git.pr.head.hash"])
+	assert.Equal(t, "gitlabhq/gitlab-test", hs[0].Payload["git.pr.head.repository"])
+	assert.Equal(t, "master", hs[0].Payload["git.branch.dest"])
+
+	// The git variables of existing merge request webhooks are unchanged
+	assert.Equal(t, "gitlabhq/gitlab-test", hs[0].Payload["git.repository"])
+	for _, k := range []string{"git.branch", "git.hash", "git.author", "cds.triggered_by.username"} {
+		_, has := hs[0].Payload[k]
+		assert.False(t, has, k)
+	}
+}
+
+var gitlabMergeRequestEvent = `
+{
+  "object_kind": "merge_request",
+  "user": {
+    "name": "Administrator",
+    "username": "root",
+    "email": "admin@example.com"
+  },
+  "project": {
+    "id": 1,
+    "name": "Gitlab Test",
+    "web_url": "http://example.com/gitlabhq/gitlab-test",
+    "path_with_namespace": "gitlabhq/gitlab-test",
+    "default_branch": "master"
+  },
+  "object_attributes": {
+    "id": 99,
+    "iid": 1,
+    "target_branch": "master",
+    "source_branch": "ms-viewport",
+    "title": "MS-Viewport",
+    "state": "opened",
+    "action": "open",
+    "source": {
+      "name": "Gitlab Test",
+      "path_with_namespace": "gitlabhq/gitlab-test"
+    },
+    "last_commit": {
+      "id": "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+      "message": "fixed readme",
+      "timestamp": "2012-01-03T23:36:29+02:00"
+    }
+  }
+}
+`
+
 var gitlabPushEvent = `
 	{
   "object_kind": "push",
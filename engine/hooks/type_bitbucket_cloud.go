@@ -7,7 +7,30 @@ type BitbucketCloudWebHookEvent struct {
 	Push struct {
 		Changes []BitbucketCloudChange `json:"changes,omitempty"`
 	} `json:"push"`
-	Actor      *BitbucketCloudActor      `json:"actor,omitempty"`
+	Actor       *BitbucketCloudActor       `json:"actor,omitempty"`
+	Repository  *BitbucketCloudRepository  `json:"repository,omitempty"`
+	PullRequest *BitbucketCloudPullRequest `json:"pullrequest,omitempty"`
+}
+
+// BitbucketCloudPullRequest represents the pull request of a pullrequest:* event
+type BitbucketCloudPullRequest struct {
+	ID          int                          `json:"id"`
+	Title       string                       `json:"title"`
+	State       string                       `json:"state"`
+	Source      BitbucketCloudPullRequestRef `json:"source"`
+	Destination BitbucketCloudPullRequestRef `json:"destination"`
+	MergeCommit *struct {
+		Hash string `json:"hash"`
+	} `json:"merge_commit,omitempty"`
+}
+
+type BitbucketCloudPullRequestRef struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
 	Repository *BitbucketCloudRepository `json:"repository,omitempty"`
 }
 
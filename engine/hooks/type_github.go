@@ -22,6 +22,29 @@ type GithubWebHookEvent struct {
 	Repository *GithubRepository `json:"repository"`
 	Pusher     GithubOwner       `json:"pusher"`
 	Sender     GithubSender      `json:"sender"`
+	// Pull request events
+	Action      string             `json:"action"`
+	Number      int                `json:"number"`
+	PullRequest *GithubPullRequest `json:"pull_request"`
+}
+
+// GithubPullRequest represents the pull request of a pull_request event
+type GithubPullRequest struct {
+	Number         int                  `json:"number"`
+	State          string               `json:"state"`
+	Title          string               `json:"title"`
+	Merged         bool                 `json:"merged"`
+	MergeCommitSha string               `json:"merge_commit_sha"`
+	User           GithubSender         `json:"user"`
+	Head           GithubPullRequestRef `json:"head"`
+	Base           GithubPullRequestRef `json:"base"`
+}
+
+// GithubPullRequestRef represents the head or the base of a pull request
+type GithubPullRequestRef struct {
+	Ref  string            `json:"ref"`
+	Sha  string            `json:"sha"`
+	Repo *GithubRepository `json:"repo"`
 }
 
 type GithubSender struct {
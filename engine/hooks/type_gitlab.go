@@ -23,6 +23,31 @@ type GitlabEvent struct {
 	Repository        *GitlabRepository `json:"repository"`
 	Commits           []GitlabCommit    `json:"commits"`
 	TotalCommitsCount int               `json:"total_commits_count"`
+	// Merge request events
+	User             *GitlabUser         `json:"user"`
+	ObjectAttributes *GitlabMergeRequest `json:"object_attributes"`
+}
+
+type GitlabUser struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// GitlabMergeRequest represents the object attributes of a merge request event
+type GitlabMergeRequest struct {
+	IID            int            `json:"iid"`
+	Title          string         `json:"title"`
+	State          string         `json:"state"`
+	Action         string         `json:"action"`
+	SourceBranch   string         `json:"source_branch"`
+	TargetBranch   string         `json:"target_branch"`
+	MergeCommitSha string         `json:"merge_commit_sha"`
+	Source         *GitlabProject `json:"source"`
+	LastCommit     struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	} `json:"last_commit"`
 }
 
 type GitlabCommit struct {
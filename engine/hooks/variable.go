@@ -2,12 +2,16 @@ package hooks
 
 const (
 	PR_ID              = "git.pr.id"
+	PR_ACTION          = "git.pr.action"
 	PR_TITLE           = "git.pr.title"
 	PR_STATE           = "git.pr.state"
 	PR_PREVIOUS_TITLE  = "git.pr.previous.title"
 	PR_PREVIOUS_BRANCH = "git.pr.previous.branch"
 	PR_PREVIOUS_HASH   = "git.pr.previous.hash"
 	PR_PREVIOUS_STATE  = "git.pr.previous.state"
+	PR_HEAD_BRANCH     = "git.pr.head.branch"
+	PR_HEAD_HASH       = "git.pr.head.hash"
+	PR_HEAD_REPOSITORY = "git.pr.head.repository"
 
 	PR_REVIEWER        = "git.pr.reviewer"
 	PR_REVIEWER_EMAIL  = "git.pr.reviewer.email"
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/docker/docker/pkg/reexec"
 	"github.com/spf13/cobra"
 	_ "github.com/spf13/viper/remote"
 
@@ -23,6 +24,10 @@ func init() {
 }
 
 func main() {
+	// The local hatchery re-executes the engine binary to init worker sandboxes
+	if reexec.Init() {
+		return
+	}
 	if err := mainCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
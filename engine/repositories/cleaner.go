@@ -30,6 +30,9 @@ func (s *Service) vacuumCleaner(ctx context.Context) error {
 			if err := s.vacuumStoreCleanerRun(ctx); err != nil {
 				log.Error(ctx, "vacuumCleaner> Error cleaning the store: %v", err)
 			}
+			if err := s.vacuumMirrorsCleanerRun(ctx); err != nil {
+				log.Error(ctx, "vacuumCleaner> Error cleaning the mirrors: %v", err)
+			}
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/cgi"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// mirrorRefreshes contains the ids of the mirrors being refreshed by this instance
+var mirrorRefreshes = struct {
+	sync.Mutex
+	ids map[string]struct{}
+}{ids: make(map[string]struct{})}
+
+func (s *Service) mirrorPath(id string) string {
+	return filepath.Join(s.Cfg.MirrorsBasedir, id)
+}
+
+func (s *Service) mirrorExists(id string) bool {
+	fi, err := os.Stat(s.mirrorPath(id))
+	return err == nil && fi.IsDir()
+}
+
+// refreshMirror creates or updates the bare mirror of a repository, only branches and tags are mirrored.
+func (s *Service) refreshMirror(ctx context.Context, m sdk.RepositoryMirror) error {
+	id := sdk.RepositoryMirrorID(m.ProjectKey, m.URL)
+
+	mirrorRefreshes.Lock()
+	if _, has := mirrorRefreshes.ids[id]; has {
+		mirrorRefreshes.Unlock()
+		log.Debug(ctx, "refreshMirror> mirror of %s is already being refreshed", m.URL)
+		return nil
+	}
+	mirrorRefreshes.ids[id] = struct{}{}
+	mirrorRefreshes.Unlock()
+	defer func() {
+		mirrorRefreshes.Lock()
+		delete(mirrorRefreshes.ids, id)
+		mirrorRefreshes.Unlock()
+	}()
+
+	dir := s.mirrorPath(id)
+	if !s.mirrorExists(id) {
+		log.Info(ctx, "refreshMirror> creating mirror of %s into %s", m.URL, dir)
+		if err := os.MkdirAll(dir, os.FileMode(0700)); err != nil {
+			return sdk.WrapError(err, "unable to create directory %q", dir)
+		}
+		if err := runMirrorGitCommand(ctx, dir, nil, "init", "--bare"); err != nil {
+			_ = os.RemoveAll(dir)
+			return err
+		}
+		// Give a clean fetch url to the mirror, credentials are only given on fetch
+		if err := runMirrorGitCommand(ctx, dir, nil, "config", "remote.origin.url", m.URL); err != nil {
+			_ = os.RemoveAll(dir)
+			return err
+		}
+	}
+
+	fetchURL := m.URL
+	var env []string
+	if m.RepositoryStrategy.ConnectionType == "ssh" {
+		keyFile, err := ioutil.TempFile("", "cds-mirror-key-")
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		defer os.Remove(keyFile.Name()) // nolint
+		if _, err := keyFile.WriteString(m.RepositoryStrategy.SSHKeyContent); err != nil {
+			keyFile.Close() // nolint
+			return sdk.WithStack(err)
+		}
+		if err := keyFile.Close(); err != nil {
+			return sdk.WithStack(err)
+		}
+		env = append(env, "GIT_SSH_COMMAND=ssh -F /dev/null -o IdentitiesOnly=yes -o StrictHostKeyChecking=no -i "+keyFile.Name())
+	} else if m.RepositoryStrategy.User != "" && m.RepositoryStrategy.Password != "" {
+		u, err := url.Parse(m.URL)
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		u.User = url.UserPassword(m.RepositoryStrategy.User, m.RepositoryStrategy.Password)
+		fetchURL = u.String()
+	}
+
+	t0 := time.Now()
+	if err := runMirrorGitCommand(ctx, dir, env, "fetch", "--prune", "--force", "--tags", fetchURL,
+		"+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"); err != nil {
+		return sdk.WrapError(err, "unable to fetch %s", m.URL)
+	}
+	log.Info(ctx, "refreshMirror> mirror of %s refreshed in %v", m.URL, time.Since(t0))
+
+	// The modification time of the mirror directory is used to remove mirrors that are no more refreshed
+	now := time.Now()
+	return sdk.WithStack(os.Chtimes(dir, now, now))
+}
+
+var mirrorIDRegexp = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+var urlCredentialsRegexp = regexp.MustCompile(`://[^/@\s]+@`)
+
+func runMirrorGitCommand(ctx context.Context, dir string, env []string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = append(cmd.Env, env...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		// Do not leak credentials given in the fetch url
+		msg := urlCredentialsRegexp.ReplaceAllString(strings.TrimSpace(string(out)), "://")
+		return sdk.NewErrorFrom(sdk.ErrUnknownError, "git %s failed: %s", args[0], msg)
+	}
+	return nil
+}
+
+// serveMirror serves the git smart http protocol of a mirror, only fetching is allowed.
+func (s *Service) serveMirror(w http.ResponseWriter, r *http.Request) error {
+	if r.URL.Query().Get("service") == "git-receive-pack" {
+		return sdk.NewErrorFrom(sdk.ErrForbidden, "mirrors are read only")
+	}
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	root, err := filepath.Abs(s.Cfg.MirrorsBasedir)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	h := &cgi.Handler{
+		Path: gitPath,
+		Args: []string{"http-backend"},
+		Root: "/mirrors",
+		Env: []string{
+			"GIT_PROJECT_ROOT=" + root,
+			"GIT_HTTP_EXPORT_ALL=1",
+		},
+	}
+	h.ServeHTTP(w, r)
+	return nil
+}
+
+func (s *Service) vacuumMirrorsCleanerRun(ctx context.Context) error {
+	if s.Cfg.MirrorsBasedir == "" {
+		return nil
+	}
+	fis, err := ioutil.ReadDir(s.Cfg.MirrorsBasedir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return sdk.WithStack(err)
+	}
+	for _, fi := range fis {
+		if !fi.IsDir() || time.Since(fi.ModTime()) < 24*time.Hour*time.Duration(s.Cfg.MirrorsRetention) {
+			continue
+		}
+		log.Info(ctx, "vacuumMirrorsCleanerRun> removing mirror %s", fi.Name())
+		if err := os.RemoveAll(filepath.Join(s.Cfg.MirrorsBasedir, fi.Name())); err != nil {
+			log.Error(ctx, "vacuumMirrorsCleanerRun> unable to remove mirror %s: %v", fi.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_refreshAndServeMirror(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mirror")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	origin := filepath.Join(dir, "origin")
+	require.NoError(t, os.MkdirAll(origin, os.ModePerm))
+	git := func(wd string, args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = wd
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@localhost", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@localhost")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	git(origin, "init")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(origin, "README.md"), []byte("hello"), os.ModePerm))
+	git(origin, "add", ".")
+	git(origin, "commit", "-m", "init")
+	git(origin, "tag", "v1.0.0")
+	head := git(origin, "rev-parse", "HEAD")
+
+	s := &Service{}
+	s.Cfg.MirrorsBasedir = filepath.Join(dir, "mirrors")
+	m := sdk.RepositoryMirror{ProjectKey: "PROJ", URL: origin}
+	require.NoError(t, s.refreshMirror(context.TODO(), m))
+	id := sdk.RepositoryMirrorID("PROJ", origin)
+	require.True(t, s.mirrorExists(id))
+	require.Equal(t, head, git(s.mirrorPath(id), "rev-parse", "v1.0.0^{commit}"))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, s.serveMirror(w, r))
+	}))
+	defer srv.Close()
+
+	git(dir, "clone", srv.URL+"/mirrors/"+id, "clone")
+	require.Equal(t, head, git(filepath.Join(dir, "clone"), "rev-parse", "HEAD"))
+
+	// New commits are fetched on refresh
+	require.NoError(t, ioutil.WriteFile(filepath.Join(origin, "README.md"), []byte("hello world"), os.ModePerm))
+	git(origin, "commit", "-am", "update")
+	head = git(origin, "rev-parse", "HEAD")
+	require.NoError(t, s.refreshMirror(context.TODO(), m))
+	git(filepath.Join(dir, "clone"), "pull")
+	require.Equal(t, head, git(filepath.Join(dir, "clone"), "rev-parse", "HEAD"))
+}
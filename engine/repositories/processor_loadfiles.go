@@ -3,8 +3,12 @@ package repositories
 import (
 	"context"
 	"io/ioutil"
+	"os/exec"
+	"regexp"
+	"strings"
 
 	repo "github.com/fsamin/go-repo"
+	"github.com/rockbears/log"
 
 	"github.com/ovh/cds/sdk"
 )
@@ -17,6 +21,19 @@ func (s *Service) processLoadFiles(ctx context.Context, op *sdk.Operation) error
 		return sdk.WithStack(err)
 	}
 
+	// Files of a workflow root are loaded only if something changed under the root on the default branch
+	if op.LoadFiles.Root != "" && op.LoadFiles.ChangedSince != "" && op.RepositoryInfo != nil &&
+		op.Setup.Checkout.Tag == "" && op.Setup.Checkout.Branch == op.RepositoryInfo.DefaultBranch {
+		changed, err := rootChangedSince(ctx, r.Basedir, op.LoadFiles.Root, op.LoadFiles.ChangedSince)
+		if err != nil {
+			log.Warn(ctx, "processLoadFiles> %s > unable to check changes under %s since %s: %v", op.UUID, op.LoadFiles.Root, op.LoadFiles.ChangedSince, err)
+		} else if !changed {
+			log.Info(ctx, "processLoadFiles> %s > nothing changed under %s since %s", op.UUID, op.LoadFiles.Root, op.LoadFiles.ChangedSince)
+			op.LoadFiles.Unchanged = true
+			return nil
+		}
+	}
+
 	files, err := gitRepo.Glob(op.LoadFiles.Pattern)
 	if err != nil {
 		return sdk.WithStack(err)
@@ -43,3 +60,24 @@ func (s *Service) processLoadFiles(ctx context.Context, op *sdk.Operation) error
 
 	return nil
 }
+
+// commitHashRegexp matches a full sha1 or sha256 git commit hash.
+var commitHashRegexp = regexp.MustCompile(`^([a-fA-F0-9]{40}|[a-fA-F0-9]{64})$`)
+
+// rootChangedSince returns true if a file under root changed between given commit and HEAD.
+func rootChangedSince(ctx context.Context, dir, root, since string) (bool, error) {
+	if strings.Trim(since, "0") == "" {
+		return true, nil
+	}
+	// The commit comes from a webhook payload, it must not be parsed as an option by git
+	if !commitHashRegexp.MatchString(since) {
+		return true, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid git commit %q", since)
+	}
+	cmd := exec.CommandContext(ctx, "git", "diff", "--name-only", since, "HEAD", "--", root)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return true, sdk.WithStack(err)
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
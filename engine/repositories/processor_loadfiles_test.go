@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_rootChangedSince(t *testing.T) {
+	dir, err := ioutil.TempDir("", "monorepo")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@localhost", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@localhost")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	write := func(file string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(file)), os.ModePerm))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, file), []byte(file), os.ModePerm))
+	}
+
+	git("init")
+	write("services/api/.cds/api.yml")
+	write("services/front/.cds/front.yml")
+	git("add", ".")
+	git("commit", "-m", "init")
+	first := git("rev-parse", "HEAD")
+
+	write("services/front/main.go")
+	git("add", ".")
+	git("commit", "-m", "front")
+
+	changed, err := rootChangedSince(context.TODO(), dir, "services/api", first)
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	changed, err = rootChangedSince(context.TODO(), dir, "services/front", first)
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	// New branches always load files
+	changed, err = rootChangedSince(context.TODO(), dir, "services/api", "0000000000000000000000000000000000000000")
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	// Only commit hashes are given to git
+	changed, err = rootChangedSince(context.TODO(), dir, "services/api", "--output=/tmp/injected")
+	require.Error(t, err)
+	require.True(t, changed)
+}
@@ -75,25 +75,26 @@ func (s *Service) processPush(ctx context.Context, op *sdk.Operation) (globalErr
 	}()
 
 	// Erase existing cds directory for migration, if update make sure that the cds directory exists
+	cdsDir := filepath.Join(path, op.LoadFiles.Root, sdk.AsCodeDir)
 	if !op.Setup.Push.Update {
-		if _, err := os.Stat(path + "/.cds"); err == nil {
-			if err := os.RemoveAll(path + "/.cds"); err != nil {
+		if _, err := os.Stat(cdsDir); err == nil {
+			if err := os.RemoveAll(cdsDir); err != nil {
 				return sdk.WrapError(err, "error removing old .cds directory")
 			}
 		}
-		if err := os.Mkdir(filepath.Join(path, ".cds"), os.ModePerm); err != nil {
+		if err := os.MkdirAll(cdsDir, os.ModePerm); err != nil {
 			return sdk.WrapError(err, "error creating .cds directory")
 		}
 	} else {
-		if _, err := os.Stat(path + "/.cds"); err != nil {
-			if err := os.Mkdir(filepath.Join(path, ".cds"), os.ModePerm); err != nil {
+		if _, err := os.Stat(cdsDir); err != nil {
+			if err := os.MkdirAll(cdsDir, os.ModePerm); err != nil {
 				return sdk.WrapError(err, "error creating .cds directory")
 			}
 		}
 	}
 
 	for k, v := range op.LoadFiles.Results {
-		fname := filepath.Join(path, ".cds", k)
+		fname := filepath.Join(cdsDir, k)
 		log.Debug(ctx, "Creating %s", fname)
 		_ = os.Remove(fname)
 		fi, err := os.Create(fname)
@@ -109,8 +110,8 @@ func (s *Service) processPush(ctx context.Context, op *sdk.Operation) (globalErr
 			return sdk.WrapError(err, "closing file %s", fname)
 		}
 	}
-	if err := gitRepo.Add(ctx, path+"/.cds/*"); err != nil {
-		return sdk.WrapError(err, "git add file %s", path+"/.cds/*")
+	if err := gitRepo.Add(ctx, cdsDir+"/*"); err != nil {
+		return sdk.WrapError(err, "git add file %s", cdsDir+"/*")
 	}
 
 	// In case that there are no changes (ex: push changes on an existing branch that was not merged)
@@ -130,6 +130,51 @@ func (s *Service) getOperationsHandler() service.Handler {
 	}
 }
 
+func (s *Service) postMirrorHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		if s.Cfg.MirrorsBasedir == "" {
+			return sdk.NewErrorFrom(sdk.ErrNotImplemented, "mirrors are disabled on this repositories service")
+		}
+		var m sdk.RepositoryMirror
+		if err := service.UnmarshalBody(r, &m); err != nil {
+			return err
+		}
+		if m.ProjectKey == "" || m.URL == "" {
+			return sdk.NewErrorFrom(sdk.ErrWrongRequest, "missing project key or repository url")
+		}
+
+		id := sdk.RepositoryMirrorID(m.ProjectKey, m.URL)
+		s.GoRoutines.Exec(context.Background(), "refreshMirror-"+id, func(ctx context.Context) {
+			if err := s.refreshMirror(ctx, m); err != nil {
+				ctx = sdk.ContextWithStacktrace(ctx, err)
+				log.Error(ctx, "unable to refresh mirror of %s: %v", m.URL, err)
+			}
+		})
+
+		return service.WriteJSON(w, sdk.GitMirror{ID: id}, http.StatusAccepted)
+	}
+}
+
+func (s *Service) getMirrorHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := muxVar(r, "id")
+		if s.Cfg.MirrorsBasedir == "" || !mirrorIDRegexp.MatchString(id) || !s.mirrorExists(id) {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		return service.WriteJSON(w, sdk.GitMirror{ID: id}, http.StatusOK)
+	}
+}
+
+func (s *Service) getMirrorGitHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id := muxVar(r, "id")
+		if s.Cfg.MirrorsBasedir == "" || !mirrorIDRegexp.MatchString(id) || !s.mirrorExists(id) {
+			return sdk.WithStack(sdk.ErrNotFound)
+		}
+		return s.serveMirror(w, r)
+	}
+}
+
 // Status returns sdk.MonitoringStatus, implements interface service.Service
 func (s *Service) Status(ctx context.Context) *sdk.MonitoringStatus {
 	m := s.NewMonitoringStatus()
@@ -22,4 +22,9 @@ func (s *Service) initRouter(ctx context.Context) {
 
 	r.Handle("/operations", nil, r.POST(s.postOperationHandler))
 	r.Handle("/operations/{uuid}", nil, r.GET(s.getOperationsHandler))
+
+	r.Handle("/mirrors", nil, r.POST(s.postMirrorHandler))
+	r.Handle("/mirrors/{id}", nil, r.GET(s.getMirrorHandler))
+	r.Handle("/mirrors/{id}/info/refs", nil, r.GET(s.getMirrorGitHandler))
+	r.Handle("/mirrors/{id}/git-upload-pack", nil, r.POST(s.getMirrorGitHandler))
 }
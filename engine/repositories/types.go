@@ -25,6 +25,8 @@ type Configuration struct {
 	Basedir               string                          `toml:"basedir" comment:"Root directory where the service will store all checked-out repositories" json:"basedir"`
 	OperationRetention    int                             `toml:"operationRetention" comment:"Operation retention in redis store (in days)" default:"5" json:"operationRetention"`
 	RepositoriesRetention int                             `toml:"repositoriesRetention" comment:"Re retention on the filesystem (in days)" default:"10" json:"repositoriesRetention"`
+	MirrorsBasedir        string                          `toml:"mirrorsBasedir" comment:"Root directory where the service will store bare mirrors of the application repositories, workers clone from these mirrors.\n Leave empty to disable mirrors" json:"mirrorsBasedir"`
+	MirrorsRetention      int                             `toml:"mirrorsRetention" comment:"Mirrors not refreshed during this number of days are removed" default:"30" json:"mirrorsRetention"`
 	HTTP                  service.HTTPRouterConfiguration `toml:"http" comment:"######################\n CDS Repositories HTTP Configuration \n######################" json:"http"`
 	URL                   string                          `default:"http://localhost:8085" json:"url"`
 	API                   service.APIServiceConfiguration `toml:"api" comment:"######################\n CDS API Settings \n######################" json:"api"`
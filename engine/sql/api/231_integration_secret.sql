@@ -0,0 +1,22 @@
+-- +migrate Up
+ALTER TABLE "integration_model" ADD COLUMN IF NOT EXISTS secret boolean DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS secret_reference_audit (
+  id BIGSERIAL PRIMARY KEY,
+  triggered_by VARCHAR(100),
+  created TIMESTAMP WITH TIME ZONE,
+  event_type VARCHAR(100),
+  project_id BIGINT,
+  project_integration_id BIGINT,
+  workflow_run_id BIGINT,
+  workflow_node_run_job_id BIGINT,
+  variable_name VARCHAR(256),
+  path TEXT
+);
+
+SELECT create_foreign_key_idx_cascade('FK_SECRET_REFERENCE_AUDIT_PROJECT', 'secret_reference_audit', 'project', 'project_id', 'id');
+SELECT create_index('secret_reference_audit', 'IDX_SECRET_REFERENCE_AUDIT_INTEGRATION', 'project_integration_id,created');
+
+-- +migrate Down
+DROP TABLE IF EXISTS secret_reference_audit;
+ALTER TABLE "integration_model" DROP COLUMN IF EXISTS secret;
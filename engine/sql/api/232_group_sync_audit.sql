@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS group_sync_audit (
+  id BIGSERIAL PRIMARY KEY,
+  triggered_by VARCHAR(100),
+  created TIMESTAMP WITH TIME ZONE,
+  event_type VARCHAR(100),
+  group_id BIGINT,
+  group_name VARCHAR(256),
+  authentified_user_id VARCHAR(36),
+  username VARCHAR(256),
+  consumer_type VARCHAR(64),
+  external_groups TEXT
+);
+
+SELECT create_foreign_key_idx_cascade('FK_GROUP_SYNC_AUDIT_GROUP', 'group_sync_audit', 'group', 'group_id', 'id');
+SELECT create_index('group_sync_audit', 'IDX_GROUP_SYNC_AUDIT_GROUP_CREATED', 'group_id,created');
+
+-- +migrate Down
+DROP TABLE IF EXISTS group_sync_audit;
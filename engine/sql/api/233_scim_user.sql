@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS scim_user (
+  authentified_user_id VARCHAR(36) PRIMARY KEY,
+  external_id VARCHAR(256),
+  active BOOLEAN DEFAULT true,
+  created TIMESTAMP WITH TIME ZONE,
+  last_modified TIMESTAMP WITH TIME ZONE,
+  sig BYTEA,
+  signer TEXT
+);
+
+SELECT create_foreign_key_idx_cascade('FK_SCIM_USER_AUTHENTIFIED_USER', 'scim_user', 'authentified_user', 'authentified_user_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS scim_user;
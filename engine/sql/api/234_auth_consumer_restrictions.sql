@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE "auth_consumer" ADD COLUMN resource_restrictions JSONB;
+ALTER TABLE "auth_consumer" ADD COLUMN allowed_cidrs JSONB;
+
+-- +migrate Down
+ALTER TABLE "auth_consumer" DROP COLUMN resource_restrictions;
+ALTER TABLE "auth_consumer" DROP COLUMN allowed_cidrs;
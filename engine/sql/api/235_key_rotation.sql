@@ -0,0 +1,11 @@
+-- +migrate Up
+ALTER TABLE "project_key" ADD COLUMN successor VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE "project_key" ADD COLUMN retire_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "application_key" ADD COLUMN successor VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE "application_key" ADD COLUMN retire_at TIMESTAMP WITH TIME ZONE;
+
+-- +migrate Down
+ALTER TABLE "project_key" DROP COLUMN successor;
+ALTER TABLE "project_key" DROP COLUMN retire_at;
+ALTER TABLE "application_key" DROP COLUMN successor;
+ALTER TABLE "application_key" DROP COLUMN retire_at;
@@ -0,0 +1,39 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS audit_log (
+  id BIGSERIAL PRIMARY KEY,
+  created TIMESTAMP WITH TIME ZONE NOT NULL,
+  request_id VARCHAR(64) NOT NULL DEFAULT '',
+  auth_consumer_id VARCHAR(36) NOT NULL DEFAULT '',
+  auth_consumer_name VARCHAR(256) NOT NULL DEFAULT '',
+  authentified_user_id VARCHAR(36) NOT NULL DEFAULT '',
+  username VARCHAR(256) NOT NULL DEFAULT '',
+  ip_address TEXT NOT NULL DEFAULT '',
+  method VARCHAR(16) NOT NULL,
+  route TEXT NOT NULL,
+  handler TEXT NOT NULL DEFAULT '',
+  target JSONB,
+  status INT NOT NULL,
+  previous_hash VARCHAR(64) NOT NULL DEFAULT '',
+  hash VARCHAR(64) NOT NULL
+);
+
+SELECT create_index('audit_log', 'IDX_AUDIT_LOG_CREATED', 'created');
+SELECT create_index('audit_log', 'IDX_AUDIT_LOG_USERNAME', 'username');
+SELECT create_index('audit_log', 'IDX_AUDIT_LOG_CONSUMER', 'auth_consumer_id');
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS TRIGGER AS $$
+    BEGIN
+        RAISE EXCEPTION 'audit_log is append-only';
+    END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+CREATE TRIGGER "audit_log_append_only" BEFORE UPDATE OR DELETE ON "audit_log" FOR EACH ROW EXECUTE PROCEDURE audit_log_append_only();
+CREATE TRIGGER "audit_log_no_truncate" BEFORE TRUNCATE ON "audit_log" FOR EACH STATEMENT EXECUTE PROCEDURE audit_log_append_only();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS "audit_log_no_truncate" ON "audit_log";
+DROP TRIGGER IF EXISTS "audit_log_append_only" ON "audit_log";
+DROP FUNCTION IF EXISTS audit_log_append_only();
+DROP TABLE IF EXISTS audit_log;
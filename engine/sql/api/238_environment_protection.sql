@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE "environment" ADD COLUMN protection JSONB;
+ALTER TABLE "workflow_node_run" ADD COLUMN protection_violation TEXT;
+
+-- +migrate Down
+ALTER TABLE "environment" DROP COLUMN protection;
+ALTER TABLE "workflow_node_run" DROP COLUMN protection_violation;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE "workflow_notification" ADD COLUMN integration VARCHAR(256) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE "workflow_notification" DROP COLUMN integration;
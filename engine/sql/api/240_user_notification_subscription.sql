@@ -0,0 +1,39 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS user_notification_subscription (
+  id BIGSERIAL PRIMARY KEY,
+  authentified_user_id VARCHAR(36) NOT NULL,
+  created TIMESTAMP WITH TIME ZONE,
+  project_key VARCHAR(256) NOT NULL DEFAULT '',
+  workflow_name VARCHAR(256) NOT NULL DEFAULT '',
+  branch VARCHAR(256) NOT NULL DEFAULT '',
+  statuses JSONB,
+  triggered_by_me BOOLEAN NOT NULL DEFAULT false,
+  channel VARCHAR(64) NOT NULL,
+  chat_type VARCHAR(64) NOT NULL DEFAULT '',
+  cipher_webhook_url BYTEA,
+  mode VARCHAR(64) NOT NULL,
+  last_digest TIMESTAMP WITH TIME ZONE,
+  sig BYTEA,
+  signer TEXT
+);
+
+SELECT create_foreign_key_idx_cascade('FK_USER_NOTIFICATION_SUBSCRIPTION_AUTHENTIFIED_USER', 'user_notification_subscription', 'authentified_user', 'authentified_user_id', 'id');
+
+CREATE TABLE IF NOT EXISTS user_notification_event (
+  id BIGSERIAL PRIMARY KEY,
+  subscription_id BIGINT NOT NULL,
+  created TIMESTAMP WITH TIME ZONE,
+  project_key VARCHAR(256),
+  workflow_name VARCHAR(256),
+  run_number BIGINT,
+  status VARCHAR(64),
+  branch VARCHAR(256),
+  commit VARCHAR(256),
+  triggered_by VARCHAR(256)
+);
+
+SELECT create_foreign_key_idx_cascade('FK_USER_NOTIFICATION_EVENT_SUBSCRIPTION', 'user_notification_event', 'user_notification_subscription', 'subscription_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS user_notification_event;
+DROP TABLE IF EXISTS user_notification_subscription;
@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS workflow_template_release (
+  id BIGSERIAL PRIMARY KEY,
+  workflow_template_id BIGINT NOT NULL,
+  version VARCHAR(64) NOT NULL,
+  template_version BIGINT NOT NULL,
+  changelog TEXT,
+  author VARCHAR(256),
+  created TIMESTAMP WITH TIME ZONE,
+  template JSONB
+);
+
+SELECT create_unique_index('workflow_template_release', 'IDX_WORKFLOW_TEMPLATE_RELEASE_VERSION', 'workflow_template_id,version');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_TEMPLATE_RELEASE_TEMPLATE', 'workflow_template_release', 'workflow_template', 'workflow_template_id', 'id');
+
+ALTER TABLE workflow_template_instance ADD COLUMN IF NOT EXISTS version_range VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE workflow_template_instance ADD COLUMN IF NOT EXISTS workflow_template_release VARCHAR(64) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE workflow_template_instance DROP COLUMN IF EXISTS workflow_template_release;
+ALTER TABLE workflow_template_instance DROP COLUMN IF EXISTS version_range;
+DROP TABLE IF EXISTS workflow_template_release;
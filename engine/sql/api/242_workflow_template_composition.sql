@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE workflow_template ADD COLUMN IF NOT EXISTS extends VARCHAR(256) NOT NULL DEFAULT '';
+ALTER TABLE workflow_template ADD COLUMN IF NOT EXISTS imports JSONB;
+ALTER TABLE workflow_template ADD COLUMN IF NOT EXISTS blocks TEXT NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE workflow_template DROP COLUMN IF EXISTS blocks;
+ALTER TABLE workflow_template DROP COLUMN IF EXISTS imports;
+ALTER TABLE workflow_template DROP COLUMN IF EXISTS extends;
@@ -0,0 +1,22 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS preview_environment (
+  id BIGSERIAL PRIMARY KEY,
+  application_id BIGINT NOT NULL,
+  workflow_id BIGINT NOT NULL,
+  environment_id BIGINT NOT NULL,
+  pull_request_id BIGINT NOT NULL,
+  branch VARCHAR(256) NOT NULL DEFAULT '',
+  hash VARCHAR(256) NOT NULL DEFAULT '',
+  status VARCHAR(64) NOT NULL,
+  workflow_run_number BIGINT NOT NULL DEFAULT 0,
+  created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+  last_modified TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+
+SELECT create_foreign_key_idx_cascade('FK_PREVIEW_ENVIRONMENT_APPLICATION', 'preview_environment', 'application', 'application_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_PREVIEW_ENVIRONMENT_WORKFLOW', 'preview_environment', 'workflow', 'workflow_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_PREVIEW_ENVIRONMENT_ENVIRONMENT', 'preview_environment', 'environment', 'environment_id', 'id');
+SELECT create_unique_index('preview_environment', 'IDX_PREVIEW_ENVIRONMENT_UNIQ', 'workflow_id,application_id,pull_request_id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS preview_environment;
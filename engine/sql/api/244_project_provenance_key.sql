@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS project_provenance_key (
+  id BIGSERIAL PRIMARY KEY,
+  project_id BIGINT NOT NULL,
+  name TEXT NOT NULL,
+  public TEXT,
+  private BYTEA,
+  created TIMESTAMP WITH TIME ZONE,
+  sig BYTEA,
+  signer TEXT
+);
+
+SELECT create_foreign_key_idx_cascade('FK_PROJECT_PROVENANCE_KEY_PROJECT', 'project_provenance_key', 'project', 'project_id', 'id');
+SELECT create_unique_index('project_provenance_key', 'IDX_PROJECT_PROVENANCE_KEY_PROJECT_ID', 'project_id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS project_provenance_key;
@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS auth_consumer_refresh_token (
+  consumer_id VARCHAR(36) PRIMARY KEY,
+  cipher_refresh_token BYTEA,
+  updated TIMESTAMP WITH TIME ZONE,
+  sig BYTEA,
+  signer TEXT
+);
+
+SELECT create_foreign_key_idx_cascade('FK_AUTH_CONSUMER_REFRESH_TOKEN_CONSUMER', 'auth_consumer_refresh_token', 'auth_consumer', 'consumer_id', 'id');
+
+-- +migrate Down
+DROP TABLE IF EXISTS auth_consumer_refresh_token;
@@ -0,0 +1,114 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Reports limits
+const (
+	reportDetailsMaxLength    = 2000
+	reportAnnotationsMaxBatch = 100
+)
+
+// SetInsightReport creates or replaces the report and its annotations on a commit
+// doc from https://support.atlassian.com/bitbucket-cloud/docs/code-insights/
+func (client *bitbucketcloudClient) SetInsightReport(ctx context.Context, repo string, report sdk.VCSInsightReport) error {
+	if client.DisableStatus {
+		log.Warn(ctx, "bitbucketcloud.SetInsightReport>  ⚠ bitbucketcloud statuses are disabled")
+		return nil
+	}
+
+	path := fmt.Sprintf("/repositories/%s/commit/%s/reports/%s", repo, report.Revision, report.Key)
+
+	// deleting the report also deletes the annotations of a previous report with the same key
+	if err := client.delete(path); err != nil {
+		log.Debug(ctx, "bitbucketcloud.SetInsightReport> cannot delete previous report %s: %v", report.Key, err)
+	}
+
+	values, err := json.Marshal(newReport(report))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	var res Report
+	if err := client.do(ctx, "PUT", "core", path, nil, values, &res); err != nil {
+		return sdk.WrapError(err, "unable to put report %s", report.Key)
+	}
+
+	annotations := newReportAnnotations(report)
+	for i := 0; i < len(annotations); i += reportAnnotationsMaxBatch {
+		end := i + reportAnnotationsMaxBatch
+		if end > len(annotations) {
+			end = len(annotations)
+		}
+		values, err := json.Marshal(annotations[i:end])
+		if err != nil {
+			return sdk.WithStack(err)
+		}
+		var res []ReportAnnotation
+		if err := client.do(ctx, "POST", "core", path+"/annotations", nil, values, &res); err != nil {
+			return sdk.WrapError(err, "unable to post annotations of report %s", report.Key)
+		}
+	}
+	return nil
+}
+
+func newReport(report sdk.VCSInsightReport) Report {
+	r := Report{
+		Title:      report.Title,
+		Details:    report.Summary(),
+		ReportType: "TEST",
+		Reporter:   report.Reporter,
+		Link:       report.Link,
+		Result:     "PASSED",
+	}
+	if len(r.Details) > reportDetailsMaxLength {
+		r.Details = r.Details[:reportDetailsMaxLength-3] + "..."
+	}
+	if report.Result == sdk.VCSInsightReportResultFail {
+		r.Result = "FAILED"
+	}
+	if report.Tests != nil {
+		r.Data = append(r.Data,
+			ReportData{Title: "Tests passed", Type: "NUMBER", Value: report.Tests.OK},
+			ReportData{Title: "Tests failed", Type: "NUMBER", Value: report.Tests.KO},
+			ReportData{Title: "Tests skipped", Type: "NUMBER", Value: report.Tests.Skipped},
+		)
+	}
+	if report.Coverage != nil {
+		r.Data = append(r.Data, ReportData{Title: "Coverage", Type: "PERCENTAGE", Value: report.Coverage.Percent()})
+	}
+	if len(report.Vulnerabilities) > 0 {
+		var total int64
+		for _, v := range report.Vulnerabilities {
+			total += v
+		}
+		r.Data = append(r.Data, ReportData{Title: "Vulnerabilities", Type: "NUMBER", Value: total})
+	}
+	return r
+}
+
+func newReportAnnotations(report sdk.VCSInsightReport) []ReportAnnotation {
+	as := make([]ReportAnnotation, 0, len(report.Annotations))
+	for i, a := range report.Annotations {
+		if i >= sdk.VCSInsightAnnotationsMax {
+			break
+		}
+		as = append(as, ReportAnnotation{
+			ExternalID:     fmt.Sprintf("%s-%d", report.Key, i),
+			AnnotationType: a.Type,
+			Summary:        a.Title,
+			Details:        a.Message,
+			Severity:       a.Severity,
+			Path:           a.Path,
+			Line:           a.Line,
+			Link:           a.Link,
+		})
+	}
+	return as
+}
@@ -0,0 +1,134 @@
+package bitbucketcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// redirectTransport sends the requests made to the Bitbucket Cloud API to a test server
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type fakeBitbucketReports struct {
+	mutex       sync.Mutex
+	calls       []string
+	report      Report
+	annotations [][]ReportAnnotation
+}
+
+func (f *fakeBitbucketReports) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.calls = append(f.calls, r.Method+" "+r.URL.Path)
+	switch r.Method {
+	case http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case http.MethodPut:
+		if err := json.NewDecoder(r.Body).Decode(&f.report); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.report)
+	case http.MethodPost:
+		var as []ReportAnnotation
+		if err := json.NewDecoder(r.Body).Decode(&as); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.annotations = append(f.annotations, as)
+		_ = json.NewEncoder(w).Encode(as)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestSetInsightReport(t *testing.T) {
+	fake := &fakeBitbucketReports{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	target, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	previousClient := httpClient
+	httpClient = &http.Client{Transport: redirectTransport{target: target}}
+	defer func() { httpClient = previousClient }()
+
+	report := sdk.VCSInsightReport{
+		Key:      "cds.PROJ.my-workflow.build",
+		Title:    "Tests",
+		Reporter: "CDS",
+		Link:     "https://cds.example.com/run/1",
+		Result:   sdk.VCSInsightReportResultFail,
+		Revision: "9f4fac7ec5642099982a86f584f2c4a362adb670",
+		Tests:    &sdk.VCSInsightTests{Total: 3, OK: 2, KO: 1},
+	}
+	for i := 0; i < reportAnnotationsMaxBatch+1; i++ {
+		report.Annotations = append(report.Annotations, sdk.VCSInsightAnnotation{
+			Severity: sdk.VCSInsightAnnotationSeverityHigh,
+			Type:     sdk.VCSInsightAnnotationTypeBug,
+			Title:    fmt.Sprintf("Test%d", i),
+		})
+	}
+
+	client := &bitbucketcloudClient{OAuthToken: "token"}
+	require.NoError(t, client.SetInsightReport(context.TODO(), "team/repo", report))
+
+	path := "/2.0/repositories/team/repo/commit/9f4fac7ec5642099982a86f584f2c4a362adb670/reports/cds.PROJ.my-workflow.build"
+	require.Equal(t, []string{
+		"DELETE " + path,
+		"PUT " + path,
+		"POST " + path + "/annotations",
+		"POST " + path + "/annotations",
+	}, fake.calls, "previous report should be deleted and annotations sent by batch")
+	assert.Equal(t, "FAILED", fake.report.Result)
+	require.Len(t, fake.annotations, 2)
+	assert.Len(t, fake.annotations[0], reportAnnotationsMaxBatch)
+	assert.Len(t, fake.annotations[1], 1)
+	assert.Equal(t, "cds.PROJ.my-workflow.build-100", fake.annotations[1][0].ExternalID)
+
+	// Nothing is sent when statuses are disabled
+	fake.calls = nil
+	client.DisableStatus = true
+	require.NoError(t, client.SetInsightReport(context.TODO(), "team/repo", report))
+	assert.Empty(t, fake.calls)
+}
+
+func TestNewReport(t *testing.T) {
+	r := newReport(sdk.VCSInsightReport{
+		Title:           "Tests",
+		Result:          sdk.VCSInsightReportResultPass,
+		Tests:           &sdk.VCSInsightTests{Total: 4, OK: 2, KO: 1, Skipped: 1},
+		Coverage:        &sdk.VCSInsightCoverage{TotalLines: 4, CoveredLines: 3},
+		Vulnerabilities: map[string]int64{"high": 1, "low": 2},
+	})
+	assert.Equal(t, "PASSED", r.Result)
+	assert.Equal(t, "TEST", r.ReportType)
+	assert.Equal(t, "Tests: 2 passed, 1 failed, 1 skipped - Coverage: 75.0% (3/4 lines) - Vulnerabilities: 1 high, 2 low", r.Details)
+	assert.Equal(t, []ReportData{
+		{Title: "Tests passed", Type: "NUMBER", Value: 2},
+		{Title: "Tests failed", Type: "NUMBER", Value: 1},
+		{Title: "Tests skipped", Type: "NUMBER", Value: 1},
+		{Title: "Coverage", Type: "PERCENTAGE", Value: 75.0},
+		{Title: "Vulnerabilities", Type: "NUMBER", Value: int64(3)},
+	}, r.Data)
+}
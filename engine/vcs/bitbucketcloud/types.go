@@ -482,3 +482,31 @@ type Tag struct {
 		Type    string    `json:"type"`
 	} `json:"target"`
 }
+
+// Report is a code insights report on a commit
+type Report struct {
+	Title      string       `json:"title"`
+	Details    string       `json:"details,omitempty"`
+	ReportType string       `json:"report_type"`
+	Reporter   string       `json:"reporter,omitempty"`
+	Link       string       `json:"link,omitempty"`
+	Result     string       `json:"result,omitempty"`
+	Data       []ReportData `json:"data,omitempty"`
+}
+
+type ReportData struct {
+	Title string      `json:"title"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+type ReportAnnotation struct {
+	ExternalID     string `json:"external_id"`
+	AnnotationType string `json:"annotation_type"`
+	Summary        string `json:"summary"`
+	Details        string `json:"details,omitempty"`
+	Severity       string `json:"severity,omitempty"`
+	Path           string `json:"path,omitempty"`
+	Line           int    `json:"line,omitempty"`
+	Link           string `json:"link,omitempty"`
+}
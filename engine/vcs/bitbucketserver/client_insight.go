@@ -0,0 +1,117 @@
+package bitbucketserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Code Insights limits
+const (
+	insightDetailsMaxLength = 2000
+	insightMessageMaxLength = 2000
+	insightDataMax          = 6
+)
+
+// SetInsightReport creates or replaces the Code Insights report and its annotations on a commit
+func (b *bitbucketClient) SetInsightReport(ctx context.Context, repo string, report sdk.VCSInsightReport) error {
+	if b.consumer.disableStatus {
+		log.Warn(ctx, "bitbucketClient.SetInsightReport>  ⚠ Bitbucket statuses are disabled")
+		return nil
+	}
+
+	project, slug, err := getRepo(repo)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+
+	path := fmt.Sprintf("/projects/%s/repos/%s/commits/%s/reports/%s", project, slug, report.Revision, report.Key)
+	values, err := json.Marshal(newInsightReport(report))
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if err := b.do(ctx, "PUT", "insights", path, nil, values, nil, nil); err != nil {
+		return sdk.WrapError(err, "unable to put insight report %s", report.Key)
+	}
+
+	// annotations from a previous report with the same key are replaced
+	if err := b.do(ctx, "DELETE", "insights", path+"/annotations", nil, nil, nil, nil); err != nil {
+		return sdk.WrapError(err, "unable to delete annotations of insight report %s", report.Key)
+	}
+	annotations := newInsightAnnotations(report)
+	if len(annotations.Annotations) == 0 {
+		return nil
+	}
+	values, err = json.Marshal(annotations)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if err := b.do(ctx, "POST", "insights", path+"/annotations", nil, values, nil, nil); err != nil {
+		return sdk.WrapError(err, "unable to post annotations of insight report %s", report.Key)
+	}
+	return nil
+}
+
+func newInsightReport(report sdk.VCSInsightReport) InsightReport {
+	r := InsightReport{
+		Title:    report.Title,
+		Details:  truncate(report.Summary(), insightDetailsMaxLength),
+		Result:   string(report.Result),
+		Reporter: report.Reporter,
+		Link:     report.Link,
+	}
+	if report.Tests != nil {
+		r.Data = append(r.Data,
+			InsightReportData{Title: "Tests passed", Type: "NUMBER", Value: report.Tests.OK},
+			InsightReportData{Title: "Tests failed", Type: "NUMBER", Value: report.Tests.KO},
+			InsightReportData{Title: "Tests skipped", Type: "NUMBER", Value: report.Tests.Skipped},
+		)
+	}
+	if report.Coverage != nil {
+		r.Data = append(r.Data, InsightReportData{Title: "Coverage", Type: "PERCENTAGE", Value: report.Coverage.Percent()})
+	}
+	if len(report.Vulnerabilities) > 0 {
+		var total int64
+		for _, v := range report.Vulnerabilities {
+			total += v
+		}
+		r.Data = append(r.Data, InsightReportData{Title: "Vulnerabilities", Type: "NUMBER", Value: total})
+	}
+	if len(r.Data) > insightDataMax {
+		r.Data = r.Data[:insightDataMax]
+	}
+	return r
+}
+
+func newInsightAnnotations(report sdk.VCSInsightReport) InsightAnnotations {
+	as := InsightAnnotations{Annotations: []InsightAnnotation{}}
+	for i, a := range report.Annotations {
+		if i >= sdk.VCSInsightAnnotationsMax {
+			break
+		}
+		message := a.Title
+		if a.Message != "" {
+			message += ": " + a.Message
+		}
+		as.Annotations = append(as.Annotations, InsightAnnotation{
+			Path:     a.Path,
+			Line:     a.Line,
+			Message:  truncate(message, insightMessageMaxLength),
+			Severity: a.Severity,
+			Type:     a.Type,
+			Link:     a.Link,
+		})
+	}
+	return as
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
@@ -0,0 +1,41 @@
+package bitbucketserver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestNewInsightReport(t *testing.T) {
+	report := sdk.VCSInsightReport{
+		Key:             "cds.PROJ.my-workflow.build",
+		Title:           "CDS/PROJ-my-workflow-build",
+		Reporter:        "CDS",
+		Result:          sdk.VCSInsightReportResultPass,
+		Tests:           &sdk.VCSInsightTests{Total: 3, OK: 2, Skipped: 1},
+		Coverage:        &sdk.VCSInsightCoverage{TotalLines: 4, CoveredLines: 3},
+		Vulnerabilities: map[string]int64{sdk.SeverityHigh: 2, sdk.SeverityLow: 1},
+		Annotations: []sdk.VCSInsightAnnotation{{
+			Title:    "CVE-1 lodash",
+			Message:  strings.Repeat("a", 3000),
+			Severity: sdk.VCSInsightAnnotationSeverityHigh,
+			Type:     sdk.VCSInsightAnnotationTypeVulnerability,
+		}},
+	}
+
+	r := newInsightReport(report)
+	assert.Equal(t, "PASS", r.Result)
+	require.Len(t, r.Data, 5)
+	assert.Equal(t, InsightReportData{Title: "Coverage", Type: "PERCENTAGE", Value: 75.0}, r.Data[3])
+	assert.Equal(t, InsightReportData{Title: "Vulnerabilities", Type: "NUMBER", Value: int64(3)}, r.Data[4])
+
+	as := newInsightAnnotations(report)
+	require.Len(t, as.Annotations, 1)
+	assert.Len(t, as.Annotations[0].Message, insightMessageMaxLength)
+	assert.True(t, strings.HasPrefix(as.Annotations[0].Message, "CVE-1 lodash: aaa"))
+	assert.Equal(t, "VULNERABILITY", as.Annotations[0].Type)
+}
@@ -100,14 +100,6 @@ func (b *bitbucketClient) PullRequestComment(ctx context.Context, repo string, p
 		prRequest.Message = prRequest.Message[0:32750] + "\n[truncated]"
 	}
 
-	payload := map[string]string{
-		"text": prRequest.Message,
-	}
-	values, err := json.Marshal(payload)
-	if err != nil {
-		return sdk.WithStack(err)
-	}
-
 	path := fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d/comments", project, slug, prRequest.ID)
 
 	canWrite, err := b.UserHasWritePermission(ctx, repo)
@@ -119,9 +111,69 @@ func (b *bitbucketClient) PullRequestComment(ctx context.Context, repo string, p
 			return err
 		}
 	}
+
+	if prRequest.Key != "" {
+		comment, err := b.pullRequestComment(ctx, project, slug, prRequest.ID, prRequest.Key)
+		if err != nil {
+			return err
+		}
+		if comment != nil {
+			payload := map[string]interface{}{
+				"text":    prRequest.Message,
+				"version": comment.Version,
+			}
+			values, err := json.Marshal(payload)
+			if err != nil {
+				return sdk.WithStack(err)
+			}
+			return b.do(ctx, "PUT", "core", fmt.Sprintf("%s/%d", path, comment.ID), nil, values, nil, &options{asUser: true})
+		}
+	}
+
+	payload := map[string]string{
+		"text": prRequest.Message,
+	}
+	values, err := json.Marshal(payload)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
 	return b.do(ctx, "POST", "core", path, nil, values, nil, &options{asUser: true})
 }
 
+// pullRequestComment returns the first comment of a pull request that starts with given key, nil if there is none
+func (b *bitbucketClient) pullRequestComment(ctx context.Context, project, slug string, id int, key string) (*Comment, error) {
+	path := fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d/activities", project, slug, id)
+	params := url.Values{}
+	nextPage := 0
+	for {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if nextPage != 0 {
+			params.Set("start", fmt.Sprintf("%d", nextPage))
+		}
+
+		// Comments are not cached, a comment posted on a previous call must be found
+		var response PullRequestActivityResponse
+		if err := b.do(ctx, "GET", "core", path, params, nil, &response, &options{noCache: true}); err != nil {
+			return nil, sdk.WrapError(err, "unable to get pull request activities")
+		}
+
+		for _, a := range response.Values {
+			if a.Action == "COMMENTED" && a.Comment != nil && strings.HasPrefix(a.Comment.Text, key) {
+				return a.Comment, nil
+			}
+		}
+
+		if response.IsLastPage {
+			break
+		}
+		nextPage = response.NextPageStart
+	}
+	return nil, nil
+}
+
 func (b *bitbucketClient) PullRequestCreate(ctx context.Context, repo string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
 	project, slug, err := getRepo(repo)
 	if err != nil {
@@ -61,13 +61,16 @@ func (c *bitbucketClient) getFullAPIURL(api string) string {
 		url = fmt.Sprintf("%s/rest/api/1.0", c.consumer.URL)
 	case "build-status":
 		url = fmt.Sprintf("%s/rest/build-status/1.0", c.consumer.URL)
+	case "insights":
+		url = fmt.Sprintf("%s/rest/insights/1.0", c.consumer.URL)
 	}
 
 	return url
 }
 
 type options struct {
-	asUser bool
+	asUser  bool
+	noCache bool
 }
 
 func (c *bitbucketClient) do(ctx context.Context, method, api, path string, params url.Values, values []byte, v interface{}, opts *options) error {
@@ -124,7 +127,8 @@ func (c *bitbucketClient) do(ctx context.Context, method, api, path string, para
 	}
 
 	cacheKey := cache.Key("vcs", "bitbucket", "request", req.URL.String(), token.Token())
-	if v != nil && method == "GET" {
+	useCache := opts == nil || !opts.noCache
+	if v != nil && method == "GET" && useCache {
 		find, err := c.consumer.cache.Get(cacheKey, v)
 		if err != nil {
 			log.Error(ctx, "cannot get from cache %s: %v", cacheKey, err)
@@ -190,7 +194,7 @@ func (c *bitbucketClient) do(ctx context.Context, method, api, path string, para
 				return err
 			}
 		}
-		if method == "GET" {
+		if method == "GET" && useCache {
 			if err := c.consumer.cache.Set(cacheKey, v); err != nil {
 				log.Error(ctx, "unable to cache set %v: %v", cacheKey, err)
 			}
@@ -82,6 +82,36 @@ type Status struct {
 	Timestamp   int64  `json:"dateAdded"`
 }
 
+// InsightReport is a Code Insights report
+// doc from https://docs.atlassian.com/bitbucket-server/rest/latest/bitbucket-code-insights-rest.html
+type InsightReport struct {
+	Title    string              `json:"title"`
+	Details  string              `json:"details,omitempty"`
+	Result   string              `json:"result,omitempty"`
+	Reporter string              `json:"reporter,omitempty"`
+	Link     string              `json:"link,omitempty"`
+	Data     []InsightReportData `json:"data,omitempty"`
+}
+
+type InsightReportData struct {
+	Title string      `json:"title"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+type InsightAnnotations struct {
+	Annotations []InsightAnnotation `json:"annotations"`
+}
+
+type InsightAnnotation struct {
+	Path     string `json:"path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+	Type     string `json:"type,omitempty"`
+	Link     string `json:"link,omitempty"`
+}
+
 type Lines struct {
 	Text string `json:"text"`
 }
@@ -167,6 +197,25 @@ type PullRequestResponse struct {
 	IsLastPage    bool                             `json:"isLastPage"`
 }
 
+type PullRequestActivityResponse struct {
+	Values        []PullRequestActivity `json:"values"`
+	Size          int                   `json:"size"`
+	NextPageStart int                   `json:"nextPageStart"`
+	IsLastPage    bool                  `json:"isLastPage"`
+}
+
+type PullRequestActivity struct {
+	ID      int64    `json:"id"`
+	Action  string   `json:"action"`
+	Comment *Comment `json:"comment,omitempty"`
+}
+
+type Comment struct {
+	ID      int64  `json:"id"`
+	Version int    `json:"version"`
+	Text    string `json:"text"`
+}
+
 type UsersPermissionResponse struct {
 	Values        []UserPermission `json:"values"`
 	Size          int              `json:"size"`
@@ -63,6 +63,12 @@ func (c *gerritClient) ListStatuses(ctx context.Context, repo string, ref string
 	return nil, nil
 }
 
+// SetInsightReport is not supported on Gerrit, the node run report is already posted as a review message
+func (c *gerritClient) SetInsightReport(ctx context.Context, repo string, report sdk.VCSInsightReport) error {
+	log.Debug(ctx, "gerrit.SetInsightReport> insight reports are not supported: %s on %s", report.Key, repo)
+	return nil
+}
+
 func (c *gerritClient) buildMessage(eventNR sdk.EventRunWorkflowNode) string {
 	var message string
 	switch eventNR.Status {
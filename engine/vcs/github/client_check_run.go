@@ -0,0 +1,159 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/rockbears/log"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Github check runs limits
+const (
+	checkRunAnnotationsMaxBatch = 50
+	checkRunTextMaxLength       = 65535
+)
+
+// SetInsightReport creates a completed check run on the commit, annotations with a path are attached to the files,
+// others are listed in the check run text.
+// https://docs.github.com/en/rest/checks/runs
+func (g *githubClient) SetInsightReport(ctx context.Context, repo string, report sdk.VCSInsightReport) error {
+	if g.DisableStatus {
+		log.Warn(ctx, "github.SetInsightReport>  ⚠ Github statuses are disabled")
+		return nil
+	}
+
+	checkRun, annotations := newCheckRun(report)
+	batch := annotations
+	if len(batch) > checkRunAnnotationsMaxBatch {
+		batch = batch[:checkRunAnnotationsMaxBatch]
+	}
+	checkRun.Output.Annotations = batch
+
+	var created CheckRun
+	if err := g.sendCheckRun(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/check-runs", repo), checkRun, &created); err != nil {
+		// check runs can only be created with a Github App token
+		if sdk.ErrorIs(err, sdk.ErrForbidden) {
+			log.Warn(ctx, "github.SetInsightReport>  ⚠ check runs are not available with the current token: %v", err)
+			return nil
+		}
+		return err
+	}
+
+	// the API accepts at most 50 annotations by request, next ones are added by updating the check run
+	for i := checkRunAnnotationsMaxBatch; i < len(annotations); i += checkRunAnnotationsMaxBatch {
+		end := i + checkRunAnnotationsMaxBatch
+		if end > len(annotations) {
+			end = len(annotations)
+		}
+		update := CreateCheckRun{Output: &CheckRunOutput{
+			Title:       checkRun.Output.Title,
+			Summary:     checkRun.Output.Summary,
+			Annotations: annotations[i:end],
+		}}
+		if err := g.sendCheckRun(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/check-runs/%d", repo, created.ID), update, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *githubClient) sendCheckRun(ctx context.Context, method, path string, checkRun CreateCheckRun, v interface{}) error {
+	b, err := json.Marshal(checkRun)
+	if err != nil {
+		return sdk.WrapError(err, "unable to marshal github check run")
+	}
+
+	var res *http.Response
+	if method == http.MethodPatch {
+		res, err = g.patch(ctx, path, "application/json", bytes.NewBuffer(b), nil)
+	} else {
+		res, err = g.post(ctx, path, "application/json", bytes.NewBuffer(b), nil, nil)
+	}
+	if err != nil {
+		return sdk.WrapError(err, "unable to send check run")
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return sdk.WrapError(err, "unable to read body")
+	}
+	if res.StatusCode == http.StatusForbidden {
+		return sdk.NewError(sdk.ErrForbidden, errorAPI(body))
+	}
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusOK {
+		return sdk.WithStack(fmt.Errorf("unable to send check run on github. Status code : %d - Body: %s", res.StatusCode, body))
+	}
+	if v != nil {
+		return sdk.JSONUnmarshal(body, v)
+	}
+	return nil
+}
+
+func newCheckRun(report sdk.VCSInsightReport) (CreateCheckRun, []CheckRunAnnotation) {
+	checkRun := CreateCheckRun{
+		Name:       report.Title,
+		HeadSHA:    report.Revision,
+		ExternalID: report.Key,
+		DetailsURL: report.Link,
+		Status:     "completed",
+		Conclusion: "success",
+		Output: &CheckRunOutput{
+			Title:   report.Title,
+			Summary: report.Summary(),
+		},
+	}
+	if report.Result == sdk.VCSInsightReportResultFail {
+		checkRun.Conclusion = "failure"
+	}
+
+	var annotations []CheckRunAnnotation
+	var text strings.Builder
+	for i, a := range report.Annotations {
+		if i >= sdk.VCSInsightAnnotationsMax {
+			break
+		}
+		if a.Path != "" {
+			line, message := a.Line, a.Message
+			if line == 0 {
+				line = 1
+			}
+			if message == "" {
+				message = a.Title
+			}
+			annotations = append(annotations, CheckRunAnnotation{
+				Path:            a.Path,
+				StartLine:       line,
+				EndLine:         line,
+				AnnotationLevel: checkRunAnnotationLevel(a.Severity),
+				Title:           a.Title,
+				Message:         message,
+			})
+			continue
+		}
+		fmt.Fprintf(&text, "* **%s** %s\n", a.Title, strings.TrimSpace(a.Message))
+	}
+	checkRun.Output.Text = text.String()
+	if len(checkRun.Output.Text) > checkRunTextMaxLength {
+		checkRun.Output.Text = checkRun.Output.Text[:checkRunTextMaxLength-3] + "..."
+	}
+	return checkRun, annotations
+}
+
+func checkRunAnnotationLevel(severity string) string {
+	switch severity {
+	case sdk.VCSInsightAnnotationSeverityHigh:
+		return "failure"
+	case sdk.VCSInsightAnnotationSeverityMedium:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
@@ -0,0 +1,41 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_newCheckRun(t *testing.T) {
+	checkRun, annotations := newCheckRun(sdk.VCSInsightReport{
+		Key:      "cds.PROJ.my-workflow.build",
+		Title:    "CDS/PROJ-my-workflow-build",
+		Result:   sdk.VCSInsightReportResultFail,
+		Revision: "abcdef",
+		Link:     "https://cds/run/1",
+		Tests:    &sdk.VCSInsightTests{Total: 2, OK: 1, KO: 1},
+		Annotations: []sdk.VCSInsightAnnotation{
+			{Title: "suite / test", Message: "expected 1, got 2", Severity: sdk.VCSInsightAnnotationSeverityHigh},
+			{Title: "lint", Path: "main.go", Severity: sdk.VCSInsightAnnotationSeverityMedium},
+		},
+	})
+
+	assert.Equal(t, "abcdef", checkRun.HeadSHA)
+	assert.Equal(t, "completed", checkRun.Status)
+	assert.Equal(t, "failure", checkRun.Conclusion)
+	assert.Equal(t, "Tests: 1 passed, 1 failed, 0 skipped", checkRun.Output.Summary)
+	assert.Equal(t, "* **suite / test** expected 1, got 2\n", checkRun.Output.Text)
+
+	require.Len(t, annotations, 1)
+	assert.Equal(t, CheckRunAnnotation{
+		Path:            "main.go",
+		StartLine:       1,
+		EndLine:         1,
+		AnnotationLevel: "warning",
+		Title:           "lint",
+		Message:         "lint",
+	}, annotations[0])
+}
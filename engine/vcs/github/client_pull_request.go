@@ -176,11 +176,22 @@ func (g *githubClient) PullRequestComment(ctx context.Context, repo string, prRe
 		}
 	}
 
-	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, prReq.ID)
 	payload := map[string]string{
 		"body": prReq.Message,
 	}
 	values, _ := json.Marshal(payload)
+
+	if prReq.Key != "" {
+		commentID, err := g.pullRequestCommentID(ctx, repo, prReq.ID, prReq.Key)
+		if err != nil {
+			return err
+		}
+		if commentID != 0 {
+			return g.updatePullRequestComment(ctx, repo, commentID, values)
+		}
+	}
+
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, prReq.ID)
 	res, err := g.post(ctx, path, "application/json", bytes.NewReader(values), nil, &postOptions{skipDefaultBaseURL: false, asUser: true})
 	if err != nil {
 		return sdk.WrapError(err, "Unable to post status")
@@ -202,6 +213,54 @@ func (g *githubClient) PullRequestComment(ctx context.Context, repo string, prRe
 	return nil
 }
 
+// pullRequestCommentID returns the id of the first comment of a pull request that starts with given key, 0 if there is none
+// https://docs.github.com/en/rest/issues/comments#list-issue-comments
+func (g *githubClient) pullRequestCommentID(ctx context.Context, repo string, id int, key string) (int64, error) {
+	nextPage := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=100", repo, id)
+	for nextPage != "" {
+		if ctx.Err() != nil {
+			break
+		}
+
+		status, body, headers, err := g.get(ctx, nextPage, withoutETag)
+		if err != nil {
+			return 0, err
+		}
+		if status >= 400 {
+			return 0, sdk.NewError(sdk.ErrUnknownError, errorAPI(body))
+		}
+		var comments []IssueComment
+		if err := sdk.JSONUnmarshal(body, &comments); err != nil {
+			return 0, sdk.WrapError(err, "unable to parse github comments")
+		}
+		for _, c := range comments {
+			if strings.HasPrefix(c.Body, key) {
+				return c.ID, nil
+			}
+		}
+
+		nextPage = getNextPage(headers)
+	}
+	return 0, nil
+}
+
+// updatePullRequestComment replaces the body of a pull request comment
+// https://docs.github.com/en/rest/issues/comments#update-an-issue-comment
+func (g *githubClient) updatePullRequestComment(ctx context.Context, repo string, commentID int64, values []byte) error {
+	path := fmt.Sprintf("/repos/%s/issues/comments/%d", repo, commentID)
+	res, err := g.patch(ctx, path, "application/json", bytes.NewReader(values), &postOptions{asUser: true})
+	if err != nil {
+		return sdk.WrapError(err, "unable to update comment")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		return sdk.NewErrorFrom(sdk.ErrUnknownError, "unable to update comment on github. Status code : %d - Body: %s", res.StatusCode, body)
+	}
+	return nil
+}
+
 func (g *githubClient) PullRequestCreate(ctx context.Context, repo string, pr sdk.VCSPullRequest) (sdk.VCSPullRequest, error) {
 	canWrite, err := g.UserHasWritePermission(ctx, repo)
 	if err != nil {
@@ -2,9 +2,13 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ovh/cds/engine/api/test"
 	"github.com/ovh/cds/sdk"
@@ -30,3 +34,40 @@ func TestPullRequestComment(t *testing.T) {
 		test.NoError(t, client.PullRequestComment(context.Background(), "ovh/cds", r))
 	}
 }
+
+func TestPullRequestCommentWithKey(t *testing.T) {
+	var calls []string
+	var body map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case http.MethodGet:
+			_ = json.NewEncoder(w).Encode([]IssueComment{
+				{ID: 10, Body: "looks good"},
+				{ID: 11, Body: "**preview of PROJ/my-workflow**\nold"},
+			})
+		case http.MethodPatch:
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusOK)
+		case http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+
+	client := &githubClient{GitHubAPIURL: srv.URL, username: "ovh", token: "token"}
+
+	// The comment that starts with the key is updated
+	r := sdk.VCSPullRequestCommentRequest{Message: "**preview of PROJ/my-workflow**\nnew", Key: "**preview of PROJ/my-workflow**\n"}
+	r.ID = 1
+	require.NoError(t, client.PullRequestComment(context.TODO(), "ovh/cds", r))
+	require.Equal(t, []string{"GET /repos/ovh/cds/issues/1/comments", "PATCH /repos/ovh/cds/issues/comments/11"}, calls)
+	assert.Equal(t, r.Message, body["body"])
+
+	// A new comment is posted when no comment starts with the key
+	calls = nil
+	r.Key = "**preview of PROJ/other-workflow**\n"
+	require.NoError(t, client.PullRequestComment(context.TODO(), "ovh/cds", r))
+	require.Equal(t, []string{"GET /repos/ovh/cds/issues/1/comments", "POST /repos/ovh/cds/issues/1/comments"}, calls)
+}
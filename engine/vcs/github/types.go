@@ -406,6 +406,42 @@ type CreateStatus struct {
 	Context     string `json:"context"`
 }
 
+// CreateCheckRun represents the body of a check run creation or update
+// https://docs.github.com/en/rest/checks/runs#create-a-check-run
+type CreateCheckRun struct {
+	Name       string          `json:"name,omitempty"`
+	HeadSHA    string          `json:"head_sha,omitempty"`
+	ExternalID string          `json:"external_id,omitempty"`
+	DetailsURL string          `json:"details_url,omitempty"`
+	Status     string          `json:"status,omitempty"`
+	Conclusion string          `json:"conclusion,omitempty"`
+	Output     *CheckRunOutput `json:"output,omitempty"`
+}
+
+// CheckRunOutput is the output of a check run
+type CheckRunOutput struct {
+	Title       string               `json:"title"`
+	Summary     string               `json:"summary"`
+	Text        string               `json:"text,omitempty"`
+	Annotations []CheckRunAnnotation `json:"annotations,omitempty"`
+}
+
+// CheckRunAnnotation is an annotation of a check run on a file
+type CheckRunAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+}
+
+// CheckRun represents a check run returned by the API
+type CheckRun struct {
+	ID      int64  `json:"id"`
+	HTMLURL string `json:"html_url"`
+}
+
 //Status represents Create a Status from API
 type Status struct {
 	CreatedAt   time.Time `json:"created_at"`
@@ -471,6 +507,13 @@ type Cursor struct {
 	Repo  Repository `json:"repo"`
 }
 
+// IssueComment represents a comment of an issue or a pull request from github api
+type IssueComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	User User   `json:"user"`
+}
+
 // PullRequest represents pull request from github api
 type PullRequest struct {
 	URL                 string    `json:"url"`
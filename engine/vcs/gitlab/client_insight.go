@@ -0,0 +1,163 @@
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/rockbears/log"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// commitStatusOptions adds the coverage field that is not available in go-gitlab SetCommitStatusOptions
+type commitStatusOptions struct {
+	gitlab.SetCommitStatusOptions
+	Coverage *float64 `url:"coverage,omitempty" json:"coverage,omitempty"`
+}
+
+// SetInsightReport sets a commit status that contains tests results and coverage. The test report is posted as a
+// note on the opened merge requests of the commit, the note of a previous run for the same report key is updated.
+func (c *gitlabClient) SetInsightReport(ctx context.Context, repo string, report sdk.VCSInsightReport) error {
+	if c.disableStatus {
+		log.Warn(ctx, "gitlabClient.SetInsightReport>  ⚠ Gitlab statuses are disabled")
+		return nil
+	}
+
+	opt := newCommitStatusOptions(report)
+	if c.disableStatusDetail {
+		opt.TargetURL = nil
+	}
+	path := fmt.Sprintf("projects/%s/statuses/%s", url.QueryEscape(repo), report.Revision)
+	req, err := c.client.NewRequest("POST", path, opt, nil)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	if _, err := c.client.Do(req, nil); err != nil {
+		return sdk.WrapError(err, "unable to set commit status %s - repo:%s hash:%s", report.Key, repo, report.Revision)
+	}
+
+	if report.Branch == "" {
+		return nil
+	}
+	mrs, _, err := c.client.MergeRequests.ListProjectMergeRequests(repo, &gitlab.ListProjectMergeRequestsOptions{
+		State:        gitlab.String("opened"),
+		SourceBranch: gitlab.String(report.Branch),
+	})
+	if err != nil {
+		return sdk.WrapError(err, "unable to list merge requests - repo:%s branch:%s", repo, report.Branch)
+	}
+	body := mergeRequestNoteBody(report)
+	for _, mr := range mrs {
+		if mr.SHA != report.Revision {
+			continue
+		}
+		if err := c.setMergeRequestNote(repo, mr.IID, reportNoteMarker(report), body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setMergeRequestNote updates the note that starts with given marker, or creates it.
+func (c *gitlabClient) setMergeRequestNote(repo string, mrIID int, marker, body string) error {
+	opt := &gitlab.ListMergeRequestNotesOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	for {
+		notes, resp, err := c.client.Notes.ListMergeRequestNotes(repo, mrIID, opt)
+		if err != nil {
+			return sdk.WrapError(err, "unable to list notes of merge request %d - repo:%s", mrIID, repo)
+		}
+		for _, n := range notes {
+			if !strings.HasPrefix(n.Body, marker) {
+				continue
+			}
+			if n.Body == body {
+				return nil
+			}
+			if _, _, err := c.client.Notes.UpdateMergeRequestNote(repo, mrIID, n.ID, &gitlab.UpdateMergeRequestNoteOptions{Body: &body}); err != nil {
+				return sdk.WrapError(err, "unable to update note %d on merge request %d - repo:%s", n.ID, mrIID, repo)
+			}
+			return nil
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	if _, _, err := c.client.Notes.CreateMergeRequestNote(repo, mrIID, &gitlab.CreateMergeRequestNoteOptions{Body: &body}); err != nil {
+		return sdk.WrapError(err, "unable to create note on merge request %d - repo:%s", mrIID, repo)
+	}
+	return nil
+}
+
+// reportNoteMarker is a hidden comment that identifies the note of a report in a merge request.
+func reportNoteMarker(report sdk.VCSInsightReport) string {
+	return fmt.Sprintf("<!-- cds-insight-report:%s -->", report.Key)
+}
+
+func newCommitStatusOptions(report sdk.VCSInsightReport) commitStatusOptions {
+	state := gitlab.Success
+	if report.Result == sdk.VCSInsightReportResultFail {
+		state = gitlab.Failed
+	}
+	opt := commitStatusOptions{
+		SetCommitStatusOptions: gitlab.SetCommitStatusOptions{
+			State:       state,
+			Name:        gitlab.String(report.Title),
+			Context:     gitlab.String(report.Key),
+			Description: gitlab.String(report.Summary()),
+		},
+	}
+	if report.Branch != "" {
+		opt.Ref = gitlab.String(report.Branch)
+	}
+	if report.Link != "" {
+		opt.TargetURL = gitlab.String(report.Link)
+	}
+	if report.Coverage != nil {
+		coverage := report.Coverage.Percent()
+		opt.Coverage = &coverage
+	}
+	return opt
+}
+
+func mergeRequestNoteBody(report sdk.VCSInsightReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n**%s**: %s\n\n", reportNoteMarker(report), report.Title, report.Summary())
+	if report.Tests != nil || report.Coverage != nil || len(report.Vulnerabilities) > 0 {
+		b.WriteString("| | |\n|---|---|\n")
+		if report.Tests != nil {
+			fmt.Fprintf(&b, "| Tests | %d |\n| Passed | %d |\n| Failed | %d |\n| Skipped | %d |\n",
+				report.Tests.Total, report.Tests.OK, report.Tests.KO, report.Tests.Skipped)
+		}
+		if report.Coverage != nil {
+			fmt.Fprintf(&b, "| Coverage | %.2f%% |\n", report.Coverage.Percent())
+		}
+		severities := make([]string, 0, len(report.Vulnerabilities))
+		for s := range report.Vulnerabilities {
+			severities = append(severities, s)
+		}
+		sort.Strings(severities)
+		for _, s := range severities {
+			fmt.Fprintf(&b, "| Vulnerabilities %s | %d |\n", s, report.Vulnerabilities[s])
+		}
+		b.WriteString("\n")
+	}
+	for i, a := range report.Annotations {
+		if i >= sdk.VCSInsightAnnotationsMax {
+			break
+		}
+		location := ""
+		if a.Path != "" {
+			location = fmt.Sprintf(" (`%s:%d`)", a.Path, a.Line)
+		}
+		fmt.Fprintf(&b, "* **%s**%s %s\n", a.Title, location, strings.TrimSpace(a.Message))
+	}
+	if report.Link != "" {
+		fmt.Fprintf(&b, "\n[Details](%s)\n", report.Link)
+	}
+	return b.String()
+}
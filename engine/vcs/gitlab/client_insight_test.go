@@ -0,0 +1,169 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// fakeGitlabNotes emulates the commit statuses, merge requests and notes API of a single merge request
+type fakeGitlabNotes struct {
+	mutex    sync.Mutex
+	sha      string
+	statuses []map[string]interface{}
+	notes    []gitlab.Note
+	created  int
+	updated  int
+}
+
+func (f *fakeGitlabNotes) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	path := r.URL.EscapedPath()
+	var res interface{}
+	switch {
+	case r.Method == http.MethodPost && strings.Contains(path, "/statuses/"):
+		var s map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.statuses = append(f.statuses, s)
+		res = s
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/merge_requests"):
+		res = []gitlab.MergeRequest{{IID: 1, SHA: f.sha, SourceBranch: r.URL.Query().Get("source_branch")}}
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/merge_requests/1/notes"):
+		res = f.notes
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/merge_requests/1/notes"):
+		var n gitlab.Note
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n.ID = len(f.notes) + 1
+		f.notes = append(f.notes, n)
+		f.created++
+		res = n
+	case r.Method == http.MethodPut && strings.Contains(path, "/merge_requests/1/notes/"):
+		var n gitlab.Note
+		if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for i := range f.notes {
+			if strings.HasSuffix(path, "/notes/"+strconv.Itoa(f.notes[i].ID)) {
+				f.notes[i].Body = n.Body
+				res = f.notes[i]
+			}
+		}
+		f.updated++
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func TestSetInsightReport(t *testing.T) {
+	fake := &fakeGitlabNotes{
+		sha:   "9f4fac7ec5642099982a86f584f2c4a362adb670",
+		notes: []gitlab.Note{{ID: 1, Body: "LGTM"}},
+	}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	c := &gitlabClient{client: gitlab.NewOAuthClient(srv.Client(), "token")}
+	require.NoError(t, c.client.SetBaseURL(srv.URL+"/api/v4"))
+
+	report := sdk.VCSInsightReport{
+		Key:      sdk.VCSInsightReportKey("PROJ", "my-workflow", "build"),
+		Title:    "Tests",
+		Reporter: "CDS",
+		Link:     "https://cds.example.com/project/PROJ/workflow/my-workflow/run/1",
+		Result:   sdk.VCSInsightReportResultFail,
+		Revision: fake.sha,
+		Branch:   "feat/insight",
+		Tests:    &sdk.VCSInsightTests{Total: 3, OK: 2, KO: 1},
+		Coverage: &sdk.VCSInsightCoverage{TotalLines: 200, CoveredLines: 150},
+		Annotations: []sdk.VCSInsightAnnotation{{
+			Severity: sdk.VCSInsightAnnotationSeverityHigh,
+			Type:     sdk.VCSInsightAnnotationTypeBug,
+			Title:    "TestFoo",
+			Message:  "expected 1, got 2",
+		}},
+	}
+
+	// First run creates the note
+	require.NoError(t, c.SetInsightReport(context.TODO(), "group/repo", report))
+	require.Len(t, fake.statuses, 1)
+	assert.Equal(t, "failed", fake.statuses[0]["state"])
+	assert.Equal(t, report.Key, fake.statuses[0]["context"])
+	assert.Equal(t, 75.0, fake.statuses[0]["coverage"])
+	require.Len(t, fake.notes, 2)
+	assert.Equal(t, 1, fake.created)
+	assert.True(t, strings.HasPrefix(fake.notes[1].Body, reportNoteMarker(report)))
+	assert.Contains(t, fake.notes[1].Body, "TestFoo")
+
+	// Same report doesn't write the note again
+	require.NoError(t, c.SetInsightReport(context.TODO(), "group/repo", report))
+	assert.Len(t, fake.statuses, 2)
+	assert.Equal(t, 1, fake.created)
+	assert.Equal(t, 0, fake.updated)
+
+	// New results update the existing note
+	report.Result = sdk.VCSInsightReportResultPass
+	report.Tests = &sdk.VCSInsightTests{Total: 3, OK: 3}
+	report.Annotations = nil
+	require.NoError(t, c.SetInsightReport(context.TODO(), "group/repo", report))
+	assert.Equal(t, 1, fake.created)
+	assert.Equal(t, 1, fake.updated)
+	require.Len(t, fake.notes, 2)
+	assert.Equal(t, "LGTM", fake.notes[0].Body)
+	assert.Equal(t, mergeRequestNoteBody(report), fake.notes[1].Body)
+	assert.NotContains(t, fake.notes[1].Body, "TestFoo")
+
+	// Another commit on the branch doesn't write on the merge request
+	report.Revision = "a5f5a6b2bc1c1a4a5e8c0d7e8b1e0e6e0b7a9c3d"
+	require.NoError(t, c.SetInsightReport(context.TODO(), "group/repo", report))
+	assert.Equal(t, 1, fake.created)
+	assert.Equal(t, 1, fake.updated)
+}
+
+func TestMergeRequestNoteBody(t *testing.T) {
+	report := sdk.VCSInsightReport{
+		Key:             "cds.PROJ.my-workflow.build",
+		Title:           "Tests",
+		Result:          sdk.VCSInsightReportResultFail,
+		Tests:           &sdk.VCSInsightTests{Total: 4, OK: 2, KO: 1, Skipped: 1},
+		Coverage:        &sdk.VCSInsightCoverage{TotalLines: 3, CoveredLines: 1},
+		Vulnerabilities: map[string]int64{"medium": 2, "high": 1},
+		Annotations: []sdk.VCSInsightAnnotation{{
+			Path:    "foo/foo_test.go",
+			Line:    12,
+			Title:   "TestFoo",
+			Message: "expected 1, got 2\n",
+		}},
+		Link: "https://cds.example.com/run/1",
+	}
+
+	body := mergeRequestNoteBody(report)
+	assert.True(t, strings.HasPrefix(body, "<!-- cds-insight-report:cds.PROJ.my-workflow.build -->\n**Tests**: "))
+	assert.Contains(t, body, "| Tests | 4 |\n| Passed | 2 |\n| Failed | 1 |\n| Skipped | 1 |\n")
+	assert.Contains(t, body, "| Coverage | 33.33% |\n")
+	assert.Contains(t, body, "| Vulnerabilities high | 1 |\n| Vulnerabilities medium | 2 |\n")
+	assert.Contains(t, body, "* **TestFoo** (`foo/foo_test.go:12`) expected 1, got 2\n")
+	assert.True(t, strings.HasSuffix(body, "\n[Details](https://cds.example.com/run/1)\n"))
+}
@@ -538,6 +538,46 @@ func (s *Service) getCommitStatusHandler() service.Handler {
 	}
 }
 
+func (s *Service) postCommitInsightReportHandler() service.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+		commit := muxVar(r, "commit")
+
+		var report sdk.VCSInsightReport
+		if err := service.UnmarshalBody(r, &report); err != nil {
+			return sdk.WithStack(err)
+		}
+		report.Revision = commit
+
+		accessToken, accessTokenSecret, created, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "Unable to get access token headers %s %s/%s", name, owner, repo)
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS server unavailable %s %s/%s", name, owner, repo)
+		}
+
+		client, err := consumer.GetAuthorizedClient(ctx, accessToken, accessTokenSecret, created)
+		if err != nil {
+			return sdk.WrapError(err, "Unable to get authorized client %s %s/%s", name, owner, repo)
+		}
+		// Check if access token has been refreshed
+		if accessToken != client.GetAccessToken(ctx) {
+			w.Header().Set(sdk.HeaderXAccessToken, client.GetAccessToken(ctx))
+		}
+
+		if err := client.SetInsightReport(ctx, fmt.Sprintf("%s/%s", owner, repo), report); err != nil {
+			return sdk.WrapError(err, "Unable to set insight report on commit %s of %s/%s", commit, owner, repo)
+		}
+
+		return nil
+	}
+}
+
 func (s *Service) getPullRequestHandler() service.Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		name := muxVar(r, "name")
@@ -56,6 +56,20 @@ Here, an example of a script inside a CDS Job using the cache feature:
 	# put in cache the updated .m2/ directory
 	worker cache push $tag .m2/
 
+## Cache keys
+The tag can be computed from the content of your files with the hashFiles function, it returns the sha256 of all files
+matching given glob patterns (relative to the current directory), ** matches any number of directories:
+
+	worker cache pull --restore-key go- 'go-{{hashFiles "go.sum"}}'
+	go build ./...
+	worker cache push 'go-{{hashFiles "go.sum"}}' ./vendor
+
+If there is no cache for the tag, restore keys are used in the given order as prefixes and the most recent cache
+with a tag starting with the restore key is downloaded.
+
+A cache is immutable: pushing a cache with a tag that already exists does nothing. Caches that were not pulled for a
+while are deleted by CDS CDN.
+
     `,
 	}
 	cmdCacheRoot.AddCommand(cmdCachePush(), cmdCachePull())
@@ -63,7 +77,10 @@ Here, an example of a script inside a CDS Job using the cache feature:
 	return cmdCacheRoot
 }
 
-var cmdStorageIntegrationName string
+var (
+	cmdStorageIntegrationName string
+	cmdCacheRestoreKeys       []string
+)
 
 func cmdCachePush() *cobra.Command {
 	c := &cobra.Command{
@@ -112,6 +129,7 @@ func cachePushCmd() func(cmd *cobra.Command, args []string) {
 
 		c := sdk.Cache{
 			Tag:              base64.RawURLEncoding.EncodeToString([]byte(args[0])),
+			TagEncoded:       true,
 			Files:            files,
 			WorkingDirectory: cwd,
 			IntegrationName:  cmdStorageIntegrationName,
@@ -185,6 +203,7 @@ If you want to push a cache into a storage integration:
 		Run: cachePullCmd(),
 	}
 	c.Flags().StringVar(&cmdStorageIntegrationName, "from", "", "optional. Your storage integration name")
+	c.Flags().StringArrayVar(&cmdCacheRestoreKeys, "restore-key", nil, "optional. Tag prefix used to restore the most recent cache if there is no cache for the tag, can be repeated")
 	return c
 }
 
@@ -209,13 +228,26 @@ func cachePullCmd() func(cmd *cobra.Command, args []string) {
 			sdk.Exit("worker cache pull > cannot get current path: %s", err)
 		}
 
+		cwd, err := os.Getwd()
+		if err != nil {
+			sdk.Exit("worker cache pull > Cannot find working directory : %s", err)
+		}
+
+		query := url.Values{}
+		query.Set("path", dir)
+		query.Set("integration", cmdStorageIntegrationName)
+		query.Set("workingDirectory", cwd)
+		query.Set("tagEncoded", "true")
+		for _, k := range cmdCacheRestoreKeys {
+			query.Add("restoreKey", k)
+		}
+
 		fmt.Printf("Worker cache pull in progress... (tag: %s)\n", args[0])
 		req, errRequest := http.NewRequest(
 			"GET",
-			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/pull?path=%s&integration=%s", port,
+			fmt.Sprintf("http://127.0.0.1:%d/cache/%s/pull?%s", port,
 				base64.RawURLEncoding.EncodeToString([]byte(args[0])),
-				url.QueryEscape(dir),
-				url.QueryEscape(cmdStorageIntegrationName)),
+				query.Encode()),
 			nil,
 		)
 		if errRequest != nil {
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/engine/worker/internal"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	cmdIDTokenAudience string
+)
+
+func cmdIDToken() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "id-token",
+		Short: "worker id-token --audience <audience>",
+		Long: `
+Inside a step script you can get a short-lived OpenID Connect identity token signed by CDS for the current job.
+
+The token can be exchanged for credentials by any service that trusts the CDS issuer (Vault, cloud IAM, Kubernetes...), the issuer configuration is available on the CDS API at /.well-known/openid-configuration.
+
+The subject of the token looks like ` + "`project:MYPROJ:workflow:my-workflow:node:deploy`" + ` and the following claims are set: project_key, workflow_name, workflow_node_name, environment_name, git_repository, git_ref, git_hash, run_number and job_id.
+
+` + "```bash" + `
+TOKEN=$(worker id-token --audience vault)
+vault write auth/jwt/login role=my-role jwt=$TOKEN
+` + "```" + `
+`,
+		Run: idTokenCmd(),
+	}
+	c.Flags().StringVar(&cmdIDTokenAudience, "audience", "", "audience of the token, it should be expected by the service that will receive it")
+	return c
+}
+
+func idTokenCmd() func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		f := func() error {
+			if cmdIDTokenAudience == "" {
+				return fmt.Errorf("missing audience, use --audience flag")
+			}
+
+			portS := os.Getenv(internal.WorkerServerPort)
+			if portS == "" {
+				return fmt.Errorf("%s not found, are you running inside a CDS worker job?", internal.WorkerServerPort)
+			}
+
+			port, err := strconv.Atoi(portS)
+			if err != nil {
+				return fmt.Errorf("cannot parse '%s' as a port number", portS)
+			}
+
+			data, err := json.Marshal(sdk.IDTokenRequest{Audience: cmdIDTokenAudience})
+			if err != nil {
+				return sdk.WithStack(err)
+			}
+
+			req, err := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/idtoken", port), bytes.NewReader(data))
+			if err != nil {
+				return fmt.Errorf("cannot post id token (Request): %s", err)
+			}
+
+			client := http.DefaultClient
+			client.Timeout = 5 * time.Minute
+
+			resp, err := client.Do(req)
+			if err != nil {
+				return fmt.Errorf("command failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				return fmt.Errorf("id token failed: unable to read body %v", err)
+			}
+			if resp.StatusCode >= 300 {
+				return sdk.DecodeError(body)
+			}
+
+			var token sdk.IDToken
+			if err := sdk.JSONUnmarshal(body, &token); err != nil {
+				return sdk.WithStack(err)
+			}
+			fmt.Println(token.Token)
+
+			return nil
+		}
+
+		if err := f(); err != nil {
+			if sdk.IsErrorWithStack(err) {
+				httpErr := sdk.ExtractHTTPError(err)
+				sdk.Exit("%v", httpErr.Error())
+			} else {
+				sdk.Exit("%v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package action
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+func RunCache(ctx context.Context, wk workerruntime.Runtime, a sdk.Action, _ []sdk.Variable) (sdk.Result, error) {
+	res := sdk.Result{Status: sdk.StatusSuccess}
+
+	if !wk.FeatureEnabled(sdk.FeatureCDNArtifact) {
+		return res, sdk.NewErrorFrom(sdk.ErrNotImplemented, "cache action needs CDS CDN to store artifacts")
+	}
+
+	workdir, err := workerruntime.WorkingDirectory(ctx)
+	if err != nil {
+		return res, err
+	}
+	var abs string
+	if x, ok := wk.BaseDir().(*afero.BasePathFs); ok {
+		abs, _ = x.RealPath(workdir.Name())
+	} else {
+		abs = workdir.Name()
+	}
+
+	key, err := ComputeCacheKey(ctx, abs, a)
+	if err != nil {
+		return res, err
+	}
+
+	switch sdk.ParameterValue(a.Parameters, "mode") {
+	case sdk.CacheActionModeSave:
+		paths := splitCacheParameter(sdk.ParameterValue(a.Parameters, "paths"))
+		if len(paths) == 0 {
+			return res, sdk.NewErrorFrom(sdk.ErrInvalidData, "cache paths are mandatory")
+		}
+		if _, err := PushWorkerCache(ctx, wk, key, abs, paths); err != nil {
+			return res, err
+		}
+	default:
+		var restoreKeys []string
+		for _, k := range splitCacheParameter(sdk.ParameterValue(a.Parameters, "restoreKeys")) {
+			restoreKey, err := RenderCacheKey(abs, k)
+			if err != nil {
+				return res, err
+			}
+			restoreKeys = append(restoreKeys, restoreKey)
+		}
+		restoredKey, err := PullWorkerCache(ctx, wk, key, restoreKeys, abs)
+		if sdk.ErrorIs(err, sdk.ErrNotFound) {
+			wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("No cache found for key '%s'", key))
+			res.NewVariables = append(res.NewVariables, sdk.Variable{Name: "cds.build.cache_hit", Type: sdk.StringVariable, Value: "false"})
+			return res, nil
+		}
+		if err != nil {
+			return res, err
+		}
+		wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache '%s' restored", restoredKey))
+		res.NewVariables = append(res.NewVariables, sdk.Variable{Name: "cds.build.cache_hit", Type: sdk.StringVariable, Value: fmt.Sprintf("%t", restoredKey == key)})
+	}
+
+	return res, nil
+}
+
+// splitCacheParameter returns non empty lines of a multi-line parameter
+func splitCacheParameter(value string) []string {
+	var res []string
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			res = append(res, line)
+		}
+	}
+	return res
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/blang/semver"
+	"github.com/rockbears/log"
 	"github.com/spf13/afero"
 
 	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
@@ -171,6 +172,20 @@ func gitClone(ctx context.Context, w workerruntime.Runtime, params []sdk.Paramet
 		Stdout: stdOut,
 	}
 
+	// Clone the repository of the application from its mirror when available
+	gitURLSSH := sdk.ParameterValue(params, "git.url")
+	gitURLHTTP := sdk.ParameterValue(params, "git.http_url")
+	if clone != nil && (gitURLSSH == url || gitURLHTTP == url) {
+		if jobID, err := workerruntime.JobID(ctx); err == nil {
+			m, err := w.Client().QueueJobGitMirror(ctx, jobID, url)
+			if err != nil {
+				log.Debug(ctx, "no git mirror available for %s: %v", url, err)
+			} else {
+				clone.Mirror = &git.MirrorOpts{URL: m.URL, Header: "Authorization: " + m.Authorization}
+			}
+		}
+	}
+
 	//git.LogFunc = log.InfoWithoutCtx
 	//Perform the git clone
 	userLogCommand, err := git.Clone(url, basedir, dir, auth, clone, output)
@@ -190,8 +205,6 @@ func gitClone(ctx context.Context, w workerruntime.Runtime, params []sdk.Paramet
 	}
 
 	// extract info only if we git clone the same repo as current application linked to the pipeline
-	gitURLSSH := sdk.ParameterValue(params, "git.url")
-	gitURLHTTP := sdk.ParameterValue(params, "git.http_url")
 	var vars []sdk.Variable
 	if gitURLSSH == url || gitURLHTTP == url {
 		vars, err = extractInfo(ctx, w, basedir, dir, params, clone.Tag, clone.Branch, clone.CheckoutCommit, clone)
@@ -275,6 +275,10 @@ func RunScriptAction(ctx context.Context, wk workerruntime.Runtime, a sdk.Action
 			}
 		}()
 
+		if wrap := workerruntime.CommandWrapper(ctx); wrap != nil {
+			wrap(cmd)
+		}
+
 		if err := cmd.Start(); err != nil {
 			chanErr <- fmt.Errorf("unable to start command: %v", err)
 			res.Status = sdk.StatusFail
@@ -0,0 +1,291 @@
+package action
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mattn/go-zglob"
+	"github.com/rockbears/log"
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+// RenderCacheKey computes a cache key from the given template. The template can use the hashFiles function
+// to compute a sha256 of all files matching given glob patterns, relative paths are resolved from workdir.
+// Patterns can use ** to match files in any sub directory, ex: **/package-lock.json.
+func RenderCacheKey(workdir string, key string) (string, error) {
+	if !strings.Contains(key, "{{") {
+		return key, nil
+	}
+	t, err := template.New("key").Funcs(template.FuncMap{
+		"hashFiles": func(patterns ...string) (string, error) {
+			return hashFiles(workdir, patterns...)
+		},
+	}).Parse(key)
+	if err != nil {
+		return "", sdk.NewErrorFrom(sdk.ErrInvalidData, "invalid cache key %q: %v", key, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, nil); err != nil {
+		return "", sdk.NewErrorFrom(sdk.ErrInvalidData, "unable to compute cache key %q: %v", key, err)
+	}
+	return buf.String(), nil
+}
+
+// ComputeCacheKey returns the key of given cache step. The key computed when the cache is restored is reused
+// by the save step, so the files changed by the job don't change the key of the saved cache.
+func ComputeCacheKey(ctx context.Context, workdir string, a sdk.Action) (string, error) {
+	raw := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "key"))
+	keys := workerruntime.CacheKeys(ctx)
+	save := sdk.ParameterValue(a.Parameters, "mode") == sdk.CacheActionModeSave
+	if key, ok := keys[raw]; ok && save {
+		return key, nil
+	}
+	key, err := RenderCacheKey(workdir, raw)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return "", sdk.NewErrorFrom(sdk.ErrInvalidData, "cache key is mandatory")
+	}
+	if keys != nil && !save {
+		keys[raw] = key
+	}
+	return key, nil
+}
+
+// hashFiles returns the sha256 of the content of all files matching given patterns,
+// files are sorted by path to get a stable result.
+func hashFiles(workdir string, patterns ...string) (string, error) {
+	var files []string
+	for _, p := range patterns {
+		if !sdk.PathIsAbs(p) {
+			p = filepath.Join(workdir, p)
+		}
+		// ** matches any number of directories
+		matches, err := zglob.Glob(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid pattern %q: %v", p, err)
+		}
+		files = append(files, matches...)
+	}
+	if len(files) == 0 {
+		return "", fmt.Errorf("no file matching %s", strings.Join(patterns, ", "))
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, file := range files {
+		fi, err := os.Stat(file)
+		if err != nil {
+			return "", err
+		}
+		if fi.IsDir() {
+			continue
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(h, f)
+		_ = f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// PullWorkerCache downloads and extracts into dest the worker cache matching the given key. If no cache exists
+// for the key, restore keys are used as prefixes in the given order and the most recent matching cache is used.
+// It returns the key of the restored cache.
+func PullWorkerCache(ctx context.Context, wk workerruntime.Runtime, key string, restoreKeys []string, dest string) (string, error) {
+	jobID, err := workerruntime.JobID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	links, err := wk.Client().QueueWorkerCacheLinks(ctx, jobID, key, restoreKeys)
+	if err != nil {
+		return "", err
+	}
+	if len(links.Items) != 1 {
+		return "", sdk.NewErrorFrom(sdk.ErrNotFound, "no unique cache found for key %q", key)
+	}
+	it := links.Items[0]
+	var restoredKey string
+	if apiRef, ok := it.GetCDNWorkerCacheApiRef(); ok {
+		restoredKey = apiRef.CacheTag
+	}
+
+	fs := afero.NewOsFs()
+	if err := fs.MkdirAll(dest, os.FileMode(0744)); err != nil {
+		return "", sdk.NewError(sdk.ErrInvalidData, fmt.Errorf("unable to create destination directory: %v", err))
+	}
+
+	archivePath := filepath.Join(dest, "workercache.tar")
+	f, err := os.OpenFile(archivePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, os.FileMode(0755))
+	if err != nil {
+		return "", sdk.NewError(sdk.ErrUnknownError, fmt.Errorf("cannot create file (OpenFile) %s: %s", archivePath, err))
+	}
+	if err := wk.Client().CDNItemDownload(ctx, wk.CDNHttpURL(), it.APIRefHash, sdk.CDNTypeItemWorkerCache, it.MD5, f); err != nil {
+		_ = f.Close()
+		return "", sdk.WrapError(err, "cannot download cache %q", restoredKey)
+	}
+	if err := f.Close(); err != nil {
+		return "", sdk.WrapError(err, "unable to close file %s", archivePath)
+	}
+
+	log.Info(ctx, "extracting worker cache %s / %s", archivePath, restoredKey)
+	archive, err := fs.Open(archivePath)
+	if err != nil {
+		return "", sdk.WrapError(err, "unable to open archive")
+	}
+	defer fs.Remove(archivePath) // nolint
+	defer archive.Close()        // nolint
+	if err := ExtractCacheArchive(ctx, archive, dest); err != nil {
+		return "", err
+	}
+	return restoredKey, nil
+}
+
+// PushWorkerCache uploads given paths as a worker cache with the given key. Cache entries are immutable,
+// nothing is uploaded if a cache already exists for the key. It returns true if the cache was uploaded.
+func PushWorkerCache(ctx context.Context, wk workerruntime.Runtime, key string, workdir string, paths []string) (bool, error) {
+	jobID, err := workerruntime.JobID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := wk.Client().QueueWorkerCacheLinks(ctx, jobID, key, nil); err == nil {
+		wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache '%s' already exists, upload skipped", key))
+		return false, nil
+	} else if !sdk.ErrorIs(err, sdk.ErrNotFound) {
+		return false, err
+	}
+
+	tmpDirectory, err := workerruntime.TmpDirectory(ctx)
+	if err != nil {
+		return false, err
+	}
+	tarF, err := afero.TempFile(wk.BaseDir(), tmpDirectory.Name(), "tar-")
+	if err != nil {
+		return false, sdk.WrapError(err, "cannot create tmp tar file")
+	}
+	defer wk.BaseDir().Remove(tarF.Name()) // nolint
+
+	if err := sdk.CreateTarFromPaths(afero.NewOsFs(), workdir, paths, tarF, nil); err != nil {
+		_ = tarF.Close()
+		return false, sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("cannot tar (%+v): %v", paths, err))
+	}
+	if err := tarF.Close(); err != nil {
+		return false, sdk.WithStack(err)
+	}
+
+	sig, err := wk.WorkerCacheSignature(key)
+	if err != nil {
+		return false, err
+	}
+	duration, err := wk.Client().CDNItemUpload(ctx, wk.CDNHttpURL(), sig, wk.BaseDir(), tarF.Name())
+	if err != nil {
+		return false, err
+	}
+	wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache '%s' uploaded in %.2fs to CDS CDN", key, duration.Seconds()))
+	return true, nil
+}
+
+// ExtractCacheArchive extracts the given tar stream into path.
+func ExtractCacheArchive(ctx context.Context, r io.Reader, path string) *sdk.Error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return &sdk.Error{
+				Message: "worker cache pull > Unable to read tar file: " + err.Error(),
+				Status:  http.StatusBadRequest,
+			}
+		}
+
+		if header == nil {
+			continue
+		}
+
+		log.Debug(ctx, "cachePullHandler> Tar contains file %s", header.Name)
+
+		// the target location where the dir/file should be created
+		target := filepath.Join(path, header.Name)
+
+		// check the file type
+		switch header.Typeflag {
+		// if its a dir and it doesn't exist create it
+		case tar.TypeDir:
+			if _, err := os.Stat(target); err != nil {
+				if err := os.MkdirAll(target, 0755); err != nil {
+					return &sdk.Error{
+						Message: "worker cache pull > Unable to mkdir all files : " + err.Error(),
+						Status:  http.StatusInternalServerError,
+					}
+				}
+			}
+		case tar.TypeSymlink:
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return &sdk.Error{
+					Message: "worker cache pull > Unable to create symlink: " + err.Error(),
+					Status:  http.StatusInternalServerError,
+				}
+			}
+
+			// if it's a file create it
+		case tar.TypeReg, tar.TypeLink:
+			// if directory of file does not exist, create it before
+			if _, err := os.Stat(filepath.Dir(target)); err != nil {
+				if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+					return &sdk.Error{
+						Message: "worker cache pull > Unable to mkdir all files : " + err.Error(),
+						Status:  http.StatusInternalServerError,
+					}
+				}
+			}
+
+			log.Debug(ctx, "cachePullHandler> Create file at %s", target)
+
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, os.FileMode(header.Mode))
+			if err != nil {
+				return &sdk.Error{
+					Message: "worker cache pull > Unable to open file: " + err.Error(),
+					Status:  http.StatusInternalServerError,
+				}
+			}
+
+			// copy over contents
+			if _, err := io.Copy(f, tr); err != nil {
+				_ = f.Close()
+				return &sdk.Error{
+					Message: "worker cache pull > Cannot copy content file: " + err.Error(),
+					Status:  http.StatusInternalServerError,
+				}
+			}
+			_ = f.Close()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package action
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+func TestRenderCacheKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache-key")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.sum"), []byte("foo"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b.lock"), []byte("bar"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.lock"), []byte("baz"), 0644))
+
+	sum := sha256.Sum256([]byte("foo"))
+	key, err := RenderCacheKey(dir, `go-{{hashFiles "go.sum"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "go-"+hex.EncodeToString(sum[:]), key)
+
+	// Files are sorted by path before computing the hash
+	sum = sha256.Sum256([]byte("bazbar"))
+	key, err = RenderCacheKey(dir, `lock-{{hashFiles "*.lock"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "lock-"+hex.EncodeToString(sum[:]), key)
+
+	key, err = RenderCacheKey(dir, "latest")
+	require.NoError(t, err)
+	assert.Equal(t, "latest", key)
+
+	_, err = RenderCacheKey(dir, `go-{{hashFiles "unknown.sum"}}`)
+	assert.Error(t, err)
+}
+
+func TestRenderCacheKeyRecursivePattern(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache-key")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "web", "app", "node_modules"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.sum"), []byte("foo"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "package-lock.json"), []byte("a"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "web", "package-lock.json"), []byte("b"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "web", "app", "package-lock.json"), []byte("c"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "web", "app", "package.json"), []byte("d"), 0644))
+
+	// ** matches files at any depth, including the working directory, files are sorted by path
+	sum := sha256.Sum256([]byte("fooacb"))
+	key, err := RenderCacheKey(dir, `app-{{hashFiles "go.sum" "**/package-lock.json"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, "app-"+hex.EncodeToString(sum[:]), key)
+
+	sum = sha256.Sum256([]byte("cb"))
+	key, err = RenderCacheKey(dir, `{{hashFiles "web/**/package-lock.json"}}`)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), key)
+}
+
+func TestComputeCacheKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cache-key")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.sum"), []byte("foo"), 0644))
+	ctx := workerruntime.SetCacheKeys(context.TODO(), make(map[string]string))
+	restore := sdk.Action{Parameters: []sdk.Parameter{
+		{Name: "key", Value: `go-{{hashFiles "go.sum"}}`},
+		{Name: "mode", Value: sdk.CacheActionModeRestore},
+	}}
+	save := sdk.Action{Parameters: []sdk.Parameter{
+		{Name: "key", Value: `go-{{hashFiles "go.sum"}}`},
+		{Name: "mode", Value: sdk.CacheActionModeSave},
+	}}
+
+	restoredKey, err := ComputeCacheKey(ctx, dir, restore)
+	require.NoError(t, err)
+
+	// The job changed the file, the cache is saved with the key computed at restore
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.sum"), []byte("bar"), 0644))
+	savedKey, err := ComputeCacheKey(ctx, dir, save)
+	require.NoError(t, err)
+	assert.Equal(t, restoredKey, savedKey)
+
+	// Without restore step, the key is computed by the save step
+	savedKey, err = ComputeCacheKey(workerruntime.SetCacheKeys(context.TODO(), make(map[string]string)), dir, save)
+	require.NoError(t, err)
+	assert.NotEqual(t, restoredKey, savedKey)
+
+	_, err = ComputeCacheKey(ctx, dir, sdk.Action{})
+	assert.Error(t, err)
+}
@@ -28,9 +28,22 @@ func init() {
 	mapBuiltinActions[sdk.ServeStaticFiles] = action.RunServeStaticFiles
 	mapBuiltinActions[sdk.InstallKeyAction] = action.RunInstallKey
 	mapBuiltinActions[sdk.PushBuildInfo] = action.PushBuildInfo
+	mapBuiltinActions[sdk.CacheAction] = action.RunCache
 }
 
 func (w *CurrentWorker) runBuiltin(ctx context.Context, a sdk.Action, secrets []sdk.Variable) sdk.Result {
+	if w.local != nil {
+		res, done, err := w.runLocalBuiltin(ctx, a)
+		if err != nil {
+			res.Status = sdk.StatusFail
+			res.Reason = err.Error()
+			w.SendLog(ctx, workerruntime.LevelError, res.Reason)
+		}
+		if done {
+			return res
+		}
+	}
+
 	f, ok := mapBuiltinActions[a.Name]
 	if !ok {
 		res := sdk.Result{
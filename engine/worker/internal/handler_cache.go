@@ -1,20 +1,18 @@
 package internal
 
 import (
-	"archive/tar"
 	"context"
+	"encoding/base64"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net/http"
-	"os"
-	"path/filepath"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rockbears/log"
 	"github.com/spf13/afero"
 
+	"github.com/ovh/cds/engine/worker/internal/action"
 	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
 	"github.com/ovh/cds/sdk"
 )
@@ -49,6 +47,13 @@ func cachePushHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 			return
 		}
 
+		key, ref, err := renderCacheKey(c.Tag, c.TagEncoded, c.WorkingDirectory)
+		if err != nil {
+			log.Error(ctx, "worker cache push > %v", err)
+			writeError(w, r, err)
+			return
+		}
+
 		tmpDirectory, err := workerruntime.TmpDirectory(wk.currentJob.context)
 		if err != nil {
 			err = sdk.Error{
@@ -60,6 +65,15 @@ func cachePushHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 			return
 		}
 
+		if cdnArtifact {
+			ctx = workerruntime.SetTmpDirectory(ctx, tmpDirectory)
+			if _, err := action.PushWorkerCache(ctx, wk, key, c.WorkingDirectory, c.Files); err != nil {
+				log.Error(ctx, "worker cache push > %v", err)
+				writeError(w, r, err)
+			}
+			return
+		}
+
 		tarF, err := afero.TempFile(wk.BaseDir(), tmpDirectory.Name(), "tar-")
 		if err != nil {
 			err = sdk.Error{
@@ -119,51 +133,31 @@ func cachePushHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 			return
 		}
 
-		if !cdnArtifact {
-			var errPush error
-			for i := 0; i < 10; i++ {
-				f, err := wk.BaseDir().Open(tarPath)
-				if err != nil {
-					err := sdk.Error{
-						Message: "worker cache push > Cannot open tar file: " + err.Error(),
-						Status:  http.StatusInternalServerError,
-					}
-					log.Error(ctx, "%v", err)
-					writeError(w, r, err)
-					return
-				}
-				if errPush = wk.client.WorkflowCachePush(projectKey, sdk.DefaultIfEmptyStorage(c.IntegrationName), c.Tag, f, int(tarInfo.Size())); errPush == nil {
-					return
-				}
-				log.Error(ctx, "worker cache push > cannot push cache (retry x%d) : %v", i, errPush)
-				err = sdk.Error{
-					Message: "worker cache push > Cannot push cache: " + errPush.Error(),
+		var errPush error
+		for i := 0; i < 10; i++ {
+			f, err := wk.BaseDir().Open(tarPath)
+			if err != nil {
+				err := sdk.Error{
+					Message: "worker cache push > Cannot open tar file: " + err.Error(),
 					Status:  http.StatusInternalServerError,
 				}
-				time.Sleep(3 * time.Second)
+				log.Error(ctx, "%v", err)
+				writeError(w, r, err)
+				return
 			}
-			log.Error(ctx, "%v", err)
-			writeError(w, r, err)
-			return
-		}
-
-		sig, err := wk.WorkerCacheSignature(c.Tag)
-		if err != nil {
-			err := sdk.Error{
-				Message: "worker cache push > Cannot create signature",
+			if errPush = wk.client.WorkflowCachePush(projectKey, sdk.DefaultIfEmptyStorage(c.IntegrationName), ref, f, int(tarInfo.Size())); errPush == nil {
+				return
+			}
+			log.Error(ctx, "worker cache push > cannot push cache (retry x%d) : %v", i, errPush)
+			err = sdk.Error{
+				Message: "worker cache push > Cannot push cache: " + errPush.Error(),
 				Status:  http.StatusInternalServerError,
 			}
-			log.Error(ctx, "%v", err)
-			writeError(w, r, err)
-			return
+			time.Sleep(3 * time.Second)
 		}
-		duration, err := wk.client.CDNItemUpload(ctx, wk.cdnHttpAddr, sig, wk.BaseDir(), tarF.Name())
-		if err != nil {
-			log.Error(ctx, "%v", err)
-			writeError(w, r, err)
-			return
-		}
-		wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache '%s' uploaded in %.2fs to CDS CDN", c.Tag, duration.Seconds()))
+		log.Error(ctx, "%v", err)
+		writeError(w, r, err)
+		return
 	}
 }
 
@@ -176,53 +170,40 @@ func cachePullHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 		vars := mux.Vars(req)
 		path := req.FormValue("path")
 
+		// Cache keys are rendered from the directory where the command was called
+		workingDirectory := req.FormValue("workingDirectory")
+		if workingDirectory == "" {
+			workingDirectory = path
+		}
+		key, ref, err := renderCacheKey(vars["ref"], req.FormValue("tagEncoded") == "true", workingDirectory)
+		if err != nil {
+			log.Error(ctx, "worker cache pull > %v", err)
+			writeError(w, req, err)
+			return
+		}
+
 		cdnArtifact := wk.FeatureEnabled(sdk.FeatureCDNArtifact)
 		params := wk.currentJob.wJob.Parameters
 		projectKey := sdk.ParameterValue(params, "cds.project")
 
 		if !cdnArtifact {
-			getWorkerCacheFromAPI(w, req, wk, projectKey, vars, ctx, path)
+			getWorkerCacheFromAPI(w, req, wk, projectKey, ref, ctx, path)
 			return
 		}
 
-		// Get cache link
-		items, err := wk.client.QueueWorkerCacheLink(ctx, wk.currentJob.wJob.ID, vars["ref"])
-		if err != nil {
-			err = sdk.Error{
-				Message: "worker cache pull > Cannot get cache links: " + err.Error(),
-				Status:  http.StatusNotFound,
-			}
-			log.Error(ctx, "%v", err)
-			writeError(w, req, err)
-			return
-		}
-		if len(items.Items) != 1 {
-			err := sdk.Error{
-				Message: "worker cache pull > No unique link found",
-				Status:  http.StatusNotFound,
+		restoreKeys := make([]string, 0, len(req.Form["restoreKey"]))
+		for _, k := range req.Form["restoreKey"] {
+			restoreKey, err := action.RenderCacheKey(workingDirectory, k)
+			if err != nil {
+				log.Error(ctx, "worker cache pull > %v", err)
+				writeError(w, req, err)
+				return
 			}
-			log.Error(ctx, "%v", err)
-			writeError(w, req, err)
-			return
-		}
-		// Download cache
-		wkDirFS := afero.NewOsFs()
-		if err := wkDirFS.MkdirAll(path, os.FileMode(0744)); err != nil {
-			newError := sdk.NewError(sdk.ErrInvalidData, fmt.Errorf("unable to create destination directory: %v", err))
-			writeError(w, req, newError)
-			return
+			restoreKeys = append(restoreKeys, restoreKey)
 		}
 
-		dest := filepath.Join(path, "workercache.tar")
-		f, err := os.OpenFile(dest, os.O_RDWR|os.O_CREATE|os.O_TRUNC, os.FileMode(0755))
+		restoredKey, err := action.PullWorkerCache(ctx, wk, key, restoreKeys, path)
 		if err != nil {
-			err = sdk.NewError(sdk.ErrUnknownError, fmt.Errorf("cannot create file (OpenFile) %s: %s", dest, err))
-			log.Error(ctx, "%v", err)
-			writeError(w, req, err)
-			return
-		}
-		if err := wk.client.CDNItemDownload(ctx, wk.cdnHttpAddr, items.Items[0].APIRefHash, sdk.CDNTypeItemWorkerCache, items.Items[0].MD5, f); err != nil {
-			_ = f.Close()
 			err = sdk.Error{
 				Message: "worker cache pull > Cannot pull cache: " + err.Error(),
 				Status:  http.StatusNotFound,
@@ -231,50 +212,40 @@ func cachePullHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
 			writeError(w, req, err)
 			return
 		}
-		if err := f.Close(); err != nil {
-			err = sdk.Error{
-				Message: fmt.Sprintf("worker cache pull > unable to close file %s: %v", dest, err),
-				Status:  http.StatusInternalServerError,
-			}
-			log.Error(ctx, "%v", err)
-			writeError(w, req, err)
-			return
+		if restoredKey != key {
+			wk.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache '%s' not found, cache '%s' restored", key, restoredKey))
 		}
+	}
+}
 
-		// Open tar file
-		log.Info(ctx, "extracting worker cache %s / %s", dest, vars["ref"])
-		archive, err := wkDirFS.Open(dest)
+// renderCacheKey decodes the tag if it was encoded by worker cache commands then computes the cache key.
+// It also returns the reference of the cache for the legacy storage, that is the tag as sent if it is not a template.
+func renderCacheKey(tag string, encoded bool, workingDirectory string) (string, string, error) {
+	raw := tag
+	if encoded {
+		btes, err := base64.RawURLEncoding.DecodeString(tag)
 		if err != nil {
-			e := sdk.Error{
-				Message: "worker cache pull > unable to open archive: " + err.Error(),
-				Status:  http.StatusInternalServerError,
-			}
-			log.Error(ctx, "%v", e)
-			writeError(w, req, e)
-			return
+			return "", "", sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid encoded cache tag %q", tag)
 		}
-		if err := extractArchive(ctx, archive, path); err != nil {
-			log.Error(ctx, "%v", err)
-			writeError(w, req, err)
-			return
-		}
-		if err := wkDirFS.Remove(dest); err != nil {
-			e := sdk.Error{
-				Message: "unable to remove worker cache archive: " + err.Error(),
-				Status:  http.StatusInternalServerError,
-			}
-			log.Error(ctx, "%v", e)
-			writeError(w, req, e)
-			return
-		}
-		return
+		raw = string(btes)
+	}
+	key, err := action.RenderCacheKey(workingDirectory, raw)
+	if err != nil {
+		return "", "", err
+	}
+	if key == raw {
+		return key, tag, nil
 	}
+	if encoded {
+		return key, base64.RawURLEncoding.EncodeToString([]byte(key)), nil
+	}
+	return key, key, nil
 }
 
-func getWorkerCacheFromAPI(w http.ResponseWriter, req *http.Request, wk *CurrentWorker, projectKey string, vars map[string]string, ctx context.Context, path string) {
+func getWorkerCacheFromAPI(w http.ResponseWriter, req *http.Request, wk *CurrentWorker, projectKey string, ref string, ctx context.Context, path string) {
 	integrationName := sdk.DefaultIfEmptyStorage(req.FormValue("integration"))
 	var err error
-	reader, err := wk.client.WorkflowCachePull(projectKey, integrationName, vars["ref"])
+	reader, err := wk.client.WorkflowCachePull(projectKey, integrationName, ref)
 	if err != nil {
 		err = sdk.Error{
 			Message: "worker cache pull > Cannot pull cache: " + err.Error(),
@@ -286,89 +257,10 @@ func getWorkerCacheFromAPI(w http.ResponseWriter, req *http.Request, wk *Current
 	}
 	log.Debug(ctx, "cachePullHandler> Start read cache tar")
 
-	if err := extractArchive(ctx, reader, path); err != nil {
+	if err := action.ExtractCacheArchive(ctx, reader, path); err != nil {
 		log.Error(ctx, "%s", err.Message)
 		writeJSON(w, err, err.Status)
 		return
 	}
 	return
 }
-
-func extractArchive(ctx context.Context, r io.Reader, path string) *sdk.Error {
-	tr := tar.NewReader(r)
-	for {
-		header, err := tr.Next()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return &sdk.Error{
-				Message: "worker cache pull > Unable to read tar file: " + err.Error(),
-				Status:  http.StatusBadRequest,
-			}
-		}
-
-		if header == nil {
-			continue
-		}
-
-		log.Debug(ctx, "cachePullHandler> Tar contains file %s", header.Name)
-
-		// the target location where the dir/file should be created
-		target := filepath.Join(path, header.Name)
-
-		// check the file type
-		switch header.Typeflag {
-		// if its a dir and it doesn't exist create it
-		case tar.TypeDir:
-			if _, err := os.Stat(target); err != nil {
-				if err := os.MkdirAll(target, 0755); err != nil {
-					return &sdk.Error{
-						Message: "worker cache pull > Unable to mkdir all files : " + err.Error(),
-						Status:  http.StatusInternalServerError,
-					}
-				}
-			}
-		case tar.TypeSymlink:
-			if err := os.Symlink(header.Linkname, target); err != nil {
-				return &sdk.Error{
-					Message: "worker cache pull > Unable to create symlink: " + err.Error(),
-					Status:  http.StatusInternalServerError,
-				}
-			}
-
-			// if it's a file create it
-		case tar.TypeReg, tar.TypeLink:
-			// if directory of file does not exist, create it before
-			if _, err := os.Stat(filepath.Dir(target)); err != nil {
-				if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
-					return &sdk.Error{
-						Message: "worker cache pull > Unable to mkdir all files : " + err.Error(),
-						Status:  http.StatusInternalServerError,
-					}
-				}
-			}
-
-			log.Debug(ctx, "cachePullHandler> Create file at %s", target)
-
-			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY, os.FileMode(header.Mode))
-			if err != nil {
-				return &sdk.Error{
-					Message: "worker cache pull > Unable to open file: " + err.Error(),
-					Status:  http.StatusInternalServerError,
-				}
-			}
-
-			// copy over contents
-			if _, err := io.Copy(f, tr); err != nil {
-				_ = f.Close()
-				return &sdk.Error{
-					Message: "worker cache pull > Cannot copy content file: " + err.Error(),
-					Status:  http.StatusInternalServerError,
-				}
-			}
-			_ = f.Close()
-		}
-	}
-	return nil
-}
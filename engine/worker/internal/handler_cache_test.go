@@ -3,6 +3,7 @@ package internal
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -175,3 +176,28 @@ func Test_cachePushPullHandler(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "absolute", string(btsAbsolute))
 }
+
+func Test_renderCacheKey(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		tag     string
+		encoded bool
+		key     string
+		ref     string
+	}{
+		// A plain tag that is valid base64 is not decoded
+		{tag: "master", key: "master", ref: "master"},
+		{tag: encode("master"), encoded: true, key: "master", ref: encode("master")},
+		{tag: "v1-2-3", key: "v1-2-3", ref: "v1-2-3"},
+		{tag: encode(`go-{{"1"}}`), encoded: true, key: "go-1", ref: encode("go-1")},
+	}
+	for _, tt := range tests {
+		key, ref, err := renderCacheKey(tt.tag, tt.encoded, t.TempDir())
+		require.NoError(t, err, tt.tag)
+		assert.Equal(t, tt.key, key, tt.tag)
+		assert.Equal(t, tt.ref, ref, tt.tag)
+	}
+
+	_, _, err := renderCacheKey("not base64!", true, t.TempDir())
+	require.Error(t, err)
+}
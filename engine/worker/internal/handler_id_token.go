@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+func idTokenHandler(ctx context.Context, wk *CurrentWorker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := workerruntime.SetJobID(ctx, wk.currentJob.wJob.ID)
+		ctx = workerruntime.SetStepOrder(ctx, wk.currentJob.currentStepIndex)
+		ctx = workerruntime.SetStepName(ctx, wk.currentJob.currentStepName)
+
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, err))
+			return
+		}
+		defer r.Body.Close()
+
+		var req sdk.IDTokenRequest
+		if err := sdk.JSONUnmarshal(data, &req); err != nil {
+			writeError(w, r, sdk.NewError(sdk.ErrWrongRequest, err))
+			return
+		}
+		if err := req.IsValid(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		token, err := wk.client.QueueJobIDToken(ctx, wk.currentJob.wJob.ID, req)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		// The token should not be displayed in step logs
+		wk.currentJob.secrets = append(wk.currentJob.secrets, sdk.Variable{
+			Name:  "cds.id_token",
+			Type:  sdk.SecretVariable,
+			Value: token.Token,
+		})
+
+		writeJSON(w, token, http.StatusOK)
+	}
+}
@@ -0,0 +1,64 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient/mock_cdsclient"
+)
+
+func Test_idTokenHandler(t *testing.T) {
+	// Setup test worker
+	wk := &CurrentWorker{}
+	wk.currentJob.wJob = &sdk.WorkflowNodeJobRun{
+		ID: 1,
+	}
+
+	// Prepare mock client for cds workers
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() { ctrl.Finish() })
+	m := mock_cdsclient.NewMockWorkerInterface(ctrl)
+	wk.client = m
+
+	m.EXPECT().QueueJobIDToken(gomock.Any(), int64(1), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, jobID int64, req sdk.IDTokenRequest) (sdk.IDToken, error) {
+			assert.Equal(t, "vault", req.Audience)
+			return sdk.IDToken{Token: "my-signed-token"}, nil
+		},
+	).Times(1)
+
+	buf, err := json.Marshal(sdk.IDTokenRequest{Audience: "vault"})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, "", bytes.NewBuffer(buf))
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	idTokenHandler(context.Background(), wk)(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var token sdk.IDToken
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &token))
+	assert.Equal(t, "my-signed-token", token.Token)
+
+	// The token is blurred in logs
+	s := "token is my-signed-token"
+	require.NoError(t, wk.Blur(&s))
+	assert.Equal(t, "token is "+sdk.PasswordPlaceholder, s)
+
+	// Audience is mandatory
+	buf, err = json.Marshal(sdk.IDTokenRequest{})
+	require.NoError(t, err)
+	req, err = http.NewRequest(http.MethodPost, "", bytes.NewBuffer(buf))
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	idTokenHandler(context.Background(), wk)(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,311 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/internal/action"
+	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
+	"github.com/ovh/cds/sdk"
+)
+
+// LocalOptions are used to run jobs without CDS API.
+type LocalOptions struct {
+	// Directory where artifacts and cache are stored
+	Directory string
+	// Sources is the directory copied by checkout application steps
+	Sources string
+	// Output receives steps logs
+	Output io.Writer
+	// CommandWrapper is set on script steps, ex: to run them in a container
+	CommandWrapper workerruntime.CommandWrapperFunc
+}
+
+// Builtin actions that can't be executed without CDS API, they are skipped
+var localSkippedBuiltinActions = []string{
+	sdk.GitTagAction,
+	sdk.ReleaseVCSAction,
+	sdk.ReleaseAction,
+	sdk.DeployApplicationAction,
+	sdk.ServeStaticFiles,
+	sdk.PushBuildInfo,
+}
+
+// NewLocalWorker returns a worker that runs jobs on the local machine with the builtin actions.
+func NewLocalWorker(name string, workspace afero.Fs, opts LocalOptions) *CurrentWorker {
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	wk := &CurrentWorker{
+		basedir: workspace,
+		client:  localClient{},
+		local:   &opts,
+	}
+	wk.status.Name = name
+	return wk
+}
+
+// ProcessLocalJob runs the given job with parameters and secrets.
+func (w *CurrentWorker) ProcessLocalJob(ctx context.Context, job sdk.Job, params []sdk.Parameter, secrets []sdk.Variable) sdk.Result {
+	jobInfo := sdk.WorkflowNodeJobRunData{
+		NodeJobRun: sdk.WorkflowNodeJobRun{
+			Job:        sdk.ExecutedJob{Job: job, WorkerName: w.Name()},
+			Parameters: params,
+		},
+		Secrets: secrets,
+	}
+	w.currentJob.wJob = &jobInfo.NodeJobRun
+	w.currentJob.secrets = secrets
+	w.currentJob.newVariables = nil
+	if w.local.CommandWrapper != nil {
+		ctx = workerruntime.SetCommandWrapper(ctx, w.local.CommandWrapper)
+	}
+	w.currentJob.context = ctx
+	return w.ProcessJob(jobInfo)
+}
+
+func (w *CurrentWorker) sendLocalLog(level workerruntime.Level, s string) {
+	if err := w.Blur(&s); err != nil {
+		return
+	}
+	if !strings.HasSuffix(s, "\n") {
+		s += "\n"
+	}
+	if level == workerruntime.LevelError {
+		s = "[ERROR] " + s
+	}
+	fmt.Fprint(w.local.Output, s) // nolint
+}
+
+// runLocalBuiltin runs builtin actions that use CDS API to store data, it returns false if the action
+// should be executed by the default builtin action.
+func (w *CurrentWorker) runLocalBuiltin(ctx context.Context, a sdk.Action) (sdk.Result, bool, error) {
+	res := sdk.Result{Status: sdk.StatusSuccess}
+	if sdk.IsInArray(a.Name, localSkippedBuiltinActions) {
+		w.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("Step %s is skipped on local execution", a.Name))
+		res.Status = sdk.StatusSkipped
+		return res, true, nil
+	}
+
+	workdir, err := workerruntime.WorkingDirectory(ctx)
+	if err != nil {
+		return res, true, err
+	}
+	var abs string
+	if x, ok := w.BaseDir().(*afero.BasePathFs); ok {
+		abs, _ = x.RealPath(workdir.Name())
+	} else {
+		abs = workdir.Name()
+	}
+
+	switch a.Name {
+	case sdk.CheckoutApplicationAction:
+		return res, true, w.localCheckout(ctx, abs, sdk.ParameterValue(a.Parameters, "directory"))
+	case sdk.ArtifactUpload:
+		return res, true, w.localArtifactUpload(ctx, abs, a)
+	case sdk.ArtifactDownload:
+		return res, true, w.localArtifactDownload(ctx, abs, a)
+	case sdk.CacheAction:
+		res.NewVariables, err = w.localCache(ctx, abs, a)
+		return res, true, err
+	}
+	return res, false, nil
+}
+
+func (w *CurrentWorker) localCheckout(ctx context.Context, workdir, directory string) error {
+	if w.local.Sources == "" {
+		w.SendLog(ctx, workerruntime.LevelWarn, "No sources directory given, checkout is skipped")
+		return nil
+	}
+	dest := workdir
+	if sdk.PathIsAbs(directory) {
+		dest = directory
+	} else if directory != "" {
+		dest = filepath.Join(workdir, directory)
+	}
+	w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Copying %s to %s", w.local.Sources, dest))
+	return copyDirectory(ctx, w.local.Sources, dest)
+}
+
+func (w *CurrentWorker) localArtifactUpload(ctx context.Context, workdir string, a sdk.Action) error {
+	artifactPath := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "path"))
+	if artifactPath == "" {
+		artifactPath = "."
+	}
+	if !sdk.PathIsAbs(artifactPath) {
+		artifactPath = filepath.Join(workdir, artifactPath)
+	}
+	files, err := filepath.Glob(artifactPath)
+	if err != nil {
+		return sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("cannot perform globbing of pattern '%s': %s", artifactPath, err))
+	}
+	if len(files) == 0 {
+		return sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("pattern '%s' matched no file", artifactPath))
+	}
+
+	dest := filepath.Join(w.local.Directory, "artifacts", url.PathEscape(sdk.ParameterValue(a.Parameters, "tag")))
+	for _, f := range files {
+		if err := copyDirectory(ctx, filepath.Dir(f), dest, filepath.Base(f)); err != nil {
+			return err
+		}
+		w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("File '%s' uploaded to %s", filepath.Base(f), dest))
+	}
+	return nil
+}
+
+func (w *CurrentWorker) localArtifactDownload(ctx context.Context, workdir string, a sdk.Action) error {
+	dest := strings.TrimSpace(sdk.ParameterValue(a.Parameters, "path"))
+	if !sdk.PathIsAbs(dest) {
+		dest = filepath.Join(workdir, dest)
+	}
+	var reg *regexp.Regexp
+	if pattern := sdk.ParameterValue(a.Parameters, "pattern"); pattern != "" {
+		var err error
+		reg, err = regexp.Compile(pattern)
+		if err != nil {
+			return sdk.NewError(sdk.ErrWorkerErrorCommand, fmt.Errorf("invalid pattern %s, must be a regex: %v", pattern, err))
+		}
+	}
+
+	src := filepath.Join(w.local.Directory, "artifacts")
+	if tag := sdk.ParameterValue(a.Parameters, "tag"); tag != "" {
+		src = filepath.Join(src, url.PathEscape(tag))
+	}
+	var files []string
+	if err := filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		if reg == nil || reg.MatchString(fi.Name()) {
+			files = append(files, path)
+		}
+		return nil
+	}); err != nil && !os.IsNotExist(err) {
+		return sdk.WithStack(err)
+	}
+	if len(files) == 0 {
+		w.SendLog(ctx, workerruntime.LevelInfo, "No artifact downloaded")
+		return nil
+	}
+	for _, f := range files {
+		if err := copyDirectory(ctx, filepath.Dir(f), dest, filepath.Base(f)); err != nil {
+			return err
+		}
+		w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("File '%s' downloaded to %s", filepath.Base(f), dest))
+	}
+	return nil
+}
+
+func (w *CurrentWorker) localCache(ctx context.Context, workdir string, a sdk.Action) ([]sdk.Variable, error) {
+	key, err := action.ComputeCacheKey(ctx, workdir, a)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(w.local.Directory, "cache")
+	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	cachePath := filepath.Join(dir, url.PathEscape(key)+".tar")
+
+	if sdk.ParameterValue(a.Parameters, "mode") == sdk.CacheActionModeSave {
+		if _, err := os.Stat(cachePath); err == nil {
+			w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache '%s' already exists, save skipped", key))
+			return nil, nil
+		}
+		var paths []string
+		for _, p := range strings.Split(sdk.ParameterValue(a.Parameters, "paths"), "\n") {
+			if p = strings.TrimSpace(p); p != "" {
+				paths = append(paths, p)
+			}
+		}
+		f, err := os.Create(cachePath)
+		if err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		if err := sdk.CreateTarFromPaths(afero.NewOsFs(), workdir, paths, f, nil); err != nil {
+			_ = f.Close()
+			_ = os.Remove(cachePath)
+			return nil, sdk.NewError(sdk.ErrWrongRequest, fmt.Errorf("cannot tar (%+v): %v", paths, err))
+		}
+		w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache '%s' saved to %s", key, cachePath))
+		return nil, sdk.WithStack(f.Close())
+	}
+
+	// Look for the exact key then for the most recent cache matching each restore key
+	restoredKey := key
+	if _, err := os.Stat(cachePath); err != nil {
+		cachePath = ""
+		for _, line := range strings.Split(sdk.ParameterValue(a.Parameters, "restoreKeys"), "\n") {
+			if line = strings.TrimSpace(line); line == "" {
+				continue
+			}
+			restoreKey, err := action.RenderCacheKey(workdir, line)
+			if err != nil {
+				return nil, err
+			}
+			matches, _ := filepath.Glob(filepath.Join(dir, url.PathEscape(restoreKey)+"*.tar"))
+			sort.Slice(matches, func(i, j int) bool {
+				fi, _ := os.Stat(matches[i])
+				fj, _ := os.Stat(matches[j])
+				return fi.ModTime().After(fj.ModTime())
+			})
+			if len(matches) > 0 {
+				cachePath = matches[0]
+				restoredKey, _ = url.PathUnescape(strings.TrimSuffix(filepath.Base(cachePath), ".tar"))
+				break
+			}
+		}
+	}
+	if cachePath == "" {
+		w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("No cache found for key '%s'", key))
+		return []sdk.Variable{{Name: "cds.build.cache_hit", Type: sdk.StringVariable, Value: "false"}}, nil
+	}
+
+	f, err := os.Open(cachePath)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	defer f.Close() // nolint
+	if err := action.ExtractCacheArchive(ctx, f, workdir); err != nil {
+		return nil, err
+	}
+	w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Cache '%s' restored", restoredKey))
+	return []sdk.Variable{{Name: "cds.build.cache_hit", Type: sdk.StringVariable, Value: fmt.Sprintf("%t", restoredKey == key)}}, nil
+}
+
+// copyDirectory copies given paths (all the directory if empty) from src to dest.
+func copyDirectory(ctx context.Context, src, dest string, paths ...string) error {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(sdk.CreateTarFromPaths(afero.NewOsFs(), src, paths, pw, nil)) // nolint
+	}()
+	if err := os.MkdirAll(dest, os.FileMode(0755)); err != nil {
+		return sdk.WithStack(err)
+	}
+	if err := action.ExtractCacheArchive(ctx, pr, dest); err != nil {
+		_ = pr.Close()
+		return err
+	}
+	return nil
+}
+
+// CheckConditions checks plain conditions or lua script with given parameters, as for job steps.
+func CheckConditions(conditions sdk.WorkflowNodeConditions, params []sdk.Parameter) (bool, error) {
+	return checkStepConditions(conditions, params)
+}
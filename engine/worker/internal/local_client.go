@@ -0,0 +1,254 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/sguiheux/go-coverage"
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/venom"
+)
+
+// localClient replaces CDS API client when jobs are executed locally. Unit tests, coverage and vulnerability
+// reports are dropped, other calls return an error.
+type localClient struct{}
+
+var _ cdsclient.WorkerInterface = localClient{}
+
+func localNotSupported(name string) error {
+	return sdk.NewErrorFrom(sdk.ErrNotImplemented, "%s is not supported in local mode", name)
+}
+
+func (localClient) CDNItemDownload(context.Context, string, string, sdk.CDNItemType, string, io.WriteSeeker) error {
+	return localNotSupported("CDNItemDownload")
+}
+
+func (localClient) CDNItemStream(context.Context, string, string, sdk.CDNItemType) (io.Reader, error) {
+	return nil, localNotSupported("CDNItemStream")
+}
+
+func (localClient) CDNItemUpload(context.Context, string, string, afero.Fs, string) (time.Duration, error) {
+	return 0, localNotSupported("CDNItemUpload")
+}
+
+func (localClient) PluginAdd(*sdk.GRPCPlugin) error { return localNotSupported("PluginAdd") }
+
+func (localClient) PluginAddBinary(*sdk.GRPCPlugin, *sdk.GRPCPluginBinary) error {
+	return localNotSupported("PluginAddBinary")
+}
+
+func (localClient) PluginDelete(string) error { return localNotSupported("PluginDelete") }
+
+func (localClient) PluginDeleteBinary(string, string, string) error {
+	return localNotSupported("PluginDeleteBinary")
+}
+
+func (localClient) PluginGetBinary(string, string, string, io.Writer) error {
+	return localNotSupported("PluginGetBinary")
+}
+
+func (localClient) PluginGetBinaryInfos(string, string, string) (*sdk.GRPCPluginBinary, error) {
+	return nil, localNotSupported("PluginGetBinaryInfos")
+}
+
+func (localClient) PluginUpdate(*sdk.GRPCPlugin) error { return localNotSupported("PluginUpdate") }
+
+func (localClient) PluginsGet(string) (*sdk.GRPCPlugin, error) {
+	return nil, localNotSupported("PluginsGet")
+}
+
+func (localClient) PluginsList() ([]sdk.GRPCPlugin, error) {
+	return nil, localNotSupported("PluginsList")
+}
+
+func (localClient) ProjectIntegrationGet(string, string, bool) (sdk.ProjectIntegration, error) {
+	return sdk.ProjectIntegration{}, localNotSupported("ProjectIntegrationGet")
+}
+
+func (localClient) QueueArtifactUpload(context.Context, string, string, int64, string, string) (bool, time.Duration, error) {
+	return false, 0, localNotSupported("QueueArtifactUpload")
+}
+
+func (localClient) QueueCountWorkflowNodeJobRun(*time.Time, *time.Time, string, *int) (sdk.WorkflowNodeJobRunCount, error) {
+	return sdk.WorkflowNodeJobRunCount{}, localNotSupported("QueueCountWorkflowNodeJobRun")
+}
+
+func (localClient) QueueJobBook(context.Context, int64) (sdk.WorkflowNodeJobRunBooked, error) {
+	return sdk.WorkflowNodeJobRunBooked{}, localNotSupported("QueueJobBook")
+}
+
+func (localClient) QueueJobGitMirror(context.Context, int64, string) (*sdk.GitMirror, error) {
+	return nil, localNotSupported("QueueJobGitMirror")
+}
+
+func (localClient) QueueJobIDToken(context.Context, int64, sdk.IDTokenRequest) (sdk.IDToken, error) {
+	return sdk.IDToken{}, localNotSupported("QueueJobIDToken")
+}
+
+func (localClient) QueueJobInfo(context.Context, int64) (*sdk.WorkflowNodeJobRun, error) {
+	return nil, localNotSupported("QueueJobInfo")
+}
+
+func (localClient) QueueJobRelease(context.Context, int64) error {
+	return localNotSupported("QueueJobRelease")
+}
+
+func (localClient) QueueJobSendSpawnInfo(context.Context, int64, []sdk.SpawnInfo) error {
+	return localNotSupported("QueueJobSendSpawnInfo")
+}
+
+func (localClient) QueueJobSetVersion(context.Context, int64, sdk.WorkflowRunVersion) error {
+	return localNotSupported("QueueJobSetVersion")
+}
+
+func (localClient) QueueJobTag(context.Context, int64, []sdk.WorkflowRunTag) error {
+	return localNotSupported("QueueJobTag")
+}
+
+func (localClient) QueuePolling(context.Context, *sdk.GoRoutines, chan<- sdk.WorkflowNodeJobRun, chan<- error, time.Duration, ...cdsclient.RequestModifier) error {
+	return localNotSupported("QueuePolling")
+}
+
+func (localClient) QueueSendCoverage(context.Context, int64, coverage.Report) error { return nil }
+
+func (localClient) QueueSendResult(context.Context, int64, sdk.Result) error {
+	return localNotSupported("QueueSendResult")
+}
+
+func (localClient) QueueSendStepResult(context.Context, int64, sdk.StepStatus) error {
+	return localNotSupported("QueueSendStepResult")
+}
+
+func (localClient) QueueSendUnitTests(context.Context, int64, venom.Tests) error { return nil }
+
+func (localClient) QueueSendVulnerability(context.Context, int64, sdk.VulnerabilityWorkerReport) error {
+	return nil
+}
+
+func (localClient) QueueStaticFilesUpload(context.Context, string, string, int64, string, string, string, io.Reader) (string, bool, time.Duration, error) {
+	return "", false, 0, localNotSupported("QueueStaticFilesUpload")
+}
+
+func (localClient) QueueTakeJob(context.Context, sdk.WorkflowNodeJobRun) (*sdk.WorkflowNodeJobRunData, error) {
+	return nil, localNotSupported("QueueTakeJob")
+}
+
+func (localClient) QueueWorkerCacheLink(context.Context, int64, string) (sdk.CDNItemLinks, error) {
+	return sdk.CDNItemLinks{}, localNotSupported("QueueWorkerCacheLink")
+}
+
+func (localClient) QueueWorkerCacheLinks(context.Context, int64, string, []string) (sdk.CDNItemLinks, error) {
+	return sdk.CDNItemLinks{}, localNotSupported("QueueWorkerCacheLinks")
+}
+
+func (localClient) QueueWorkflowNodeJobRun(...cdsclient.RequestModifier) ([]sdk.WorkflowNodeJobRun, error) {
+	return nil, localNotSupported("QueueWorkflowNodeJobRun")
+}
+
+func (localClient) QueueWorkflowRunResultCheck(context.Context, int64, sdk.WorkflowRunResultCheck) (int, error) {
+	return 0, localNotSupported("QueueWorkflowRunResultCheck")
+}
+
+func (localClient) QueueWorkflowRunResultsAdd(context.Context, int64, sdk.WorkflowRunResult) error {
+	return localNotSupported("QueueWorkflowRunResultsAdd")
+}
+
+func (localClient) Requirements() ([]sdk.Requirement, error) {
+	return nil, localNotSupported("Requirements")
+}
+
+func (localClient) ServiceConfigurationGet(context.Context, string) ([]sdk.ServiceConfiguration, error) {
+	return nil, localNotSupported("ServiceConfigurationGet")
+}
+
+func (localClient) WorkerDisable(context.Context, string) error {
+	return localNotSupported("WorkerDisable")
+}
+
+func (localClient) WorkerGet(context.Context, string, ...cdsclient.RequestModifier) (*sdk.Worker, error) {
+	return nil, localNotSupported("WorkerGet")
+}
+
+func (localClient) WorkerList(context.Context) ([]sdk.Worker, error) {
+	return nil, localNotSupported("WorkerList")
+}
+
+func (localClient) WorkerModelAdd(string, string, string, *sdk.ModelDocker, *sdk.ModelVirtualMachine, int64) (sdk.Model, error) {
+	return sdk.Model{}, localNotSupported("WorkerModelAdd")
+}
+
+func (localClient) WorkerModelBook(string, string) error { return localNotSupported("WorkerModelBook") }
+
+func (localClient) WorkerModelDelete(string, string) error {
+	return localNotSupported("WorkerModelDelete")
+}
+
+func (localClient) WorkerModelEnabledList() ([]sdk.Model, error) {
+	return nil, localNotSupported("WorkerModelEnabledList")
+}
+
+func (localClient) WorkerModelGet(string, string) (sdk.Model, error) {
+	return sdk.Model{}, localNotSupported("WorkerModelGet")
+}
+
+func (localClient) WorkerModelList(*cdsclient.WorkerModelFilter) ([]sdk.Model, error) {
+	return nil, localNotSupported("WorkerModelList")
+}
+
+func (localClient) WorkerModelSecretList(string, string) (sdk.WorkerModelSecrets, error) {
+	return sdk.WorkerModelSecrets{}, localNotSupported("WorkerModelSecretList")
+}
+
+func (localClient) WorkerModelSpawnError(string, string, sdk.SpawnErrorForm) error {
+	return localNotSupported("WorkerModelSpawnError")
+}
+
+func (localClient) WorkerRefresh(context.Context) error { return localNotSupported("WorkerRefresh") }
+
+func (localClient) WorkerRegister(context.Context, string, sdk.WorkerRegistrationForm) (*sdk.Worker, bool, error) {
+	return nil, false, localNotSupported("WorkerRegister")
+}
+
+func (localClient) WorkerSetStatus(context.Context, string) error {
+	return localNotSupported("WorkerSetStatus")
+}
+
+func (localClient) WorkerUnregister(context.Context) error {
+	return localNotSupported("WorkerUnregister")
+}
+
+func (localClient) WorkflowCachePull(string, string, string) (io.Reader, error) {
+	return nil, localNotSupported("WorkflowCachePull")
+}
+
+func (localClient) WorkflowCachePush(string, string, string, io.Reader, int) error {
+	return localNotSupported("WorkflowCachePush")
+}
+
+func (localClient) WorkflowNodeRunArtifactDownload(string, string, sdk.WorkflowNodeRunArtifact, io.Writer) error {
+	return localNotSupported("WorkflowNodeRunArtifactDownload")
+}
+
+func (localClient) WorkflowNodeRunRelease(string, string, int64, int64, sdk.WorkflowNodeRunRelease) error {
+	return localNotSupported("WorkflowNodeRunRelease")
+}
+
+func (localClient) WorkflowRunArtifacts(string, string, int64) ([]sdk.WorkflowNodeRunArtifact, error) {
+	return nil, localNotSupported("WorkflowRunArtifacts")
+}
+
+func (localClient) WorkflowRunArtifactsLinks(string, string, int64) (sdk.CDNItemLinks, error) {
+	return sdk.CDNItemLinks{}, localNotSupported("WorkflowRunArtifactsLinks")
+}
+
+func (localClient) WorkflowRunList(string, string, int64, int64) ([]sdk.WorkflowRun, error) {
+	return nil, localNotSupported("WorkflowRunList")
+}
+
+func (localClient) WorkflowRunResultsList(context.Context, string, string, int64) ([]sdk.WorkflowRunResult, error) {
+	return nil, localNotSupported("WorkflowRunResultsList")
+}
@@ -0,0 +1,21 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestLocalClient(t *testing.T) {
+	w := NewLocalWorker("local", nil, LocalOptions{})
+
+	require.NoError(t, w.Client().QueueSendUnitTests(context.TODO(), 1, venom.Tests{}))
+
+	_, err := w.Client().QueueJobInfo(context.TODO(), 1)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrNotImplemented))
+	require.Contains(t, err.Error(), "QueueJobInfo is not supported in local mode")
+}
@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -23,6 +24,7 @@ var requirementCheckFuncs = map[string]func(w *CurrentWorker, r sdk.Requirement)
 	sdk.PluginRequirement:   checkPluginRequirement,
 	sdk.ServiceRequirement:  checkServiceRequirement,
 	sdk.MemoryRequirement:   checkMemoryRequirement,
+	sdk.CPURequirement:      checkCPURequirement,
 	sdk.OSArchRequirement:   checkOSArchRequirement,
 	sdk.RegionRequirement:   checkRegionRequirement,
 }
@@ -149,6 +151,15 @@ func checkServiceRequirement(w *CurrentWorker, r sdk.Requirement) (bool, error)
 	return false, nil
 }
 
+// checkCPURequirement checks that the host has enough CPUs, the limit itself is applied by the hatchery
+func checkCPURequirement(w *CurrentWorker, r sdk.Requirement) (bool, error) {
+	neededCPUs, err := strconv.ParseFloat(r.Value, 64)
+	if err != nil {
+		return false, err
+	}
+	return neededCPUs <= float64(runtime.NumCPU()), nil
+}
+
 func checkMemoryRequirement(w *CurrentWorker, r sdk.Requirement) (bool, error) {
 	var totalMemory int64
 	neededMemory, err := strconv.ParseInt(r.Value, 10, 64)
@@ -17,6 +17,7 @@ import (
 	"github.com/ovh/cds/engine/worker/pkg/workerruntime"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/interpolate"
+	"github.com/ovh/cds/sdk/luascript"
 )
 
 func processVariablesAndParameters(action *sdk.Action, jobParameters []sdk.Parameter, jobSecrets []sdk.Variable) error {
@@ -125,7 +126,7 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 	}
 
 	defer func() {
-		w.gelfLogger.hook.Flush()
+		w.flushLogs()
 		log.Info(ctx, "runJob> end of job %s (%d)", a.Name, jobID)
 	}()
 
@@ -153,7 +154,25 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 			Status:  sdk.StatusNeverBuilt,
 			BuildID: jobID,
 		}
-		if nCriticalFailed == 0 || step.AlwaysExecuted {
+
+		// Conditions are checked with the current job parameters, that contain variables exported by previous steps
+		var conditionsOK = true
+		if step.Enabled && step.Conditions != nil && (nCriticalFailed == 0 || step.AlwaysExecuted) {
+			var err error
+			conditionsOK, err = checkStepConditions(*step.Conditions, w.currentJob.params)
+			if err != nil {
+				w.SendLog(ctx, workerruntime.LevelError, fmt.Sprintf("Unable to check conditions of step %q: %v", w.currentJob.currentStepName, err))
+				stepResult.Status = sdk.StatusFail
+				if !step.Optional {
+					nCriticalFailed++
+				}
+			} else if !conditionsOK {
+				w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Step %q skipped: conditions are not satisfied", w.currentJob.currentStepName))
+				stepResult.Status = sdk.StatusSkipped
+			}
+		}
+
+		if conditionsOK && (nCriticalFailed == 0 || step.AlwaysExecuted) {
 			stepResult = w.runRootAction(ctx, step, jobID, secrets, step.Name)
 
 			// Check if all newVariables are in currentJob.params
@@ -204,11 +223,33 @@ func (w *CurrentWorker) runJob(ctx context.Context, a *sdk.Action, jobID int64,
 	return jobResult
 }
 
+func (w *CurrentWorker) flushLogs() {
+	if w.gelfLogger != nil {
+		w.gelfLogger.hook.Flush()
+	}
+}
+
+// checkStepConditions checks plain conditions or lua script of a step with given parameters
+func checkStepConditions(conditions sdk.WorkflowNodeConditions, params []sdk.Parameter) (bool, error) {
+	if conditions.LuaScript == "" {
+		return sdk.WorkflowCheckConditions(conditions.PlainConditions, params)
+	}
+	luacheck, err := luascript.NewCheck()
+	if err != nil {
+		return false, sdk.WrapError(err, "unable to init lua system")
+	}
+	luacheck.SetVariables(sdk.ParametersToMap(params))
+	if err := luacheck.Perform(conditions.LuaScript); err != nil {
+		return false, err
+	}
+	return luacheck.Result, nil
+}
+
 func (w *CurrentWorker) runRootAction(ctx context.Context, a sdk.Action, jobID int64, secrets []sdk.Variable, actionName string) sdk.Result {
 	w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Starting step %q", actionName))
 	defer func() {
 		w.SendTerminatedStepLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("End of step %q", actionName))
-		w.gelfLogger.hook.Flush()
+		w.flushLogs()
 	}()
 	return w.runAction(ctx, a, jobID, secrets, actionName)
 }
@@ -217,7 +258,7 @@ func (w *CurrentWorker) runSubAction(ctx context.Context, a sdk.Action, jobID in
 	w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Starting sub step %q", actionName))
 	defer func() {
 		w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("End of sub step %q", actionName))
-		w.gelfLogger.hook.Flush()
+		w.flushLogs()
 	}()
 	return w.runAction(ctx, a, jobID, secrets, actionName)
 }
@@ -262,6 +303,13 @@ func (w *CurrentWorker) runAction(ctx context.Context, a sdk.Action, jobID int64
 		res := w.runBuiltin(ctx, a, secrets)
 		return res
 	case sdk.PluginAction:
+		if w.local != nil {
+			w.SendLog(ctx, workerruntime.LevelWarn, fmt.Sprintf("Plugin step %s is skipped on local execution", a.Name))
+			return sdk.Result{
+				Status:  sdk.StatusSkipped,
+				BuildID: jobID,
+			}
+		}
 		res := w.runGRPCPlugin(ctx, a)
 		return res
 	}
@@ -343,6 +391,13 @@ func (w *CurrentWorker) runSteps(ctx context.Context, steps []sdk.Action, a sdk.
 }
 
 func (w *CurrentWorker) updateStepStatus(ctx context.Context, buildID int64, stepOrder int, status string) error {
+	if w.local != nil {
+		if status != sdk.StatusBuilding {
+			w.SendLog(ctx, workerruntime.LevelInfo, fmt.Sprintf("Step %q: %s", w.currentJob.currentStepName, status))
+		}
+		return nil
+	}
+
 	step := sdk.StepStatus{
 		StepOrder: stepOrder,
 		Status:    status,
@@ -581,6 +636,7 @@ func (w *CurrentWorker) ProcessJob(jobInfo sdk.WorkflowNodeJobRunData) (res sdk.
 	}
 	ctx = workerruntime.SetTmpDirectory(ctx, tdFile)
 	log.Debug(ctx, "processJob> Setup tmp directory - %s", tdFile.Name())
+	ctx = workerruntime.SetCacheKeys(ctx, make(map[string]string))
 
 	w.currentJob.context = ctx
 
@@ -31,3 +31,31 @@ func Test_processVariablesAndParameters(t *testing.T) {
 	assert.Equal(t, expectedJobParameters, string(actualJobParameters))
 
 }
+
+func Test_checkStepConditions(t *testing.T) {
+	params := []sdk.Parameter{
+		{Name: "git.branch", Type: sdk.StringParameter, Value: "master"},
+		{Name: "cds.build.version", Type: sdk.StringParameter, Value: "1.2.0"},
+	}
+
+	ok, err := checkStepConditions(sdk.WorkflowNodeConditions{
+		PlainConditions: []sdk.WorkflowNodeCondition{{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorEquals, Value: "master"}},
+	}, params)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = checkStepConditions(sdk.WorkflowNodeConditions{
+		PlainConditions: []sdk.WorkflowNodeCondition{{Variable: "git.branch", Operator: sdk.WorkflowConditionsOperatorEquals, Value: "develop"}},
+	}, params)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = checkStepConditions(sdk.WorkflowNodeConditions{
+		LuaScript: `return git_branch == "master" and cds_build_version == "1.2.0"`,
+	}, params)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = checkStepConditions(sdk.WorkflowNodeConditions{LuaScript: `return git_branch ==`}, params)
+	require.Error(t, err)
+}
@@ -71,6 +71,8 @@ type CurrentWorker struct {
 		Status string `json:"status"`
 	}
 	client cdsclient.WorkerInterface
+	// local is set when jobs are executed without CDS API
+	local *LocalOptions
 }
 
 // BuiltInAction defines builtin action signature
@@ -117,6 +119,10 @@ func (wk *CurrentWorker) FeatureEnabled(name sdk.FeatureName) bool {
 }
 
 func (wk *CurrentWorker) SendTerminatedStepLog(ctx context.Context, level workerruntime.Level, logLine string) {
+	if wk.local != nil {
+		wk.sendLocalLog(level, logLine)
+		return
+	}
 	msg, sign, err := wk.prepareLog(ctx, level, logLine)
 	if err != nil {
 		log.Error(wk.GetContext(), "unable to prepare log: %v", err)
@@ -177,6 +183,10 @@ func (wk *CurrentWorker) RunResultSignature(artifactName string, perm uint32, t
 }
 
 func (wk *CurrentWorker) SendLog(ctx context.Context, level workerruntime.Level, logLine string) {
+	if wk.local != nil {
+		wk.sendLocalLog(level, logLine)
+		return
+	}
 	msg, sign, err := wk.prepareLog(ctx, level, logLine)
 	if err != nil {
 		log.Error(wk.GetContext(), "unable to prepare log: %v", err)
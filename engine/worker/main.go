@@ -21,6 +21,7 @@ func main() {
 	cmd.AddCommand(cmdKey())
 	cmd.AddCommand(cmdJunitParser())
 	cmd.AddCommand(cmdCDSVersionSet())
+	cmd.AddCommand(cmdIDToken())
 	cmd.AddCommand(cmdRunResult())
 
 	// last command: doc, this command is hidden
@@ -0,0 +1,69 @@
+package local
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// container is used to run script steps with docker exec, the workspace is mounted with the same path
+// in the container so scripts and working directories are the same as on the host.
+type container struct {
+	id     string
+	docker string
+}
+
+func startContainer(ctx context.Context, image, workspace string, output io.Writer) (*container, error) {
+	docker, err := exec.LookPath("docker")
+	if err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrNotFound, "docker binary is required to run jobs in a container: %v", err)
+	}
+
+	fmt.Fprintf(output, "Starting container from image %s\n", image) // nolint
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, docker, "run", "--detach", "--rm",
+		"--volume", workspace+":"+workspace,
+		"--workdir", workspace,
+		"--entrypoint", "tail",
+		image, "-f", "/dev/null")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, sdk.NewErrorFrom(sdk.ErrWorkerErrorCommand, "unable to start container from image %s: %v: %s", image, err, strings.TrimSpace(stderr.String()))
+	}
+	return &container{id: strings.TrimSpace(stdout.String()), docker: docker}, nil
+}
+
+func (c *container) stop(ctx context.Context) {
+	_ = exec.CommandContext(ctx, c.docker, "rm", "--force", c.id).Run()
+}
+
+// wrap changes the command to run it in the container. Only the environment variables that are not
+// inherited from the host are given to the container.
+func (c *container) wrap(cmd *exec.Cmd) {
+	host := make(map[string]struct{})
+	for _, e := range os.Environ() {
+		host[strings.SplitN(e, "=", 2)[0]] = struct{}{}
+	}
+
+	args := []string{"docker", "exec", "--interactive", "--workdir", cmd.Dir}
+	for _, e := range cmd.Env {
+		name := strings.SplitN(e, "=", 2)[0]
+		if _, has := host[name]; has {
+			continue
+		}
+		// docker takes the value from its own environment
+		args = append(args, "--env", name)
+	}
+	args = append(args, c.id)
+	args = append(args, cmd.Args...)
+
+	cmd.Path = c.docker
+	cmd.Args = args
+}
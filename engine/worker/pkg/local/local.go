@@ -0,0 +1,171 @@
+// Package local runs pipeline jobs on the local machine with the worker builtin actions, without CDS API.
+package local
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/spf13/afero"
+
+	"github.com/ovh/cds/engine/worker/internal"
+	"github.com/ovh/cds/sdk"
+)
+
+// Options for a local execution
+type Options struct {
+	// Jobs to run, all jobs are executed if empty
+	Jobs []string
+	// Parameters values, pipeline parameters are given by name without the cds.pip prefix
+	Parameters map[string]string
+	// Secrets are available as password variables
+	Secrets map[string]string
+	// Directory where artifacts and cache are stored
+	Directory string
+	// Sources is the directory copied by checkout application steps
+	Sources string
+	// DockerImage is used to run script steps in a container if set
+	DockerImage string
+	// Output receives jobs logs
+	Output io.Writer
+}
+
+// RunPipeline runs the pipeline stage by stage. Jobs of a stage are executed one after the other,
+// variables exported by a job are available for the next jobs.
+func RunPipeline(ctx context.Context, pip sdk.Pipeline, opts Options) error {
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+
+	selected := make(map[string]bool, len(opts.Jobs))
+	for _, name := range opts.Jobs {
+		selected[name] = false
+	}
+	for _, s := range pip.Stages {
+		for _, j := range s.Jobs {
+			if _, has := selected[j.Action.Name]; has {
+				selected[j.Action.Name] = true
+			}
+		}
+	}
+	for name, found := range selected {
+		if !found {
+			return sdk.NewErrorFrom(sdk.ErrNotFound, "job %q not found in pipeline %s", name, pip.Name)
+		}
+	}
+
+	params := parameters(pip, opts.Parameters)
+	secrets := make([]sdk.Variable, 0, len(opts.Secrets))
+	for k, v := range opts.Secrets {
+		secrets = append(secrets, sdk.Variable{Name: k, Type: sdk.SecretVariable, Value: v})
+	}
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+
+	workspace, err := ioutil.TempDir("", "cds-exec-")
+	if err != nil {
+		return sdk.WithStack(err)
+	}
+	defer os.RemoveAll(workspace) // nolint
+
+	localOpts := internal.LocalOptions{
+		Directory: opts.Directory,
+		Sources:   opts.Sources,
+		Output:    opts.Output,
+	}
+	if opts.DockerImage != "" {
+		c, err := startContainer(ctx, opts.DockerImage, workspace, opts.Output)
+		if err != nil {
+			return err
+		}
+		defer c.stop(context.Background())
+		localOpts.CommandWrapper = c.wrap
+	}
+	fs := afero.NewBasePathFs(afero.NewOsFs(), workspace)
+
+	for _, s := range pip.Stages {
+		var jobs []sdk.Job
+		for _, j := range s.Jobs {
+			if _, has := selected[j.Action.Name]; len(selected) == 0 || has {
+				jobs = append(jobs, j)
+			}
+		}
+		if len(jobs) == 0 {
+			continue
+		}
+
+		stageParams := append([]sdk.Parameter{}, params...)
+		sdk.ParameterAddOrSetValue(&stageParams, "cds.stage", sdk.StringParameter, s.Name)
+		if !s.Enabled {
+			fmt.Fprintf(opts.Output, "Stage %q is disabled\n", s.Name) // nolint
+			continue
+		}
+		ok, err := internal.CheckConditions(s.Conditions, stageParams)
+		if err != nil {
+			return sdk.WrapError(err, "unable to check conditions of stage %q", s.Name)
+		}
+		if !ok {
+			fmt.Fprintf(opts.Output, "Stage %q skipped: conditions are not satisfied\n", s.Name) // nolint
+			continue
+		}
+
+		var failed []string
+		for _, j := range jobs {
+			if !j.Enabled {
+				fmt.Fprintf(opts.Output, "Job %q is disabled\n", j.Action.Name) // nolint
+				continue
+			}
+			fmt.Fprintf(opts.Output, "Starting job %q of stage %q\n", j.Action.Name, s.Name) // nolint
+
+			jobParams := append([]sdk.Parameter{}, stageParams...)
+			sdk.ParameterAddOrSetValue(&jobParams, "cds.job", sdk.StringParameter, j.Action.Name)
+
+			wk := internal.NewLocalWorker("local", fs, localOpts)
+			res := wk.ProcessLocalJob(ctx, j, jobParams, secrets)
+			fmt.Fprintf(opts.Output, "Job %q: %s\n", j.Action.Name, res.Status) // nolint
+			if res.Status == sdk.StatusFail {
+				failed = append(failed, j.Action.Name)
+			}
+
+			// Exported variables are available for next jobs
+			for _, v := range res.NewVariables {
+				sdk.ParameterAddOrSetValue(&params, v.Name, v.Type, v.Value)
+			}
+		}
+		if len(failed) > 0 {
+			return sdk.NewErrorFrom(sdk.ErrWorkerErrorCommand, "stage %q failed, failed job(s): %v", s.Name, failed)
+		}
+	}
+
+	return nil
+}
+
+// parameters returns default run parameters with pipeline parameters overridden by given values.
+func parameters(pip sdk.Pipeline, values map[string]string) []sdk.Parameter {
+	params := []sdk.Parameter{
+		{Name: "cds.pipeline", Type: sdk.StringParameter, Value: pip.Name},
+		{Name: "cds.version", Type: sdk.StringParameter, Value: "0"},
+		{Name: "cds.run.number", Type: sdk.StringParameter, Value: "0"},
+	}
+	for _, p := range pip.Parameter {
+		value := p.Value
+		if v, has := values[p.Name]; has {
+			value = v
+		}
+		sdk.ParameterAddOrSetValue(&params, "cds.pip."+p.Name, p.Type, value)
+	}
+
+	names := make([]string, 0, len(values))
+	for k := range values {
+		if sdk.ParameterFind(pip.Parameter, k) == nil {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		sdk.ParameterAddOrSetValue(&params, k, sdk.StringParameter, values[k])
+	}
+	return params
+}
@@ -0,0 +1,87 @@
+package local_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/engine/worker/pkg/local"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func TestRunPipeline(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint
+	sources := filepath.Join(dir, "sources")
+	require.NoError(t, os.MkdirAll(sources, os.ModePerm))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(sources, "file.txt"), []byte("hello"), os.ModePerm))
+
+	payload, err := exportentities.ParsePipeline(exportentities.FormatYAML, []byte(`version: v1.0
+name: build
+parameters:
+  branch:
+    type: string
+    default: master
+stages:
+- Build
+- Test
+jobs:
+- job: Build
+  stage: Build
+  steps:
+  - checkout: '{{.cds.workspace}}'
+  - script:
+    - echo {{.cds.pip.branch}} {{.cds.proj.password}}
+    - cp file.txt out.txt
+  - artifactUpload:
+      path: out.txt
+      tag: '{{.cds.version}}'
+- job: Test
+  stage: Test
+  steps:
+  - artifactDownload:
+      path: '{{.cds.workspace}}'
+      tag: '{{.cds.version}}'
+  - script:
+    - test "$(cat out.txt)" = "hello"
+    - exit {{.cds.pip.code}}`))
+	require.NoError(t, err)
+	pip, err := payload.Pipeline()
+	require.NoError(t, err)
+
+	run := func(t *testing.T, code string, jobs ...string) (string, error) {
+		var out bytes.Buffer
+		err := local.RunPipeline(context.TODO(), *pip, local.Options{
+			Jobs:       jobs,
+			Parameters: map[string]string{"branch": "develop", "cds.pip.code": code},
+			Secrets:    map[string]string{"cds.proj.password": "s3cr3t"},
+			Directory:  filepath.Join(dir, "store"),
+			Sources:    sources,
+			Output:     &out,
+		})
+		t.Log(out.String())
+		return out.String(), err
+	}
+
+	out, err := run(t, "0")
+	require.NoError(t, err)
+	assert.Contains(t, out, "develop **********")
+	assert.NotContains(t, out, "s3cr3t")
+	assert.FileExists(t, filepath.Join(dir, "store", "artifacts", "0", "out.txt"))
+
+	_, err = run(t, "1", "Test")
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrWorkerErrorCommand))
+
+	_, err = run(t, "0", "Unknown")
+	require.Error(t, err)
+	assert.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+}
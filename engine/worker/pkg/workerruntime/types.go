@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os/exec"
 
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/rockbears/log"
@@ -65,6 +66,8 @@ const (
 	workDir
 	keysDir
 	tmpDir
+	commandWrapper
+	cacheKeys
 )
 
 type Runtime interface {
@@ -173,3 +176,27 @@ func SetTmpDirectory(ctx context.Context, s afero.File) context.Context {
 	log.Debug(ctx, "SetTmpDirectory> working directory is: %s", s.Name())
 	return context.WithValue(ctx, tmpDir, s)
 }
+
+// CommandWrapperFunc can modify a command before it is started by a script step,
+// it is used to run steps in a container when a job is executed locally.
+type CommandWrapperFunc func(cmd *exec.Cmd)
+
+func CommandWrapper(ctx context.Context) CommandWrapperFunc {
+	f, _ := ctx.Value(commandWrapper).(CommandWrapperFunc)
+	return f
+}
+
+func SetCommandWrapper(ctx context.Context, f CommandWrapperFunc) context.Context {
+	return context.WithValue(ctx, commandWrapper, f)
+}
+
+// CacheKeys returns the keys computed by the cache steps of the current job, indexed by key template.
+// The keys computed when a cache is restored are used to save it at the end of the job.
+func CacheKeys(ctx context.Context) map[string]string {
+	m, _ := ctx.Value(cacheKeys).(map[string]string)
+	return m
+}
+
+func SetCacheKeys(ctx context.Context, m map[string]string) context.Context {
+	return context.WithValue(ctx, cacheKeys, m)
+}
@@ -27,8 +27,13 @@ const (
 	PushBuildInfo             = "PushBuildInfo"
 	InstallKeyAction          = "InstallKey"
 	ReleaseAction             = "Release"
+	CacheAction               = "Cache"
 
 	DefaultGitCloneParameterTagValue = "{{.git.tag}}"
+
+	// Cache action modes, a cache step restores the cache, the cache is saved by a step added at the end of the job
+	CacheActionModeRestore = "restore"
+	CacheActionModeSave    = "save"
 )
 
 // NewAction instantiate a new Action
@@ -52,6 +57,8 @@ type Action struct {
 	StepName       string `json:"step_name,omitempty" yaml:"step_name,omitempty" db:"-"`
 	Optional       bool   `json:"optional" yaml:"-" db:"-"`
 	AlwaysExecuted bool   `json:"always_executed" yaml:"-" db:"-"`
+	// Conditions are only used for job steps, the step is skipped if they are not satisfied
+	Conditions *WorkflowNodeConditions `json:"conditions,omitempty" yaml:"-" db:"-"`
 	// aggregates
 	Requirements RequirementList `json:"requirements" db:"-"`
 	Parameters   []Parameter     `json:"parameters" db:"-"`
@@ -15,6 +15,7 @@ var List = []Manifest{
 	ArtifactDownload,
 	ArtifactUpload,
 	PushBuildInfo,
+	Cache,
 	CheckoutApplication,
 	Coverage,
 	DeployApplication,
@@ -0,0 +1,63 @@
+package action
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// Cache action definition.
+var Cache = Manifest{
+	Action: sdk.Action{
+		Name: sdk.CacheAction,
+		Description: `CDS Builtin Action.
+Restore a worker cache in the workspace, the cache is saved at the end of the job if all the steps succeeded.
+
+The key can be computed from files with the hashFiles function, example: go-{{hashFiles "go.sum"}}. Patterns can use ** to match files in any directory, example: npm-{{hashFiles "**/package-lock.json"}}.
+The key is computed when the cache is restored, the same key is used to save the cache.
+If there is no cache for the key, restore keys are used in the given order to restore the most recent cache with a key starting with the restore key.
+A cache is immutable, nothing is saved if a cache already exists for the key.
+`,
+		Parameters: []sdk.Parameter{
+			{
+				Name:        "key",
+				Type:        sdk.StringParameter,
+				Description: `Key of the cache, example: go-{{hashFiles "go.sum"}}.`,
+			},
+			{
+				Name:        "restoreKeys",
+				Type:        sdk.TextParameter,
+				Description: "(optional) Key prefixes used to restore a cache if there is no cache for the key, one by line.",
+			},
+			{
+				Name:        "paths",
+				Type:        sdk.TextParameter,
+				Description: "Paths to save in the cache, one by line.",
+			},
+			{
+				Name:        "mode",
+				Type:        sdk.StringParameter,
+				Description: "restore or save, a save step is added at the end of the job for each cache step.",
+				Value:       sdk.CacheActionModeRestore,
+				Advanced:    true,
+			},
+		},
+	},
+	Example: exportentities.PipelineV1{
+		Version: exportentities.PipelineVersion1,
+		Name:    "Pipeline1",
+		Stages:  []string{"Stage1"},
+		Jobs: []exportentities.Job{{
+			Name:  "Job1",
+			Stage: "Stage1",
+			Steps: []exportentities.Step{
+				{
+					Cache: &exportentities.StepCache{
+						Key:         `go-{{hashFiles "go.sum"}}`,
+						RestoreKeys: []string{"go-"},
+						Paths:       []string{"vendor"},
+					},
+				},
+			},
+		}},
+	},
+}
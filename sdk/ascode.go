@@ -4,9 +4,109 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"path"
+	"strings"
 	"time"
 )
 
+// AsCodeDir is the name of the directory that contains as code files, at the root of a repository
+// or of each workflow root in a monorepo.
+const AsCodeDir = ".cds"
+
+// AsCodeRootSeparator separates the repository url from the workflow root in the from_repository value of
+// entities imported from a sub directory of a repository.
+const AsCodeRootSeparator = "#"
+
+// NewAsCodeFromRepository returns the from_repository value of entities imported from given root of a repository.
+func NewAsCodeFromRepository(repositoryURL, root string) string {
+	if root == "" {
+		return repositoryURL
+	}
+	return repositoryURL + AsCodeRootSeparator + root
+}
+
+// SplitAsCodeFromRepository returns the repository url and the workflow root of a from_repository value.
+func SplitAsCodeFromRepository(fromRepository string) (string, string) {
+	i := strings.LastIndex(fromRepository, AsCodeRootSeparator)
+	if i < 0 {
+		return fromRepository, ""
+	}
+	return fromRepository[:i], fromRepository[i+1:]
+}
+
+// AsCodeFilesPattern returns the pattern of the as code files of a workflow root, root can be a glob to match
+// several workflow roots.
+func AsCodeFilesPattern(root string) string {
+	return path.Join(root, AsCodeDir, "**", "*.yml")
+}
+
+// AsCodeRootFromPattern returns the workflow roots glob of an as code files pattern, it returns an error if the
+// pattern is not valid.
+func AsCodeRootFromPattern(pattern string) (string, error) {
+	suffix := AsCodeFilesPattern("")
+	if pattern == suffix {
+		return "", nil
+	}
+	root := strings.TrimSuffix(pattern, "/"+suffix)
+	if root == pattern || root == "" || path.IsAbs(root) || path.Clean(root) != root || strings.HasPrefix(root, "..") ||
+		strings.Contains(root, AsCodeRootSeparator) {
+		return "", NewErrorFrom(ErrWrongRequest, "invalid as code pattern %q, it should be like %q", pattern, AsCodeFilesPattern("services/*"))
+	}
+	return root, nil
+}
+
+// AsCodeRootFromFile returns the workflow root of an as code file path.
+func AsCodeRootFromFile(file string) string {
+	if strings.HasPrefix(file, AsCodeDir+"/") {
+		return ""
+	}
+	i := strings.Index(file, "/"+AsCodeDir+"/")
+	if i < 0 {
+		return path.Dir(file)
+	}
+	return file[:i]
+}
+
+// AsCodeDiffEntry kinds.
+const (
+	AsCodeDiffKindNode        = "node"
+	AsCodeDiffKindHook        = "hook"
+	AsCodeDiffKindPermission  = "permission"
+	AsCodeDiffKindIntegration = "integration"
+	AsCodeDiffKindPipeline    = "pipeline"
+)
+
+// AsCodeDiffEntry changes.
+const (
+	AsCodeDiffAdded    = "added"
+	AsCodeDiffRemoved  = "removed"
+	AsCodeDiffModified = "modified"
+)
+
+// AsCodeDiffEntry is a structural change between the current version of an as code workflow and the version
+// from a branch.
+type AsCodeDiffEntry struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Change string `json:"change"`
+}
+
+// AsCodeDiff is the list of structural changes of an as code workflow.
+type AsCodeDiff []AsCodeDiffEntry
+
+// Markdown returns the diff as a markdown table.
+func (d AsCodeDiff) Markdown() string {
+	if len(d) == 0 {
+		return "No structural change."
+	}
+	var b strings.Builder
+	b.WriteString("| Kind | Name | Change |\n|---|---|---|\n")
+	for _, e := range d {
+		b.WriteString("| " + e.Kind + " | " + e.Name + " | " + e.Change + " |\n")
+	}
+	return b.String()
+}
+
 type AsCodeEvent struct {
 	ID             int64           `json:"id" db:"id"`
 	WorkflowID     int64           `json:"workflow_id" db:"workflow_id"`
@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsCodeFromRepository(t *testing.T) {
+	require.Equal(t, "https://github.com/ovh/cds.git", NewAsCodeFromRepository("https://github.com/ovh/cds.git", ""))
+	from := NewAsCodeFromRepository("https://github.com/ovh/cds.git", "services/api")
+	require.Equal(t, "https://github.com/ovh/cds.git#services/api", from)
+
+	url, root := SplitAsCodeFromRepository(from)
+	require.Equal(t, "https://github.com/ovh/cds.git", url)
+	require.Equal(t, "services/api", root)
+
+	url, root = SplitAsCodeFromRepository("git@github.com:ovh/cds.git")
+	require.Equal(t, "git@github.com:ovh/cds.git", url)
+	require.Equal(t, "", root)
+}
+
+func TestAsCodeRootFromPattern(t *testing.T) {
+	root, err := AsCodeRootFromPattern(".cds/**/*.yml")
+	require.NoError(t, err)
+	require.Equal(t, "", root)
+
+	root, err = AsCodeRootFromPattern("services/*/.cds/**/*.yml")
+	require.NoError(t, err)
+	require.Equal(t, "services/*", root)
+	require.Equal(t, "services/*/.cds/**/*.yml", AsCodeFilesPattern(root))
+
+	for _, p := range []string{"**/*.yml", "/services/.cds/**/*.yml", "../services/.cds/**/*.yml", "services/.cds/*.yml", "a#b/.cds/**/*.yml"} {
+		_, err := AsCodeRootFromPattern(p)
+		require.Error(t, err, p)
+	}
+}
+
+func TestAsCodeRootFromFile(t *testing.T) {
+	require.Equal(t, "", AsCodeRootFromFile(".cds/my.yml"))
+	require.Equal(t, "services/api", AsCodeRootFromFile("services/api/.cds/my.yml"))
+	require.Equal(t, "services/api", AsCodeRootFromFile("services/api/.cds/sub/my.yml"))
+}
@@ -2,9 +2,15 @@ package sdk
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
 	"time"
 
 	"github.com/go-gorp/gorp"
+	"github.com/pkg/errors"
 )
 
 // Different type of Audit event
@@ -32,6 +38,34 @@ type AuditWorkflow struct {
 	DataAfter  string `json:"data_after" db:"data_after"`
 }
 
+// Event types for secret reference audits.
+const (
+	AuditSecretReferenceRead  = "read"
+	AuditSecretReferenceError = "error"
+)
+
+// AuditSecretReference represents the read of a secret reference by a job, the secret value is never stored.
+type AuditSecretReference struct {
+	AuditCommon
+	ProjectID            int64  `json:"project_id" db:"project_id"`
+	ProjectIntegrationID int64  `json:"project_integration_id" db:"project_integration_id"`
+	WorkflowRunID        int64  `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeJobRunID int64  `json:"workflow_node_run_job_id" db:"workflow_node_run_job_id"`
+	VariableName         string `json:"variable_name" db:"variable_name"`
+	Path                 string `json:"path" db:"path"`
+}
+
+// AuditGroupSync represents a change of group membership made by the synchronisation with an auth driver.
+type AuditGroupSync struct {
+	AuditCommon
+	GroupID            int64            `json:"group_id" db:"group_id"`
+	GroupName          string           `json:"group_name" db:"group_name"`
+	AuthentifiedUserID string           `json:"authentified_user_id" db:"authentified_user_id"`
+	Username           string           `json:"username" db:"username"`
+	ConsumerType       AuthConsumerType `json:"consumer_type" db:"consumer_type"`
+	ExternalGroups     string           `json:"external_groups" db:"external_groups"`
+}
+
 // Audit represents audit interface.
 type Audit interface {
 	Compute(ctx context.Context, db gorp.SqlExecutor, e Event) error
@@ -63,3 +97,95 @@ type AuditAction struct {
 	DataBefore string `json:"data_before" db:"data_before"`
 	DataAfter  string `json:"data_after" db:"data_after"`
 }
+
+// AuditLog is a record of the unified security audit log, one record is written for each mutating API call.
+// Records are chained: the hash of a record is a HMAC computed from its content and from the hash of the previous one,
+// with a key that is not stored in the database.
+type AuditLog struct {
+	ID                 int64          `json:"id" db:"id" cli:"id,key"`
+	Created            time.Time      `json:"created" db:"created" cli:"created"`
+	RequestID          string         `json:"request_id" db:"request_id" cli:"-"`
+	AuthConsumerID     string         `json:"auth_consumer_id,omitempty" db:"auth_consumer_id" cli:"-"`
+	AuthConsumerName   string         `json:"auth_consumer_name,omitempty" db:"auth_consumer_name" cli:"consumer"`
+	AuthentifiedUserID string         `json:"authentified_user_id,omitempty" db:"authentified_user_id" cli:"-"`
+	Username           string         `json:"username,omitempty" db:"username" cli:"username"`
+	IPAddress          string         `json:"ip_address" db:"ip_address" cli:"ip_address"`
+	Method             string         `json:"method" db:"method" cli:"method"`
+	Route              string         `json:"route" db:"route" cli:"route"`
+	Handler            string         `json:"handler" db:"handler" cli:"-"`
+	Target             AuditLogTarget `json:"target" db:"target" cli:"target"`
+	Status             int            `json:"status" db:"status" cli:"status"`
+	PreviousHash       string         `json:"previous_hash" db:"previous_hash" cli:"-"`
+	Hash               string         `json:"hash" db:"hash" cli:"-"`
+}
+
+// ComputeHash returns the HMAC-SHA256 with given key of the audit log content chained with its previous hash.
+func (a AuditLog) ComputeHash(key []byte) string {
+	content, _ := json.Marshal([]interface{}{
+		a.PreviousHash,
+		a.Created.UTC().Format(time.RFC3339Nano),
+		a.RequestID,
+		a.AuthConsumerID,
+		a.AuthConsumerName,
+		a.AuthentifiedUserID,
+		a.Username,
+		a.IPAddress,
+		a.Method,
+		a.Route,
+		a.Handler,
+		a.Target,
+		a.Status,
+	})
+	mac := hmac.New(sha256.New, key)
+	mac.Write(content) // nolint
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyAuditLogs checks the hash of each given audit log and the chain between consecutive logs.
+// Logs should be ordered by id and not filtered, given key is the one used by the API to compute the hashes.
+func VerifyAuditLogs(logs []AuditLog, key []byte) error {
+	for i := range logs {
+		if !hmac.Equal([]byte(logs[i].Hash), []byte(logs[i].ComputeHash(key))) {
+			return NewErrorFrom(ErrWrongRequest, "invalid hash for audit log %d", logs[i].ID)
+		}
+		if i > 0 && logs[i].PreviousHash != logs[i-1].Hash {
+			return NewErrorFrom(ErrWrongRequest, "broken chain between audit logs %d and %d", logs[i-1].ID, logs[i].ID)
+		}
+	}
+	return nil
+}
+
+// AuditLogTarget contains the entities targeted by an audited request, ie. route variables like project_key.
+type AuditLogTarget map[string]string
+
+// Value returns driver.Value from audit log target.
+func (a AuditLogTarget) Value() (driver.Value, error) {
+	j, err := json.Marshal(a)
+	return j, WrapError(err, "cannot marshal AuditLogTarget")
+}
+
+// Scan audit log target.
+func (a *AuditLogTarget) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(JSONUnmarshal(source, a), "cannot unmarshal AuditLogTarget")
+}
+
+// AuditLogFilter is used to query the audit log.
+type AuditLogFilter struct {
+	Since      *time.Time
+	Until      *time.Time
+	Username   string
+	ConsumerID string
+	Method     string
+	Route      string
+	Target     map[string]string
+	AfterID    int64
+	Limit      int64
+	Offset     int64
+}
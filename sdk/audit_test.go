@@ -0,0 +1,49 @@
+package sdk_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestVerifyAuditLogs(t *testing.T) {
+	key := []byte("my-audit-key")
+	now := time.Now()
+	var logs []sdk.AuditLog
+	for i := 0; i < 3; i++ {
+		l := sdk.AuditLog{
+			ID:       int64(i + 1),
+			Created:  now.Add(time.Duration(i) * time.Second),
+			Username: "admin",
+			Method:   "POST",
+			Route:    "/project/<project-key>/group",
+			Target:   sdk.AuditLogTarget{"project_key": "MYPROJ"},
+			Status:   201,
+		}
+		if i > 0 {
+			l.PreviousHash = logs[i-1].Hash
+		}
+		l.Hash = l.ComputeHash(key)
+		logs = append(logs, l)
+	}
+	require.NoError(t, sdk.VerifyAuditLogs(logs, key))
+
+	// Hashes can't be computed again without the key
+	require.Error(t, sdk.VerifyAuditLogs(logs, nil))
+	require.Error(t, sdk.VerifyAuditLogs(logs, []byte("another-key")))
+
+	// Created is stored in UTC by the database, the hash should not change
+	utc := logs[1]
+	utc.Created = utc.Created.UTC()
+	require.Equal(t, logs[1].Hash, utc.ComputeHash(key))
+
+	tampered := append([]sdk.AuditLog{}, logs...)
+	tampered[1].Username = "someone"
+	require.Error(t, sdk.VerifyAuditLogs(tampered, key))
+
+	removed := []sdk.AuditLog{logs[0], logs[2]}
+	require.Error(t, sdk.VerifyAuditLogs(removed, key))
+}
@@ -18,6 +18,7 @@ type Cache struct {
 	Project         string `json:"project"`
 	Name            string `json:"name" cli:"name"`
 	Tag             string `json:"tag"`
+	TagEncoded      bool   `json:"tag_encoded,omitempty"`
 	TmpURL          string `json:"tmp_url"`
 	SecretKey       string `json:"secret_key"`
 	IntegrationName string `json:"integration_name"`
@@ -30,6 +30,7 @@ type CDNItem struct {
 	Size         int64           `json:"size" db:"size"`
 	MD5          string          `json:"md5" db:"md5"`
 	ToDelete     bool            `json:"to_delete" db:"to_delete"`
+	LastAccess   *time.Time      `json:"last_access,omitempty" db:"last_access"`
 }
 
 type CDNItemLinks struct {
@@ -163,6 +164,12 @@ type CDNRunResultAPIRef struct {
 	RunResultType WorkflowRunResultType `json:"type"`
 }
 
+// CDNProvenanceUpload is sent by the API to store a provenance attestation in CDN.
+type CDNProvenanceUpload struct {
+	Signature cdn.Signature `json:"signature"`
+	Content   []byte        `json:"content"`
+}
+
 type CDNWorkerCacheAPIRef struct {
 	ProjectKey string    `json:"project_key"`
 	CacheTag   string    `json:"cache_tag"`
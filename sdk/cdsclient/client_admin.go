@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 
@@ -322,3 +323,22 @@ func (c *client) AdminWorkflowUpdateMaxRuns(projectKey string, workflowName stri
 	}
 	return nil
 }
+
+func (c *client) AdminAuditLogs(mods ...RequestModifier) ([]sdk.AuditLog, error) {
+	var res []sdk.AuditLog
+	if _, err := c.GetJSON(context.Background(), "/admin/audit", &res, mods...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *client) AdminAuditLogsExport(ctx context.Context, w io.Writer, mods ...RequestModifier) error {
+	reader, _, _, err := c.Stream(ctx, c.HTTPNoTimeoutClient(), http.MethodGet, "/admin/audit/export", nil, mods...)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	_, err = io.Copy(w, reader)
+	return err
+}
@@ -46,3 +46,18 @@ func (c *client) ApplicationAttachToReposistoriesManager(projectKey, appName, re
 	_, _, _, err := c.Request(context.Background(), "POST", uri, nil)
 	return err
 }
+
+func (c *client) ApplicationPreviewEnvironmentList(key string, appName string) ([]sdk.PreviewEnvironment, error) {
+	previews := []sdk.PreviewEnvironment{}
+	if _, err := c.GetJSON(context.Background(), "/project/"+key+"/application/"+appName+"/preview", &previews); err != nil {
+		return nil, err
+	}
+	return previews, nil
+}
+
+func (c *client) ApplicationPreviewEnvironmentDelete(key string, appName string, workflowName string, pullRequestID int64) error {
+	if _, err := c.DeleteJSON(context.Background(), fmt.Sprintf("/project/%s/application/%s/preview/%s/%d", key, appName, workflowName, pullRequestID), nil); err != nil {
+		return err
+	}
+	return nil
+}
@@ -24,3 +24,24 @@ func (c *client) ApplicationKeysDelete(projectKey string, appName string, keyNam
 	_, _, _, err := c.Request(context.Background(), "DELETE", "/project/"+projectKey+"/application/"+appName+"/keys/"+url.QueryEscape(keyName), nil)
 	return err
 }
+
+func (c *client) ApplicationKeyRotate(projectKey string, appName string, keyName string, req sdk.KeyRotationRequest) (sdk.ApplicationKey, error) {
+	var k sdk.ApplicationKey
+	_, err := c.PostJSON(context.Background(), "/project/"+projectKey+"/application/"+appName+"/keys/"+url.QueryEscape(keyName)+"/rotate", req, &k)
+	return k, err
+}
+
+func (c *client) ApplicationKeyUsage(projectKey string, appName string, keyName string) (sdk.KeyUsage, error) {
+	var u sdk.KeyUsage
+	_, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/application/"+appName+"/keys/"+url.QueryEscape(keyName)+"/usage", &u)
+	return u, err
+}
+
+func (c *client) ApplicationKeyRetire(projectKey string, appName string, keyName string, force bool) error {
+	path := "/project/" + projectKey + "/application/" + appName + "/keys/" + url.QueryEscape(keyName) + "/retire"
+	if force {
+		path += "?force=true"
+	}
+	_, err := c.PostJSON(context.Background(), path, nil, nil)
+	return err
+}
@@ -36,3 +36,18 @@ func (c *client) EnvironmentList(key string) ([]sdk.Environment, error) {
 	}
 	return envs, nil
 }
+
+func (c *client) EnvironmentProtectionGet(key string, envName string) (sdk.EnvironmentProtection, error) {
+	var protection sdk.EnvironmentProtection
+	if _, err := c.GetJSON(context.Background(), "/project/"+key+"/environment/"+url.QueryEscape(envName)+"/protection", &protection); err != nil {
+		return protection, err
+	}
+	return protection, nil
+}
+
+func (c *client) EnvironmentProtectionUpdate(key string, envName string, protection sdk.EnvironmentProtection) error {
+	if _, err := c.PutJSON(context.Background(), "/project/"+key+"/environment/"+url.QueryEscape(envName)+"/protection", protection, nil); err != nil {
+		return err
+	}
+	return nil
+}
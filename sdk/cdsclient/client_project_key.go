@@ -24,3 +24,30 @@ func (c *client) ProjectKeysDelete(projectKey string, keyName string) error {
 	_, _, _, err := c.Request(context.Background(), "DELETE", "/project/"+projectKey+"/keys/"+url.QueryEscape(keyName), nil)
 	return err
 }
+
+func (c *client) ProjectKeyRotate(projectKey string, keyName string, req sdk.KeyRotationRequest) (sdk.ProjectKey, error) {
+	var k sdk.ProjectKey
+	_, err := c.PostJSON(context.Background(), "/project/"+projectKey+"/keys/"+url.QueryEscape(keyName)+"/rotate", req, &k)
+	return k, err
+}
+
+func (c *client) ProjectKeyUsage(projectKey string, keyName string) (sdk.KeyUsage, error) {
+	var u sdk.KeyUsage
+	_, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/keys/"+url.QueryEscape(keyName)+"/usage", &u)
+	return u, err
+}
+
+func (c *client) ProjectKeyRetire(projectKey string, keyName string, force bool) error {
+	path := "/project/" + projectKey + "/keys/" + url.QueryEscape(keyName) + "/retire"
+	if force {
+		path += "?force=true"
+	}
+	_, err := c.PostJSON(context.Background(), path, nil, nil)
+	return err
+}
+
+func (c *client) ProjectProvenanceKeyGet(projectKey string) (sdk.ProjectProvenanceKey, error) {
+	var k sdk.ProjectProvenanceKey
+	_, err := c.GetJSON(context.Background(), "/project/"+projectKey+"/provenance/key", &k)
+	return k, err
+}
@@ -188,6 +188,24 @@ func (c *client) QueueJobInfo(ctx context.Context, id int64) (*sdk.WorkflowNodeJ
 	return &job, nil
 }
 
+// QueueJobGitMirror returns the mirror of a repository that a job can clone from, through the API.
+// The request is not retried, a job without mirror clones from the origin.
+func (c *client) QueueJobGitMirror(ctx context.Context, jobID int64, repoURL string) (*sdk.GitMirror, error) {
+	path := fmt.Sprintf("/queue/workflows/%d/mirror?url=%s", jobID, url.QueryEscape(repoURL))
+	body, _, _, err := c.StreamNoRetry(ctx, c.HTTPClient(), http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close() // nolint
+	var m sdk.GitMirror
+	if err := json.NewDecoder(body).Decode(&m); err != nil {
+		return nil, newError(err)
+	}
+	m.URL = fmt.Sprintf("%s/queue/workflows/%d/mirror/%s", c.APIURL(), jobID, m.ID)
+	m.Authorization = "Bearer " + c.config.SessionToken
+	return &m, nil
+}
+
 // QueueJobSendSpawnInfo sends a spawn info on a job
 func (c *client) QueueJobSendSpawnInfo(ctx context.Context, id int64, in []sdk.SpawnInfo) error {
 	path := fmt.Sprintf("/queue/workflows/%d/spawn/infos", id)
@@ -494,6 +512,20 @@ func (c *client) QueueWorkerCacheLink(ctx context.Context, jobID int64, tag stri
 	return result, err
 }
 
+func (c *client) QueueWorkerCacheLinks(ctx context.Context, jobID int64, key string, restoreKeys []string) (sdk.CDNItemLinks, error) {
+	var result sdk.CDNItemLinks
+	path := fmt.Sprintf("/queue/workflows/%d/cache/links", jobID)
+	_, err := c.GetJSON(ctx, path, &result, func(r *http.Request) {
+		q := r.URL.Query()
+		q.Set("key", key)
+		for _, k := range restoreKeys {
+			q.Add("restoreKey", k)
+		}
+		r.URL.RawQuery = q.Encode()
+	})
+	return result, err
+}
+
 func (c *client) QueueJobTag(ctx context.Context, jobID int64, tags []sdk.WorkflowRunTag) error {
 	path := fmt.Sprintf("/queue/workflows/%d/tag", jobID)
 	_, err := c.PostJSON(ctx, path, tags, nil)
@@ -506,6 +538,13 @@ func (c *client) QueueJobSetVersion(ctx context.Context, jobID int64, version sd
 	return err
 }
 
+func (c *client) QueueJobIDToken(ctx context.Context, jobID int64, req sdk.IDTokenRequest) (sdk.IDToken, error) {
+	var token sdk.IDToken
+	path := fmt.Sprintf("/queue/workflows/%d/idtoken", jobID)
+	_, err := c.PostJSON(ctx, path, req, &token)
+	return token, err
+}
+
 //  STATIC FILES -----
 
 func (c *client) QueueStaticFilesUpload(ctx context.Context, projectKey, integrationName string, nodeJobRunID int64, name, entrypoint, staticKey string, tarContent io.Reader) (string, bool, time.Duration, error) {
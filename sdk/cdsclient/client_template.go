@@ -151,3 +151,36 @@ func (c *client) TemplateDeleteInstance(groupName, templateSlug string, id int64
 
 	return nil
 }
+
+func (c *client) TemplateGetReleases(groupName, templateSlug string) ([]sdk.WorkflowTemplateRelease, error) {
+	url := fmt.Sprintf("/template/%s/%s/release", groupName, templateSlug)
+
+	var rs []sdk.WorkflowTemplateRelease
+	if _, err := c.GetJSON(context.Background(), url, &rs); err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+func (c *client) TemplateRelease(groupName, templateSlug string, release sdk.WorkflowTemplateRelease) (*sdk.WorkflowTemplateRelease, error) {
+	url := fmt.Sprintf("/template/%s/%s/release", groupName, templateSlug)
+
+	var res sdk.WorkflowTemplateRelease
+	if _, err := c.PostJSON(context.Background(), url, release, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+func (c *client) TemplateUpgradeInstance(groupName, templateSlug string, id int64, req sdk.WorkflowTemplateUpgradeRequest) (*sdk.WorkflowTemplateUpgradeResult, error) {
+	url := fmt.Sprintf("/template/%s/%s/instance/%d/upgrade", groupName, templateSlug, id)
+
+	var res sdk.WorkflowTemplateUpgradeResult
+	if _, err := c.PostJSON(context.Background(), url, req, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
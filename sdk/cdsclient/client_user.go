@@ -2,6 +2,7 @@ package cdsclient
 
 import (
 	"context"
+	"fmt"
 	"net/url"
 
 	"github.com/ovh/cds/sdk"
@@ -70,3 +71,21 @@ func (c *client) UserGetSchema() (sdk.SchemaResponse, error) {
 	}
 	return res, nil
 }
+
+func (c *client) UserNotificationSubscriptionList(username string) ([]sdk.UserNotificationSubscription, error) {
+	res := []sdk.UserNotificationSubscription{}
+	if _, err := c.GetJSON(context.Background(), "/user/"+url.QueryEscape(username)+"/notification/subscription", &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *client) UserNotificationSubscriptionCreate(username string, s *sdk.UserNotificationSubscription) error {
+	_, err := c.PostJSON(context.Background(), "/user/"+url.QueryEscape(username)+"/notification/subscription", s, s)
+	return err
+}
+
+func (c *client) UserNotificationSubscriptionDelete(username string, id int64) error {
+	_, err := c.DeleteJSON(context.Background(), fmt.Sprintf("/user/%s/notification/subscription/%d", url.QueryEscape(username), id), nil)
+	return err
+}
@@ -345,6 +345,24 @@ func (c *client) WorkflowStop(projectKey string, workflowName string, number int
 	return run, nil
 }
 
+func (c *client) WorkflowNodeRunApprove(projectKey string, workflowName string, number, nodeRunID int64, comment string) (*sdk.WorkflowNodeRun, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/approve", projectKey, workflowName, number, nodeRunID)
+	var nodeRun sdk.WorkflowNodeRun
+	if _, err := c.PostJSON(context.Background(), url, sdk.WorkflowNodeRunApprovalRequest{Comment: comment}, &nodeRun); err != nil {
+		return nil, err
+	}
+	return &nodeRun, nil
+}
+
+func (c *client) WorkflowNodeRunReject(projectKey string, workflowName string, number, nodeRunID int64, comment string) (*sdk.WorkflowNodeRun, error) {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/reject", projectKey, workflowName, number, nodeRunID)
+	var nodeRun sdk.WorkflowNodeRun
+	if _, err := c.PostJSON(context.Background(), url, sdk.WorkflowNodeRunApprovalRequest{Comment: comment}, &nodeRun); err != nil {
+		return nil, err
+	}
+	return &nodeRun, nil
+}
+
 func (c *client) WorkflowNodeStop(projectKey string, workflowName string, number, fromNodeID int64) (*sdk.WorkflowNodeRun, error) {
 	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/nodes/%d/stop", projectKey, workflowName, number, fromNodeID)
 
@@ -35,6 +35,9 @@ type TemplateClient interface {
 	TemplateDelete(groupName, templateSlug string) error
 	TemplateGetInstances(groupName, templateSlug string) ([]sdk.WorkflowTemplateInstance, error)
 	TemplateDeleteInstance(groupName, templateSlug string, id int64) error
+	TemplateGetReleases(groupName, templateSlug string) ([]sdk.WorkflowTemplateRelease, error)
+	TemplateRelease(groupName, templateSlug string, release sdk.WorkflowTemplateRelease) (*sdk.WorkflowTemplateRelease, error)
+	TemplateUpgradeInstance(groupName, templateSlug string, id int64, req sdk.WorkflowTemplateUpgradeRequest) (*sdk.WorkflowTemplateUpgradeResult, error)
 }
 
 // Admin expose all function to CDS administration
@@ -52,6 +55,8 @@ type Admin interface {
 	AdminCDSMigrationCancel(id int64) error
 	AdminCDSMigrationReset(id int64) error
 	AdminWorkflowUpdateMaxRuns(projectKey string, workflowName string, maxRuns int64) error
+	AdminAuditLogs(mods ...RequestModifier) ([]sdk.AuditLog, error)
+	AdminAuditLogsExport(ctx context.Context, w io.Writer, mods ...RequestModifier) error
 	Features() ([]sdk.Feature, error)
 	FeatureCreate(f sdk.Feature) error
 	FeatureDelete(name sdk.FeatureName) error
@@ -103,6 +108,8 @@ type ApplicationClient interface {
 	ApplicationDelete(projectKey string, appName string) error
 	ApplicationGet(projectKey string, appName string, opts ...RequestModifier) (*sdk.Application, error)
 	ApplicationList(projectKey string) ([]sdk.Application, error)
+	ApplicationPreviewEnvironmentList(projectKey string, appName string) ([]sdk.PreviewEnvironment, error)
+	ApplicationPreviewEnvironmentDelete(projectKey string, appName string, workflowName string, pullRequestID int64) error
 	ApplicationVariableClient
 	ApplicationKeysClient
 }
@@ -112,6 +119,9 @@ type ApplicationKeysClient interface {
 	ApplicationKeysList(projectKey string, appName string) ([]sdk.ApplicationKey, error)
 	ApplicationKeyCreate(projectKey string, appName string, keyApp *sdk.ApplicationKey) error
 	ApplicationKeysDelete(projectKey string, appName string, KeyAppName string) error
+	ApplicationKeyRotate(projectKey string, appName string, keyName string, req sdk.KeyRotationRequest) (sdk.ApplicationKey, error)
+	ApplicationKeyUsage(projectKey string, appName string, keyName string) (sdk.KeyUsage, error)
+	ApplicationKeyRetire(projectKey string, appName string, keyName string, force bool) error
 }
 
 // ApplicationVariableClient exposes application variables related functions
@@ -131,6 +141,8 @@ type EnvironmentClient interface {
 	EnvironmentList(projectKey string) ([]sdk.Environment, error)
 	EnvironmentExport(projectKey, name string, mods ...RequestModifier) ([]byte, error)
 	EnvironmentImport(projectKey string, content io.Reader, mods ...RequestModifier) ([]string, error)
+	EnvironmentProtectionGet(projectKey string, envName string) (sdk.EnvironmentProtection, error)
+	EnvironmentProtectionUpdate(projectKey string, envName string, protection sdk.EnvironmentProtection) error
 	EnvironmentVariableClient
 	EnvironmentKeysClient
 }
@@ -235,6 +247,10 @@ type ProjectKeysClient interface {
 	ProjectKeysList(projectKey string) ([]sdk.ProjectKey, error)
 	ProjectKeyCreate(projectKey string, key *sdk.ProjectKey) error
 	ProjectKeysDelete(projectKey string, keyProjectName string) error
+	ProjectKeyRotate(projectKey string, keyName string, req sdk.KeyRotationRequest) (sdk.ProjectKey, error)
+	ProjectKeyUsage(projectKey string, keyName string) (sdk.KeyUsage, error)
+	ProjectKeyRetire(projectKey string, keyName string, force bool) error
+	ProjectProvenanceKeyGet(projectKey string) (sdk.ProjectProvenanceKey, error)
 }
 
 // ProjectVariablesClient exposes project variables related functions
@@ -267,7 +283,10 @@ type QueueClient interface {
 	QueueStaticFilesUpload(ctx context.Context, projectKey, integrationName string, nodeJobRunID int64, name, entrypoint, staticKey string, tarContent io.Reader) (string, bool, time.Duration, error)
 	QueueJobTag(ctx context.Context, jobID int64, tags []sdk.WorkflowRunTag) error
 	QueueJobSetVersion(ctx context.Context, jobID int64, version sdk.WorkflowRunVersion) error
+	QueueJobIDToken(ctx context.Context, jobID int64, req sdk.IDTokenRequest) (sdk.IDToken, error)
+	QueueJobGitMirror(ctx context.Context, jobID int64, repoURL string) (*sdk.GitMirror, error)
 	QueueWorkerCacheLink(ctx context.Context, jobID int64, tag string) (sdk.CDNItemLinks, error)
+	QueueWorkerCacheLinks(ctx context.Context, jobID int64, key string, restoreKeys []string) (sdk.CDNItemLinks, error)
 	QueueWorkflowRunResultsAdd(ctx context.Context, jobID int64, addRequest sdk.WorkflowRunResult) error
 	QueueWorkflowRunResultCheck(ctx context.Context, jobID int64, runResultCheck sdk.WorkflowRunResultCheck) (int, error)
 }
@@ -280,6 +299,9 @@ type UserClient interface {
 	UserGetGroups(username string) (map[string][]sdk.Group, error)
 	UpdateFavorite(params sdk.FavoriteParams) (interface{}, error)
 	UserGetSchema() (sdk.SchemaResponse, error)
+	UserNotificationSubscriptionList(username string) ([]sdk.UserNotificationSubscription, error)
+	UserNotificationSubscriptionCreate(username string, s *sdk.UserNotificationSubscription) error
+	UserNotificationSubscriptionDelete(username string, id int64) error
 }
 
 // WorkerClient exposes workers functions
@@ -343,6 +365,8 @@ type WorkflowClient interface {
 	WorkflowRunNumberSet(projectKey string, workflowName string, number int64) error
 	WorkflowStop(projectKey string, workflowName string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowNodeStop(projectKey string, workflowName string, number, fromNodeID int64) (*sdk.WorkflowNodeRun, error)
+	WorkflowNodeRunApprove(projectKey string, workflowName string, number, nodeRunID int64, comment string) (*sdk.WorkflowNodeRun, error)
+	WorkflowNodeRunReject(projectKey string, workflowName string, number, nodeRunID int64, comment string) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
 	WorkflowNodeRunArtifactDownload(projectKey string, name string, a sdk.WorkflowNodeRunArtifact, w io.Writer) error
 	WorkflowNodeRunJobStepLinks(ctx context.Context, projectKey string, workflowName string, nodeRunID, job int64) (*sdk.CDNLogLinks, error)
@@ -103,6 +103,51 @@ func (mr *MockTemplateClientMockRecorder) TemplateDeleteInstance(groupName, temp
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateDeleteInstance", reflect.TypeOf((*MockTemplateClient)(nil).TemplateDeleteInstance), groupName, templateSlug, id)
 }
 
+// TemplateGetReleases mocks base method.
+func (m *MockTemplateClient) TemplateGetReleases(groupName, templateSlug string) ([]sdk.WorkflowTemplateRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TemplateGetReleases", groupName, templateSlug)
+	ret0, _ := ret[0].([]sdk.WorkflowTemplateRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TemplateGetReleases indicates an expected call of TemplateGetReleases.
+func (mr *MockTemplateClientMockRecorder) TemplateGetReleases(groupName, templateSlug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateGetReleases", reflect.TypeOf((*MockTemplateClient)(nil).TemplateGetReleases), groupName, templateSlug)
+}
+
+// TemplateRelease mocks base method.
+func (m *MockTemplateClient) TemplateRelease(groupName, templateSlug string, release sdk.WorkflowTemplateRelease) (*sdk.WorkflowTemplateRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TemplateRelease", groupName, templateSlug, release)
+	ret0, _ := ret[0].(*sdk.WorkflowTemplateRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TemplateRelease indicates an expected call of TemplateRelease.
+func (mr *MockTemplateClientMockRecorder) TemplateRelease(groupName, templateSlug, release interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateRelease", reflect.TypeOf((*MockTemplateClient)(nil).TemplateRelease), groupName, templateSlug, release)
+}
+
+// TemplateUpgradeInstance mocks base method.
+func (m *MockTemplateClient) TemplateUpgradeInstance(groupName, templateSlug string, id int64, req sdk.WorkflowTemplateUpgradeRequest) (*sdk.WorkflowTemplateUpgradeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TemplateUpgradeInstance", groupName, templateSlug, id, req)
+	ret0, _ := ret[0].(*sdk.WorkflowTemplateUpgradeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TemplateUpgradeInstance indicates an expected call of TemplateUpgradeInstance.
+func (mr *MockTemplateClientMockRecorder) TemplateUpgradeInstance(groupName, templateSlug, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateUpgradeInstance", reflect.TypeOf((*MockTemplateClient)(nil).TemplateUpgradeInstance), groupName, templateSlug, id, req)
+}
+
 // TemplateGet mocks base method.
 func (m *MockTemplateClient) TemplateGet(groupName, templateSlug string) (*sdk.WorkflowTemplate, error) {
 	m.ctrl.T.Helper()
@@ -217,6 +262,44 @@ func (m *MockAdmin) EXPECT() *MockAdminMockRecorder {
 	return m.recorder
 }
 
+// AdminAuditLogs mocks base method.
+func (m *MockAdmin) AdminAuditLogs(mods ...cdsclient.RequestModifier) ([]sdk.AuditLog, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AdminAuditLogs", varargs...)
+	ret0, _ := ret[0].([]sdk.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminAuditLogs indicates an expected call of AdminAuditLogs.
+func (mr *MockAdminMockRecorder) AdminAuditLogs(mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminAuditLogs", reflect.TypeOf((*MockAdmin)(nil).AdminAuditLogs), mods...)
+}
+
+// AdminAuditLogsExport mocks base method.
+func (m *MockAdmin) AdminAuditLogsExport(ctx context.Context, w io.Writer, mods ...cdsclient.RequestModifier) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, w}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AdminAuditLogsExport", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminAuditLogsExport indicates an expected call of AdminAuditLogsExport.
+func (mr *MockAdminMockRecorder) AdminAuditLogsExport(ctx, w interface{}, mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, w}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminAuditLogsExport", reflect.TypeOf((*MockAdmin)(nil).AdminAuditLogsExport), varargs...)
+}
+
 // AdminCDSMigrationCancel mocks base method.
 func (m *MockAdmin) AdminCDSMigrationCancel(id int64) error {
 	m.ctrl.T.Helper()
@@ -1096,6 +1179,50 @@ func (mr *MockApplicationClientMockRecorder) ApplicationKeysDelete(projectKey, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeysDelete", reflect.TypeOf((*MockApplicationClient)(nil).ApplicationKeysDelete), projectKey, appName, KeyAppName)
 }
 
+// ApplicationKeyRotate mocks base method.
+func (m *MockApplicationClient) ApplicationKeyRotate(projectKey, appName, keyName string, req sdk.KeyRotationRequest) (sdk.ApplicationKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationKeyRotate", projectKey, appName, keyName, req)
+	ret0, _ := ret[0].(sdk.ApplicationKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicationKeyRotate indicates an expected call of ApplicationKeyRotate.
+func (mr *MockApplicationClientMockRecorder) ApplicationKeyRotate(projectKey, appName, keyName, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeyRotate", reflect.TypeOf((*MockApplicationClient)(nil).ApplicationKeyRotate), projectKey, appName, keyName, req)
+}
+
+// ApplicationKeyUsage mocks base method.
+func (m *MockApplicationClient) ApplicationKeyUsage(projectKey, appName, keyName string) (sdk.KeyUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationKeyUsage", projectKey, appName, keyName)
+	ret0, _ := ret[0].(sdk.KeyUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicationKeyUsage indicates an expected call of ApplicationKeyUsage.
+func (mr *MockApplicationClientMockRecorder) ApplicationKeyUsage(projectKey, appName, keyName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeyUsage", reflect.TypeOf((*MockApplicationClient)(nil).ApplicationKeyUsage), projectKey, appName, keyName)
+}
+
+// ApplicationKeyRetire mocks base method.
+func (m *MockApplicationClient) ApplicationKeyRetire(projectKey, appName, keyName string, force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationKeyRetire", projectKey, appName, keyName, force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplicationKeyRetire indicates an expected call of ApplicationKeyRetire.
+func (mr *MockApplicationClientMockRecorder) ApplicationKeyRetire(projectKey, appName, keyName, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeyRetire", reflect.TypeOf((*MockApplicationClient)(nil).ApplicationKeyRetire), projectKey, appName, keyName, force)
+}
+
 // ApplicationKeysList mocks base method.
 func (m *MockApplicationClient) ApplicationKeysList(projectKey, appName string) ([]sdk.ApplicationKey, error) {
 	m.ctrl.T.Helper()
@@ -1126,6 +1253,35 @@ func (mr *MockApplicationClientMockRecorder) ApplicationList(projectKey interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationList", reflect.TypeOf((*MockApplicationClient)(nil).ApplicationList), projectKey)
 }
 
+// ApplicationPreviewEnvironmentDelete mocks base method.
+func (m *MockApplicationClient) ApplicationPreviewEnvironmentDelete(projectKey, appName, workflowName string, pullRequestID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationPreviewEnvironmentDelete", projectKey, appName, workflowName, pullRequestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplicationPreviewEnvironmentDelete indicates an expected call of ApplicationPreviewEnvironmentDelete.
+func (mr *MockApplicationClientMockRecorder) ApplicationPreviewEnvironmentDelete(projectKey, appName, workflowName, pullRequestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationPreviewEnvironmentDelete", reflect.TypeOf((*MockApplicationClient)(nil).ApplicationPreviewEnvironmentDelete), projectKey, appName, workflowName, pullRequestID)
+}
+
+// ApplicationPreviewEnvironmentList mocks base method.
+func (m *MockApplicationClient) ApplicationPreviewEnvironmentList(projectKey, appName string) ([]sdk.PreviewEnvironment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationPreviewEnvironmentList", projectKey, appName)
+	ret0, _ := ret[0].([]sdk.PreviewEnvironment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicationPreviewEnvironmentList indicates an expected call of ApplicationPreviewEnvironmentList.
+func (mr *MockApplicationClientMockRecorder) ApplicationPreviewEnvironmentList(projectKey, appName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationPreviewEnvironmentList", reflect.TypeOf((*MockApplicationClient)(nil).ApplicationPreviewEnvironmentList), projectKey, appName)
+}
+
 // ApplicationUpdate mocks base method.
 func (m *MockApplicationClient) ApplicationUpdate(projectKey, appName string, app *sdk.Application) error {
 	m.ctrl.T.Helper()
@@ -1263,6 +1419,50 @@ func (mr *MockApplicationKeysClientMockRecorder) ApplicationKeysDelete(projectKe
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeysDelete", reflect.TypeOf((*MockApplicationKeysClient)(nil).ApplicationKeysDelete), projectKey, appName, KeyAppName)
 }
 
+// ApplicationKeyRotate mocks base method.
+func (m *MockApplicationKeysClient) ApplicationKeyRotate(projectKey, appName, keyName string, req sdk.KeyRotationRequest) (sdk.ApplicationKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationKeyRotate", projectKey, appName, keyName, req)
+	ret0, _ := ret[0].(sdk.ApplicationKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicationKeyRotate indicates an expected call of ApplicationKeyRotate.
+func (mr *MockApplicationKeysClientMockRecorder) ApplicationKeyRotate(projectKey, appName, keyName, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeyRotate", reflect.TypeOf((*MockApplicationKeysClient)(nil).ApplicationKeyRotate), projectKey, appName, keyName, req)
+}
+
+// ApplicationKeyUsage mocks base method.
+func (m *MockApplicationKeysClient) ApplicationKeyUsage(projectKey, appName, keyName string) (sdk.KeyUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationKeyUsage", projectKey, appName, keyName)
+	ret0, _ := ret[0].(sdk.KeyUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicationKeyUsage indicates an expected call of ApplicationKeyUsage.
+func (mr *MockApplicationKeysClientMockRecorder) ApplicationKeyUsage(projectKey, appName, keyName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeyUsage", reflect.TypeOf((*MockApplicationKeysClient)(nil).ApplicationKeyUsage), projectKey, appName, keyName)
+}
+
+// ApplicationKeyRetire mocks base method.
+func (m *MockApplicationKeysClient) ApplicationKeyRetire(projectKey, appName, keyName string, force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationKeyRetire", projectKey, appName, keyName, force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplicationKeyRetire indicates an expected call of ApplicationKeyRetire.
+func (mr *MockApplicationKeysClientMockRecorder) ApplicationKeyRetire(projectKey, appName, keyName, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeyRetire", reflect.TypeOf((*MockApplicationKeysClient)(nil).ApplicationKeyRetire), projectKey, appName, keyName, force)
+}
+
 // ApplicationKeysList mocks base method.
 func (m *MockApplicationKeysClient) ApplicationKeysList(projectKey, appName string) ([]sdk.ApplicationKey, error) {
 	m.ctrl.T.Helper()
@@ -1542,6 +1742,35 @@ func (mr *MockEnvironmentClientMockRecorder) EnvironmentList(projectKey interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnvironmentList", reflect.TypeOf((*MockEnvironmentClient)(nil).EnvironmentList), projectKey)
 }
 
+// EnvironmentProtectionGet mocks base method.
+func (m *MockEnvironmentClient) EnvironmentProtectionGet(projectKey, envName string) (sdk.EnvironmentProtection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnvironmentProtectionGet", projectKey, envName)
+	ret0, _ := ret[0].(sdk.EnvironmentProtection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnvironmentProtectionGet indicates an expected call of EnvironmentProtectionGet.
+func (mr *MockEnvironmentClientMockRecorder) EnvironmentProtectionGet(projectKey, envName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnvironmentProtectionGet", reflect.TypeOf((*MockEnvironmentClient)(nil).EnvironmentProtectionGet), projectKey, envName)
+}
+
+// EnvironmentProtectionUpdate mocks base method.
+func (m *MockEnvironmentClient) EnvironmentProtectionUpdate(projectKey, envName string, protection sdk.EnvironmentProtection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnvironmentProtectionUpdate", projectKey, envName, protection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnvironmentProtectionUpdate indicates an expected call of EnvironmentProtectionUpdate.
+func (mr *MockEnvironmentClientMockRecorder) EnvironmentProtectionUpdate(projectKey, envName, protection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnvironmentProtectionUpdate", reflect.TypeOf((*MockEnvironmentClient)(nil).EnvironmentProtectionUpdate), projectKey, envName, protection)
+}
+
 // EnvironmentVariableCreate mocks base method.
 func (m *MockEnvironmentClient) EnvironmentVariableCreate(projectKey, envName string, variable *sdk.Variable) error {
 	m.ctrl.T.Helper()
@@ -2616,6 +2845,65 @@ func (mr *MockProjectClientMockRecorder) ProjectKeysDelete(projectKey, keyProjec
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeysDelete", reflect.TypeOf((*MockProjectClient)(nil).ProjectKeysDelete), projectKey, keyProjectName)
 }
 
+// ProjectKeyRotate mocks base method.
+func (m *MockProjectClient) ProjectKeyRotate(projectKey, keyName string, req sdk.KeyRotationRequest) (sdk.ProjectKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectKeyRotate", projectKey, keyName, req)
+	ret0, _ := ret[0].(sdk.ProjectKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectKeyRotate indicates an expected call of ProjectKeyRotate.
+func (mr *MockProjectClientMockRecorder) ProjectKeyRotate(projectKey, keyName, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeyRotate", reflect.TypeOf((*MockProjectClient)(nil).ProjectKeyRotate), projectKey, keyName, req)
+}
+
+// ProjectKeyUsage mocks base method.
+func (m *MockProjectClient) ProjectKeyUsage(projectKey, keyName string) (sdk.KeyUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectKeyUsage", projectKey, keyName)
+	ret0, _ := ret[0].(sdk.KeyUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectKeyUsage indicates an expected call of ProjectKeyUsage.
+func (mr *MockProjectClientMockRecorder) ProjectKeyUsage(projectKey, keyName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeyUsage", reflect.TypeOf((*MockProjectClient)(nil).ProjectKeyUsage), projectKey, keyName)
+}
+
+// ProjectKeyRetire mocks base method.
+func (m *MockProjectClient) ProjectKeyRetire(projectKey, keyName string, force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectKeyRetire", projectKey, keyName, force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectKeyRetire indicates an expected call of ProjectKeyRetire.
+func (mr *MockProjectClientMockRecorder) ProjectKeyRetire(projectKey, keyName, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeyRetire", reflect.TypeOf((*MockProjectClient)(nil).ProjectKeyRetire), projectKey, keyName, force)
+}
+
+// ProjectProvenanceKeyGet mocks base method.
+func (m *MockProjectClient) ProjectProvenanceKeyGet(projectKey string) (sdk.ProjectProvenanceKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectProvenanceKeyGet", projectKey)
+	ret0, _ := ret[0].(sdk.ProjectProvenanceKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectProvenanceKeyGet indicates an expected call of ProjectProvenanceKeyGet.
+func (mr *MockProjectClientMockRecorder) ProjectProvenanceKeyGet(projectKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectProvenanceKeyGet", reflect.TypeOf((*MockProjectClient)(nil).ProjectProvenanceKeyGet), projectKey)
+}
+
 // ProjectKeysList mocks base method.
 func (m *MockProjectClient) ProjectKeysList(projectKey string) ([]sdk.ProjectKey, error) {
 	m.ctrl.T.Helper()
@@ -2847,6 +3135,65 @@ func (mr *MockProjectKeysClientMockRecorder) ProjectKeysDelete(projectKey, keyPr
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeysDelete", reflect.TypeOf((*MockProjectKeysClient)(nil).ProjectKeysDelete), projectKey, keyProjectName)
 }
 
+// ProjectKeyRotate mocks base method.
+func (m *MockProjectKeysClient) ProjectKeyRotate(projectKey, keyName string, req sdk.KeyRotationRequest) (sdk.ProjectKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectKeyRotate", projectKey, keyName, req)
+	ret0, _ := ret[0].(sdk.ProjectKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectKeyRotate indicates an expected call of ProjectKeyRotate.
+func (mr *MockProjectKeysClientMockRecorder) ProjectKeyRotate(projectKey, keyName, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeyRotate", reflect.TypeOf((*MockProjectKeysClient)(nil).ProjectKeyRotate), projectKey, keyName, req)
+}
+
+// ProjectKeyUsage mocks base method.
+func (m *MockProjectKeysClient) ProjectKeyUsage(projectKey, keyName string) (sdk.KeyUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectKeyUsage", projectKey, keyName)
+	ret0, _ := ret[0].(sdk.KeyUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectKeyUsage indicates an expected call of ProjectKeyUsage.
+func (mr *MockProjectKeysClientMockRecorder) ProjectKeyUsage(projectKey, keyName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeyUsage", reflect.TypeOf((*MockProjectKeysClient)(nil).ProjectKeyUsage), projectKey, keyName)
+}
+
+// ProjectKeyRetire mocks base method.
+func (m *MockProjectKeysClient) ProjectKeyRetire(projectKey, keyName string, force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectKeyRetire", projectKey, keyName, force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectKeyRetire indicates an expected call of ProjectKeyRetire.
+func (mr *MockProjectKeysClientMockRecorder) ProjectKeyRetire(projectKey, keyName, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeyRetire", reflect.TypeOf((*MockProjectKeysClient)(nil).ProjectKeyRetire), projectKey, keyName, force)
+}
+
+// ProjectProvenanceKeyGet mocks base method.
+func (m *MockProjectKeysClient) ProjectProvenanceKeyGet(projectKey string) (sdk.ProjectProvenanceKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectProvenanceKeyGet", projectKey)
+	ret0, _ := ret[0].(sdk.ProjectProvenanceKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectProvenanceKeyGet indicates an expected call of ProjectProvenanceKeyGet.
+func (mr *MockProjectKeysClientMockRecorder) ProjectProvenanceKeyGet(projectKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectProvenanceKeyGet", reflect.TypeOf((*MockProjectKeysClient)(nil).ProjectProvenanceKeyGet), projectKey)
+}
+
 // ProjectKeysList mocks base method.
 func (m *MockProjectKeysClient) ProjectKeysList(projectKey string) ([]sdk.ProjectKey, error) {
 	m.ctrl.T.Helper()
@@ -3056,6 +3403,21 @@ func (mr *MockQueueClientMockRecorder) QueueJobBook(ctx, id interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobBook", reflect.TypeOf((*MockQueueClient)(nil).QueueJobBook), ctx, id)
 }
 
+// QueueJobGitMirror mocks base method.
+func (m *MockQueueClient) QueueJobGitMirror(ctx context.Context, jobID int64, repoURL string) (*sdk.GitMirror, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobGitMirror", ctx, jobID, repoURL)
+	ret0, _ := ret[0].(*sdk.GitMirror)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueJobGitMirror indicates an expected call of QueueJobGitMirror.
+func (mr *MockQueueClientMockRecorder) QueueJobGitMirror(ctx, jobID, repoURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobGitMirror", reflect.TypeOf((*MockQueueClient)(nil).QueueJobGitMirror), ctx, jobID, repoURL)
+}
+
 // QueueJobInfo mocks base method.
 func (m *MockQueueClient) QueueJobInfo(ctx context.Context, id int64) (*sdk.WorkflowNodeJobRun, error) {
 	m.ctrl.T.Helper()
@@ -3113,6 +3475,21 @@ func (mr *MockQueueClientMockRecorder) QueueJobSetVersion(ctx, jobID, version in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobSetVersion", reflect.TypeOf((*MockQueueClient)(nil).QueueJobSetVersion), ctx, jobID, version)
 }
 
+// QueueJobIDToken mocks base method.
+func (m *MockQueueClient) QueueJobIDToken(ctx context.Context, jobID int64, req sdk.IDTokenRequest) (sdk.IDToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobIDToken", ctx, jobID, req)
+	ret0, _ := ret[0].(sdk.IDToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueJobIDToken indicates an expected call of QueueJobIDToken.
+func (mr *MockQueueClientMockRecorder) QueueJobIDToken(ctx, jobID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobIDToken", reflect.TypeOf((*MockQueueClient)(nil).QueueJobIDToken), ctx, jobID, req)
+}
+
 // QueueJobTag mocks base method.
 func (m *MockQueueClient) QueueJobTag(ctx context.Context, jobID int64, tags []sdk.WorkflowRunTag) error {
 	m.ctrl.T.Helper()
@@ -3263,6 +3640,21 @@ func (mr *MockQueueClientMockRecorder) QueueWorkerCacheLink(ctx, jobID, tag inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueWorkerCacheLink", reflect.TypeOf((*MockQueueClient)(nil).QueueWorkerCacheLink), ctx, jobID, tag)
 }
 
+// QueueWorkerCacheLinks mocks base method.
+func (m *MockQueueClient) QueueWorkerCacheLinks(ctx context.Context, jobID int64, key string, restoreKeys []string) (sdk.CDNItemLinks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueWorkerCacheLinks", ctx, jobID, key, restoreKeys)
+	ret0, _ := ret[0].(sdk.CDNItemLinks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueWorkerCacheLinks indicates an expected call of QueueWorkerCacheLinks.
+func (mr *MockQueueClientMockRecorder) QueueWorkerCacheLinks(ctx, jobID, key, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueWorkerCacheLinks", reflect.TypeOf((*MockQueueClient)(nil).QueueWorkerCacheLinks), ctx, jobID, key, restoreKeys)
+}
+
 // QueueWorkflowNodeJobRun mocks base method.
 func (m *MockQueueClient) QueueWorkflowNodeJobRun(mods ...cdsclient.RequestModifier) ([]sdk.WorkflowNodeJobRun, error) {
 	m.ctrl.T.Helper()
@@ -3424,6 +3816,49 @@ func (mr *MockUserClientMockRecorder) UserList() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserList", reflect.TypeOf((*MockUserClient)(nil).UserList))
 }
 
+// UserNotificationSubscriptionCreate mocks base method.
+func (m *MockUserClient) UserNotificationSubscriptionCreate(username string, s *sdk.UserNotificationSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserNotificationSubscriptionCreate", username, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UserNotificationSubscriptionCreate indicates an expected call of UserNotificationSubscriptionCreate.
+func (mr *MockUserClientMockRecorder) UserNotificationSubscriptionCreate(username, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserNotificationSubscriptionCreate", reflect.TypeOf((*MockUserClient)(nil).UserNotificationSubscriptionCreate), username, s)
+}
+
+// UserNotificationSubscriptionDelete mocks base method.
+func (m *MockUserClient) UserNotificationSubscriptionDelete(username string, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserNotificationSubscriptionDelete", username, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UserNotificationSubscriptionDelete indicates an expected call of UserNotificationSubscriptionDelete.
+func (mr *MockUserClientMockRecorder) UserNotificationSubscriptionDelete(username, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserNotificationSubscriptionDelete", reflect.TypeOf((*MockUserClient)(nil).UserNotificationSubscriptionDelete), username, id)
+}
+
+// UserNotificationSubscriptionList mocks base method.
+func (m *MockUserClient) UserNotificationSubscriptionList(username string) ([]sdk.UserNotificationSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserNotificationSubscriptionList", username)
+	ret0, _ := ret[0].([]sdk.UserNotificationSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserNotificationSubscriptionList indicates an expected call of UserNotificationSubscriptionList.
+func (mr *MockUserClientMockRecorder) UserNotificationSubscriptionList(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserNotificationSubscriptionList", reflect.TypeOf((*MockUserClient)(nil).UserNotificationSubscriptionList), username)
+}
+
 // MockWorkerClient is a mock of WorkerClient interface.
 type MockWorkerClient struct {
 	ctrl     *gomock.Controller
@@ -4183,6 +4618,36 @@ func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRunRelease(projectKey, wor
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunRelease", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRunRelease), projectKey, workflowName, runNumber, nodeRunID, release)
 }
 
+// WorkflowNodeRunApprove mocks base method.
+func (m *MockWorkflowClient) WorkflowNodeRunApprove(projectKey, workflowName string, number, nodeRunID int64, comment string) (*sdk.WorkflowNodeRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunApprove", projectKey, workflowName, number, nodeRunID, comment)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunApprove indicates an expected call of WorkflowNodeRunApprove.
+func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRunApprove(projectKey, workflowName, number, nodeRunID, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunApprove", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRunApprove), projectKey, workflowName, number, nodeRunID, comment)
+}
+
+// WorkflowNodeRunReject mocks base method.
+func (m *MockWorkflowClient) WorkflowNodeRunReject(projectKey, workflowName string, number, nodeRunID int64, comment string) (*sdk.WorkflowNodeRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunReject", projectKey, workflowName, number, nodeRunID, comment)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunReject indicates an expected call of WorkflowNodeRunReject.
+func (mr *MockWorkflowClientMockRecorder) WorkflowNodeRunReject(projectKey, workflowName, number, nodeRunID, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunReject", reflect.TypeOf((*MockWorkflowClient)(nil).WorkflowNodeRunReject), projectKey, workflowName, number, nodeRunID, comment)
+}
+
 // WorkflowNodeStop mocks base method.
 func (m *MockWorkflowClient) WorkflowNodeStop(projectKey, workflowName string, number, fromNodeID int64) (*sdk.WorkflowNodeRun, error) {
 	m.ctrl.T.Helper()
@@ -4787,6 +5252,44 @@ func (mr *MockInterfaceMockRecorder) ActionUsage(groupName, name interface{}, mo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActionUsage", reflect.TypeOf((*MockInterface)(nil).ActionUsage), varargs...)
 }
 
+// AdminAuditLogs mocks base method.
+func (m *MockInterface) AdminAuditLogs(mods ...cdsclient.RequestModifier) ([]sdk.AuditLog, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AdminAuditLogs", varargs...)
+	ret0, _ := ret[0].([]sdk.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminAuditLogs indicates an expected call of AdminAuditLogs.
+func (mr *MockInterfaceMockRecorder) AdminAuditLogs(mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminAuditLogs", reflect.TypeOf((*MockInterface)(nil).AdminAuditLogs), mods...)
+}
+
+// AdminAuditLogsExport mocks base method.
+func (m *MockInterface) AdminAuditLogsExport(ctx context.Context, w io.Writer, mods ...cdsclient.RequestModifier) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, w}
+	for _, a := range mods {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AdminAuditLogsExport", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdminAuditLogsExport indicates an expected call of AdminAuditLogsExport.
+func (mr *MockInterfaceMockRecorder) AdminAuditLogsExport(ctx, w interface{}, mods ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, w}, mods...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminAuditLogsExport", reflect.TypeOf((*MockInterface)(nil).AdminAuditLogsExport), varargs...)
+}
+
 // AdminCDSMigrationCancel mocks base method.
 func (m *MockInterface) AdminCDSMigrationCancel(id int64) error {
 	m.ctrl.T.Helper()
@@ -5103,6 +5606,50 @@ func (mr *MockInterfaceMockRecorder) ApplicationKeysDelete(projectKey, appName,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeysDelete", reflect.TypeOf((*MockInterface)(nil).ApplicationKeysDelete), projectKey, appName, KeyAppName)
 }
 
+// ApplicationKeyRotate mocks base method.
+func (m *MockInterface) ApplicationKeyRotate(projectKey, appName, keyName string, req sdk.KeyRotationRequest) (sdk.ApplicationKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationKeyRotate", projectKey, appName, keyName, req)
+	ret0, _ := ret[0].(sdk.ApplicationKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicationKeyRotate indicates an expected call of ApplicationKeyRotate.
+func (mr *MockInterfaceMockRecorder) ApplicationKeyRotate(projectKey, appName, keyName, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeyRotate", reflect.TypeOf((*MockInterface)(nil).ApplicationKeyRotate), projectKey, appName, keyName, req)
+}
+
+// ApplicationKeyUsage mocks base method.
+func (m *MockInterface) ApplicationKeyUsage(projectKey, appName, keyName string) (sdk.KeyUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationKeyUsage", projectKey, appName, keyName)
+	ret0, _ := ret[0].(sdk.KeyUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicationKeyUsage indicates an expected call of ApplicationKeyUsage.
+func (mr *MockInterfaceMockRecorder) ApplicationKeyUsage(projectKey, appName, keyName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeyUsage", reflect.TypeOf((*MockInterface)(nil).ApplicationKeyUsage), projectKey, appName, keyName)
+}
+
+// ApplicationKeyRetire mocks base method.
+func (m *MockInterface) ApplicationKeyRetire(projectKey, appName, keyName string, force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationKeyRetire", projectKey, appName, keyName, force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplicationKeyRetire indicates an expected call of ApplicationKeyRetire.
+func (mr *MockInterfaceMockRecorder) ApplicationKeyRetire(projectKey, appName, keyName, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationKeyRetire", reflect.TypeOf((*MockInterface)(nil).ApplicationKeyRetire), projectKey, appName, keyName, force)
+}
+
 // ApplicationKeysList mocks base method.
 func (m *MockInterface) ApplicationKeysList(projectKey, appName string) ([]sdk.ApplicationKey, error) {
 	m.ctrl.T.Helper()
@@ -5133,6 +5680,35 @@ func (mr *MockInterfaceMockRecorder) ApplicationList(projectKey interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationList", reflect.TypeOf((*MockInterface)(nil).ApplicationList), projectKey)
 }
 
+// ApplicationPreviewEnvironmentDelete mocks base method.
+func (m *MockInterface) ApplicationPreviewEnvironmentDelete(projectKey, appName, workflowName string, pullRequestID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationPreviewEnvironmentDelete", projectKey, appName, workflowName, pullRequestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplicationPreviewEnvironmentDelete indicates an expected call of ApplicationPreviewEnvironmentDelete.
+func (mr *MockInterfaceMockRecorder) ApplicationPreviewEnvironmentDelete(projectKey, appName, workflowName, pullRequestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationPreviewEnvironmentDelete", reflect.TypeOf((*MockInterface)(nil).ApplicationPreviewEnvironmentDelete), projectKey, appName, workflowName, pullRequestID)
+}
+
+// ApplicationPreviewEnvironmentList mocks base method.
+func (m *MockInterface) ApplicationPreviewEnvironmentList(projectKey, appName string) ([]sdk.PreviewEnvironment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationPreviewEnvironmentList", projectKey, appName)
+	ret0, _ := ret[0].([]sdk.PreviewEnvironment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicationPreviewEnvironmentList indicates an expected call of ApplicationPreviewEnvironmentList.
+func (mr *MockInterfaceMockRecorder) ApplicationPreviewEnvironmentList(projectKey, appName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationPreviewEnvironmentList", reflect.TypeOf((*MockInterface)(nil).ApplicationPreviewEnvironmentList), projectKey, appName)
+}
+
 // ApplicationUpdate mocks base method.
 func (m *MockInterface) ApplicationUpdate(projectKey, appName string, app *sdk.Application) error {
 	m.ctrl.T.Helper()
@@ -5781,6 +6357,35 @@ func (mr *MockInterfaceMockRecorder) EnvironmentList(projectKey interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnvironmentList", reflect.TypeOf((*MockInterface)(nil).EnvironmentList), projectKey)
 }
 
+// EnvironmentProtectionGet mocks base method.
+func (m *MockInterface) EnvironmentProtectionGet(projectKey, envName string) (sdk.EnvironmentProtection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnvironmentProtectionGet", projectKey, envName)
+	ret0, _ := ret[0].(sdk.EnvironmentProtection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnvironmentProtectionGet indicates an expected call of EnvironmentProtectionGet.
+func (mr *MockInterfaceMockRecorder) EnvironmentProtectionGet(projectKey, envName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnvironmentProtectionGet", reflect.TypeOf((*MockInterface)(nil).EnvironmentProtectionGet), projectKey, envName)
+}
+
+// EnvironmentProtectionUpdate mocks base method.
+func (m *MockInterface) EnvironmentProtectionUpdate(projectKey, envName string, protection sdk.EnvironmentProtection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnvironmentProtectionUpdate", projectKey, envName, protection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnvironmentProtectionUpdate indicates an expected call of EnvironmentProtectionUpdate.
+func (mr *MockInterfaceMockRecorder) EnvironmentProtectionUpdate(projectKey, envName, protection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnvironmentProtectionUpdate", reflect.TypeOf((*MockInterface)(nil).EnvironmentProtectionUpdate), projectKey, envName, protection)
+}
+
 // EnvironmentVariableCreate mocks base method.
 func (m *MockInterface) EnvironmentVariableCreate(projectKey, envName string, variable *sdk.Variable) error {
 	m.ctrl.T.Helper()
@@ -6754,6 +7359,65 @@ func (mr *MockInterfaceMockRecorder) ProjectKeysDelete(projectKey, keyProjectNam
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeysDelete", reflect.TypeOf((*MockInterface)(nil).ProjectKeysDelete), projectKey, keyProjectName)
 }
 
+// ProjectKeyRotate mocks base method.
+func (m *MockInterface) ProjectKeyRotate(projectKey, keyName string, req sdk.KeyRotationRequest) (sdk.ProjectKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectKeyRotate", projectKey, keyName, req)
+	ret0, _ := ret[0].(sdk.ProjectKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectKeyRotate indicates an expected call of ProjectKeyRotate.
+func (mr *MockInterfaceMockRecorder) ProjectKeyRotate(projectKey, keyName, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeyRotate", reflect.TypeOf((*MockInterface)(nil).ProjectKeyRotate), projectKey, keyName, req)
+}
+
+// ProjectKeyUsage mocks base method.
+func (m *MockInterface) ProjectKeyUsage(projectKey, keyName string) (sdk.KeyUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectKeyUsage", projectKey, keyName)
+	ret0, _ := ret[0].(sdk.KeyUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectKeyUsage indicates an expected call of ProjectKeyUsage.
+func (mr *MockInterfaceMockRecorder) ProjectKeyUsage(projectKey, keyName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeyUsage", reflect.TypeOf((*MockInterface)(nil).ProjectKeyUsage), projectKey, keyName)
+}
+
+// ProjectKeyRetire mocks base method.
+func (m *MockInterface) ProjectKeyRetire(projectKey, keyName string, force bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectKeyRetire", projectKey, keyName, force)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProjectKeyRetire indicates an expected call of ProjectKeyRetire.
+func (mr *MockInterfaceMockRecorder) ProjectKeyRetire(projectKey, keyName, force interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectKeyRetire", reflect.TypeOf((*MockInterface)(nil).ProjectKeyRetire), projectKey, keyName, force)
+}
+
+// ProjectProvenanceKeyGet mocks base method.
+func (m *MockInterface) ProjectProvenanceKeyGet(projectKey string) (sdk.ProjectProvenanceKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectProvenanceKeyGet", projectKey)
+	ret0, _ := ret[0].(sdk.ProjectProvenanceKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectProvenanceKeyGet indicates an expected call of ProjectProvenanceKeyGet.
+func (mr *MockInterfaceMockRecorder) ProjectProvenanceKeyGet(projectKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectProvenanceKeyGet", reflect.TypeOf((*MockInterface)(nil).ProjectProvenanceKeyGet), projectKey)
+}
+
 // ProjectKeysList mocks base method.
 func (m *MockInterface) ProjectKeysList(projectKey string) ([]sdk.ProjectKey, error) {
 	m.ctrl.T.Helper()
@@ -6970,6 +7634,21 @@ func (mr *MockInterfaceMockRecorder) QueueJobBook(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobBook", reflect.TypeOf((*MockInterface)(nil).QueueJobBook), ctx, id)
 }
 
+// QueueJobGitMirror mocks base method.
+func (m *MockInterface) QueueJobGitMirror(ctx context.Context, jobID int64, repoURL string) (*sdk.GitMirror, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobGitMirror", ctx, jobID, repoURL)
+	ret0, _ := ret[0].(*sdk.GitMirror)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueJobGitMirror indicates an expected call of QueueJobGitMirror.
+func (mr *MockInterfaceMockRecorder) QueueJobGitMirror(ctx, jobID, repoURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobGitMirror", reflect.TypeOf((*MockInterface)(nil).QueueJobGitMirror), ctx, jobID, repoURL)
+}
+
 // QueueJobInfo mocks base method.
 func (m *MockInterface) QueueJobInfo(ctx context.Context, id int64) (*sdk.WorkflowNodeJobRun, error) {
 	m.ctrl.T.Helper()
@@ -7027,6 +7706,21 @@ func (mr *MockInterfaceMockRecorder) QueueJobSetVersion(ctx, jobID, version inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobSetVersion", reflect.TypeOf((*MockInterface)(nil).QueueJobSetVersion), ctx, jobID, version)
 }
 
+// QueueJobIDToken mocks base method.
+func (m *MockInterface) QueueJobIDToken(ctx context.Context, jobID int64, req sdk.IDTokenRequest) (sdk.IDToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobIDToken", ctx, jobID, req)
+	ret0, _ := ret[0].(sdk.IDToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueJobIDToken indicates an expected call of QueueJobIDToken.
+func (mr *MockInterfaceMockRecorder) QueueJobIDToken(ctx, jobID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobIDToken", reflect.TypeOf((*MockInterface)(nil).QueueJobIDToken), ctx, jobID, req)
+}
+
 // QueueJobTag mocks base method.
 func (m *MockInterface) QueueJobTag(ctx context.Context, jobID int64, tags []sdk.WorkflowRunTag) error {
 	m.ctrl.T.Helper()
@@ -7177,6 +7871,21 @@ func (mr *MockInterfaceMockRecorder) QueueWorkerCacheLink(ctx, jobID, tag interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueWorkerCacheLink", reflect.TypeOf((*MockInterface)(nil).QueueWorkerCacheLink), ctx, jobID, tag)
 }
 
+// QueueWorkerCacheLinks mocks base method.
+func (m *MockInterface) QueueWorkerCacheLinks(ctx context.Context, jobID int64, key string, restoreKeys []string) (sdk.CDNItemLinks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueWorkerCacheLinks", ctx, jobID, key, restoreKeys)
+	ret0, _ := ret[0].(sdk.CDNItemLinks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueWorkerCacheLinks indicates an expected call of QueueWorkerCacheLinks.
+func (mr *MockInterfaceMockRecorder) QueueWorkerCacheLinks(ctx, jobID, key, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueWorkerCacheLinks", reflect.TypeOf((*MockInterface)(nil).QueueWorkerCacheLinks), ctx, jobID, key, restoreKeys)
+}
+
 // QueueWorkflowNodeJobRun mocks base method.
 func (m *MockInterface) QueueWorkflowNodeJobRun(mods ...cdsclient.RequestModifier) ([]sdk.WorkflowNodeJobRun, error) {
 	m.ctrl.T.Helper()
@@ -7570,6 +8279,51 @@ func (mr *MockInterfaceMockRecorder) TemplateDeleteInstance(groupName, templateS
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateDeleteInstance", reflect.TypeOf((*MockInterface)(nil).TemplateDeleteInstance), groupName, templateSlug, id)
 }
 
+// TemplateGetReleases mocks base method.
+func (m *MockInterface) TemplateGetReleases(groupName, templateSlug string) ([]sdk.WorkflowTemplateRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TemplateGetReleases", groupName, templateSlug)
+	ret0, _ := ret[0].([]sdk.WorkflowTemplateRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TemplateGetReleases indicates an expected call of TemplateGetReleases.
+func (mr *MockInterfaceMockRecorder) TemplateGetReleases(groupName, templateSlug interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateGetReleases", reflect.TypeOf((*MockInterface)(nil).TemplateGetReleases), groupName, templateSlug)
+}
+
+// TemplateRelease mocks base method.
+func (m *MockInterface) TemplateRelease(groupName, templateSlug string, release sdk.WorkflowTemplateRelease) (*sdk.WorkflowTemplateRelease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TemplateRelease", groupName, templateSlug, release)
+	ret0, _ := ret[0].(*sdk.WorkflowTemplateRelease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TemplateRelease indicates an expected call of TemplateRelease.
+func (mr *MockInterfaceMockRecorder) TemplateRelease(groupName, templateSlug, release interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateRelease", reflect.TypeOf((*MockInterface)(nil).TemplateRelease), groupName, templateSlug, release)
+}
+
+// TemplateUpgradeInstance mocks base method.
+func (m *MockInterface) TemplateUpgradeInstance(groupName, templateSlug string, id int64, req sdk.WorkflowTemplateUpgradeRequest) (*sdk.WorkflowTemplateUpgradeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TemplateUpgradeInstance", groupName, templateSlug, id, req)
+	ret0, _ := ret[0].(*sdk.WorkflowTemplateUpgradeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TemplateUpgradeInstance indicates an expected call of TemplateUpgradeInstance.
+func (mr *MockInterfaceMockRecorder) TemplateUpgradeInstance(groupName, templateSlug, id, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TemplateUpgradeInstance", reflect.TypeOf((*MockInterface)(nil).TemplateUpgradeInstance), groupName, templateSlug, id, req)
+}
+
 // TemplateGet mocks base method.
 func (m *MockInterface) TemplateGet(groupName, templateSlug string) (*sdk.WorkflowTemplate, error) {
 	m.ctrl.T.Helper()
@@ -7751,6 +8505,49 @@ func (mr *MockInterfaceMockRecorder) UserList() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserList", reflect.TypeOf((*MockInterface)(nil).UserList))
 }
 
+// UserNotificationSubscriptionCreate mocks base method.
+func (m *MockInterface) UserNotificationSubscriptionCreate(username string, s *sdk.UserNotificationSubscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserNotificationSubscriptionCreate", username, s)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UserNotificationSubscriptionCreate indicates an expected call of UserNotificationSubscriptionCreate.
+func (mr *MockInterfaceMockRecorder) UserNotificationSubscriptionCreate(username, s interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserNotificationSubscriptionCreate", reflect.TypeOf((*MockInterface)(nil).UserNotificationSubscriptionCreate), username, s)
+}
+
+// UserNotificationSubscriptionDelete mocks base method.
+func (m *MockInterface) UserNotificationSubscriptionDelete(username string, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserNotificationSubscriptionDelete", username, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UserNotificationSubscriptionDelete indicates an expected call of UserNotificationSubscriptionDelete.
+func (mr *MockInterfaceMockRecorder) UserNotificationSubscriptionDelete(username, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserNotificationSubscriptionDelete", reflect.TypeOf((*MockInterface)(nil).UserNotificationSubscriptionDelete), username, id)
+}
+
+// UserNotificationSubscriptionList mocks base method.
+func (m *MockInterface) UserNotificationSubscriptionList(username string) ([]sdk.UserNotificationSubscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserNotificationSubscriptionList", username)
+	ret0, _ := ret[0].([]sdk.UserNotificationSubscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserNotificationSubscriptionList indicates an expected call of UserNotificationSubscriptionList.
+func (mr *MockInterfaceMockRecorder) UserNotificationSubscriptionList(username interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserNotificationSubscriptionList", reflect.TypeOf((*MockInterface)(nil).UserNotificationSubscriptionList), username)
+}
+
 // VCSConfiguration mocks base method.
 func (m *MockInterface) VCSConfiguration() (map[string]sdk.VCSConfiguration, error) {
 	m.ctrl.T.Helper()
@@ -8458,6 +9255,36 @@ func (mr *MockInterfaceMockRecorder) WorkflowNodeRunRelease(projectKey, workflow
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunRelease", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRunRelease), projectKey, workflowName, runNumber, nodeRunID, release)
 }
 
+// WorkflowNodeRunApprove mocks base method.
+func (m *MockInterface) WorkflowNodeRunApprove(projectKey, workflowName string, number, nodeRunID int64, comment string) (*sdk.WorkflowNodeRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunApprove", projectKey, workflowName, number, nodeRunID, comment)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunApprove indicates an expected call of WorkflowNodeRunApprove.
+func (mr *MockInterfaceMockRecorder) WorkflowNodeRunApprove(projectKey, workflowName, number, nodeRunID, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunApprove", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRunApprove), projectKey, workflowName, number, nodeRunID, comment)
+}
+
+// WorkflowNodeRunReject mocks base method.
+func (m *MockInterface) WorkflowNodeRunReject(projectKey, workflowName string, number, nodeRunID int64, comment string) (*sdk.WorkflowNodeRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WorkflowNodeRunReject", projectKey, workflowName, number, nodeRunID, comment)
+	ret0, _ := ret[0].(*sdk.WorkflowNodeRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WorkflowNodeRunReject indicates an expected call of WorkflowNodeRunReject.
+func (mr *MockInterfaceMockRecorder) WorkflowNodeRunReject(projectKey, workflowName, number, nodeRunID, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WorkflowNodeRunReject", reflect.TypeOf((*MockInterface)(nil).WorkflowNodeRunReject), projectKey, workflowName, number, nodeRunID, comment)
+}
+
 // WorkflowNodeStop mocks base method.
 func (m *MockInterface) WorkflowNodeStop(projectKey, workflowName string, number, fromNodeID int64) (*sdk.WorkflowNodeRun, error) {
 	m.ctrl.T.Helper()
@@ -9002,6 +9829,21 @@ func (mr *MockWorkerInterfaceMockRecorder) QueueJobBook(ctx, id interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobBook", reflect.TypeOf((*MockWorkerInterface)(nil).QueueJobBook), ctx, id)
 }
 
+// QueueJobGitMirror mocks base method.
+func (m *MockWorkerInterface) QueueJobGitMirror(ctx context.Context, jobID int64, repoURL string) (*sdk.GitMirror, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobGitMirror", ctx, jobID, repoURL)
+	ret0, _ := ret[0].(*sdk.GitMirror)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueJobGitMirror indicates an expected call of QueueJobGitMirror.
+func (mr *MockWorkerInterfaceMockRecorder) QueueJobGitMirror(ctx, jobID, repoURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobGitMirror", reflect.TypeOf((*MockWorkerInterface)(nil).QueueJobGitMirror), ctx, jobID, repoURL)
+}
+
 // QueueJobInfo mocks base method.
 func (m *MockWorkerInterface) QueueJobInfo(ctx context.Context, id int64) (*sdk.WorkflowNodeJobRun, error) {
 	m.ctrl.T.Helper()
@@ -9059,6 +9901,21 @@ func (mr *MockWorkerInterfaceMockRecorder) QueueJobSetVersion(ctx, jobID, versio
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobSetVersion", reflect.TypeOf((*MockWorkerInterface)(nil).QueueJobSetVersion), ctx, jobID, version)
 }
 
+// QueueJobIDToken mocks base method.
+func (m *MockWorkerInterface) QueueJobIDToken(ctx context.Context, jobID int64, req sdk.IDTokenRequest) (sdk.IDToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueJobIDToken", ctx, jobID, req)
+	ret0, _ := ret[0].(sdk.IDToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueJobIDToken indicates an expected call of QueueJobIDToken.
+func (mr *MockWorkerInterfaceMockRecorder) QueueJobIDToken(ctx, jobID, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueJobIDToken", reflect.TypeOf((*MockWorkerInterface)(nil).QueueJobIDToken), ctx, jobID, req)
+}
+
 // QueueJobTag mocks base method.
 func (m *MockWorkerInterface) QueueJobTag(ctx context.Context, jobID int64, tags []sdk.WorkflowRunTag) error {
 	m.ctrl.T.Helper()
@@ -9209,6 +10066,21 @@ func (mr *MockWorkerInterfaceMockRecorder) QueueWorkerCacheLink(ctx, jobID, tag
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueWorkerCacheLink", reflect.TypeOf((*MockWorkerInterface)(nil).QueueWorkerCacheLink), ctx, jobID, tag)
 }
 
+// QueueWorkerCacheLinks mocks base method.
+func (m *MockWorkerInterface) QueueWorkerCacheLinks(ctx context.Context, jobID int64, key string, restoreKeys []string) (sdk.CDNItemLinks, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueWorkerCacheLinks", ctx, jobID, key, restoreKeys)
+	ret0, _ := ret[0].(sdk.CDNItemLinks)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueWorkerCacheLinks indicates an expected call of QueueWorkerCacheLinks.
+func (mr *MockWorkerInterfaceMockRecorder) QueueWorkerCacheLinks(ctx, jobID, key, restoreKeys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueWorkerCacheLinks", reflect.TypeOf((*MockWorkerInterface)(nil).QueueWorkerCacheLinks), ctx, jobID, key, restoreKeys)
+}
+
 // QueueWorkflowNodeJobRun mocks base method.
 func (m *MockWorkerInterface) QueueWorkflowNodeJobRun(mods ...cdsclient.RequestModifier) ([]sdk.WorkflowNodeJobRun, error) {
 	m.ctrl.T.Helper()
@@ -7,30 +7,32 @@ import (
 
 // Environment represent a deployment environment
 type Environment struct {
-	ID                   int64                 `json:"id" yaml:"-"`
-	Name                 string                `json:"name" yaml:"name" cli:"name,key"`
-	Variables            []EnvironmentVariable `json:"variables,omitempty" yaml:"variables"`
-	ProjectID            int64                 `json:"-" yaml:"-"`
-	ProjectKey           string                `json:"project_key" yaml:"-"`
-	Created              time.Time             `json:"created"`
-	LastModified         time.Time             `json:"last_modified"`
-	Keys                 []EnvironmentKey      `json:"keys"`
-	Usage                *Usage                `json:"usage,omitempty"`
-	FromRepository       string                `json:"from_repository,omitempty"`
-	WorkflowAscodeHolder *Workflow             `json:"workflow_ascode_holder,omitempty" cli:"-"`
+	ID                   int64                  `json:"id" yaml:"-"`
+	Name                 string                 `json:"name" yaml:"name" cli:"name,key"`
+	Variables            []EnvironmentVariable  `json:"variables,omitempty" yaml:"variables"`
+	ProjectID            int64                  `json:"-" yaml:"-"`
+	ProjectKey           string                 `json:"project_key" yaml:"-"`
+	Created              time.Time              `json:"created"`
+	LastModified         time.Time              `json:"last_modified"`
+	Keys                 []EnvironmentKey       `json:"keys"`
+	Usage                *Usage                 `json:"usage,omitempty"`
+	FromRepository       string                 `json:"from_repository,omitempty"`
+	Protection           *EnvironmentProtection `json:"protection,omitempty" yaml:"protection,omitempty"`
+	WorkflowAscodeHolder *Workflow              `json:"workflow_ascode_holder,omitempty" cli:"-"`
 }
 
 // UnmarshalJSON custom for last modified.
 func (e *Environment) UnmarshalJSON(data []byte) error {
 	var tmp struct {
-		ID             int64                 `json:"id"`
-		Name           string                `json:"name"`
-		Variables      []EnvironmentVariable `json:"variables"`
-		ProjectKey     string                `json:"project_key"`
-		Created        time.Time             `json:"created"`
-		Keys           []EnvironmentKey      `json:"keys"`
-		Usage          *Usage                `json:"usage"`
-		FromRepository string                `json:"from_repository"`
+		ID             int64                  `json:"id"`
+		Name           string                 `json:"name"`
+		Variables      []EnvironmentVariable  `json:"variables"`
+		ProjectKey     string                 `json:"project_key"`
+		Created        time.Time              `json:"created"`
+		Keys           []EnvironmentKey       `json:"keys"`
+		Usage          *Usage                 `json:"usage"`
+		FromRepository string                 `json:"from_repository"`
+		Protection     *EnvironmentProtection `json:"protection"`
 	}
 
 	if err := json.Unmarshal(data, &tmp); err != nil {
@@ -44,6 +46,7 @@ func (e *Environment) UnmarshalJSON(data []byte) error {
 	e.Keys = tmp.Keys
 	e.Usage = tmp.Usage
 	e.FromRepository = tmp.FromRepository
+	e.Protection = tmp.Protection
 
 	var v map[string]interface{}
 	if err := json.Unmarshal(data, &v); err != nil {
@@ -0,0 +1,187 @@
+package sdk
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gorhill/cronexpr"
+)
+
+// EnvironmentProtection contains the rules that a node run should respect to be started on an environment.
+type EnvironmentProtection struct {
+	// Git branches and tags allowed to be deployed, glob patterns are supported (ie. release/*)
+	Branches []string `json:"branches,omitempty" yaml:"branches,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Names of the workflows allowed to deploy on the environment
+	Workflows []string `json:"workflows,omitempty" yaml:"workflows,omitempty"`
+	// If set, node runs can only be started during a deployment window
+	DeploymentWindows []EnvironmentTimeWindow `json:"deployment_windows,omitempty" yaml:"deployment_windows,omitempty"`
+	// Node runs can't be started during a freeze period
+	FreezePeriods []EnvironmentTimeWindow `json:"freeze_periods,omitempty" yaml:"freeze_periods,omitempty"`
+	// Number of approvals required from members of given groups before starting a node run
+	RequiredApprovals int      `json:"required_approvals,omitempty" yaml:"required_approvals,omitempty"`
+	ApprovalGroups    []string `json:"approval_groups,omitempty" yaml:"approval_groups,omitempty"`
+}
+
+// EnvironmentTimeWindow is a period that starts at each occurrence of a cron expression and lasts for given duration.
+type EnvironmentTimeWindow struct {
+	Cron     string `json:"cron" yaml:"cron"`
+	Duration string `json:"duration" yaml:"duration"`
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+}
+
+// Value returns driver.Value from environment protection.
+func (p EnvironmentProtection) Value() (driver.Value, error) {
+	j, err := json.Marshal(p)
+	return j, WrapError(err, "cannot marshal EnvironmentProtection")
+}
+
+// Scan environment protection.
+func (p *EnvironmentProtection) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(JSONUnmarshal(source, p), "cannot unmarshal EnvironmentProtection")
+}
+
+// IsValid returns an error if a protection rule is not valid.
+func (p EnvironmentProtection) IsValid() error {
+	for _, pattern := range append(append([]string{}, p.Branches...), p.Tags...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid git pattern %q", pattern)
+		}
+	}
+	for _, w := range append(append([]EnvironmentTimeWindow{}, p.DeploymentWindows...), p.FreezePeriods...) {
+		if err := w.IsValid(); err != nil {
+			return err
+		}
+	}
+	if p.RequiredApprovals < 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid required approvals count %d", p.RequiredApprovals)
+	}
+	if p.RequiredApprovals > 0 && len(p.ApprovalGroups) == 0 {
+		return NewErrorFrom(ErrWrongRequest, "at least one group is required for approvals")
+	}
+	return nil
+}
+
+// IsEmpty returns true if there is no rule.
+func (p EnvironmentProtection) IsEmpty() bool {
+	return len(p.Branches) == 0 && len(p.Tags) == 0 && len(p.Workflows) == 0 &&
+		len(p.DeploymentWindows) == 0 && len(p.FreezePeriods) == 0 && p.RequiredApprovals == 0
+}
+
+// AllowsTag returns true if given git tag matches the tag rules.
+func (p EnvironmentProtection) AllowsTag(tag string) bool {
+	return tag != "" && matchOneOf(p.Tags, tag)
+}
+
+// Check returns the reason why a node run from given workflow and git ref can't be started at given time.
+// An empty string is returned if all the rules are respected.
+func (p EnvironmentProtection) Check(workflowName, branch, tag string, now time.Time) string {
+	if len(p.Workflows) > 0 && !IsInArray(workflowName, p.Workflows) {
+		return fmt.Sprintf("workflow %s is not allowed", workflowName)
+	}
+
+	if len(p.Branches) > 0 || len(p.Tags) > 0 {
+		allowed := p.AllowsTag(tag) || (branch != "" && matchOneOf(p.Branches, branch))
+		if !allowed {
+			switch {
+			case tag != "":
+				return fmt.Sprintf("git tag %s is not allowed", tag)
+			case branch != "":
+				return fmt.Sprintf("git branch %s is not allowed", branch)
+			default:
+				return "a git branch or tag is required"
+			}
+		}
+	}
+
+	for _, w := range p.FreezePeriods {
+		if w.Contains(now) {
+			return fmt.Sprintf("deployments are frozen (%s for %s)", w.Cron, w.Duration)
+		}
+	}
+
+	if len(p.DeploymentWindows) > 0 {
+		var inWindow bool
+		for _, w := range p.DeploymentWindows {
+			if w.Contains(now) {
+				inWindow = true
+				break
+			}
+		}
+		if !inWindow {
+			return "outside of deployment windows"
+		}
+	}
+
+	return ""
+}
+
+// AddApprovalGate adds the approvals required by the environment to the approval of a node run. The environment gate
+// is separate from the node one: only the members of the environment groups can give its approvals.
+func (p EnvironmentProtection) AddApprovalGate(a *WorkflowNodeRunApproval, now time.Time) *WorkflowNodeRunApproval {
+	if p.RequiredApprovals == 0 {
+		return a
+	}
+	if a == nil {
+		a = &WorkflowNodeRunApproval{
+			Status:  ApprovalStatusPending,
+			Created: now,
+		}
+	}
+	a.EnvironmentGroups = p.ApprovalGroups
+	a.EnvironmentRequiredApprovals = p.RequiredApprovals
+	return a
+}
+
+// IsValid returns an error if the cron expression, the duration or the timezone is not valid.
+func (w EnvironmentTimeWindow) IsValid() error {
+	if _, err := cronexpr.Parse(w.Cron); err != nil {
+		return NewErrorFrom(ErrWrongRequest, "invalid cron expression %q: %v", w.Cron, err)
+	}
+	if d, err := time.ParseDuration(w.Duration); err != nil || d <= 0 {
+		return NewErrorFrom(ErrWrongRequest, "invalid duration %q, duration expected (ie. 8h)", w.Duration)
+	}
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return NewErrorFrom(ErrWrongRequest, "invalid timezone %q", w.Timezone)
+	}
+	return nil
+}
+
+// Contains returns true if given time is in the window. An invalid window contains nothing.
+func (w EnvironmentTimeWindow) Contains(t time.Time) bool {
+	expr, err := cronexpr.Parse(w.Cron)
+	if err != nil {
+		return false
+	}
+	d, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return false
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return false
+	}
+	// The window contains t if it started between t-duration and t
+	start := expr.Next(t.Add(-d).In(loc))
+	return !start.IsZero() && !start.After(t)
+}
+
+func matchOneOf(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.TrimSpace(p), s); ok {
+			return true
+		}
+	}
+	return false
+}
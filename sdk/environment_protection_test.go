@@ -0,0 +1,101 @@
+package sdk_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestEnvironmentProtectionCheck(t *testing.T) {
+	p := sdk.EnvironmentProtection{
+		Branches:  []string{"master", "release/*"},
+		Tags:      []string{"v*"},
+		Workflows: []string{"deploy"},
+		// Monday to Friday, from 9:00 to 17:00
+		DeploymentWindows: []sdk.EnvironmentTimeWindow{{Cron: "0 9 * * 1-5", Duration: "8h", Timezone: "Europe/Paris"}},
+		// From Friday 12:00 to Monday
+		FreezePeriods: []sdk.EnvironmentTimeWindow{{Cron: "0 12 * * 5", Duration: "60h", Timezone: "Europe/Paris"}},
+	}
+	require.NoError(t, p.IsValid())
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	wednesdayMorning := time.Date(2021, 3, 3, 10, 0, 0, 0, paris)
+	wednesdayNight := time.Date(2021, 3, 3, 22, 0, 0, 0, paris)
+	fridayAfternoon := time.Date(2021, 3, 5, 14, 0, 0, 0, paris)
+
+	require.Empty(t, p.Check("deploy", "master", "", wednesdayMorning))
+	require.Empty(t, p.Check("deploy", "release/1.0", "", wednesdayMorning))
+	require.Empty(t, p.Check("deploy", "feat/foo", "v1.0.0", wednesdayMorning))
+	require.Empty(t, p.Check("deploy", "master", "", wednesdayMorning.UTC()))
+
+	require.Equal(t, "workflow build is not allowed", p.Check("build", "master", "", wednesdayMorning))
+	require.Equal(t, "git branch feat/foo is not allowed", p.Check("deploy", "feat/foo", "", wednesdayMorning))
+	require.Equal(t, "git tag foo is not allowed", p.Check("deploy", "feat/foo", "foo", wednesdayMorning))
+	require.Equal(t, "a git branch or tag is required", p.Check("deploy", "", "", wednesdayMorning))
+	require.Equal(t, "outside of deployment windows", p.Check("deploy", "master", "", wednesdayNight))
+	require.Contains(t, p.Check("deploy", "master", "", fridayAfternoon), "deployments are frozen")
+}
+
+func TestEnvironmentProtectionIsValid(t *testing.T) {
+	require.Error(t, sdk.EnvironmentProtection{Branches: []string{"["}}.IsValid())
+	require.Error(t, sdk.EnvironmentProtection{FreezePeriods: []sdk.EnvironmentTimeWindow{{Cron: "foo", Duration: "1h"}}}.IsValid())
+	require.Error(t, sdk.EnvironmentProtection{FreezePeriods: []sdk.EnvironmentTimeWindow{{Cron: "0 0 * * *", Duration: "-1h"}}}.IsValid())
+	require.Error(t, sdk.EnvironmentProtection{FreezePeriods: []sdk.EnvironmentTimeWindow{{Cron: "0 0 * * *", Duration: "1h", Timezone: "Mars/Olympus"}}}.IsValid())
+	require.Error(t, sdk.EnvironmentProtection{RequiredApprovals: 1}.IsValid())
+	require.NoError(t, sdk.EnvironmentProtection{RequiredApprovals: 1, ApprovalGroups: []string{"ops"}}.IsValid())
+}
+
+func TestEnvironmentProtectionApprovalGate(t *testing.T) {
+	now := time.Now()
+	p := sdk.EnvironmentProtection{RequiredApprovals: 2, ApprovalGroups: []string{"ops"}}
+
+	a := p.AddApprovalGate(nil, now)
+	require.NotNil(t, a)
+	require.True(t, a.IsPending())
+	require.Empty(t, a.Groups)
+	require.Equal(t, []string{"ops"}, a.EnvironmentGroups)
+	require.Equal(t, 2, a.EnvironmentRequiredApprovals)
+
+	require.Nil(t, sdk.EnvironmentProtection{}.AddApprovalGate(nil, now))
+}
+
+func TestEnvironmentProtectionApprovalGateIsSeparate(t *testing.T) {
+	now := time.Now()
+	p := sdk.EnvironmentProtection{RequiredApprovals: 2, ApprovalGroups: []string{"ops"}}
+	nodeGate := sdk.NodeApproval{Groups: []string{"devs"}, RequiredApprovals: 1}
+	a := p.AddApprovalGate(sdk.NewWorkflowNodeRunApproval(nodeGate, "", nil, now), now)
+	require.Equal(t, []string{"devs"}, a.Groups)
+	require.Equal(t, 1, a.RequiredApprovals)
+	require.Equal(t, 2, a.MinRequiredApprovals())
+
+	dev1 := sdk.AuthentifiedUser{Username: "dev1"}
+	dev2 := sdk.AuthentifiedUser{Username: "dev2"}
+	ops1 := sdk.AuthentifiedUser{Username: "ops1"}
+	ops2 := sdk.AuthentifiedUser{Username: "ops2"}
+
+	// Members of the node groups can't meet the approvals required by the environment
+	require.NoError(t, a.CanDecide(dev1, nil, []string{"devs"}))
+	a.AddDecision(dev1, []string{"devs"}, true, "", now)
+	require.NoError(t, a.CanDecide(dev2, nil, []string{"devs"}))
+	a.AddDecision(dev2, []string{"devs"}, true, "", now)
+	require.True(t, a.IsPending())
+	require.Equal(t, 0, a.EnvironmentApprovals())
+
+	require.NoError(t, a.CanDecide(ops1, nil, []string{"ops"}))
+	a.AddDecision(ops1, []string{"ops"}, true, "", now)
+	require.True(t, a.IsPending())
+	a.AddDecision(ops2, []string{"ops"}, true, "", now)
+	require.Equal(t, sdk.ApprovalStatusApproved, a.Status)
+
+	// Members of the environment groups can't meet the approvals required by the node
+	b := p.AddApprovalGate(sdk.NewWorkflowNodeRunApproval(nodeGate, "", nil, now), now)
+	b.AddDecision(ops1, []string{"ops"}, true, "", now)
+	b.AddDecision(ops2, []string{"ops"}, true, "", now)
+	require.True(t, b.IsPending())
+	b.AddDecision(dev1, []string{"devs"}, true, "", now)
+	require.Equal(t, sdk.ApprovalStatusApproved, b.Status)
+}
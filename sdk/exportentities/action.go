@@ -62,9 +62,13 @@ func NewAction(a sdk.Action) Action {
 }
 
 func newSteps(a sdk.Action) []Step {
-	res := make([]Step, len(a.Actions))
+	res := make([]Step, 0, len(a.Actions))
 	for i := range a.Actions {
-		res[i] = newStep(a.Actions[i])
+		// Steps that save caches are computed from cache steps
+		if isCacheSaveAction(a.Actions[i]) {
+			continue
+		}
+		res = append(res, newStep(a.Actions[i]))
 	}
 
 	return res
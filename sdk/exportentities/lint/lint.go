@@ -0,0 +1,489 @@
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/gorhill/cronexpr"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/interpolate"
+	"github.com/ovh/cds/sdk/luascript"
+)
+
+// Severity of an issue
+type Severity string
+
+// Available severities
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rules checked by the linter
+const (
+	RuleSyntax        = "syntax"
+	RuleReference     = "reference"
+	RuleInterpolation = "interpolation"
+	RuleRequirement   = "requirement"
+	RuleCondition     = "condition"
+	RuleHook          = "hook"
+)
+
+// Rules is the list of all rules with their description
+var Rules = map[string]string{
+	RuleSyntax:        "Files must be valid workflow, pipeline, application or environment files",
+	RuleReference:     "Pipelines, applications, environments and parameters used by a workflow must be declared",
+	RuleInterpolation: "Interpolated values must be valid and use declared parameters and variables",
+	RuleRequirement:   "Job requirements must be valid",
+	RuleCondition:     "Plain conditions must use known operators and lua conditions must be valid",
+	RuleHook:          "Hooks must use a known model with a valid configuration",
+}
+
+// Issue is a problem found in a file
+type Issue struct {
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	location := i.File
+	if i.Line > 0 {
+		location = fmt.Sprintf("%s:%d", i.File, i.Line)
+	}
+	return fmt.Sprintf("%s: %s [%s] %s", location, i.Severity, i.Rule, i.Message)
+}
+
+// Issues is a list of issues
+type Issues []Issue
+
+// HasErrors returns true if at least one issue has the error severity.
+func (is Issues) HasErrors() bool {
+	for _, i := range is {
+		if i.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+var interpolationVariableRegex = regexp.MustCompile(`\.cds\.(pip|app|env)\.([a-zA-Z0-9_\-.]+)`)
+
+// Dir lints all the as-code files (yml, yaml and json) found in given directory.
+func Dir(path string) (Issues, error) {
+	fis, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, sdk.WithStack(err)
+	}
+	files := make(map[string][]byte)
+	for _, fi := range fis {
+		if fi.IsDir() {
+			continue
+		}
+		if _, err := exportentities.GetFormatFromPath(fi.Name()); err != nil {
+			continue
+		}
+		filePath := filepath.Join(path, fi.Name())
+		btes, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, sdk.WithStack(err)
+		}
+		files[filePath] = btes
+	}
+	if len(files) == 0 {
+		return nil, sdk.WithStack(fmt.Errorf("no file found in %s", path))
+	}
+	return Files(files), nil
+}
+
+// Files lints given as-code files, keys are file paths. Files are classified like for a workflow push,
+// application, pipeline and environment files must contain respectively '.app.', '.pip.' and '.env.'.
+func Files(files map[string][]byte) Issues {
+	l := &linter{
+		files:        files,
+		pipelines:    make(map[string]pipelineFile),
+		applications: make(map[string]exportentities.Application),
+		environments: make(map[string]exportentities.Environment),
+	}
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var workflows []string
+	for _, p := range paths {
+		switch name := filepath.Base(p); {
+		case strings.Contains(name, ".app."):
+			l.loadApplication(p)
+		case strings.Contains(name, ".env."):
+			l.loadEnvironment(p)
+		case strings.Contains(name, ".pip."):
+			l.loadPipeline(p)
+		default:
+			workflows = append(workflows, p)
+		}
+	}
+	for _, p := range workflows {
+		l.lintWorkflow(p)
+	}
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		if l.issues[i].File != l.issues[j].File {
+			return l.issues[i].File < l.issues[j].File
+		}
+		return l.issues[i].Line < l.issues[j].Line
+	})
+	return l.issues
+}
+
+type pipelineFile struct {
+	path     string
+	pipeline *sdk.Pipeline
+	// variables used in pipeline values by type (app or env)
+	variables map[string][]string
+}
+
+type linter struct {
+	files        map[string][]byte
+	pipelines    map[string]pipelineFile
+	applications map[string]exportentities.Application
+	environments map[string]exportentities.Environment
+	issues       Issues
+}
+
+func (l *linter) add(path string, severity Severity, rule string, needle string, format string, args ...interface{}) {
+	l.issues = append(l.issues, Issue{
+		File:     path,
+		Line:     l.line(path, needle),
+		Rule:     rule,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// line returns the first line of the file that contains the needle, 0 if not found.
+// For a 'key: value' needle, the json form and then the value alone are also searched.
+func (l *linter) line(path string, needle string) int {
+	if needle == "" {
+		return 0
+	}
+	needles := []string{needle}
+	if kv := strings.SplitN(needle, ": ", 2); len(kv) == 2 {
+		needles = append(needles, fmt.Sprintf("%q: %q", kv[0], kv[1]), kv[1])
+	}
+	lines := bytes.Split(l.files[path], []byte("\n"))
+	for _, n := range needles {
+		for i, line := range lines {
+			if bytes.Contains(line, []byte(n)) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+func (l *linter) unmarshal(path string, i interface{}) bool {
+	format, err := exportentities.GetFormatFromPath(path)
+	if err != nil {
+		l.add(path, SeverityError, RuleSyntax, "", "%s", sdk.ExtractHTTPError(err))
+		return false
+	}
+	if err := exportentities.Unmarshal(l.files[path], format, i); err != nil {
+		l.add(path, SeverityError, RuleSyntax, "", "%s", sdk.ExtractHTTPError(err))
+		return false
+	}
+	return true
+}
+
+func (l *linter) loadApplication(path string) {
+	var app exportentities.Application
+	if !l.unmarshal(path, &app) {
+		return
+	}
+	if _, has := l.applications[app.Name]; has {
+		l.add(path, SeverityError, RuleReference, app.Name, "application %s is declared twice", app.Name)
+	}
+	l.applications[app.Name] = app
+	for name, v := range app.Variables {
+		l.lintInterpolation(path, v.Value, "variable "+name)
+	}
+}
+
+func (l *linter) loadEnvironment(path string) {
+	var env exportentities.Environment
+	if !l.unmarshal(path, &env) {
+		return
+	}
+	if _, has := l.environments[env.Name]; has {
+		l.add(path, SeverityError, RuleReference, env.Name, "environment %s is declared twice", env.Name)
+	}
+	l.environments[env.Name] = env
+	for name, v := range env.Values {
+		l.lintInterpolation(path, v.Value, "variable "+name)
+	}
+}
+
+func (l *linter) loadPipeline(path string) {
+	format, err := exportentities.GetFormatFromPath(path)
+	if err != nil {
+		l.add(path, SeverityError, RuleSyntax, "", "%s", sdk.ExtractHTTPError(err))
+		return
+	}
+	p, err := exportentities.ParsePipeline(format, l.files[path])
+	if err != nil {
+		l.add(path, SeverityError, RuleSyntax, "", "%s", sdk.ExtractHTTPError(err))
+		return
+	}
+	pip, err := p.Pipeline()
+	if err != nil {
+		l.add(path, SeverityError, RuleSyntax, "", "%s", sdk.ExtractHTTPError(err))
+		return
+	}
+	if _, has := l.pipelines[pip.Name]; has {
+		l.add(path, SeverityError, RuleReference, pip.Name, "pipeline %s is declared twice", pip.Name)
+	}
+
+	pf := pipelineFile{path: path, pipeline: pip, variables: make(map[string][]string)}
+	l.lintRequirements(path, format)
+
+	var values []string
+	for _, s := range pip.Stages {
+		l.lintConditions(path, s.Conditions, "stage "+s.Name)
+		for _, j := range s.Jobs {
+			if err := sdk.RequirementList(j.Action.Requirements).IsValid(); err != nil {
+				l.add(path, SeverityError, RuleRequirement, j.Action.Name, "job %s: %s", j.Action.Name, sdk.ExtractHTTPError(err))
+			}
+			for _, r := range j.Action.Requirements {
+				if r.Type == sdk.OSArchRequirement && !sdk.IsInArray(r.Value, sdk.OSArchRequirementValues.Values()) {
+					l.add(path, SeverityWarning, RuleRequirement, r.Value, "job %s: unknown os-architecture %s", j.Action.Name, r.Value)
+				}
+				values = append(values, r.Value)
+			}
+			for i, a := range j.Action.Actions {
+				stepName := fmt.Sprintf("job %s step #%d", j.Action.Name, i+1)
+				if a.Conditions != nil {
+					l.lintConditions(path, *a.Conditions, stepName)
+				}
+				for _, p := range a.Parameters {
+					l.lintInterpolation(path, p.Value, stepName)
+					values = append(values, p.Value)
+				}
+			}
+		}
+	}
+
+	for _, v := range values {
+		for _, m := range interpolationVariableRegex.FindAllStringSubmatch(v, -1) {
+			if m[1] == "pip" {
+				if sdk.ParameterFind(pip.Parameter, m[2]) == nil {
+					l.add(path, SeverityWarning, RuleInterpolation, m[0], "parameter %s is not declared in pipeline %s", m[2], pip.Name)
+				}
+				continue
+			}
+			if !sdk.IsInArray(m[2], pf.variables[m[1]]) {
+				pf.variables[m[1]] = append(pf.variables[m[1]], m[2])
+			}
+		}
+	}
+
+	l.pipelines[pip.Name] = pf
+}
+
+// lintRequirements checks the requirements types on raw data because unknown types are ignored by the parser.
+func (l *linter) lintRequirements(path string, format exportentities.Format) {
+	var raw struct {
+		Jobs []struct {
+			Name         string                   `json:"job" yaml:"job"`
+			Requirements []map[string]interface{} `json:"requirements" yaml:"requirements"`
+		} `json:"jobs" yaml:"jobs"`
+	}
+	if err := exportentities.Unmarshal(l.files[path], format, &raw); err != nil {
+		return
+	}
+	for _, j := range raw.Jobs {
+		for _, r := range j.Requirements {
+			for t := range r {
+				if !sdk.IsInArray(t, sdk.AvailableRequirementsType) {
+					l.add(path, SeverityError, RuleRequirement, t+":", "job %s: unknown requirement type %s", j.Name, t)
+				}
+			}
+			if len(r) > 1 {
+				l.add(path, SeverityError, RuleRequirement, j.Name, "job %s: a requirement must have a single type", j.Name)
+			}
+		}
+	}
+}
+
+// lintInterpolation checks each line of the value, unknown variables are allowed because they can be
+// provided by the run context.
+func (l *linter) lintInterpolation(path string, value string, context string) {
+	for _, line := range strings.Split(value, "\n") {
+		if _, err := interpolate.Do(line, nil); err != nil {
+			// interpolate error contains the escaped input, get the error of the raw line instead if possible
+			if _, perr := template.New("").Funcs(interpolate.InterpolateHelperFuncs).Parse(line); perr != nil {
+				err = perr
+			}
+			l.add(path, SeverityError, RuleInterpolation, strings.TrimSpace(line), "%s: invalid interpolation in %q: %v", context, strings.TrimSpace(line), err)
+		}
+	}
+}
+
+func (l *linter) lintConditions(path string, conditions sdk.WorkflowNodeConditions, context string) {
+	for _, c := range conditions.PlainConditions {
+		if _, has := sdk.WorkflowConditionsOperators[c.Operator]; !has {
+			l.add(path, SeverityError, RuleCondition, c.Operator, "%s: unknown operator %q for condition on %s", context, c.Operator, c.Variable)
+		}
+		if c.Operator == sdk.WorkflowConditionsOperatorRegex {
+			if _, err := regexp.Compile(c.Value); err != nil {
+				l.add(path, SeverityError, RuleCondition, c.Value, "%s: invalid regex for condition on %s: %v", context, c.Variable, err)
+			}
+		}
+		l.lintInterpolation(path, c.Value, context)
+	}
+	if conditions.LuaScript != "" {
+		if err := luascript.CheckSyntax(conditions.LuaScript); err != nil {
+			l.add(path, SeverityError, RuleCondition, firstLine(conditions.LuaScript), "%s: invalid lua script: %v", context, err)
+		}
+	}
+}
+
+func (l *linter) lintWorkflow(path string) {
+	format, err := exportentities.GetFormatFromPath(path)
+	if err != nil {
+		l.add(path, SeverityError, RuleSyntax, "", "%s", sdk.ExtractHTTPError(err))
+		return
+	}
+	ew, err := exportentities.UnmarshalWorkflow(l.files[path], format)
+	if err != nil {
+		l.add(path, SeverityError, RuleSyntax, "", "%s", sdk.ExtractHTTPError(err))
+		return
+	}
+	w, err := exportentities.ParseWorkflow(ew)
+	if err != nil {
+		l.add(path, SeverityError, RuleSyntax, "", "%s", sdk.ExtractHTTPError(err))
+		return
+	}
+
+	for _, n := range w.WorkflowData.Array() {
+		switch n.Type {
+		case sdk.NodeTypePipeline:
+			l.lintNode(path, n)
+		case sdk.NodeTypeOutGoingHook:
+			if n.OutGoingHookContext != nil {
+				m := sdk.GetBuiltinOutgoingHookModelByName(n.OutGoingHookContext.HookModelName)
+				if m == nil {
+					l.add(path, SeverityError, RuleHook, "trigger: "+n.OutGoingHookContext.HookModelName, "node %s: unknown outgoing hook model %s", n.Name, n.OutGoingHookContext.HookModelName)
+				} else {
+					l.lintHookConfig(path, n.Name, *m, n.OutGoingHookContext.Config)
+				}
+			}
+		}
+		l.lintConditions(path, n.Context.Conditions, "node "+n.Name)
+		for _, h := range n.Hooks {
+			m := sdk.GetBuiltinHookModelByName(h.HookModelName)
+			if m == nil {
+				l.add(path, SeverityError, RuleHook, "type: "+h.HookModelName, "node %s: unknown hook model %s", n.Name, h.HookModelName)
+				continue
+			}
+			l.lintHookConfig(path, n.Name, *m, h.Config)
+			l.lintConditions(path, h.Conditions, "hook "+h.HookModelName)
+		}
+	}
+}
+
+func (l *linter) lintNode(path string, n *sdk.Node) {
+	ctx := n.Context
+	if ctx == nil {
+		return
+	}
+
+	pf, hasPipeline := l.pipelines[ctx.PipelineName]
+	if !hasPipeline {
+		l.add(path, SeverityError, RuleReference, "pipeline: "+ctx.PipelineName, "node %s: pipeline %s not found", n.Name, ctx.PipelineName)
+	}
+	app, hasApplication := l.applications[ctx.ApplicationName]
+	if ctx.ApplicationName != "" && !hasApplication {
+		l.add(path, SeverityError, RuleReference, "application: "+ctx.ApplicationName, "node %s: application %s not found", n.Name, ctx.ApplicationName)
+	}
+	env, hasEnvironment := l.environments[ctx.EnvironmentName]
+	if ctx.EnvironmentName != "" && !hasEnvironment {
+		l.add(path, SeverityError, RuleReference, "environment: "+ctx.EnvironmentName, "node %s: environment %s not found", n.Name, ctx.EnvironmentName)
+	}
+
+	for _, p := range ctx.DefaultPipelineParameters {
+		l.lintInterpolation(path, p.Value, "node "+n.Name)
+		if hasPipeline && sdk.ParameterFind(pf.pipeline.Parameter, p.Name) == nil {
+			l.add(path, SeverityError, RuleReference, p.Name, "node %s: parameter %s is not declared in pipeline %s", n.Name, p.Name, pf.pipeline.Name)
+		}
+	}
+
+	if !hasPipeline {
+		return
+	}
+	if hasApplication {
+		for _, v := range pf.variables["app"] {
+			if !isDeclared(v, app.Variables, app.Keys) {
+				l.add(pf.path, SeverityWarning, RuleInterpolation, "cds.app."+v, "variable %s used by pipeline %s is not declared in application %s", v, pf.pipeline.Name, app.Name)
+			}
+		}
+	}
+	if hasEnvironment {
+		for _, v := range pf.variables["env"] {
+			if !isDeclared(v, env.Values, env.Keys) {
+				l.add(pf.path, SeverityWarning, RuleInterpolation, "cds.env."+v, "variable %s used by pipeline %s is not declared in environment %s", v, pf.pipeline.Name, env.Name)
+			}
+		}
+	}
+}
+
+func (l *linter) lintHookConfig(path string, nodeName string, m sdk.WorkflowHookModel, cfg sdk.WorkflowNodeHookConfig) {
+	for k, v := range cfg {
+		dft, has := m.DefaultConfig[k]
+		if !has {
+			l.add(path, SeverityWarning, RuleHook, k, "node %s: unknown configuration %s for hook %s", nodeName, k, m.Name)
+			continue
+		}
+		if !dft.Configurable {
+			l.add(path, SeverityWarning, RuleHook, k, "node %s: configuration %s for hook %s is not configurable and will be ignored", nodeName, k, m.Name)
+		}
+		if len(dft.MultipleChoiceList) > 0 && !sdk.IsInArray(v.Value, dft.MultipleChoiceList) {
+			l.add(path, SeverityError, RuleHook, v.Value, "node %s: invalid value %q for %s, expected one of %s", nodeName, v.Value, k, strings.Join(dft.MultipleChoiceList, ", "))
+		}
+	}
+	if m.Name == sdk.SchedulerModelName {
+		if cron, has := cfg[sdk.SchedulerModelCron]; has {
+			if _, err := cronexpr.Parse(cron.Value); err != nil {
+				l.add(path, SeverityError, RuleHook, cron.Value, "node %s: invalid cron expression %q: %v", nodeName, cron.Value, err)
+			}
+		}
+	}
+}
+
+// isDeclared checks if a variable is declared or comes from a key (ex: cds.app.mykey.pub).
+func isDeclared(name string, variables map[string]exportentities.VariableValue, keys map[string]exportentities.KeyValue) bool {
+	if _, has := variables[name]; has {
+		return true
+	}
+	for k := range keys {
+		if strings.HasPrefix(name, k+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func firstLine(s string) string {
+	return strings.TrimSpace(strings.SplitN(s, "\n", 2)[0])
+}
@@ -0,0 +1,107 @@
+package lint_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk/exportentities/lint"
+)
+
+func TestFiles(t *testing.T) {
+	files := map[string][]byte{
+		".cds/my-workflow.yml": []byte(`version: v2.0
+name: my-workflow
+workflow:
+  build:
+    pipeline: build
+    application: my-app
+    parameters:
+      unknown: value
+  deploy:
+    depends_on:
+    - build
+    pipeline: deploy
+    environment: my-env
+    conditions:
+      script: return cds_status ==
+hooks:
+  build:
+  - type: Scheduler
+    config:
+      cron: "not a cron"
+  - type: Unknown`),
+		".cds/build.pip.yml": []byte(`version: v1.0
+name: build
+parameters:
+  branch:
+    type: string
+jobs:
+- job: Build
+  requirements:
+  - binary: git
+  - gpu: nvidia
+  steps:
+  - script:
+    - echo {{.cds.pip.branch}} {{.cds.pip.tag}}
+    - echo {{.cds.app.version}} {{.cds.app.missing}}
+    - echo {{.cds.pip.branch | upper}`),
+		".cds/my-app.app.yml": []byte(`version: v1.0
+name: my-app
+variables:
+  version:
+    type: string
+    value: "1.0.0"`),
+	}
+
+	issues := lint.Files(files)
+	for _, i := range issues {
+		t.Log(i.String())
+	}
+	require.True(t, issues.HasErrors())
+
+	expected := []lint.Issue{
+		{File: ".cds/build.pip.yml", Line: 10, Rule: lint.RuleRequirement, Severity: lint.SeverityError, Message: "job Build: unknown requirement type gpu"},
+		{File: ".cds/build.pip.yml", Line: 13, Rule: lint.RuleInterpolation, Severity: lint.SeverityWarning, Message: "parameter tag is not declared in pipeline build"},
+		{File: ".cds/build.pip.yml", Line: 14, Rule: lint.RuleInterpolation, Severity: lint.SeverityWarning, Message: "variable missing used by pipeline build is not declared in application my-app"},
+		{File: ".cds/build.pip.yml", Line: 15, Rule: lint.RuleInterpolation, Severity: lint.SeverityError},
+		{File: ".cds/my-workflow.yml", Line: 8, Rule: lint.RuleReference, Severity: lint.SeverityError, Message: "node build: parameter unknown is not declared in pipeline build"},
+		{File: ".cds/my-workflow.yml", Line: 12, Rule: lint.RuleReference, Severity: lint.SeverityError, Message: "node deploy: pipeline deploy not found"},
+		{File: ".cds/my-workflow.yml", Line: 13, Rule: lint.RuleReference, Severity: lint.SeverityError, Message: "node deploy: environment my-env not found"},
+		{File: ".cds/my-workflow.yml", Line: 15, Rule: lint.RuleCondition, Severity: lint.SeverityError},
+		{File: ".cds/my-workflow.yml", Line: 20, Rule: lint.RuleHook, Severity: lint.SeverityError},
+		{File: ".cds/my-workflow.yml", Line: 21, Rule: lint.RuleHook, Severity: lint.SeverityError, Message: "node build: unknown hook model Unknown"},
+	}
+
+	for _, e := range expected {
+		var found bool
+		for _, i := range issues {
+			if i.File == e.File && i.Line == e.Line && i.Rule == e.Rule && i.Severity == e.Severity && (e.Message == "" || e.Message == i.Message) {
+				found = true
+				break
+			}
+		}
+		assert.True(t, found, "issue %+v not found", e)
+	}
+}
+
+func TestIssuesSARIF(t *testing.T) {
+	issues := lint.Issues{
+		{File: ".cds/my-workflow.yml", Line: 3, Rule: lint.RuleReference, Severity: lint.SeverityError, Message: "node build: pipeline build not found"},
+	}
+	btes, err := issues.SARIF("snapshot")
+	require.NoError(t, err)
+
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(btes, &res))
+	assert.Equal(t, "2.1.0", res["version"])
+	runs := res["runs"].([]interface{})
+	require.Len(t, runs, 1)
+	results := runs[0].(map[string]interface{})["results"].([]interface{})
+	require.Len(t, results, 1)
+	result := results[0].(map[string]interface{})
+	assert.Equal(t, "reference", result["ruleId"])
+	assert.Equal(t, "error", result["level"])
+}
@@ -0,0 +1,108 @@
+package lint
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sort"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// SARIF 2.1.0 structures, only used fields are defined
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// SARIF returns issues as a SARIF 2.1.0 log that can be used for code scanning annotations.
+func (is Issues) SARIF(toolVersion string) ([]byte, error) {
+	ruleIDs := make([]string, 0, len(Rules))
+	for id := range Rules {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+
+	driver := sarifDriver{
+		Name:           "cdsctl lint",
+		Version:        toolVersion,
+		InformationURI: "https://ovh.github.io/cds/",
+	}
+	for _, id := range ruleIDs {
+		driver.Rules = append(driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: Rules[id]}})
+	}
+
+	results := make([]sarifResult, 0, len(is))
+	for _, i := range is {
+		loc := sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(i.File)},
+		}
+		if i.Line > 0 {
+			loc.Region = &sarifRegion{StartLine: i.Line}
+		}
+		results = append(results, sarifResult{
+			RuleID:    i.Rule,
+			Level:     string(i.Severity),
+			Message:   sarifMessage{Text: i.Message},
+			Locations: []sarifLocation{{PhysicalLocation: loc}},
+		})
+	}
+
+	btes, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}, "", "  ")
+	return btes, sdk.WithStack(err)
+}
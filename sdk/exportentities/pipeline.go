@@ -57,6 +57,7 @@ type Requirement struct {
 	Plugin            string             `json:"plugin,omitempty" yaml:"plugin,omitempty"`
 	Service           ServiceRequirement `json:"service,omitempty" yaml:"service,omitempty"`
 	Memory            string             `json:"memory,omitempty" yaml:"memory,omitempty"`
+	CPU               string             `json:"cpu,omitempty" yaml:"cpu,omitempty"`
 	OSArchRequirement string             `json:"os-architecture,omitempty" yaml:"os-architecture,omitempty"`
 	RegionRequirement string             `json:"region,omitempty" yaml:"region,omitempty"`
 }
@@ -152,6 +153,8 @@ func newRequirements(req []sdk.Requirement) []Requirement {
 			res = append(res, Requirement{RegionRequirement: r.Value})
 		case sdk.MemoryRequirement:
 			res = append(res, Requirement{Memory: r.Value})
+		case sdk.CPURequirement:
+			res = append(res, Requirement{CPU: r.Value})
 		}
 	}
 	return res
@@ -182,15 +185,20 @@ func newJobs(jobs []sdk.Job) map[string]Job {
 }
 
 func computeSteps(steps []Step) ([]sdk.Action, error) {
-	res := make([]sdk.Action, len(steps))
-	for i, s := range steps {
+	res := make([]sdk.Action, 0, len(steps))
+	var saveCaches []sdk.Action
+	for _, s := range steps {
 		a, err := s.toAction()
 		if err != nil {
 			return nil, err
 		}
-		res[i] = *a
+		res = append(res, *a)
+		// Caches are restored by the step and saved at the end of the job
+		if s.isCache() {
+			saveCaches = append(saveCaches, newCacheSaveAction(*a))
+		}
 	}
-	return res, nil
+	return append(res, saveCaches...), nil
 }
 
 func computeJobRequirements(req []Requirement) []sdk.Requirement {
@@ -209,6 +217,10 @@ func computeJobRequirements(req []Requirement) []sdk.Requirement {
 			name = "memory"
 			val = r.Memory
 			tpe = sdk.MemoryRequirement
+		} else if r.CPU != "" {
+			name = "cpu"
+			val = r.CPU
+			tpe = sdk.CPURequirement
 		} else if r.Model != "" {
 			name = "model"
 			val = r.Model
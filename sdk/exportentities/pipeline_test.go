@@ -602,6 +602,43 @@ jobs:
 	assert.Len(t, p.Stages[0].Jobs[0].Action.Actions[0].Parameters, 1)
 }
 
+func Test_ImportPipelineWithCache(t *testing.T) {
+	in := `name: build
+jobs:
+- job: build
+  steps:
+  - name: go modules
+    cache:
+      key: go-{{hashFiles "go.sum"}}
+      restoreKeys:
+      - go-
+      paths:
+      - vendor
+  - script: go build ./...
+`
+
+	payload := &exportentities.PipelineV1{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	steps := p.Stages[0].Jobs[0].Action.Actions
+	assert.Len(t, steps, 3)
+	assert.Equal(t, sdk.CacheAction, steps[0].Name)
+	assert.Equal(t, sdk.CacheActionModeRestore, sdk.ParameterValue(steps[0].Parameters, "mode"))
+	assert.Equal(t, sdk.ScriptAction, steps[1].Name)
+	assert.Equal(t, sdk.CacheAction, steps[2].Name)
+	assert.Equal(t, "Save go modules", steps[2].StepName)
+	assert.Equal(t, sdk.CacheActionModeSave, sdk.ParameterValue(steps[2].Parameters, "mode"))
+	assert.Equal(t, `go-{{hashFiles "go.sum"}}`, sdk.ParameterValue(steps[2].Parameters, "key"))
+
+	// The save step should not be exported
+	exported := exportentities.NewPipelineV1(*p)
+	assert.Len(t, exported.Jobs[0].Steps, 2)
+	assert.Equal(t, payload.Jobs[0].Steps[0].Cache, exported.Jobs[0].Steps[0].Cache)
+}
+
 func Test_ImportPipelineWithOneStageAndRunConditions(t *testing.T) {
 	in := `version: v1.0
 name: echo
@@ -26,6 +26,9 @@ func newStep(act sdk.Action) Step {
 	if act.AlwaysExecuted {
 		s.AlwaysExecuted = &sdk.True
 	}
+	if act.Conditions != nil && !act.Conditions.IsEmpty() {
+		s.Conditions = act.Conditions
+	}
 
 	switch act.Type {
 	case sdk.BuiltinAction:
@@ -230,6 +233,20 @@ func newStep(act sdk.Action) Step {
 		case sdk.DeployApplicationAction:
 			step := StepDeploy("{{.cds.application}}")
 			s.Deploy = &step
+		case sdk.CacheAction:
+			s.Cache = &StepCache{}
+			key := sdk.ParameterFind(act.Parameters, "key")
+			if key != nil {
+				s.Cache.Key = key.Value
+			}
+			restoreKeys := sdk.ParameterFind(act.Parameters, "restoreKeys")
+			if restoreKeys != nil && restoreKeys.Value != "" {
+				s.Cache.RestoreKeys = strings.Split(restoreKeys.Value, "\n")
+			}
+			paths := sdk.ParameterFind(act.Parameters, "paths")
+			if paths != nil && paths.Value != "" {
+				s.Cache.Paths = strings.Split(paths.Value, "\n")
+			}
 		}
 	default:
 		args := make(StepParameters)
@@ -340,13 +357,21 @@ type StepInstallKey interface{}
 // StepDeploy represents exported push build info step.
 type StepDeploy string
 
+// StepCache represents exported cache step.
+type StepCache struct {
+	Key         string   `json:"key,omitempty" yaml:"key,omitempty" jsonschema:"required"`
+	RestoreKeys []string `json:"restoreKeys,omitempty" yaml:"restoreKeys,omitempty"`
+	Paths       []string `json:"paths,omitempty" yaml:"paths,omitempty" jsonschema:"required"`
+}
+
 // Step represents exported step used in a job.
 type Step struct {
 	// common step data
-	Name           string `json:"name,omitempty" yaml:"name,omitempty" jsonschema_description:"The name for this step."`
-	Enabled        *bool  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
-	Optional       *bool  `json:"optional,omitempty" yaml:"optional,omitempty"`
-	AlwaysExecuted *bool  `json:"always_executed,omitempty" yaml:"always_executed,omitempty"`
+	Name           string                      `json:"name,omitempty" yaml:"name,omitempty" jsonschema_description:"The name for this step."`
+	Enabled        *bool                       `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Optional       *bool                       `json:"optional,omitempty" yaml:"optional,omitempty"`
+	AlwaysExecuted *bool                       `json:"always_executed,omitempty" yaml:"always_executed,omitempty"`
+	Conditions     *sdk.WorkflowNodeConditions `json:"conditions,omitempty" yaml:"conditions,omitempty" jsonschema_description:"Conditions to run this step, it will be skipped if they are not satisfied.\nhttps://ovh.github.io/cds/docs/concepts/workflow/run-conditions."`
 	// step specific data, only one option should be set
 	StepCustom       `json:"-" yaml:",inline"`
 	Script           interface{}           `json:"script,omitempty" yaml:"script,omitempty" jsonschema:"oneof_type=string;array,oneof_required=actionScript" jsonschema_description:"Script.\nhttps://ovh.github.io/cds/docs/actions/builtin-script"`
@@ -363,6 +388,7 @@ type Step struct {
 	Checkout         *StepCheckout         `json:"checkout,omitempty" yaml:"checkout,omitempty" jsonschema:"oneof_required=actionCheckout" jsonschema_description:"Checkout repository for an application.\nhttps://ovh.github.io/cds/docs/actions/builtin-checkoutapplication"`
 	InstallKey       *StepInstallKey       `json:"installKey,omitempty" yaml:"installKey,omitempty" jsonschema:"oneof_required=actionInstallKey" jsonschema_description:"Install a key (GPG, SSH) in your current workspace.\nhttps://ovh.github.io/cds/docs/actions/builtin-installkey"`
 	Deploy           *StepDeploy           `json:"deploy,omitempty" yaml:"deploy,omitempty" jsonschema:"oneof_required=actionDeploy" jsonschema_description:"Deploy an application.\nhttps://ovh.github.io/cds/docs/actions/builtin-deployapplication"`
+	Cache            *StepCache            `json:"cache,omitempty" yaml:"cache,omitempty" jsonschema:"oneof_required=actionCache" jsonschema_description:"Restore a worker cache, the cache is saved at the end of the job.\nhttps://ovh.github.io/cds/docs/actions/builtin-cache"`
 }
 
 // MarshalJSON custom marshal json impl to inline custom step.
@@ -488,6 +514,9 @@ func (s Step) IsValid() bool {
 	if s.isPushBuildInfo() {
 		count++
 	}
+	if s.isCache() {
+		count++
+	}
 	count += len(s.StepCustom)
 
 	return count == 1
@@ -528,6 +557,8 @@ func (s Step) toAction() (*sdk.Action, error) {
 		a, err = s.asScript()
 	} else if s.isPushBuildInfo() {
 		a = s.asPushBuildInfo()
+	} else if s.isCache() {
+		a, err = s.asCache()
 	} else {
 		a = s.asAction()
 	}
@@ -539,6 +570,9 @@ func (s Step) toAction() (*sdk.Action, error) {
 	a.Enabled = s.Enabled == nil || *s.Enabled == sdk.True // enabled is true by default
 	a.Optional = s.Optional != nil && *s.Optional == sdk.True
 	a.AlwaysExecuted = s.AlwaysExecuted != nil && *s.AlwaysExecuted == sdk.True
+	if s.Conditions != nil && !s.Conditions.IsEmpty() {
+		a.Conditions = s.Conditions
+	}
 
 	return &a, nil
 }
@@ -664,6 +698,49 @@ func (s Step) asPushBuildInfo() sdk.Action {
 	}
 }
 
+func (s Step) isCache() bool { return s.Cache != nil }
+
+func (s Step) asCache() (sdk.Action, error) {
+	if s.Cache.Key == "" {
+		return sdk.Action{}, sdk.NewErrorFrom(sdk.ErrMalformattedStep, "missing key for cache action")
+	}
+	if len(s.Cache.Paths) == 0 {
+		return sdk.Action{}, sdk.NewErrorFrom(sdk.ErrMalformattedStep, "missing paths for cache action")
+	}
+	return sdk.Action{
+		Name: sdk.CacheAction,
+		Type: sdk.BuiltinAction,
+		Parameters: []sdk.Parameter{
+			{Name: "key", Value: s.Cache.Key, Type: sdk.StringParameter},
+			{Name: "restoreKeys", Value: strings.Join(s.Cache.RestoreKeys, "\n"), Type: sdk.TextParameter},
+			{Name: "paths", Value: strings.Join(s.Cache.Paths, "\n"), Type: sdk.TextParameter},
+			{Name: "mode", Value: sdk.CacheActionModeRestore, Type: sdk.StringParameter},
+		},
+	}, nil
+}
+
+// newCacheSaveAction returns the step that saves the cache restored by given cache step
+func newCacheSaveAction(restore sdk.Action) sdk.Action {
+	save := restore
+	save.StepName = "Save cache"
+	if restore.StepName != "" {
+		save.StepName = "Save " + restore.StepName
+	}
+	save.Parameters = make([]sdk.Parameter, 0, len(restore.Parameters))
+	for _, p := range restore.Parameters {
+		if p.Name == "mode" {
+			p.Value = sdk.CacheActionModeSave
+		}
+		save.Parameters = append(save.Parameters, p)
+	}
+	return save
+}
+
+// isCacheSaveAction returns true for steps added at the end of a job to save a cache
+func isCacheSaveAction(a sdk.Action) bool {
+	return a.Type == sdk.BuiltinAction && a.Name == sdk.CacheAction && sdk.ParameterValue(a.Parameters, "mode") == sdk.CacheActionModeSave
+}
+
 func (s Step) isCoverage() bool { return s.Coverage != nil }
 
 func (s Step) asCoverage() (sdk.Action, error) {
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	yaml "gopkg.in/yaml.v2"
 
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/exportentities"
 )
 
@@ -68,6 +69,35 @@ var tests = []struct {
 		Json: `{"script":["line1","line2"]}`,
 		Yaml: "script:\n- line1\n- line2\n",
 	},
+	{
+		Name: "Step with conditions",
+		Step: exportentities.Step{
+			Conditions: &sdk.WorkflowNodeConditions{
+				PlainConditions: []sdk.WorkflowNodeCondition{{
+					Variable: "cds.git.branch",
+					Operator: "eq",
+					Value:    "master",
+				}},
+			},
+			Script: []interface{}{
+				"line1",
+			},
+		},
+		Json: `{"conditions":{"plain":[{"variable":"cds.git.branch","operator":"eq","value":"master"}]},"script":["line1"]}`,
+		Yaml: "conditions:\n  check:\n  - variable: cds.git.branch\n    operator: eq\n    value: master\nscript:\n- line1\n",
+	},
+	{
+		Name: "Cache step",
+		Step: exportentities.Step{
+			Cache: &exportentities.StepCache{
+				Key:         "go-{{.cds.version}}",
+				RestoreKeys: []string{"go-"},
+				Paths:       []string{"vendor"},
+			},
+		},
+		Json: `{"cache":{"key":"go-{{.cds.version}}","restoreKeys":["go-"],"paths":["vendor"]}}`,
+		Yaml: "cache:\n  key: go-{{.cds.version}}\n  restoreKeys:\n  - go-\n  paths:\n  - vendor\n",
+	},
 }
 
 func TestMarshal(t *testing.T) {
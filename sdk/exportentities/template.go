@@ -22,17 +22,31 @@ type Template struct {
 	Group        string              `json:"group" yaml:"group"`
 	Description  string              `json:"description,omitempty" yaml:"description,omitempty"`
 	Parameters   []TemplateParameter `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Extends      string              `json:"extends,omitempty" yaml:"extends,omitempty"`
+	Imports      []TemplateImport    `json:"imports,omitempty" yaml:"imports,omitempty"`
 	Workflow     string
 	Pipelines    []string
 	Applications []string
 	Environments []string
+	Blocks       string `json:"blocks,omitempty" yaml:"blocks,omitempty"`
+}
+
+// TemplateImport is the "as code" representation of a sdk.WorkflowTemplateImport.
+type TemplateImport struct {
+	From         string   `json:"from" yaml:"from"`
+	Pipelines    []string `json:"pipelines,omitempty" yaml:"pipelines,omitempty"`
+	Environments []string `json:"environments,omitempty" yaml:"environments,omitempty"`
 }
 
 // TemplateParameter is the "as code" representation of a sdk.TemplateParameter.
 type TemplateParameter struct {
-	Key      string `json:"key" yaml:"key"`
-	Type     string `json:"type" yaml:"type"`
-	Required bool   `json:"required" yaml:"required"`
+	Key      string   `json:"key" yaml:"key"`
+	Type     string   `json:"type" yaml:"type"`
+	Required bool     `json:"required" yaml:"required"`
+	Choices  []string `json:"choices,omitempty" yaml:"choices,omitempty"`
+	Pattern  string   `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Min      *int64   `json:"min,omitempty" yaml:"min,omitempty"`
+	Max      *int64   `json:"max,omitempty" yaml:"max,omitempty"`
 }
 
 // Name pattern for template files.
@@ -41,8 +55,48 @@ const (
 	TemplatePipelineName    = "%d.pipeline.yml"
 	TemplateApplicationName = "%d.application.yml"
 	TemplateEnvironmentName = "%d.environment.yml"
+	TemplateBlocksName      = "blocks.tmpl"
+
+	// Named pipelines and environments can be imported by other templates
+	TemplateNamedPipelineName    = "%s.pipeline.yml"
+	TemplateNamedApplicationName = "%s.application.yml"
+	TemplateNamedEnvironmentName = "%s.environment.yml"
 )
 
+// TemplatePipelineFileName returns the file name of the pipeline at given index.
+func TemplatePipelineFileName(p sdk.PipelineTemplate, i int) string {
+	if p.Name != "" {
+		return fmt.Sprintf(TemplateNamedPipelineName, p.Name)
+	}
+	return fmt.Sprintf(TemplatePipelineName, i+1)
+}
+
+// TemplateApplicationFileName returns the file name of the application at given index.
+func TemplateApplicationFileName(a sdk.ApplicationTemplate, i int) string {
+	if a.Name != "" {
+		return fmt.Sprintf(TemplateNamedApplicationName, a.Name)
+	}
+	return fmt.Sprintf(TemplateApplicationName, i+1)
+}
+
+// TemplateEnvironmentFileName returns the file name of the environment at given index.
+func TemplateEnvironmentFileName(e sdk.EnvironmentTemplate, i int) string {
+	if e.Name != "" {
+		return fmt.Sprintf(TemplateNamedEnvironmentName, e.Name)
+	}
+	return fmt.Sprintf(TemplateEnvironmentName, i+1)
+}
+
+// templateFragmentName returns the name of a pipeline, application or environment from its file name, files named with their
+// index (ex: 1.pipeline.yml) have no name.
+func templateFragmentName(fileName, kind string) string {
+	name := strings.Split(filepath.Base(fileName), "."+kind+".")[0]
+	if _, err := strconv.Atoi(name); err == nil {
+		return ""
+	}
+	return name
+}
+
 // NewTemplate creates a new exportable workflow template.
 func NewTemplate(wt sdk.WorkflowTemplate) (Template, error) {
 	exportedTemplate := Template{
@@ -61,16 +115,32 @@ func NewTemplate(wt sdk.WorkflowTemplate) (Template, error) {
 		exportedTemplate.Parameters[i].Key = p.Key
 		exportedTemplate.Parameters[i].Type = string(p.Type)
 		exportedTemplate.Parameters[i].Required = p.Required
+		exportedTemplate.Parameters[i].Choices = p.Choices
+		exportedTemplate.Parameters[i].Pattern = p.Pattern
+		exportedTemplate.Parameters[i].Min = p.Min
+		exportedTemplate.Parameters[i].Max = p.Max
+	}
+
+	exportedTemplate.Extends = wt.Extends
+	for _, i := range wt.Imports {
+		exportedTemplate.Imports = append(exportedTemplate.Imports, TemplateImport{
+			From:         i.From,
+			Pipelines:    i.Pipelines,
+			Environments: i.Environments,
+		})
+	}
+	if wt.Blocks != "" {
+		exportedTemplate.Blocks = TemplateBlocksName
 	}
 
 	for i := range wt.Pipelines {
-		exportedTemplate.Pipelines[i] = fmt.Sprintf(TemplatePipelineName, i+1)
+		exportedTemplate.Pipelines[i] = TemplatePipelineFileName(wt.Pipelines[i], i)
 	}
 	for i := range wt.Applications {
-		exportedTemplate.Applications[i] = fmt.Sprintf(TemplateApplicationName, i+1)
+		exportedTemplate.Applications[i] = TemplateApplicationFileName(wt.Applications[i], i)
 	}
 	for i := range wt.Environments {
-		exportedTemplate.Environments[i] = fmt.Sprintf(TemplateEnvironmentName, i+1)
+		exportedTemplate.Environments[i] = TemplateEnvironmentFileName(wt.Environments[i], i)
 	}
 
 	return exportedTemplate, nil
@@ -85,6 +155,7 @@ func (w Template) GetTemplate(wkf []byte, pips, apps, envs [][]byte) sdk.Workflo
 			Name: w.Group,
 		},
 		Description:  w.Description,
+		Extends:      w.Extends,
 		Workflow:     base64.StdEncoding.EncodeToString(wkf),
 		Pipelines:    make([]sdk.PipelineTemplate, len(pips)),
 		Applications: make([]sdk.ApplicationTemplate, len(apps)),
@@ -96,6 +167,18 @@ func (w Template) GetTemplate(wkf []byte, pips, apps, envs [][]byte) sdk.Workflo
 			Key:      p.Key,
 			Type:     sdk.TemplateParameterType(p.Type),
 			Required: p.Required,
+			Choices:  p.Choices,
+			Pattern:  p.Pattern,
+			Min:      p.Min,
+			Max:      p.Max,
+		})
+	}
+
+	for _, i := range w.Imports {
+		wt.Imports = append(wt.Imports, sdk.WorkflowTemplateImport{
+			From:         i.From,
+			Pipelines:    i.Pipelines,
+			Environments: i.Environments,
 		})
 	}
 
@@ -137,6 +220,9 @@ func DownloadTemplate(manifestURL string, tBuf io.Writer) error {
 	paths = append(paths, t.Pipelines...)
 	paths = append(paths, t.Applications...)
 	paths = append(paths, t.Environments...)
+	if t.Blocks != "" {
+		paths = append(paths, t.Blocks)
+	}
 
 	links := make([]string, len(paths)+1)
 	links[0] = manifestURL
@@ -182,7 +268,8 @@ func ReadTemplateFromTar(tr *tar.Reader) (sdk.WorkflowTemplate, error) {
 
 	// extract template data from tar
 	var apps, pips, envs [][]byte
-	var wkf []byte
+	var pipNames, appNames, envNames []string
+	var wkf, blocks []byte
 	var tmpl Template
 
 	mError := new(sdk.MultiError)
@@ -205,10 +292,15 @@ func ReadTemplateFromTar(tr *tar.Reader) (sdk.WorkflowTemplate, error) {
 		switch {
 		case strings.Contains(hdr.Name, ".application."):
 			apps = append(apps, b)
+			appNames = append(appNames, templateFragmentName(hdr.Name, "application"))
 		case strings.Contains(hdr.Name, ".pipeline."):
 			pips = append(pips, b)
+			pipNames = append(pipNames, templateFragmentName(hdr.Name, "pipeline"))
 		case strings.Contains(hdr.Name, ".environment."):
 			envs = append(envs, b)
+			envNames = append(envNames, templateFragmentName(hdr.Name, "environment"))
+		case hdr.Name == TemplateBlocksName:
+			blocks = b
 		case hdr.Name == "workflow.yml":
 			// if a workflow was already found, it's a mistake
 			if len(wkf) != 0 {
@@ -236,29 +328,30 @@ func ReadTemplateFromTar(tr *tar.Reader) (sdk.WorkflowTemplate, error) {
 
 	// init workflow template struct from data
 	wt = tmpl.GetTemplate(wkf, pips, apps, envs)
+	for i := range pipNames {
+		wt.Pipelines[i].Name = pipNames[i]
+	}
+	for i := range appNames {
+		wt.Applications[i].Name = appNames[i]
+	}
+	for i := range envNames {
+		wt.Environments[i].Name = envNames[i]
+	}
+	if len(blocks) > 0 {
+		wt.Blocks = base64.StdEncoding.EncodeToString(blocks)
+	}
 
 	return wt, nil
 }
 
 type TemplateInstance struct {
 	Name       string            `json:"name,omitempty" yaml:"name,omitempty" jsonschema_description:"Name of the generated the workflow."`
-	From       string            `json:"from,omitempty" yaml:"from,omitempty" jsonschema_description:"Path of the template used to generate the workflow (ex: my-group/my-template:1)."`
+	From       string            `json:"from,omitempty" yaml:"from,omitempty" jsonschema_description:"Path of the template used to generate the workflow (ex: my-group/my-template@1 or my-group/my-template@^1.2)."`
 	Parameters map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty" jsonschema_description:"Optional template parameters."`
 }
 
-func (t TemplateInstance) ParseFrom() (string, string, int64, error) {
-	pathWithVersion := strings.Split(t.From, "@")
-	path := strings.Split(pathWithVersion[0], "/")
-	if len(path) < 2 {
-		return "", "", 0, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given workflow template path")
-	}
-	var version int64
-	if len(pathWithVersion) > 1 {
-		var err error
-		version, err = strconv.ParseInt(pathWithVersion[1], 10, 64)
-		if err != nil {
-			return "", "", 0, sdk.NewErrorWithStack(err, sdk.NewErrorFrom(sdk.ErrWrongRequest, "invalid given version %d", version))
-		}
-	}
-	return path[0], path[1], version, nil
+// ParseFrom returns the group name and the slug of the template, and the version or the release version range
+// of the template if given (ex: my-group/my-template@2 or my-group/my-template@^1.2).
+func (t TemplateInstance) ParseFrom() (string, string, int64, string, error) {
+	return sdk.ParseWorkflowTemplatePath(t.From)
 }
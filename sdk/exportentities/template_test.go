@@ -1,12 +1,16 @@
 package exportentities_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"encoding/base64"
 	"testing"
 
 	"github.com/ovh/cds/sdk/exportentities"
 
 	"github.com/ovh/cds/sdk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -53,3 +57,50 @@ func TestNewTemplateAndGetTemplate(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, sdkTemplateYaml, importedYaml)
 }
+
+func TestReadTemplateFromTarWithNamedFragments(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	files := []struct{ name, content string }{
+		{"my-template.yml", "name: My template\nslug: my-template\ngroup: my-group\nextends: shared.infra/base@^1.0\nimports:\n- from: shared.infra/fragments@1.2.0\n  pipelines: [deploy]\n"},
+		{"workflow.yml", "name: [[.name]]"},
+		{"1.pipeline.yml", "name: build"},
+		{"test.pipeline.yml", "name: test"},
+		{"api.application.yml", "name: [[.name]]-api"},
+		{"preprod.environment.yml", "name: preprod"},
+		{exportentities.TemplateBlocksName, `[[define "stages"]]- build[[end]]`},
+	}
+	for _, f := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0644, Size: int64(len(f.content))}))
+		_, err := tw.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	wt, err := exportentities.ReadTemplateFromTar(tar.NewReader(buf))
+	require.NoError(t, err)
+
+	assert.Equal(t, "shared.infra/base@^1.0", wt.Extends)
+	require.Len(t, wt.Imports, 1)
+	assert.Equal(t, "shared.infra/fragments@1.2.0", wt.Imports[0].From)
+	assert.Equal(t, []string{"deploy"}, wt.Imports[0].Pipelines)
+
+	require.Len(t, wt.Pipelines, 2)
+	assert.Equal(t, "", wt.Pipelines[0].Name)
+	assert.Equal(t, "test", wt.Pipelines[1].Name)
+	require.Len(t, wt.Applications, 1)
+	assert.Equal(t, "api", wt.Applications[0].Name)
+	require.Len(t, wt.Environments, 1)
+	assert.Equal(t, "preprod", wt.Environments[0].Name)
+
+	blocks, err := base64.StdEncoding.DecodeString(wt.Blocks)
+	require.NoError(t, err)
+	assert.Equal(t, `[[define "stages"]]- build[[end]]`, string(blocks))
+
+	exported, err := exportentities.NewTemplate(wt)
+	require.NoError(t, err)
+	assert.Equal(t, exportentities.TemplateBlocksName, exported.Blocks)
+	assert.Equal(t, "test.pipeline.yml", exportentities.TemplatePipelineFileName(wt.Pipelines[1], 1))
+	assert.Equal(t, "1.pipeline.yml", exportentities.TemplatePipelineFileName(wt.Pipelines[0], 0))
+	assert.Equal(t, []string{"api.application.yml"}, exported.Applications)
+}
@@ -68,7 +68,9 @@ func craftNotificationEntry(ctx context.Context, w sdk.Workflow, notif sdk.Workf
 			entry.Settings.Template.Body = ""
 		}
 		if entry.Settings.Template.Body == "" && entry.Settings.Template.Subject == "" {
-			if entry.Settings.Template.DisableComment == nil || !*entry.Settings.Template.DisableComment {
+			disableComment := entry.Settings.Template.DisableComment != nil && *entry.Settings.Template.DisableComment
+			disableReport := entry.Settings.Template.DisableReport != nil && *entry.Settings.Template.DisableReport
+			if !disableComment && !disableReport {
 				entry.Settings.Template = nil
 			}
 		}
@@ -187,6 +189,9 @@ func ProcessNotificationValues(notif NotificationEntry) (sdk.WorkflowNotificatio
 		if n.Settings.Template.DisableComment == nil || !*n.Settings.Template.DisableComment {
 			n.Settings.Template.DisableComment = nil
 		}
+		if n.Settings.Template.DisableReport == nil || !*n.Settings.Template.DisableReport {
+			n.Settings.Template.DisableReport = nil
+		}
 	}
 	return n, nil
 }
@@ -59,6 +59,8 @@ type NodeEntry struct {
 	EnvironmentName        string                 `json:"environment,omitempty" yaml:"environment,omitempty" jsonschema_description:"The environment to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
 	ProjectIntegrationName string                 `json:"integration,omitempty" yaml:"integration,omitempty" jsonschema_description:"The integration to use in the context of the node.\nhttps://ovh.github.io/cds/docs/concepts/workflow/pipeline-context"`
 	OneAtATime             *bool                  `json:"one_at_a_time,omitempty" yaml:"one_at_a_time,omitempty" jsonschema_description:"Set to true if you want to limit the execution of this node to one at a time."`
+	Approval               *ApprovalEntry         `json:"approval,omitempty" yaml:"approval,omitempty" jsonschema_description:"Approval required from reviewers before running this node."`
+	Preview                string                 `json:"preview,omitempty" yaml:"preview,omitempty" jsonschema_description:"Run this node in the preview environment of pull requests: deploy when a pull request is opened or updated, teardown when it is merged or declined.\nhttps://ovh.github.io/cds/docs/concepts/workflow/preview-environments"`
 	Payload                map[string]interface{} `json:"payload,omitempty" yaml:"payload,omitempty"`
 	Parameters             map[string]string      `json:"parameters,omitempty" yaml:"parameters,omitempty" jsonschema_description:"List of parameters for the workflow."`
 	OutgoingHookModelName  string                 `json:"trigger,omitempty" yaml:"trigger,omitempty"`
@@ -78,6 +80,14 @@ type PlainConditionEntry struct {
 	Value    string `json:"value" yaml:"value"`
 }
 
+// ApprovalEntry represents an approval gate as code
+type ApprovalEntry struct {
+	Groups             []string `json:"groups" yaml:"groups" jsonschema_description:"Groups of the reviewers."`
+	Required           int      `json:"required,omitempty" yaml:"required,omitempty" jsonschema_description:"Number of distinct reviewers that must approve, default is 1."`
+	ForbidSelfApproval bool     `json:"forbid_self_approval,omitempty" yaml:"forbid_self_approval,omitempty" jsonschema_description:"Set to true to forbid the approval by the commit author or the user that triggered the run."`
+	ExpireAfter        string   `json:"expire_after,omitempty" yaml:"expire_after,omitempty" jsonschema_description:"Duration after which the run is stopped if not approved (ex: 48h)."`
+}
+
 // HookEntry represents a hook as code
 type HookEntry struct {
 	Model      string                      `json:"type,omitempty" yaml:"type,omitempty" jsonschema_description:"Model of the hook.\nhttps://ovh.github.io/cds/docs/concepts/workflow/hooks"`
@@ -300,6 +310,19 @@ func craftNodeEntry(w sdk.Workflow, n sdk.Node) (NodeEntry, error) {
 			entry.OneAtATime = &n.Context.Mutex
 		}
 
+		if n.Context.Approval != nil {
+			entry.Approval = &ApprovalEntry{
+				Groups:             n.Context.Approval.Groups,
+				ForbidSelfApproval: n.Context.Approval.ForbidSelfApproval,
+				ExpireAfter:        n.Context.Approval.ExpireAfter,
+			}
+			if n.Context.Approval.RequiredApprovals > 1 {
+				entry.Approval.Required = n.Context.Approval.RequiredApprovals
+			}
+		}
+
+		entry.Preview = n.Context.Preview
+
 		if n.Context.HasDefaultPayload() {
 			enc := dump.NewDefaultEncoder()
 			enc.ExtraFields.DetailedMap = false
@@ -762,6 +785,25 @@ func (e *NodeEntry) getNode(name string) (*sdk.Node, error) {
 		node.Context.Mutex = *e.OneAtATime
 	}
 
+	if e.Approval != nil {
+		node.Context.Approval = &sdk.NodeApproval{
+			Groups:             e.Approval.Groups,
+			RequiredApprovals:  e.Approval.Required,
+			ForbidSelfApproval: e.Approval.ForbidSelfApproval,
+			ExpireAfter:        e.Approval.ExpireAfter,
+		}
+		if node.Context.Approval.RequiredApprovals == 0 {
+			node.Context.Approval.RequiredApprovals = 1
+		}
+	}
+
+	if e.Preview != "" {
+		if err := sdk.IsValidNodePreview(e.Preview); err != nil {
+			return nil, err
+		}
+		node.Context.Preview = e.Preview
+	}
+
 	if e.OutgoingHookModelName != "" {
 		node.Type = sdk.NodeTypeOutGoingHook
 		config := sdk.WorkflowNodeHookConfig{}
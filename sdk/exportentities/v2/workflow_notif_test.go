@@ -215,6 +215,26 @@ notifications:
         Branch : {{.git.branch}}
 - type: event
   integration: my-integration
+`,
+		},
+		{
+			name: "chat notifications with integration",
+			yaml: `name: test-notif-chat
+version: v2.0
+workflow:
+  test:
+    pipeline: test
+notifications:
+- type: slack
+  pipelines:
+  - test
+  integration: my-slack
+- type: teams
+  pipelines:
+  - test
+  settings:
+    on_success: always
+  integration: my-teams
 `,
 		},
 		{
@@ -37,6 +37,7 @@ func craftNotificationEntry(ctx context.Context, w sdk.Workflow, notif sdk.Workf
 	}
 	sort.Strings(entry.Pipelines)
 	entry.Type = notif.Type
+	entry.Integration = notif.Integration
 	entry.Settings = &notif.Settings
 
 	// Replace the default values by nil
@@ -69,7 +70,9 @@ func craftNotificationEntry(ctx context.Context, w sdk.Workflow, notif sdk.Workf
 			entry.Settings.Template.Body = ""
 		}
 		if entry.Settings.Template.Body == "" && entry.Settings.Template.Subject == "" {
-			if entry.Settings.Template.DisableComment == nil || !*entry.Settings.Template.DisableComment {
+			disableComment := entry.Settings.Template.DisableComment != nil && *entry.Settings.Template.DisableComment
+			disableReport := entry.Settings.Template.DisableReport != nil && *entry.Settings.Template.DisableReport
+			if !disableComment && !disableReport {
 				entry.Settings.Template = nil
 			}
 		}
@@ -182,6 +185,9 @@ func ProcessNotificationValues(notif NotificationEntry) (sdk.WorkflowNotificatio
 		if n.Settings.Template.DisableComment == nil || !*n.Settings.Template.DisableComment {
 			n.Settings.Template.DisableComment = nil
 		}
+		if n.Settings.Template.DisableReport == nil || !*n.Settings.Template.DisableReport {
+			n.Settings.Template.DisableReport = nil
+		}
 	}
 	return n, nil
 }
@@ -201,6 +207,12 @@ func (w *Workflow) processNotifications(wrkflw *sdk.Workflow) error {
 		if err != nil {
 			return sdk.WrapError(err, "unable to process notification")
 		}
+		if sdk.IsChatUserNotification(notif.Type) {
+			if notif.Integration == "" {
+				return sdk.NewErrorFrom(sdk.ErrWrongRequest, "notification of type %s must be linked to an integration", notif.Type)
+			}
+			n.Integration = notif.Integration
+		}
 		n.SourceNodeRefs = notif.Pipelines
 		wrkflw.Notifications = append(wrkflw.Notifications, n)
 	}
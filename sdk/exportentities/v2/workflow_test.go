@@ -234,6 +234,124 @@ func TestWorkflow_GetWorkflow(t *testing.T) {
 				RetentionPolicy: "return false",
 			},
 		},
+		// approval
+		{
+			name: "Workflow with an approval on a node",
+			fields: fields{
+				Name:    "myWorkflow",
+				Version: exportentities.WorkflowVersion2,
+				Workflow: map[string]v2.NodeEntry{
+					"root": {
+						PipelineName: "pipeline-root",
+					},
+					"deploy": {
+						PipelineName: "pipeline-deploy",
+						DependsOn:    []string{"root"},
+						Approval: &v2.ApprovalEntry{
+							Groups:             []string{"ops"},
+							Required:           2,
+							ForbidSelfApproval: true,
+							ExpireAfter:        "48h",
+						},
+					},
+				},
+			},
+			wantErr: false,
+			want: sdk.Workflow{
+				Name: "myWorkflow",
+				WorkflowData: sdk.WorkflowData{
+					Node: sdk.Node{
+						Name: "root",
+						Ref:  "root",
+						Type: "pipeline",
+						Context: &sdk.NodeContext{
+							PipelineName: "pipeline-root",
+						},
+						Triggers: []sdk.NodeTrigger{
+							{
+								ChildNode: sdk.Node{
+									Name: "deploy",
+									Ref:  "deploy",
+									Type: "pipeline",
+									Context: &sdk.NodeContext{
+										PipelineName: "pipeline-deploy",
+										Approval: &sdk.NodeApproval{
+											Groups:             []string{"ops"},
+											RequiredApprovals:  2,
+											ForbidSelfApproval: true,
+											ExpireAfter:        "48h",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		// preview
+		{
+			name: "Workflow with a preview deploy node",
+			fields: fields{
+				Name:    "myWorkflow",
+				Version: exportentities.WorkflowVersion2,
+				Workflow: map[string]v2.NodeEntry{
+					"root": {
+						PipelineName: "pipeline-root",
+					},
+					"deploy": {
+						PipelineName:    "pipeline-deploy",
+						ApplicationName: "app",
+						EnvironmentName: "preview",
+						DependsOn:       []string{"root"},
+						Preview:         sdk.NodePreviewDeploy,
+					},
+				},
+			},
+			wantErr: false,
+			want: sdk.Workflow{
+				Name: "myWorkflow",
+				WorkflowData: sdk.WorkflowData{
+					Node: sdk.Node{
+						Name: "root",
+						Ref:  "root",
+						Type: "pipeline",
+						Context: &sdk.NodeContext{
+							PipelineName: "pipeline-root",
+						},
+						Triggers: []sdk.NodeTrigger{
+							{
+								ChildNode: sdk.Node{
+									Name: "deploy",
+									Ref:  "deploy",
+									Type: "pipeline",
+									Context: &sdk.NodeContext{
+										PipelineName:    "pipeline-deploy",
+										ApplicationName: "app",
+										EnvironmentName: "preview",
+										Preview:         sdk.NodePreviewDeploy,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Workflow with an invalid preview should raise an error",
+			fields: fields{
+				Name:    "myWorkflow",
+				Version: exportentities.WorkflowVersion2,
+				Workflow: map[string]v2.NodeEntry{
+					"root": {
+						PipelineName: "pipeline-root",
+						Preview:      "destroy",
+					},
+				},
+			},
+			wantErr: true,
+		},
 		// root(pipeline-root) -> child(pipeline-child)
 		{
 			name: "Complexe workflow without joins and mutex should not raise an error",
@@ -62,6 +62,15 @@ func (g Groups) ToIDs() []int64 {
 	return ids
 }
 
+// ToNames returns names for groups.
+func (g Groups) ToNames() []string {
+	names := make([]string, len(g))
+	for i := range g {
+		names[i] = g[i].Name
+	}
+	return names
+}
+
 // ToMap returns a map of groups by ids.
 func (g Groups) ToMap() map[int64]Group {
 	mGroups := make(map[int64]Group, len(g))
@@ -341,7 +341,7 @@ func canRunJob(ctx context.Context, h Interface, j workerStarterRequest) bool {
 		}
 
 		// Skip others requirement as we can't check it
-		if r.Type == sdk.PluginRequirement || r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
+		if r.Type == sdk.PluginRequirement || r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement || r.Type == sdk.CPURequirement {
 			log.Debug(ctx, "canRunJob> %d - job %d - job with service, plugin or memory requirement. Skip these check as we can't check it on hatchery routine", j.timestamp, j.id)
 			continue
 		}
@@ -422,6 +422,12 @@ func canRunJobWithModel(ctx context.Context, h InterfaceWithModels, j workerStar
 			return false
 		}
 
+		// cpu requirement is only supported by sandboxed local workers
+		if r.Type == sdk.CPURequirement {
+			log.Debug(ctx, "canRunJobWithModel> %d - job %d - job with cpu requirement: not supported with worker models", j.timestamp, j.id)
+			return false
+		}
+
 		// Skip other requirement as we can't check it
 		if r.Type == sdk.PluginRequirement || r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
 			log.Debug(ctx, "canRunJobWithModel> %d - job %d - job with service, plugin, network or memory requirement. Skip these check as we can't check it on hatchery routine", j.timestamp, j.id)
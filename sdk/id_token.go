@@ -0,0 +1,68 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// IDTokenRequest is sent by a worker to get an OpenID Connect identity token for its job.
+type IDTokenRequest struct {
+	Audience string `json:"audience"`
+}
+
+// IsValid returns an error if the request has no audience.
+func (r IDTokenRequest) IsValid() error {
+	if r.Audience == "" {
+		return NewErrorFrom(ErrWrongRequest, "invalid given audience")
+	}
+	return nil
+}
+
+// IDToken is a short-lived identity token signed by CDS for a job.
+type IDToken struct {
+	Token    string    `json:"token"`
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+// IDTokenClaims contains the identity of the job that requested the token.
+type IDTokenClaims struct {
+	ProjectKey       string `json:"project_key"`
+	WorkflowName     string `json:"workflow_name"`
+	WorkflowNodeName string `json:"workflow_node_name"`
+	EnvironmentName  string `json:"environment_name,omitempty"`
+	GitRepository    string `json:"git_repository,omitempty"`
+	GitRef           string `json:"git_ref,omitempty"`
+	GitHash          string `json:"git_hash,omitempty"`
+	RunNumber        int64  `json:"run_number"`
+	JobID            int64  `json:"job_id"`
+	jwt.StandardClaims
+}
+
+// IDTokenSubject returns the subject of an identity token, ex: project:MYPROJ:workflow:my-workflow:node:build.
+func IDTokenSubject(projectKey, workflowName, nodeName string) string {
+	return fmt.Sprintf("project:%s:workflow:%s:node:%s", projectKey, workflowName, nodeName)
+}
+
+// IDTokenGitRef returns the git reference for a branch or a tag, the tag has priority.
+func IDTokenGitRef(branch, tag string) string {
+	switch {
+	case tag != "":
+		return "refs/tags/" + tag
+	case branch != "":
+		return "refs/heads/" + branch
+	}
+	return ""
+}
+
+// OpenIDConfiguration is the discovery document of CDS identity tokens issuer.
+type OpenIDConfiguration struct {
+	Issuer                           string   `json:"issuer"`
+	JWKSURI                          string   `json:"jwks_uri"`
+	ResponseTypesSupported           []string `json:"response_types_supported"`
+	SubjectTypesSupported            []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                  []string `json:"scopes_supported"`
+	ClaimsSupported                  []string `json:"claims_supported"`
+}
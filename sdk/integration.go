@@ -14,6 +14,8 @@ const (
 	AWSIntegrationModel           = "AWS"
 	DefaultStorageIntegrationName = "shared.infra"
 	ArtifactManagerModel          = "ArtifactManager"
+	VaultIntegrationModel         = "Vault"
+	ChatWebhookIntegrationModel   = "ChatWebhook"
 
 	ArtifactManagerConfigPlatform              = "platform"
 	ArtifactManagerConfigURL                   = "url"
@@ -25,6 +27,12 @@ const (
 	ArtifactManagerConfigPromotionLowMaturity  = "promotion.maturity.low"
 	ArtifactManagerConfigPromotionHighMaturity = "promotion.maturity.high"
 	ArtifactManagerConfigBuildInfoPath         = "build.info.path"
+
+	VaultConfigURL       = "url"
+	VaultConfigToken     = "token"
+	VaultConfigNamespace = "namespace"
+
+	ChatWebhookConfigURL = "webhook.url"
 )
 
 // Here are the default plateform models
@@ -35,6 +43,8 @@ var (
 		&OpenstackIntegration,
 		&AWSIntegration,
 		&ArtifactManagerIntegration,
+		&VaultIntegration,
+		&ChatWebhookIntegration,
 	}
 	// KafkaIntegration represents a kafka integration
 	KafkaIntegration = IntegrationModel{
@@ -160,6 +170,43 @@ var (
 		},
 		ArtifactManager: true,
 	}
+	// VaultIntegration represents a secret integration, password variables can reference Vault secrets
+	VaultIntegration = IntegrationModel{
+		Name:       VaultIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/vault",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			VaultConfigURL: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "Vault server address, ex: https://vault.local:8200",
+			},
+			VaultConfigToken: IntegrationConfigValue{
+				Type:        IntegrationConfigTypePassword,
+				Description: "Vault token with read policy on referenced paths",
+			},
+			VaultConfigNamespace: IntegrationConfigValue{
+				Type:        IntegrationConfigTypeString,
+				Description: "Vault namespace (optional)",
+			},
+		},
+		Secret:   true,
+		Disabled: false,
+	}
+	// ChatWebhookIntegration represents an incoming webhook of Slack, Mattermost or Microsoft Teams used by chat notifications
+	ChatWebhookIntegration = IntegrationModel{
+		Name:       ChatWebhookIntegrationModel,
+		Author:     "CDS",
+		Identifier: "github.com/ovh/cds/integration/builtin/chat-webhook",
+		Icon:       "",
+		DefaultConfig: IntegrationConfig{
+			ChatWebhookConfigURL: IntegrationConfigValue{
+				Type:        IntegrationConfigTypePassword,
+				Description: "Incoming webhook URL, ex: https://hooks.slack.com/services/...",
+			},
+		},
+		Disabled: false,
+	}
 	// AWSIntegration represents an aws integration
 	AWSIntegration = IntegrationModel{
 		Name:       AWSIntegrationModel,
@@ -207,6 +254,7 @@ const (
 	IntegrationTypeHook       = IntegrationType("hook")
 	IntegrationTypeStorage    = IntegrationType("storage")
 	IntegrationTypeDeployment = IntegrationType("deployment")
+	IntegrationTypeSecret     = IntegrationType("secret")
 )
 
 // DefaultIfEmptyStorage return sdk.DefaultStorageIntegrationName if integrationName is empty
@@ -384,6 +432,7 @@ type IntegrationModel struct {
 	Compute                 bool                 `json:"compute" db:"compute" yaml:"compute" cli:"compute_supported"`
 	Event                   bool                 `json:"event" db:"event" yaml:"event" cli:"event_supported"`
 	ArtifactManager         bool                 `json:"artifact_manager" db:"artifact_manager" yaml:"artifact_manager" cli:"artifact_manager_supported"`
+	Secret                  bool                 `json:"secret" db:"secret" yaml:"secret" cli:"secret_supported"`
 	Public                  bool                 `json:"public,omitempty" db:"public" yaml:"public,omitempty"`
 }
 
@@ -447,7 +496,8 @@ func (config *IntegrationConfig) HideSecrets() {
 }
 
 type FileInfo struct {
-	Size int64
-	Md5  string
-	Type string
+	Size   int64
+	Md5    string
+	Sha256 string
+	Type   string
 }
@@ -64,6 +64,8 @@ func init() {
 		"b64enc":       base64encode,
 		"b64dec":       base64decode,
 		"escape":       escape,
+		// hashFiles is evaluated by the worker on job files, the expression is kept as is
+		"hashFiles": func(patterns ...interface{}) string { return "{{hashFiles " + quote(patterns...) + "}}" },
 		"add": func(i ...interface{}) int64 {
 			var a int64 = 0
 			for _, b := range i {
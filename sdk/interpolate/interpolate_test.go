@@ -446,6 +446,15 @@ func TestDo(t *testing.T) {
 			want:   "my value 4 4",
 			enable: true,
 		},
+		{
+			name: "hashFiles is kept for the worker",
+			args: args{
+				input: `{{.cds.application}}-{{hashFiles "go.sum" "**/package-lock.json"}}`,
+				vars:  map[string]string{"cds.application": "app"},
+			},
+			want:   `app-{{hashFiles "go.sum" "**/package-lock.json"}}`,
+			enable: true,
+		},
 	}
 	for _, tt := range tests {
 		if !tt.enable {
@@ -3,6 +3,7 @@ package sdk
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 type KeyType string
@@ -17,6 +18,19 @@ const (
 	KeyTypePGP KeyType = "pgp"
 )
 
+// SSHKeyAlgorithm is the algorithm used to generate a SSH key
+type SSHKeyAlgorithm string
+
+// Those are algorithms available to generate SSH keys
+const (
+	SSHKeyAlgorithmRSA     SSHKeyAlgorithm = "rsa"
+	SSHKeyAlgorithmED25519 SSHKeyAlgorithm = "ed25519"
+	SSHKeyAlgorithmECDSA   SSHKeyAlgorithm = "ecdsa"
+)
+
+// DefaultKeyRotationOverlap is the default duration while a rotated key and its successor are both valid.
+const DefaultKeyRotationOverlap = 7 * 24 * time.Hour
+
 func GenerateProjectDefaultKeyName(projectKey string, t KeyType) string {
 	return fmt.Sprintf("proj-%s-%s", t, strings.ToLower(projectKey))
 }
@@ -41,6 +55,10 @@ type ProjectKey struct {
 	Type      KeyType `json:"type" db:"type" cli:"type"`
 	ProjectID int64   `json:"project_id" db:"project_id" cli:"-"`
 	Builtin   bool    `json:"-" db:"builtin" cli:"-"`
+	// Algorithm is only used when generating a new ssh key
+	Algorithm SSHKeyAlgorithm `json:"algorithm,omitempty" db:"-" cli:"-"`
+	Successor string          `json:"successor,omitempty" db:"successor" cli:"successor"`
+	RetireAt  *time.Time      `json:"retire_at,omitempty" db:"retire_at" cli:"retire_at"`
 }
 
 // ApplicationKey represent a key attach to an application
@@ -52,6 +70,20 @@ type ApplicationKey struct {
 	KeyID         string  `json:"key_id" db:"key_id" cli:"-"`
 	Type          KeyType `json:"type" db:"type" cli:"type"`
 	ApplicationID int64   `json:"application_id" db:"application_id"`
+	// Algorithm is only used when generating a new ssh key
+	Algorithm SSHKeyAlgorithm `json:"algorithm,omitempty" db:"-" cli:"-"`
+	Successor string          `json:"successor,omitempty" db:"successor" cli:"successor"`
+	RetireAt  *time.Time      `json:"retire_at,omitempty" db:"retire_at" cli:"retire_at"`
+}
+
+// IsRetired returns true if the key was rotated and its overlap period is over.
+func (k ProjectKey) IsRetired(t time.Time) bool {
+	return k.RetireAt != nil && !t.Before(*k.RetireAt)
+}
+
+// IsRetired returns true if the key was rotated and its overlap period is over.
+func (k ApplicationKey) IsRetired(t time.Time) bool {
+	return k.RetireAt != nil && !t.Before(*k.RetireAt)
 }
 
 // EnvironmentKey represent a key attach to an environment
@@ -64,3 +96,28 @@ type EnvironmentKey struct {
 	Type          KeyType `json:"type" db:"type" cli:"type"`
 	EnvironmentID int64   `json:"environment_id" db:"environment_id"`
 }
+
+// KeyRotationRequest is used to rotate a project or an application key.
+// A successor key is generated, or imported if a private key is given, and the rotated key
+// remains valid until it is retired.
+type KeyRotationRequest struct {
+	SuccessorName string          `json:"successor_name,omitempty"`
+	Algorithm     SSHKeyAlgorithm `json:"algorithm,omitempty"`
+	Private       string          `json:"private,omitempty"`
+	OverlapHours  int64           `json:"overlap_hours,omitempty"`
+}
+
+// KeyUsage reports the entities that still reference a key.
+type KeyUsage struct {
+	Key          string         `json:"key"`
+	Successor    string         `json:"successor,omitempty"`
+	RetireAt     *time.Time     `json:"retire_at,omitempty"`
+	Applications []string       `json:"applications"`
+	Pipelines    []string       `json:"pipelines"`
+	Workflows    []WorkflowName `json:"workflows"`
+}
+
+// IsUsed returns true if the key is still referenced.
+func (u KeyUsage) IsUsed() bool {
+	return len(u.Applications) > 0 || len(u.Pipelines) > 0 || len(u.Workflows) > 0
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/yuin/gluare"
 	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
 	luajson "layeh.com/gopher-json"
 )
 
@@ -110,6 +111,12 @@ func (c *Check) EnableStrict() error {
 	return nil
 }
 
+// CheckSyntax parses the given script without running it
+func CheckSyntax(script string) error {
+	_, err := parse.Parse(strings.NewReader(script), "<condition>")
+	return err
+}
+
 //Perform the lua script
 func (c *Check) Perform(script string) error {
 	var ok bool
@@ -175,3 +175,8 @@ func Test_luaPerformStrictCheckOnVariable(t *testing.T) {
 	require.NoError(t, luaCheck.Perform("return defined_number < 100"))
 	require.False(t, luaCheck.Result)
 }
+
+func TestLuaCheckSyntax(t *testing.T) {
+	require.NoError(t, CheckSyntax("return cds_application == \"mon-appli\""))
+	require.Error(t, CheckSyntax("return cds_application == "))
+}
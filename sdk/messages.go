@@ -94,6 +94,14 @@ var (
 	MsgWorkflowGeneratedFromTemplateVersion = &Message{"MsgWorkflowGeneratedFromTemplateVersion", trad{FR: "Le workflow a été généré à partir du modèle de workflow: %s.", EN: "The workflow was generated from the template: %s"}, nil, RunInfoTypInfo}
 	MsgTooMuchWorkflowRun                   = &Message{"MsgTooMuchWorkflowRun", trad{FR: "L'exécution de ce workflow est suspendu. Vous dépassez le nombre maximum d'éxécution autorisé (%.f). Merci de revoir la politique de retention de ce workflow", EN: "Workflow run is delayed. The maximum number of runs for this workflow has been reached ( %.f ). Please update your workflow retention policy"}, nil, RunInfoTypeWarning}
 	MsgSpawnErrorHatcheryRetryAttempt       = &Message{"MsgSpawnErrorHatcheryRetryAttempt", trad{EN: "Job execution failed by hatchery %s. Reason: %s"}, nil, RunInfoTypeError}
+	MsgSpawnInfoSecretReferenceError        = &Message{"MsgSpawnInfoSecretReferenceError", trad{FR: "⚠ Impossible de résoudre la référence de secret de la variable %s: %s", EN: "⚠ Unable to resolve secret reference of variable %s: %s"}, nil, RunInfoTypeError}
+	MsgWorkflowNodeApprovalWaiting          = &Message{"MsgWorkflowNodeApprovalWaiting", trad{FR: "Le pipeline %s est en attente de %d approbation(s)", EN: "The pipeline %s is waiting for %d approval(s)"}, nil, RunInfoTypInfo}
+	MsgWorkflowNodeApprovalApproved         = &Message{"MsgWorkflowNodeApprovalApproved", trad{FR: "Le pipeline %s a été approuvé par %s", EN: "The pipeline %s has been approved by %s"}, nil, RunInfoTypInfo}
+	MsgWorkflowNodeApprovalRejected         = &Message{"MsgWorkflowNodeApprovalRejected", trad{FR: "Le pipeline %s a été rejeté par %s", EN: "The pipeline %s has been rejected by %s"}, nil, RunInfoTypeWarning}
+	MsgWorkflowNodeApprovalExpired          = &Message{"MsgWorkflowNodeApprovalExpired", trad{FR: "La demande d'approbation du pipeline %s a expiré", EN: "The approval request of pipeline %s has expired"}, nil, RunInfoTypeWarning}
+	MsgWorkflowNodeEnvironmentProtected     = &Message{"MsgWorkflowNodeEnvironmentProtected", trad{FR: "Le pipeline %s ne peut pas être lancé sur l'environnement protégé %s: %s", EN: "The pipeline %s can't be started on protected environment %s: %s"}, nil, RunInfoTypeWarning}
+	MsgWorkflowPreviewEnvironmentDeploy     = &Message{"MsgWorkflowPreviewEnvironmentDeploy", trad{FR: "Le pipeline %s va déployer l'environnement de prévisualisation %s de la pull request #%d", EN: "The pipeline %s will deploy the preview environment %s of pull request #%d"}, nil, RunInfoTypInfo}
+	MsgWorkflowPreviewEnvironmentTeardown   = &Message{"MsgWorkflowPreviewEnvironmentTeardown", trad{FR: "Le pipeline %s va supprimer l'environnement de prévisualisation %s de la pull request #%d", EN: "The pipeline %s will tear down the preview environment %s of pull request #%d"}, nil, RunInfoTypInfo}
 )
 
 // Messages contains all sdk Messages
@@ -172,6 +180,14 @@ var Messages = map[string]*Message{
 	MsgWorkflowGeneratedFromTemplateVersion.ID: MsgWorkflowGeneratedFromTemplateVersion,
 	MsgTooMuchWorkflowRun.ID:                   MsgTooMuchWorkflowRun,
 	MsgSpawnErrorHatcheryRetryAttempt.ID:       MsgSpawnErrorHatcheryRetryAttempt,
+	MsgSpawnInfoSecretReferenceError.ID:        MsgSpawnInfoSecretReferenceError,
+	MsgWorkflowNodeApprovalWaiting.ID:          MsgWorkflowNodeApprovalWaiting,
+	MsgWorkflowNodeApprovalApproved.ID:         MsgWorkflowNodeApprovalApproved,
+	MsgWorkflowNodeApprovalRejected.ID:         MsgWorkflowNodeApprovalRejected,
+	MsgWorkflowNodeApprovalExpired.ID:          MsgWorkflowNodeApprovalExpired,
+	MsgWorkflowNodeEnvironmentProtected.ID:     MsgWorkflowNodeEnvironmentProtected,
+	MsgWorkflowPreviewEnvironmentDeploy.ID:     MsgWorkflowPreviewEnvironmentDeploy,
+	MsgWorkflowPreviewEnvironmentTeardown.ID:   MsgWorkflowPreviewEnvironmentTeardown,
 }
 
 //Message represent a struc format translated messages
@@ -19,8 +19,22 @@ const (
 	JabberUserNotification = "jabber"
 	VCSUserNotification    = "vcs"
 	EventsNotification     = "event"
+
+	// Chat notifications are posted on the incoming webhook of a project integration
+	SlackUserNotification      = "slack"
+	MattermostUserNotification = "mattermost"
+	TeamsUserNotification      = "teams"
 )
 
+// IsChatUserNotification returns true if the notification type is posted on a chat webhook.
+func IsChatUserNotification(t string) bool {
+	switch t {
+	case SlackUserNotification, MattermostUserNotification, TeamsUserNotification:
+		return true
+	}
+	return false
+}
+
 //const
 const (
 	UserNotificationAlways = "always"
@@ -75,6 +89,7 @@ type UserNotificationTemplate struct {
 	// For VCS
 	DisableComment *bool `json:"disable_comment,omitempty" yaml:"disable_comment,omitempty"`
 	DisableStatus  *bool `json:"disable_status,omitempty" yaml:"disable_status,omitempty"`
+	DisableReport  *bool `json:"disable_report,omitempty" yaml:"disable_report,omitempty"`
 }
 
 //userNotificationInput is a way to parse notification
@@ -103,9 +118,17 @@ Branch : {{.git.branch | default "n/a"}}`,
 		Body:    `{{.cds.buildURL}}`,
 	}
 
+	UserNotificationTemplateChat = UserNotificationTemplate{
+		Subject: "{{.cds.project}}/{{.cds.workflow}}#{{.cds.version}} {{.cds.node}} {{.cds.status}}",
+		Body:    `{{.git.message | default ""}}`,
+	}
+
 	UserNotificationTemplateMap = map[string]UserNotificationTemplate{
-		EmailUserNotification:  UserNotificationTemplateEmail,
-		JabberUserNotification: UserNotificationTemplateJabber,
+		EmailUserNotification:      UserNotificationTemplateEmail,
+		JabberUserNotification:     UserNotificationTemplateJabber,
+		SlackUserNotification:      UserNotificationTemplateChat,
+		MattermostUserNotification: UserNotificationTemplateChat,
+		TeamsUserNotification:      UserNotificationTemplateChat,
 		VCSUserNotification: {
 			Body: DefaultWorkflowNodeRunReport,
 		},
@@ -0,0 +1,158 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Preview roles of a workflow node. A deploy node runs in the preview environment of a pull request when the pull
+// request is opened or updated, a teardown node runs in it when the pull request is merged or declined.
+const (
+	NodePreviewDeploy   = "deploy"
+	NodePreviewTeardown = "teardown"
+)
+
+// Preview environment status
+const (
+	PreviewEnvironmentStatusDeploying      = "Deploying"
+	PreviewEnvironmentStatusDeployed       = "Deployed"
+	PreviewEnvironmentStatusDeployFailed   = "DeployFailed"
+	PreviewEnvironmentStatusTearingDown    = "TearingDown"
+	PreviewEnvironmentStatusTeardownFailed = "TeardownFailed"
+)
+
+// Build parameters added to the runs that deploy or tear down a preview environment.
+const (
+	PreviewParamAction      = "cds.preview.action"
+	PreviewParamPullRequest = "cds.preview.pr"
+	PreviewParamBranch      = "cds.preview.branch"
+	PreviewParamBranchSlug  = "cds.preview.branch.slug"
+)
+
+// PreviewEnvironment is an ephemeral environment created for a pull request from the environment of a workflow deploy
+// node, it is deleted when the pull request is merged or declined.
+type PreviewEnvironment struct {
+	ID                int64     `json:"id" db:"id" cli:"-"`
+	ApplicationID     int64     `json:"application_id" db:"application_id" cli:"-"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id" cli:"-"`
+	WorkflowName      string    `json:"workflow_name" db:"-" cli:"workflow"`
+	EnvironmentID     int64     `json:"environment_id" db:"environment_id" cli:"-"`
+	EnvironmentName   string    `json:"environment_name" db:"-" cli:"environment"`
+	PullRequestID     int64     `json:"pull_request_id" db:"pull_request_id" cli:"pull_request,key"`
+	Branch            string    `json:"branch" db:"branch" cli:"branch"`
+	Hash              string    `json:"hash" db:"hash" cli:"-"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	WorkflowRunNumber int64     `json:"workflow_run_number" db:"workflow_run_number" cli:"run"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+	LastModified      time.Time `json:"last_modified" db:"last_modified" cli:"last_modified"`
+}
+
+// PreviewEvent is a pull request event that deploys or tears down a preview environment.
+type PreviewEvent struct {
+	Action        string
+	PullRequestID int64
+	Branch        string
+	Hash          string
+	// HeadRepository is the repository of the pull request head
+	HeadRepository string
+}
+
+// Pull request events by repository manager, values are the ones given by the hooks service in git.hook
+// and git.pr.action payload variables.
+var (
+	previewDeployEvents = []string{
+		"pr:opened", "pr:from_ref_updated", // Bitbucket Server
+		"pullrequest:created", "pullrequest:updated", // Bitbucket Cloud
+		"pull_request/opened", "pull_request/reopened", "pull_request/synchronize", // GitHub
+		"Merge Request Hook/open", "Merge Request Hook/reopen", "Merge Request Hook/update", // GitLab
+	}
+	previewTeardownEvents = []string{
+		"pr:merged", "pr:declined", "pr:deleted",
+		"pullrequest:fulfilled", "pullrequest:rejected",
+		"pull_request/closed",
+		"Merge Request Hook/merge", "Merge Request Hook/close",
+	}
+)
+
+// PreviewEventFromPayload returns the preview event for a repository webhook payload, nil is returned if the payload
+// is not a pull request opened, updated, merged or declined event.
+func PreviewEventFromPayload(payload map[string]string) *PreviewEvent {
+	id, err := strconv.ParseInt(payload["git.pr.id"], 10, 64)
+	if err != nil || id <= 0 {
+		return nil
+	}
+
+	event := payload["git.hook"]
+	if action := payload["git.pr.action"]; action != "" {
+		event += "/" + action
+	}
+
+	// Pull requests from forks don't get a preview environment, their code must not run with the secrets of the template.
+	// Bitbucket Server gives the head repository in git.repository and the target one in git.repository.dest.
+	headRepository := payload["git.pr.head.repository"]
+	if headRepository == "" {
+		headRepository = payload["git.repository"]
+	}
+	targetRepository := payload["git.repository"]
+	if dest := payload["git.repository.dest"]; dest != "" {
+		targetRepository = dest
+	}
+	if headRepository != targetRepository {
+		return nil
+	}
+
+	e := PreviewEvent{
+		PullRequestID:  id,
+		Branch:         payload["git.branch"],
+		Hash:           payload["git.hash"],
+		HeadRepository: headRepository,
+	}
+	// Some repository managers give the head of the pull request apart from the git variables of the run
+	if b := payload["git.pr.head.branch"]; b != "" {
+		e.Branch = b
+		e.Hash = payload["git.pr.head.hash"]
+	}
+	switch {
+	case IsInArray(event, previewDeployEvents):
+		e.Action = NodePreviewDeploy
+	case IsInArray(event, previewTeardownEvents):
+		e.Action = NodePreviewTeardown
+		// Merge events give the destination branch, the pull request one is the previous branch
+		if b := payload["git.branch.before"]; b != "" {
+			e.Branch = b
+		}
+	default:
+		return nil
+	}
+	return &e
+}
+
+var previewSlugRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Params returns the build parameters for the run of the preview event.
+func (e PreviewEvent) Params() map[string]string {
+	return map[string]string{
+		PreviewParamAction:      e.Action,
+		PreviewParamPullRequest: strconv.FormatInt(e.PullRequestID, 10),
+		PreviewParamBranch:      e.Branch,
+		PreviewParamBranchSlug:  strings.Trim(previewSlugRegexp.ReplaceAllString(strings.ToLower(e.Branch), "-"), "-"),
+	}
+}
+
+// PreviewEnvironmentName returns the name of the preview environment of a pull request. The workflow name is part of
+// it as several workflows of a project can deploy previews from the same template.
+func PreviewEnvironmentName(templateName, workflowName string, pullRequestID int64) string {
+	return fmt.Sprintf("%s-%s-pr-%d", templateName, workflowName, pullRequestID)
+}
+
+// IsValidNodePreview returns an error if given node preview role is unknown.
+func IsValidNodePreview(preview string) error {
+	switch preview {
+	case "", NodePreviewDeploy, NodePreviewTeardown:
+		return nil
+	}
+	return NewErrorFrom(ErrWorkflowInvalid, "invalid preview %q, expected %s or %s", preview, NodePreviewDeploy, NodePreviewTeardown)
+}
@@ -0,0 +1,93 @@
+package sdk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestPreviewEventFromPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload map[string]string
+		want    *sdk.PreviewEvent
+	}{
+		{
+			name:    "push event",
+			payload: map[string]string{"git.hook": "push", "git.branch": "master"},
+		},
+		{
+			name:    "bitbucket server pull request opened",
+			payload: map[string]string{"git.hook": "pr:opened", "git.pr.id": "12", "git.branch": "feat/login", "git.hash": "abc"},
+			want:    &sdk.PreviewEvent{Action: sdk.NodePreviewDeploy, PullRequestID: 12, Branch: "feat/login", Hash: "abc"},
+		},
+		{
+			name: "bitbucket server pull request updated",
+			payload: map[string]string{"git.hook": "pr:from_ref_updated", "git.pr.id": "12", "git.branch": "feat/login", "git.hash": "abc",
+				"git.repository": "PROJ/repo", "git.repository.dest": "PROJ/repo", "git.pr.head.repository": "PROJ/repo"},
+			want: &sdk.PreviewEvent{Action: sdk.NodePreviewDeploy, PullRequestID: 12, Branch: "feat/login", Hash: "abc", HeadRepository: "PROJ/repo"},
+		},
+		{
+			name: "bitbucket server pull request from a fork",
+			payload: map[string]string{"git.hook": "pr:opened", "git.pr.id": "13", "git.branch": "feat/login", "git.hash": "abc",
+				"git.repository": "~SOMEONE/repo", "git.repository.dest": "PROJ/repo", "git.pr.head.repository": "~SOMEONE/repo"},
+		},
+		{
+			name:    "bitbucket server pull request merged",
+			payload: map[string]string{"git.hook": "pr:merged", "git.pr.id": "12", "git.branch": "master", "git.branch.before": "feat/login"},
+			want:    &sdk.PreviewEvent{Action: sdk.NodePreviewTeardown, PullRequestID: 12, Branch: "feat/login"},
+		},
+		{
+			name:    "bitbucket server pull request reviewer updated",
+			payload: map[string]string{"git.hook": "pr:reviewer:updated", "git.pr.id": "12"},
+		},
+		{
+			name: "github pull request synchronized",
+			payload: map[string]string{"git.hook": "pull_request", "git.pr.action": "synchronize", "git.pr.id": "3",
+				"git.repository": "org/repo", "git.pr.head.repository": "org/repo", "git.pr.head.branch": "fix", "git.pr.head.hash": "def"},
+			want: &sdk.PreviewEvent{Action: sdk.NodePreviewDeploy, PullRequestID: 3, Branch: "fix", Hash: "def", HeadRepository: "org/repo"},
+		},
+		{
+			name: "github pull request closed",
+			payload: map[string]string{"git.hook": "pull_request", "git.pr.action": "closed", "git.pr.id": "3",
+				"git.repository": "org/repo", "git.pr.head.repository": "org/repo", "git.pr.head.branch": "fix", "git.pr.head.hash": "def"},
+			want: &sdk.PreviewEvent{Action: sdk.NodePreviewTeardown, PullRequestID: 3, Branch: "fix", Hash: "def", HeadRepository: "org/repo"},
+		},
+		{
+			name: "github pull request from a fork",
+			payload: map[string]string{"git.hook": "pull_request", "git.pr.action": "opened", "git.pr.id": "4",
+				"git.repository": "org/repo", "git.pr.head.repository": "someone/repo", "git.pr.head.branch": "fix"},
+		},
+		{
+			name:    "github pull request labeled",
+			payload: map[string]string{"git.hook": "pull_request", "git.pr.action": "labeled", "git.pr.id": "3"},
+		},
+		{
+			name:    "gitlab merge request declined",
+			payload: map[string]string{"git.hook": "Merge Request Hook", "git.pr.action": "close", "git.pr.id": "7", "git.branch": "dev"},
+			want:    &sdk.PreviewEvent{Action: sdk.NodePreviewTeardown, PullRequestID: 7, Branch: "dev"},
+		},
+		{
+			name:    "invalid pull request id",
+			payload: map[string]string{"git.hook": "pr:opened", "git.pr.id": "foo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, sdk.PreviewEventFromPayload(tt.payload))
+		})
+	}
+}
+
+func TestPreviewEventParams(t *testing.T) {
+	e := sdk.PreviewEvent{Action: sdk.NodePreviewDeploy, PullRequestID: 42, Branch: "Feat/New_Login"}
+	require.Equal(t, map[string]string{
+		"cds.preview.action":      "deploy",
+		"cds.preview.pr":          "42",
+		"cds.preview.branch":      "Feat/New_Login",
+		"cds.preview.branch.slug": "feat-new-login",
+	}, e.Params())
+	require.Equal(t, "staging-my-workflow-pr-42", sdk.PreviewEnvironmentName("staging", "my-workflow", 42))
+}
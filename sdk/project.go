@@ -144,6 +144,16 @@ func (proj Project) IsValid() error {
 	return nil
 }
 
+// GetKey returns a key given his name
+func (proj Project) GetKey(name string) *ProjectKey {
+	for i := range proj.Keys {
+		if proj.Keys[i].Name == name {
+			return &proj.Keys[i]
+		}
+	}
+	return nil
+}
+
 // GetSSHKey returns a ssh key given his name
 func (proj Project) GetSSHKey(name string) *ProjectKey {
 	for _, k := range proj.Keys {
@@ -0,0 +1,230 @@
+package sdk
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Provenance attestations follow the in-toto attestation framework with a SLSA provenance predicate,
+// signed in a DSSE envelope (https://github.com/secure-systems-lab/dsse).
+const (
+	InTotoStatementType       = "https://in-toto.io/Statement/v0.1"
+	InTotoPayloadType         = "application/vnd.in-toto+json"
+	SLSAProvenancePredicateV2 = "https://slsa.dev/provenance/v0.2"
+	ProvenanceBuildType       = "https://github.com/ovh/cds/workflow-run@v1"
+	ProvenanceFileSuffix      = ".intoto.jsonl"
+)
+
+// GenerateProjectProvenanceKeyName returns the name of the key used to sign provenance attestations of a project.
+func GenerateProjectProvenanceKeyName(projectKey string) string {
+	return fmt.Sprintf("proj-provenance-%s", strings.ToLower(projectKey))
+}
+
+// ProjectProvenanceKey is the key used to sign the provenance attestations of a project. It is stored apart from the
+// project keys so its private part is never given to the jobs.
+type ProjectProvenanceKey struct {
+	ID        int64     `json:"id" db:"id" cli:"-"`
+	ProjectID int64     `json:"project_id" db:"project_id" cli:"-"`
+	Name      string    `json:"name" db:"name" cli:"name"`
+	Public    string    `json:"public" db:"public" cli:"publickey"`
+	Private   string    `json:"-" db:"private" cli:"-" gorpmapping:"encrypted,ID,Name"`
+	Created   time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// InTotoStatement binds a predicate to the artifacts it is about.
+type InTotoStatement struct {
+	Type          string          `json:"_type"`
+	Subject       []InTotoSubject `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     SLSAProvenance  `json:"predicate"`
+}
+
+// InTotoSubject is an artifact identified by its name and digests.
+type InTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// SLSAProvenance describes how an artifact was produced.
+type SLSAProvenance struct {
+	Builder    SLSABuilder    `json:"builder"`
+	BuildType  string         `json:"buildType"`
+	Invocation SLSAInvocation `json:"invocation"`
+	Metadata   SLSAMetadata   `json:"metadata"`
+	Materials  []SLSAMaterial `json:"materials,omitempty"`
+}
+
+type SLSABuilder struct {
+	ID string `json:"id"`
+}
+
+type SLSAInvocation struct {
+	ConfigSource SLSAConfigSource       `json:"configSource"`
+	Parameters   map[string]string      `json:"parameters,omitempty"`
+	Environment  SLSAProvenanceBuildEnv `json:"environment"`
+}
+
+type SLSAConfigSource struct {
+	URI        string            `json:"uri,omitempty"`
+	Digest     map[string]string `json:"digest,omitempty"`
+	EntryPoint string            `json:"entryPoint"`
+}
+
+// SLSAProvenanceBuildEnv contains the CDS specific build context.
+type SLSAProvenanceBuildEnv struct {
+	ProjectKey             string `json:"project_key"`
+	WorkflowName           string `json:"workflow_name"`
+	WorkflowDefinitionHash string `json:"workflow_definition_sha256"`
+	PipelineName           string `json:"pipeline_name"`
+	PipelineDefinitionHash string `json:"pipeline_definition_sha256"`
+	RunNumber              int64  `json:"run_number"`
+	RunSubNumber           int64  `json:"run_subnumber"`
+	NodeRunID              int64  `json:"node_run_id"`
+	JobName                string `json:"job_name,omitempty"`
+	JobRunID               int64  `json:"job_run_id,omitempty"`
+	WorkerModel            string `json:"worker_model,omitempty"`
+	WorkerModelType        string `json:"worker_model_type,omitempty"`
+}
+
+type SLSAMetadata struct {
+	BuildInvocationID string     `json:"buildInvocationId"`
+	BuildStartedOn    *time.Time `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn   *time.Time `json:"buildFinishedOn,omitempty"`
+}
+
+type SLSAMaterial struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// DSSEEnvelope is a signed payload.
+type DSSEEnvelope struct {
+	PayloadType string          `json:"payloadType"`
+	Payload     string          `json:"payload"`
+	Signatures  []DSSESignature `json:"signatures"`
+}
+
+type DSSESignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// SHA256Hex returns the hex encoded SHA-256 digest of given data.
+func SHA256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// dssePAE returns the pre-authentication encoding of a DSSE payload, this is the signed message.
+func dssePAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// SignInTotoStatement returns a DSSE envelope of the statement signed with given key.
+// Ed25519, ECDSA and RSA keys are supported.
+func SignInTotoStatement(statement InTotoStatement, keyID string, signer crypto.Signer) (*DSSEEnvelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, WithStack(err)
+	}
+	msg := dssePAE(InTotoPayloadType, payload)
+
+	var sig []byte
+	switch signer.Public().(type) {
+	case ed25519.PublicKey:
+		sig, err = signer.Sign(rand.Reader, msg, crypto.Hash(0))
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		digest := sha256.Sum256(msg)
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return nil, NewErrorFrom(ErrWrongRequest, "unsupported key type %T to sign a provenance", signer.Public())
+	}
+	if err != nil {
+		return nil, WrapError(err, "unable to sign provenance")
+	}
+
+	return &DSSEEnvelope{
+		PayloadType: InTotoPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []DSSESignature{{KeyID: keyID, Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// Verify checks that the envelope was signed by given public key and returns the signed statement.
+func (e DSSEEnvelope) Verify(keyID string, pub crypto.PublicKey) (*InTotoStatement, error) {
+	if e.PayloadType != InTotoPayloadType {
+		return nil, NewErrorFrom(ErrInvalidData, "unsupported payload type %q", e.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return nil, NewErrorFrom(ErrInvalidData, "invalid payload encoding")
+	}
+	msg := dssePAE(e.PayloadType, payload)
+	digest := sha256.Sum256(msg)
+
+	var verified bool
+	for _, s := range e.Signatures {
+		if keyID != "" && s.KeyID != keyID {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err != nil {
+			continue
+		}
+		switch k := pub.(type) {
+		case ed25519.PublicKey:
+			verified = ed25519.Verify(k, msg, sig)
+		case *ecdsa.PublicKey:
+			verified = ecdsa.VerifyASN1(k, digest[:], sig)
+		case *rsa.PublicKey:
+			verified = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+		default:
+			return nil, NewErrorFrom(ErrWrongRequest, "unsupported key type %T to verify a provenance", pub)
+		}
+		if verified {
+			break
+		}
+	}
+	if !verified {
+		return nil, NewErrorFrom(ErrInvalidData, "invalid provenance signature")
+	}
+
+	var statement InTotoStatement
+	if err := JSONUnmarshal(payload, &statement); err != nil {
+		return nil, NewErrorFrom(ErrInvalidData, "invalid provenance statement: %v", err)
+	}
+	if statement.Type != InTotoStatementType || statement.PredicateType != SLSAProvenancePredicateV2 {
+		return nil, NewErrorFrom(ErrInvalidData, "unsupported statement %s with predicate %s", statement.Type, statement.PredicateType)
+	}
+	return &statement, nil
+}
+
+// VerifySubject checks that the content read from given reader is a subject of the statement.
+func (s InTotoStatement) VerifySubject(name string, r io.Reader) error {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return WithStack(err)
+	}
+	digest := hex.EncodeToString(h.Sum(nil))
+	for _, subject := range s.Subject {
+		if subject.Name != name {
+			continue
+		}
+		if subject.Digest["sha256"] == digest {
+			return nil
+		}
+		return NewErrorFrom(ErrInvalidData, "sha256 digest %s of %s doesn't match provenance digest %s", digest, name, subject.Digest["sha256"])
+	}
+	return NewErrorFrom(ErrInvalidData, "%s is not a subject of the provenance", name)
+}
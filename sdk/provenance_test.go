@@ -0,0 +1,60 @@
+package sdk_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestSignInTotoStatement(t *testing.T) {
+	content := "my binary"
+	statement := sdk.InTotoStatement{
+		Type:          sdk.InTotoStatementType,
+		PredicateType: sdk.SLSAProvenancePredicateV2,
+		Subject: []sdk.InTotoSubject{
+			{Name: "my-binary", Digest: map[string]string{"sha256": sdk.SHA256Hex([]byte(content))}},
+		},
+		Predicate: sdk.SLSAProvenance{
+			Builder:   sdk.SLSABuilder{ID: "https://cds.local"},
+			BuildType: sdk.ProvenanceBuildType,
+		},
+	}
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	for _, key := range []crypto.Signer{edKey, ecKey} {
+		envelope, err := sdk.SignInTotoStatement(statement, "proj-provenance-foo", key)
+		require.NoError(t, err)
+
+		s, err := envelope.Verify("proj-provenance-foo", key.Public())
+		require.NoError(t, err)
+		require.Equal(t, "https://cds.local", s.Predicate.Builder.ID)
+
+		require.NoError(t, s.VerifySubject("my-binary", strings.NewReader(content)))
+		require.Error(t, s.VerifySubject("my-binary", strings.NewReader("another binary")))
+		require.Error(t, s.VerifySubject("another-binary", strings.NewReader(content)))
+
+		_, err = envelope.Verify("another-key", key.Public())
+		require.Error(t, err, "key id should match")
+
+		// Changing the payload should invalidate the signature
+		tampered := *envelope
+		payload, _ := base64.StdEncoding.DecodeString(envelope.Payload)
+		payload = []byte(strings.Replace(string(payload), sdk.SHA256Hex([]byte(content)), sdk.SHA256Hex([]byte("another binary")), 1))
+		tampered.Payload = base64.StdEncoding.EncodeToString(payload)
+		_, err = tampered.Verify("proj-provenance-foo", key.Public())
+		require.Error(t, err)
+	}
+}
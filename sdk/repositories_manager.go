@@ -122,6 +122,8 @@ func (s VCSPullRequestState) IsValid() bool {
 type VCSPullRequestCommentRequest struct {
 	VCSPullRequest
 	Message string `json:"message"`
+	// Key identifies a comment, if set the comment that starts with the key is updated instead of posting a new one
+	Key string `json:"key,omitempty"`
 }
 
 //VCSPushEvent represents a push events for polling
@@ -1,7 +1,9 @@
 package sdk
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"time"
 )
 
@@ -79,6 +81,10 @@ type OperationRepositoryInfo struct {
 type OperationLoadFiles struct {
 	Pattern string            `json:"pattern,omitempty"`
 	Results map[string][]byte `json:"results,omitempty"`
+	// Root and ChangedSince are set to load files only if a file under the workflow root changed since given commit
+	Root         string `json:"root,omitempty"`
+	ChangedSince string `json:"changed_since,omitempty"`
+	Unchanged    bool   `json:"unchanged,omitempty"`
 }
 
 // OperationCheckout represents a smart git checkout
@@ -119,3 +125,24 @@ type OperationRepo struct {
 func (r OperationRepo) ID() string {
 	return base64.StdEncoding.EncodeToString([]byte(r.URL))
 }
+
+// RepositoryMirror is a request to the repositories service to create or refresh the bare mirror of a repository.
+type RepositoryMirror struct {
+	ProjectKey         string             `json:"project_key"`
+	URL                string             `json:"url"`
+	RepositoryStrategy RepositoryStrategy `json:"strategy,omitempty"`
+}
+
+// GitMirror is the mirror of a repository that a worker can clone from, through the API.
+type GitMirror struct {
+	ID            string `json:"id"`
+	URL           string `json:"url,omitempty"`
+	Authorization string `json:"-"`
+}
+
+// RepositoryMirrorID returns a generated ID for the mirror of a repository in a project, it can be used in urls and paths.
+// Mirrors are not shared between projects because each project gives its own credentials to fetch the repository.
+func RepositoryMirrorID(projectKey, url string) string {
+	h := sha256.Sum256([]byte(projectKey + "/" + url))
+	return hex.EncodeToString(h[:])
+}
@@ -13,6 +13,8 @@ const (
 	ServiceRequirement = "service"
 	//MemoryRequirement set memory limit on a container
 	MemoryRequirement = "memory"
+	// CPURequirement set the maximum number of CPUs usable by a sandboxed worker
+	CPURequirement = "cpu"
 	// OSArchRequirement checks the 'dist' of a worker eg {GOOS}/{GOARCH}
 	OSArchRequirement = "os-architecture"
 	// RegionRequirement lets a use to force a job running in a hatchery's region
@@ -81,6 +83,7 @@ var (
 	// AvailableRequirementsType List of all requirements
 	AvailableRequirementsType = []string{
 		BinaryRequirement,
+		CPURequirement,
 		HostnameRequirement,
 		MemoryRequirement,
 		ModelRequirement,
@@ -0,0 +1,215 @@
+package sdk
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SCIM 2.0 schemas, see https://tools.ietf.org/html/rfc7643 and https://tools.ietf.org/html/rfc7644.
+const (
+	SCIMSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SCIMSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMSchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+)
+
+// SCIMMeta contains resource metadata.
+type SCIMMeta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+// SCIMName is the name of a SCIM user.
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// SCIMEmail is an email of a SCIM user.
+type SCIMEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// SCIMMember references a user in a group or a group of a user.
+type SCIMMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// SCIMUser is a SCIM user resource mapped to an authentified user.
+type SCIMUser struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *SCIMName    `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []SCIMEmail  `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+	Groups      []SCIMMember `json:"groups,omitempty"`
+	Meta        *SCIMMeta    `json:"meta,omitempty"`
+}
+
+// IsActive returns the active attribute of the user, an absent attribute means that the user is active.
+func (u SCIMUser) IsActive() bool {
+	return u.Active == nil || *u.Active
+}
+
+// Fullname returns the name to use as user fullname.
+func (u SCIMUser) Fullname() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	if u.Name != nil {
+		if u.Name.Formatted != "" {
+			return u.Name.Formatted
+		}
+		if n := strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName); n != "" {
+			return n
+		}
+	}
+	return u.UserName
+}
+
+// PrimaryEmail returns the primary email of the user or its first email.
+func (u SCIMUser) PrimaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+// IsValid returns an error if given user is not valid.
+func (u SCIMUser) IsValid() error {
+	if u.UserName == "" {
+		return NewErrorFrom(ErrWrongRequest, "missing userName")
+	}
+	if u.PrimaryEmail() == "" {
+		return NewErrorFrom(ErrWrongRequest, "missing email")
+	}
+	return nil
+}
+
+// SCIMGroup is a SCIM group resource mapped to a group.
+type SCIMGroup struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id"`
+	DisplayName string       `json:"displayName"`
+	Members     []SCIMMember `json:"members"`
+	Meta        *SCIMMeta    `json:"meta,omitempty"`
+}
+
+// SCIMListResponse is returned when searching resources.
+type SCIMListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// SCIMPatchRequest contains operations to apply on a resource.
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+// SCIMPatchOperation is an operation of a patch request, the value depends on the path.
+type SCIMPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Available SCIM patch operations, operation names are not case sensitive.
+const (
+	SCIMPatchOpAdd     = "add"
+	SCIMPatchOpRemove  = "remove"
+	SCIMPatchOpReplace = "replace"
+)
+
+// SCIMServiceProviderConfig describes supported SCIM features.
+type SCIMServiceProviderConfig struct {
+	Schemas               []string             `json:"schemas"`
+	Patch                 SCIMSupported        `json:"patch"`
+	Bulk                  SCIMSupported        `json:"bulk"`
+	Filter                SCIMFilterSupported  `json:"filter"`
+	ChangePassword        SCIMSupported        `json:"changePassword"`
+	Sort                  SCIMSupported        `json:"sort"`
+	ETag                  SCIMSupported        `json:"etag"`
+	AuthenticationSchemes []SCIMAuthentication `json:"authenticationSchemes"`
+}
+
+// SCIMSupported is a feature of the service provider config.
+type SCIMSupported struct {
+	Supported bool `json:"supported"`
+}
+
+// SCIMFilterSupported is the filter feature of the service provider config.
+type SCIMFilterSupported struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+// SCIMAuthentication is an authentication scheme of the service provider config.
+type SCIMAuthentication struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// SCIMFilter is an equality filter on a resource attribute, only the eq operator is supported.
+type SCIMFilter struct {
+	Attribute string
+	Value     string
+}
+
+var scimFilterRegexp = regexp.MustCompile(`^\s*([A-Za-z.]+)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// ParseSCIMFilter returns the filter for given value, ex: userName eq "john".
+func ParseSCIMFilter(value string) (*SCIMFilter, error) {
+	if value == "" {
+		return nil, nil
+	}
+	m := scimFilterRegexp.FindStringSubmatch(value)
+	if m == nil {
+		return nil, NewErrorFrom(ErrWrongRequest, "unsupported filter %q, only 'attribute eq \"value\"' is supported", value)
+	}
+	v, err := strconv.Unquote(`"` + m[2] + `"`)
+	if err != nil {
+		return nil, NewErrorFrom(ErrWrongRequest, "invalid filter value %q", m[2])
+	}
+	return &SCIMFilter{Attribute: m[1], Value: v}, nil
+}
+
+// SCIMBool returns the boolean for a patch value, some identity providers send booleans as strings.
+func SCIMBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return false, NewErrorFrom(ErrWrongRequest, "invalid boolean value %s", string(value))
+	}
+	b, err := strconv.ParseBool(strings.ToLower(s))
+	if err != nil {
+		return false, NewErrorFrom(ErrWrongRequest, "invalid boolean value %s", string(value))
+	}
+	return b, nil
+}
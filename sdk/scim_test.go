@@ -0,0 +1,49 @@
+package sdk_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseSCIMFilter(t *testing.T) {
+	f, err := sdk.ParseSCIMFilter("")
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	f, err = sdk.ParseSCIMFilter(`userName eq "john.doe@example.com"`)
+	require.NoError(t, err)
+	assert.Equal(t, &sdk.SCIMFilter{Attribute: "userName", Value: "john.doe@example.com"}, f)
+
+	f, err = sdk.ParseSCIMFilter(`displayName EQ "team \"a\""`)
+	require.NoError(t, err)
+	assert.Equal(t, &sdk.SCIMFilter{Attribute: "displayName", Value: `team "a"`}, f)
+
+	_, err = sdk.ParseSCIMFilter(`userName sw "john"`)
+	require.Error(t, err)
+	_, err = sdk.ParseSCIMFilter(`userName eq "john" and active eq "true"`)
+	require.Error(t, err)
+}
+
+func TestSCIMBool(t *testing.T) {
+	for value, expected := range map[string]bool{`true`: true, `false`: false, `"True"`: true, `"false"`: false} {
+		b, err := sdk.SCIMBool(json.RawMessage(value))
+		require.NoError(t, err)
+		assert.Equal(t, expected, b, value)
+	}
+	_, err := sdk.SCIMBool(json.RawMessage(`"yes please"`))
+	require.Error(t, err)
+}
+
+func TestSCIMUserIsActive(t *testing.T) {
+	var u sdk.SCIMUser
+	require.NoError(t, json.Unmarshal([]byte(`{"userName": "john.doe"}`), &u))
+	assert.True(t, u.IsActive(), "an absent active attribute means that the user is active")
+
+	require.NoError(t, json.Unmarshal([]byte(`{"userName": "john.doe", "active": false}`), &u))
+	assert.False(t, u.IsActive())
+}
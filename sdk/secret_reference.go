@@ -0,0 +1,45 @@
+package sdk
+
+import (
+	"strings"
+)
+
+// SecretReferencePrefix is the prefix of password variable values that reference a secret stored in Vault,
+// ex: vault://kv/data/team/db#password.
+const SecretReferencePrefix = "vault://"
+
+// SecretReference is the path and the key of a secret referenced by a variable.
+type SecretReference struct {
+	Path string `json:"path"`
+	Key  string `json:"key"`
+}
+
+// String returns the reference as written in variable value.
+func (r SecretReference) String() string {
+	return SecretReferencePrefix + r.Path + "#" + r.Key
+}
+
+// IsSecretReference returns true if given value should be resolved by a secret integration.
+func IsSecretReference(value string) bool {
+	return strings.HasPrefix(value, SecretReferencePrefix)
+}
+
+// ParseSecretReference returns the secret reference for given value.
+func ParseSecretReference(value string) (SecretReference, error) {
+	if !IsSecretReference(value) {
+		return SecretReference{}, NewErrorFrom(ErrWrongRequest, "secret reference should start with %s", SecretReferencePrefix)
+	}
+	ref := strings.TrimPrefix(value, SecretReferencePrefix)
+	i := strings.LastIndex(ref, "#")
+	if i < 0 {
+		return SecretReference{}, NewErrorFrom(ErrWrongRequest, "missing key in secret reference %q, expected format is %s<path>#<key>", value, SecretReferencePrefix)
+	}
+	r := SecretReference{
+		Path: strings.Trim(ref[:i], "/"),
+		Key:  ref[i+1:],
+	}
+	if r.Path == "" || r.Key == "" {
+		return SecretReference{}, NewErrorFrom(ErrWrongRequest, "invalid secret reference %q, expected format is %s<path>#<key>", value, SecretReferencePrefix)
+	}
+	return r, nil
+}
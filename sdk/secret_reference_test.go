@@ -0,0 +1,20 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSecretReference(t *testing.T) {
+	ref, err := ParseSecretReference("vault://kv/data/team/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, SecretReference{Path: "kv/data/team/db", Key: "password"}, ref)
+	assert.Equal(t, "vault://kv/data/team/db#password", ref.String())
+
+	for _, v := range []string{"kv/data/team/db#password", "vault://kv/data/team/db", "vault://#password", "vault://kv/data/team/db#"} {
+		_, err := ParseSecretReference(v)
+		assert.Error(t, err, v)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"database/sql/driver"
 	json "encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"sort"
+	"strings"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
@@ -31,6 +33,19 @@ type AuthDriverWithSigninStateToken interface {
 	CheckSigninStateToken(AuthConsumerSigninRequest) error
 }
 
+// AuthDriverWithUserGroups is implemented by drivers that can look up the groups of a user without its credentials.
+type AuthDriverWithUserGroups interface {
+	AuthDriver
+	GetUserGroups(ctx context.Context, username string) ([]string, error)
+}
+
+// AuthDriverWithRefreshedUserGroups is implemented by drivers that can look up the groups of a user with the refresh
+// token given at signin. A new refresh token can be returned, it replaces the given one.
+type AuthDriverWithRefreshedUserGroups interface {
+	AuthDriver
+	RefreshUserGroups(ctx context.Context, refreshToken string) (groups []string, newRefreshToken string, err error)
+}
+
 type AuthDriverSigningRedirect struct {
 	Method      string            `json:"method"`
 	URL         string            `json:"url"`
@@ -121,6 +136,7 @@ const (
 	AuthConsumerScopeWorkerModel  AuthConsumerScope = "WorkerModel"
 	AuthConsumerScopeHatchery     AuthConsumerScope = "Hatchery"
 	AuthConsumerScopeService      AuthConsumerScope = "Service"
+	AuthConsumerScopeSCIM         AuthConsumerScope = "SCIM"
 )
 
 // AuthConsumerScopes list.
@@ -139,6 +155,7 @@ var AuthConsumerScopes = []AuthConsumerScope{
 	AuthConsumerScopeWorkerModel,
 	AuthConsumerScopeHatchery,
 	AuthConsumerScopeService,
+	AuthConsumerScopeSCIM,
 }
 
 func NewAuthConsumerScopeDetails(scopes ...AuthConsumerScope) AuthConsumerScopeDetails {
@@ -261,6 +278,8 @@ type AuthDriverUserInfo struct {
 	Email           string
 	MFA             bool
 	ExternalTokenID string
+	Groups          []string
+	RefreshToken    string
 }
 
 // AuthCurrentConsumerResponse describe the current consumer and the current session
@@ -331,11 +350,22 @@ const (
 	WarningGroupInvalid     AuthConsumerWarningType = "group-invalid"
 	WarningGroupRemoved     AuthConsumerWarningType = "group-removed"
 	WarningLastGroupRemoved AuthConsumerWarningType = "last-group-removed"
+	WarningUserDeactivated  AuthConsumerWarningType = "user-deactivated"
 )
 
 // AuthConsumerWarnings contains specific information from the auth driver.
 type AuthConsumerWarnings []AuthConsumerWarning
 
+// Contains returns true if a warning exists for given type.
+func (w AuthConsumerWarnings) Contains(t AuthConsumerWarningType) bool {
+	for i := range w {
+		if w[i].Type == t {
+			return true
+		}
+	}
+	return false
+}
+
 // NewConsumerWarningGroupInvalid returns a new warning for given group info.
 func NewConsumerWarningGroupInvalid(groupID int64, groupName string) AuthConsumerWarning {
 	return AuthConsumerWarning{
@@ -359,6 +389,11 @@ func NewConsumerWarningLastGroupRemoved() AuthConsumerWarning {
 	return AuthConsumerWarning{Type: WarningLastGroupRemoved}
 }
 
+// NewConsumerWarningUserDeactivated returns a new warning.
+func NewConsumerWarningUserDeactivated() AuthConsumerWarning {
+	return AuthConsumerWarning{Type: WarningUserDeactivated}
+}
+
 // AuthConsumerWarning contains info about a warning.
 type AuthConsumerWarning struct {
 	Type      AuthConsumerWarningType `json:"type"`
@@ -381,9 +416,182 @@ func (w AuthConsumerWarnings) Value() (driver.Value, error) {
 	return j, WrapError(err, "cannot marshal AuthConsumerWarnings")
 }
 
+// AuthConsumerResourceRestriction limits a consumer to a project, or to a workflow of a project, with a max permission level.
+type AuthConsumerResourceRestriction struct {
+	ProjectKey   string `json:"project_key" cli:"project_key"`
+	WorkflowName string `json:"workflow_name,omitempty" cli:"workflow_name"`
+	Permission   int    `json:"permission" cli:"permission"`
+}
+
+// AuthConsumerResourceRestrictions type used for database json storage, empty list means that all resources are allowed.
+type AuthConsumerResourceRestrictions []AuthConsumerResourceRestriction
+
+// IsValid returns an error if given restrictions are not valid.
+func (r AuthConsumerResourceRestrictions) IsValid() error {
+	for _, rr := range r {
+		if rr.ProjectKey == "" {
+			return NewErrorFrom(ErrWrongRequest, "invalid given project key for resource restriction")
+		}
+		if !IsValidPermissionValue(rr.Permission) {
+			return NewErrorFrom(ErrWrongRequest, "invalid given permission %d for resource restriction on %s", rr.Permission, rr.ProjectKey)
+		}
+	}
+	return nil
+}
+
+// Level returns the max permission level allowed for given project or workflow, workflowName should be empty for
+// a project. A restriction on a workflow only gives a read access to its project.
+func (r AuthConsumerResourceRestrictions) Level(projectKey, workflowName string) int {
+	if len(r) == 0 {
+		return PermissionReadWriteExecute
+	}
+	var level int
+	for _, rr := range r {
+		if rr.ProjectKey != projectKey {
+			continue
+		}
+		l := rr.Permission
+		if rr.WorkflowName != "" && rr.WorkflowName != workflowName {
+			if workflowName != "" {
+				continue
+			}
+			if l > PermissionRead {
+				l = PermissionRead
+			}
+		}
+		if l > level {
+			level = l
+		}
+	}
+	return level
+}
+
+// Contains returns true if given restriction doesn't allow more than current restrictions.
+func (r AuthConsumerResourceRestrictions) Contains(rr AuthConsumerResourceRestriction) bool {
+	if len(r) == 0 {
+		return true
+	}
+	for _, p := range r {
+		if p.ProjectKey == rr.ProjectKey && (p.WorkflowName == "" || p.WorkflowName == rr.WorkflowName) && p.Permission >= rr.Permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan resource restrictions.
+func (r *AuthConsumerResourceRestrictions) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(JSONUnmarshal(source, r), "cannot unmarshal AuthConsumerResourceRestrictions")
+}
+
+// Value returns driver.Value from resource restrictions.
+func (r AuthConsumerResourceRestrictions) Value() (driver.Value, error) {
+	j, err := json.Marshal(r)
+	return j, WrapError(err, "cannot marshal AuthConsumerResourceRestrictions")
+}
+
+// AuthConsumerAllowedCIDRs type used for database json storage, empty list means that all source networks are allowed.
+type AuthConsumerAllowedCIDRs []string
+
+// IsValid returns an error if a given value is not a valid CIDR.
+func (c AuthConsumerAllowedCIDRs) IsValid() error {
+	for _, s := range c {
+		if _, _, err := net.ParseCIDR(s); err != nil {
+			return NewErrorFrom(ErrWrongRequest, "invalid given CIDR %q", s)
+		}
+	}
+	return nil
+}
+
+// ContainsIP returns true if given ip address is in one of the allowed networks.
+func (c AuthConsumerAllowedCIDRs) ContainsIP(ip net.IP) bool {
+	if len(c) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, s := range c {
+		if _, n, err := net.ParseCIDR(s); err == nil && n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsCIDR returns true if given network is included in one of the allowed networks.
+func (c AuthConsumerAllowedCIDRs) ContainsCIDR(cidr string) bool {
+	if len(c) == 0 {
+		return true
+	}
+	ip, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	ones, _ := n.Mask.Size()
+	for _, s := range c {
+		_, p, err := net.ParseCIDR(s)
+		if err != nil {
+			continue
+		}
+		parentOnes, _ := p.Mask.Size()
+		if p.Contains(ip) && parentOnes <= ones {
+			return true
+		}
+	}
+	return false
+}
+
+// Scan allowed CIDRs.
+func (c *AuthConsumerAllowedCIDRs) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(errors.New("type assertion .([]byte) failed"))
+	}
+	return WrapError(JSONUnmarshal(source, c), "cannot unmarshal AuthConsumerAllowedCIDRs")
+}
+
+// Value returns driver.Value from allowed CIDRs.
+func (c AuthConsumerAllowedCIDRs) Value() (driver.Value, error) {
+	j, err := json.Marshal(c)
+	return j, WrapError(err, "cannot marshal AuthConsumerAllowedCIDRs")
+}
+
+// ParseRemoteIP returns the client ip address from a remote address or a X-Forwarded-For header value.
+func ParseRemoteIP(addr string) net.IP {
+	// The first address of a forwarded for list is the client address
+	if i := strings.Index(addr, ","); i >= 0 {
+		addr = addr[:i]
+	}
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	return net.ParseIP(addr)
+}
+
 // AuthConsumers gives functions for auth consumer slice.
 type AuthConsumers []AuthConsumer
 
+// IDs returns consumer ids.
+func (c AuthConsumers) IDs() []string {
+	ids := make([]string, len(c))
+	for i := range c {
+		ids[i] = c[i].ID
+	}
+	return ids
+}
+
 // AuthConsumer issues session linked to an authentified user.
 type AuthConsumer struct {
 	ID                 string                      `json:"id" cli:"id,key" db:"id"`
@@ -402,6 +610,9 @@ type AuthConsumer struct {
 	Warnings           AuthConsumerWarnings        `json:"warnings,omitempty" db:"warnings"`
 	LastAuthentication *time.Time                  `json:"last_authentication,omitempty" db:"last_authentication"`
 	ValidityPeriods    AuthConsumerValidityPeriods `json:"validity_periods,omitempty" db:"validity_periods"`
+	// restrictions for builtin consumers
+	ResourceRestrictions AuthConsumerResourceRestrictions `json:"resource_restrictions,omitempty" cli:"resource_restrictions" db:"resource_restrictions"`
+	AllowedCIDRs         AuthConsumerAllowedCIDRs         `json:"allowed_cidrs,omitempty" cli:"allowed_cidrs" db:"allowed_cidrs"`
 	// aggregates
 	AuthentifiedUser *AuthentifiedUser `json:"user,omitempty" db:"-"`
 	Groups           Groups            `json:"groups,omitempty" db:"-"`
@@ -465,6 +676,12 @@ func (c AuthConsumer) IsValid(scopeDetails AuthConsumerScopeDetails) error {
 	if err := c.ScopeDetails.IsValid(); err != nil {
 		return err
 	}
+	if err := c.ResourceRestrictions.IsValid(); err != nil {
+		return err
+	}
+	if err := c.AllowedCIDRs.IsValid(); err != nil {
+		return err
+	}
 
 	mEndpoints := scopeDetails.ToEndpointsMap()
 
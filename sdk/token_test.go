@@ -109,3 +109,42 @@ func TestAuthConsumerScopeDetailsIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthConsumerResourceRestrictionsLevel(t *testing.T) {
+	var none sdk.AuthConsumerResourceRestrictions
+	assert.Equal(t, sdk.PermissionReadWriteExecute, none.Level("PROJ", ""))
+
+	rs := sdk.AuthConsumerResourceRestrictions{
+		{ProjectKey: "PROJ1", Permission: sdk.PermissionReadExecute},
+		{ProjectKey: "PROJ2", WorkflowName: "build", Permission: sdk.PermissionReadWriteExecute},
+	}
+	assert.Equal(t, sdk.PermissionReadExecute, rs.Level("PROJ1", ""))
+	assert.Equal(t, sdk.PermissionReadExecute, rs.Level("PROJ1", "deploy"))
+	assert.Equal(t, sdk.PermissionRead, rs.Level("PROJ2", ""))
+	assert.Equal(t, sdk.PermissionReadWriteExecute, rs.Level("PROJ2", "build"))
+	assert.Equal(t, 0, rs.Level("PROJ2", "deploy"))
+	assert.Equal(t, 0, rs.Level("PROJ3", ""))
+
+	assert.True(t, rs.Contains(sdk.AuthConsumerResourceRestriction{ProjectKey: "PROJ1", WorkflowName: "deploy", Permission: sdk.PermissionRead}))
+	assert.False(t, rs.Contains(sdk.AuthConsumerResourceRestriction{ProjectKey: "PROJ1", Permission: sdk.PermissionReadWriteExecute}))
+	assert.False(t, rs.Contains(sdk.AuthConsumerResourceRestriction{ProjectKey: "PROJ2", Permission: sdk.PermissionRead}))
+}
+
+func TestAuthConsumerAllowedCIDRs(t *testing.T) {
+	var none sdk.AuthConsumerAllowedCIDRs
+	assert.True(t, none.ContainsIP(sdk.ParseRemoteIP("1.2.3.4:1234")))
+
+	cidrs := sdk.AuthConsumerAllowedCIDRs{"10.0.0.0/8", "2001:db8::/32"}
+	assert.NoError(t, cidrs.IsValid())
+	assert.True(t, cidrs.ContainsIP(sdk.ParseRemoteIP("10.1.2.3:5678")))
+	assert.True(t, cidrs.ContainsIP(sdk.ParseRemoteIP("10.1.2.3, 192.168.0.1")))
+	assert.True(t, cidrs.ContainsIP(sdk.ParseRemoteIP("[2001:db8::1]:443")))
+	assert.False(t, cidrs.ContainsIP(sdk.ParseRemoteIP("192.168.0.1")))
+	assert.False(t, cidrs.ContainsIP(sdk.ParseRemoteIP("invalid")))
+
+	assert.True(t, cidrs.ContainsCIDR("10.1.0.0/16"))
+	assert.False(t, cidrs.ContainsCIDR("0.0.0.0/0"))
+	assert.False(t, cidrs.ContainsCIDR("192.168.0.0/24"))
+
+	assert.Error(t, sdk.AuthConsumerAllowedCIDRs{"10.0.0.1"}.IsValid())
+}
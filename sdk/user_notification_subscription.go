@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"time"
+)
+
+// Channels of user notification subscriptions.
+const (
+	UserSubscriptionChannelEmail = "email"
+	UserSubscriptionChannelChat  = "chat"
+)
+
+// Delivery modes of user notification subscriptions.
+const (
+	UserSubscriptionModeImmediate = "immediate"
+	UserSubscriptionModeHourly    = "hourly"
+	UserSubscriptionModeDaily     = "daily"
+)
+
+// UserNotificationSubscription is a notification subscription owned by a user, it matches the
+// workflow runs ending in a project or triggered by the user.
+type UserNotificationSubscription struct {
+	ID                 int64       `json:"id" db:"id" cli:"id,key"`
+	AuthentifiedUserID string      `json:"authentified_user_id" db:"authentified_user_id" cli:"-"`
+	Created            time.Time   `json:"created" db:"created" cli:"created"`
+	ProjectKey         string      `json:"project_key,omitempty" db:"project_key" cli:"project"`
+	WorkflowName       string      `json:"workflow_name,omitempty" db:"workflow_name" cli:"workflow"`
+	Branch             string      `json:"branch,omitempty" db:"branch" cli:"branch"`
+	Statuses           StringSlice `json:"statuses,omitempty" db:"statuses" cli:"statuses"`
+	TriggeredByMe      bool        `json:"triggered_by_me" db:"triggered_by_me" cli:"triggered_by_me"`
+	Channel            string      `json:"channel" db:"channel" cli:"channel"`
+	ChatType           string      `json:"chat_type,omitempty" db:"chat_type" cli:"chat_type"`
+	WebhookURL         string      `json:"webhook_url,omitempty" db:"cipher_webhook_url" gorpmapping:"encrypted,ID,AuthentifiedUserID" cli:"-"`
+	Mode               string      `json:"mode" db:"mode" cli:"mode"`
+	LastDigest         time.Time   `json:"last_digest" db:"last_digest" cli:"-"`
+}
+
+// IsValid returns an error if the subscription is not valid.
+func (s UserNotificationSubscription) IsValid() error {
+	if s.ProjectKey == "" && !s.TriggeredByMe {
+		return NewErrorFrom(ErrWrongRequest, "subscription should be limited to a project or to the runs you triggered")
+	}
+	if s.WorkflowName != "" && s.ProjectKey == "" {
+		return NewErrorFrom(ErrWrongRequest, "a project is required to subscribe to workflow %s", s.WorkflowName)
+	}
+	for _, st := range s.Statuses {
+		if !StatusIsTerminated(st) {
+			return NewErrorFrom(ErrWrongRequest, "invalid status %q, only final statuses can be subscribed", st)
+		}
+	}
+	switch s.Channel {
+	case UserSubscriptionChannelEmail:
+	case UserSubscriptionChannelChat:
+		if !IsChatUserNotification(s.ChatType) {
+			return NewErrorFrom(ErrWrongRequest, "invalid chat type %q", s.ChatType)
+		}
+		if s.WebhookURL == "" {
+			return NewErrorFrom(ErrWrongRequest, "missing webhook url for chat subscription")
+		}
+	default:
+		return NewErrorFrom(ErrWrongRequest, "invalid channel %q", s.Channel)
+	}
+	switch s.Mode {
+	case UserSubscriptionModeImmediate, UserSubscriptionModeHourly, UserSubscriptionModeDaily:
+	default:
+		return NewErrorFrom(ErrWrongRequest, "invalid mode %q", s.Mode)
+	}
+	return nil
+}
+
+// DigestPeriod returns the period between two digests, zero for immediate subscriptions.
+func (s UserNotificationSubscription) DigestPeriod() time.Duration {
+	switch s.Mode {
+	case UserSubscriptionModeHourly:
+		return time.Hour
+	case UserSubscriptionModeDaily:
+		return 24 * time.Hour
+	}
+	return 0
+}
+
+// Match returns true if the ended workflow run matches the subscription of given user.
+func (s UserNotificationSubscription) Match(username string, e UserNotificationEvent) bool {
+	if s.ProjectKey != "" && s.ProjectKey != e.ProjectKey {
+		return false
+	}
+	if s.WorkflowName != "" && s.WorkflowName != e.WorkflowName {
+		return false
+	}
+	if s.Branch != "" && s.Branch != e.Branch {
+		return false
+	}
+	if len(s.Statuses) > 0 && !s.Statuses.Contains(e.Status) {
+		return false
+	}
+	if s.TriggeredByMe && e.TriggeredBy != username {
+		return false
+	}
+	return true
+}
+
+// UserNotificationEvent is an ended workflow run sent to subscribers, it is stored until
+// the next digest for subscriptions that are not immediate.
+type UserNotificationEvent struct {
+	ID             int64     `json:"id" db:"id"`
+	SubscriptionID int64     `json:"subscription_id" db:"subscription_id"`
+	Created        time.Time `json:"created" db:"created"`
+	ProjectKey     string    `json:"project_key" db:"project_key"`
+	WorkflowName   string    `json:"workflow_name" db:"workflow_name"`
+	RunNumber      int64     `json:"run_number" db:"run_number"`
+	Status         string    `json:"status" db:"status"`
+	Branch         string    `json:"branch" db:"branch"`
+	Commit         string    `json:"commit" db:"commit"`
+	TriggeredBy    string    `json:"triggered_by" db:"triggered_by"`
+}
+
+// NewUserNotificationEvent returns the event of an ended workflow run.
+func NewUserNotificationEvent(wr WorkflowRun) UserNotificationEvent {
+	e := UserNotificationEvent{
+		ProjectKey:   wr.Workflow.ProjectKey,
+		WorkflowName: wr.Workflow.Name,
+		RunNumber:    wr.Number,
+		Status:       wr.Status,
+	}
+	for _, t := range wr.Tags {
+		switch t.Tag {
+		case "git.branch":
+			e.Branch = t.Value
+		case "git.hash":
+			e.Commit = t.Value
+		case "triggered_by":
+			e.TriggeredBy = t.Value
+		}
+	}
+	return e
+}
@@ -0,0 +1,51 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserNotificationSubscription(t *testing.T) {
+	s := UserNotificationSubscription{Channel: UserSubscriptionChannelEmail, Mode: UserSubscriptionModeDaily}
+	require.Error(t, s.IsValid(), "a subscription on everything is not allowed")
+
+	s.ProjectKey = "MYPROJ"
+	s.Branch = "master"
+	s.Statuses = StringSlice{StatusFail}
+	require.NoError(t, s.IsValid())
+
+	s.Statuses = StringSlice{StatusBuilding}
+	require.Error(t, s.IsValid())
+	s.Statuses = StringSlice{StatusFail}
+
+	s.Channel = UserSubscriptionChannelChat
+	s.ChatType = SlackUserNotification
+	require.Error(t, s.IsValid(), "webhook url is required")
+	s.WebhookURL = "https://hooks.slack.com/services/xxx"
+	require.NoError(t, s.IsValid())
+
+	wr := WorkflowRun{
+		Number:   12,
+		Status:   StatusFail,
+		Workflow: Workflow{ProjectKey: "MYPROJ", Name: "my-workflow"},
+		Tags: []WorkflowRunTag{
+			{Tag: "git.branch", Value: "master"},
+			{Tag: "triggered_by", Value: "john.doe"},
+		},
+	}
+	e := NewUserNotificationEvent(wr)
+	require.Equal(t, "master", e.Branch)
+	require.Equal(t, "john.doe", e.TriggeredBy)
+	require.True(t, s.Match("jane.doe", e))
+
+	s.TriggeredByMe = true
+	require.False(t, s.Match("jane.doe", e))
+	require.True(t, s.Match("john.doe", e))
+
+	e.Status = StatusSuccess
+	require.False(t, s.Match("john.doe", e))
+	e.Status = StatusFail
+	e.Branch = "feat/foo"
+	require.False(t, s.Match("john.doe", e))
+}
@@ -243,6 +243,9 @@ type VCSAuthorizedClientCommon interface {
 	SetStatus(context.Context, Event) error
 	ListStatuses(ctx context.Context, repo string, ref string) ([]VCSCommitStatus, error)
 
+	// Set tests, coverage and vulnerabilities report on a commit
+	SetInsightReport(ctx context.Context, repo string, report VCSInsightReport) error
+
 	// Release
 	Release(ctx context.Context, repo, tagName, releaseTitle, releaseDescription string) (*VCSRelease, error)
 	UploadReleaseFile(ctx context.Context, repo string, releaseName string, uploadURL string, artifactName string, r io.Reader, length int) error
@@ -56,6 +56,7 @@ type cmd struct {
 	workdir string
 	cmd     string
 	args    []string
+	env     []string
 }
 
 func (c cmd) String() string {
@@ -128,7 +129,7 @@ func runGitCommandRaw(cmds cmds, output *OutputOpts, envs ...string) error {
 		}
 		cmd := exec.Command(c.cmd, c.args...)
 		cmd.Dir = c.workdir
-		cmd.Env = osEnv
+		cmd.Env = append(append([]string{}, osEnv...), c.env...)
 
 		if verbose {
 			LogFunc("Executing Command %s - %v", c, envs)
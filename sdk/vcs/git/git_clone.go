@@ -2,8 +2,14 @@ package git
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ovh/cds/sdk"
@@ -21,6 +27,13 @@ type CloneOpts struct {
 	CheckoutCommit          string
 	NoStrictHostKeyChecking bool
 	ForceGetGitDescribe     bool
+	Mirror                  *MirrorOpts
+}
+
+// MirrorOpts describes a mirror of the repository to clone from before fetching the origin
+type MirrorOpts struct {
+	URL    string
+	Header string
 }
 
 // Clone make a git clone
@@ -42,6 +55,17 @@ func Clone(repo, workdirPath, path string, auth *AuthOpts, opts *CloneOpts, outp
 	}
 
 	var userLogCommand string
+	// Submodules urls could be relative to the cloned repository so the mirror can't be used
+	if opts != nil && opts.Mirror != nil && !opts.Recursive {
+		userLogCommand, err = cloneFromMirror(repo, repoURL, workdirPath, path, auth, opts, output)
+		if err == nil {
+			return userLogCommand, nil
+		}
+		if output != nil && output.Stderr != nil {
+			fmt.Fprintf(output.Stderr, "Unable to clone from mirror, cloning from origin: %v\n", err) // nolint
+		}
+	}
+
 	userLogCommand, commands, err = prepareGitCloneCommands(repoURL, workdirPath, path, opts)
 	if err != nil {
 		return "", err
@@ -49,6 +73,145 @@ func Clone(repo, workdirPath, path string, auth *AuthOpts, opts *CloneOpts, outp
 	return userLogCommand, runGitCommands(repo, commands, auth, output)
 }
 
+var gitConfigEnv struct {
+	sync.Once
+	supported bool
+}
+
+// gitConfigEnvSupported returns true if the installed git reads its configuration from GIT_CONFIG_COUNT
+// environment variables, they are used to give the mirror authorization header without exposing it in the process list.
+func gitConfigEnvSupported() bool {
+	gitConfigEnv.Do(func() {
+		out, err := exec.Command("git", "version").Output()
+		gitConfigEnv.supported = err == nil && isGitConfigEnvSupported(string(out))
+	})
+	return gitConfigEnv.supported
+}
+
+var gitVersionRegexp = regexp.MustCompile(`git version (\d+)\.(\d+)`)
+
+// isGitConfigEnvSupported checks the output of git version, GIT_CONFIG_COUNT was added in git 2.31
+func isGitConfigEnvSupported(version string) bool {
+	m := gitVersionRegexp.FindStringSubmatch(version)
+	if m == nil {
+		return false
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	return major > 2 || (major == 2 && minor >= 31)
+}
+
+// cloneFromMirror clones the repository from its mirror then fetches the origin to be up to date.
+// The clone directory is cleaned on failure so a regular clone can be done.
+func cloneFromMirror(repo, repoURL, workdirPath, path string, auth *AuthOpts, opts *CloneOpts, output *OutputOpts) (string, error) {
+	if !gitConfigEnvSupported() {
+		return "", sdk.WithStack(fmt.Errorf("git 2.31 or later is required"))
+	}
+	userLogCommand, commands, err := prepareGitMirrorCloneCommands(repoURL, workdirPath, path, opts)
+	if err != nil {
+		return "", err
+	}
+	// all the commands run after the clone are located in the cloned repository
+	dir := commands[len(commands)-1].workdir
+	_, errStat := os.Stat(dir)
+	existed := errStat == nil
+
+	if err := runGitCommands(repo, commands, auth, output); err != nil {
+		if !existed {
+			_ = os.RemoveAll(dir)
+			return "", err
+		}
+		fis, _ := ioutil.ReadDir(dir)
+		for _, fi := range fis {
+			_ = os.RemoveAll(filepath.Join(dir, fi.Name()))
+		}
+		return "", err
+	}
+	return userLogCommand, nil
+}
+
+func prepareGitMirrorCloneCommands(repo, workdirPath, path string, opts *CloneOpts) (string, cmds, error) {
+	var err error
+	workdirPath, err = filepath.Abs(workdirPath)
+	if err != nil {
+		return "", nil, sdk.WithStack(err)
+	}
+	dir := cloneDirectory(repo, workdirPath, path)
+
+	mirrorOpts := *opts
+	mirrorOpts.CheckoutCommit = ""
+	userLogCommand, allCmd, err := prepareGitCloneCommands(opts.Mirror.URL, workdirPath, dir, &mirrorOpts)
+	if err != nil {
+		return "", nil, err
+	}
+	userLogCommand = strings.Replace(userLogCommand, "git clone", "git clone (from mirror)", 1)
+	if opts.Mirror.Header != "" {
+		allCmd[0].env = []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=" + opts.Mirror.Header}
+	}
+
+	// the mirror is replaced by the origin in the cloned repository
+	allCmd = append(allCmd, cmd{
+		cmd:     "git",
+		workdir: dir,
+		args:    []string{"remote", "set-url", "origin", repo},
+	})
+
+	// a tag can't have moved since the mirror was refreshed
+	if opts.Tag != "" && opts.Tag != sdk.DefaultGitCloneParameterTagValue {
+		return userLogCommand, allCmd, nil
+	}
+
+	// the mirror can be late, fetch the origin to get the last commits
+	fetchCmd := cmd{
+		cmd:     "git",
+		workdir: dir,
+		args:    []string{"fetch"},
+	}
+	if opts.Depth != 0 {
+		fetchCmd.args = append(fetchCmd.args, "--depth", fmt.Sprintf("%d", opts.Depth))
+	}
+	fetchCmd.args = append(fetchCmd.args, "origin")
+	userLogCommand += "\n\rExecuting: git " + strings.Join(fetchCmd.args, " ")
+	allCmd = append(allCmd, fetchCmd)
+
+	if opts.CheckoutCommit != "" {
+		if opts.Branch == "" {
+			allCmd = append(allCmd, cmd{
+				cmd:     "git",
+				workdir: dir,
+				args:    []string{"fetch", "origin", opts.CheckoutCommit},
+			})
+			userLogCommand += "\n\rExecuting: git fetch origin " + opts.CheckoutCommit
+		}
+		allCmd = append(allCmd, cmd{
+			cmd:     "git",
+			workdir: dir,
+			args:    []string{"reset", "--hard", opts.CheckoutCommit},
+		})
+		userLogCommand += "\n\rExecuting: git reset --hard " + opts.CheckoutCommit
+		return userLogCommand, allCmd, nil
+	}
+
+	allCmd = append(allCmd, cmd{
+		cmd:     "git",
+		workdir: dir,
+		args:    []string{"reset", "--hard", "@{upstream}"},
+	})
+	userLogCommand += "\n\rExecuting: git reset --hard @{upstream}"
+	return userLogCommand, allCmd, nil
+}
+
+// cloneDirectory returns the directory of the repository cloned by git clone
+func cloneDirectory(repo, workdirPath, path string) string {
+	if path == "" {
+		t := strings.Split(repo, "/")
+		return filepath.Join(workdirPath, strings.TrimSuffix(t[len(t)-1], ".git"))
+	} else if sdk.PathIsAbs(path) {
+		return path
+	}
+	return filepath.Join(workdirPath, path)
+}
+
 func prepareGitCloneCommands(repo, workdirPath, path string, opts *CloneOpts) (string, cmds, error) {
 	allCmd := []cmd{}
 	var err error
@@ -109,14 +272,7 @@ func prepareGitCloneCommands(repo, workdirPath, path string, opts *CloneOpts) (s
 			}
 			userLogCommand += "\n\rExecuting: git " + strings.Join(fetchCmd.args, " ")
 			//Locate the git reset cmd to the right directory
-			if path == "" {
-				t := strings.Split(repo, "/")
-				fetchCmd.workdir = filepath.Join(workdirPath, strings.TrimSuffix(t[len(t)-1], ".git"))
-			} else if sdk.PathIsAbs(path) {
-				fetchCmd.workdir = path
-			} else {
-				fetchCmd.workdir = filepath.Join(workdirPath, path)
-			}
+			fetchCmd.workdir = cloneDirectory(repo, workdirPath, path)
 
 			allCmd = append(allCmd, fetchCmd)
 		}
@@ -128,14 +284,7 @@ func prepareGitCloneCommands(repo, workdirPath, path string, opts *CloneOpts) (s
 		}
 		userLogCommand += "\n\rExecuting: git " + strings.Join(resetCmd.args, " ")
 		// locate the git reset cmd to the right directory
-		if path == "" {
-			t := strings.Split(repo, "/")
-			resetCmd.workdir = filepath.Join(workdirPath, strings.TrimSuffix(t[len(t)-1], ".git"))
-		} else if sdk.PathIsAbs(path) {
-			resetCmd.workdir = path
-		} else {
-			resetCmd.workdir = filepath.Join(workdirPath, path)
-		}
+		resetCmd.workdir = cloneDirectory(repo, workdirPath, path)
 
 		allCmd = append(allCmd, resetCmd)
 	}
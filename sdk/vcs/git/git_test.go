@@ -237,3 +237,75 @@ func Test_gitCommand(t *testing.T) {
 		}
 	}
 }
+
+func Test_gitMirrorCommand(t *testing.T) {
+	mirror := &MirrorOpts{URL: "https://cds.local/queue/workflows/1/mirror/abcd", Header: "Authorization: Bearer token"}
+	type args struct {
+		repo string
+		path string
+		opts *CloneOpts
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "Clone from mirror on default branch",
+			args: args{
+				repo: "https://github.com/ovh/cds.git",
+				path: "/tmp/Test_gitMirrorCommand-1",
+				opts: &CloneOpts{Quiet: true, Mirror: mirror},
+			},
+			want: []string{
+				"git clone --quiet https://cds.local/queue/workflows/1/mirror/abcd /tmp/Test_gitMirrorCommand-1",
+				"git remote set-url origin https://github.com/ovh/cds.git",
+				"git fetch origin",
+				"git reset --hard @{upstream}",
+			},
+		},
+		{
+			name: "Clone from mirror with depth and only checkout commit",
+			args: args{
+				repo: "https://github.com/ovh/cds.git",
+				path: "/tmp/Test_gitMirrorCommand-2",
+				opts: &CloneOpts{Depth: 10, CheckoutCommit: "eb8b87a", Mirror: mirror},
+			},
+			want: []string{
+				"git clone --depth 10 https://cds.local/queue/workflows/1/mirror/abcd /tmp/Test_gitMirrorCommand-2",
+				"git remote set-url origin https://github.com/ovh/cds.git",
+				"git fetch --depth 10 origin",
+				"git fetch origin eb8b87a",
+				"git reset --hard eb8b87a",
+			},
+		},
+		{
+			name: "Clone from mirror with tag",
+			args: args{
+				repo: "https://github.com/ovh/cds.git",
+				path: "/tmp/Test_gitMirrorCommand-3",
+				opts: &CloneOpts{Tag: "v1.0.0", CheckoutCommit: "eb8b87a", Mirror: mirror},
+			},
+			want: []string{
+				"git clone --branch v1.0.0 https://cds.local/queue/workflows/1/mirror/abcd /tmp/Test_gitMirrorCommand-3",
+				"git remote set-url origin https://github.com/ovh/cds.git",
+			},
+		},
+	}
+	for _, tt := range tests {
+		_, got, err := prepareGitMirrorCloneCommands(tt.args.repo, test.GetTestName(t), tt.args.path, tt.args.opts)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got.Strings(), tt.name)
+		require.Equal(t, []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=Authorization: Bearer token"}, got[0].env)
+		require.Equal(t, tt.args.path, got[1].workdir)
+	}
+}
+
+func Test_isGitConfigEnvSupported(t *testing.T) {
+	require.True(t, isGitConfigEnvSupported("git version 2.31.0\n"))
+	require.True(t, isGitConfigEnvSupported("git version 2.39.5"))
+	require.True(t, isGitConfigEnvSupported("git version 3.0.0"))
+	require.False(t, isGitConfigEnvSupported("git version 2.30.1 (Apple Git-130)"))
+	require.False(t, isGitConfigEnvSupported("git version 1.8.3.1"))
+	require.False(t, isGitConfigEnvSupported(""))
+}
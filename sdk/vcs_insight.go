@@ -0,0 +1,127 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// VCSInsightReportResult is the global result of an insight report.
+type VCSInsightReportResult string
+
+// Insight report results.
+const (
+	VCSInsightReportResultPass VCSInsightReportResult = "PASS"
+	VCSInsightReportResultFail VCSInsightReportResult = "FAIL"
+)
+
+// Insight annotation severities and types, values are the ones used by Bitbucket Code Insights.
+const (
+	VCSInsightAnnotationSeverityLow    = "LOW"
+	VCSInsightAnnotationSeverityMedium = "MEDIUM"
+	VCSInsightAnnotationSeverityHigh   = "HIGH"
+
+	VCSInsightAnnotationTypeBug           = "BUG"
+	VCSInsightAnnotationTypeVulnerability = "VULNERABILITY"
+	VCSInsightAnnotationTypeCodeSmell     = "CODE_SMELL"
+)
+
+// VCSInsightAnnotationsMax is the max number of annotations sent for a report.
+const VCSInsightAnnotationsMax = 1000
+
+// VCSInsightReport is a structured report of a workflow node run pushed on a commit, it contains tests, coverage and
+// vulnerabilities results.
+type VCSInsightReport struct {
+	Key             string                 `json:"key"`
+	Title           string                 `json:"title"`
+	Details         string                 `json:"details,omitempty"`
+	Reporter        string                 `json:"reporter"`
+	Link            string                 `json:"link,omitempty"`
+	Result          VCSInsightReportResult `json:"result"`
+	Revision        string                 `json:"revision"`
+	Branch          string                 `json:"branch,omitempty"`
+	Tests           *VCSInsightTests       `json:"tests,omitempty"`
+	Coverage        *VCSInsightCoverage    `json:"coverage,omitempty"`
+	Vulnerabilities map[string]int64       `json:"vulnerabilities,omitempty"`
+	Annotations     []VCSInsightAnnotation `json:"annotations,omitempty"`
+}
+
+// VCSInsightTests summarizes the tests results of a report.
+type VCSInsightTests struct {
+	Total   int `json:"total"`
+	OK      int `json:"ok"`
+	KO      int `json:"ko"`
+	Skipped int `json:"skipped"`
+}
+
+// VCSInsightCoverage summarizes the code coverage of a report.
+type VCSInsightCoverage struct {
+	TotalLines   int `json:"total_lines"`
+	CoveredLines int `json:"covered_lines"`
+}
+
+// Percent returns the percentage of covered lines.
+func (c VCSInsightCoverage) Percent() float64 {
+	if c.TotalLines == 0 {
+		return 0
+	}
+	return float64(c.CoveredLines) * 100 / float64(c.TotalLines)
+}
+
+// VCSInsightAnnotation is a failed test or a vulnerability, the path is optional as junit results don't always
+// give the source file of a test.
+type VCSInsightAnnotation struct {
+	Path     string `json:"path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Link     string `json:"link,omitempty"`
+}
+
+var vcsInsightReportKeyRegexp = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// VCSInsightReportKey returns the unique key of the report for given workflow node.
+func VCSInsightReportKey(projKey, workflowName, nodeName string) string {
+	return "cds." + vcsInsightReportKeyRegexp.ReplaceAllString(fmt.Sprintf("%s.%s.%s", projKey, workflowName, nodeName), "-")
+}
+
+// vulnerabilitySeverities ordered from the most to the least critical.
+var vulnerabilitySeverities = []string{SeverityDefcon1, SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow,
+	SeverityNegligible, SeverityUnknown}
+
+// Summary returns a one line text summary of the report.
+func (r VCSInsightReport) Summary() string {
+	var parts []string
+	if r.Tests != nil {
+		parts = append(parts, fmt.Sprintf("Tests: %d passed, %d failed, %d skipped", r.Tests.OK, r.Tests.KO, r.Tests.Skipped))
+	}
+	if r.Coverage != nil {
+		parts = append(parts, fmt.Sprintf("Coverage: %.1f%% (%d/%d lines)", r.Coverage.Percent(), r.Coverage.CoveredLines, r.Coverage.TotalLines))
+	}
+	if len(r.Vulnerabilities) > 0 {
+		var vs []string
+		for _, s := range vulnerabilitySeverities {
+			if r.Vulnerabilities[s] > 0 {
+				vs = append(vs, fmt.Sprintf("%d %s", r.Vulnerabilities[s], s))
+			}
+		}
+		if len(vs) > 0 {
+			parts = append(parts, "Vulnerabilities: "+strings.Join(vs, ", "))
+		}
+	}
+	return strings.Join(parts, " - ")
+}
+
+// VulnerabilitySeverityToInsight converts a vulnerability severity to an annotation severity.
+func VulnerabilitySeverityToInsight(s string) string {
+	switch s {
+	case SeverityDefcon1, SeverityCritical, SeverityHigh:
+		return VCSInsightAnnotationSeverityHigh
+	case SeverityMedium:
+		return VCSInsightAnnotationSeverityMedium
+	default:
+		return VCSInsightAnnotationSeverityLow
+	}
+}
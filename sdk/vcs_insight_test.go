@@ -0,0 +1,24 @@
+package sdk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestVCSInsightReportSummary(t *testing.T) {
+	r := sdk.VCSInsightReport{
+		Tests:           &sdk.VCSInsightTests{Total: 12, OK: 9, KO: 2, Skipped: 1},
+		Coverage:        &sdk.VCSInsightCoverage{TotalLines: 200, CoveredLines: 150},
+		Vulnerabilities: map[string]int64{sdk.SeverityLow: 3, sdk.SeverityCritical: 1, sdk.SeverityMedium: 0},
+	}
+	assert.Equal(t, "Tests: 9 passed, 2 failed, 1 skipped - Coverage: 75.0% (150/200 lines) - Vulnerabilities: 1 critical, 3 low", r.Summary())
+	assert.Equal(t, "", sdk.VCSInsightReport{}.Summary())
+}
+
+func TestVCSInsightReportKey(t *testing.T) {
+	assert.Equal(t, "cds.PROJ.my-workflow.build-1", sdk.VCSInsightReportKey("PROJ", "my-workflow", "build 1"))
+	assert.Equal(t, "cds.PROJ.my_workflow.deploy-prod", sdk.VCSInsightReportKey("PROJ", "my_workflow", "deploy/prod"))
+}
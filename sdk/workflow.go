@@ -185,7 +185,7 @@ type WorkflowNotification struct {
 	NodeIDs        []int64                  `json:"node_id,omitempty" db:"-"`
 	Type           string                   `json:"type" db:"type"`
 	Settings       UserNotificationSettings `json:"settings" db:"-"`
-	Integration    string                   `json:"integration,omitempty" db:"-"`
+	Integration    string                   `json:"integration,omitempty" db:"integration"`
 }
 
 // ResetIDs resets all nodes, joins, integration ids
@@ -387,6 +387,11 @@ type WorkflowNodeConditions struct {
 	LuaScript       string                  `json:"lua_script,omitempty" yaml:"script,omitempty"`
 }
 
+// IsEmpty returns true if there is no plain condition and no lua script.
+func (w WorkflowNodeConditions) IsEmpty() bool {
+	return len(w.PlainConditions) == 0 && w.LuaScript == ""
+}
+
 // Value returns driver.Value from WorkflowNodeConditions request.
 func (w WorkflowNodeConditions) Value() (driver.Value, error) {
 	j, err := json.Marshal(w)
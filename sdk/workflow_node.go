@@ -55,6 +55,8 @@ type NodeContext struct {
 	DefaultPipelineParameters []Parameter            `json:"default_pipeline_parameters" db:"-"`
 	Conditions                WorkflowNodeConditions `json:"conditions" db:"-"`
 	Mutex                     bool                   `json:"mutex" db:"mutex"`
+	Approval                  *NodeApproval          `json:"approval,omitempty" db:"-"`
+	Preview                   string                 `json:"preview,omitempty" db:"-"`
 }
 
 // FilterHooksConfig filter all hooks configuration and remove somme configuration key
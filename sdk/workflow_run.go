@@ -362,6 +362,8 @@ type WorkflowNodeRun struct {
 	HookExecutionID        string                               `json:"execution_id,omitempty"`
 	Callback               *WorkflowNodeOutgoingHookRunCallback `json:"callback,omitempty"`
 	VCSReport              string                               `json:"vcs_report,omitempty"`
+	Approval               *WorkflowNodeRunApproval             `json:"approval,omitempty"`
+	ProtectionViolation    string                               `json:"protection_violation,omitempty"`
 }
 
 func (nodeRun *WorkflowNodeRun) GetStageIndex(job *WorkflowNodeJobRun) int {
@@ -605,6 +607,7 @@ type WorkflowNodeRunManual struct {
 	Username           string      `json:"username" db:"-"`
 	Fullname           string      `json:"fullname" db:"-"`
 	Email              string      `json:"email" db:"-"`
+	UserID             string      `json:"user_id,omitempty" db:"-"`
 }
 
 //GetName returns the name the artifact
@@ -0,0 +1,193 @@
+package sdk
+
+import (
+	"time"
+)
+
+// NodeApproval represents an approval gate on a pipeline node: a run of the node waits for the approval
+// of reviewers from given groups before its jobs are queued.
+type NodeApproval struct {
+	Groups             []string `json:"groups"`
+	RequiredApprovals  int      `json:"required_approvals"`
+	ForbidSelfApproval bool     `json:"forbid_self_approval,omitempty"`
+	ExpireAfter        string   `json:"expire_after,omitempty"`
+}
+
+// IsValid returns an error if the approval gate is not valid.
+func (a NodeApproval) IsValid() error {
+	if len(a.Groups) == 0 {
+		return NewErrorFrom(ErrWorkflowInvalid, "at least one group is required for an approval")
+	}
+	if a.RequiredApprovals < 1 {
+		return NewErrorFrom(ErrWorkflowInvalid, "at least one approval is required")
+	}
+	if a.ExpireAfter != "" {
+		d, err := time.ParseDuration(a.ExpireAfter)
+		if err != nil || d <= 0 {
+			return NewErrorFrom(ErrWorkflowInvalid, "invalid approval expiration %q, duration expected (ie. 48h)", a.ExpireAfter)
+		}
+	}
+	return nil
+}
+
+// Node run approval status
+const (
+	ApprovalStatusPending  = "Pending"
+	ApprovalStatusApproved = "Approved"
+	ApprovalStatusRejected = "Rejected"
+	ApprovalStatusExpired  = "Expired"
+)
+
+// WorkflowNodeRunApproval is the state of the approval gate for a node run. The approvals required by the protection
+// of the environment are a separate gate, only the members of the environment groups can give them.
+type WorkflowNodeRunApproval struct {
+	Status                       string                            `json:"status"`
+	Groups                       []string                          `json:"groups"`
+	RequiredApprovals            int                               `json:"required_approvals"`
+	EnvironmentGroups            []string                          `json:"environment_groups,omitempty"`
+	EnvironmentRequiredApprovals int                               `json:"environment_required_approvals,omitempty"`
+	ForbiddenUserIDs             []string                          `json:"forbidden_user_ids,omitempty"`
+	ForbiddenEmails              []string                          `json:"forbidden_emails,omitempty"`
+	Created                      time.Time                         `json:"created"`
+	ExpireAt                     *time.Time                        `json:"expire_at,omitempty"`
+	Decisions                    []WorkflowNodeRunApprovalDecision `json:"decisions,omitempty"`
+}
+
+// WorkflowNodeRunApprovalDecision is an approval or a rejection given by a reviewer. The gates tell if the reviewer
+// was a member of the node or of the environment approval groups.
+type WorkflowNodeRunApprovalDecision struct {
+	Username        string    `json:"username"`
+	Fullname        string    `json:"fullname"`
+	Approved        bool      `json:"approved"`
+	Comment         string    `json:"comment"`
+	Date            time.Time `json:"date"`
+	NodeGate        bool      `json:"node_gate,omitempty"`
+	EnvironmentGate bool      `json:"environment_gate,omitempty"`
+}
+
+// WorkflowNodeRunApprovalRequest is the body of approve and reject requests.
+type WorkflowNodeRunApprovalRequest struct {
+	Comment string `json:"comment"`
+}
+
+// NewWorkflowNodeRunApproval returns a pending approval for given gate. When self approval is forbidden,
+// the CDS user that triggered the run and the users with the email of the commit author can't approve.
+func NewWorkflowNodeRunApproval(gate NodeApproval, triggeredByUserID string, params []Parameter, now time.Time) *WorkflowNodeRunApproval {
+	a := &WorkflowNodeRunApproval{
+		Status:            ApprovalStatusPending,
+		Groups:            gate.Groups,
+		RequiredApprovals: gate.RequiredApprovals,
+		Created:           now,
+	}
+	if gate.ExpireAfter != "" {
+		if d, err := time.ParseDuration(gate.ExpireAfter); err == nil {
+			t := now.Add(d)
+			a.ExpireAt = &t
+		}
+	}
+	if gate.ForbidSelfApproval {
+		if triggeredByUserID != "" {
+			a.ForbiddenUserIDs = append(a.ForbiddenUserIDs, triggeredByUserID)
+		}
+		for _, name := range []string{"cds.triggered_by.email", "git.author.email"} {
+			if p := ParameterFind(params, name); p != nil && p.Value != "" && !IsInArray(p.Value, a.ForbiddenEmails) {
+				a.ForbiddenEmails = append(a.ForbiddenEmails, p.Value)
+			}
+		}
+	}
+	return a
+}
+
+// IsPending returns true if the approval is waiting for decisions.
+func (a *WorkflowNodeRunApproval) IsPending() bool {
+	return a != nil && a.Status == ApprovalStatusPending
+}
+
+// Approvals returns the number of approvals.
+func (a WorkflowNodeRunApproval) Approvals() int {
+	var n int
+	for _, d := range a.Decisions {
+		if d.Approved {
+			n++
+		}
+	}
+	return n
+}
+
+// NodeApprovals returns the number of approvals given by members of the node approval groups.
+func (a WorkflowNodeRunApproval) NodeApprovals() int {
+	var n int
+	for _, d := range a.Decisions {
+		if d.Approved && d.NodeGate {
+			n++
+		}
+	}
+	return n
+}
+
+// EnvironmentApprovals returns the number of approvals given by members of the environment approval groups.
+func (a WorkflowNodeRunApproval) EnvironmentApprovals() int {
+	var n int
+	for _, d := range a.Decisions {
+		if d.Approved && d.EnvironmentGate {
+			n++
+		}
+	}
+	return n
+}
+
+// MinRequiredApprovals returns the minimum number of reviewers required to approve both gates.
+func (a WorkflowNodeRunApproval) MinRequiredApprovals() int {
+	if a.EnvironmentRequiredApprovals > a.RequiredApprovals {
+		return a.EnvironmentRequiredApprovals
+	}
+	return a.RequiredApprovals
+}
+
+// CanDecide returns an error if given user is not allowed to approve or reject.
+func (a WorkflowNodeRunApproval) CanDecide(u AuthentifiedUser, emails []string, groupNames []string) error {
+	if a.Status != ApprovalStatusPending {
+		return NewErrorFrom(ErrForbidden, "approval is %s", a.Status)
+	}
+	if IsInArray(u.ID, a.ForbiddenUserIDs) {
+		return NewErrorFrom(ErrForbidden, "self approval is forbidden")
+	}
+	for _, e := range emails {
+		if IsInArray(e, a.ForbiddenEmails) {
+			return NewErrorFrom(ErrForbidden, "self approval is forbidden")
+		}
+	}
+	for _, d := range a.Decisions {
+		if d.Username == u.Username {
+			return NewErrorFrom(ErrForbidden, "user %s already gave a decision", u.Username)
+		}
+	}
+	for _, g := range groupNames {
+		if IsInArray(g, a.Groups) || IsInArray(g, a.EnvironmentGroups) {
+			return nil
+		}
+	}
+	return NewErrorFrom(ErrForbidden, "user %s is not a member of the approval groups", u.Username)
+}
+
+// AddDecision records the decision of given user, member of given groups, and updates the approval status.
+// The node run is approved when both the node and the environment gates have enough approvals.
+func (a *WorkflowNodeRunApproval) AddDecision(u AuthentifiedUser, groupNames []string, approved bool, comment string, now time.Time) {
+	d := WorkflowNodeRunApprovalDecision{
+		Username: u.Username,
+		Fullname: u.Fullname,
+		Approved: approved,
+		Comment:  comment,
+		Date:     now,
+	}
+	for _, g := range groupNames {
+		d.NodeGate = d.NodeGate || IsInArray(g, a.Groups)
+		d.EnvironmentGate = d.EnvironmentGate || IsInArray(g, a.EnvironmentGroups)
+	}
+	a.Decisions = append(a.Decisions, d)
+	if !approved {
+		a.Status = ApprovalStatusRejected
+	} else if a.NodeApprovals() >= a.RequiredApprovals && a.EnvironmentApprovals() >= a.EnvironmentRequiredApprovals {
+		a.Status = ApprovalStatusApproved
+	}
+}
@@ -0,0 +1,54 @@
+package sdk_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestWorkflowNodeRunApproval(t *testing.T) {
+	now := time.Now()
+	params := []sdk.Parameter{
+		{Name: "cds.triggered_by.username", Value: "alice"},
+		{Name: "git.author", Value: "Carol"},
+		{Name: "git.author.email", Value: "bob@localhost"},
+	}
+	gate := sdk.NodeApproval{Groups: []string{"ops"}, RequiredApprovals: 2, ForbidSelfApproval: true, ExpireAfter: "1h"}
+	require.NoError(t, gate.IsValid())
+
+	a := sdk.NewWorkflowNodeRunApproval(gate, "alice-id", params, now)
+	require.True(t, a.IsPending())
+	require.NotNil(t, a.ExpireAt)
+	require.Equal(t, now.Add(time.Hour), *a.ExpireAt)
+
+	alice := sdk.AuthentifiedUser{ID: "alice-id", Username: "alice"}
+	bob := sdk.AuthentifiedUser{ID: "bob-id", Username: "bob"}
+	carol := sdk.AuthentifiedUser{ID: "carol-id", Username: "carol"}
+	dave := sdk.AuthentifiedUser{ID: "dave-id", Username: "dave"}
+
+	require.Error(t, a.CanDecide(alice, nil, []string{"ops"}), "triggerer can't approve")
+	require.Error(t, a.CanDecide(bob, []string{"bob@localhost"}, []string{"ops"}), "commit author can't approve")
+	require.Error(t, a.CanDecide(carol, nil, []string{"devs"}), "user should be a member of the approval groups")
+
+	require.NoError(t, a.CanDecide(carol, nil, []string{"ops"}), "the VCS name of the commit author is not a CDS user")
+	a.AddDecision(carol, []string{"ops"}, true, "lgtm", now)
+	require.True(t, a.IsPending())
+	require.Error(t, a.CanDecide(carol, nil, []string{"ops"}), "approvers should be distinct")
+
+	require.NoError(t, a.CanDecide(dave, nil, []string{"ops"}))
+	a.AddDecision(dave, []string{"ops"}, true, "", now)
+	require.Equal(t, sdk.ApprovalStatusApproved, a.Status)
+	require.Equal(t, 2, a.Approvals())
+
+	r := sdk.NewWorkflowNodeRunApproval(gate, "alice-id", params, now)
+	r.AddDecision(carol, []string{"ops"}, false, "not now", now)
+	require.Equal(t, sdk.ApprovalStatusRejected, r.Status)
+	require.Error(t, r.CanDecide(dave, nil, []string{"ops"}))
+
+	require.Error(t, sdk.NodeApproval{Groups: []string{"ops"}}.IsValid())
+	require.Error(t, sdk.NodeApproval{RequiredApprovals: 1}.IsValid())
+	require.Error(t, sdk.NodeApproval{Groups: []string{"ops"}, RequiredApprovals: 1, ExpireAfter: "tomorrow"}.IsValid())
+}
@@ -9,6 +9,7 @@ const (
 	WorkflowRunResultTypeArtifact        WorkflowRunResultType = "artifact"
 	WorkflowRunResultTypeCoverage        WorkflowRunResultType = "coverage"
 	WorkflowRunResultTypeArtifactManager WorkflowRunResultType = "artifact-manager"
+	WorkflowRunResultTypeProvenance      WorkflowRunResultType = "provenance"
 )
 
 type WorkflowRunResultType string
@@ -49,6 +50,34 @@ func (r *WorkflowRunResult) GetArtifactManager() (WorkflowRunResultArtifactManag
 	return data, nil
 }
 
+func (r *WorkflowRunResult) GetProvenance() (WorkflowRunResultProvenance, error) {
+	var data WorkflowRunResultProvenance
+	if err := JSONUnmarshal(r.DataRaw, &data); err != nil {
+		return data, WithStack(err)
+	}
+	return data, nil
+}
+
+// GetNameAndSHA256 returns the name and the SHA-256 digest of the file of a run result.
+// The digest is empty for results uploaded before digests were computed.
+func (r *WorkflowRunResult) GetNameAndSHA256() (string, string, error) {
+	switch r.Type {
+	case WorkflowRunResultTypeArtifact:
+		a, err := r.GetArtifact()
+		return a.Name, a.SHA256, err
+	case WorkflowRunResultTypeCoverage:
+		c, err := r.GetCoverage()
+		return c.Name, c.SHA256, err
+	case WorkflowRunResultTypeArtifactManager:
+		a, err := r.GetArtifactManager()
+		return a.Name, a.SHA256, err
+	case WorkflowRunResultTypeProvenance:
+		p, err := r.GetProvenance()
+		return p.Name, "", err
+	}
+	return "", "", NewErrorFrom(ErrInvalidData, "unknown result type %s", r.Type)
+}
+
 type WorkflowRunResultCheck struct {
 	Name       string                `json:"name"`
 	RunID      int64                 `json:"run_id"`
@@ -61,6 +90,7 @@ type WorkflowRunResultArtifactManager struct {
 	Name     string `json:"name"`
 	Size     int64  `json:"size"`
 	MD5      string `json:"md5"`
+	SHA256   string `json:"sha256,omitempty"`
 	Path     string `json:"path"`
 	Perm     uint32 `json:"perm"`
 	RepoName string `json:"repository_name"`
@@ -87,6 +117,7 @@ type WorkflowRunResultArtifact struct {
 	Name       string `json:"name"`
 	Size       int64  `json:"size"`
 	MD5        string `json:"md5"`
+	SHA256     string `json:"sha256,omitempty"`
 	CDNRefHash string `json:"cdn_hash"`
 	Perm       uint32 `json:"perm"`
 }
@@ -111,6 +142,7 @@ type WorkflowRunResultCoverage struct {
 	Name       string `json:"name"`
 	Size       int64  `json:"size"`
 	MD5        string `json:"md5"`
+	SHA256     string `json:"sha256,omitempty"`
 	CDNRefHash string `json:"cdn_hash"`
 	Perm       uint32 `json:"perm"`
 }
@@ -130,3 +162,28 @@ func (a *WorkflowRunResultCoverage) IsValid() error {
 	}
 	return nil
 }
+
+// WorkflowRunResultProvenance is a signed provenance attestation stored in CDN for another run result.
+type WorkflowRunResultProvenance struct {
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	MD5        string `json:"md5"`
+	CDNRefHash string `json:"cdn_hash"`
+	Perm       uint32 `json:"perm"`
+	// Subject is the name of the attested run result
+	Subject string `json:"subject"`
+	KeyName string `json:"key_name"`
+}
+
+func (a *WorkflowRunResultProvenance) IsValid() error {
+	if a.Name == "" {
+		return WrapError(ErrInvalidData, "missing provenance name")
+	}
+	if a.Subject == "" {
+		return WrapError(ErrInvalidData, "missing provenance subject")
+	}
+	if a.CDNRefHash == "" {
+		return WrapError(ErrInvalidData, "missing cdn item hash")
+	}
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql/driver"
 	json "encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -58,12 +60,18 @@ type WorkflowTemplate struct {
 	Environments EnvironmentTemplates       `json:"environments" db:"environments"`
 	Version      int64                      `json:"version" db:"version"`
 	ImportURL    string                     `json:"import_url" db:"import_url"`
+	Extends      string                     `json:"extends,omitempty" db:"extends"`
+	Imports      WorkflowTemplateImports    `json:"imports,omitempty" db:"imports"`
+	Blocks       string                     `json:"blocks,omitempty" db:"blocks"`
 	// aggregates
 	Group         *Group                 `json:"group,omitempty" db:"-"`
 	FirstAudit    *AuditWorkflowTemplate `json:"first_audit,omitempty" db:"-"`
 	LastAudit     *AuditWorkflowTemplate `json:"last_audit,omitempty" db:"-"`
 	Editable      bool                   `json:"editable,omitempty" db:"-"`
 	ChangeMessage string                 `json:"change_message,omitempty" db:"-"`
+	// InheritedBlocks is set when dependencies are resolved, it contains the blocks of parent and imported
+	// templates ordered from the root template.
+	InheritedBlocks []string `json:"-" db:"-"`
 }
 
 // Value returns driver.Value from workflow template.
@@ -109,6 +117,17 @@ func (w *WorkflowTemplate) IsValid() error {
 		}
 	}
 
+	if w.Extends != "" {
+		if err := checkPinnedTemplatePath(w.Extends); err != nil {
+			return err
+		}
+	}
+	for _, i := range w.Imports {
+		if err := i.IsValid(); err != nil {
+			return err
+		}
+	}
+
 	for _, p := range w.Pipelines {
 		if err := p.IsValid(); err != nil {
 			return err
@@ -143,34 +162,21 @@ func (w *WorkflowTemplate) CheckParams(r WorkflowTemplateRequest) error {
 		return NewErrorFrom(ErrInvalidData, "Invalid given workflow name '%s', should match %s pattern", r.WorkflowName, NamePattern)
 	}
 
+	var errs []WorkflowTemplateParameterError
 	for _, p := range w.Parameters {
 		v, ok := r.Parameters[p.Key]
-		if !ok && p.Required {
-			return NewErrorFrom(ErrInvalidData, "Param %s is required", p.Key)
-		}
-		if ok {
-			if p.Required && v == "" {
-				return NewErrorFrom(ErrInvalidData, "Param %s is required", p.Key)
-			}
-			switch p.Type {
-			case ParameterTypeBoolean:
-				if v != "" && !(v == "true" || v == "false") {
-					return NewErrorFrom(ErrInvalidData, "Given value it's not a boolean for %s", p.Key)
-				}
-			case ParameterTypeRepository:
-				sp := strings.Split(v, "/")
-				if len(sp) != 3 {
-					return NewErrorFrom(ErrInvalidData, "Given value don't match vcs/repository pattern for %s", p.Key)
-				}
-			case ParameterTypeJSON:
-				if v != "" {
-					var res interface{}
-					if err := JSONUnmarshal([]byte(v), &res); err != nil {
-						return NewErrorFrom(ErrInvalidData, "Given value it's not json for %s", p.Key)
-					}
-				}
+		if !ok {
+			if p.Required {
+				errs = append(errs, WorkflowTemplateParameterError{Key: p.Key, Message: fmt.Sprintf("Param %s is required", p.Key)})
 			}
+			continue
 		}
+		if err := p.CheckValue(v); err != nil {
+			errs = append(errs, WorkflowTemplateParameterError{Key: p.Key, Message: err.Error()})
+		}
+	}
+	if len(errs) > 0 {
+		return NewWorkflowTemplateParametersError(errs)
 	}
 
 	return nil
@@ -189,6 +195,9 @@ func (w *WorkflowTemplate) Update(data WorkflowTemplate) {
 	w.Environments = data.Environments
 	w.Version = w.Version + 1
 	w.ImportURL = data.ImportURL
+	w.Extends = data.Extends
+	w.Imports = data.Imports
+	w.Blocks = data.Blocks
 }
 
 func (w WorkflowTemplate) Path() string {
@@ -202,6 +211,81 @@ func (w WorkflowTemplate) PathWithVersion() string {
 	return fmt.Sprintf("%s@%d", w.Path(), w.Version)
 }
 
+// ParseWorkflowTemplatePath returns the group name and the slug of the template, and the version or the release
+// version range of the template if given (ex: my-group/my-template@2 or my-group/my-template@^1.2).
+func ParseWorkflowTemplatePath(s string) (string, string, int64, string, error) {
+	pathWithVersion := strings.Split(s, "@")
+	path := strings.Split(pathWithVersion[0], "/")
+	if len(path) < 2 {
+		return "", "", 0, "", NewErrorFrom(ErrWrongRequest, "invalid given workflow template path")
+	}
+	var version int64
+	var versionRange string
+	if len(pathWithVersion) > 1 {
+		var err error
+		version, err = strconv.ParseInt(pathWithVersion[1], 10, 64)
+		if err != nil {
+			version = 0
+			versionRange = pathWithVersion[1]
+			if _, err := ParseWorkflowTemplateVersionRange(versionRange); err != nil {
+				return "", "", 0, "", err
+			}
+		}
+	}
+	return path[0], path[1], version, versionRange, nil
+}
+
+// checkPinnedTemplatePath returns an error if given template path has no version or version range.
+func checkPinnedTemplatePath(s string) error {
+	_, _, version, versionRange, err := ParseWorkflowTemplatePath(s)
+	if err != nil {
+		return err
+	}
+	if version == 0 && versionRange == "" {
+		return NewErrorFrom(ErrWrongRequest, "template dependency %s should be pinned to a version (ex: my-group/my-template@2 or my-group/my-template@^1.2)", s)
+	}
+	return nil
+}
+
+// WorkflowTemplateImport describes pipeline and environment fragments imported from another template.
+type WorkflowTemplateImport struct {
+	From         string   `json:"from"`
+	Pipelines    []string `json:"pipelines,omitempty"`
+	Environments []string `json:"environments,omitempty"`
+}
+
+// IsValid returns template import validity.
+func (w WorkflowTemplateImport) IsValid() error {
+	if err := checkPinnedTemplatePath(w.From); err != nil {
+		return err
+	}
+	if len(w.Pipelines) == 0 && len(w.Environments) == 0 {
+		return NewErrorFrom(ErrWrongRequest, "no pipeline or environment to import from template %s", w.From)
+	}
+	return nil
+}
+
+// WorkflowTemplateImports struct.
+type WorkflowTemplateImports []WorkflowTemplateImport
+
+// Value returns driver.Value from workflow template imports.
+func (w WorkflowTemplateImports) Value() (driver.Value, error) {
+	j, err := json.Marshal(w)
+	return j, WrapError(err, "cannot marshal WorkflowTemplateImports")
+}
+
+// Scan workflow template imports.
+func (w *WorkflowTemplateImports) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	source, ok := src.([]byte)
+	if !ok {
+		return WithStack(fmt.Errorf("type assertion .([]byte) failed (%T)", src))
+	}
+	return WrapError(JSONUnmarshal(source, w), "cannot unmarshal WorkflowTemplateImports")
+}
+
 // WorkflowTemplatesToIDs returns ids of given workflow templates.
 func WorkflowTemplatesToIDs(wts []*WorkflowTemplate) []int64 {
 	ids := make([]int64, len(wts))
@@ -220,8 +304,15 @@ func WorkflowTemplatesToGroupIDs(wts []*WorkflowTemplate) []int64 {
 	return ids
 }
 
-// PipelineTemplate struct.
+// WorkflowTemplateUsage lists workflows generated from a template and templates that depend on it.
+type WorkflowTemplateUsage struct {
+	Workflows []WorkflowName     `json:"workflows"`
+	Templates []WorkflowTemplate `json:"templates"`
+}
+
+// PipelineTemplate struct, a named pipeline can be imported by other templates.
 type PipelineTemplate struct {
+	Name  string `json:"name,omitempty"`
 	Value string `json:"value"`
 }
 
@@ -233,8 +324,9 @@ func (p *PipelineTemplate) IsValid() error {
 	return nil
 }
 
-// ApplicationTemplate struct.
+// ApplicationTemplate struct, a named application overrides the application with the same name of a parent template.
 type ApplicationTemplate struct {
+	Name  string `json:"name,omitempty"`
 	Value string `json:"value"`
 }
 
@@ -246,8 +338,9 @@ func (a *ApplicationTemplate) IsValid() error {
 	return nil
 }
 
-// EnvironmentTemplate struct.
+// EnvironmentTemplate struct, a named environment can be imported by other templates.
 type EnvironmentTemplate struct {
+	Name  string `json:"name,omitempty"`
 	Value string `json:"value"`
 }
 
@@ -264,18 +357,24 @@ type TemplateParameterType string
 
 // Parameter types.
 const (
-	ParameterTypeString     TemplateParameterType = "string"
-	ParameterTypeBoolean    TemplateParameterType = "boolean"
-	ParameterTypeRepository TemplateParameterType = "repository"
-	ParameterTypeSSHKey     TemplateParameterType = "ssh-key"
-	ParameterTypePGPKey     TemplateParameterType = "pgp-key"
-	ParameterTypeJSON       TemplateParameterType = "json"
+	ParameterTypeString      TemplateParameterType = "string"
+	ParameterTypeBoolean     TemplateParameterType = "boolean"
+	ParameterTypeRepository  TemplateParameterType = "repository"
+	ParameterTypeSSHKey      TemplateParameterType = "ssh-key"
+	ParameterTypePGPKey      TemplateParameterType = "pgp-key"
+	ParameterTypeJSON        TemplateParameterType = "json"
+	ParameterTypeChoice      TemplateParameterType = "choice"
+	ParameterTypeInteger     TemplateParameterType = "integer"
+	ParameterTypeList        TemplateParameterType = "list"
+	ParameterTypeEnvironment TemplateParameterType = "environment"
+	ParameterTypeIntegration TemplateParameterType = "integration"
 )
 
 // IsValid returns parameter type validity.
 func (t TemplateParameterType) IsValid() bool {
 	switch t {
-	case ParameterTypeString, ParameterTypeBoolean, ParameterTypeRepository, ParameterTypeSSHKey, ParameterTypePGPKey, ParameterTypeJSON:
+	case ParameterTypeString, ParameterTypeBoolean, ParameterTypeRepository, ParameterTypeSSHKey, ParameterTypePGPKey, ParameterTypeJSON,
+		ParameterTypeChoice, ParameterTypeInteger, ParameterTypeList, ParameterTypeEnvironment, ParameterTypeIntegration:
 		return true
 	}
 	return false
@@ -286,6 +385,117 @@ type WorkflowTemplateParameter struct {
 	Key      string                `json:"key"`
 	Type     TemplateParameterType `json:"type"`
 	Required bool                  `json:"required"`
+	// Choices contains allowed values for choice and list parameters.
+	Choices []string `json:"choices,omitempty"`
+	// Pattern is a regular expression that string values and list items should match.
+	Pattern string `json:"pattern,omitempty"`
+	// Min and Max are the bounds of an integer parameter.
+	Min *int64 `json:"min,omitempty"`
+	Max *int64 `json:"max,omitempty"`
+}
+
+// CheckValue returns an error if given value is not valid for the parameter.
+func (w WorkflowTemplateParameter) CheckValue(v string) error {
+	if v == "" {
+		if w.Required {
+			return fmt.Errorf("Param %s is required", w.Key)
+		}
+		return nil
+	}
+
+	switch w.Type {
+	case ParameterTypeBoolean:
+		if !(v == "true" || v == "false") {
+			return fmt.Errorf("Given value it's not a boolean for %s", w.Key)
+		}
+	case ParameterTypeRepository:
+		sp := strings.Split(v, "/")
+		if len(sp) != 3 {
+			return fmt.Errorf("Given value don't match vcs/repository pattern for %s", w.Key)
+		}
+	case ParameterTypeJSON:
+		var res interface{}
+		if err := JSONUnmarshal([]byte(v), &res); err != nil {
+			return fmt.Errorf("Given value it's not json for %s", w.Key)
+		}
+	case ParameterTypeChoice:
+		if !IsInArray(v, w.Choices) {
+			return fmt.Errorf("Given value %q for %s should be one of: %s", v, w.Key, strings.Join(w.Choices, ", "))
+		}
+	case ParameterTypeInteger:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("Given value it's not an integer for %s", w.Key)
+		}
+		if w.Min != nil && i < *w.Min {
+			return fmt.Errorf("Given value %d for %s should be greater or equal to %d", i, w.Key, *w.Min)
+		}
+		if w.Max != nil && i > *w.Max {
+			return fmt.Errorf("Given value %d for %s should be lower or equal to %d", i, w.Key, *w.Max)
+		}
+	case ParameterTypeList:
+		for _, item := range SplitTemplateParameterList(v) {
+			if len(w.Choices) > 0 && !IsInArray(item, w.Choices) {
+				return fmt.Errorf("Given item %q for %s should be one of: %s", item, w.Key, strings.Join(w.Choices, ", "))
+			}
+			if err := w.checkPattern(item); err != nil {
+				return err
+			}
+		}
+	case ParameterTypeString, ParameterTypeEnvironment, ParameterTypeIntegration:
+		if err := w.checkPattern(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w WorkflowTemplateParameter) checkPattern(v string) error {
+	if w.Pattern == "" {
+		return nil
+	}
+	r, err := regexp.Compile(w.Pattern)
+	if err != nil {
+		return fmt.Errorf("Invalid pattern for %s", w.Key)
+	}
+	if !r.MatchString(v) {
+		return fmt.Errorf("Given value %q for %s should match pattern %s", v, w.Key, w.Pattern)
+	}
+	return nil
+}
+
+// SplitTemplateParameterList returns the items of a list parameter value, items are separated by commas.
+func SplitTemplateParameterList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// WorkflowTemplateParameterError contains info about an invalid template parameter value.
+type WorkflowTemplateParameterError struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+func (w WorkflowTemplateParameterError) Error() string {
+	return w.Message
+}
+
+// NewWorkflowTemplateParametersError returns an invalid data error that contains given parameters errors.
+func NewWorkflowTemplateParametersError(errs []WorkflowTemplateParameterError) error {
+	causes := make([]string, len(errs))
+	for i := range errs {
+		causes[i] = errs[i].Message
+	}
+	return NewErrorFrom(Error{
+		ID:     ErrInvalidData.ID,
+		Status: ErrInvalidData.Status,
+		Data:   errs,
+	}, strings.Join(causes, ", "))
 }
 
 // WorkflowTemplateParameters struct.
@@ -377,6 +587,20 @@ func (w *WorkflowTemplateParameter) IsValid() error {
 	if w.Key == "" || !w.Type.IsValid() {
 		return NewErrorFrom(ErrInvalidData, "Invalid given key or type for parameter")
 	}
+	if w.Type == ParameterTypeChoice && len(w.Choices) == 0 {
+		return NewErrorFrom(ErrInvalidData, "Missing choices for parameter %s", w.Key)
+	}
+	if (w.Min != nil || w.Max != nil) && w.Type != ParameterTypeInteger {
+		return NewErrorFrom(ErrInvalidData, "Min and max are only allowed for integer parameter %s", w.Key)
+	}
+	if w.Min != nil && w.Max != nil && *w.Min > *w.Max {
+		return NewErrorFrom(ErrInvalidData, "Min should be lower than max for parameter %s", w.Key)
+	}
+	if w.Pattern != "" {
+		if _, err := regexp.Compile(w.Pattern); err != nil {
+			return NewErrorFrom(ErrInvalidData, "Invalid pattern for parameter %s: %v", w.Key, err)
+		}
+	}
 	return nil
 }
 
@@ -389,6 +613,8 @@ type WorkflowTemplateInstance struct {
 	WorkflowTemplateVersion int64                   `json:"workflow_template_version" db:"workflow_template_version"`
 	Request                 WorkflowTemplateRequest `json:"request" db:"request"`
 	WorkflowName            string                  `json:"workflow_name" db:"workflow_name"`
+	VersionRange            string                  `json:"version_range,omitempty" db:"version_range"`
+	WorkflowTemplateRelease string                  `json:"workflow_template_release,omitempty" db:"workflow_template_release"`
 	// aggregates
 	FirstAudit *AuditWorkflowTemplateInstance `json:"first_audit,omitempty" db:"-"`
 	LastAudit  *AuditWorkflowTemplateInstance `json:"last_audit,omitempty" db:"-"`
@@ -0,0 +1,165 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blang/semver"
+)
+
+// WorkflowTemplateRelease is a semver tagged snapshot of a workflow template.
+type WorkflowTemplateRelease struct {
+	ID                 int64            `json:"id" db:"id"`
+	WorkflowTemplateID int64            `json:"workflow_template_id" db:"workflow_template_id"`
+	Version            string           `json:"version" db:"version"`
+	TemplateVersion    int64            `json:"template_version" db:"template_version"`
+	Changelog          string           `json:"changelog" db:"changelog"`
+	Author             string           `json:"author" db:"author"`
+	Created            time.Time        `json:"created" db:"created"`
+	Template           WorkflowTemplate `json:"template" db:"template"`
+}
+
+// IsValid returns an error if the release version is not a valid semver.
+func (r WorkflowTemplateRelease) IsValid() error {
+	if _, err := semver.Parse(r.Version); err != nil {
+		return NewErrorFrom(ErrWrongRequest, "invalid release version %q, it should be like 1.2.3", r.Version)
+	}
+	return nil
+}
+
+// SemVer returns the parsed version of the release.
+func (r WorkflowTemplateRelease) SemVer() semver.Version {
+	v, _ := semver.Parse(r.Version)
+	return v
+}
+
+// WorkflowTemplateReleases is a list of template releases.
+type WorkflowTemplateReleases []WorkflowTemplateRelease
+
+// Sort releases by descending version.
+func (rs WorkflowTemplateReleases) Sort() {
+	sort.Slice(rs, func(i, j int) bool { return rs[i].SemVer().GT(rs[j].SemVer()) })
+}
+
+// LatestMatching returns the release with the highest version that matches given range.
+func (rs WorkflowTemplateReleases) LatestMatching(versionRange string) (*WorkflowTemplateRelease, error) {
+	r, err := ParseWorkflowTemplateVersionRange(versionRange)
+	if err != nil {
+		return nil, err
+	}
+	var latest *WorkflowTemplateRelease
+	for i := range rs {
+		if !r(rs[i].SemVer()) {
+			continue
+		}
+		if latest == nil || rs[i].SemVer().GT(latest.SemVer()) {
+			latest = &rs[i]
+		}
+	}
+	if latest == nil {
+		return nil, NewErrorFrom(ErrNotFound, "no template release matches version %q", versionRange)
+	}
+	return latest, nil
+}
+
+// ParseWorkflowTemplateVersionRange parses a template version range. Ranges can be like ^2.1 (>=2.1.0 <3.0.0),
+// ~2.1 (>=2.1.0 <2.2.0), a partial version like 2.1 (same as ~), an exact version like 2.1.0 or any range
+// expression like >=2.0.0 <2.5.0. A single number is not a range because my-group/my-template@2 references the
+// revision 2 of the template, ^2 or ~2 should be used to select releases of major version 2.
+func ParseWorkflowTemplateVersionRange(s string) (semver.Range, error) {
+	s = strings.TrimSpace(s)
+	invalid := NewErrorFrom(ErrWrongRequest, "invalid template version range %q", s)
+	if s == "" {
+		return nil, invalid
+	}
+
+	var expr string
+	switch {
+	case strings.HasPrefix(s, "^"):
+		v, n, err := parsePartialVersion(s[1:])
+		if err != nil {
+			return nil, invalid
+		}
+		var upper semver.Version
+		switch {
+		case v.Major > 0 || n == 1:
+			upper = semver.Version{Major: v.Major + 1}
+		case v.Minor > 0 || n == 2:
+			upper = semver.Version{Minor: v.Minor + 1}
+		default:
+			upper = semver.Version{Patch: v.Patch + 1}
+		}
+		expr = fmt.Sprintf(">=%s <%s", v, upper)
+	case strings.HasPrefix(s, "~"):
+		v, n, err := parsePartialVersion(s[1:])
+		if err != nil {
+			return nil, invalid
+		}
+		expr = tildeRange(v, n)
+	default:
+		if v, n, err := parsePartialVersion(s); err == nil {
+			if n == 1 {
+				return nil, NewErrorFrom(ErrWrongRequest, "invalid template version range %q, a single number is a template revision, use ^%s to select releases of major version %s", s, s, s)
+			}
+			if n == 3 {
+				expr = "=" + v.String()
+			} else {
+				expr = tildeRange(v, n)
+			}
+		} else {
+			expr = s
+		}
+	}
+
+	r, err := semver.ParseRange(expr)
+	if err != nil {
+		return nil, invalid
+	}
+	return r, nil
+}
+
+func tildeRange(v semver.Version, n int) string {
+	if n == 1 {
+		return fmt.Sprintf(">=%s <%s", v, semver.Version{Major: v.Major + 1})
+	}
+	return fmt.Sprintf(">=%s <%s", v, semver.Version{Major: v.Major, Minor: v.Minor + 1})
+}
+
+// parsePartialVersion parses versions like 1, 1.2 or 1.2.3 and returns the number of given parts.
+func parsePartialVersion(s string) (semver.Version, int, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return semver.Version{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+	var nums [3]uint64
+	for i, p := range parts {
+		n, err := strconv.ParseUint(p, 10, 64)
+		if err != nil {
+			return semver.Version{}, 0, err
+		}
+		nums[i] = n
+	}
+	return semver.Version{Major: nums[0], Minor: nums[1], Patch: nums[2]}, len(parts), nil
+}
+
+// WorkflowTemplateUpgradeRequest is the request to upgrade a template instance to the latest release that matches
+// given version range.
+type WorkflowTemplateUpgradeRequest struct {
+	VersionRange string `json:"version_range"`
+	DryRun       bool   `json:"dry_run,omitempty"`
+	Branch       string `json:"branch,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
+// WorkflowTemplateUpgradeResult describes the upgrade of a template instance.
+type WorkflowTemplateUpgradeResult struct {
+	FromVersion string     `json:"from_version"`
+	ToVersion   string     `json:"to_version"`
+	Changelogs  []string   `json:"changelogs,omitempty"`
+	Diff        string     `json:"diff"`
+	Applied     bool       `json:"applied"`
+	Operation   *Operation `json:"operation,omitempty"`
+}
@@ -0,0 +1,95 @@
+package sdk_test
+
+import (
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseWorkflowTemplateVersionRange(t *testing.T) {
+	tests := []struct {
+		in       string
+		match    []string
+		notMatch []string
+		err      bool
+	}{
+		{in: "^2.1", match: []string{"2.1.0", "2.9.3"}, notMatch: []string{"2.0.9", "3.0.0"}},
+		{in: "^0.2", match: []string{"0.2.0", "0.2.5"}, notMatch: []string{"0.3.0", "0.1.9"}},
+		{in: "^0.0.3", match: []string{"0.0.3"}, notMatch: []string{"0.0.4"}},
+		{in: "~2.1", match: []string{"2.1.0", "2.1.9"}, notMatch: []string{"2.2.0"}},
+		{in: "^2", match: []string{"2.0.0", "2.5.1"}, notMatch: []string{"3.0.0", "1.9.9"}},
+		{in: "~2", match: []string{"2.0.0", "2.5.1"}, notMatch: []string{"3.0.0", "1.9.9"}},
+		{in: "2.1", match: []string{"2.1.0", "2.1.9"}, notMatch: []string{"2.2.0"}},
+		{in: "2", err: true},
+		{in: "2.1.3", match: []string{"2.1.3"}, notMatch: []string{"2.1.4"}},
+		{in: ">=1.0.0 <1.5.0", match: []string{"1.4.9"}, notMatch: []string{"1.5.0"}},
+		{in: "", err: true},
+		{in: "^a.b", err: true},
+		{in: "latest", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			r, err := sdk.ParseWorkflowTemplateVersionRange(tt.in)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			for _, v := range tt.match {
+				require.True(t, r(semver.MustParse(v)), "%s should match %s", v, tt.in)
+			}
+			for _, v := range tt.notMatch {
+				require.False(t, r(semver.MustParse(v)), "%s should not match %s", v, tt.in)
+			}
+		})
+	}
+}
+
+func TestWorkflowTemplateReleasesLatestMatching(t *testing.T) {
+	rs := sdk.WorkflowTemplateReleases{{Version: "1.0.0"}, {Version: "2.3.0"}, {Version: "2.1.0"}, {Version: "3.0.0"}}
+
+	r, err := rs.LatestMatching("^2.1")
+	require.NoError(t, err)
+	require.Equal(t, "2.3.0", r.Version)
+
+	_, err = rs.LatestMatching("^4")
+	require.True(t, sdk.ErrorIs(err, sdk.ErrNotFound))
+
+	rs.Sort()
+	require.Equal(t, "3.0.0", rs[0].Version)
+	require.Equal(t, "1.0.0", rs[3].Version)
+}
+
+func TestParseWorkflowTemplatePath(t *testing.T) {
+	tests := []struct {
+		in           string
+		version      int64
+		versionRange string
+		err          bool
+	}{
+		{in: "my-group/my-template"},
+		{in: "my-group/my-template@2", version: 2},
+		{in: "my-group/my-template@^2", versionRange: "^2"},
+		{in: "my-group/my-template@2.1", versionRange: "2.1"},
+		{in: "my-group/my-template@>=2.0.0 <2.5.0", versionRange: ">=2.0.0 <2.5.0"},
+		{in: "my-group/my-template@latest", err: true},
+		{in: "my-template", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			groupName, slug, version, versionRange, err := sdk.ParseWorkflowTemplatePath(tt.in)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "my-group", groupName)
+			require.Equal(t, "my-template", slug)
+			require.Equal(t, tt.version, version)
+			require.Equal(t, tt.versionRange, versionRange)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package sdk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestWorkflowTemplateCheckParams(t *testing.T) {
+	min, max := int64(1), int64(10)
+	wt := sdk.WorkflowTemplate{
+		Parameters: []sdk.WorkflowTemplateParameter{
+			{Key: "region", Type: sdk.ParameterTypeChoice, Required: true, Choices: []string{"eu-west", "us-east"}},
+			{Key: "replicas", Type: sdk.ParameterTypeInteger, Min: &min, Max: &max},
+			{Key: "version", Type: sdk.ParameterTypeString, Pattern: "^v[0-9]+$"},
+			{Key: "zones", Type: sdk.ParameterTypeList, Choices: []string{"a", "b", "c"}},
+		},
+	}
+	req := sdk.WorkflowTemplateRequest{ProjectKey: "KEY", WorkflowName: "my-workflow"}
+
+	req.Parameters = map[string]string{"region": "eu-west", "replicas": "3", "version": "v2", "zones": "a, c"}
+	require.NoError(t, wt.CheckParams(req))
+
+	req.Parameters = map[string]string{"replicas": "11", "version": "2", "zones": "a,d"}
+	err := wt.CheckParams(req)
+	require.Error(t, err)
+	require.True(t, sdk.ErrorIs(err, sdk.ErrInvalidData))
+
+	httpErr := sdk.ExtractHTTPError(err)
+	errs, ok := httpErr.Data.([]sdk.WorkflowTemplateParameterError)
+	require.True(t, ok)
+	require.Equal(t, []sdk.WorkflowTemplateParameterError{
+		{Key: "region", Message: "Param region is required"},
+		{Key: "replicas", Message: "Given value 11 for replicas should be lower or equal to 10"},
+		{Key: "version", Message: "Given value \"2\" for version should match pattern ^v[0-9]+$"},
+		{Key: "zones", Message: "Given item \"d\" for zones should be one of: a, b, c"},
+	}, errs)
+
+	require.EqualError(t, sdk.WorkflowTemplateParameter{Key: "replicas", Type: sdk.ParameterTypeInteger}.CheckValue("three"),
+		"Given value it's not an integer for replicas")
+}
+
+func TestWorkflowTemplateParameterIsValid(t *testing.T) {
+	min, max := int64(5), int64(1)
+	require.Error(t, (&sdk.WorkflowTemplateParameter{Key: "p", Type: sdk.ParameterTypeChoice}).IsValid())
+	require.Error(t, (&sdk.WorkflowTemplateParameter{Key: "p", Type: sdk.ParameterTypeInteger, Min: &min, Max: &max}).IsValid())
+	require.Error(t, (&sdk.WorkflowTemplateParameter{Key: "p", Type: sdk.ParameterTypeString, Min: &min}).IsValid())
+	require.Error(t, (&sdk.WorkflowTemplateParameter{Key: "p", Type: sdk.ParameterTypeString, Pattern: "("}).IsValid())
+	require.NoError(t, (&sdk.WorkflowTemplateParameter{Key: "p", Type: sdk.ParameterTypeList, Choices: []string{"a"}}).IsValid())
+}
@@ -74,6 +74,12 @@ export class AuthConsumerWarning {
     group_name: string;
 }
 
+export class AuthConsumerResourceRestriction {
+    project_key: string;
+    workflow_name: string;
+    permission: number;
+}
+
 export class AuthConsumer {
     id: string;
     name: string;
@@ -89,6 +95,8 @@ export class AuthConsumer {
     warnings: Array<AuthConsumerWarning>;
     validity_periods: Array<AuthConsumerValidityPeriod>;
     last_authentication: string;
+    resource_restrictions: Array<AuthConsumerResourceRestriction>;
+    allowed_cidrs: Array<string>;
 
     // UI fields
     parent: AuthConsumer;
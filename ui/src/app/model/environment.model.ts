@@ -12,8 +12,25 @@ export class Environment {
     last_modified: number;
     usage: Usage;
     from_repository: string;
+    protection: EnvironmentProtection;
 
     mute: boolean;
     editModeChanged: boolean;
     workflow_ascode_holder: Workflow;
 }
+
+export class EnvironmentProtection {
+    branches: Array<string>;
+    tags: Array<string>;
+    workflows: Array<string>;
+    deployment_windows: Array<EnvironmentTimeWindow>;
+    freeze_periods: Array<EnvironmentTimeWindow>;
+    required_approvals: number;
+    approval_groups: Array<string>;
+}
+
+export class EnvironmentTimeWindow {
+    cron: string;
+    duration: string;
+    timezone: string;
+}
@@ -24,6 +24,9 @@ export class Key {
     type: string;
     application_id: number;
     pipeline_id: number;
+    algorithm: string;
+    successor: string;
+    retire_at: string;
 
     constructor() {
         this.name = '';
@@ -41,3 +44,18 @@ export class KeyType {
         return v;
     }
 }
+
+export class SSHKeyAlgorithm {
+    static RSA = 'rsa';
+    static ED25519 = 'ed25519';
+    static ECDSA = 'ecdsa';
+}
+
+export class KeyUsage {
+    key: string;
+    successor: string;
+    retire_at: string;
+    applications: Array<string>;
+    pipelines: Array<string>;
+    workflows: Array<{ id: number, name: string, project_key: string }>;
+}
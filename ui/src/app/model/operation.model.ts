@@ -46,6 +46,9 @@ export class OperationRepositoryInfo {
 // Response from api
 export class OperationLoadFiles {
     pattern: string;
+    root: string;
+    changed_since: string;
+    unchanged: boolean;
     results: {};
 }
 
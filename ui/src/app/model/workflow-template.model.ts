@@ -15,6 +15,9 @@ export class WorkflowTemplate {
     pipelines: Array<PipelineTemplate>;
     applications: Array<ApplicationTemplate>;
     environments: Array<EnvironmentTemplate>;
+    extends: string;
+    imports: Array<WorkflowTemplateImport>;
+    blocks: string;
     version: number;
     group: Group;
     first_audit: AuditWorkflowTemplate;
@@ -28,17 +31,35 @@ export class WorkflowTemplateParameter {
     key: string;
     type: string;
     required: boolean;
+    choices: Array<string>;
+    pattern: string;
+    min: number;
+    max: number;
+}
+
+export class WorkflowTemplateImport {
+    from: string;
+    pipelines: Array<string>;
+    environments: Array<string>;
+}
+
+export class WorkflowTemplateUsage {
+    workflows: Array<Workflow>;
+    templates: Array<WorkflowTemplate>;
 }
 
 export class PipelineTemplate {
+    name: string;
     value: string;
 }
 
 export class ApplicationTemplate {
+    name: string;
     value: string;
 }
 
 export class EnvironmentTemplate {
+    name: string;
     value: string;
 }
 
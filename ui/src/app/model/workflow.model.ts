@@ -389,6 +389,7 @@ export class WorkflowNotification {
     source_node_ref: Array<string>;
     type: string;
     settings: UserNotificationSettings;
+    integration: string;
 
     constructor() {
         this.type = notificationTypes[0];
@@ -646,6 +647,15 @@ export class WNodeContext {
     default_pipeline_parameters: Array<Parameter>;
     conditions: WorkflowNodeConditions;
     mutex: boolean;
+    approval: WNodeApproval;
+    preview: string;
+}
+
+export class WNodeApproval {
+    groups: Array<string>;
+    required_approvals: number;
+    forbid_self_approval: boolean;
+    expire_after: string;
 }
 
 export class WNodeOutgoingHook {
@@ -686,7 +696,8 @@ export class WorkflowPull {
     environments: Array<string>;
 }
 
-export const notificationTypes = ['jabber', 'email', 'vcs'];
+export const notificationTypes = ['jabber', 'email', 'vcs', 'slack', 'mattermost', 'teams'];
+export const chatNotificationTypes = ['slack', 'mattermost', 'teams'];
 export const notificationOnSuccess = ['always', 'change', 'never'];
 export const notificationOnFailure = ['always', 'change', 'never'];
 
@@ -731,4 +742,5 @@ export class UserNotificationTemplate {
     body: string;
     disable_comment: boolean;
     disable_status: boolean;
+    disable_report: boolean;
 }
@@ -96,6 +96,31 @@ export class WorkflowRunTags {
     value: string;
 }
 
+export class WorkflowNodeRunApproval {
+    static STATUS_PENDING = 'Pending';
+
+    status: string;
+    groups: Array<string>;
+    required_approvals: number;
+    environment_groups: Array<string>;
+    environment_required_approvals: number;
+    forbidden_user_ids: Array<string>;
+    forbidden_emails: Array<string>;
+    created: string;
+    expire_at: string;
+    decisions: Array<WorkflowNodeRunApprovalDecision>;
+}
+
+export class WorkflowNodeRunApprovalDecision {
+    username: string;
+    fullname: string;
+    approved: boolean;
+    comment: string;
+    date: string;
+    node_gate: boolean;
+    environment_gate: boolean;
+}
+
 // WorkflowNodeRun is as execution instance of a node
 export class WorkflowNodeRun implements WithKey {
     workflow_run_id: number;
@@ -126,6 +151,8 @@ export class WorkflowNodeRun implements WithKey {
     execution_id: string;
     callback: WorkflowNodeOutgoingHookRunCallback;
     static_files: Array<WorkflowNodeRunStaticFiles>;
+    approval: WorkflowNodeRunApproval;
+    protection_violation: string;
 
     // ui data
     results: Array<WorkflowRunResult>;
@@ -159,7 +186,17 @@ export class WorkflowRunResultArtifact {
     name: string
     size: number;
     md5: string;
+    sha256: string;
+    cdn_hash: string;
+}
+
+export class WorkflowRunResultProvenance {
+    name: string;
+    size: number;
+    md5: string;
     cdn_hash: string;
+    subject: string;
+    key_name: string;
 }
 
 export class WorkflowRunResultArtifactManager {
@@ -94,6 +94,9 @@ export class RequirementsFormComponent implements OnInit {
                     case 'memory':
                         placeHolderValue = '4096';
                         break;
+                    case 'cpu':
+                        placeHolderValue = '2';
+                        break;
                     case 'os-architecture':
                         placeHolderName = this._translate.instant('requirement_placeholder_name_os-architecture');
                         placeHolderValue = 'linux-amd64';
@@ -154,6 +157,9 @@ export class RequirementsFormComponent implements OnInit {
                 // memory: memory_4096
                 this.newRequirement.name = 'memory_' + this.newRequirement.value;
                 break;
+            case 'cpu':
+                this.newRequirement.name = 'cpu';
+                break;
             case 'model':
                 this.workerModelLinked = this.computeDisplayLinkWorkerModel();
                 this.newRequirement.name = this.newRequirement.value;
@@ -91,6 +91,9 @@ export class RequirementsListComponent extends Table<Requirement> implements OnI
                 // memory: memory_4096
                 req.name = 'memory_' + req.value;
                 break
+            case 'cpu':
+                req.name = 'cpu';
+                break
             case 'model':
                 req.name = req.value;
                 break
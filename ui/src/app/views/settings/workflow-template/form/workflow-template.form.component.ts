@@ -131,7 +131,8 @@ export class WorkflowTemplateFormComponent {
         private _sharedService: SharedService,
         private _cd: ChangeDetectorRef
     ) {
-        this.templateParameterTypes = ['boolean', 'string', 'repository', 'json', 'ssh-key', 'pgp-key'];
+        this.templateParameterTypes = ['boolean', 'string', 'repository', 'json', 'ssh-key', 'pgp-key', 'choice', 'integer', 'list',
+            'environment', 'integration'];
 
         this.resetParameterValue();
     }
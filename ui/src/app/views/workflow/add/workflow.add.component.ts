@@ -4,7 +4,7 @@ import { TranslateService } from '@ngx-translate/core';
 import { Store } from '@ngxs/store';
 import { EventService } from 'app/event.service';
 import { EventType } from 'app/model/event.model';
-import { Operation, PerformAsCodeResponse } from 'app/model/operation.model';
+import { Operation, OperationLoadFiles, PerformAsCodeResponse } from 'app/model/operation.model';
 import { Project } from 'app/model/project.model';
 import { Repository } from 'app/model/repositories.model';
 import { VCSStrategy } from 'app/model/vcs.model';
@@ -53,6 +53,7 @@ workflow:
     repos: Array<Repository>;
     selectedRepoManager: string;
     selectedRepo: Repository;
+    selectedRoots: string;
     selectedStrategy: VCSStrategy;
     pollingImport = false;
     pollingResponse: Operation;
@@ -208,6 +209,11 @@ workflow:
         }
         operationRequest.vcs_server = this.selectedRepoManager;
         operationRequest.repo_fullname = this.selectedRepo.fullname;
+        // In a monorepo, each directory matching the roots pattern is imported as its own workflow
+        if (this.selectedRoots) {
+            operationRequest.load_files = new OperationLoadFiles();
+            operationRequest.load_files.pattern = this.selectedRoots.replace(/\/+$/, '') + '/.cds/**/*.yml';
+        }
         this.loading = true;
         this._import.import(this.project.key, operationRequest).pipe(first(), finalize(() => {
             this.loading = false;
@@ -5,7 +5,7 @@ import {
     WorkflowNodeRun,
     WorkflowNodeRunArtifact,
     WorkflowNodeRunStaticFiles, WorkflowRunResult,
-    WorkflowRunResultArtifact, WorkflowRunResultArtifactManager
+    WorkflowRunResultArtifact, WorkflowRunResultArtifactManager, WorkflowRunResultProvenance
 } from 'app/model/workflow.run.model';
 
 import { AutoUnsubscribe } from 'app/shared/decorator/autoUnsubscribe';
@@ -152,6 +152,15 @@ export class WorkflowRunArtifactListComponent implements OnInit, OnDestroy {
                     uiArtifact.size = data.size;
                     uiArtifact.type = 'file';
                     return uiArtifact;
+                case 'provenance':
+                    let dataProvenance = <WorkflowRunResultProvenance>r.data;
+                    let uiProvenance = new UIArtifact();
+                    uiProvenance.link = `./cdscdn/item/run-result/${dataProvenance.cdn_hash}/download`;
+                    uiProvenance.md5 = dataProvenance.md5;
+                    uiProvenance.name = dataProvenance.name;
+                    uiProvenance.size = dataProvenance.size;
+                    uiProvenance.type = 'provenance';
+                    return uiProvenance;
                 case 'artifact-manager':
                     let dataAM = <WorkflowRunResultArtifactManager>r.data;
                     let uiArtifactAM = new UIArtifact();
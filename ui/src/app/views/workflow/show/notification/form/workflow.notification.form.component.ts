@@ -1,7 +1,7 @@
 import { ChangeDetectionStrategy, ChangeDetectorRef, Component, EventEmitter, Input, OnInit, Output } from '@angular/core';
 import { Project } from 'app/model/project.model';
 // eslint-disable-next-line max-len
-import { notificationOnFailure, notificationOnSuccess, notificationTypes, WNode, WNodeType, Workflow, WorkflowNotification, WorkflowTriggerConditionCache } from 'app/model/workflow.model';
+import { chatNotificationTypes, notificationOnFailure, notificationOnSuccess, notificationTypes, WNode, WNodeType, Workflow, WorkflowNotification, WorkflowTriggerConditionCache } from 'app/model/workflow.model';
 import { NotificationService } from 'app/service/notification/notification.service';
 import cloneDeep from 'lodash-es/cloneDeep';
 import { finalize, first } from 'rxjs/operators';
@@ -37,6 +37,7 @@ export class WorkflowNotificationFormComponent implements OnInit {
     selectedUsers: string;
     commentEnabled = true;
     statusEnabled = true;
+    reportEnabled = true;
     alwaysSend = true;
     loadingNotifTemplate = false;
     triggerConditions: WorkflowTriggerConditionCache;
@@ -61,9 +62,20 @@ export class WorkflowNotificationFormComponent implements OnInit {
     @Output() deleteNotificationEvent = new EventEmitter<WorkflowNotification>();
 
     @Input() loading: boolean;
-    @Input() project: Project;
+    _project: Project;
+    @Input() set project(data: Project) {
+        this._project = data;
+        this.chatIntegrations = (data?.integrations ?? [])
+            .filter(i => i.model && i.model.name === 'ChatWebhook')
+            .map(i => i.name);
+    }
+    get project() {
+        return this._project;
+    }
     @Input() canDelete: boolean;
 
+    chatIntegrations: Array<string> = [];
+
     constructor(private _notificationService: NotificationService, private _cd: ChangeDetectorRef) {
         this.notifOnSuccess = notificationOnSuccess;
         this.notifOnFailure = notificationOnFailure;
@@ -91,10 +103,15 @@ export class WorkflowNotificationFormComponent implements OnInit {
         if (this._notification && this._notification.type === 'vcs') {
             this.statusEnabled = !this._notification.settings.template.disable_status;
             this.commentEnabled = !this._notification.settings.template.disable_comment;
+            this.reportEnabled = !this._notification.settings.template.disable_report;
             this.alwaysSend = this._notification.settings.on_success === 'always';
         }
     }
 
+    isChatNotification(): boolean {
+        return this._notification && chatNotificationTypes.indexOf(this._notification.type) !== -1;
+    }
+
     formatNode(): void {
         this.setNotificationTemplate();
     }
@@ -117,12 +134,16 @@ export class WorkflowNotificationFormComponent implements OnInit {
         if (this._notification.type === 'vcs') {
             this._notification.settings.template.disable_comment = !this.commentEnabled;
             this._notification.settings.template.disable_status = !this.statusEnabled;
+            this._notification.settings.template.disable_report = !this.reportEnabled;
             if (this.alwaysSend) {
                 this._notification.settings.on_success = 'always';
             } else {
                 this._notification.settings.on_success = null;
             }
         }
+        if (!this.isChatNotification()) {
+            delete this._notification.integration;
+        }
         this.updatedNotification.emit(cloneDeep(this._notification));
     }
 